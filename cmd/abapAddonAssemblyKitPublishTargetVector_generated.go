@@ -9,6 +9,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
@@ -72,8 +73,10 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -154,13 +157,14 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_password"),
 					},
 					{
-						Name:        "targetVectorScope",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_targetVectorScope"),
+						Name:           "targetVectorScope",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_targetVectorScope"),
+						PossibleValues: []interface{}{"T", "P"},
 					},
 					{
 						Name: "addonDescriptor",
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+// sbomFile is the BOM created by artifactCreateSBOM, its JSON representation is written to bom.json
+const sbomFile = "bom.xml"
+
+type artifactCreateSBOMUtils interface {
+	Abs(path string) (string, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+
+	SetDir(dir string)
+	Stdout(out io.Writer)
+	RunExecutable(executable string, params ...string) error
+}
+
+type artifactCreateSBOMUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func newArtifactCreateSBOMUtils() artifactCreateSBOMUtils {
+	utils := artifactCreateSBOMUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func artifactCreateSBOM(config artifactCreateSBOMOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *artifactCreateSBOMCommonPipelineEnvironment) {
+	utils := newArtifactCreateSBOMUtils()
+
+	err := runArtifactCreateSBOM(&config, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runArtifactCreateSBOM(config *artifactCreateSBOMOptions, utils artifactCreateSBOMUtils, commonPipelineEnvironment *artifactCreateSBOMCommonPipelineEnvironment) error {
+	descriptors, err := sbomBuildDescriptors(config, utils)
+	if err != nil {
+		return err
+	}
+
+	var pipFreeze []byte
+	if config.BuildTool == "pip" {
+		if pipFreeze, err = installPipRequirements(descriptors, utils); err != nil {
+			return err
+		}
+	}
+
+	boms := []*cyclonedx.BOM{}
+	for _, descriptor := range descriptors {
+		var bom *cyclonedx.BOM
+		switch config.BuildTool {
+		case "golang":
+			var goList []byte
+			goList, err = sbomCommandOutput(utils, filepath.Dir(descriptor), "go", "list", "-m", "-json", "all")
+			if err != nil {
+				log.SetErrorCategory(log.ErrorBuild)
+				return errors.Wrapf(err, "failed to list the modules of '%v'", descriptor)
+			}
+			bom, err = cyclonedx.FromGoModuleList(goList, config.ArtifactVersion)
+		case "pip":
+			var content []byte
+			content, err = utils.FileRead(descriptor)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read build descriptor '%v'", descriptor)
+			}
+			name := config.ArtifactName
+			if len(name) == 0 {
+				dir, err := utils.Abs(filepath.Dir(descriptor))
+				if err != nil {
+					return err
+				}
+				name = filepath.Base(dir)
+			}
+			bom, err = cyclonedx.FromPipFreeze(pipFreeze, content, name, config.ArtifactVersion)
+		default:
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("build tool '%v' not supported", config.BuildTool)
+		}
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to create BOM of '%v'", descriptor)
+		}
+		log.Entry().Infof("BOM of '%v' contains %v components", descriptor, len(bom.Components))
+		boms = append(boms, bom)
+	}
+
+	// the first build descriptor is the one closest to the project root and defines the project
+	bom := cyclonedx.Merge(boms[0].Metadata.Component, boms...)
+	if err := cyclonedx.WriteBOM(bom, sbomFile, utils); err != nil {
+		return err
+	}
+	log.Entry().Infof("BOM with %v components written to '%v'", len(bom.Components), sbomFile)
+	commonPipelineEnvironment.custom.sbomPath = sbomFile
+	return nil
+}
+
+// installPipRequirements installs the requirements of all build descriptors and returns the resulting set of installed packages
+func installPipRequirements(descriptors []string, utils artifactCreateSBOMUtils) ([]byte, error) {
+	for _, descriptor := range descriptors {
+		if err := utils.RunExecutable("pip", "install", "--requirement", descriptor); err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return nil, errors.Wrapf(err, "failed to install the requirements of '%v'", descriptor)
+		}
+	}
+	freeze, err := sbomCommandOutput(utils, "", "pip", "freeze")
+	if err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return nil, errors.Wrap(err, "failed to list the installed packages")
+	}
+	return freeze, nil
+}
+
+// sbomCommandOutput runs the executable within the directory and returns its output
+func sbomCommandOutput(utils artifactCreateSBOMUtils, dir, executable string, params ...string) ([]byte, error) {
+	var output bytes.Buffer
+	utils.SetDir(dir)
+	utils.Stdout(&output)
+	defer func() {
+		utils.SetDir("")
+		utils.Stdout(log.Writer())
+	}()
+	err := utils.RunExecutable(executable, params...)
+	return output.Bytes(), err
+}
+
+func sbomBuildDescriptors(config *artifactCreateSBOMOptions, utils artifactCreateSBOMUtils) ([]string, error) {
+	if len(config.BuildDescriptorList) > 0 {
+		return config.BuildDescriptorList, nil
+	}
+	pattern := "**/go.mod"
+	if config.BuildTool == "pip" {
+		pattern = "**/requirements.txt"
+	}
+	matches, err := utils.Glob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search for build descriptors '%v'", pattern)
+	}
+	descriptors := []string{}
+	for _, match := range matches {
+		path := filepath.ToSlash(match)
+		if strings.HasPrefix(path, "vendor/") || strings.Contains(path, "/vendor/") || strings.Contains(path, "node_modules/") {
+			continue
+		}
+		descriptors = append(descriptors, match)
+	}
+	if len(descriptors) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no build descriptor '%v' found", pattern)
+	}
+	sort.Slice(descriptors, func(i, j int) bool {
+		depthI, depthJ := strings.Count(filepath.ToSlash(descriptors[i]), "/"), strings.Count(filepath.ToSlash(descriptors[j]), "/")
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		return descriptors[i] < descriptors[j]
+	})
+	return descriptors, nil
+}
@@ -0,0 +1,221 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type artifactCreateSBOMOptions struct {
+	BuildTool           string   `json:"buildTool,omitempty"`
+	BuildDescriptorList []string `json:"buildDescriptorList,omitempty"`
+	ArtifactName        string   `json:"artifactName,omitempty"`
+	ArtifactVersion     string   `json:"artifactVersion,omitempty"`
+}
+
+type artifactCreateSBOMCommonPipelineEnvironment struct {
+	custom struct {
+		sbomPath string
+	}
+}
+
+func (p *artifactCreateSBOMCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "sbomPath", value: p.custom.sbomPath},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Piper environment")
+	}
+}
+
+// ArtifactCreateSBOMCommand Creates the CycloneDX bill of materials (BOM) of Go and Python projects
+func ArtifactCreateSBOMCommand() *cobra.Command {
+	const STEP_NAME = "artifactCreateSBOM"
+
+	metadata := artifactCreateSBOMMetadata()
+	var stepConfig artifactCreateSBOMOptions
+	var startTime time.Time
+	var commonPipelineEnvironment artifactCreateSBOMCommonPipelineEnvironment
+	var logCollector *log.CollectorHook
+
+	var createArtifactCreateSBOMCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Creates the CycloneDX bill of materials (BOM) of Go and Python projects",
+		Long: `This step creates the [CycloneDX](https://cyclonedx.org/) bill of materials (BOM) of projects which are built with ` + "`" + `golang` + "`" + ` or ` + "`" + `pip` + "`" + `.
+For the other build tools the BOM is created by the build steps, see parameter ` + "`" + `createBOM` + "`" + ` of the steps mavenBuild, mtaBuild, npmExecuteScripts and kanikoExecute.
+
+The BOM contains the complete set of resolved dependencies, the BOMs of several build descriptors (` + "`" + `go.mod` + "`" + ` respectively ` + "`" + `requirements.txt` + "`" + `) are merged into one BOM.
+For ` + "`" + `golang` + "`" + ` the modules are listed via ` + "`" + `go list -m -json all` + "`" + ` within the directory of each ` + "`" + `go.mod` + "`" + `, replacements are applied and modules replaced by a local directory are listed without version.
+For ` + "`" + `pip` + "`" + ` the requirements of all ` + "`" + `requirements.txt` + "`" + ` files are installed via ` + "`" + `pip install --requirement` + "`" + ` and the installed packages are listed via ` + "`" + `pip freeze` + "`" + `, the requirements of a ` + "`" + `requirements.txt` + "`" + ` are the direct dependencies of the project.
+It is written to ` + "`" + `bom.xml` + "`" + ` and ` + "`" + `bom.json` + "`" + ` and its path is provided in the commonPipelineEnvironment as ` + "`" + `custom/sbomPath` + "`" + `.
+Steps like nexusUpload and githubPublishRelease publish the BOM together with the build artifacts.
+protecodeExecuteScan scans the BOM instead of the Docker image with ` + "`" + `scanSBOM` + "`" + `, whitesourceExecuteScan reports the components of the BOM which are not contained in its scan results.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
+				telemetry.Send(&telemetryData)
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunk.Send(&telemetryData, logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunk.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			artifactCreateSBOM(stepConfig, &telemetryData, &commonPipelineEnvironment)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addArtifactCreateSBOMFlags(createArtifactCreateSBOMCmd, &stepConfig)
+	return createArtifactCreateSBOMCmd
+}
+
+func addArtifactCreateSBOMFlags(cmd *cobra.Command, stepConfig *artifactCreateSBOMOptions) {
+	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact.")
+	cmd.Flags().StringSliceVar(&stepConfig.BuildDescriptorList, "buildDescriptorList", []string{}, "List of build descriptors the BOM is created for. By default all `go.mod` respectively `requirements.txt` files of the project are used.")
+	cmd.Flags().StringVar(&stepConfig.ArtifactName, "artifactName", os.Getenv("PIPER_artifactName"), "Name of the Python project, defaults to the name of the directory containing the `requirements.txt`. Go modules are named by their module path.")
+	cmd.Flags().StringVar(&stepConfig.ArtifactVersion, "artifactVersion", os.Getenv("PIPER_artifactVersion"), "Version of the project the BOM is created for.")
+
+	cmd.MarkFlagRequired("buildTool")
+}
+
+// retrieve step metadata
+func artifactCreateSBOMMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "artifactCreateSBOM",
+			Aliases:     []config.Alias{},
+			Description: "Creates the CycloneDX bill of materials (BOM) of Go and Python projects",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:           "buildTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_buildTool"),
+						PossibleValues: []interface{}{"golang", "pip"},
+					},
+					{
+						Name:        "buildDescriptorList",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "artifactName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_artifactName"),
+					},
+					{
+						Name: "artifactVersion",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_artifactVersion"),
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Image: "golang:1", Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "buildTool", Value: "golang"}}}}},
+				{Image: "python:3.9", Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "buildTool", Value: "pip"}}}}},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"Name": "custom/sbomPath"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
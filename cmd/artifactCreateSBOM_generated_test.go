@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactCreateSBOMCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ArtifactCreateSBOMCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "artifactCreateSBOM", testCmd.Use, "command name incorrect")
+
+}
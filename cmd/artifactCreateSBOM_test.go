@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type artifactCreateSBOMMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+	// goLists contains the output of "go list" per directory
+	goLists map[string]string
+}
+
+func (m *artifactCreateSBOMMockUtils) RunExecutable(executable string, params ...string) error {
+	if err := m.ExecMockRunner.RunExecutable(executable, params...); err != nil {
+		return err
+	}
+	if executable == "go" && len(m.Dir) > 0 {
+		_, err := m.GetStdout().Write([]byte(m.goLists[m.Dir[len(m.Dir)-1]]))
+		return err
+	}
+	return nil
+}
+
+func newArtifactCreateSBOMMockUtils() *artifactCreateSBOMMockUtils {
+	return &artifactCreateSBOMMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{StdoutReturn: map[string]string{}},
+		FilesMock:      &mock.FilesMock{},
+	}
+}
+
+func TestRunArtifactCreateSBOM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("golang", func(t *testing.T) {
+		t.Parallel()
+		config := artifactCreateSBOMOptions{BuildTool: "golang", ArtifactVersion: "1.2.3"}
+		cpe := artifactCreateSBOMCommonPipelineEnvironment{}
+
+		utils := newArtifactCreateSBOMMockUtils()
+		utils.AddFile("go.mod", []byte("module github.com/SAP/app\n"))
+		utils.AddFile("tools/go.mod", []byte("module github.com/SAP/app/tools\n"))
+		utils.AddFile("vendor/github.com/pkg/errors/go.mod", []byte("module github.com/pkg/errors\n"))
+		utils.goLists = map[string]string{
+			".": `{"Path": "github.com/SAP/app", "Main": true}
+{"Path": "github.com/pkg/errors", "Version": "v0.9.1"}
+{"Path": "golang.org/x/sys", "Version": "v0.1.0", "Indirect": true}`,
+			"tools": `{"Path": "github.com/SAP/app/tools", "Main": true}
+{"Path": "github.com/google/uuid", "Version": "v1.3.0"}`,
+		}
+
+		err := runArtifactCreateSBOM(&config, utils, &cpe)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{".", "", "tools", ""}, utils.Dir)
+		if assert.Len(t, utils.Calls, 2) {
+			assert.Equal(t, mock.ExecCall{Exec: "go", Params: []string{"list", "-m", "-json", "all"}}, utils.Calls[0])
+		}
+		assert.Equal(t, "bom.xml", cpe.custom.sbomPath)
+		assert.True(t, utils.HasWrittenFile("bom.xml"))
+		bom, err := cyclonedx.ReadBOMFile("bom.json", utils)
+		require.NoError(t, err)
+		assert.Equal(t, "pkg:golang/github.com/SAP/app@1.2.3", bom.Metadata.Component.PURL)
+		purls := []string{}
+		for _, component := range bom.Components {
+			purls = append(purls, component.PURL)
+		}
+		assert.Equal(t, []string{"pkg:golang/github.com/pkg/errors@v0.9.1", "pkg:golang/golang.org/x/sys@v0.1.0", "pkg:golang/github.com/SAP/app/tools@1.2.3", "pkg:golang/github.com/google/uuid@v1.3.0"}, purls)
+	})
+
+	t.Run("pip", func(t *testing.T) {
+		t.Parallel()
+		config := artifactCreateSBOMOptions{BuildTool: "pip", BuildDescriptorList: []string{"service/requirements.txt"}, ArtifactVersion: "1.0.0"}
+		cpe := artifactCreateSBOMCommonPipelineEnvironment{}
+
+		utils := newArtifactCreateSBOMMockUtils()
+		utils.AddFile("service/requirements.txt", []byte("Flask>=2.0\n"))
+		utils.StdoutReturn["pip freeze"] = "Flask==2.0.1\nWerkzeug==2.0.2\n"
+
+		err := runArtifactCreateSBOM(&config, utils, &cpe)
+
+		require.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "pip", Params: []string{"install", "--requirement", "service/requirements.txt"}},
+			{Exec: "pip", Params: []string{"freeze"}},
+		}, utils.Calls)
+		bom, err := cyclonedx.ReadBOMFile("bom.xml", utils)
+		require.NoError(t, err)
+		assert.Equal(t, "pkg:pypi/service@1.0.0", bom.Metadata.Component.PURL)
+		purls := []string{}
+		for _, component := range bom.Components {
+			purls = append(purls, component.PURL)
+		}
+		assert.Equal(t, []string{"pkg:pypi/flask@2.0.1", "pkg:pypi/werkzeug@2.0.2"}, purls)
+	})
+
+	t.Run("pip install fails", func(t *testing.T) {
+		t.Parallel()
+		config := artifactCreateSBOMOptions{BuildTool: "pip"}
+
+		utils := newArtifactCreateSBOMMockUtils()
+		utils.AddFile("requirements.txt", []byte("Flask==2.0.1\n"))
+		utils.ShouldFailOnCommand = map[string]error{"pip install": fmt.Errorf("no matching distribution")}
+
+		err := runArtifactCreateSBOM(&config, utils, &artifactCreateSBOMCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to install the requirements of 'requirements.txt': no matching distribution")
+	})
+
+	t.Run("no build descriptor", func(t *testing.T) {
+		t.Parallel()
+		config := artifactCreateSBOMOptions{BuildTool: "pip"}
+
+		err := runArtifactCreateSBOM(&config, newArtifactCreateSBOMMockUtils(), &artifactCreateSBOMCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "no build descriptor '**/requirements.txt' found")
+	})
+
+	t.Run("go list fails", func(t *testing.T) {
+		t.Parallel()
+		config := artifactCreateSBOMOptions{BuildTool: "golang"}
+
+		utils := newArtifactCreateSBOMMockUtils()
+		utils.AddFile("go.mod", []byte("go 1.17\n"))
+		utils.ShouldFailOnCommand = map[string]error{"go list": fmt.Errorf("go.mod: no module declaration")}
+
+		err := runArtifactCreateSBOM(&config, utils, &artifactCreateSBOMCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to list the modules of 'go.mod': go.mod: no module declaration")
+	})
+}
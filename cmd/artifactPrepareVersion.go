@@ -24,6 +24,7 @@ import (
 	gitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
@@ -36,6 +37,7 @@ type gitRepository interface {
 	Push(*git.PushOptions) error
 	Remote(string) (*git.Remote, error)
 	ResolveRevision(plumbing.Revision) (*plumbing.Hash, error)
+	Tags() (storer.ReferenceIter, error)
 	Worktree() (*git.Worktree, error)
 }
 
@@ -96,6 +98,7 @@ func artifactPrepareVersion(config artifactPrepareVersionOptions, telemetryData
 }
 
 var sshAgentAuth = ssh.NewSSHAgentAuth
+var gitLogRange = gitUtils.LogRange
 
 func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *artifactPrepareVersionCommonPipelineEnvironment, artifact versioning.Artifact, utils artifactPrepareVersionUtils, repository gitRepository, getWorktree func(gitRepository) (gitWorktree, error)) error {
 
@@ -148,20 +151,30 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 	commonPipelineEnvironment.git.headCommitID = gitCommitID
 	newVersion := version
 
-	if versioningType == "cloud" || versioningType == "cloud_noTag" {
+	release := false
+	now := time.Now()
+
+	switch versioningType {
+	case "cloud", "cloud_noTag":
 		versioningTempl, err := versioningTemplate(artifact.VersioningScheme())
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
 			return errors.Wrapf(err, "failed to get versioning template for scheme '%v'", artifact.VersioningScheme())
 		}
 
-		now := time.Now()
-
 		newVersion, err = calculateNewVersion(versioningTempl, version, gitCommitID, config.IncludeCommitID, config.ShortCommitID, config.UnixTimestamp, now)
 		if err != nil {
 			return errors.Wrap(err, "failed to calculate new version")
 		}
+		release = true
+	case "semantic":
+		newVersion, release, err = nextSemanticVersion(config.TagPrefix, version, repository)
+		if err != nil {
+			return errors.Wrap(err, "failed to calculate new version")
+		}
+	}
 
+	if release {
 		worktree, err := getWorktree(repository)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
@@ -187,7 +200,7 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 
 		//ToDo: what about closure in current Groovy step. Discard the possibility or provide extension mechanism?
 
-		if versioningType == "cloud" {
+		if versioningType == "cloud" || versioningType == "semantic" {
 			// commit changes and push to repository (including new version tag)
 			gitCommitID, err = pushChanges(config, newVersion, repository, worktree, now)
 			if err != nil {
@@ -239,6 +252,75 @@ func getGitCommitID(repository gitRepository) (plumbing.Hash, string, error) {
 	return *commitID, commitObject.Message, nil
 }
 
+// nextSemanticVersion derives the version increment from the Conventional Commit messages since the latest release tag.
+// A new release is only required in case at least one commit requires an increment.
+// Without a previous release tag the version of the build descriptor is released as is.
+func nextSemanticVersion(tagPrefix, version string, repository gitRepository) (string, bool, error) {
+	tag, releasedVersion, err := latestReleaseTag(tagPrefix, repository)
+	if err != nil {
+		return "", false, err
+	}
+	if len(tag) == 0 {
+		currentVersion, err := versioning.ParseSemanticVersion(version)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return "", false, err
+		}
+		log.Entry().Infof("No release tag with prefix '%v' found, releasing version of build descriptor", tagPrefix)
+		return currentVersion.String(), true, nil
+	}
+
+	commits, err := gitLogRange(repository, plumbing.NewTagReferenceName(tag).String(), "HEAD")
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to retrieve commits since tag '%v'", tag)
+	}
+	increment := versioning.NoIncrement
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if commitIncrement := versioning.ConventionalCommitIncrement(commit.Message); commitIncrement > increment {
+			increment = commitIncrement
+		}
+		return nil
+	})
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to analyze commits since tag '%v'", tag)
+	}
+
+	if increment == versioning.NoIncrement {
+		log.Entry().Infof("No commits requiring a new version since tag '%v'", tag)
+		return releasedVersion.String(), false, nil
+	}
+	return releasedVersion.Increment(increment).String(), true, nil
+}
+
+// latestReleaseTag returns the tag with the highest semantic version among the tags starting with the prefix
+func latestReleaseTag(tagPrefix string, repository gitRepository) (string, versioning.SemanticVersion, error) {
+	tags, err := repository.Tags()
+	if err != nil {
+		return "", versioning.SemanticVersion{}, errors.Wrap(err, "failed to retrieve git tags")
+	}
+	latestTag := ""
+	latestVersion := versioning.SemanticVersion{}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, tagPrefix) {
+			return nil
+		}
+		version, err := versioning.ParseSemanticVersion(strings.TrimPrefix(name, tagPrefix))
+		if err != nil || version.String() != strings.TrimPrefix(name, tagPrefix) {
+			// only released versions are considered, e.g. not cloud versions carrying a timestamp
+			return nil
+		}
+		if len(latestTag) == 0 || latestVersion.LessThan(version) {
+			latestTag, latestVersion = name, version
+		}
+		return nil
+	})
+	if err != nil {
+		return "", versioning.SemanticVersion{}, errors.Wrap(err, "failed to retrieve git tags")
+	}
+	return latestTag, latestVersion, nil
+}
+
 func versioningTemplate(scheme string) (string, error) {
 	// generally: timestamp acts as build number providing a proper order
 	switch scheme {
@@ -344,21 +426,38 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return commitID, fmt.Errorf("no remote url maintained")
 	}
-	if strings.HasPrefix(urls[0], "http") {
+	if len(config.GitAuthMethod) > 0 {
+		pushOptions.Auth, err = gitUtils.AuthMethod(gitUtils.AuthOptions{
+			Method:                  config.GitAuthMethod,
+			Username:                config.Username,
+			Password:                config.Password,
+			SSHKeyPath:              config.GitSshKeyPath,
+			SSHKeyPassphrase:        config.GitSshKeyPassphrase,
+			SSHKnownHostsPath:       config.GitSshKnownHostsPath,
+			Token:                   config.GitToken,
+			GitHubAppID:             config.GithubAppID,
+			GitHubAppInstallationID: config.GithubAppInstallationID,
+			GitHubAppPrivateKeyPath: config.GithubAppPrivateKeyPath,
+			GitHubAPIURL:            config.GithubAPIURL,
+		})
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return commitID, errors.Wrap(err, "failed to determine git authentication")
+		}
+		if gitUtils.IsSSH(pushOptions.Auth) && strings.HasPrefix(urls[0], "http") {
+			updatedRemoteOrigin, err = updateRemoteOrigin(repository, convertHTTPToSSHURL(urls[0]))
+			if err != nil {
+				return commitID, err
+			}
+		}
+	} else if strings.HasPrefix(urls[0], "http") {
 		if len(config.Username) == 0 || len(config.Password) == 0 {
 			// handling compatibility: try to use ssh in case no credentials are available
 			log.Entry().Info("git username/password missing - switching to ssh")
 
-			remoteURL := convertHTTPToSSHURL(urls[0])
-
-			// update remote origin url to point to ssh url instead of http(s) url
-			err = repository.DeleteRemote("origin")
+			updatedRemoteOrigin, err = updateRemoteOrigin(repository, convertHTTPToSSHURL(urls[0]))
 			if err != nil {
-				return commitID, errors.Wrap(err, "failed to update remote origin - remove")
-			}
-			updatedRemoteOrigin, err = repository.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{remoteURL}})
-			if err != nil {
-				return commitID, errors.Wrap(err, "failed to update remote origin - create")
+				return commitID, err
 			}
 
 			pushOptions.Auth, err = sshAgentAuth("git")
@@ -366,7 +465,6 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return commitID, errors.Wrap(err, "failed to retrieve ssh authentication")
 			}
-			log.Entry().Infof("using remote '%v'", remoteURL)
 		} else {
 			pushOptions.Auth = &http.BasicAuth{Username: config.Username, Password: config.Password}
 		}
@@ -415,6 +513,20 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 	return commitID, nil
 }
 
+// updateRemoteOrigin lets remote origin point to the remote url, e.g. the ssh url instead of the http(s) url
+func updateRemoteOrigin(repository gitRepository, remoteURL string) (*git.Remote, error) {
+	err := repository.DeleteRemote("origin")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update remote origin - remove")
+	}
+	updatedRemoteOrigin, err := repository.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{remoteURL}})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update remote origin - create")
+	}
+	log.Entry().Infof("using remote '%v'", remoteURL)
+	return updatedRemoteOrigin, nil
+}
+
 func addAndCommit(config *artifactPrepareVersionOptions, worktree gitWorktree, newVersion string, t time.Time) (plumbing.Hash, error) {
 	//maybe more options are required: https://github.com/go-git/go-git/blob/master/_examples/commit/main.go
 	commit, err := worktree.Commit(fmt.Sprintf("update version %v", newVersion), &git.CommitOptions{All: true, Author: &object.Signature{Name: config.CommitUserName, When: t}})
@@ -11,31 +11,41 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
 type artifactPrepareVersionOptions struct {
-	BuildTool              string `json:"buildTool,omitempty"`
-	CommitUserName         string `json:"commitUserName,omitempty"`
-	CustomVersionField     string `json:"customVersionField,omitempty"`
-	CustomVersionSection   string `json:"customVersionSection,omitempty"`
-	CustomVersioningScheme string `json:"customVersioningScheme,omitempty"`
-	DockerVersionSource    string `json:"dockerVersionSource,omitempty"`
-	FetchCoordinates       bool   `json:"fetchCoordinates,omitempty"`
-	FilePath               string `json:"filePath,omitempty"`
-	GlobalSettingsFile     string `json:"globalSettingsFile,omitempty"`
-	IncludeCommitID        bool   `json:"includeCommitId,omitempty"`
-	M2Path                 string `json:"m2Path,omitempty"`
-	Password               string `json:"password,omitempty"`
-	ProjectSettingsFile    string `json:"projectSettingsFile,omitempty"`
-	ShortCommitID          bool   `json:"shortCommitId,omitempty"`
-	TagPrefix              string `json:"tagPrefix,omitempty"`
-	UnixTimestamp          bool   `json:"unixTimestamp,omitempty"`
-	Username               string `json:"username,omitempty"`
-	VersioningTemplate     string `json:"versioningTemplate,omitempty"`
-	VersioningType         string `json:"versioningType,omitempty"`
+	BuildTool               string `json:"buildTool,omitempty"`
+	CommitUserName          string `json:"commitUserName,omitempty"`
+	CustomVersionField      string `json:"customVersionField,omitempty"`
+	CustomVersionSection    string `json:"customVersionSection,omitempty"`
+	CustomVersioningScheme  string `json:"customVersioningScheme,omitempty"`
+	DockerVersionSource     string `json:"dockerVersionSource,omitempty"`
+	FetchCoordinates        bool   `json:"fetchCoordinates,omitempty"`
+	FilePath                string `json:"filePath,omitempty"`
+	GitAuthMethod           string `json:"gitAuthMethod,omitempty"`
+	GitSshKeyPassphrase     string `json:"gitSshKeyPassphrase,omitempty"`
+	GitSshKeyPath           string `json:"gitSshKeyPath,omitempty"`
+	GitSshKnownHostsPath    string `json:"gitSshKnownHostsPath,omitempty"`
+	GitToken                string `json:"gitToken,omitempty"`
+	GithubAPIURL            string `json:"githubApiUrl,omitempty"`
+	GithubAppID             string `json:"githubAppId,omitempty"`
+	GithubAppInstallationID string `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKeyPath string `json:"githubAppPrivateKeyPath,omitempty"`
+	GlobalSettingsFile      string `json:"globalSettingsFile,omitempty"`
+	IncludeCommitID         bool   `json:"includeCommitId,omitempty"`
+	M2Path                  string `json:"m2Path,omitempty"`
+	Password                string `json:"password,omitempty"`
+	ProjectSettingsFile     string `json:"projectSettingsFile,omitempty"`
+	ShortCommitID           bool   `json:"shortCommitId,omitempty"`
+	TagPrefix               string `json:"tagPrefix,omitempty"`
+	UnixTimestamp           bool   `json:"unixTimestamp,omitempty"`
+	Username                string `json:"username,omitempty"`
+	VersioningTemplate      string `json:"versioningTemplate,omitempty"`
+	VersioningType          string `json:"versioningType,omitempty"`
 }
 
 type artifactPrepareVersionCommonPipelineEnvironment struct {
@@ -131,6 +141,16 @@ Unlike for the _Continuous Deloyment_ pattern described above, in this case ther
 
 Configuration of this pattern is done via ` + "`" + `versioningType: library` + "`" + `.
 
+### 3. Semantic versioning based on Conventional Commits
+
+With ` + "`" + `versioningType: semantic` + "`" + ` the next ` + "`" + `<major>.<minor>.<patch>` + "`" + ` version is derived from the commit messages following the [Conventional Commits](https://www.conventionalcommits.org) specification.
+
+* The latest release is the tag ` + "`" + `<tagPrefix><major>.<minor>.<patch>` + "`" + ` with the highest version, e.g. ` + "`" + `v1.2.3` + "`" + ` for ` + "`" + `tagPrefix: v` + "`" + `.
+* All commits reachable from ` + "`" + `HEAD` + "`" + ` but not from this tag are inspected: breaking changes (` + "`" + `feat!: ...` + "`" + ` or a ` + "`" + `BREAKING CHANGE:` + "`" + ` footer) increase the major version, ` + "`" + `feat` + "`" + ` commits the minor version, ` + "`" + `fix` + "`" + ` and ` + "`" + `perf` + "`" + ` commits the patch version.
+* The new version is written to the build descriptor, committed and pushed as tag like for ` + "`" + `versioningType: cloud` + "`" + `.
+* In case no commit requires a new version, the version of the latest release is kept and no tag is created.
+* In case no release tag exists yet, the ` + "`" + `<major>.<minor>.<patch>` + "`" + ` version of the build descriptor is released.
+
 ### Support of additional build tools
 
 Besides the ` + "`" + `buildTools` + "`" + ` provided out of the box (like ` + "`" + `maven` + "`" + `, ` + "`" + `mta` + "`" + `, ` + "`" + `npm` + "`" + `, ...) it is possible to set ` + "`" + `buildTool: custom` + "`" + `.
@@ -153,6 +173,10 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 
 Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to your *.json file` + "`" + ` as well as parameter ` + "`" + `versionSource` + "`" + ` to point to the parameter containing the version.
 
+#### ` + "`" + `toml` + "`" + ` file containing the version
+
+Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to your *.toml file` + "`" + ` as well as parameters ` + "`" + `customVersionSection` + "`" + ` and ` + "`" + `customVersionField` + "`" + ` to point to the version location (table & key name) within the file.
+
 #### ` + "`" + `yaml` + "`" + ` file containing the version
 
 Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to your *.yml/*.yaml file` + "`" + ` as well as parameter ` + "`" + `versionSource` + "`" + ` to point to the parameter containing the version.`,
@@ -170,6 +194,10 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.GitSshKeyPassphrase)
+			log.RegisterSecret(stepConfig.GitSshKeyPath)
+			log.RegisterSecret(stepConfig.GitToken)
+			log.RegisterSecret(stepConfig.GithubAppPrivateKeyPath)
 			log.RegisterSecret(stepConfig.Password)
 			log.RegisterSecret(stepConfig.Username)
 
@@ -190,9 +218,11 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -219,25 +249,34 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 }
 
 func addArtifactPrepareVersionFlags(cmd *cobra.Command, stepConfig *artifactPrepareVersionOptions) {
-	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact. Supports `custom`, `dub`, `golang`, `maven`, `mta`, `npm`, `pip`, `sbt`.")
+	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact. Supports `cargo`, `composer`, `custom`, `dotnet`, `dub`, `golang`, `helm`, `maven`, `mta`, `npm`, `pip`, `sbt`.")
 	cmd.Flags().StringVar(&stepConfig.CommitUserName, "commitUserName", `Project Piper`, "Defines the user name which appears in version control for the versioning update (in case `versioningType: cloud`).")
 	cmd.Flags().StringVar(&stepConfig.CustomVersionField, "customVersionField", os.Getenv("PIPER_customVersionField"), "For `buildTool: custom`: Defines the field which contains the version in the descriptor file.")
-	cmd.Flags().StringVar(&stepConfig.CustomVersionSection, "customVersionSection", os.Getenv("PIPER_customVersionSection"), "For `buildTool: custom`: Defines the section for version retrieval in vase a *.ini/*.cfg file is used.")
+	cmd.Flags().StringVar(&stepConfig.CustomVersionSection, "customVersionSection", os.Getenv("PIPER_customVersionSection"), "For `buildTool: custom`: Defines the section for version retrieval in vase a *.ini/*.cfg file or the table in case a *.toml file is used.")
 	cmd.Flags().StringVar(&stepConfig.CustomVersioningScheme, "customVersioningScheme", os.Getenv("PIPER_customVersioningScheme"), "For `buildTool: custom`: Defines the versioning scheme to be used.")
 	cmd.Flags().StringVar(&stepConfig.DockerVersionSource, "dockerVersionSource", os.Getenv("PIPER_dockerVersionSource"), "For `buildTool: docker`: Defines the source of the version. Can be `FROM`, any supported _buildTool_ or an environment variable name.")
 	cmd.Flags().BoolVar(&stepConfig.FetchCoordinates, "fetchCoordinates", false, "If set to `true` the step will retreive artifact coordinates and store them in the common pipeline environment.")
-	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Defines a custom path to the descriptor file. Build tool specific defaults are used (e.g. `maven: pom.xml`, `npm: package.json`, `mta: mta.yaml`).")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Defines a custom path to the descriptor file. Build tool specific defaults are used (e.g. `maven: pom.xml`, `npm: package.json`, `mta: mta.yaml`, `cargo: Cargo.toml`, `helm: Chart.yaml`, `composer: composer.json`). For `dotnet` the `Directory.Build.props` or the only `*.csproj` file is used, for `pip` also a `pyproject.toml` is supported.")
+	cmd.Flags().StringVar(&stepConfig.GitAuthMethod, "gitAuthMethod", os.Getenv("PIPER_gitAuthMethod"), "Method for the authentication against the git repository: `basic` uses [`username`](#username) and [`password`](#password), `ssh` uses the private key [`gitSshKeyPath`](#gitsshkeypath) or the keys of the ssh agent, `token` sends [`gitToken`](#gittoken) as bearer token and `githubApp` uses an installation access token of the GitHub App [`githubAppId`](#githubappid).")
+	cmd.Flags().StringVar(&stepConfig.GitSshKeyPassphrase, "gitSshKeyPassphrase", os.Getenv("PIPER_gitSshKeyPassphrase"), "Passphrase of the private key [`gitSshKeyPath`](#gitsshkeypath).")
+	cmd.Flags().StringVar(&stepConfig.GitSshKeyPath, "gitSshKeyPath", os.Getenv("PIPER_gitSshKeyPath"), "Path to the private ssh key for `gitAuthMethod: ssh`. In case no key is provided, the keys of the ssh agent are used, e.g. provided via [`gitSshKeyCredentialsId`](#gitsshkeycredentialsid) on Jenkins.")
+	cmd.Flags().StringVar(&stepConfig.GitSshKnownHostsPath, "gitSshKnownHostsPath", os.Getenv("PIPER_gitSshKnownHostsPath"), "Path to the known_hosts file used to verify the host key of the git server for `gitAuthMethod: ssh`. By default the files referenced by the environment variable `SSH_KNOWN_HOSTS` or `~/.ssh/known_hosts` and `/etc/ssh/ssh_known_hosts` are used. Unknown hosts are always rejected.")
+	cmd.Flags().StringVar(&stepConfig.GitToken, "gitToken", os.Getenv("PIPER_gitToken"), "Token sent as bearer token for `gitAuthMethod: token`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "GitHub API url used to create the installation access token for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppID, "githubAppId", os.Getenv("PIPER_githubAppId"), "ID of the GitHub App for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", os.Getenv("PIPER_githubAppInstallationId"), "ID of the installation of the GitHub App in the organization or account owning the git repository for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKeyPath, "githubAppPrivateKeyPath", os.Getenv("PIPER_githubAppPrivateKeyPath"), "Path to the private key of the GitHub App for `gitAuthMethod: githubApp`.")
 	cmd.Flags().StringVar(&stepConfig.GlobalSettingsFile, "globalSettingsFile", os.Getenv("PIPER_globalSettingsFile"), "Maven only - Path to the mvn settings file that should be used as global settings file.")
 	cmd.Flags().BoolVar(&stepConfig.IncludeCommitID, "includeCommitId", true, "Defines if the automatically generated version (`versioningType: cloud`) should include the commit id hash.")
 	cmd.Flags().StringVar(&stepConfig.M2Path, "m2Path", os.Getenv("PIPER_m2Path"), "Maven only - Path to the location of the local repository that should be used.")
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for git authentication.")
 	cmd.Flags().StringVar(&stepConfig.ProjectSettingsFile, "projectSettingsFile", os.Getenv("PIPER_projectSettingsFile"), "Maven only - Path to the mvn settings file that should be used as project settings file.")
 	cmd.Flags().BoolVar(&stepConfig.ShortCommitID, "shortCommitId", false, "Defines if a short version of the commitId should be used. GitHub format is used (first 7 characters).")
-	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud`).")
+	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud` and `versioningType: semantic`). For `versioningType: semantic` it identifies the release tags as well.")
 	cmd.Flags().BoolVar(&stepConfig.UnixTimestamp, "unixTimestamp", false, "Defines if the Unix timestamp number should be used as build number instead of the standard date format.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for git authentication")
 	cmd.Flags().StringVar(&stepConfig.VersioningTemplate, "versioningTemplate", os.Getenv("PIPER_versioningTemplate"), "DEPRECATED: Defines the template for the automatic version which will be created")
-	cmd.Flags().StringVar(&stepConfig.VersioningType, "versioningType", `cloud`, "Defines the type of versioning (`cloud`: fully automatic, `cloud_noTag`: automatic but no tag created, `library`: manual, i.e. the pipeline will pick up the version from the build descriptor, but not generate a new version)")
+	cmd.Flags().StringVar(&stepConfig.VersioningType, "versioningType", `cloud`, "Defines the type of versioning (`cloud`: fully automatic, `cloud_noTag`: automatic but no tag created, `library`: manual, i.e. the pipeline will pick up the version from the build descriptor, but not generate a new version, `semantic`: new semantic version derived from the Conventional Commit messages since the latest release tag)")
 
 	cmd.MarkFlagRequired("buildTool")
 }
@@ -255,16 +294,19 @@ func artifactPrepareVersionMetadata() config.StepData {
 				Secrets: []config.StepSecrets{
 					{Name: "gitHttpsCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repository.", Type: "jenkins"},
 					{Name: "gitSshKeyCredentialsId", Description: "Jenkins 'SSH Username with private key' credentials ID ssh key for accessing your git repository. You can find details about how to generate an ssh key in the [GitHub documentation](https://docs.github.com/en/enterprise/2.15/user/articles/generating-a-new-ssh-key-and-adding-it-to-the-ssh-agent).", Type: "jenkins", Aliases: []config.Alias{{Name: "gitCredentialsId", Deprecated: true}}},
+					{Name: "gitTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the token for `gitAuthMethod: token`.", Type: "jenkins"},
+					{Name: "githubAppPrivateKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key of the GitHub App for `gitAuthMethod: githubApp`.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
-						Name:        "buildTool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_buildTool"),
+						Name:           "buildTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_buildTool"),
+						PossibleValues: []interface{}{"cargo", "composer", "custom", "docker", "dotnet", "dub", "golang", "helm", "maven", "mta", "npm", "pip", "sbt"},
 					},
 					{
 						Name:        "commitUserName",
@@ -294,13 +336,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_customVersionSection"),
 					},
 					{
-						Name:        "customVersioningScheme",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_customVersioningScheme"),
+						Name:           "customVersioningScheme",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_customVersioningScheme"),
+						PossibleValues: []interface{}{"maven", "pep440", "semver2"},
 					},
 					{
 						Name:        "dockerVersionSource",
@@ -329,6 +372,98 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_filePath"),
 					},
+					{
+						Name:           "gitAuthMethod",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_gitAuthMethod"),
+						PossibleValues: []interface{}{"basic", "ssh", "token", "githubApp"},
+					},
+					{
+						Name:        "gitSshKeyPassphrase",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKeyPassphrase"),
+					},
+					{
+						Name:        "gitSshKeyPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKeyPath"),
+					},
+					{
+						Name:        "gitSshKnownHostsPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKnownHostsPath"),
+					},
+					{
+						Name: "gitToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "gitTokenCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_gitToken"),
+					},
+					{
+						Name:        "githubApiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_githubAppId"),
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_githubAppInstallationId"),
+					},
+					{
+						Name: "githubAppPrivateKeyPath",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_githubAppPrivateKeyPath"),
+					},
 					{
 						Name:        "globalSettingsFile",
 						ResourceRef: []config.ResourceReference{},
@@ -444,13 +579,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_versioningTemplate"),
 					},
 					{
-						Name:        "versioningType",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `cloud`,
+						Name:           "versioningType",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `cloud`,
+						PossibleValues: []interface{}{"cloud", "cloud_noTag", "library", "semantic"},
 					},
 				},
 			},
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	gitUtils "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -14,6 +15,7 @@ import (
 	gitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
@@ -73,6 +75,7 @@ type gitRepositoryMock struct {
 	tag                 string
 	tagHash             plumbing.Hash
 	tagError            string
+	tags                []string
 	worktree            *git.Worktree
 	worktreeError       string
 	commitObjectHash    string
@@ -134,6 +137,33 @@ func (r *gitRepositoryMock) ResolveRevision(rev plumbing.Revision) (*plumbing.Ha
 	return &r.revisionHash, nil
 }
 
+func (r *gitRepositoryMock) Tags() (storer.ReferenceIter, error) {
+	refs := []*plumbing.Reference{}
+	for _, tag := range r.tags {
+		refs = append(refs, plumbing.NewHashReference(plumbing.NewTagReferenceName(tag), plumbing.ComputeHash(plumbing.CommitObject, []byte(tag))))
+	}
+	return storer.NewReferenceSliceIter(refs), nil
+}
+
+type commitIterMock struct {
+	commits []*object.Commit
+}
+
+func (c *commitIterMock) Next() (*object.Commit, error) {
+	panic("implement me")
+}
+
+func (c *commitIterMock) ForEach(cb func(*object.Commit) error) error {
+	for _, commit := range c.commits {
+		if err := cb(commit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *commitIterMock) Close() {}
+
 func (r *gitRepositoryMock) Worktree() (*git.Worktree, error) {
 	if len(r.worktreeError) > 0 {
 		return nil, fmt.Errorf(r.worktreeError)
@@ -218,6 +248,127 @@ func TestRunArtifactPrepareVersion(t *testing.T) {
 		assert.Equal(t, telemetry.CustomData{Custom1Label: "buildTool", Custom1: "maven", Custom2Label: "filePath", Custom2: ""}, telemetryData)
 	})
 
+	t.Run("success case - semantic", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			Password:       "****",
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3-SNAPSHOT",
+			versioningScheme: "maven",
+		}
+		worktree := gitWorktreeMock{
+			commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4}),
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+			tags:         []string{"v1.2.3", "v1.10.0", "v1.9.0", "v1.11.0-20200101120000", "build_2.0.0"},
+		}
+		var logFrom, logTo string
+		defer func() { gitLogRange = gitUtils.LogRange }()
+		gitLogRange = func(_ gitUtils.CommitResolver, from, to string) (object.CommitIter, error) {
+			logFrom, logTo = from, to
+			return &commitIterMock{commits: []*object.Commit{{Message: "fix: handle empty input"}, {Message: "feat(parser): support arrays"}, {Message: "docs: update readme"}}}, nil
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "refs/tags/v1.10.0", logFrom)
+		assert.Equal(t, "HEAD", logTo)
+		assert.Equal(t, "1.11.0", versioningMock.newVersion)
+		assert.Equal(t, "v1.11.0", repo.tag)
+		assert.True(t, repo.pushCalled)
+		assert.Equal(t, "1.11.0", cpe.artifactVersion)
+		assert.Equal(t, "1.2.3-SNAPSHOT", cpe.originalArtifactVersion)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("success case - semantic without relevant commits", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			TagPrefix:      "v",
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3",
+			versioningScheme: "maven",
+		}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			tags:         []string{"v1.2.3"},
+		}
+		defer func() { gitLogRange = gitUtils.LogRange }()
+		gitLogRange = func(_ gitUtils.CommitResolver, from, to string) (object.CommitIter, error) {
+			return &commitIterMock{commits: []*object.Commit{{Message: "chore: update dependencies"}}}, nil
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) {
+			return nil, fmt.Errorf("worktree not expected")
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", versioningMock.newVersion)
+		assert.False(t, repo.pushCalled)
+		assert.Equal(t, "1.2.3", cpe.artifactVersion)
+		assert.Equal(t, repo.revisionHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("success case - semantic first release", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			TagPrefix:      "v",
+			VersioningType: "semantic",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.0.0-SNAPSHOT",
+			versioningScheme: "maven",
+		}
+		worktree := gitWorktreeMock{
+			commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4}),
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"git@my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+		originalSSHAgentAuth := sshAgentAuth
+		sshAgentAuth = func(u string) (*ssh.PublicKeysCallback, error) { return &ssh.PublicKeysCallback{}, nil }
+		defer func() { sshAgentAuth = originalSSHAgentAuth }()
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, nil, &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", versioningMock.newVersion)
+		assert.Equal(t, "v1.0.0", repo.tag)
+		assert.True(t, repo.pushCalled)
+	})
+
+	t.Run("error case - semantic with invalid version", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			TagPrefix:      "v",
+			VersioningType: "semantic",
+		}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.0",
+			versioningScheme: "maven",
+		}
+		repo := gitRepositoryMock{}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, &versioningMock, nil, &repo, nil)
+
+		assert.EqualError(t, err, "failed to calculate new version: version '1.0' is not a semantic version <major>.<minor>.<patch>")
+	})
+
 	t.Run("success case - cloud_noTag", func(t *testing.T) {
 
 		config := artifactPrepareVersionOptions{
@@ -592,6 +743,29 @@ func TestPushChanges(t *testing.T) {
 		assert.Equal(t, &git.PushOptions{RefSpecs: []gitConfig.RefSpec{"refs/tags/1.2.3:refs/tags/1.2.3"}, Auth: &ssh.PublicKeysCallback{}}, repo.pushOptions)
 	})
 
+	t.Run("success - token", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{GitAuthMethod: "token", GitToken: "theToken", Username: "testUser", Password: "****"}
+		repo := gitRepositoryMock{remote: remote}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
+
+		commitID, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "428ecf70bc22df0ba3dcf194b5ce53e769abab07", commitID)
+		assert.Equal(t, &git.PushOptions{RefSpecs: []gitConfig.RefSpec{"refs/tags/1.2.3:refs/tags/1.2.3"}, Auth: &http.TokenAuth{Token: "theToken"}}, repo.pushOptions)
+	})
+
+	t.Run("error - git authentication", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{GitAuthMethod: "basic", Username: "testUser"}
+		repo := gitRepositoryMock{remote: remote}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3})}
+
+		_, err := pushChanges(&config, newVersion, &repo, &worktree, testTime)
+
+		assert.EqualError(t, err, "failed to determine git authentication: username and password are required for basic authentication")
+		assert.Nil(t, repo.pushOptions)
+	})
+
 	t.Run("error - commit", func(t *testing.T) {
 		config := artifactPrepareVersionOptions{}
 		repo := gitRepositoryMock{}
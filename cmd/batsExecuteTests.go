@@ -108,7 +108,7 @@ func runBatsExecuteTests(config *batsExecuteTestsOptions, telemetryData *telemet
 }
 
 func (b *batsExecuteTestsUtilsBundle) CloneRepo(URL string) error {
-	_, err := pipergit.PlainClone("", "", URL, "bats-core")
+	_, err := pipergit.PlainClone(nil, URL, "bats-core")
 	return err
 
 }
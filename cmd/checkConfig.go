@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type checkConfigCommandOptions struct {
+	output         string // output format, json or text
+	failOnWarnings bool
+	openFile       func(s string) (io.ReadCloser, error)
+	stepMetadata   func() map[string]config.StepData
+}
+
+var checkConfigOptions checkConfigCommandOptions
+
+// checkConfigResult is the machine-readable result of the configuration check
+type checkConfigResult struct {
+	File     string           `json:"file"`
+	Valid    bool             `json:"valid"`
+	Errors   int              `json:"errors"`
+	Warnings int              `json:"warnings"`
+	Findings []config.Finding `json:"findings"`
+}
+
+// CheckConfigCommand is the entry command for validating the project 'Piper' configuration against the step metadata
+func CheckConfigCommand() *cobra.Command {
+
+	checkConfigOptions.openFile = config.OpenPiperFile
+	checkConfigOptions.stepMetadata = GetAllStepMetadata
+	var checkConfigCmd = &cobra.Command{
+		Use:   "checkConfig",
+		Short: "Validates the project 'Piper' configuration against the metadata of all steps.",
+		Long: `Validates the project configuration (default: .pipeline/config.yml) against the metadata of all steps.
+Unknown steps and parameters, parameters used in a section outside of their scope, values of a wrong type,
+values which are not part of the possible values as well as deprecated aliases are reported.
+The command exits with a non-zero exit code in case errors (or warnings with --failOnWarnings) are found.`,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			result, err := checkConfig(os.Stdout)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Fatal("failed to check configuration")
+			}
+			if !result.Valid || (checkConfigOptions.failOnWarnings && result.Warnings > 0) {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().Fatalf("configuration check failed with %v error(s) and %v warning(s)", result.Errors, result.Warnings)
+			}
+		},
+	}
+
+	addCheckConfigFlags(checkConfigCmd)
+	return checkConfigCmd
+}
+
+func checkConfig(w io.Writer) (checkConfigResult, error) {
+	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
+	result := checkConfigResult{File: projectConfigFile, Findings: []config.Finding{}}
+
+	configFile, err := checkConfigOptions.openFile(projectConfigFile)
+	if err != nil {
+		return result, errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
+	}
+
+	var projectConfig config.Config
+	if err := projectConfig.ReadConfig(configFile); err != nil {
+		return result, errors.Wrapf(err, "config: reading configuration file '%v' failed", projectConfigFile)
+	}
+
+	result.Findings = append(result.Findings, projectConfig.Check(checkConfigOptions.stepMetadata())...)
+	for _, finding := range result.Findings {
+		switch finding.Severity {
+		case config.SeverityError:
+			result.Errors++
+		case config.SeverityWarning:
+			result.Warnings++
+		}
+	}
+	result.Valid = result.Errors == 0
+
+	return result, printCheckConfigResult(w, result, checkConfigOptions.output)
+}
+
+func printCheckConfigResult(w io.Writer, result checkConfigResult, output string) error {
+	if output == "json" {
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal result of configuration check")
+		}
+		fmt.Fprintln(w, string(resultJSON))
+		return nil
+	}
+
+	for _, finding := range result.Findings {
+		fmt.Fprintf(w, "%v: %v: %v: %v\n", result.File, finding.Section, finding.Severity, finding.Message)
+	}
+	fmt.Fprintf(w, "%v: %v error(s), %v warning(s)\n", result.File, result.Errors, result.Warnings)
+	return nil
+}
+
+func addCheckConfigFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&checkConfigOptions.output, "output", "text", "Defines the output format (text, json)")
+	cmd.Flags().BoolVar(&checkConfigOptions.failOnWarnings, "failOnWarnings", false, "Defines if warnings should also lead to a non-zero exit code")
+}
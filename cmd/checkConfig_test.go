@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConfigCommand(t *testing.T) {
+	cmd := CheckConfigCommand()
+
+	gotReq := []string{}
+	gotOpt := []string{}
+
+	cmd.Flags().VisitAll(func(pflag *flag.Flag) {
+		annotations, found := pflag.Annotations[cobra.BashCompOneRequiredFlag]
+		if found && annotations[0] == "true" {
+			gotReq = append(gotReq, pflag.Name)
+		} else {
+			gotOpt = append(gotOpt, pflag.Name)
+		}
+	})
+
+	t.Run("Required flags", func(t *testing.T) {
+		exp := []string{}
+		assert.Equal(t, exp, gotReq, "required flags incorrect")
+	})
+
+	t.Run("Optional flags", func(t *testing.T) {
+		exp := []string{"failOnWarnings", "output"}
+		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
+	})
+}
+
+func TestCheckConfig(t *testing.T) {
+	projectConfig := `general:
+  unknownGeneral: true
+steps:
+  githubCreateIssue:
+    owner: SAP
+    unknownParameter: true
+`
+	defer func() {
+		checkConfigOptions.openFile = config.OpenPiperFile
+		checkConfigOptions.stepMetadata = GetAllStepMetadata
+		checkConfigOptions.output = ""
+	}()
+	checkConfigOptions.stepMetadata = GetAllStepMetadata
+	checkConfigOptions.openFile = func(name string) (io.ReadCloser, error) {
+		if name != ".pipeline/config.yml" {
+			return nil, fmt.Errorf("file '%v' not found", name)
+		}
+		return ioutil.NopCloser(strings.NewReader(projectConfig)), nil
+	}
+	GeneralConfig.CustomConfig = ".pipeline/config.yml"
+
+	t.Run("text output", func(t *testing.T) {
+		checkConfigOptions.output = "text"
+		var out strings.Builder
+
+		result, err := checkConfig(&out)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, 1, result.Errors)
+		assert.Equal(t, 1, result.Warnings)
+		assert.Equal(t, `.pipeline/config.yml: general: warning: unknown parameter 'unknownGeneral'
+.pipeline/config.yml: steps/githubCreateIssue: error: unknown parameter 'unknownParameter' for step 'githubCreateIssue'
+.pipeline/config.yml: 1 error(s), 1 warning(s)
+`, out.String())
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		checkConfigOptions.output = "json"
+		var out strings.Builder
+
+		_, err := checkConfig(&out)
+
+		assert.NoError(t, err)
+		var result checkConfigResult
+		assert.NoError(t, json.Unmarshal([]byte(out.String()), &result))
+		assert.Equal(t, ".pipeline/config.yml", result.File)
+		assert.Len(t, result.Findings, 2)
+		assert.Equal(t, "unknownParameter", result.Findings[1].Parameter)
+	})
+
+	t.Run("error - missing configuration", func(t *testing.T) {
+		GeneralConfig.CustomConfig = "notExisting.yml"
+		defer func() { GeneralConfig.CustomConfig = ".pipeline/config.yml" }()
+
+		_, err := checkConfig(&strings.Builder{})
+
+		assert.EqualError(t, err, "config: open configuration file 'notExisting.yml' failed: file 'notExisting.yml' not found")
+	})
+}
@@ -17,11 +17,14 @@ import (
 	"encoding/xml"
 
 	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	"github.com/bmatcuk/doublestar"
 	"github.com/pkg/errors"
 )
@@ -287,12 +290,24 @@ func verifyCxProjectCompliance(config checkmarxExecuteScanOptions, sys checkmarx
 	}
 
 	xmlReportName := createReportName(utils.GetWorkspace(), "CxSASTResults_%v.xml")
-	results, err := getDetailedResults(sys, xmlReportName, scanID, utils)
+	results, detailedResult, err := getDetailedResults(sys, xmlReportName, scanID, utils)
 	if err != nil {
 		return errors.Wrap(err, "failed to get detailed results")
 	}
 	reports = append(reports, piperutils.Path{Target: xmlReportName})
 
+	sarifReportName := filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("checkmarxExecuteScan_sast_%v.sarif", detailedResult.ScanID))
+	if err := format.WriteSARIF(checkmarx.ConvertCxxmlToSarif(detailedResult), sarifReportName, &piperutils.Files{}); err != nil {
+		log.Entry().WithError(err).Warn("failed to write SARIF report")
+	} else {
+		reports = append(reports, piperutils.Path{Name: "Checkmarx SARIF Report", Target: sarifReportName})
+	}
+	exemptions, exemptionErr := vulnerability.ValidExemptions(&piperutils.Files{}, time.Now())
+	findings := exemptions.Apply(checkmarx.ConvertCxxmlToFindings(detailedResult))
+	if _, err := vulnerability.WriteFindings(findings, fmt.Sprintf("checkmarxExecuteScan_sast_%v", detailedResult.ScanID), &piperutils.Files{}); err != nil {
+		log.Entry().WithError(err).Warn("failed to write findings")
+	}
+
 	// create toolrecord
 	toolRecordFileName, err := createToolRecordCx(utils.GetWorkspace(), config, results)
 	if err != nil {
@@ -307,9 +322,13 @@ func verifyCxProjectCompliance(config checkmarxExecuteScanOptions, sys checkmarx
 
 	reportToInflux(results, influx)
 
+	if exemptionErr != nil {
+		return exemptionErr
+	}
+
 	insecure := false
 	if config.VulnerabilityThresholdEnabled {
-		insecure = enforceThresholds(config, results)
+		insecure = enforceThresholds(config, exemptResults(results, detailedResult, exemptions))
 	}
 
 	if insecure {
@@ -423,6 +442,38 @@ func downloadAndSaveReport(sys checkmarx.System, reportFileName string, scanID i
 	return utils.WriteFile(reportFileName, report, 0700)
 }
 
+// exemptResults returns a copy of the results in which the results exempted via the exemptions file are counted as not exploitable,
+// hence they don't violate the thresholds
+func exemptResults(results map[string]interface{}, detailedResult checkmarx.DetailedResult, exemptions *vulnerability.Exemptions) map[string]interface{} {
+	exempted := map[string]interface{}{}
+	for key, value := range results {
+		if counts, ok := value.(map[string]int); ok {
+			countsCopy := map[string]int{}
+			for state, count := range counts {
+				countsCopy[state] = count
+			}
+			value = countsCopy
+		}
+		exempted[key] = value
+	}
+	for _, query := range detailedResult.Queries {
+		for _, result := range query.Results {
+			finding := checkmarx.ConvertResultToFinding(query, result)
+			if !finding.IsOpen() || exemptions.Match(&finding) == nil {
+				continue
+			}
+			counts, ok := exempted[result.Severity].(map[string]int)
+			if !ok {
+				continue
+			}
+			counts[checkmarx.AuditState(result.State)]--
+			counts["NotExploitable"]++
+			counts["NotFalsePositive"]--
+		}
+	}
+	return exempted
+}
+
 func enforceThresholds(config checkmarxExecuteScanOptions, results map[string]interface{}) bool {
 	insecure := false
 	cxHighThreshold := config.VulnerabilityThresholdHigh
@@ -593,21 +644,21 @@ func getNumCoherentIncrementalScans(scans []checkmarx.ScanStatus) int {
 	return count
 }
 
-func getDetailedResults(sys checkmarx.System, reportFileName string, scanID int, utils checkmarxExecuteScanUtils) (map[string]interface{}, error) {
+func getDetailedResults(sys checkmarx.System, reportFileName string, scanID int, utils checkmarxExecuteScanUtils) (map[string]interface{}, checkmarx.DetailedResult, error) {
 	resultMap := map[string]interface{}{}
+	var xmlResult checkmarx.DetailedResult
 	data, err := generateAndDownloadReport(sys, scanID, "XML")
 	if err != nil {
-		return resultMap, errors.Wrap(err, "failed to download xml report")
+		return resultMap, xmlResult, errors.Wrap(err, "failed to download xml report")
 	}
 	if len(data) > 0 {
 		err = utils.WriteFile(reportFileName, data, 0700)
 		if err != nil {
-			return resultMap, errors.Wrap(err, "failed to write file")
+			return resultMap, xmlResult, errors.Wrap(err, "failed to write file")
 		}
-		var xmlResult checkmarx.DetailedResult
 		err := xml.Unmarshal(data, &xmlResult)
 		if err != nil {
-			return resultMap, errors.Wrapf(err, "failed to unmarshal XML report for scan %v", scanID)
+			return resultMap, xmlResult, errors.Wrapf(err, "failed to unmarshal XML report for scan %v", scanID)
 		}
 		resultMap["InitiatorName"] = xmlResult.InitiatorName
 		resultMap["Owner"] = xmlResult.Owner
@@ -668,7 +719,7 @@ func getDetailedResults(sys checkmarx.System, reportFileName string, scanID int,
 			}
 		}
 	}
-	return resultMap, nil
+	return resultMap, xmlResult, nil
 }
 
 func zipFolder(source string, zipFile io.Writer, patterns []string, utils checkmarxExecuteScanUtils) error {
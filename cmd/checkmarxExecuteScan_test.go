@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -474,7 +475,7 @@ func TestGetDetailedResults(t *testing.T) {
 		}
 		// clean up tmp dir
 		defer os.RemoveAll(dir)
-		result, err := getDetailedResults(sys, filepath.Join(dir, "abc.xml"), 2635, newCheckmarxExecuteScanUtilsMock())
+		result, _, err := getDetailedResults(sys, filepath.Join(dir, "abc.xml"), 2635, newCheckmarxExecuteScanUtilsMock())
 		assert.NoError(t, err, "error occurred but none expected")
 		assert.Equal(t, "2", result["ProjectId"], "Project ID incorrect")
 		assert.Equal(t, "Project 1", result["ProjectName"], "Project name incorrect")
@@ -514,7 +515,7 @@ func TestGetDetailedResults(t *testing.T) {
 		defer os.RemoveAll(dir)
 		utils := newCheckmarxExecuteScanUtilsMock()
 		utils.errorOnWriteFile = true
-		_, err = getDetailedResults(sys, filepath.Join(dir, "abc.xml"), 2635, utils)
+		_, _, err = getDetailedResults(sys, filepath.Join(dir, "abc.xml"), 2635, utils)
 		assert.EqualError(t, err, "failed to write file: error on WriteFile")
 	})
 }
@@ -939,6 +940,36 @@ func TestEnforceThresholds(t *testing.T) {
 	})
 }
 
+func TestExemptResults(t *testing.T) {
+	t.Parallel()
+
+	results := map[string]interface{}{
+		"High":        map[string]int{"Issues": 3, "NotFalsePositive": 3, "ToVerify": 2, "Confirmed": 1},
+		"Medium":      map[string]int{},
+		"Low":         map[string]int{},
+		"ProjectName": "project",
+	}
+	detailedResult := checkmarx.DetailedResult{Queries: []checkmarx.Query{{Name: "SQL_Injection", CweID: "89", Results: []checkmarx.Result{
+		{Severity: "High", State: "0", FileName: "src/a.java"},
+		{Severity: "High", State: "2", FileName: "src/b.java"},
+		{Severity: "High", State: "0", FileName: "src/c.java"},
+	}}}}
+	exemptions := &vulnerability.Exemptions{Exemptions: []vulnerability.Exemption{
+		{ID: "CWE-89", Justification: "input is sanitized", Owner: "security@example.com", Expires: "2099-12-31"},
+	}}
+
+	exempted := exemptResults(results, detailedResult, exemptions)
+
+	assert.Equal(t, map[string]int{"Issues": 3, "NotFalsePositive": 0, "ToVerify": 0, "Confirmed": 0, "NotExploitable": 3}, exempted["High"])
+	assert.Equal(t, "project", exempted["ProjectName"])
+	// the original results used for reporting are unchanged
+	assert.Equal(t, 3, results["High"].(map[string]int)["NotFalsePositive"])
+
+	options := checkmarxExecuteScanOptions{VulnerabilityThresholdUnit: "absolute", VulnerabilityThresholdHigh: 0, VulnerabilityThresholdEnabled: true}
+	assert.True(t, enforceThresholds(options, results))
+	assert.False(t, enforceThresholds(options, exempted))
+}
+
 func TestLoadPreset(t *testing.T) {
 	t.Parallel()
 
@@ -5,16 +5,22 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/blackduck"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 )
 
 type detectUtils interface {
@@ -34,10 +40,16 @@ type detectUtils interface {
 	SetEnv(env []string)
 	RunExecutable(e string, p ...string) error
 	RunShell(shell, script string) error
+	GetExitCode() int
 
 	DownloadFile(url, filename string, header http.Header, cookies []*http.Cookie) error
 }
 
+// detectVulnerabilityClient fetches the vulnerabilities of a scanned project version from Black Duck
+type detectVulnerabilityClient interface {
+	GetVulnerabilities(projectName, versionName string) (*blackduck.Vulnerabilities, error)
+}
+
 type detectUtilsBundle struct {
 	*command.Command
 	*piperutils.Files
@@ -64,9 +76,30 @@ func newDetectUtils() detectUtils {
 	return &utils
 }
 
+// detectPolicyViolationExitCode is the exit code of Detect in case of policy violations (FAILURE_POLICY_VIOLATION)
+const detectPolicyViolationExitCode = 3
+
 func detectExecuteScan(config detectExecuteScanOptions, _ *telemetry.CustomData) {
 	utils := newDetectUtils()
 	err := runDetect(config, utils)
+	policyViolation := err != nil && utils.GetExitCode() == detectPolicyViolationExitCode
+
+	if err != nil && !policyViolation {
+		log.Entry().
+			WithError(err).
+			Fatal("failed to execute detect scan")
+	}
+
+	if len(config.ServerURL) > 0 && len(config.Token) > 0 {
+		client := blackduck.NewClient(config.Token, config.ServerURL, &piperhttp.Client{})
+		findings, sarifErr := writeDetectSarif(config, &client, utils)
+		if sarifErr != nil {
+			log.Entry().WithError(sarifErr).Warn("failed to write SARIF report and findings")
+		} else if policyViolation && violatedByExemptedFindingsOnly(findings) {
+			log.Entry().Warnf("Detect reported policy violations, all vulnerabilities are exempted via '%v' though", vulnerability.ExemptionsFile)
+			err = nil
+		}
+	}
 
 	if err != nil {
 		log.Entry().
@@ -75,7 +108,26 @@ func detectExecuteScan(config detectExecuteScanOptions, _ *telemetry.CustomData)
 	}
 }
 
+// violatedByExemptedFindingsOnly checks if the vulnerabilities reported by Black Duck are either suppressed or exempted via the exemptions file
+// while at least one is exempted, hence a policy violation reported by Detect is caused by exempted vulnerabilities.
+func violatedByExemptedFindingsOnly(findings []vulnerability.Finding) bool {
+	exempted := false
+	for _, finding := range findings {
+		if finding.IsOpen() {
+			return false
+		}
+		if len(finding.ExemptedBy) > 0 {
+			exempted = true
+		}
+	}
+	return exempted
+}
+
 func runDetect(config detectExecuteScanOptions, utils detectUtils) error {
+	// the exemptions are applied to the findings after the scan, expired exemptions fail the build before scanning
+	if err := vulnerability.CheckExemptions(utils, time.Now()); err != nil {
+		return err
+	}
 	// detect execution details, see https://synopsys.atlassian.net/wiki/spaces/INTDOCS/pages/88440888/Sample+Synopsys+Detect+Scan+Configuration+Scenarios+for+Black+Duck
 	err := getDetectScript(config, utils)
 	if err != nil {
@@ -126,12 +178,38 @@ func getDetectScript(config detectExecuteScanOptions, utils detectUtils) error {
 	return utils.DownloadFile("https://detect.synopsys.com/detect.sh", "detect.sh", nil, nil)
 }
 
+// writeDetectSarif writes the vulnerabilities of the scanned project version as SARIF log into the step report directory
+// and as findings to be consolidated with the ones of other scanners, the findings are returned after applying the exemptions
+func writeDetectSarif(config detectExecuteScanOptions, client detectVulnerabilityClient, utils detectUtils) ([]vulnerability.Finding, error) {
+	vulnerabilities, err := client.GetVulnerabilities(config.ProjectName, getDetectVersionName(config))
+	if err != nil {
+		return nil, err
+	}
+	sarif := blackduck.ConvertVulnerabilitiesToSarif(vulnerabilities, config.ProjectName, getDetectVersionName(config))
+	if err := format.WriteSARIF(sarif, filepath.Join(reporting.StepReportDirectory, "detectExecuteScan_oss.sarif"), utils); err != nil {
+		return nil, err
+	}
+	findings, err := vulnerability.ApplyExemptions(blackduck.ConvertVulnerabilitiesToFindings(vulnerabilities), utils, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := vulnerability.WriteFindings(findings, "detectExecuteScan_oss", utils); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func getDetectVersionName(config detectExecuteScanOptions) string {
+	if len(config.CustomScanVersion) > 0 {
+		return config.CustomScanVersion
+	}
+	return versioning.ApplyVersioningModel(config.VersioningModel, config.Version)
+}
+
 func addDetectArgs(args []string, config detectExecuteScanOptions, utils detectUtils) ([]string, error) {
-	detectVersionName := config.CustomScanVersion
-	if len(detectVersionName) > 0 {
+	detectVersionName := getDetectVersionName(config)
+	if len(config.CustomScanVersion) > 0 {
 		log.Entry().Infof("Using custom version: %v", detectVersionName)
-	} else {
-		detectVersionName = versioning.ApplyVersioningModel(config.VersioningModel, config.Version)
 	}
 	//Split on spaces, the scanPropeties, so that each property is available as a single string
 	//instead of all properties being part of a single string
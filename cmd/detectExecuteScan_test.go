@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/blackduck"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -76,6 +78,15 @@ func TestRunDetect(t *testing.T) {
 		assert.True(t, utilsMock.HasRemovedFile("detect.sh"))
 	})
 
+	t.Run("failure case - expired exemptions", func(t *testing.T) {
+		t.Parallel()
+		utilsMock := newDetectTestUtilsBundle()
+		utilsMock.AddFile(".pipeline/exemptions.yml", []byte("exemptions:\n  - id: CVE-2019-0001\n    justification: not reachable\n    owner: jane.doe\n    expires: 2020-01-31\n"))
+		err := runDetect(detectExecuteScanOptions{}, utilsMock)
+		assert.EqualError(t, err, "1 exemptions in '.pipeline/exemptions.yml' are expired, please review them: CVE-2019-0001 (owner: jane.doe, expired: 2020-01-31)")
+		assert.Len(t, utilsMock.Calls, 0)
+	})
+
 	t.Run("maven parameters", func(t *testing.T) {
 		t.Parallel()
 		utilsMock := newDetectTestUtilsBundle()
@@ -411,3 +422,58 @@ func TestAddDetectArgs(t *testing.T) {
 		})
 	}
 }
+
+type detectVulnerabilityClientMock struct {
+	vulnerabilities *blackduck.Vulnerabilities
+	err             error
+}
+
+func (c *detectVulnerabilityClientMock) GetVulnerabilities(projectName, versionName string) (*blackduck.Vulnerabilities, error) {
+	return c.vulnerabilities, c.err
+}
+
+func TestWriteDetectSarif(t *testing.T) {
+	t.Parallel()
+	config := detectExecuteScanOptions{ProjectName: "SHC-PiperTest", Version: "1.2.3", VersioningModel: "major"}
+
+	t.Run("success case", func(t *testing.T) {
+		t.Parallel()
+		utilsMock := newDetectTestUtilsBundle()
+		client := &detectVulnerabilityClientMock{vulnerabilities: &blackduck.Vulnerabilities{Items: []blackduck.Vulnerability{
+			{Name: "log4j", Version: "2.14.1", VulnerabilityWithRemediation: blackduck.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2021-44228", BaseScore: 10.0}},
+		}}}
+
+		findings, err := writeDetectSarif(config, client, utilsMock)
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		content, err := utilsMock.FileRead(filepath.Join(".pipeline", "stepReports", "detectExecuteScan_oss.sarif"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"text": "CVE-2021-44228 in log4j:2.14.1"`)
+		assert.Contains(t, string(content), `"blackduckVulnerability": "SHC-PiperTest/1/log4j:2.14.1/CVE-2021-44228"`)
+	})
+
+	t.Run("failure case", func(t *testing.T) {
+		t.Parallel()
+		utilsMock := newDetectTestUtilsBundle()
+		client := &detectVulnerabilityClientMock{err: fmt.Errorf("project 'SHC-PiperTest' not found")}
+
+		_, err := writeDetectSarif(config, client, utilsMock)
+
+		assert.EqualError(t, err, "project 'SHC-PiperTest' not found")
+	})
+}
+
+func TestViolatedByExemptedFindingsOnly(t *testing.T) {
+	t.Parallel()
+
+	exempted := vulnerability.Finding{ID: "CVE-2021-44228", Status: vulnerability.StatusSuppressed, ExemptedBy: "security@example.com"}
+	ignored := vulnerability.Finding{ID: "CVE-2021-45046", Status: vulnerability.StatusSuppressed, Justification: "IGNORED"}
+	open := vulnerability.Finding{ID: "CVE-2022-22965", Status: vulnerability.StatusOpen}
+
+	assert.True(t, violatedByExemptedFindingsOnly([]vulnerability.Finding{exempted, ignored}))
+	assert.False(t, violatedByExemptedFindingsOnly([]vulnerability.Finding{exempted, open}))
+	// without exempted vulnerabilities the violation is caused by something else, e.g. a license policy
+	assert.False(t, violatedByExemptedFindingsOnly([]vulnerability.Finding{ignored}))
+	assert.False(t, violatedByExemptedFindingsOnly(nil))
+}
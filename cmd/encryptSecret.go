@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type encryptSecretCommandOptions struct {
+	file        string
+	path        string
+	key         string
+	generateKey bool
+}
+
+var encryptSecretOptions encryptSecretCommandOptions
+
+type encryptSecretUtils interface {
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// EncryptSecretCommand is the entry command for adding secrets to an encrypted secret file
+func EncryptSecretCommand() *cobra.Command {
+	var encryptSecretCmd = &cobra.Command{
+		Use:   "encryptSecret",
+		Short: "Adds a secret to an encrypted secret file.",
+		Long: `Encrypts a secret value read from stdin and adds it to an encrypted secret file (default: .pipeline/secrets.piper.yaml)
+which can be used as secret provider via the parameter encryptedSecretFile.
+The base64 encoded AES-256 key is read from the environment variable ` + config.EncryptedSecretFileKeyEnv + `,
+a new key can be generated with --generateKey.
+
+Example: echo -n "$TOKEN" | piper encryptSecret --path team1/github --key token`,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := encryptSecret(encryptSecretOptions, os.Getenv(config.EncryptedSecretFileKeyEnv), os.Stdin, os.Stdout, &piperutils.Files{}); err != nil {
+				log.Entry().WithError(err).Fatal("failed to encrypt secret")
+			}
+		},
+	}
+
+	addEncryptSecretFlags(encryptSecretCmd)
+	return encryptSecretCmd
+}
+
+func encryptSecret(options encryptSecretCommandOptions, key string, stdin io.Reader, stdout io.Writer, utils encryptSecretUtils) error {
+	if options.generateKey {
+		generatedKey, err := config.GenerateSecretFileKey()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, generatedKey)
+		return nil
+	}
+
+	if len(options.path) == 0 || len(options.key) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("the flags --path and --key are required")
+	}
+	if len(key) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Errorf("no key provided, please set environment variable %v", config.EncryptedSecretFileKeyEnv)
+	}
+
+	// the value is read from stdin in order to keep it out of the shell history and the process list
+	value, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return errors.Wrap(err, "failed to read secret value from stdin")
+	}
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "\n"), "\r")
+	if len(value) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("no secret value provided via stdin")
+	}
+
+	var content []byte
+	if exists, _ := utils.FileExists(options.file); exists {
+		if content, err = utils.FileRead(options.file); err != nil {
+			return errors.Wrapf(err, "failed to read encrypted secret file '%v'", options.file)
+		}
+	}
+	content, err = config.SetEncryptedSecret(content, key, options.path, options.key, value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encrypt secret for '%v'", options.file)
+	}
+	if err := utils.FileWrite(options.file, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write encrypted secret file '%v'", options.file)
+	}
+	log.Entry().Infof("Secret '%v' with key '%v' written to '%v'", options.path, options.key, options.file)
+	return nil
+}
+
+func addEncryptSecretFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&encryptSecretOptions.file, "file", ".pipeline/secrets.piper.yaml", "Path of the encrypted secret file, it is created in case it does not exist")
+	cmd.Flags().StringVar(&encryptSecretOptions.path, "path", "", "Path of the secret, e.g. <vaultPath>/github")
+	cmd.Flags().StringVar(&encryptSecretOptions.key, "key", "", "Key of the value within the secret, e.g. token")
+	cmd.Flags().BoolVar(&encryptSecretOptions.generateKey, "generateKey", false, "Generates a new base64 encoded key instead of encrypting a secret")
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptSecretCommand(t *testing.T) {
+	cmd := EncryptSecretCommand()
+
+	gotReq := []string{}
+	gotOpt := []string{}
+
+	cmd.Flags().VisitAll(func(pflag *flag.Flag) {
+		annotations, found := pflag.Annotations[cobra.BashCompOneRequiredFlag]
+		if found && annotations[0] == "true" {
+			gotReq = append(gotReq, pflag.Name)
+		} else {
+			gotOpt = append(gotOpt, pflag.Name)
+		}
+	})
+
+	t.Run("Required flags", func(t *testing.T) {
+		exp := []string{}
+		assert.Equal(t, exp, gotReq, "required flags incorrect")
+	})
+
+	t.Run("Optional flags", func(t *testing.T) {
+		exp := []string{"file", "generateKey", "key", "path"}
+		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
+	})
+}
+
+func TestEncryptSecret(t *testing.T) {
+	key, err := config.GenerateSecretFileKey()
+	assert.NoError(t, err)
+	options := encryptSecretCommandOptions{file: ".pipeline/secrets.piper.yaml", path: "team1/github", key: "token"}
+
+	t.Run("generate key", func(t *testing.T) {
+		var stdout bytes.Buffer
+		err := encryptSecret(encryptSecretCommandOptions{generateKey: true}, "", strings.NewReader(""), &stdout, &mock.FilesMock{})
+		assert.NoError(t, err)
+		assert.Len(t, strings.TrimSpace(stdout.String()), 44)
+	})
+
+	t.Run("create and update file", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		assert.NoError(t, encryptSecret(options, key, strings.NewReader("secret1\n"), &bytes.Buffer{}, files))
+		otherOptions := options
+		otherOptions.key = "user"
+		assert.NoError(t, encryptSecret(otherOptions, key, strings.NewReader("user1"), &bytes.Buffer{}, files))
+
+		content, err := files.FileRead(options.file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "team1/github:")
+		assert.Contains(t, string(content), "token: PIPER_SECRET[v1,")
+		assert.Contains(t, string(content), "user: PIPER_SECRET[v1,")
+		assert.NotContains(t, string(content), "secret1")
+		assert.NotContains(t, string(content), "user1")
+		info, err := files.Stat(options.file)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode())
+	})
+
+	t.Run("error - SOPS file", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		files.AddFile(options.file, []byte("secrets:\n  team1/github:\n    token: ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]\nsops:\n  version: 3.7.1\n"))
+		err := encryptSecret(options, key, strings.NewReader("secret1"), &bytes.Buffer{}, files)
+		assert.EqualError(t, err, "failed to encrypt secret for '.pipeline/secrets.piper.yaml': failed to parse encrypted secret file: files encrypted by SOPS are not supported, please use a file written by 'piper encryptSecret'")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		err := encryptSecret(options, "", strings.NewReader("secret1"), &bytes.Buffer{}, &mock.FilesMock{})
+		assert.EqualError(t, err, "no key provided, please set environment variable "+config.EncryptedSecretFileKeyEnv)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		err := encryptSecret(options, key, strings.NewReader(""), &bytes.Buffer{}, &mock.FilesMock{})
+		assert.EqualError(t, err, "no secret value provided via stdin")
+	})
+
+	t.Run("missing flags", func(t *testing.T) {
+		err := encryptSecret(encryptSecretCommandOptions{file: options.file}, key, strings.NewReader("secret1"), &bytes.Buffer{}, &mock.FilesMock{})
+		assert.EqualError(t, err, "the flags --path and --key are required")
+	})
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 
@@ -292,6 +293,30 @@ func verifyFFProjectCompliance(config fortifyExecuteScanOptions, sys fortify.Sys
 	scanReport := fortify.CreateCustomReport(prepareReportData(influx), issueGroups)
 	paths, err := fortify.WriteCustomReports(scanReport, influx.fortify_data.fields.projectName, influx.fortify_data.fields.projectVersion)
 	reports = append(reports, paths...)
+
+	// expired exemptions fail the build independent of the issue details
+	exemptions, err := vulnerability.ValidExemptions(&piperutils.Files{}, time.Now())
+	if err != nil {
+		return err, reports
+	}
+	issues, err := sys.GetAllIssueDetails(projectVersion.ID)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to fetch issue details, no SARIF report created and exemptions not applied to the violations")
+	} else {
+		sarifPath, err := fortify.WriteSarif(fortify.ConvertIssuesToSarif(issues, config.ServerURL, projectVersion.ID), *project.Name, *projectVersion.Name)
+		if err != nil {
+			log.Entry().WithError(err).Warn("failed to write SARIF report")
+		} else {
+			reports = append(reports, sarifPath)
+		}
+		if err := fortify.WriteFindings(exemptions.Apply(fortify.ConvertIssuesToFindings(issues)), *project.Name, *projectVersion.Name); err != nil {
+			log.Entry().WithError(err).Warn("failed to write findings")
+		}
+		if exempted := exemptedViolations(config, issues, filterSet, exemptions); exempted > 0 {
+			log.Entry().Infof("%v violations exempted via '%v'", exempted, vulnerability.ExemptionsFile)
+			numberOfViolations -= exempted
+		}
+	}
 	if numberOfViolations > 0 {
 		log.SetErrorCategory(log.ErrorCompliance)
 		return errors.New("fortify scan failed, the project is not compliant. For details check the archived report"), reports
@@ -299,6 +324,44 @@ func verifyFFProjectCompliance(config fortifyExecuteScanOptions, sys fortify.Sys
 	return nil, reports
 }
 
+// exemptedViolations counts the issues exempted via the exemptions file which are counted as violations, i.e. unaudited issues
+// of the issue groups which must be audited and issues audited as exploitable or suspicious. Spot checks are not affected by exemptions.
+func exemptedViolations(config fortifyExecuteScanOptions, issues []*models.ProjectVersionIssue, filterSet *models.FilterSet, exemptions *vulnerability.Exemptions) int {
+	folders := map[string]string{}
+	if filterSet != nil {
+		for _, folder := range filterSet.Folders {
+			if folder != nil {
+				folders[folder.GUID] = folder.Name
+			}
+		}
+	}
+	exempted := 0
+	for _, issue := range issues {
+		if issue == nil || (issue.Removed != nil && *issue.Removed) {
+			continue
+		}
+		finding := fortify.ConvertIssueToFinding(issue)
+		if !finding.IsOpen() || exemptions.Match(&finding) == nil {
+			continue
+		}
+		analysis := ""
+		if issue.PrimaryTag != nil {
+			analysis = *issue.PrimaryTag
+		}
+		folder := ""
+		if issue.FolderGUID != nil {
+			folder = folders[*issue.FolderGUID]
+		}
+		switch {
+		case !issue.Audited && len(folder) > 0 && strings.Contains(config.MustAuditIssueGroups, folder):
+			exempted++
+		case analysis == "Exploitable", analysis == "Suspicious" && config.ConsiderSuspicious:
+			exempted++
+		}
+	}
+	return exempted
+}
+
 func prepareReportData(influx *fortifyExecuteScanInflux) fortify.FortifyReportData {
 	input := influx.fortify_data.fields
 	output := fortify.FortifyReportData{}
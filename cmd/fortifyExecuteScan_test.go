@@ -20,6 +20,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/fortify"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 
 	"github.com/google/go-github/v32/github"
 	"github.com/stretchr/testify/assert"
@@ -253,6 +254,11 @@ func (f *fortifyMock) GetIssueStatisticsOfProjectVersion(id int64) ([]*models.Is
 	suppressed := int32(6)
 	return []*models.IssueStatistics{{SuppressedCount: &suppressed}}, nil
 }
+func (f *fortifyMock) GetAllIssueDetails(projectVersionID int64) ([]*models.ProjectVersionIssue, error) {
+	name := "SQL Injection"
+	friority := "Critical"
+	return []*models.ProjectVersionIssue{{ID: 1111, IssueName: &name, Friority: &friority}}, nil
+}
 func (f *fortifyMock) GenerateQGateReport(projectID, projectVersionID, reportTemplateID int64, projectName, projectVersionName, reportFormat string) (*models.SavedReport, error) {
 	if !f.Successive {
 		f.Successive = true
@@ -450,6 +456,31 @@ func TestAnalyseSuspiciousExploitable(t *testing.T) {
 	assert.Equal(t, 6, influx.fortify_data.fields.suppressed)
 }
 
+func TestExemptedViolations(t *testing.T) {
+	config := fortifyExecuteScanOptions{MustAuditIssueGroups: "Audit All, Corporate Security Requirements"}
+	filterSet := models.FilterSet{Folders: []*models.FolderDto{{GUID: "folder-1", Name: "Audit All"}, {GUID: "folder-2", Name: "Spot Checks of Each Category"}}}
+	issue := func(name, folder, analysis string, audited, suppressed bool) *models.ProjectVersionIssue {
+		return &models.ProjectVersionIssue{IssueName: &name, FolderGUID: &folder, PrimaryTag: &analysis, Audited: audited, Suppressed: &suppressed}
+	}
+	issues := []*models.ProjectVersionIssue{
+		issue("SQL Injection", "folder-1", "", false, false),
+		issue("SQL Injection", "folder-2", "", false, false),
+		issue("SQL Injection", "folder-2", "Exploitable", true, false),
+		issue("SQL Injection", "folder-2", "Suspicious", true, false),
+		issue("SQL Injection", "folder-1", "Not an Issue", true, true),
+		issue("Cross-Site Scripting", "folder-1", "", false, false),
+		nil,
+	}
+	exemptions := &vulnerability.Exemptions{Exemptions: []vulnerability.Exemption{
+		{ID: "SQL Injection", Justification: "prepared statements", Owner: "security@example.com", Expires: "2099-12-31"},
+	}}
+
+	assert.Equal(t, 2, exemptedViolations(config, issues, &filterSet, exemptions))
+	config.ConsiderSuspicious = true
+	assert.Equal(t, 3, exemptedViolations(config, issues, &filterSet, exemptions))
+	assert.Equal(t, 0, exemptedViolations(config, issues, &filterSet, &vulnerability.Exemptions{}))
+}
+
 func TestAnalyseUnauditedIssues(t *testing.T) {
 	config := fortifyExecuteScanOptions{SpotCheckMinimum: 4, MustAuditIssueGroups: "Audit All, Corporate Security Requirements", SpotAuditIssueGroups: "Spot Checks of Each Category"}
 	ff := fortifyMock{}
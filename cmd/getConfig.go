@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -21,6 +23,7 @@ type configCommandOptions struct {
 	stepMetadata   string //metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	stepName       string
 	contextConfig  bool
+	explain        bool
 	openFile       func(s string) (io.ReadCloser, error)
 }
 
@@ -69,6 +72,7 @@ func generateConfig() error {
 	prepareOutputEnvironment(metadata.Spec.Outputs.Resources, GeneralConfig.EnvRootPath)
 
 	resourceParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+	myConfig.SetCommonPipelineEnvironmentPath(filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment"))
 
 	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
 
@@ -98,11 +102,17 @@ func generateConfig() error {
 
 	var flags map[string]interface{}
 
+	if configOptions.explain {
+		myConfig.TrackProvenance()
+	}
+
 	params := []config.StepParameters{}
 	if !configOptions.contextConfig {
 		params = metadata.Spec.Inputs.Parameters
 	}
 
+	// the configuration is only printed, thus leases of dynamic secrets are revoked once this command finished
+	defer config.RevokeVaultCredentials()
 	stepConfig, err = myConfig.GetStepConfig(flags, GeneralConfig.ParametersJSON, customConfig, defaultConfig, GeneralConfig.IgnoreCustomDefaults, paramFilter, params, metadata.Spec.Inputs.Secrets, resourceParams, GeneralConfig.StageName, metadata.Metadata.Name, metadata.Metadata.Aliases)
 	if err != nil {
 		return errors.Wrap(err, "getting step config failed")
@@ -113,6 +123,10 @@ func generateConfig() error {
 		applyContextConditions(metadata, &stepConfig)
 	}
 
+	if configOptions.explain {
+		return printProvenance(os.Stdout, stepConfig.GetProvenance(metadata.Spec.Inputs.Parameters, metadata.Spec.Inputs.Secrets), configOptions.output)
+	}
+
 	myConfigJSON, _ := config.GetJSON(stepConfig.Config)
 
 	fmt.Println(myConfigJSON)
@@ -120,15 +134,60 @@ func generateConfig() error {
 	return nil
 }
 
+func printProvenance(w io.Writer, provenance []config.ParameterProvenance, output string) error {
+	if output == "json" {
+		provenanceJSON, err := json.MarshalIndent(provenance, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal configuration provenance")
+		}
+		fmt.Fprintln(w, string(provenanceJSON))
+		return nil
+	}
+
+	for _, p := range provenance {
+		fmt.Fprintf(w, "%v: %v\n", p.Name, formatProvenanceValue(p.Value))
+		for i, origin := range p.Origins {
+			details := []string{}
+			if origin.Source != "" {
+				details = append(details, origin.Source)
+			}
+			if origin.Section != "" {
+				details = append(details, origin.Section)
+			}
+			if origin.Alias != "" {
+				details = append(details, fmt.Sprintf("alias: %v", origin.Alias))
+			}
+			location := ""
+			if len(details) > 0 {
+				location = fmt.Sprintf(" (%v)", strings.Join(details, ", "))
+			}
+			fmt.Fprintf(w, "  %v. %v%v = %v\n", i+1, origin.Layer, location, formatProvenanceValue(origin.Value))
+		}
+	}
+	return nil
+}
+
+func formatProvenanceValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		valueJSON, err := json.Marshal(value)
+		if err == nil {
+			return string(valueJSON)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
 func addConfigFlags(cmd *cobra.Command) {
 
 	//ToDo: support more output options, like https://kubernetes.io/docs/reference/kubectl/overview/#formatting-output
-	cmd.Flags().StringVar(&configOptions.output, "output", "json", "Defines the output format")
+	cmd.Flags().StringVar(&configOptions.output, "output", "json", "Defines the output format (json, text)")
 
 	cmd.Flags().StringVar(&configOptions.parametersJSON, "parametersJSON", os.Getenv("PIPER_parametersJSON"), "Parameters to be considered in JSON format")
 	cmd.Flags().StringVar(&configOptions.stepMetadata, "stepMetadata", "", "Step metadata, passed as path to yaml")
 	cmd.Flags().StringVar(&configOptions.stepName, "stepName", "", "Step name, used to get step metadata if yaml path is not set")
 	cmd.Flags().BoolVar(&configOptions.contextConfig, "contextConfig", false, "Defines if step context configuration should be loaded instead of step config")
+	cmd.Flags().BoolVar(&configOptions.explain, "explain", false, "Explains for every parameter from which layer (defaults, configuration, environment, flags, ...) its value originates. Use '--output text' for a human readable format")
 
 }
 
@@ -50,7 +50,7 @@ func TestConfigCommand(t *testing.T) {
 	})
 
 	t.Run("Optional flags", func(t *testing.T) {
-		exp := []string{"contextConfig", "output", "parametersJSON", "stepMetadata", "stepName"}
+		exp := []string{"contextConfig", "explain", "output", "parametersJSON", "stepMetadata", "stepName"}
 		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
 	})
 
@@ -333,3 +333,43 @@ func TestResolveMetadata(t *testing.T) {
 		assert.EqualError(t, err, "either one of stepMetadata or stepName parameter has to be passed")
 	})
 }
+
+func TestPrintProvenance(t *testing.T) {
+	provenance := []config.ParameterProvenance{
+		{
+			Name:  "buildTool",
+			Value: "maven",
+			Origins: []config.ValueOrigin{
+				{Layer: config.LayerDefaults, Source: "defaults #1", Section: "general", Value: "npm"},
+				{Layer: config.LayerConfig, Source: ".pipeline/config.yml", Section: "steps/myStep", Alias: "tool", Value: "maven"},
+			},
+		},
+		{
+			Name:    "password",
+			Value:   "****",
+			Secret:  true,
+			Origins: []config.ValueOrigin{{Layer: config.LayerFlag, Value: "****"}},
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var out strings.Builder
+		err := printProvenance(&out, provenance, "text")
+		assert.NoError(t, err)
+		assert.Equal(t, `buildTool: maven
+  1. defaults (defaults #1, general) = npm
+  2. config (.pipeline/config.yml, steps/myStep, alias: tool) = maven
+password: ****
+  1. flag = ****
+`, out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out strings.Builder
+		err := printProvenance(&out, provenance, "json")
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `"name": "buildTool"`)
+		assert.Contains(t, out.String(), `"alias": "tool"`)
+		assert.Contains(t, out.String(), `"secret": true`)
+	})
+}
@@ -3,13 +3,22 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"mime"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/changelog"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	gitUtils "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-github/v32/github"
 	"github.com/pkg/errors"
 
@@ -28,6 +37,24 @@ type githubIssueClient interface {
 	ListByRepo(ctx context.Context, owner string, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
 }
 
+var releaseCommits = releaseCommitsDefault
+
+// releaseCommitsDefault provides the commits of the local repository since the tag of the last release, all commits in case of the first release
+func releaseCommitsDefault(lastTag string) (object.CommitIter, error) {
+	repository, err := gitUtils.PlainOpen(".")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open git repository")
+	}
+	if len(lastTag) == 0 {
+		head, err := repository.Head()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve HEAD")
+		}
+		return repository.Log(&git.LogOptions{From: head.Hash()})
+	}
+	return gitUtils.LogRange(repository, plumbing.NewTagReferenceName(lastTag).String(), "HEAD")
+}
+
 func githubPublishRelease(config githubPublishReleaseOptions, telemetryData *telemetry.CustomData) {
 	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, config.UploadURL)
 	if err != nil {
@@ -59,7 +86,7 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 
 	//updating assets only supported on latest release
 	if len(config.AssetPath) > 0 && config.Version == "latest" {
-		return uploadReleaseAsset(ctx, lastRelease.GetID(), config, ghRepoClient)
+		return uploadReleaseAssets(ctx, lastRelease.GetID(), config, ghRepoClient)
 	}
 
 	releaseBody := ""
@@ -68,6 +95,14 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 		releaseBody += config.ReleaseBodyHeader + "\n"
 	}
 
+	if config.AddChangelog {
+		changelogText, err := getChangelogText(config, lastRelease)
+		if err != nil {
+			return err
+		}
+		releaseBody += changelogText
+	}
+
 	if config.AddClosedIssues {
 		releaseBody += getClosedIssuesText(ctx, publishedAt, config, ghIssueClient)
 	}
@@ -90,11 +125,7 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 	}
 	log.Entry().Infof("Release %v created on %v/%v", *createdRelease.TagName, config.Owner, config.Repository)
 
-	if len(config.AssetPath) > 0 {
-		return uploadReleaseAsset(ctx, createdRelease.GetID(), config, ghRepoClient)
-	}
-
-	return nil
+	return uploadReleaseAssets(ctx, createdRelease.GetID(), config, ghRepoClient)
 }
 
 func getClosedIssuesText(ctx context.Context, publishedAt github.Timestamp, config *githubPublishReleaseOptions, ghIssueClient githubIssueClient) string {
@@ -136,6 +167,40 @@ func getClosedIssuesText(ctx context.Context, publishedAt github.Timestamp, conf
 	return closedIssuesText
 }
 
+func getChangelogText(config *githubPublishReleaseOptions, lastRelease *github.RepositoryRelease) (string, error) {
+	commits, err := releaseCommits(lastRelease.GetTagName())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve commits since last release")
+	}
+	defer commits.Close()
+
+	release, err := changelog.NewRelease(config.Version, time.Now(), commits)
+	if err != nil {
+		return "", err
+	}
+	release.RepositoryURL = fmt.Sprintf("%v/%v/%v", config.ServerURL, config.Owner, config.Repository)
+	if len(lastRelease.GetTagName()) > 0 {
+		release.CompareURL = fmt.Sprintf("%v/compare/%v...%v", release.RepositoryURL, lastRelease.GetTagName(), config.Version)
+	}
+
+	if len(config.ChangelogFile) > 0 {
+		content, err := ioutil.ReadFile(config.ChangelogFile)
+		if err != nil && !os.IsNotExist(err) {
+			return "", errors.Wrapf(err, "failed to read changelog '%v'", config.ChangelogFile)
+		}
+		content = changelog.UpdateChangelog(content, release)
+		if err := ioutil.WriteFile(config.ChangelogFile, content, 0644); err != nil {
+			return "", errors.Wrapf(err, "failed to write changelog '%v'", config.ChangelogFile)
+		}
+		log.Entry().Infof("Changelog '%v' updated with release %v", config.ChangelogFile, config.Version)
+	}
+
+	if release.Empty() {
+		log.Entry().Info("No changes following the Conventional Commits specification found since last release.")
+	}
+	return "\n" + release.Notes(), nil
+}
+
 func getReleaseDeltaText(config *githubPublishReleaseOptions, lastRelease *github.RepositoryRelease) string {
 	releaseDeltaText := ""
 
@@ -154,7 +219,28 @@ func getReleaseDeltaText(config *githubPublishReleaseOptions, lastRelease *githu
 	return releaseDeltaText
 }
 
-func uploadReleaseAsset(ctx context.Context, releaseID int64, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) error {
+// uploadReleaseAssets uploads the asset as well as the BOM of the release in XML and JSON format if available
+func uploadReleaseAssets(ctx context.Context, releaseID int64, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) error {
+	assetPaths := []string{}
+	if len(config.AssetPath) > 0 {
+		assetPaths = append(assetPaths, config.AssetPath)
+	}
+	if len(config.SbomPath) > 0 {
+		for _, sbomPath := range []string{config.SbomPath, cyclonedx.JSONPath(config.SbomPath)} {
+			if exists, _ := piperutils.FileExists(sbomPath); exists {
+				assetPaths = append(assetPaths, sbomPath)
+			}
+		}
+	}
+	for _, assetPath := range assetPaths {
+		if err := uploadReleaseAsset(ctx, releaseID, assetPath, config, ghRepoClient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func uploadReleaseAsset(ctx context.Context, releaseID int64, assetPath string, config *githubPublishReleaseOptions, ghRepoClient githubRepoClient) error {
 
 	assets, _, err := ghRepoClient.ListReleaseAssets(ctx, config.Owner, config.Repository, releaseID, &github.ListOptions{})
 	if err != nil {
@@ -162,7 +248,7 @@ func uploadReleaseAsset(ctx context.Context, releaseID int64, config *githubPubl
 	}
 	var assetID int64
 	for _, a := range assets {
-		if a.GetName() == filepath.Base(config.AssetPath) {
+		if a.GetName() == filepath.Base(assetPath) {
 			assetID = a.GetID()
 			break
 		}
@@ -175,23 +261,23 @@ func uploadReleaseAsset(ctx context.Context, releaseID int64, config *githubPubl
 		}
 	}
 
-	mediaType := mime.TypeByExtension(filepath.Ext(config.AssetPath))
+	mediaType := mime.TypeByExtension(filepath.Ext(assetPath))
 	if mediaType == "" {
 		mediaType = "application/octet-stream"
 	}
 	log.Entry().Debugf("Using mediaType '%v'", mediaType)
 
-	name := filepath.Base(config.AssetPath)
+	name := filepath.Base(assetPath)
 	log.Entry().Debugf("Using file name '%v'", name)
 
 	opts := github.UploadOptions{
 		Name:      name,
 		MediaType: mediaType,
 	}
-	file, err := os.Open(config.AssetPath)
+	file, err := os.Open(assetPath)
 	defer file.Close()
 	if err != nil {
-		return errors.Wrapf(err, "Failed to load release asset '%v'", config.AssetPath)
+		return errors.Wrapf(err, "Failed to load release asset '%v'", assetPath)
 	}
 
 	log.Entry().Info("Starting to upload release asset.")
@@ -3,14 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-github/v32/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type ghRCMock struct {
@@ -34,6 +38,7 @@ type ghRCMock struct {
 	uploadOpts        *github.UploadOptions
 	uploadOwner       string
 	uploadRepo        string
+	uploadNames       []string
 }
 
 func (g *ghRCMock) CreateRelease(ctx context.Context, owner string, repo string, release *github.RepositoryRelease) (*github.RepositoryRelease, *github.Response, error) {
@@ -75,6 +80,7 @@ func (g *ghRCMock) UploadReleaseAsset(ctx context.Context, owner string, repo st
 	g.uploadOwner = owner
 	g.uploadRepo = repo
 	g.uploadOpts = opt
+	g.uploadNames = append(g.uploadNames, opt.Name)
 	return nil, nil, nil
 }
 
@@ -167,6 +173,59 @@ func TestRunGithubPublishRelease(t *testing.T) {
 		assert.Equal(t, lastPublishedAt.Time, ghIssueClient.lastPublished)
 	})
 
+	t.Run("Success - with changelog", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "githubPublishRelease")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var fromTag string
+		defer func() { releaseCommits = releaseCommitsDefault }()
+		releaseCommits = func(lastTag string) (object.CommitIter, error) {
+			fromTag = lastTag
+			return &commitIterMock{commits: []*object.Commit{
+				{Hash: plumbing.NewHash("1111111111111111111111111111111111111111"), Author: object.Signature{Name: "Jane Doe"}, Message: "feat: support arrays (#3)"},
+				{Hash: plumbing.NewHash("2222222222222222222222222222222222222222"), Author: object.Signature{Name: "John Doe"}, Message: "chore: bump dependencies"},
+			}}, nil
+		}
+
+		lastTag := "1.0"
+		ghRepoClient := ghRCMock{
+			latestRelease: &github.RepositoryRelease{TagName: &lastTag},
+		}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			AddChangelog:  true,
+			ChangelogFile: filepath.Join(dir, "CHANGELOG.md"),
+			Owner:         "TEST",
+			Repository:    "test",
+			ServerURL:     "https://github.com",
+			Version:       "1.1",
+		}
+		err = runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0", fromTag)
+		assert.Equal(t, "\n### Added\n\n- support arrays ([#3](https://github.com/TEST/test/issues/3)) ([1111111](https://github.com/TEST/test/commit/1111111111111111111111111111111111111111))\n\n### Contributors\n\n- Jane Doe\n- John Doe\n\n", ghRepoClient.release.GetBody())
+		content, err := ioutil.ReadFile(myGithubPublishReleaseOptions.ChangelogFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "## [1.1] - ")
+		assert.Contains(t, string(content), "[1.1]: https://github.com/TEST/test/compare/1.0...1.1\n")
+		assert.NotContains(t, string(content), "Contributors")
+	})
+
+	t.Run("Error - changelog commits", func(t *testing.T) {
+		defer func() { releaseCommits = releaseCommitsDefault }()
+		releaseCommits = func(string) (object.CommitIter, error) {
+			return nil, fmt.Errorf("reference not found")
+		}
+		ghRepoClient := ghRCMock{latestStatusCode: 404, latestErr: fmt.Errorf("not found")}
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{AddChangelog: true, Owner: "TEST", Repository: "test", Version: "1.0"}
+
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghICMock{})
+
+		assert.EqualError(t, err, "failed to retrieve commits since last release: reference not found")
+		assert.Nil(t, ghRepoClient.release)
+	})
+
 	t.Run("Success - update asset", func(t *testing.T) {
 		var releaseID int64 = 1
 		ghIssueClient := ghICMock{}
@@ -191,6 +250,32 @@ func TestRunGithubPublishRelease(t *testing.T) {
 		assert.Equal(t, releaseID, ghRepoClient.uploadID)
 	})
 
+	t.Run("Success - with BOM", func(t *testing.T) {
+		dir := t.TempDir()
+		sbomPath := filepath.Join(dir, "bom.xml")
+		require.NoError(t, ioutil.WriteFile(sbomPath, []byte("<bom/>"), 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bom.json"), []byte("{}"), 0644))
+
+		ghIssueClient := ghICMock{}
+		ghRepoClient := ghRCMock{
+			latestStatusCode: 404,
+			latestErr:        fmt.Errorf("not found"),
+		}
+
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			Owner:      "TEST",
+			Repository: "test",
+			Version:    "1.0",
+			AssetPath:  filepath.Join("testdata", "TestRunGithubPublishRelease", "Success_-_update_asset_test.txt"),
+			SbomPath:   sbomPath,
+		}
+
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+
+		assert.NoError(t, err, "Error occurred but none expected.")
+		assert.Equal(t, []string{"Success_-_update_asset_test.txt", "bom.xml", "bom.json"}, ghRepoClient.uploadNames)
+	})
+
 	t.Run("Error - get release", func(t *testing.T) {
 		ghIssueClient := ghICMock{}
 		ghRepoClient := ghRCMock{
@@ -325,7 +410,7 @@ func TestUploadReleaseAsset(t *testing.T) {
 			AssetPath:  filepath.Join("testdata", t.Name()+"_test.txt"),
 		}
 
-		err := uploadReleaseAsset(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+		err := uploadReleaseAsset(ctx, releaseID, myGithubPublishReleaseOptions.AssetPath, &myGithubPublishReleaseOptions, &ghRepoClient)
 
 		assert.NoError(t, err, "Error occurred but none expected.")
 
@@ -362,7 +447,7 @@ func TestUploadReleaseAsset(t *testing.T) {
 			AssetPath:  filepath.Join("testdata", t.Name()+"_test.txt"),
 		}
 
-		err := uploadReleaseAsset(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+		err := uploadReleaseAsset(ctx, releaseID, myGithubPublishReleaseOptions.AssetPath, &myGithubPublishReleaseOptions, &ghRepoClient)
 
 		assert.NoError(t, err, "Error occurred but none expected.")
 
@@ -376,7 +461,7 @@ func TestUploadReleaseAsset(t *testing.T) {
 		}
 		myGithubPublishReleaseOptions := githubPublishReleaseOptions{}
 
-		err := uploadReleaseAsset(ctx, releaseID, &myGithubPublishReleaseOptions, &ghRepoClient)
+		err := uploadReleaseAsset(ctx, releaseID, myGithubPublishReleaseOptions.AssetPath, &myGithubPublishReleaseOptions, &ghRepoClient)
 		assert.Equal(t, "Failed to get list of release assets.: List Asset Error", fmt.Sprint(err), "Wrong error received")
 	})
 }
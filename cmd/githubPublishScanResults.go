@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// issueCategoryMarker identifies the finding category of an issue, it is added as invisible comment to the issue body
+const issueCategoryMarker = "<!-- scan-finding-category: %v -->"
+
+// reportTimePattern matches the time of the scan report within the issue body, it is ignored when checking whether an issue is up to date
+var reportTimePattern = regexp.MustCompile(`Snapshot taken: <i>[^<]*</i>`)
+
+type githubPublishScanResultsUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileExists(filename string) (bool, error)
+	Glob(pattern string) (matches []string, err error)
+}
+
+type githubScanIssueService interface {
+	ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+}
+
+type githubCodeScanningService interface {
+	UploadSarif(ctx context.Context, owner, repo string, analysis *piperGithub.SarifAnalysis) (*piperGithub.SarifID, *github.Response, error)
+}
+
+func githubPublishScanResults(config githubPublishScanResultsOptions, telemetryData *telemetry.CustomData) {
+	ctx, client, err := piperGithub.NewClient(config.Token, config.APIURL, "")
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
+	}
+	err = runGithubPublishScanResults(ctx, &config, telemetryData, &piperutils.Files{}, client.Issues, piperGithub.NewCodeScanningService(client))
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to publish scan results")
+	}
+}
+
+func runGithubPublishScanResults(ctx context.Context, config *githubPublishScanResultsOptions, _ *telemetry.CustomData, utils githubPublishScanResultsUtils, issueService githubScanIssueService, codeScanningService githubCodeScanningService) error {
+	if config.Mode == "codeScanning" {
+		return publishToCodeScanning(ctx, config, utils, codeScanningService)
+	}
+	return publishAsIssues(ctx, config, utils, issueService)
+}
+
+// readScanReports reads the scan reports of all steps and returns them together with the report file names
+func readScanReports(utils githubPublishScanResultsUtils) ([]string, []reporting.ScanReport, error) {
+	reportFiles, _ := utils.Glob(filepath.Join(reporting.StepReportDirectory, "*.json"))
+	scanReports := []reporting.ScanReport{}
+	for _, reportFile := range reportFiles {
+		log.Entry().Debugf("reading file %v", reportFile)
+		reportContent, err := utils.FileRead(reportFile)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, nil, errors.Wrapf(err, "failed to read report %v", reportFile)
+		}
+		scanReport := reporting.ScanReport{}
+		if err = json.Unmarshal(reportContent, &scanReport); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse report %v", reportFile)
+		}
+		scanReports = append(scanReports, scanReport)
+	}
+	return reportFiles, scanReports, nil
+}
+
+func publishToCodeScanning(ctx context.Context, config *githubPublishScanResultsOptions, utils githubPublishScanResultsUtils, service githubCodeScanningService) error {
+	if len(config.CommitID) == 0 || len(config.Ref) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("parameters `commitId` and `ref` are required for mode `codeScanning`")
+	}
+
+	sarifLogs := []format.SARIF{}
+	sarifFiles, _ := utils.Glob(filepath.Join(reporting.StepReportDirectory, "*.sarif"))
+	for _, sarifFile := range sarifFiles {
+		log.Entry().Debugf("reading file %v", sarifFile)
+		content, err := utils.FileRead(sarifFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read report %v", sarifFile)
+		}
+		sarifLog, err := format.ReadSARIF(content)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse report %v", sarifFile)
+		}
+		sarifLogs = append(sarifLogs, sarifLog)
+	}
+
+	// scan reports are only converted in case the step did not provide a SARIF log
+	reportFiles, scanReports, err := readScanReports(utils)
+	if err != nil {
+		return err
+	}
+	for i, scanReport := range scanReports {
+		if exists, _ := utils.FileExists(strings.TrimSuffix(reportFiles[i], ".json") + ".sarif"); !exists {
+			sarifLogs = append(sarifLogs, scanReport.ToSARIF())
+		}
+	}
+
+	sarif := format.MergeSARIF(sarifLogs...)
+	addDefaultLocation(&sarif, config.DefaultLocation)
+	content, err := sarif.ToJSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF log")
+	}
+	encoded, err := piperGithub.EncodeSarif(content)
+	if err != nil {
+		return err
+	}
+
+	sarifID, resp, err := service.UploadSarif(ctx, config.Owner, config.Repository, &piperGithub.SarifAnalysis{
+		CommitSHA: config.CommitID,
+		Ref:       config.Ref,
+		Sarif:     encoded,
+	})
+	if err != nil {
+		if resp != nil {
+			log.Entry().Errorf("GitHub response code %v", resp.Status)
+		}
+		return errors.Wrap(err, "failed to upload scan results to GitHub code scanning")
+	}
+	log.Entry().Infof("Uploaded %v runs to GitHub code scanning: %v", len(sarif.Runs), sarifID.URL)
+	return nil
+}
+
+// addDefaultLocation adds the default location to all results without location since GitHub code scanning requires a location for every result
+func addDefaultLocation(sarif *format.SARIF, defaultLocation string) {
+	if len(defaultLocation) == 0 {
+		return
+	}
+	for i := range sarif.Runs {
+		for j := range sarif.Runs[i].Results {
+			if len(sarif.Runs[i].Results[j].Locations) == 0 {
+				sarif.Runs[i].Results[j].Locations = []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: defaultLocation}}}}
+			}
+		}
+	}
+}
+
+func publishAsIssues(ctx context.Context, config *githubPublishScanResultsOptions, utils githubPublishScanResultsUtils, service githubScanIssueService) error {
+	if len(config.IssueLabels) == 0 || len(config.IssueLabels[0]) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("parameter `issueLabels` must contain at least one label for mode `issues`")
+	}
+
+	_, scanReports, err := readScanReports(utils)
+	if err != nil {
+		return err
+	}
+
+	// reports of the same category, e.g. of multiple projects scanned by one step, are combined into one issue
+	categories := []string{}
+	reportsByCategory := map[string][]reporting.ScanReport{}
+	for _, scanReport := range scanReports {
+		category := scanReport.Category()
+		if _, ok := reportsByCategory[category]; !ok {
+			categories = append(categories, category)
+		}
+		reportsByCategory[category] = append(reportsByCategory[category], scanReport)
+	}
+	sort.Strings(categories)
+
+	issues, err := listScanIssues(ctx, config, service)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		if err := publishCategoryIssue(ctx, config, service, category, reportsByCategory[category], issues[category]); err != nil {
+			return err
+		}
+	}
+
+	if !config.CloseIssuesWithoutReport {
+		return nil
+	}
+	// issues of categories without a report, e.g. since the scan step was removed from the pipeline
+	orphanedCategories := []string{}
+	for category, issue := range issues {
+		if _, ok := reportsByCategory[category]; !ok && issue.GetState() == "open" {
+			orphanedCategories = append(orphanedCategories, category)
+		}
+	}
+	sort.Strings(orphanedCategories)
+	for _, category := range orphanedCategories {
+		log.Entry().Infof("Closing issue #%v since no scan report exists for category '%v' anymore", issues[category].GetNumber(), category)
+		if err := closeScanIssue(ctx, config, service, issues[category], "No scan report has been published for this category anymore.", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listScanIssues returns the issues maintained by this step per category
+func listScanIssues(ctx context.Context, config *githubPublishScanResultsOptions, service githubScanIssueService) (map[string]*github.Issue, error) {
+	issues := map[string]*github.Issue{}
+	options := &github.IssueListByRepoOptions{State: "all", Labels: []string{config.IssueLabels[0]}, ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := service.ListByRepo(ctx, config.Owner, config.Repository, options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list issues")
+		}
+		for _, issue := range page {
+			if category := issueCategory(issue.GetBody()); len(category) > 0 {
+				issues[category] = issue
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return issues, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+func issueCategory(body string) string {
+	prefix := strings.SplitN(issueCategoryMarker, "%v", 2)
+	start := strings.Index(body, prefix[0])
+	if start < 0 {
+		return ""
+	}
+	rest := body[start+len(prefix[0]):]
+	end := strings.Index(rest, prefix[1])
+	if end < 0 {
+		return ""
+	}
+	return rest[:end]
+}
+
+func publishCategoryIssue(ctx context.Context, config *githubPublishScanResultsOptions, service githubScanIssueService, category string, scanReports []reporting.ScanReport, issue *github.Issue) error {
+	hasFindings := false
+	body := fmt.Sprintf(issueCategoryMarker+"\n\n", category)
+	if len(config.PipelineLink) > 0 {
+		body += fmt.Sprintf("## Pipeline Source for Details\n\n[%v](%v)\n\n", config.PipelineLink, config.PipelineLink)
+	}
+	for _, scanReport := range scanReports {
+		if !scanReport.SuccessfulScan || len(scanReport.DetailTable.Rows) > 0 {
+			hasFindings = true
+		}
+		// ignore templating errors since template is in our hands
+		mdReport, _ := scanReport.ToMarkdown()
+		body += string(mdReport)
+	}
+
+	if !hasFindings {
+		if issue == nil || issue.GetState() == "closed" {
+			return nil
+		}
+		log.Entry().Infof("Closing issue #%v since category '%v' has no findings anymore", issue.GetNumber(), category)
+		return closeScanIssue(ctx, config, service, issue, "All findings have been resolved.", &body)
+	}
+
+	if issue == nil {
+		title := config.IssueTitlePrefix + scanReports[0].Title
+		newIssue, _, err := service.Create(ctx, config.Owner, config.Repository, &github.IssueRequest{
+			Title:     &title,
+			Body:      &body,
+			Labels:    &config.IssueLabels,
+			Assignees: &config.IssueAssignees,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create issue for category '%v'", category)
+		}
+		log.Entry().Infof("Created issue #%v for category '%v'", newIssue.GetNumber(), category)
+		return nil
+	}
+
+	if issue.GetState() == "open" && reportTimePattern.ReplaceAllString(issue.GetBody(), "") == reportTimePattern.ReplaceAllString(body, "") {
+		log.Entry().Infof("Issue #%v for category '%v' is up to date", issue.GetNumber(), category)
+		return nil
+	}
+	state := "open"
+	if _, _, err := service.Edit(ctx, config.Owner, config.Repository, issue.GetNumber(), &github.IssueRequest{State: &state, Body: &body}); err != nil {
+		return errors.Wrapf(err, "failed to update issue #%v", issue.GetNumber())
+	}
+	log.Entry().Infof("Updated issue #%v for category '%v'", issue.GetNumber(), category)
+	return nil
+}
+
+// closeScanIssue comments on the issue and closes it, the body is only updated if provided
+func closeScanIssue(ctx context.Context, config *githubPublishScanResultsOptions, service githubScanIssueService, issue *github.Issue, comment string, body *string) error {
+	if len(config.PipelineLink) > 0 {
+		comment += fmt.Sprintf(" See [%v](%v).", config.PipelineLink, config.PipelineLink)
+	}
+	if _, _, err := service.CreateComment(ctx, config.Owner, config.Repository, issue.GetNumber(), &github.IssueComment{Body: &comment}); err != nil {
+		return errors.Wrapf(err, "failed to comment on issue #%v", issue.GetNumber())
+	}
+	state := "closed"
+	if _, _, err := service.Edit(ctx, config.Owner, config.Repository, issue.GetNumber(), &github.IssueRequest{State: &state, Body: body}); err != nil {
+		return errors.Wrapf(err, "failed to close issue #%v", issue.GetNumber())
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type githubPublishScanResultsOptions struct {
+	APIURL                   string   `json:"apiUrl,omitempty"`
+	Owner                    string   `json:"owner,omitempty"`
+	Repository               string   `json:"repository,omitempty"`
+	Token                    string   `json:"token,omitempty"`
+	Mode                     string   `json:"mode,omitempty"`
+	CommitID                 string   `json:"commitId,omitempty"`
+	Ref                      string   `json:"ref,omitempty"`
+	DefaultLocation          string   `json:"defaultLocation,omitempty"`
+	IssueLabels              []string `json:"issueLabels,omitempty"`
+	IssueAssignees           []string `json:"issueAssignees,omitempty"`
+	CloseIssuesWithoutReport bool     `json:"closeIssuesWithoutReport,omitempty"`
+	IssueTitlePrefix         string   `json:"issueTitlePrefix,omitempty"`
+	PipelineLink             string   `json:"pipelineLink,omitempty"`
+}
+
+// GithubPublishScanResultsCommand Publish scan results to GitHub code scanning or as GitHub issues.
+func GithubPublishScanResultsCommand() *cobra.Command {
+	const STEP_NAME = "githubPublishScanResults"
+
+	metadata := githubPublishScanResultsMetadata()
+	var stepConfig githubPublishScanResultsOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+
+	var createGithubPublishScanResultsCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Publish scan results to GitHub code scanning or as GitHub issues.",
+		Long: `This step publishes the results of the scan steps which were written into the directory ` + "`" + `.pipeline/stepReports` + "`" + `.
+
+With ` + "`" + `mode: codeScanning` + "`" + ` the results are uploaded as one analysis to [GitHub code scanning](https://docs.github.com/en/code-security/code-scanning).
+SARIF logs written by the scan steps are used as they are, scan reports (` + "`" + `*.json` + "`" + `) without a corresponding SARIF log are converted.
+
+With ` + "`" + `mode: issues` + "`" + ` one GitHub issue is maintained per finding category, i.e. per scan report title of a step:
+
+* An issue is created for a category with findings in case no issue for the category exists yet.
+* An existing issue is updated with the current findings and reopened if required.
+* An issue is closed in case the category's scan report does not contain findings anymore.
+* An issue is closed in case no scan report exists for its category anymore, e.g. since the scan step was removed, see [` + "`" + `closeIssuesWithoutReport` + "`" + `](#closeissueswithoutreport).
+
+An issue is only updated if the findings changed, the time of the scan report is ignored.
+
+Issues are identified via the label defined in [` + "`" + `issueLabels` + "`" + `](#issuelabels) and a category marker within the issue body.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
+				telemetry.Send(&telemetryData)
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunk.Send(&telemetryData, logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunk.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			githubPublishScanResults(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addGithubPublishScanResultsFlags(createGithubPublishScanResultsCmd, &stepConfig)
+	return createGithubPublishScanResultsCmd
+}
+
+func addGithubPublishScanResultsFlags(cmd *cobra.Command, stepConfig *githubPublishScanResultsOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line.")
+	cmd.Flags().StringVar(&stepConfig.Mode, "mode", `issues`, "Defines how the scan results are published.")
+	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", os.Getenv("PIPER_commitId"), "Mode `codeScanning`: SHA of the commit which was scanned.")
+	cmd.Flags().StringVar(&stepConfig.Ref, "ref", os.Getenv("PIPER_ref"), "Mode `codeScanning`: Full git reference which was scanned, e.g. `refs/heads/main` or `refs/pull/42/merge`.")
+	cmd.Flags().StringVar(&stepConfig.DefaultLocation, "defaultLocation", `.pipeline/config.yml`, "Mode `codeScanning`: Repository file used as location for findings without a source location, e.g. vulnerable dependencies. GitHub code scanning rejects findings without location.")
+	cmd.Flags().StringSliceVar(&stepConfig.IssueLabels, "issueLabels", []string{`scan-finding`}, "Mode `issues`: Labels of the issues. The first label is used to identify the issues maintained by this step.")
+	cmd.Flags().StringSliceVar(&stepConfig.IssueAssignees, "issueAssignees", []string{}, "Mode `issues`: GitHub user names to assign new issues to.")
+	cmd.Flags().BoolVar(&stepConfig.CloseIssuesWithoutReport, "closeIssuesWithoutReport", true, "Mode `issues`: Closes open issues of categories without a scan report in the current run. Disable it in case scan steps publishing issues with the same label run in different pipelines.")
+	cmd.Flags().StringVar(&stepConfig.IssueTitlePrefix, "issueTitlePrefix", `[Scan] `, "Mode `issues`: Prefix for the issue titles which consist of the scan report title otherwise.")
+	cmd.Flags().StringVar(&stepConfig.PipelineLink, "pipelineLink", os.Getenv("PIPER_pipelineLink"), "Link to the pipeline (e.g. Jenkins job url) for reference in the issues.")
+
+	cmd.MarkFlagRequired("apiUrl")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("repository")
+	cmd.MarkFlagRequired("token")
+}
+
+// retrieve step metadata
+func githubPublishScanResultsMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "githubPublishScanResults",
+			Aliases:     []config.Alias{},
+			Description: "Publish scan results to GitHub code scanning or as GitHub issues.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+						Default:   os.Getenv("PIPER_owner"),
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+						Default:   os.Getenv("PIPER_repository"),
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github", "$(vaultBasePath)/$(vaultPipelineName)/github", "$(vaultBasePath)/GROUP-SECRETS/github"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
+						Default:   os.Getenv("PIPER_token"),
+					},
+					{
+						Name:           "mode",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `issues`,
+						PossibleValues: []interface{}{"issues", "codeScanning"},
+					},
+					{
+						Name: "commitId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/commitId",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_commitId"),
+					},
+					{
+						Name:        "ref",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_ref"),
+					},
+					{
+						Name:        "defaultLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/config.yml`,
+					},
+					{
+						Name:        "issueLabels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`scan-finding`},
+					},
+					{
+						Name:        "issueAssignees",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "closeIssuesWithoutReport",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "issueTitlePrefix",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `[Scan] `,
+					},
+					{
+						Name:        "pipelineLink",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_pipelineLink"),
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubPublishScanResultsCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := GithubPublishScanResultsCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "githubPublishScanResults", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ghScanIssueServiceMock struct {
+	issues        []*github.Issue
+	created       []*github.IssueRequest
+	edited        map[int]*github.IssueRequest
+	comments      map[int]string
+	listedOptions *github.IssueListByRepoOptions
+	listError     error
+}
+
+func (g *ghScanIssueServiceMock) ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	g.listedOptions = opts
+	// simulate paging with one issue per page
+	if opts.Page >= len(g.issues) {
+		return []*github.Issue{}, &github.Response{}, g.listError
+	}
+	response := &github.Response{}
+	if opts.Page < len(g.issues)-1 {
+		response.NextPage = opts.Page + 1
+	}
+	return []*github.Issue{g.issues[opts.Page]}, response, g.listError
+}
+
+func (g *ghScanIssueServiceMock) Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	g.created = append(g.created, issue)
+	number := 100 + len(g.created)
+	return &github.Issue{Number: &number}, &github.Response{}, nil
+}
+
+func (g *ghScanIssueServiceMock) Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	if g.edited == nil {
+		g.edited = map[int]*github.IssueRequest{}
+	}
+	g.edited[number] = issue
+	return &github.Issue{Number: &number}, &github.Response{}, nil
+}
+
+func (g *ghScanIssueServiceMock) CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	if g.comments == nil {
+		g.comments = map[int]string{}
+	}
+	g.comments[number] = comment.GetBody()
+	return comment, &github.Response{}, nil
+}
+
+type ghCodeScanningServiceMock struct {
+	owner       string
+	repo        string
+	analysis    *piperGithub.SarifAnalysis
+	uploadError error
+}
+
+func (g *ghCodeScanningServiceMock) UploadSarif(ctx context.Context, owner, repo string, analysis *piperGithub.SarifAnalysis) (*piperGithub.SarifID, *github.Response, error) {
+	g.owner = owner
+	g.repo = repo
+	g.analysis = analysis
+	if g.uploadError != nil {
+		return nil, &github.Response{Response: &http.Response{Status: "403"}}, g.uploadError
+	}
+	return &piperGithub.SarifID{ID: "1", URL: "https://api.github.com/repos/SAP/jenkins-library/code-scanning/sarifs/1"}, &github.Response{}, nil
+}
+
+func newScanIssue(number int, state, category string) *github.Issue {
+	body := fmt.Sprintf(issueCategoryMarker+"\n\nold findings", category)
+	return &github.Issue{Number: &number, State: &state, Body: &body}
+}
+
+func TestRunGithubPublishScanResults(t *testing.T) {
+	ctx := context.Background()
+	t.Parallel()
+
+	t.Run("issues - create, update and close", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{
+			Owner:                    "SAP",
+			Repository:               "jenkins-library",
+			Mode:                     "issues",
+			IssueLabels:              []string{"scan-finding", "security"},
+			IssueAssignees:           []string{"octocat"},
+			IssueTitlePrefix:         "[Scan] ",
+			PipelineLink:             "https://jenkins/job/1",
+			CloseIssuesWithoutReport: true,
+		}
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/stepReports/whitesourceExecuteScan_oss_1.json", []byte(`{"stepName":"whitesourceExecuteScan","title":"WhiteSource Security Vulnerability Report","successfulScan":true,"detailTable":{"headers":["CVE"],"rows":[{"columns":[{"content":"CVE-1"}]}]}}`))
+		utils.AddFile(".pipeline/stepReports/whitesourceExecuteScan_oss_2.json", []byte(`{"stepName":"whitesourceExecuteScan","title":"WhiteSource Security Vulnerability Report","successfulScan":true,"detailTable":{"headers":["CVE"],"rows":[{"columns":[{"content":"CVE-2"}]}]}}`))
+		utils.AddFile(".pipeline/stepReports/fortifyExecuteScan_sast.json", []byte(`{"title":"Fortify SAST Report","successfulScan":false}`))
+		utils.AddFile(".pipeline/stepReports/checkmarxExecuteScan_sast.json", []byte(`{"title":"Checkmarx SAST Report","successfulScan":true}`))
+		utils.AddFile(".pipeline/stepReports/detectExecuteScan_oss.json", []byte(`{"title":"Detect Report","successfulScan":true}`))
+		issueService := &ghScanIssueServiceMock{issues: []*github.Issue{
+			newScanIssue(1, "closed", "Fortify SAST Report"),
+			newScanIssue(2, "open", "Checkmarx SAST Report"),
+			newScanIssue(3, "closed", "Detect Report"),
+			newScanIssue(4, "open", "SonarQube Report"),
+			newScanIssue(5, "closed", "Protecode Report"),
+			{Body: github.String("issue without category")},
+		}}
+
+		err := runGithubPublishScanResults(ctx, &config, nil, utils, issueService, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, &github.IssueListByRepoOptions{State: "all", Labels: []string{"scan-finding"}, ListOptions: github.ListOptions{Page: 5, PerPage: 100}}, issueService.listedOptions)
+
+		// both WhiteSource reports are combined into one new issue
+		require.Len(t, issueService.created, 1)
+		created := issueService.created[0]
+		assert.Equal(t, "[Scan] WhiteSource Security Vulnerability Report", created.GetTitle())
+		assert.Equal(t, []string{"scan-finding", "security"}, created.GetLabels())
+		assert.Equal(t, []string{"octocat"}, created.GetAssignees())
+		assert.Contains(t, created.GetBody(), "<!-- scan-finding-category: whitesourceExecuteScan/WhiteSource Security Vulnerability Report -->")
+		assert.Contains(t, created.GetBody(), "[https://jenkins/job/1](https://jenkins/job/1)")
+		assert.Contains(t, created.GetBody(), "CVE-1")
+		assert.Contains(t, created.GetBody(), "CVE-2")
+
+		// failed Fortify scan reopens the existing issue
+		require.Contains(t, issueService.edited, 1)
+		assert.Equal(t, "open", issueService.edited[1].GetState())
+		assert.Contains(t, issueService.edited[1].GetBody(), "Fortify SAST Report")
+
+		// Checkmarx findings disappeared
+		require.Contains(t, issueService.edited, 2)
+		assert.Equal(t, "closed", issueService.edited[2].GetState())
+		assert.Equal(t, "All findings have been resolved. See [https://jenkins/job/1](https://jenkins/job/1).", issueService.comments[2])
+
+		// closed Detect issue stays untouched
+		assert.NotContains(t, issueService.edited, 3)
+
+		// SonarQube report disappeared, the closed Protecode issue stays untouched
+		require.Contains(t, issueService.edited, 4)
+		assert.Equal(t, "closed", issueService.edited[4].GetState())
+		assert.Nil(t, issueService.edited[4].Body)
+		assert.Equal(t, "No scan report has been published for this category anymore. See [https://jenkins/job/1](https://jenkins/job/1).", issueService.comments[4])
+		assert.NotContains(t, issueService.edited, 5)
+		assert.Len(t, issueService.comments, 2)
+	})
+
+	t.Run("issues - keep issues without report", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "issues", IssueLabels: []string{"scan-finding"}}
+		issueService := &ghScanIssueServiceMock{issues: []*github.Issue{newScanIssue(4, "open", "SonarQube Report")}}
+
+		require.NoError(t, runGithubPublishScanResults(ctx, &config, nil, &mock.FilesMock{}, issueService, nil))
+		assert.Empty(t, issueService.edited)
+		assert.Empty(t, issueService.comments)
+	})
+
+	t.Run("issues - up to date", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "issues", IssueLabels: []string{"scan-finding"}}
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/stepReports/fortifyExecuteScan_sast.json", []byte(`{"title":"Fortify SAST Report","reportTime":"2021-01-01T00:00:00Z","successfulScan":false}`))
+		issueService := &ghScanIssueServiceMock{}
+		require.NoError(t, runGithubPublishScanResults(ctx, &config, nil, utils, issueService, nil))
+		require.Len(t, issueService.created, 1)
+
+		// a new report with the same findings does not touch the issue
+		utils.AddFile(".pipeline/stepReports/fortifyExecuteScan_sast.json", []byte(`{"title":"Fortify SAST Report","reportTime":"2021-01-02T00:00:00Z","successfulScan":false}`))
+		number, state := 1, "open"
+		issueService = &ghScanIssueServiceMock{issues: []*github.Issue{{Number: &number, State: &state, Body: issueService.created[0].Body}}}
+		require.NoError(t, runGithubPublishScanResults(ctx, &config, nil, utils, issueService, nil))
+		assert.Empty(t, issueService.created)
+		assert.Empty(t, issueService.edited)
+	})
+
+	t.Run("issues - no label", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "issues"}
+		err := runGithubPublishScanResults(ctx, &config, nil, &mock.FilesMock{}, &ghScanIssueServiceMock{}, nil)
+		assert.EqualError(t, err, "parameter `issueLabels` must contain at least one label for mode `issues`")
+	})
+
+	t.Run("issues - list error", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "issues", IssueLabels: []string{"scan-finding"}}
+		err := runGithubPublishScanResults(ctx, &config, nil, &mock.FilesMock{}, &ghScanIssueServiceMock{listError: fmt.Errorf("authentication failed")}, nil)
+		assert.EqualError(t, err, "failed to list issues: authentication failed")
+	})
+
+	t.Run("issues - invalid report", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "issues", IssueLabels: []string{"scan-finding"}}
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/stepReports/step1.json", []byte(`{"title":"Title Scan 1"`))
+		err := runGithubPublishScanResults(ctx, &config, nil, utils, &ghScanIssueServiceMock{}, nil)
+		assert.Contains(t, fmt.Sprint(err), "failed to parse report")
+	})
+
+	t.Run("code scanning", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{
+			Owner:           "SAP",
+			Repository:      "jenkins-library",
+			Mode:            "codeScanning",
+			CommitID:        "4b2e2b8",
+			Ref:             "refs/heads/master",
+			DefaultLocation: ".pipeline/config.yml",
+		}
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/stepReports/whitesourceExecuteScan_oss_1.json", []byte(`{"title":"WhiteSource Security Vulnerability Report","detailTable":{"rows":[{"columns":[{"content":"converted"}]}]}}`))
+		utils.AddFile(".pipeline/stepReports/whitesourceExecuteScan_oss_1.sarif", []byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"WhiteSource","rules":[{"id":"CVE-1"}]}},"results":[{"ruleId":"CVE-1","message":{"text":"CVE-1 in lib"}}]}]}`))
+		utils.AddFile(".pipeline/stepReports/fortifyExecuteScan_sast.json", []byte(`{"title":"Fortify SAST Report","detailTable":{"rows":[{"columns":[{"content":"SQL Injection"}]}]}}`))
+		codeScanningService := &ghCodeScanningServiceMock{}
+
+		err := runGithubPublishScanResults(ctx, &config, nil, utils, nil, codeScanningService)
+
+		require.NoError(t, err)
+		assert.Equal(t, "SAP", codeScanningService.owner)
+		assert.Equal(t, "jenkins-library", codeScanningService.repo)
+		assert.Equal(t, "4b2e2b8", codeScanningService.analysis.CommitSHA)
+		assert.Equal(t, "refs/heads/master", codeScanningService.analysis.Ref)
+
+		compressed, err := base64.StdEncoding.DecodeString(codeScanningService.analysis.Sarif)
+		require.NoError(t, err)
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		sarif, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Contains(t, string(sarif), `"text": "CVE-1 in lib"`)
+		assert.Contains(t, string(sarif), `"text": "SQL Injection"`)
+		assert.NotContains(t, string(sarif), "converted")
+		assert.Contains(t, string(sarif), `"uri": ".pipeline/config.yml"`)
+	})
+
+	t.Run("code scanning - missing ref", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "codeScanning", CommitID: "4b2e2b8"}
+		err := runGithubPublishScanResults(ctx, &config, nil, &mock.FilesMock{}, nil, &ghCodeScanningServiceMock{})
+		assert.EqualError(t, err, "parameters `commitId` and `ref` are required for mode `codeScanning`")
+	})
+
+	t.Run("code scanning - upload error", func(t *testing.T) {
+		t.Parallel()
+		config := githubPublishScanResultsOptions{Mode: "codeScanning", CommitID: "4b2e2b8", Ref: "refs/heads/master"}
+		err := runGithubPublishScanResults(ctx, &config, nil, &mock.FilesMock{}, nil, &ghCodeScanningServiceMock{uploadError: fmt.Errorf("forbidden")})
+		assert.EqualError(t, err, "failed to upload scan results to GitHub code scanning: forbidden")
+	})
+}
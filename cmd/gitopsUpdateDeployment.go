@@ -2,36 +2,49 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/docker"
 	gitUtil "github.com/SAP/jenkins-library/pkg/git"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/kubernetes"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v32/github"
 	"github.com/pkg/errors"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"text/template"
 )
 
 const toolKubectl = "kubectl"
 const toolHelm = "helm"
+const toolKustomize = "kustomize"
 
 type iGitopsUpdateDeploymentGitUtils interface {
 	CommitSingleFile(filePath, commitMessage, author string) (plumbing.Hash, error)
-	PushChangesToRepository(username, password string) error
-	PlainClone(username, password, serverURL, directory string) error
+	PushChangesToRepository() error
+	PushChangesToBranch(branchName string) error
+	PlainClone(serverURL, directory string) error
 	ChangeBranch(branchName string) error
 }
 
 type gitopsUpdateDeploymentFileUtils interface {
 	TempDir(dir, pattern string) (name string, err error)
+	FileRead(path string) ([]byte, error)
 	RemoveAll(path string) error
 	FileWrite(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
 }
 
 type gitopsUpdateDeploymentExecRunner interface {
@@ -40,7 +53,25 @@ type gitopsUpdateDeploymentExecRunner interface {
 	Stderr(err io.Writer)
 }
 
+type gitopsUpdateDeploymentGithubUtils interface {
+	CreatePullRequest(owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, error)
+	RequestReviewers(owner, repo string, number int, reviewers []string) error
+	AddLabels(owner, repo string, number int, labels []string) error
+	EnableAutoMerge(pullRequestNodeID, mergeMethod string) error
+}
+
+// gitopsPullRequestData contains the fields available in the pull request title and body templates
+type gitopsPullRequestData struct {
+	Image      string
+	Version    string
+	FilePath   string
+	Commit     string
+	CommitURL  string
+	BranchName string
+}
+
 type gitopsUpdateDeploymentGitUtils struct {
+	auth       transport.AuthMethod
 	worktree   *git.Worktree
 	repository *git.Repository
 }
@@ -49,13 +80,21 @@ func (g *gitopsUpdateDeploymentGitUtils) CommitSingleFile(filePath, commitMessag
 	return gitUtil.CommitSingleFile(filePath, commitMessage, author, g.worktree)
 }
 
-func (g *gitopsUpdateDeploymentGitUtils) PushChangesToRepository(username, password string) error {
-	return gitUtil.PushChangesToRepository(username, password, g.repository)
+func (g *gitopsUpdateDeploymentGitUtils) PushChangesToRepository() error {
+	return gitUtil.PushChangesToRepository(g.auth, g.repository)
 }
 
-func (g *gitopsUpdateDeploymentGitUtils) PlainClone(username, password, serverURL, directory string) error {
+func (g *gitopsUpdateDeploymentGitUtils) PushChangesToBranch(branchName string) error {
+	return gitUtil.PushBranchToRepository(g.auth, branchName, g.repository)
+}
+
+func (g *gitopsUpdateDeploymentGitUtils) PlainClone(serverURL, directory string) error {
+	if gitUtil.IsSSH(g.auth) && strings.HasPrefix(serverURL, "http") {
+		serverURL = convertHTTPToSSHURL(serverURL)
+		log.Entry().Infof("using ssh url '%v'", serverURL)
+	}
 	var err error
-	g.repository, err = gitUtil.PlainClone(username, password, serverURL, directory)
+	g.repository, err = gitUtil.PlainClone(g.auth, serverURL, directory)
 	if err != nil {
 		return errors.Wrap(err, "plain clone failed")
 	}
@@ -67,7 +106,31 @@ func (g *gitopsUpdateDeploymentGitUtils) ChangeBranch(branchName string) error {
 	return gitUtil.ChangeBranch(branchName, g.worktree)
 }
 
-func gitopsUpdateDeployment(config gitopsUpdateDeploymentOptions, _ *telemetry.CustomData) {
+type gitopsUpdateDeploymentGithubUtilsBundle struct {
+	ctx    context.Context
+	client *github.Client
+}
+
+func (g *gitopsUpdateDeploymentGithubUtilsBundle) CreatePullRequest(owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, error) {
+	pullRequest, _, err := g.client.PullRequests.Create(g.ctx, owner, repo, pull)
+	return pullRequest, err
+}
+
+func (g *gitopsUpdateDeploymentGithubUtilsBundle) RequestReviewers(owner, repo string, number int, reviewers []string) error {
+	_, _, err := g.client.PullRequests.RequestReviewers(g.ctx, owner, repo, number, github.ReviewersRequest{Reviewers: reviewers})
+	return err
+}
+
+func (g *gitopsUpdateDeploymentGithubUtilsBundle) AddLabels(owner, repo string, number int, labels []string) error {
+	_, _, err := g.client.Issues.AddLabelsToIssue(g.ctx, owner, repo, number, labels)
+	return err
+}
+
+func (g *gitopsUpdateDeploymentGithubUtilsBundle) EnableAutoMerge(pullRequestNodeID, mergeMethod string) error {
+	return piperGithub.EnablePullRequestAutoMerge(g.ctx, g.client, pullRequestNodeID, mergeMethod)
+}
+
+func gitopsUpdateDeployment(config gitopsUpdateDeploymentOptions, _ *telemetry.CustomData, commonPipelineEnvironment *gitopsUpdateDeploymentCommonPipelineEnvironment) {
 	// for command execution use Command
 	var c gitopsUpdateDeploymentExecRunner = &command.Command{}
 	// reroute command output to logging framework
@@ -78,14 +141,46 @@ func gitopsUpdateDeployment(config gitopsUpdateDeploymentOptions, _ *telemetry.C
 	// and use a  &piperhttp.Client{} in a custom system
 	// Example: step checkmarxExecuteScan.go
 
+	auth, err := gitUtil.AuthMethod(gitUtil.AuthOptions{
+		Method:                  config.GitAuthMethod,
+		Username:                config.Username,
+		Password:                config.Password,
+		SSHKeyPath:              config.GitSshKeyPath,
+		SSHKeyPassphrase:        config.GitSshKeyPassphrase,
+		SSHKnownHostsPath:       config.GitSshKnownHostsPath,
+		Token:                   config.GitToken,
+		GitHubAppID:             config.GithubAppID,
+		GitHubAppInstallationID: config.GithubAppInstallationID,
+		GitHubAppPrivateKeyPath: config.GithubAppPrivateKeyPath,
+		GitHubAPIURL:            config.APIURL,
+	})
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		log.Entry().WithError(err).Fatal("Failed to determine git authentication")
+	}
+
+	var githubUtils gitopsUpdateDeploymentGithubUtils
+	if config.CreatePullRequest {
+		token, err := githubAPIToken(&config, auth)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			log.Entry().WithError(err).Fatal("Failed to determine the token for the GitHub API")
+		}
+		ctx, client, err := piperGithub.NewClient(token, config.APIURL, "")
+		if err != nil {
+			log.Entry().WithError(err).Fatal("Failed to get GitHub client")
+		}
+		githubUtils = &gitopsUpdateDeploymentGithubUtilsBundle{ctx: ctx, client: client}
+	}
+
 	// error situations should stop execution through log.Entry().Fatal() call which leads to an os.Exit(1) in the end
-	err := runGitopsUpdateDeployment(&config, c, &gitopsUpdateDeploymentGitUtils{}, piperutils.Files{})
+	err = runGitopsUpdateDeployment(&config, c, &gitopsUpdateDeploymentGitUtils{auth: auth}, piperutils.Files{}, githubUtils, commonPipelineEnvironment)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gitopsUpdateDeploymentExecRunner, gitUtils iGitopsUpdateDeploymentGitUtils, fileUtils gitopsUpdateDeploymentFileUtils) error {
+func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gitopsUpdateDeploymentExecRunner, gitUtils iGitopsUpdateDeploymentGitUtils, fileUtils gitopsUpdateDeploymentFileUtils, githubUtils gitopsUpdateDeploymentGithubUtils, commonPipelineEnvironment *gitopsUpdateDeploymentCommonPipelineEnvironment) error {
 	err := checkRequiredFieldsForDeployTool(config)
 	if err != nil {
 		return err
@@ -110,7 +205,7 @@ func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gi
 
 	filePath := filepath.Join(temporaryFolder, config.FilePath)
 
-	var outputBytes []byte
+	var outputBytes, renderedBytes []byte
 	if config.Tool == toolKubectl {
 		outputBytes, err = executeKubectl(config, command, outputBytes, filePath)
 		if err != nil {
@@ -121,6 +216,11 @@ func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gi
 		if err != nil {
 			return errors.Wrap(err, "failed to apply helm command")
 		}
+	} else if config.Tool == toolKustomize {
+		outputBytes, err = updateKustomization(config, fileUtils, filePath)
+		if err != nil {
+			return errors.Wrap(err, "failed to update kustomization")
+		}
 	} else {
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return errors.New("tool " + config.Tool + " is not supported")
@@ -131,6 +231,25 @@ func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gi
 		return errors.Wrap(err, "failed to write file")
 	}
 
+	renderedBytes = outputBytes
+	if config.Tool == toolKustomize {
+		// render the overlay in order to validate the updated kustomization before it gets committed
+		renderedBytes, err = runKustomizeCommand(command, config, filepath.Dir(filePath))
+		if err != nil {
+			return errors.Wrap(err, "failed to validate kustomization")
+		}
+	}
+
+	if len(config.RenderedManifestFile) > 0 {
+		if err := writeRenderedManifest(config.RenderedManifestFile, renderedBytes, fileUtils); err != nil {
+			return err
+		}
+	}
+
+	if config.CreatePullRequest {
+		return commitAndOpenPullRequest(config, gitUtils, githubUtils, commonPipelineEnvironment)
+	}
+
 	commit, err := commitAndPushChanges(config, gitUtils)
 	if err != nil {
 		return errors.Wrap(err, "failed to commit and push changes")
@@ -141,6 +260,22 @@ func runGitopsUpdateDeployment(config *gitopsUpdateDeploymentOptions, command gi
 	return nil
 }
 
+type renderedManifestFileUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// writeRenderedManifest keeps a copy of a rendered deployment descriptor, e.g. since the cloned repository is removed afterwards
+func writeRenderedManifest(path string, content []byte, fileUtils renderedManifestFileUtils) error {
+	if err := fileUtils.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrapf(err, "failed to create directory for rendered manifest '%v'", path)
+	}
+	if err := fileUtils.FileWrite(path, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write rendered manifest '%v'", path)
+	}
+	return nil
+}
+
 func checkRequiredFieldsForDeployTool(config *gitopsUpdateDeploymentOptions) error {
 	if config.Tool == toolHelm {
 		err := checkRequiredFieldsForHelm(config)
@@ -154,6 +289,8 @@ func checkRequiredFieldsForDeployTool(config *gitopsUpdateDeploymentOptions) err
 			return errors.Wrap(err, "missing required fields for kubectl")
 		}
 		logNotRequiredButFilledFieldForKubectl(config)
+	} else if config.Tool == toolKustomize {
+		logNotRequiredButFilledFieldForKustomize(config)
 	}
 
 	return nil
@@ -204,8 +341,23 @@ func logNotRequiredButFilledFieldForKubectl(config *gitopsUpdateDeploymentOption
 	}
 }
 
+func logNotRequiredButFilledFieldForKustomize(config *gitopsUpdateDeploymentOptions) {
+	if config.ContainerName != "" {
+		log.Entry().Info("containerName is not used for kustomize and can be removed")
+	}
+	if config.ChartPath != "" {
+		log.Entry().Info("chartPath is not used for kustomize and can be removed")
+	}
+	if len(config.HelmValues) > 0 {
+		log.Entry().Info("helmValues is not used for kustomize and can be removed")
+	}
+	if len(config.DeploymentName) > 0 {
+		log.Entry().Info("deploymentName is not used for kustomize and can be removed")
+	}
+}
+
 func cloneRepositoryAndChangeBranch(config *gitopsUpdateDeploymentOptions, gitUtils iGitopsUpdateDeploymentGitUtils, temporaryFolder string) error {
-	err := gitUtils.PlainClone(config.Username, config.Password, config.ServerURL, temporaryFolder)
+	err := gitUtils.PlainClone(config.ServerURL, temporaryFolder)
 	if err != nil {
 		return errors.Wrap(err, "failed to plain clone repository")
 	}
@@ -292,6 +444,37 @@ func runHelmCommand(runner gitopsUpdateDeploymentExecRunner, config *gitopsUpdat
 	return helmOutput.Bytes(), nil
 }
 
+// updateKustomization sets the new image name and tag in the images section of the kustomization
+func updateKustomization(config *gitopsUpdateDeploymentOptions, fileUtils gitopsUpdateDeploymentFileUtils, filePath string) ([]byte, error) {
+	registryImage, imageTag, err := buildRegistryPlusImageAndTagSeparately(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract registry URL, image name, and image tag")
+	}
+	kustomization, err := fileUtils.FileRead(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read kustomization '%v'", config.FilePath)
+	}
+	imageName := strings.Split(config.ContainerImageNameTag, ":")[0]
+	return kubernetes.SetKustomizeImage(kustomization, kubernetes.KustomizeImage{Name: imageName, NewName: registryImage, NewTag: imageTag})
+}
+
+func runKustomizeCommand(runner gitopsUpdateDeploymentExecRunner, config *gitopsUpdateDeploymentOptions, kustomizationDir string) ([]byte, error) {
+	var kustomizeOutput = bytes.Buffer{}
+	runner.Stdout(&kustomizeOutput)
+
+	err := runner.RunExecutable(toolKubectl, "kustomize", kustomizationDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute kubectl kustomize")
+	}
+
+	registryImage, imageTag, _ := buildRegistryPlusImageAndTagSeparately(config)
+	if !strings.Contains(kustomizeOutput.String(), registryImage+":"+imageTag) {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Errorf("rendered kustomization does not reference image '%v:%v', please check the images section of '%v'", registryImage, imageTag, config.FilePath)
+	}
+	return kustomizeOutput.Bytes(), nil
+}
+
 // buildRegistryPlusImageAndTagSeparately combines the registry together with the image name. Handles the tag separately.
 // Tag is defined by everything on the right hand side of the colon sign. This looks weird for sha container versions but works for helm.
 func buildRegistryPlusImageAndTagSeparately(config *gitopsUpdateDeploymentOptions) (string, string, error) {
@@ -342,7 +525,7 @@ func commitAndPushChanges(config *gitopsUpdateDeploymentOptions, gitUtils iGitop
 		return [20]byte{}, errors.Wrap(err, "committing changes failed")
 	}
 
-	err = gitUtils.PushChangesToRepository(config.Username, config.Password)
+	err = gitUtils.PushChangesToRepository()
 	if err != nil {
 		return [20]byte{}, errors.Wrap(err, "pushing changes failed")
 	}
@@ -350,6 +533,140 @@ func commitAndPushChanges(config *gitopsUpdateDeploymentOptions, gitUtils iGitop
 	return commit, nil
 }
 
+// commitAndOpenPullRequest pushes the changes to a new branch and opens a pull request targeting the configured branch
+func commitAndOpenPullRequest(config *gitopsUpdateDeploymentOptions, gitUtils iGitopsUpdateDeploymentGitUtils, githubUtils gitopsUpdateDeploymentGithubUtils, commonPipelineEnvironment *gitopsUpdateDeploymentCommonPipelineEnvironment) error {
+	owner, repository, err := githubRepositoryFromURL(config.ServerURL)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	commitMessage := config.CommitMessage
+	if commitMessage == "" {
+		commitMessage = defaultCommitMessage(config)
+	}
+	commit, err := gitUtils.CommitSingleFile(config.FilePath, commitMessage, config.Username)
+	if err != nil {
+		return errors.Wrap(err, "committing changes failed")
+	}
+
+	image, version, _ := buildRegistryPlusImageAndTagSeparately(config)
+	data := gitopsPullRequestData{
+		Image:      image,
+		Version:    version,
+		FilePath:   config.FilePath,
+		Commit:     commit.String(),
+		CommitURL:  fmt.Sprintf("%v/commit/%v", strings.TrimSuffix(strings.TrimSuffix(config.ServerURL, "/"), ".git"), commit.String()),
+		BranchName: pullRequestBranchName(config, commit.String()),
+	}
+
+	// the new branch starts at the commit containing the changes
+	if err := gitUtils.ChangeBranch(data.BranchName); err != nil {
+		return errors.Wrap(err, "failed to create pull request branch")
+	}
+	if err := gitUtils.PushChangesToBranch(data.BranchName); err != nil {
+		return errors.Wrap(err, "pushing changes failed")
+	}
+	log.Entry().Infof("Changes committed with %v to branch '%v'", data.Commit, data.BranchName)
+
+	title, err := renderPullRequestTemplate("pullRequestTitle", config.PullRequestTitle, data)
+	if err != nil {
+		return err
+	}
+	body, err := renderPullRequestTemplate("pullRequestBody", config.PullRequestBody, data)
+	if err != nil {
+		return err
+	}
+	pullRequest, err := githubUtils.CreatePullRequest(owner, repository, &github.NewPullRequest{
+		Title: &title,
+		Head:  &data.BranchName,
+		Base:  &config.BranchName,
+		Body:  &body,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create pull request")
+	}
+	commonPipelineEnvironment.custom.gitopsPullRequestURL = pullRequest.GetHTMLURL()
+	log.Entry().Infof("Pull request created: %v", pullRequest.GetHTMLURL())
+
+	if len(config.PullRequestReviewers) > 0 {
+		if err := githubUtils.RequestReviewers(owner, repository, pullRequest.GetNumber(), config.PullRequestReviewers); err != nil {
+			return errors.Wrap(err, "failed to request reviewers for pull request")
+		}
+	}
+	if len(config.PullRequestLabels) > 0 {
+		if err := githubUtils.AddLabels(owner, repository, pullRequest.GetNumber(), config.PullRequestLabels); err != nil {
+			return errors.Wrap(err, "failed to add labels to pull request")
+		}
+	}
+	if config.PullRequestAutoMerge {
+		// the pull request is already open, it can still be merged manually if auto-merge is not possible
+		if err := githubUtils.EnableAutoMerge(pullRequest.GetNodeID(), config.PullRequestMergeMethod); err != nil {
+			log.Entry().WithError(err).Warnf("Failed to enable auto-merge for pull request #%v", pullRequest.GetNumber())
+		} else {
+			log.Entry().Infof("Auto-merge enabled for pull request #%v", pullRequest.GetNumber())
+		}
+	}
+	return nil
+}
+
+// githubAPIToken provides the token for the GitHub API, tokens used for the git authentication are valid for the API as well
+func githubAPIToken(config *gitopsUpdateDeploymentOptions, auth transport.AuthMethod) (string, error) {
+	if len(config.GithubToken) > 0 {
+		return config.GithubToken, nil
+	}
+	switch gitAuth := auth.(type) {
+	case *http.TokenAuth:
+		return gitAuth.Token, nil
+	case *http.BasicAuth:
+		if len(gitAuth.Password) > 0 {
+			return gitAuth.Password, nil
+		}
+	}
+	return "", errors.New("no token for the GitHub API available, please provide parameter githubToken in case of ssh authentication")
+}
+
+// githubRepositoryFromURL extracts owner and repository name from a repository url like https://github.com/owner/repository.git
+func githubRepositoryFromURL(repositoryURL string) (string, string, error) {
+	u, err := url.Parse(repositoryURL)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid repository url '%v'", repositoryURL)
+	}
+	segments := strings.Split(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"), "/")
+	if len(segments) < 2 || segments[len(segments)-2] == "" {
+		return "", "", fmt.Errorf("failed to determine owner and repository from url '%v'", repositoryURL)
+	}
+	return segments[len(segments)-2], segments[len(segments)-1], nil
+}
+
+var invalidBranchCharacters = regexp.MustCompile(`[^A-Za-z0-9._/-]+`)
+
+func pullRequestBranchName(config *gitopsUpdateDeploymentOptions, commit string) string {
+	imageNameTag := strings.SplitN(config.ContainerImageNameTag, ":", 2)
+	name := imageNameTag[0]
+	if len(imageNameTag) > 1 {
+		name += "-" + imageNameTag[1]
+	}
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("%v/%v-%v", config.PullRequestBranchPrefix, invalidBranchCharacters.ReplaceAllString(name, "-"), commit)
+}
+
+func renderPullRequestTemplate(name, text string, data gitopsPullRequestData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", errors.Wrapf(err, "invalid template for %v", name)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", errors.Wrapf(err, "failed to render %v", name)
+	}
+	return rendered.String(), nil
+}
+
 func defaultCommitMessage(config *gitopsUpdateDeploymentOptions) string {
 	image, tag, _ := buildRegistryPlusImageAndTagSeparately(config)
 	commitMessage := fmt.Sprintf("Updated %v to version %v", image, tag)
@@ -5,29 +5,79 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
 type gitopsUpdateDeploymentOptions struct {
-	BranchName            string   `json:"branchName,omitempty"`
-	CommitMessage         string   `json:"commitMessage,omitempty"`
-	ServerURL             string   `json:"serverUrl,omitempty"`
-	Username              string   `json:"username,omitempty"`
-	Password              string   `json:"password,omitempty"`
-	FilePath              string   `json:"filePath,omitempty"`
-	ContainerName         string   `json:"containerName,omitempty"`
-	ContainerRegistryURL  string   `json:"containerRegistryUrl,omitempty"`
-	ContainerImageNameTag string   `json:"containerImageNameTag,omitempty"`
-	ChartPath             string   `json:"chartPath,omitempty"`
-	HelmValues            []string `json:"helmValues,omitempty"`
-	DeploymentName        string   `json:"deploymentName,omitempty"`
-	Tool                  string   `json:"tool,omitempty"`
+	BranchName              string   `json:"branchName,omitempty"`
+	CommitMessage           string   `json:"commitMessage,omitempty"`
+	ServerURL               string   `json:"serverUrl,omitempty"`
+	Username                string   `json:"username,omitempty"`
+	Password                string   `json:"password,omitempty"`
+	GitAuthMethod           string   `json:"gitAuthMethod,omitempty"`
+	GitSshKeyPassphrase     string   `json:"gitSshKeyPassphrase,omitempty"`
+	GitSshKeyPath           string   `json:"gitSshKeyPath,omitempty"`
+	GitSshKnownHostsPath    string   `json:"gitSshKnownHostsPath,omitempty"`
+	GitToken                string   `json:"gitToken,omitempty"`
+	GithubAppID             string   `json:"githubAppId,omitempty"`
+	GithubAppInstallationID string   `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKeyPath string   `json:"githubAppPrivateKeyPath,omitempty"`
+	FilePath                string   `json:"filePath,omitempty"`
+	RenderedManifestFile    string   `json:"renderedManifestFile,omitempty"`
+	CreatePullRequest       bool     `json:"createPullRequest,omitempty"`
+	GithubToken             string   `json:"githubToken,omitempty"`
+	APIURL                  string   `json:"apiUrl,omitempty"`
+	PullRequestBranchPrefix string   `json:"pullRequestBranchPrefix,omitempty"`
+	PullRequestTitle        string   `json:"pullRequestTitle,omitempty"`
+	PullRequestBody         string   `json:"pullRequestBody,omitempty"`
+	PullRequestReviewers    []string `json:"pullRequestReviewers,omitempty"`
+	PullRequestLabels       []string `json:"pullRequestLabels,omitempty"`
+	PullRequestAutoMerge    bool     `json:"pullRequestAutoMerge,omitempty"`
+	PullRequestMergeMethod  string   `json:"pullRequestMergeMethod,omitempty"`
+	ContainerName           string   `json:"containerName,omitempty"`
+	ContainerRegistryURL    string   `json:"containerRegistryUrl,omitempty"`
+	ContainerImageNameTag   string   `json:"containerImageNameTag,omitempty"`
+	ChartPath               string   `json:"chartPath,omitempty"`
+	HelmValues              []string `json:"helmValues,omitempty"`
+	DeploymentName          string   `json:"deploymentName,omitempty"`
+	Tool                    string   `json:"tool,omitempty"`
+}
+
+type gitopsUpdateDeploymentCommonPipelineEnvironment struct {
+	custom struct {
+		gitopsPullRequestURL string
+	}
+}
+
+func (p *gitopsUpdateDeploymentCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "gitopsPullRequestUrl", value: p.custom.gitopsPullRequestURL},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Piper environment")
+	}
 }
 
 // GitopsUpdateDeploymentCommand Updates Kubernetes Deployment Manifest in an Infrastructure Git Repository
@@ -37,6 +87,7 @@ func GitopsUpdateDeploymentCommand() *cobra.Command {
 	metadata := gitopsUpdateDeploymentMetadata()
 	var stepConfig gitopsUpdateDeploymentOptions
 	var startTime time.Time
+	var commonPipelineEnvironment gitopsUpdateDeploymentCommonPipelineEnvironment
 	var logCollector *log.CollectorHook
 
 	var createGitopsUpdateDeploymentCmd = &cobra.Command{
@@ -48,7 +99,13 @@ It can for example be used for GitOps scenarios where the update of the manifest
 
 As of today, it supports the update of deployment yaml files via kubectl patch and update a whole helm template.
 For kubectl the container inside the yaml must be described within the following hierarchy: ` + "`" + `{"spec":{"template":{"spec":{"containers":[{...}]}}}}` + "`" + `
-For helm the whole template is generated into a file and uploaded into the repository.`,
+For helm the whole template is generated into a file and uploaded into the repository.
+With [` + "`" + `createPullRequest` + "`" + `](#createpullrequest) the changes are not pushed to [` + "`" + `branchName` + "`" + `](#branchname) directly, which is typically rejected for protected branches.
+Instead they are pushed to a new branch and a GitHub pull request targeting [` + "`" + `branchName` + "`" + `](#branchname) is opened. The url of the pull request is written to the commonPipelineEnvironment as ` + "`" + `custom/gitopsPullRequestUrl` + "`" + `.
+The GitHub API is accessed with the token used for the git authentication, i.e. the [` + "`" + `password` + "`" + `](#password), the [` + "`" + `gitToken` + "`" + `](#gittoken) or the installation access token of the GitHub App.
+In case of ` + "`" + `gitAuthMethod: ssh` + "`" + ` the [` + "`" + `password` + "`" + `](#password) needs to contain a GitHub access token.
+
+For kustomize the ` + "`" + `images` + "`" + ` entries of the kustomization file matching the image name are updated with the new image name and tag, afterwards the kustomization is rendered via ` + "`" + `kubectl kustomize` + "`" + ` in order to validate the result before the kustomization file is uploaded into the repository.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -65,6 +122,11 @@ For helm the whole template is generated into a file and uploaded into the repos
 			}
 			log.RegisterSecret(stepConfig.Username)
 			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.GitSshKeyPassphrase)
+			log.RegisterSecret(stepConfig.GitSshKeyPath)
+			log.RegisterSecret(stepConfig.GitToken)
+			log.RegisterSecret(stepConfig.GithubAppPrivateKeyPath)
+			log.RegisterSecret(stepConfig.GithubToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -83,8 +145,11 @@ For helm the whole template is generated into a file and uploaded into the repos
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -100,7 +165,7 @@ For helm the whole template is generated into a file and uploaded into the repos
 					GeneralConfig.HookConfig.SplunkConfig.Index,
 					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 			}
-			gitopsUpdateDeployment(stepConfig, &telemetryData)
+			gitopsUpdateDeployment(stepConfig, &telemetryData, &commonPipelineEnvironment)
 			telemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -111,12 +176,33 @@ For helm the whole template is generated into a file and uploaded into the repos
 }
 
 func addGitopsUpdateDeploymentFlags(cmd *cobra.Command, stepConfig *gitopsUpdateDeploymentOptions) {
-	cmd.Flags().StringVar(&stepConfig.BranchName, "branchName", `master`, "The name of the branch where the changes should get pushed into.")
+	cmd.Flags().StringVar(&stepConfig.BranchName, "branchName", `master`, "The name of the branch where the changes should get pushed into. In case of `createPullRequest:true` the base branch of the pull request.")
 	cmd.Flags().StringVar(&stepConfig.CommitMessage, "commitMessage", os.Getenv("PIPER_commitMessage"), "The commit message of the commit that will be done to do the changes.")
 	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", `https://github.com`, "GitHub server url to the repository.")
-	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for git authentication")
-	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for git authentication.")
-	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Relative path in the git repository to the deployment descriptor file that shall be updated")
+	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for `gitAuthMethod: basic`, it is used as author of the commit as well.")
+	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for `gitAuthMethod: basic`.")
+	cmd.Flags().StringVar(&stepConfig.GitAuthMethod, "gitAuthMethod", `basic`, "Method for the authentication against the git repository: `basic` uses [`username`](#username) and [`password`](#password), `ssh` uses the private key [`gitSshKeyPath`](#gitsshkeypath) or the keys of the ssh agent, `token` sends [`gitToken`](#gittoken) as bearer token and `githubApp` uses an installation access token of the GitHub App [`githubAppId`](#githubappid) created via [`apiUrl`](#apiurl).")
+	cmd.Flags().StringVar(&stepConfig.GitSshKeyPassphrase, "gitSshKeyPassphrase", os.Getenv("PIPER_gitSshKeyPassphrase"), "Passphrase of the private key [`gitSshKeyPath`](#gitsshkeypath).")
+	cmd.Flags().StringVar(&stepConfig.GitSshKeyPath, "gitSshKeyPath", os.Getenv("PIPER_gitSshKeyPath"), "Path to the private ssh key for `gitAuthMethod: ssh`. In case no key is provided, the keys of the ssh agent are used, e.g. provided via [`gitSshKeyCredentialsId`](#gitsshkeycredentialsid) on Jenkins.")
+	cmd.Flags().StringVar(&stepConfig.GitSshKnownHostsPath, "gitSshKnownHostsPath", os.Getenv("PIPER_gitSshKnownHostsPath"), "Path to the known_hosts file used to verify the host key of the git server for `gitAuthMethod: ssh`. By default the files referenced by the environment variable `SSH_KNOWN_HOSTS` or `~/.ssh/known_hosts` and `/etc/ssh/ssh_known_hosts` are used. Unknown hosts are always rejected.")
+	cmd.Flags().StringVar(&stepConfig.GitToken, "gitToken", os.Getenv("PIPER_gitToken"), "Token sent as bearer token for `gitAuthMethod: token`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppID, "githubAppId", os.Getenv("PIPER_githubAppId"), "ID of the GitHub App for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", os.Getenv("PIPER_githubAppInstallationId"), "ID of the installation of the GitHub App in the organization or account owning the git repository for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKeyPath, "githubAppPrivateKeyPath", os.Getenv("PIPER_githubAppPrivateKeyPath"), "Path to the private key of the GitHub App for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Relative path in the git repository to the deployment descriptor file that shall be updated. For `tool: kustomize` this is the path to the `kustomization.yaml` of the overlay.")
+	cmd.Flags().StringVar(&stepConfig.RenderedManifestFile, "renderedManifestFile", os.Getenv("PIPER_renderedManifestFile"), "Path in the workspace where a copy of the updated deployment descriptor is written to, e.g. for evaluating it with step [`policyEvaluate`](policyEvaluate.md). For `tool: kustomize` the rendered overlay is written.")
+	cmd.Flags().BoolVar(&stepConfig.CreatePullRequest, "createPullRequest", false, "Pushes the changes to a new branch and opens a GitHub pull request for them instead of pushing them to `branchName` directly.")
+	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "Token for the GitHub API in case of `createPullRequest: true`. Defaults to the token respectively the password used for the git authentication, it is required for `gitAuthMethod: ssh`.")
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url, used in case of `createPullRequest:true`.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestBranchPrefix, "pullRequestBranchPrefix", `gitops`, "Prefix of the branch the changes are pushed to in case of `createPullRequest:true`. The branch name is completed by image name, version and commit.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestTitle, "pullRequestTitle", `Update {{.Image}} to version {{.Version}}`, "Title of the pull request as [Go template](https://golang.org/pkg/text/template/). Available fields: `.Image`, `.Version`, `.FilePath`, `.Commit`, `.CommitURL`, `.BranchName`.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestBody, "pullRequestBody", `Updates image {{.Image}} to version {{.Version}} in {{.FilePath}}.
+
+Commit: {{.CommitURL}}`, "Body of the pull request as [Go template](https://golang.org/pkg/text/template/), the same fields as for `pullRequestTitle` are available.")
+	cmd.Flags().StringSliceVar(&stepConfig.PullRequestReviewers, "pullRequestReviewers", []string{}, "GitHub users who are requested to review the pull request.")
+	cmd.Flags().StringSliceVar(&stepConfig.PullRequestLabels, "pullRequestLabels", []string{}, "Labels which are added to the pull request.")
+	cmd.Flags().BoolVar(&stepConfig.PullRequestAutoMerge, "pullRequestAutoMerge", false, "Enables auto-merge for the pull request, it gets merged as soon as all requirements of the base branch are met. Auto-merge needs to be allowed for the repository, if it cannot be enabled a warning is logged and the pull request stays open.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestMergeMethod, "pullRequestMergeMethod", `merge`, "Merge method used for auto-merge.")
 	cmd.Flags().StringVar(&stepConfig.ContainerName, "containerName", os.Getenv("PIPER_containerName"), "The name of the container to update")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry where the image is located")
 	cmd.Flags().StringVar(&stepConfig.ContainerImageNameTag, "containerImageNameTag", os.Getenv("PIPER_containerImageNameTag"), "Container image name with version tag to annotate in the deployment configuration.")
@@ -127,8 +213,6 @@ func addGitopsUpdateDeploymentFlags(cmd *cobra.Command, stepConfig *gitopsUpdate
 
 	cmd.MarkFlagRequired("branchName")
 	cmd.MarkFlagRequired("serverUrl")
-	cmd.MarkFlagRequired("username")
-	cmd.MarkFlagRequired("password")
 	cmd.MarkFlagRequired("filePath")
 	cmd.MarkFlagRequired("containerRegistryUrl")
 	cmd.MarkFlagRequired("containerImageNameTag")
@@ -147,6 +231,10 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "gitHttpsCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repository.", Type: "jenkins"},
+					{Name: "gitSshKeyCredentialsId", Description: "Jenkins 'SSH Username with private key' credentials ID for `gitAuthMethod: ssh`, the key is provided via the ssh agent.", Type: "jenkins"},
+					{Name: "gitTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the token for `gitAuthMethod: token`.", Type: "jenkins"},
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the token for the GitHub API in case of `createPullRequest: true`.", Type: "jenkins"},
+					{Name: "githubAppPrivateKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key of the GitHub App for `gitAuthMethod: githubApp`.", Type: "jenkins"},
 				},
 				Resources: []config.StepResources{
 					{Name: "deployDescriptor", Type: "stash"},
@@ -190,7 +278,7 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_username"),
 					},
@@ -205,10 +293,93 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_password"),
 					},
+					{
+						Name:           "gitAuthMethod",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `basic`,
+						PossibleValues: []interface{}{"basic", "ssh", "token", "githubApp"},
+					},
+					{
+						Name:        "gitSshKeyPassphrase",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKeyPassphrase"),
+					},
+					{
+						Name:        "gitSshKeyPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKeyPath"),
+					},
+					{
+						Name:        "gitSshKnownHostsPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKnownHostsPath"),
+					},
+					{
+						Name: "gitToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "gitTokenCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_gitToken"),
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_githubAppId"),
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_githubAppInstallationId"),
+					},
+					{
+						Name: "githubAppPrivateKeyPath",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_githubAppPrivateKeyPath"),
+					},
 					{
 						Name:        "filePath",
 						ResourceRef: []config.ResourceReference{},
@@ -218,6 +389,119 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_filePath"),
 					},
+					{
+						Name:        "renderedManifestFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_renderedManifestFile"),
+					},
+					{
+						Name:        "createPullRequest",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "githubToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/github", "$(vaultBasePath)/$(vaultPipelineName)/github", "$(vaultBasePath)/GROUP-SECRETS/github"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_githubToken"),
+					},
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name:        "pullRequestBranchPrefix",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `gitops`,
+					},
+					{
+						Name:        "pullRequestTitle",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Update {{.Image}} to version {{.Version}}`,
+					},
+					{
+						Name:        "pullRequestBody",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default: `Updates image {{.Image}} to version {{.Version}} in {{.FilePath}}.
+
+Commit: {{.CommitURL}}`,
+					},
+					{
+						Name:        "pullRequestReviewers",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "pullRequestLabels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "pullRequestAutoMerge",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:           "pullRequestMergeMethod",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `merge`,
+						PossibleValues: []interface{}{"merge", "squash", "rebase"},
+					},
 					{
 						Name:        "containerName",
 						ResourceRef: []config.ResourceReference{},
@@ -252,7 +536,7 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
 						Mandatory: true,
-						Aliases:   []config.Alias{{Name: "image"}, {Name: "containerImage"}},
+						Aliases:   []config.Alias{{Name: "image", Deprecated: true}, {Name: "containerImage"}},
 						Default:   os.Getenv("PIPER_containerImageNameTag"),
 					},
 					{
@@ -283,20 +567,33 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_deploymentName"),
 					},
 					{
-						Name:        "tool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     `kubectl`,
+						Name:           "tool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        `kubectl`,
+						PossibleValues: []interface{}{"kubectl", "helm", "kustomize"},
 					},
 				},
 			},
 			Containers: []config.Container{
 				{Image: "dtzar/helm-kubectl:3.3.4", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "tool", Value: "helm"}}}}},
+				{Image: "dtzar/helm-kubectl:3.3.4", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "tool", Value: "kustomize"}}}}},
 				{Image: "dtzar/helm-kubectl:2.17.0", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "tool", Value: "kubectl"}}}}},
 			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"Name": "custom/gitopsPullRequestUrl"},
+						},
+					},
+				},
+			},
 		},
 	}
 	return theMetaData
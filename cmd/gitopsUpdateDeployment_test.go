@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/google/go-github/v32/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -97,7 +104,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(validConfiguration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, validConfiguration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -110,6 +117,21 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		assert.True(t, strings.Contains(runnerMock.params[4], filepath.Join("dir1/dir2/depl.yaml")))
 	})
 
+	t.Run("successful run with rendered manifest", func(t *testing.T) {
+		t.Parallel()
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		var configuration = *validConfiguration
+		configuration.RenderedManifestFile = filepath.Join(dir, "manifests", "deployment.yaml")
+
+		err = runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+		assert.NoError(t, err)
+		manifest, err := ioutil.ReadFile(configuration.RenderedManifestFile)
+		require.NoError(t, err)
+		assert.Equal(t, expectedYaml, string(manifest))
+	})
+
 	t.Run("default commit message", func(t *testing.T) {
 		t.Parallel()
 		var configuration = *validConfiguration
@@ -118,7 +140,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, validConfiguration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -139,7 +161,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, configuration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -159,7 +181,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, configuration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -179,7 +201,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, configuration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -199,7 +221,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "missing required fields for kubectl: the following parameters are necessary for kubectl: [containerName]")
 	})
 
@@ -207,7 +229,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		runner := &gitOpsExecRunnerMock{failOnRunExecutable: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, runner, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, runner, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "error on kubectl execution: failed to apply kubectl command: failed to apply kubectl command: error happened")
 	})
 
@@ -216,7 +238,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		var configuration = *validConfiguration
 		configuration.ContainerRegistryURL = "//myregistry.com/registry/containers"
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "error on kubectl execution: failed to apply kubectl command: registry URL could not be extracted: invalid registry url")
 	})
 
@@ -224,7 +246,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnClone: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "repository could not get prepared: failed to plain clone repository: error on clone")
 	})
 
@@ -232,7 +254,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnChangeBranch: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "repository could not get prepared: failed to change branch: error on change branch")
 	})
 
@@ -240,7 +262,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnCommit: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to commit and push changes: committing changes failed: error on commit")
 	})
 
@@ -248,7 +270,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnPush: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to commit and push changes: pushing changes failed: error on push")
 	})
 
@@ -256,7 +278,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		fileUtils := &filesMock{failOnCreation: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils)
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to create temporary directory: error appeared")
 	})
 
@@ -264,7 +286,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		fileUtils := &filesMock{failOnWrite: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils)
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to write file: error appeared")
 	})
 
@@ -272,7 +294,7 @@ func TestRunGitopsUpdateDeploymentWithKubectl(t *testing.T) {
 		t.Parallel()
 		fileUtils := &filesMock{failOnDeletion: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils)
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		_ = piperutils.Files{}.RemoveAll(fileUtils.path)
 	})
@@ -297,7 +319,7 @@ func TestRunGitopsUpdateDeploymentWithInvalid(t *testing.T) {
 			HelmValues:            []string{"./helm/additionalValues.yaml"},
 		}
 
-		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "tool invalid is not supported")
 	})
 }
@@ -324,7 +346,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(validConfiguration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, validConfiguration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -347,7 +369,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, configuration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -370,7 +392,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, configuration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -392,7 +414,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		gitUtilsMock := &gitUtilsMock{}
 		runnerMock := &gitOpsExecRunnerMock{}
 
-		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		assert.Equal(t, configuration.BranchName, gitUtilsMock.changedBranch)
 		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
@@ -409,7 +431,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		var configuration = *validConfiguration
 		configuration.ContainerRegistryURL = "://myregistry.com"
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, `failed to apply helm command: failed to extract registry URL, image name, and image tag: registry URL could not be extracted: invalid registry url: parse "://myregistry.com": missing protocol scheme`)
 	})
 
@@ -418,7 +440,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		var configuration = *validConfiguration
 		configuration.ChartPath = ""
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "missing required fields for helm: the following parameters are necessary for helm: [chartPath]")
 	})
 
@@ -427,7 +449,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		var configuration = *validConfiguration
 		configuration.DeploymentName = ""
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "missing required fields for helm: the following parameters are necessary for helm: [deploymentName]")
 	})
 
@@ -437,7 +459,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		configuration.DeploymentName = ""
 		configuration.ChartPath = ""
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "missing required fields for helm: the following parameters are necessary for helm: [chartPath deploymentName]")
 	})
 
@@ -446,7 +468,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		var configuration = *validConfiguration
 		configuration.ContainerImageNameTag = "registry/containers/myFancyContainer:"
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to apply helm command: failed to extract registry URL, image name, and image tag: tag could not be extracted")
 	})
 
@@ -455,7 +477,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		var configuration = *validConfiguration
 		configuration.ContainerImageNameTag = ":1.0.1"
 
-		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to apply helm command: failed to extract registry URL, image name, and image tag: image name could not be extracted")
 	})
 
@@ -463,7 +485,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		runner := &gitOpsExecRunnerMock{failOnRunExecutable: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, runner, &gitUtilsMock{}, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, runner, &gitUtilsMock{}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to apply helm command: failed to execute helm command: error happened")
 	})
 
@@ -471,7 +493,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnClone: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "repository could not get prepared: failed to plain clone repository: error on clone")
 	})
 
@@ -479,7 +501,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnChangeBranch: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "repository could not get prepared: failed to change branch: error on change branch")
 	})
 
@@ -487,7 +509,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnCommit: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to commit and push changes: committing changes failed: error on commit")
 	})
 
@@ -495,7 +517,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		gitUtils := &gitUtilsMock{failOnPush: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{})
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to commit and push changes: pushing changes failed: error on push")
 	})
 
@@ -503,7 +525,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		fileUtils := &filesMock{failOnCreation: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils)
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to create temporary directory: error appeared")
 	})
 
@@ -511,7 +533,7 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		fileUtils := &filesMock{failOnWrite: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils)
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "failed to write file: error appeared")
 	})
 
@@ -519,12 +541,301 @@ func TestRunGitopsUpdateDeploymentWithHelm(t *testing.T) {
 		t.Parallel()
 		fileUtils := &filesMock{failOnDeletion: true}
 
-		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils)
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, fileUtils, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 		_ = piperutils.Files{}.RemoveAll(fileUtils.path)
 	})
 }
 
+func TestRunGitopsUpdateDeploymentWithKustomize(t *testing.T) {
+	var validConfiguration = &gitopsUpdateDeploymentOptions{
+		BranchName:            "main",
+		CommitMessage:         "This is the commit message",
+		ServerURL:             "https://github.com",
+		Username:              "admin3",
+		Password:              "validAccessToken",
+		FilePath:              "dir1/dir2/depl.yaml",
+		ContainerRegistryURL:  "https://myregistry.com/registry/containers",
+		ContainerImageNameTag: "myFancyContainer:1337",
+		Tool:                  "kustomize",
+	}
+	existingKustomization := "resources:\n- ../../base\nimages:\n- name: myFancyContainer\n  newName: myregistry.com/myFancyContainer\n  newTag: \"1336\"\n"
+
+	t.Parallel()
+	t.Run("successful run", func(t *testing.T) {
+		t.Parallel()
+		gitUtilsMock := &gitUtilsMock{existingFile: existingKustomization}
+		runnerMock := &gitOpsExecRunnerMock{}
+
+		err := runGitopsUpdateDeployment(validConfiguration, runnerMock, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+		assert.NoError(t, err)
+		assert.Equal(t, validConfiguration.BranchName, gitUtilsMock.changedBranch)
+		assert.Equal(t, "resources:\n  - ../../base\nimages:\n  - name: myFancyContainer\n    newName: myregistry.com/myFancyContainer\n    newTag: \"1337\"\n", gitUtilsMock.savedFile)
+		assert.Equal(t, "This is the commit message", gitUtilsMock.commitMessage)
+		assert.Equal(t, "kubectl", runnerMock.executable)
+		assert.Equal(t, "kustomize", runnerMock.params[0])
+		assert.Equal(t, filepath.Join(gitUtilsMock.temporaryDirectory, "dir1/dir2"), runnerMock.params[1])
+	})
+
+	t.Run("successful run with rendered manifest", func(t *testing.T) {
+		t.Parallel()
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		var configuration = *validConfiguration
+		configuration.RenderedManifestFile = filepath.Join(dir, "manifests", "deployment.yaml")
+
+		err = runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{existingFile: existingKustomization}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+		assert.NoError(t, err)
+		manifest, err := ioutil.ReadFile(configuration.RenderedManifestFile)
+		require.NoError(t, err)
+		assert.Equal(t, expectedYaml, string(manifest))
+	})
+
+	t.Run("rendered kustomization does not reference image", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.ContainerImageNameTag = "myFancyContainer:1338"
+		gitUtilsMock := &gitUtilsMock{existingFile: existingKustomization}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+		assert.EqualError(t, err, "failed to validate kustomization: rendered kustomization does not reference image 'myregistry.com/myFancyContainer:1338', please check the images section of 'dir1/dir2/depl.yaml'")
+		assert.Empty(t, gitUtilsMock.commitMessage)
+	})
+
+	t.Run("invalid kustomization", func(t *testing.T) {
+		t.Parallel()
+		gitUtilsMock := &gitUtilsMock{existingFile: "images: myFancyContainer"}
+
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+		assert.EqualError(t, err, "failed to update kustomization: images of kustomization is not a list")
+	})
+
+	t.Run("error on kustomize execution", func(t *testing.T) {
+		t.Parallel()
+		runner := &gitOpsExecRunnerMock{failOnRunExecutable: true}
+
+		err := runGitopsUpdateDeployment(validConfiguration, runner, &gitUtilsMock{existingFile: existingKustomization}, &filesMock{}, nil, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+		assert.EqualError(t, err, "failed to validate kustomization: failed to execute kubectl kustomize: error happened")
+	})
+}
+
+func TestRunGitopsUpdateDeploymentWithPullRequest(t *testing.T) {
+	var validConfiguration = &gitopsUpdateDeploymentOptions{
+		BranchName:              "main",
+		CommitMessage:           "This is the commit message",
+		ServerURL:               "https://github.com/org/gitops.git",
+		Username:                "admin3",
+		Password:                "validAccessToken",
+		FilePath:                "dir1/dir2/depl.yaml",
+		ContainerName:           "myContainer",
+		ContainerRegistryURL:    "https://myregistry.com/registry/containers",
+		ContainerImageNameTag:   "myFancyContainer:1337",
+		Tool:                    "kubectl",
+		CreatePullRequest:       true,
+		PullRequestBranchPrefix: "gitops",
+		PullRequestTitle:        "Update {{.Image}} to version {{.Version}}",
+		PullRequestBody:         "Updates image {{.Image}} to version {{.Version}} in {{.FilePath}}.\n\nCommit: {{.CommitURL}}",
+		PullRequestMergeMethod:  "squash",
+	}
+
+	t.Parallel()
+	t.Run("successful run", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.PullRequestReviewers = []string{"reviewer"}
+		configuration.PullRequestLabels = []string{"gitops", "automerge"}
+		configuration.PullRequestAutoMerge = true
+		gitUtilsMock := &gitUtilsMock{}
+		githubUtilsMock := &gitopsGithubUtilsMock{}
+		cpe := gitopsUpdateDeploymentCommonPipelineEnvironment{}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, gitUtilsMock, &filesMock{}, githubUtilsMock, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedYaml, gitUtilsMock.savedFile)
+		assert.Equal(t, "This is the commit message", gitUtilsMock.commitMessage)
+		assert.Equal(t, "gitops/myFancyContainer-1337-7b00000", gitUtilsMock.changedBranch)
+		assert.Equal(t, "gitops/myFancyContainer-1337-7b00000", gitUtilsMock.pushedBranch)
+		assert.Equal(t, "org/gitops", githubUtilsMock.repository)
+		assert.Equal(t, "Update myregistry.com/myFancyContainer to version 1337", githubUtilsMock.pullRequest.GetTitle())
+		assert.Equal(t, "Updates image myregistry.com/myFancyContainer to version 1337 in dir1/dir2/depl.yaml.\n\nCommit: https://github.com/org/gitops/commit/7b00000000000000000000000000000000000000", githubUtilsMock.pullRequest.GetBody())
+		assert.Equal(t, "gitops/myFancyContainer-1337-7b00000", githubUtilsMock.pullRequest.GetHead())
+		assert.Equal(t, "main", githubUtilsMock.pullRequest.GetBase())
+		assert.Equal(t, []string{"reviewer"}, githubUtilsMock.reviewers)
+		assert.Equal(t, []string{"gitops", "automerge"}, githubUtilsMock.labels)
+		assert.Equal(t, "PR_node", githubUtilsMock.autoMergeNodeID)
+		assert.Equal(t, "squash", githubUtilsMock.mergeMethod)
+		assert.Equal(t, "https://github.com/org/gitops/pull/42", cpe.custom.gitopsPullRequestURL)
+	})
+
+	t.Run("without reviewers, labels and auto-merge", func(t *testing.T) {
+		t.Parallel()
+		githubUtilsMock := &gitopsGithubUtilsMock{}
+		cpe := gitopsUpdateDeploymentCommonPipelineEnvironment{}
+
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, githubUtilsMock, &cpe)
+
+		assert.NoError(t, err)
+		assert.Nil(t, githubUtilsMock.reviewers)
+		assert.Nil(t, githubUtilsMock.labels)
+		assert.Empty(t, githubUtilsMock.autoMergeNodeID)
+		assert.Equal(t, "https://github.com/org/gitops/pull/42", cpe.custom.gitopsPullRequestURL)
+	})
+
+	t.Run("error on pull request creation", func(t *testing.T) {
+		t.Parallel()
+		githubUtilsMock := &gitopsGithubUtilsMock{failOnCreate: true}
+		cpe := gitopsUpdateDeploymentCommonPipelineEnvironment{}
+
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, githubUtilsMock, &cpe)
+
+		assert.EqualError(t, err, "failed to create pull request: error on create")
+		assert.Empty(t, cpe.custom.gitopsPullRequestURL)
+	})
+
+	t.Run("error on auto-merge is only logged", func(t *testing.T) {
+		// not parallel, the log output is captured
+		logBuffer := new(bytes.Buffer)
+		logOutput := log.Entry().Logger.Out
+		log.Entry().Logger.Out = logBuffer
+		defer func() { log.Entry().Logger.Out = logOutput }()
+
+		var configuration = *validConfiguration
+		configuration.PullRequestAutoMerge = true
+		githubUtilsMock := &gitopsGithubUtilsMock{failOnAutoMerge: true}
+		cpe := gitopsUpdateDeploymentCommonPipelineEnvironment{}
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, githubUtilsMock, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://github.com/org/gitops/pull/42", cpe.custom.gitopsPullRequestURL)
+		assert.Contains(t, logBuffer.String(), "Failed to enable auto-merge for pull request #42")
+		assert.Contains(t, logBuffer.String(), "auto-merge is not allowed")
+	})
+
+	t.Run("error on push", func(t *testing.T) {
+		t.Parallel()
+		gitUtils := &gitUtilsMock{failOnPush: true}
+
+		err := runGitopsUpdateDeployment(validConfiguration, &gitOpsExecRunnerMock{}, gitUtils, &filesMock{}, &gitopsGithubUtilsMock{}, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "pushing changes failed: error on push")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.PullRequestTitle = "Update {{.Image"
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, &gitopsGithubUtilsMock{}, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+
+		assert.Contains(t, err.Error(), "invalid template for pullRequestTitle")
+	})
+
+	t.Run("invalid repository url", func(t *testing.T) {
+		t.Parallel()
+		var configuration = *validConfiguration
+		configuration.ServerURL = "https://github.com"
+
+		err := runGitopsUpdateDeployment(&configuration, &gitOpsExecRunnerMock{}, &gitUtilsMock{}, &filesMock{}, &gitopsGithubUtilsMock{}, &gitopsUpdateDeploymentCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to determine owner and repository from url 'https://github.com'")
+	})
+}
+
+func TestGithubRepositoryFromURL(t *testing.T) {
+	t.Parallel()
+	owner, repository, err := githubRepositoryFromURL("https://github.example.com/org/gitops.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "org", owner)
+	assert.Equal(t, "gitops", repository)
+
+	owner, repository, err = githubRepositoryFromURL("https://github.com/org/gitops/")
+	assert.NoError(t, err)
+	assert.Equal(t, "org", owner)
+	assert.Equal(t, "gitops", repository)
+}
+
+func TestGithubAPIToken(t *testing.T) {
+	t.Parallel()
+	t.Run("token of git authentication", func(t *testing.T) {
+		t.Parallel()
+		config := &gitopsUpdateDeploymentOptions{}
+		token, err := githubAPIToken(config, &http.TokenAuth{Token: "theToken"})
+		assert.NoError(t, err)
+		assert.Equal(t, "theToken", token)
+		token, err = githubAPIToken(config, &http.BasicAuth{Username: "x-access-token", Password: "installationToken"})
+		assert.NoError(t, err)
+		assert.Equal(t, "installationToken", token)
+	})
+
+	t.Run("explicit token", func(t *testing.T) {
+		t.Parallel()
+		config := &gitopsUpdateDeploymentOptions{GithubToken: "apiToken"}
+		token, err := githubAPIToken(config, &ssh.PublicKeys{User: "git"})
+		assert.NoError(t, err)
+		assert.Equal(t, "apiToken", token)
+		token, err = githubAPIToken(config, &http.TokenAuth{Token: "theToken"})
+		assert.NoError(t, err)
+		assert.Equal(t, "apiToken", token)
+	})
+
+	t.Run("error - no token", func(t *testing.T) {
+		t.Parallel()
+		config := &gitopsUpdateDeploymentOptions{Password: "unused"}
+		_, err := githubAPIToken(config, &ssh.PublicKeys{User: "git"})
+		assert.EqualError(t, err, "no token for the GitHub API available, please provide parameter githubToken in case of ssh authentication")
+		_, err = githubAPIToken(config, nil)
+		assert.Error(t, err)
+		_, err = githubAPIToken(config, &http.BasicAuth{Username: "user"})
+		assert.Error(t, err)
+	})
+}
+
+type gitopsGithubUtilsMock struct {
+	repository      string
+	pullRequest     *github.NewPullRequest
+	reviewers       []string
+	labels          []string
+	autoMergeNodeID string
+	mergeMethod     string
+	failOnCreate    bool
+	failOnAutoMerge bool
+}
+
+func (g *gitopsGithubUtilsMock) CreatePullRequest(owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, error) {
+	if g.failOnCreate {
+		return nil, errors.New("error on create")
+	}
+	g.repository = owner + "/" + repo
+	g.pullRequest = pull
+	return &github.PullRequest{
+		Number:  github.Int(42),
+		NodeID:  github.String("PR_node"),
+		HTMLURL: github.String("https://github.com/org/gitops/pull/42"),
+	}, nil
+}
+
+func (g *gitopsGithubUtilsMock) RequestReviewers(owner, repo string, number int, reviewers []string) error {
+	g.reviewers = reviewers
+	return nil
+}
+
+func (g *gitopsGithubUtilsMock) AddLabels(owner, repo string, number int, labels []string) error {
+	g.labels = labels
+	return nil
+}
+
+func (g *gitopsGithubUtilsMock) EnableAutoMerge(pullRequestNodeID, mergeMethod string) error {
+	if g.failOnAutoMerge {
+		return errors.New("auto-merge is not allowed")
+	}
+	g.autoMergeNodeID = pullRequestNodeID
+	g.mergeMethod = mergeMethod
+	return nil
+}
+
 type gitOpsExecRunnerMock struct {
 	out                 io.Writer
 	params              []string
@@ -564,6 +875,14 @@ func (f filesMock) FileWrite(path string, content []byte, perm os.FileMode) erro
 	return piperutils.Files{}.FileWrite(path, content, perm)
 }
 
+func (f filesMock) FileRead(path string) ([]byte, error) {
+	return piperutils.Files{}.FileRead(path)
+}
+
+func (f filesMock) MkdirAll(path string, perm os.FileMode) error {
+	return piperutils.Files{}.MkdirAll(path, perm)
+}
+
 func (f filesMock) TempDir(dir string, pattern string) (name string, err error) {
 	if f.failOnCreation {
 		return "", errors.New("error appeared")
@@ -584,6 +903,8 @@ type gitUtilsMock struct {
 	changedBranch      string
 	commitMessage      string
 	temporaryDirectory string
+	existingFile       string
+	pushedBranch       string
 	failOnClone        bool
 	failOnChangeBranch bool
 	failOnCommit       bool
@@ -618,14 +939,22 @@ func (v *gitUtilsMock) CommitSingleFile(_ string, commitMessage string, _ string
 	return [20]byte{123}, nil
 }
 
-func (v gitUtilsMock) PushChangesToRepository(string, string) error {
+func (v *gitUtilsMock) PushChangesToBranch(branchName string) error {
 	if v.failOnPush {
 		return errors.New("error on push")
 	}
+	v.pushedBranch = branchName
 	return nil
 }
 
-func (v *gitUtilsMock) PlainClone(_, _, _, directory string) error {
+func (v gitUtilsMock) PushChangesToRepository() error {
+	if v.failOnPush {
+		return errors.New("error on push")
+	}
+	return nil
+}
+
+func (v *gitUtilsMock) PlainClone(_, directory string) error {
 	if v.failOnClone {
 		return errors.New("error on clone")
 	}
@@ -635,7 +964,11 @@ func (v *gitUtilsMock) PlainClone(_, _, _, directory string) error {
 	if err != nil {
 		return err
 	}
-	err = piperutils.Files{}.FileWrite(filePath, []byte(existingYaml), 0755)
+	content := existingYaml
+	if len(v.existingFile) > 0 {
+		content = v.existingFile
+	}
+	err = piperutils.Files{}.FileWrite(filePath, []byte(content), 0755)
 	if err != nil {
 		return err
 	}
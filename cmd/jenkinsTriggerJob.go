@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/jenkins"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type jenkinsTriggerJobUtils interface {
+	TriggerJob(ctx context.Context, jobName string, parameters map[string]string) (jenkins.Build, error)
+	FetchBuildArtifact(ctx context.Context, build jenkins.Build, fileName string) (jenkins.Artifact, error)
+
+	MkdirAll(path string, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+type jenkinsTriggerJobUtilsBundle struct {
+	*piperutils.Files
+	jenkins jenkins.Jenkins
+}
+
+func (j *jenkinsTriggerJobUtilsBundle) TriggerJob(ctx context.Context, jobName string, parameters map[string]string) (jenkins.Build, error) {
+	job, err := jenkins.GetJob(ctx, j.jenkins, jobName)
+	if err != nil {
+		return nil, err
+	}
+	build, err := jenkins.TriggerJob(ctx, j.jenkins, job, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return &jenkins.BuildImpl{Build: build}, nil
+}
+
+func (j *jenkinsTriggerJobUtilsBundle) FetchBuildArtifact(ctx context.Context, build jenkins.Build, fileName string) (jenkins.Artifact, error) {
+	return jenkins.FetchBuildArtifact(ctx, build, fileName)
+}
+
+func jenkinsTriggerJob(config jenkinsTriggerJobOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *jenkinsTriggerJobCommonPipelineEnvironment) {
+	// the timeout covers starting as well as running the triggered build
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.Timeout)*time.Minute)
+	defer cancel()
+
+	jenkinsURL := config.JenkinsURL
+	if len(jenkinsURL) == 0 {
+		// trigger the job on the Jenkins instance executing the pipeline
+		jenkinsURL = os.Getenv("JENKINS_URL")
+	}
+	if len(jenkinsURL) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		log.Entry().Fatal("parameter jenkinsUrl is required when not running on Jenkins")
+	}
+
+	instance, err := jenkins.Instance(ctx, &http.Client{}, jenkinsURL, config.JenkinsUsername, config.JenkinsToken)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorInfrastructure)
+		log.Entry().WithError(err).Fatalf("failed to connect to Jenkins '%v'", jenkinsURL)
+	}
+
+	utils := &jenkinsTriggerJobUtilsBundle{Files: &piperutils.Files{}, jenkins: instance}
+	err = runJenkinsTriggerJob(ctx, &config, telemetryData, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runJenkinsTriggerJob(ctx context.Context, config *jenkinsTriggerJobOptions, _ *telemetry.CustomData, utils jenkinsTriggerJobUtils, commonPipelineEnvironment *jenkinsTriggerJobCommonPipelineEnvironment) error {
+	parameters := map[string]string{}
+	for name, value := range config.JobParameters {
+		parameters[name] = fmt.Sprint(value)
+	}
+
+	log.Entry().Infof("Triggering job '%v'", config.JobName)
+	build, err := utils.TriggerJob(ctx, config.JobName, parameters)
+	if err != nil {
+		return errors.Wrapf(err, "failed to trigger job '%v'", config.JobName)
+	}
+	commonPipelineEnvironment.custom.jenkinsJobBuildURL = build.GetUrl()
+	log.Entry().Infof("Build #%v of job '%v' started: %v", build.GetBuildNumber(), config.JobName, build.GetUrl())
+
+	pollInterval := time.Duration(config.PollInterval) * time.Second
+	if config.StreamConsoleOutput {
+		err = jenkins.WaitForBuildToFinishAndStreamOutput(ctx, build, pollInterval, log.Writer())
+	} else {
+		err = jenkins.WaitForBuildToFinish(ctx, build, pollInterval)
+	}
+	if err != nil {
+		log.SetErrorCategory(log.ErrorInfrastructure)
+		return errors.Wrapf(err, "build #%v of job '%v' did not finish within %v minutes", build.GetBuildNumber(), config.JobName, config.Timeout)
+	}
+
+	result := build.GetResult()
+	commonPipelineEnvironment.custom.jenkinsJobResult = result
+	log.Entry().Infof("Build #%v of job '%v' finished with result %v", build.GetBuildNumber(), config.JobName, result)
+
+	if err := downloadJenkinsArtifacts(ctx, config, utils, build); err != nil {
+		return err
+	}
+
+	switch result {
+	case "SUCCESS":
+		return nil
+	case "UNSTABLE":
+		if config.AllowUnstable {
+			log.Entry().Warnf("Build #%v of job '%v' is unstable", build.GetBuildNumber(), config.JobName)
+			return nil
+		}
+		log.SetErrorCategory(log.ErrorTest)
+	default:
+		log.SetErrorCategory(log.ErrorBuild)
+	}
+	return fmt.Errorf("build #%v of job '%v' finished with result %v", build.GetBuildNumber(), config.JobName, result)
+}
+
+func downloadJenkinsArtifacts(ctx context.Context, config *jenkinsTriggerJobOptions, utils jenkinsTriggerJobUtils, build jenkins.Build) error {
+	if len(config.Artifacts) == 0 {
+		return nil
+	}
+	if err := utils.MkdirAll(config.ArtifactsDirectory, 0777); err != nil {
+		return errors.Wrapf(err, "failed to create directory '%v'", config.ArtifactsDirectory)
+	}
+	for _, fileName := range config.Artifacts {
+		artifact, err := utils.FetchBuildArtifact(ctx, build, fileName)
+		if err != nil {
+			return err
+		}
+		data, err := artifact.GetData(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to download artifact '%v'", fileName)
+		}
+		target := filepath.Join(config.ArtifactsDirectory, artifact.FileName())
+		if err := utils.FileWrite(target, data, 0666); err != nil {
+			return errors.Wrapf(err, "failed to write artifact '%v'", target)
+		}
+		log.Entry().Infof("Downloaded artifact '%v' to '%v'", fileName, target)
+	}
+	return nil
+}
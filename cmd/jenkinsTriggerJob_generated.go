@@ -0,0 +1,324 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type jenkinsTriggerJobOptions struct {
+	JenkinsURL          string                 `json:"jenkinsUrl,omitempty"`
+	JenkinsUsername     string                 `json:"jenkinsUsername,omitempty"`
+	JenkinsToken        string                 `json:"jenkinsToken,omitempty"`
+	JobName             string                 `json:"jobName,omitempty"`
+	JobParameters       map[string]interface{} `json:"jobParameters,omitempty"`
+	Timeout             int                    `json:"timeout,omitempty"`
+	PollInterval        int                    `json:"pollInterval,omitempty"`
+	StreamConsoleOutput bool                   `json:"streamConsoleOutput,omitempty"`
+	Artifacts           []string               `json:"artifacts,omitempty"`
+	ArtifactsDirectory  string                 `json:"artifactsDirectory,omitempty"`
+	AllowUnstable       bool                   `json:"allowUnstable,omitempty"`
+}
+
+type jenkinsTriggerJobCommonPipelineEnvironment struct {
+	custom struct {
+		jenkinsJobBuildURL string
+		jenkinsJobResult   string
+	}
+}
+
+func (p *jenkinsTriggerJobCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "jenkinsJobBuildUrl", value: p.custom.jenkinsJobBuildURL},
+		{category: "custom", name: "jenkinsJobResult", value: p.custom.jenkinsJobResult},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Piper environment")
+	}
+}
+
+// JenkinsTriggerJobCommand Triggers a Jenkins job and waits for its result
+func JenkinsTriggerJobCommand() *cobra.Command {
+	const STEP_NAME = "jenkinsTriggerJob"
+
+	metadata := jenkinsTriggerJobMetadata()
+	var stepConfig jenkinsTriggerJobOptions
+	var startTime time.Time
+	var commonPipelineEnvironment jenkinsTriggerJobCommonPipelineEnvironment
+	var logCollector *log.CollectorHook
+
+	var createJenkinsTriggerJobCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Triggers a Jenkins job and waits for its result",
+		Long: `This step triggers a (parameterized) job on Jenkins and waits till the triggered build is finished.
+
+The job can run on the Jenkins instance executing the pipeline or on another Jenkins instance defined via [` + "`" + `jenkinsUrl` + "`" + `](#jenkinsurl).
+While waiting, the console output of the triggered build is streamed into the log of the step.
+After the build is finished, the artifacts defined via [` + "`" + `artifacts` + "`" + `](#artifacts) are downloaded into the workspace.
+
+The result of the triggered build defines the result of the step: the step fails in case the build is not successful, unstable builds are only accepted with [` + "`" + `allowUnstable` + "`" + `](#allowunstable).
+The URL and the result of the triggered build are written to the commonPipelineEnvironment.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.JenkinsUsername)
+			log.RegisterSecret(stepConfig.JenkinsToken)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
+				telemetry.Send(&telemetryData)
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunk.Send(&telemetryData, logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunk.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			jenkinsTriggerJob(stepConfig, &telemetryData, &commonPipelineEnvironment)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addJenkinsTriggerJobFlags(createJenkinsTriggerJobCmd, &stepConfig)
+	return createJenkinsTriggerJobCmd
+}
+
+func addJenkinsTriggerJobFlags(cmd *cobra.Command, stepConfig *jenkinsTriggerJobOptions) {
+	cmd.Flags().StringVar(&stepConfig.JenkinsURL, "jenkinsUrl", os.Getenv("PIPER_jenkinsUrl"), "The URL of the Jenkins instance running the job. Defaults to the Jenkins instance executing the pipeline.")
+	cmd.Flags().StringVar(&stepConfig.JenkinsUsername, "jenkinsUsername", os.Getenv("PIPER_jenkinsUsername"), "The user name for the Jenkins instance running the job.")
+	cmd.Flags().StringVar(&stepConfig.JenkinsToken, "jenkinsToken", os.Getenv("PIPER_jenkinsToken"), "The API token for the Jenkins instance running the job.")
+	cmd.Flags().StringVar(&stepConfig.JobName, "jobName", os.Getenv("PIPER_jobName"), "The full name of the job to trigger, folders are separated by `/`, e.g. `folder/job`.")
+
+	cmd.Flags().IntVar(&stepConfig.Timeout, "timeout", 60, "Timeout in minutes for the triggered build to start and finish.")
+	cmd.Flags().IntVar(&stepConfig.PollInterval, "pollInterval", 10, "Interval in seconds for polling the status of the triggered build. The step fails if polling the status fails three times in a row.")
+	cmd.Flags().BoolVar(&stepConfig.StreamConsoleOutput, "streamConsoleOutput", true, "Writes the console output of the triggered build into the log of the step.")
+	cmd.Flags().StringSliceVar(&stepConfig.Artifacts, "artifacts", []string{}, "File names of the artifacts of the triggered build which are downloaded into the workspace.")
+	cmd.Flags().StringVar(&stepConfig.ArtifactsDirectory, "artifactsDirectory", `.`, "Directory in the workspace the artifacts are downloaded to.")
+	cmd.Flags().BoolVar(&stepConfig.AllowUnstable, "allowUnstable", false, "Accepts an unstable result of the triggered build.")
+
+	cmd.MarkFlagRequired("jobName")
+}
+
+// retrieve step metadata
+func jenkinsTriggerJobMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "jenkinsTriggerJob",
+			Aliases:     []config.Alias{},
+			Description: "Triggers a Jenkins job and waits for its result",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "jenkinsCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing the user name and API token for the Jenkins instance running the job.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name: "jenkinsUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/jenkins", "$(vaultBasePath)/$(vaultPipelineName)/jenkins", "$(vaultBasePath)/GROUP-SECRETS/jenkins"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "url"}},
+						Default:   os.Getenv("PIPER_jenkinsUrl"),
+					},
+					{
+						Name: "jenkinsUsername",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "jenkinsCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/jenkins", "$(vaultBasePath)/$(vaultPipelineName)/jenkins", "$(vaultBasePath)/GROUP-SECRETS/jenkins"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "userId"}},
+						Default:   os.Getenv("PIPER_jenkinsUsername"),
+					},
+					{
+						Name: "jenkinsToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "jenkinsCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/jenkins", "$(vaultBasePath)/$(vaultPipelineName)/jenkins", "$(vaultBasePath)/GROUP-SECRETS/jenkins"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "token"}},
+						Default:   os.Getenv("PIPER_jenkinsToken"),
+					},
+					{
+						Name:        "jobName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_jobName"),
+					},
+					{
+						Name:        "jobParameters",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "timeout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     60,
+					},
+					{
+						Name:        "pollInterval",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     10,
+					},
+					{
+						Name:        "streamConsoleOutput",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "artifacts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "artifactsDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.`,
+					},
+					{
+						Name:        "allowUnstable",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"Name": "custom/jenkinsJobBuildUrl"},
+							{"Name": "custom/jenkinsJobResult"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJenkinsTriggerJobCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := JenkinsTriggerJobCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "jenkinsTriggerJob", testCmd.Use, "command name incorrect")
+
+}
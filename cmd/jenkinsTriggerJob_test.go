@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/jenkins"
+	"github.com/SAP/jenkins-library/pkg/jenkins/mocks"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jenkinsTriggerJobMockUtils struct {
+	*mock.FilesMock
+	build      jenkins.Build
+	artifacts  map[string]jenkins.Artifact
+	triggerErr error
+	jobName    string
+	parameters map[string]string
+}
+
+func (j *jenkinsTriggerJobMockUtils) TriggerJob(ctx context.Context, jobName string, parameters map[string]string) (jenkins.Build, error) {
+	j.jobName = jobName
+	j.parameters = parameters
+	if j.triggerErr != nil {
+		return nil, j.triggerErr
+	}
+	return j.build, nil
+}
+
+func (j *jenkinsTriggerJobMockUtils) FetchBuildArtifact(ctx context.Context, build jenkins.Build, fileName string) (jenkins.Artifact, error) {
+	artifact, ok := j.artifacts[fileName]
+	if !ok {
+		return nil, fmt.Errorf("failed to fetch artifact: Artifact '%s' not found", fileName)
+	}
+	return artifact, nil
+}
+
+func newJenkinsTriggerJobTestsUtils(ctx context.Context, result string) (*jenkinsTriggerJobMockUtils, *mocks.Build) {
+	build := &mocks.Build{}
+	build.On("GetUrl").Return("https://jenkins.example.com/job/folder/job/deploy/42/")
+	build.On("GetBuildNumber").Return(int64(42))
+	build.On("Poll", ctx).Return(200, nil)
+	build.On("IsBuilding").Return(true).Once()
+	build.On("IsBuilding").Return(false)
+	build.On("GetConsoleOutputFromIndex", ctx, int64(0)).Return("deploying\n", int64(10), false, nil)
+	build.On("GetConsoleOutputFromIndex", ctx, int64(10)).Return("", int64(10), false, nil)
+	build.On("GetResult").Return(result)
+	utils := jenkinsTriggerJobMockUtils{
+		FilesMock: &mock.FilesMock{},
+		build:     build,
+		artifacts: map[string]jenkins.Artifact{},
+	}
+	return &utils, build
+}
+
+func TestRunJenkinsTriggerJob(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{
+			JobName:             "folder/deploy",
+			JobParameters:       map[string]interface{}{"VERSION": "1.2.3", "DRY_RUN": false},
+			StreamConsoleOutput: true,
+			Artifacts:           []string{"report.xml"},
+			ArtifactsDirectory:  "downstream",
+		}
+		utils, build := newJenkinsTriggerJobTestsUtils(ctx, "SUCCESS")
+		artifact := &mocks.Artifact{}
+		artifact.On("GetData", ctx).Return([]byte("<report/>"), nil)
+		artifact.On("FileName").Return("report.xml")
+		utils.artifacts["report.xml"] = artifact
+		cpe := jenkinsTriggerJobCommonPipelineEnvironment{}
+
+		err := runJenkinsTriggerJob(ctx, &config, nil, utils, &cpe)
+
+		assert.NoError(t, err)
+		build.AssertExpectations(t)
+		assert.Equal(t, "folder/deploy", utils.jobName)
+		assert.Equal(t, map[string]string{"VERSION": "1.2.3", "DRY_RUN": "false"}, utils.parameters)
+		assert.Equal(t, "https://jenkins.example.com/job/folder/job/deploy/42/", cpe.custom.jenkinsJobBuildURL)
+		assert.Equal(t, "SUCCESS", cpe.custom.jenkinsJobResult)
+		content, err := utils.FileRead("downstream/report.xml")
+		require.NoError(t, err)
+		assert.Equal(t, "<report/>", string(content))
+	})
+
+	t.Run("failed build", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{JobName: "deploy"}
+		utils, _ := newJenkinsTriggerJobTestsUtils(ctx, "FAILURE")
+		cpe := jenkinsTriggerJobCommonPipelineEnvironment{}
+
+		err := runJenkinsTriggerJob(ctx, &config, nil, utils, &cpe)
+
+		assert.EqualError(t, err, "build #42 of job 'deploy' finished with result FAILURE")
+		assert.Equal(t, "FAILURE", cpe.custom.jenkinsJobResult)
+	})
+
+	t.Run("unstable build", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{JobName: "deploy"}
+		utils, _ := newJenkinsTriggerJobTestsUtils(ctx, "UNSTABLE")
+
+		err := runJenkinsTriggerJob(ctx, &config, nil, utils, &jenkinsTriggerJobCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "build #42 of job 'deploy' finished with result UNSTABLE")
+	})
+
+	t.Run("allowed unstable build", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{JobName: "deploy", AllowUnstable: true}
+		utils, _ := newJenkinsTriggerJobTestsUtils(ctx, "UNSTABLE")
+
+		err := runJenkinsTriggerJob(ctx, &config, nil, utils, &jenkinsTriggerJobCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{JobName: "deploy", Timeout: 1}
+		timeoutCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		utils, _ := newJenkinsTriggerJobTestsUtils(timeoutCtx, "")
+
+		err := runJenkinsTriggerJob(timeoutCtx, &config, nil, utils, &jenkinsTriggerJobCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "build #42 of job 'deploy' did not finish within 1 minutes: failed to wait for build to finish: context canceled")
+	})
+
+	t.Run("trigger error", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{JobName: "deploy"}
+		utils, _ := newJenkinsTriggerJobTestsUtils(ctx, "")
+		utils.triggerErr = fmt.Errorf("unable to queue build")
+
+		err := runJenkinsTriggerJob(ctx, &config, nil, utils, &jenkinsTriggerJobCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to trigger job 'deploy': unable to queue build")
+	})
+
+	t.Run("missing artifact", func(t *testing.T) {
+		t.Parallel()
+		config := jenkinsTriggerJobOptions{JobName: "deploy", Artifacts: []string{"missing.xml"}, ArtifactsDirectory: "."}
+		utils, _ := newJenkinsTriggerJobTestsUtils(ctx, "SUCCESS")
+
+		err := runJenkinsTriggerJob(ctx, &config, nil, utils, &jenkinsTriggerJobCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to fetch artifact: Artifact 'missing.xml' not found")
+	})
+}
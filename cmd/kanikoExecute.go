@@ -11,12 +11,17 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
 	"github.com/SAP/jenkins-library/pkg/docker"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/syft"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 )
 
+// kanikoBOMFile is the BOM of the built image, its JSON representation is written to bom-docker.json
+const kanikoBOMFile = "bom-docker.xml"
+
 func kanikoExecute(config kanikoExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment) {
 	// for command execution use Command
 	c := command.Command{
@@ -50,6 +55,11 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		telemetryData.Custom1 = config.ContainerBuildOptions
 	}
 
+	if config.CreateBOM && len(config.SyftDownloadSha256) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("parameter syftDownloadSha256 is mandatory when createBOM is active")
+	}
+
 	// prepare kaniko container for running with proper Docker config.json and custom certificates
 	// custom certificates will be downloaded and appended to ca-certificates.crt file used in container
 	prepCommand := strings.Split(config.ContainerPreparationCommand, " ")
@@ -118,6 +128,53 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		log.SetErrorCategory(log.ErrorBuild)
 		return errors.Wrap(err, "execution of '/kaniko/executor' failed")
 	}
+	for i, option := range config.BuildOptions {
+		if option == "--destination" && i+1 < len(config.BuildOptions) {
+			piperutils.AddStepArtifacts(piperutils.Path{Name: "containerImage", Target: config.BuildOptions[i+1]})
+		} else if strings.HasPrefix(option, "--destination=") {
+			piperutils.AddStepArtifacts(piperutils.Path{Name: "containerImage", Target: strings.TrimPrefix(option, "--destination=")})
+		}
+	}
+
+	if config.CreateBOM {
+		return createImageBOM(config, commonPipelineEnvironment, execRunner, httpClient, fileUtils)
+	}
+	return nil
+}
+
+// createImageBOM creates the BOM of the pushed image, all destinations refer to the same image
+func createImageBOM(config *kanikoExecuteOptions, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, execRunner command.ExecRunner, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils) error {
+	image := ""
+	for i, option := range config.BuildOptions {
+		if option == "--destination" && i+1 < len(config.BuildOptions) {
+			image = config.BuildOptions[i+1]
+			break
+		}
+		if strings.HasPrefix(option, "--destination=") {
+			image = strings.TrimPrefix(option, "--destination=")
+			break
+		}
+	}
+	if len(image) == 0 {
+		log.Entry().Warning("No BOM created since the image has not been pushed")
+		return nil
+	}
+
+	syftPath, err := syft.Install(config.SyftDownloadURL, config.SyftDownloadSha256, "/kaniko/syft", httpClient, fileUtils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorInfrastructure)
+		return err
+	}
+	// syft uses the credentials provided for kaniko to pull the image
+	execRunner.SetEnv([]string{"DOCKER_CONFIG=/kaniko/.docker"})
+	if err := syft.CreateBOM(syftPath, image, kanikoBOMFile, execRunner); err != nil {
+		return err
+	}
+	if err := cyclonedx.ConvertToJSON(kanikoBOMFile, fileUtils); err != nil {
+		return err
+	}
+	commonPipelineEnvironment.custom.sbomPath = kanikoBOMFile
+	piperutils.AddStepArtifacts(piperutils.Path{Name: "bom", Target: kanikoBOMFile})
 	return nil
 }
 
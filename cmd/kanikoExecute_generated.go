@@ -11,6 +11,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
@@ -27,6 +28,9 @@ type kanikoExecuteOptions struct {
 	CustomTLSCertificateLinks   []string `json:"customTlsCertificateLinks,omitempty"`
 	DockerConfigJSON            string   `json:"dockerConfigJSON,omitempty"`
 	DockerfilePath              string   `json:"dockerfilePath,omitempty"`
+	CreateBOM                   bool     `json:"createBOM,omitempty"`
+	SyftDownloadURL             string   `json:"syftDownloadUrl,omitempty"`
+	SyftDownloadSha256          string   `json:"syftDownloadSha256,omitempty"`
 }
 
 type kanikoExecuteCommonPipelineEnvironment struct {
@@ -34,6 +38,9 @@ type kanikoExecuteCommonPipelineEnvironment struct {
 		registryURL  string
 		imageNameTag string
 	}
+	custom struct {
+		sbomPath string
+	}
 }
 
 func (p *kanikoExecuteCommonPipelineEnvironment) persist(path, resourceName string) {
@@ -44,6 +51,7 @@ func (p *kanikoExecuteCommonPipelineEnvironment) persist(path, resourceName stri
 	}{
 		{category: "container", name: "registryUrl", value: p.container.registryURL},
 		{category: "container", name: "imageNameTag", value: p.container.imageNameTag},
+		{category: "custom", name: "sbomPath", value: p.custom.sbomPath},
 	}
 
 	errCount := 0
@@ -106,9 +114,11 @@ func KanikoExecuteCommand() *cobra.Command {
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -145,6 +155,9 @@ func addKanikoExecuteFlags(cmd *cobra.Command, stepConfig *kanikoExecuteOptions)
 	cmd.Flags().StringSliceVar(&stepConfig.CustomTLSCertificateLinks, "customTlsCertificateLinks", []string{}, "List containing download links of custom TLS certificates. This is required to ensure trusted connections to registries with custom certificates.")
 	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
 	cmd.Flags().StringVar(&stepConfig.DockerfilePath, "dockerfilePath", `Dockerfile`, "Defines the location of the Dockerfile relative to the Jenkins workspace.")
+	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) of the pushed image using [syft](https://github.com/anchore/syft). The BOM is written to `bom-docker.xml` and `bom-docker.json` and its path is provided in the commonPipelineEnvironment as `custom/sbomPath`.")
+	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v0.46.3/syft_0.46.3_linux_amd64.tar.gz`, "Download url of the syft release archive used for creating the BOM.")
+	cmd.Flags().StringVar(&stepConfig.SyftDownloadSha256, "syftDownloadSha256", os.Getenv("PIPER_syftDownloadSha256"), "SHA-256 checksum of the syft release archive at `syftDownloadUrl`, as published in the checksums file of the syft release. The archive is only extracted in case its checksum matches. Mandatory when `createBOM` is active.")
 
 }
 
@@ -186,7 +199,7 @@ func kanikoExecuteMetadata() config.StepData {
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
 						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "containerImageNameAndTag"}},
+						Aliases:     []config.Alias{{Name: "containerImageNameAndTag", Deprecated: true}},
 						Default:     os.Getenv("PIPER_containerImage"),
 					},
 					{
@@ -278,6 +291,33 @@ func kanikoExecuteMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "dockerfile"}},
 						Default:     `Dockerfile`,
 					},
+					{
+						Name:        "createBOM",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "syftDownloadUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://github.com/anchore/syft/releases/download/v0.46.3/syft_0.46.3_linux_amd64.tar.gz`,
+					},
+					{
+						Name:        "syftDownloadSha256",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_syftDownloadSha256"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -291,6 +331,7 @@ func kanikoExecuteMetadata() config.StepData {
 						Parameters: []map[string]interface{}{
 							{"Name": "container/registryUrl"},
 							{"Name": "container/imageNameTag"},
+							{"Name": "custom/sbomPath"},
 						},
 					},
 				},
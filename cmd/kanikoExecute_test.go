@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -82,6 +86,19 @@ func (f *kanikoFileMock) Glob(pattern string) (matches []string, err error) {
 	return nil, fmt.Errorf("not implemented. func is only present in order to fullfil the interface contract. Needs to be ajusted in case it gets used.")
 }
 
+func syftArchive(t *testing.T) string {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content := []byte("syft binary")
+	assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "syft", Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tarWriter.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	return buffer.String()
+}
+
 func TestRunKanikoExecute(t *testing.T) {
 
 	commonPipelineEnvironment := kanikoExecuteCommonPipelineEnvironment{}
@@ -160,6 +177,85 @@ func TestRunKanikoExecute(t *testing.T) {
 
 	})
 
+	t.Run("success case - create BOM", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			BuildOptions:                []string{"--skip-tls-verify-pull"},
+			ContainerImage:              "my.registry.com/myImage:tag",
+			ContainerPreparationCommand: "rm -f /kaniko/.docker/config.json",
+			DockerfilePath:              "Dockerfile",
+			CreateBOM:                   true,
+			SyftDownloadURL:             "https://example.org/syft.tar.gz",
+		}
+		cpe := kanikoExecuteCommonPipelineEnvironment{}
+
+		runner := &mock.ExecMockRunner{}
+
+		archive := syftArchive(t)
+		checksum := sha256.Sum256([]byte(archive))
+		config.SyftDownloadSha256 = hex.EncodeToString(checksum[:])
+		client := &kanikoMockClient{
+			httpStatusCode: http.StatusOK,
+			responseBody:   archive,
+		}
+		fileUtils := &kanikoFileMock{
+			fileReadContent:  map[string]string{"bom-docker.xml": `<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1"><components><component type="library"><name>openssl</name><version>1.1.1n</version></component></components></bom>`},
+			fileWriteContent: map[string]string{},
+		}
+
+		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, runner, client, fileUtils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"https://example.org/syft.tar.gz"}, client.urlsCalled)
+		assert.Equal(t, "syft binary", fileUtils.fileWriteContent["/kaniko/syft/syft"])
+		if assert.Len(t, runner.Calls, 3) {
+			assert.Equal(t, mock.ExecCall{Exec: "/kaniko/syft/syft", Params: []string{"packages", "registry:my.registry.com/myImage:tag", "-o", "cyclonedx-xml", "--file", "bom-docker.xml"}}, runner.Calls[2])
+		}
+		assert.Contains(t, runner.Env, "DOCKER_CONFIG=/kaniko/.docker")
+		assert.Contains(t, fileUtils.fileWriteContent["bom-docker.json"], `"name": "openssl"`)
+		assert.Equal(t, "bom-docker.xml", cpe.custom.sbomPath)
+	})
+
+	t.Run("error case - create BOM without checksum", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			ContainerImage:              "my.registry.com/myImage:tag",
+			ContainerPreparationCommand: "rm -f /kaniko/.docker/config.json",
+			DockerfilePath:              "Dockerfile",
+			CreateBOM:                   true,
+			SyftDownloadURL:             "https://example.org/syft.tar.gz",
+		}
+		runner := &mock.ExecMockRunner{}
+		client := &kanikoMockClient{}
+
+		err := runKanikoExecute(config, &telemetry.CustomData{}, &kanikoExecuteCommonPipelineEnvironment{}, runner, client, &kanikoFileMock{})
+
+		assert.EqualError(t, err, "parameter syftDownloadSha256 is mandatory when createBOM is active")
+		assert.Empty(t, runner.Calls)
+		assert.Empty(t, client.urlsCalled)
+	})
+
+	t.Run("success case - no BOM without push", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			ContainerPreparationCommand: "rm -f /kaniko/.docker/config.json",
+			DockerfilePath:              "Dockerfile",
+			CreateBOM:                   true,
+			SyftDownloadSha256:          "0123456789abcdef",
+		}
+		cpe := kanikoExecuteCommonPipelineEnvironment{}
+
+		runner := &mock.ExecMockRunner{}
+		client := &kanikoMockClient{}
+		fileUtils := &kanikoFileMock{
+			fileWriteContent: map[string]string{},
+		}
+
+		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, runner, client, fileUtils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, client.urlsCalled)
+		assert.Len(t, runner.Calls, 2)
+		assert.Empty(t, cpe.custom.sbomPath)
+	})
+
 	t.Run("no error case - when cert update skipped", func(t *testing.T) {
 		config := &kanikoExecuteOptions{
 			BuildOptions:                []string{"--skip-tls-verify-pull"},
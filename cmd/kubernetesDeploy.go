@@ -6,16 +6,35 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/kubernetes"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
 )
 
-func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.CustomData) {
+// values of custom/kubernetesDeployStatus in the commonPipelineEnvironment
+const (
+	kubernetesDeployStatusSuccess    = "success"
+	kubernetesDeployStatusFailure    = "failure"
+	kubernetesDeployStatusRolledBack = "rolledBack"
+)
+
+// rolloutWorkload is a Deployment or StatefulSet whose rollout is verified
+type rolloutWorkload struct {
+	kind     string
+	name     string
+	selector string
+}
+
+func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) {
 	c := command.Command{
 		ErrorCategoryMapping: map[string][]string{
 			log.ErrorConfiguration.String(): {
@@ -38,22 +57,36 @@ func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.C
 	c.Stderr(log.Writer())
 
 	// error situations should stop execution through log.Entry().Fatal() call which leads to an os.Exit(1) in the end
-	err := runKubernetesDeploy(config, &c, log.Writer())
+	err := runKubernetesDeploy(config, &c, log.Writer(), commonPipelineEnvironment)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runKubernetesDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer) error {
-	if config.DeployTool == "helm" || config.DeployTool == "helm3" {
-		return runHelmDeploy(config, command, stdout)
-	} else if config.DeployTool == "kubectl" {
-		return runKubectlDeploy(config, command)
+func runKubernetesDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) error {
+	var err error
+	if config.DeployTool == "helm3" {
+		err = runHelmDeploy(config, command, stdout, commonPipelineEnvironment)
+	} else if config.DeployTool == "helm" {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		err = fmt.Errorf("deployTool 'helm' (Helm 2 with Tiller) is not supported anymore, please switch to deployTool 'helm3'")
+	} else if config.DeployTool == "kubectl" || config.DeployTool == "kustomize" {
+		err = runKubectlDeploy(config, command, stdout, commonPipelineEnvironment)
+	} else {
+		err = fmt.Errorf("Failed to execute deployments")
+	}
+	if err != nil {
+		// a successful rollback already reported its own status
+		if len(commonPipelineEnvironment.custom.kubernetesDeployStatus) == 0 {
+			commonPipelineEnvironment.custom.kubernetesDeployStatus = kubernetesDeployStatusFailure
+		}
+		return err
 	}
-	return fmt.Errorf("Failed to execute deployments")
+	commonPipelineEnvironment.custom.kubernetesDeployStatus = kubernetesDeployStatusSuccess
+	return nil
 }
 
-func runHelmDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer) error {
+func runHelmDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) error {
 	if len(config.ChartPath) <= 0 {
 		return fmt.Errorf("chart path has not been set, please configure chartPath parameter")
 	}
@@ -77,20 +110,10 @@ func runHelmDeploy(config kubernetesDeployOptions, command command.ExecRunner, s
 	log.Entry().WithFields(helmLogFields).Debug("Calling Helm")
 
 	helmEnv := []string{fmt.Sprintf("KUBECONFIG=%v", config.KubeConfig)}
-	if config.DeployTool == "helm" && len(config.TillerNamespace) > 0 {
-		helmEnv = append(helmEnv, fmt.Sprintf("TILLER_NAMESPACE=%v", config.TillerNamespace))
-	}
 	log.Entry().Debugf("Helm SetEnv: %v", helmEnv)
 	command.SetEnv(helmEnv)
 	command.Stdout(stdout)
 
-	if config.DeployTool == "helm" {
-		initParams := []string{"init", "--client-only"}
-		if err := command.RunExecutable("helm", initParams...); err != nil {
-			log.Entry().WithError(err).Fatal("Helm init call failed")
-		}
-	}
-
 	var secretsData string
 	if len(config.DockerConfigJSON) == 0 && (len(config.ContainerRegistryUser) == 0 || len(config.ContainerRegistryPassword) == 0) {
 		log.Entry().Info("No container registry credentials or docker config.json file provided or credentials incomplete: skipping secret creation")
@@ -137,37 +160,48 @@ func runHelmDeploy(config kubernetesDeployOptions, command command.ExecRunner, s
 		ingressHosts += fmt.Sprintf(",ingress.hosts[%v]=%v", i, h)
 	}
 
-	upgradeParams := []string{
-		"upgrade",
-		config.DeploymentName,
-		config.ChartPath,
-	}
-
+	valuesParams := []string{}
 	for _, v := range config.HelmValues {
-		upgradeParams = append(upgradeParams, "--values", v)
+		valuesParams = append(valuesParams, "--values", v)
 	}
-
-	upgradeParams = append(
-		upgradeParams,
-		"--install",
+	setParams := []string{
 		"--namespace", config.Namespace,
 		"--set",
 		fmt.Sprintf("image.repository=%v/%v,image.tag=%v%v%v", containerRegistry, containerImageName, containerImageTag, secretsData, ingressHosts),
-	)
+	}
 
-	if config.ForceUpdates {
-		upgradeParams = append(upgradeParams, "--force")
+	if len(config.RenderedManifestFile) > 0 {
+		templateParams := append(append([]string{"template", config.DeploymentName, config.ChartPath}, valuesParams...), setParams...)
+		var rendered bytes.Buffer
+		command.Stdout(&rendered)
+		log.Entry().Info("Calling helm template ...")
+		err := command.RunExecutable("helm", templateParams...)
+		command.Stdout(stdout)
+		if err != nil {
+			return errors.Wrapf(err, "failed to render chart '%v'", config.ChartPath)
+		}
+		if err := writeRenderedManifest(config.RenderedManifestFile, rendered.Bytes(), &piperutils.Files{}); err != nil {
+			return err
+		}
 	}
 
-	if config.DeployTool == "helm" {
-		upgradeParams = append(upgradeParams, "--wait", "--timeout", strconv.Itoa(config.HelmDeployWaitSeconds))
+	upgradeParams := append([]string{"upgrade", config.DeploymentName, config.ChartPath}, valuesParams...)
+	upgradeParams = append(upgradeParams, "--install")
+	upgradeParams = append(upgradeParams, setParams...)
+
+	if config.ForceUpdates {
+		upgradeParams = append(upgradeParams, "--force")
 	}
 
-	if config.DeployTool == "helm3" {
-		upgradeParams = append(upgradeParams, "--wait", "--timeout", fmt.Sprintf("%vs", config.HelmDeployWaitSeconds))
+	// with rollout verification the step itself waits for the rollout
+	if !config.VerifyRollout {
+		upgradeParams = append(upgradeParams, "--wait")
 	}
+	upgradeParams = append(upgradeParams, "--timeout", fmt.Sprintf("%vs", config.HelmDeployWaitSeconds))
 
-	if !config.KeepFailedDeployments {
+	// the step only takes care of the rollback itself in case of rollout verification with rollbackOnFailure
+	stepRollsBack := config.VerifyRollout && config.RollbackOnFailure
+	if !config.KeepFailedDeployments && !stepRollsBack {
 		upgradeParams = append(upgradeParams, "--atomic")
 	}
 
@@ -179,16 +213,56 @@ func runHelmDeploy(config kubernetesDeployOptions, command command.ExecRunner, s
 		upgradeParams = append(upgradeParams, config.AdditionalParameters...)
 	}
 
+	helmParams := []string{"--namespace", config.Namespace}
+	if len(config.KubeContext) > 0 {
+		helmParams = append(helmParams, "--kube-context", config.KubeContext)
+	}
+	rollback := func() error {
+		rollbackParams := append([]string{"rollback", config.DeploymentName}, helmParams...)
+		rollbackParams = append(rollbackParams, "--wait", "--timeout", fmt.Sprintf("%vs", config.HelmDeployWaitSeconds))
+		log.Entry().Infof("Rolling back release '%v' to its previous revision ...", config.DeploymentName)
+		return command.RunExecutable("helm", rollbackParams...)
+	}
+
 	command.Stdout(stdout)
 	log.Entry().Info("Calling helm upgrade ...")
 	log.Entry().Debugf("Helm parameters %v", upgradeParams)
 	if err := command.RunExecutable("helm", upgradeParams...); err != nil {
-		log.Entry().WithError(err).Fatal("Helm upgrade call failed")
+		err = errors.Wrap(err, "Helm upgrade call failed")
+		if stepRollsBack {
+			// without --atomic helm does not roll back a failed upgrade itself
+			return rollBackOnFailure(config, err, rollback, commonPipelineEnvironment)
+		}
+		return err
+	}
+
+	if config.VerifyRollout {
+		return verifyHelmRollout(config, helmParams, command, stdout, rollback, commonPipelineEnvironment)
 	}
 	return nil
 }
 
-func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner) error {
+func verifyHelmRollout(config kubernetesDeployOptions, helmParams []string, command command.ExecRunner, stdout io.Writer, rollback func() error, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) error {
+	kubeParams := []string{fmt.Sprintf("--namespace=%v", config.Namespace)}
+	if len(config.KubeContext) > 0 {
+		kubeParams = append(kubeParams, fmt.Sprintf("--context=%v", config.KubeContext))
+	}
+
+	var manifest bytes.Buffer
+	command.Stdout(&manifest)
+	err := command.RunExecutable("helm", append([]string{"get", "manifest", config.DeploymentName}, helmParams...)...)
+	command.Stdout(stdout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve manifest of release '%v'", config.DeploymentName)
+	}
+	workloads, err := rolloutWorkloads(manifest.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse manifest of release '%v'", config.DeploymentName)
+	}
+	return verifyRolloutOrRollBack(config, workloads, kubeParams, command, rollback, commonPipelineEnvironment)
+}
+
+func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner, stdout io.Writer, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) error {
 	_, containerRegistry, err := splitRegistryURL(config.ContainerRegistryURL)
 	if err != nil {
 		log.Entry().WithError(err).Fatalf("Container registry url '%v' incorrect", config.ContainerRegistryURL)
@@ -232,32 +306,249 @@ func runKubectlDeploy(config kubernetesDeployOptions, command command.ExecRunner
 		}
 	}
 
-	appTemplate, err := ioutil.ReadFile(config.AppTemplate)
-	if err != nil {
-		log.Entry().WithError(err).Fatalf("Error when reading appTemplate '%v'", config.AppTemplate)
+	// sourceParams define the resources to apply, manifest contains them for a lookup of the workloads to verify
+	var sourceParams []string
+	var manifest []byte
+	if config.DeployTool == "kustomize" {
+		if err := updateKustomizationImage(config, containerRegistry); err != nil {
+			return err
+		}
+		sourceParams = []string{"--kustomize", config.KustomizationPath}
+		if config.VerifyRollout || len(config.RenderedManifestFile) > 0 {
+			var rendered bytes.Buffer
+			command.Stdout(&rendered)
+			err := command.RunExecutable("kubectl", "kustomize", config.KustomizationPath)
+			command.Stdout(stdout)
+			if err != nil {
+				return errors.Wrapf(err, "failed to render kustomization '%v'", config.KustomizationPath)
+			}
+			manifest = rendered.Bytes()
+		}
+	} else {
+		appTemplate, err := ioutil.ReadFile(config.AppTemplate)
+		if err != nil {
+			log.Entry().WithError(err).Fatalf("Error when reading appTemplate '%v'", config.AppTemplate)
+		}
+
+		// Update image name in deployment yaml, expects placeholder like 'image: <image-name>'
+		re := regexp.MustCompile(`image:[ ]*<image-name>`)
+		appTemplate = []byte(re.ReplaceAllString(string(appTemplate), fmt.Sprintf("image: %v/%v", containerRegistry, config.Image)))
+
+		err = ioutil.WriteFile(config.AppTemplate, appTemplate, 0644)
+		if err != nil {
+			log.Entry().WithError(err).Fatalf("Error when updating appTemplate '%v'", config.AppTemplate)
+		}
+		sourceParams = []string{"--filename", config.AppTemplate}
+		manifest = appTemplate
 	}
 
-	// Update image name in deployment yaml, expects placeholder like 'image: <image-name>'
-	re := regexp.MustCompile(`image:[ ]*<image-name>`)
-	appTemplate = []byte(re.ReplaceAllString(string(appTemplate), fmt.Sprintf("image: %v/%v", containerRegistry, config.Image)))
+	if len(config.RenderedManifestFile) > 0 {
+		if err := writeRenderedManifest(config.RenderedManifestFile, manifest, &piperutils.Files{}); err != nil {
+			return err
+		}
+	}
 
-	err = ioutil.WriteFile(config.AppTemplate, appTemplate, 0700)
-	if err != nil {
-		log.Entry().WithError(err).Fatalf("Error when updating appTemplate '%v'", config.AppTemplate)
+	var previousManifest []byte
+	if config.VerifyRollout && config.RollbackOnFailure {
+		// remember the currently applied state in order to be able to re-apply it in case the rollout fails
+		var lastApplied bytes.Buffer
+		command.Stdout(&lastApplied)
+		err := command.RunExecutable("kubectl", kubectlParams(kubeParams, append(append([]string{"apply", "view-last-applied"}, sourceParams...), "--output", "yaml")...)...)
+		command.Stdout(stdout)
+		if err != nil {
+			log.Entry().WithError(err).Info("No previously applied manifest found, the deployment cannot be rolled back")
+		} else {
+			previousManifest = lastApplied.Bytes()
+		}
 	}
 
-	kubeApplyParams := append(kubeParams, "apply", "--filename", config.AppTemplate)
+	kubeApplyParams := kubectlParams(kubeParams, append([]string{"apply"}, sourceParams...)...)
 	if len(config.AdditionalParameters) > 0 {
 		kubeApplyParams = append(kubeApplyParams, config.AdditionalParameters...)
 	}
 
 	if err := command.RunExecutable("kubectl", kubeApplyParams...); err != nil {
 		log.Entry().Debugf("Running kubectl with following parameters: %v", kubeApplyParams)
-		log.Entry().WithError(err).Fatal("Deployment with kubectl failed.")
+		return errors.Wrap(err, "Deployment with kubectl failed.")
+	}
+
+	if !config.VerifyRollout {
+		return nil
+	}
+	workloads, err := rolloutWorkloads(manifest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse manifest of '%v'", sourceParams[1])
+	}
+	rollback := func() error {
+		if len(previousManifest) == 0 {
+			return fmt.Errorf("no previously applied manifest available")
+		}
+		// the previous manifest is not written to the workspace in order to keep it out of later commits
+		tmpDir, err := ioutil.TempDir("", "kubernetesDeploy")
+		if err != nil {
+			return errors.Wrap(err, "failed to create temporary directory for the previous manifest")
+		}
+		defer os.RemoveAll(tmpDir)
+		previousManifestFile := filepath.Join(tmpDir, "previous-manifest.yaml")
+		if err := ioutil.WriteFile(previousManifestFile, previousManifest, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write previous manifest '%v'", previousManifestFile)
+		}
+		log.Entry().Info("Re-applying the previously applied manifest ...")
+		return command.RunExecutable("kubectl", kubectlParams(kubeParams, "apply", "--filename", previousManifestFile)...)
+	}
+	return verifyRolloutOrRollBack(config, workloads, kubeParams, command, rollback, commonPipelineEnvironment)
+}
+
+// updateKustomizationImage sets the image to deploy in the images section of the kustomization
+func updateKustomizationImage(config kubernetesDeployOptions, containerRegistry string) error {
+	kustomizationFile := ""
+	for _, name := range kubernetes.KustomizationFileNames {
+		candidate := filepath.Join(config.KustomizationPath, name)
+		if _, err := os.Stat(candidate); err == nil {
+			kustomizationFile = candidate
+			break
+		}
+	}
+	if len(kustomizationFile) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no kustomization found in '%v'", config.KustomizationPath)
+	}
+
+	imageName, imageTag, err := splitFullImageName(config.Image)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "Container image '%v' incorrect", config.Image)
+	}
+	kustomization, err := ioutil.ReadFile(kustomizationFile)
+	if err != nil {
+		return errors.Wrapf(err, "Error when reading kustomization '%v'", kustomizationFile)
+	}
+	kustomization, err = kubernetes.SetKustomizeImage(kustomization, kubernetes.KustomizeImage{
+		Name:    imageName,
+		NewName: fmt.Sprintf("%v/%v", containerRegistry, imageName),
+		NewTag:  imageTag,
+	})
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "Error when updating kustomization '%v'", kustomizationFile)
+	}
+	if err := ioutil.WriteFile(kustomizationFile, kustomization, 0644); err != nil {
+		return errors.Wrapf(err, "Error when updating kustomization '%v'", kustomizationFile)
+	}
+	return nil
+}
+
+// verifyRolloutOrRollBack waits for the rollout of all workloads and rolls the deployment back in case one of them fails
+func verifyRolloutOrRollBack(config kubernetesDeployOptions, workloads []rolloutWorkload, kubeParams []string, command command.ExecRunner, rollback func() error, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) error {
+	err := verifyRollout(workloads, kubeParams, config.RolloutTimeoutSeconds, command)
+	if err == nil {
+		return nil
+	}
+	log.SetErrorCategory(log.ErrorInfrastructure)
+	return rollBackOnFailure(config, err, rollback, commonPipelineEnvironment)
+}
+
+// rollBackOnFailure rolls the deployment back after it failed with err in case rollbackOnFailure is active
+func rollBackOnFailure(config kubernetesDeployOptions, err error, rollback func() error, commonPipelineEnvironment *kubernetesDeployCommonPipelineEnvironment) error {
+	if !config.RollbackOnFailure {
+		return err
+	}
+	if rollbackErr := rollback(); rollbackErr != nil {
+		log.Entry().WithError(rollbackErr).Error("Rollback failed")
+		return errors.Wrapf(err, "rollback failed (%v)", rollbackErr)
+	}
+	commonPipelineEnvironment.custom.kubernetesDeployStatus = kubernetesDeployStatusRolledBack
+	return errors.Wrap(err, "deployment has been rolled back")
+}
+
+func verifyRollout(workloads []rolloutWorkload, kubeParams []string, timeoutSeconds int, command command.ExecRunner) error {
+	if len(workloads) == 0 {
+		log.Entry().Info("No Deployments or StatefulSets found, skipping rollout verification")
+		return nil
+	}
+	for _, workload := range workloads {
+		resource := fmt.Sprintf("%v/%v", strings.ToLower(workload.kind), workload.name)
+		log.Entry().Infof("Waiting for rollout of %v ...", resource)
+		if err := command.RunExecutable("kubectl", kubectlParams(kubeParams, "rollout", "status", resource, fmt.Sprintf("--timeout=%vs", timeoutSeconds))...); err != nil {
+			collectRolloutDiagnostics(workload, kubeParams, command)
+			return errors.Wrapf(err, "rollout of %v did not complete within %v seconds", resource, timeoutSeconds)
+		}
 	}
 	return nil
 }
 
+// collectRolloutDiagnostics writes warning events and pod logs to the log in order to ease the analysis of a failed rollout
+func collectRolloutDiagnostics(workload rolloutWorkload, kubeParams []string, command command.ExecRunner) {
+	log.Entry().Infof("Warning events of namespace:")
+	if err := command.RunExecutable("kubectl", kubectlParams(kubeParams, "get", "events", "--field-selector=type=Warning", "--sort-by=.lastTimestamp")...); err != nil {
+		log.Entry().WithError(err).Warn("Failed to retrieve events")
+	}
+	if len(workload.selector) == 0 {
+		return
+	}
+	log.Entry().Infof("Pods of %v '%v':", workload.kind, workload.name)
+	if err := command.RunExecutable("kubectl", kubectlParams(kubeParams, "get", "pods", fmt.Sprintf("--selector=%v", workload.selector), "--output=wide")...); err != nil {
+		log.Entry().WithError(err).Warn("Failed to retrieve pods")
+	}
+	log.Entry().Infof("Logs of %v '%v':", workload.kind, workload.name)
+	if err := command.RunExecutable("kubectl", kubectlParams(kubeParams, "logs", fmt.Sprintf("--selector=%v", workload.selector), "--all-containers=true", "--prefix=true", "--tail=100")...); err != nil {
+		log.Entry().WithError(err).Warn("Failed to retrieve pod logs")
+	}
+}
+
+// rolloutWorkloads returns the Deployments and StatefulSets contained in a multi document manifest
+func rolloutWorkloads(manifest []byte) ([]rolloutWorkload, error) {
+	documents, err := splitYAMLDocuments(manifest)
+	if err != nil {
+		return nil, err
+	}
+	resources := []map[string]interface{}{}
+	for _, document := range documents {
+		resource, ok := document.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if resource["kind"] == "List" {
+			items, _ := resource["items"].([]interface{})
+			for _, item := range items {
+				if itemResource, ok := item.(map[string]interface{}); ok {
+					resources = append(resources, itemResource)
+				}
+			}
+			continue
+		}
+		resources = append(resources, resource)
+	}
+
+	workloads := []rolloutWorkload{}
+	for _, resource := range resources {
+		kind, _ := resource["kind"].(string)
+		if kind != "Deployment" && kind != "StatefulSet" {
+			continue
+		}
+		metadata, _ := resource["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if len(name) == 0 {
+			continue
+		}
+		spec, _ := resource["spec"].(map[string]interface{})
+		selector, _ := spec["selector"].(map[string]interface{})
+		matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+		labels := []string{}
+		for key, value := range matchLabels {
+			labels = append(labels, fmt.Sprintf("%v=%v", key, value))
+		}
+		sort.Strings(labels)
+		workloads = append(workloads, rolloutWorkload{kind: kind, name: name, selector: strings.Join(labels, ",")})
+	}
+	return workloads, nil
+}
+
+// kubectlParams appends params to a copy of the common kubectl parameters
+func kubectlParams(kubeParams []string, params ...string) []string {
+	return append(append([]string{}, kubeParams...), params...)
+}
+
 func splitRegistryURL(registryURL string) (protocol, registry string, err error) {
 	parts := strings.Split(registryURL, "://")
 	if len(parts) != 2 || len(parts[1]) == 0 {
@@ -287,7 +578,7 @@ func defineKubeSecretParams(config kubernetesDeployOptions, containerRegistry st
 		"create",
 		"secret",
 	}
-	if config.DeployTool == "helm" || config.DeployTool == "helm3" {
+	if config.DeployTool == "helm3" {
 		kubeSecretParams = append(
 			kubeSecretParams,
 			"--insecure-skip-tls-verify=true",
@@ -5,10 +5,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
@@ -18,6 +21,7 @@ type kubernetesDeployOptions struct {
 	AdditionalParameters       []string `json:"additionalParameters,omitempty"`
 	APIServer                  string   `json:"apiServer,omitempty"`
 	AppTemplate                string   `json:"appTemplate,omitempty"`
+	KustomizationPath          string   `json:"kustomizationPath,omitempty"`
 	ChartPath                  string   `json:"chartPath,omitempty"`
 	ContainerRegistryPassword  string   `json:"containerRegistryPassword,omitempty"`
 	ContainerRegistryURL       string   `json:"containerRegistryUrl,omitempty"`
@@ -36,10 +40,41 @@ type kubernetesDeployOptions struct {
 	KubeContext                string   `json:"kubeContext,omitempty"`
 	KubeToken                  string   `json:"kubeToken,omitempty"`
 	Namespace                  string   `json:"namespace,omitempty"`
-	TillerNamespace            string   `json:"tillerNamespace,omitempty"`
+	VerifyRollout              bool     `json:"verifyRollout,omitempty"`
+	RolloutTimeoutSeconds      int      `json:"rolloutTimeoutSeconds,omitempty"`
+	RollbackOnFailure          bool     `json:"rollbackOnFailure,omitempty"`
+	RenderedManifestFile       string   `json:"renderedManifestFile,omitempty"`
 	DockerConfigJSON           string   `json:"dockerConfigJSON,omitempty"`
 }
 
+type kubernetesDeployCommonPipelineEnvironment struct {
+	custom struct {
+		kubernetesDeployStatus string
+	}
+}
+
+func (p *kubernetesDeployCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "kubernetesDeployStatus", value: p.custom.kubernetesDeployStatus},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Piper environment")
+	}
+}
+
 // KubernetesDeployCommand Deployment to Kubernetes test or production namespace within the specified Kubernetes cluster.
 func KubernetesDeployCommand() *cobra.Command {
 	const STEP_NAME = "kubernetesDeploy"
@@ -47,6 +82,7 @@ func KubernetesDeployCommand() *cobra.Command {
 	metadata := kubernetesDeployMetadata()
 	var stepConfig kubernetesDeployOptions
 	var startTime time.Time
+	var commonPipelineEnvironment kubernetesDeployCommonPipelineEnvironment
 	var logCollector *log.CollectorHook
 
 	var createKubernetesDeployCmd = &cobra.Command{
@@ -59,6 +95,7 @@ func KubernetesDeployCommand() *cobra.Command {
 
     * [Helm](https://helm.sh/) command line tool and [Helm Charts](https://docs.helm.sh/developing_charts/#charts).
     * [kubectl](https://kubernetes.io/docs/reference/kubectl/overview/) and ` + "`" + `kubectl apply` + "`" + ` command.
+    * [Kustomize](https://kustomize.io/) overlays applied via ` + "`" + `kubectl apply --kustomize` + "`" + `.
 
 ## Helm
 Following helm command will be executed by default:
@@ -69,7 +106,19 @@ helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespa
 
 * ` + "`" + `yourRegistry` + "`" + ` will be retrieved from ` + "`" + `containerRegistryUrl` + "`" + `
 * ` + "`" + `yourImageName` + "`" + `, ` + "`" + `yourImageTag` + "`" + ` will be retrieved from ` + "`" + `image` + "`" + `
-* ` + "`" + `dockerSecret` + "`" + ` will be calculated with a call to ` + "`" + `kubectl create secret docker-registry regsecret --docker-server=<yourRegistry> --docker-username=<containerRegistryUser> --docker-password=<containerRegistryPassword> --dry-run=true --output=json'` + "`" + ``,
+* ` + "`" + `dockerSecret` + "`" + ` will be calculated with a call to ` + "`" + `kubectl create secret docker-registry regsecret --docker-server=<yourRegistry> --docker-username=<containerRegistryUser> --docker-password=<containerRegistryPassword> --dry-run=true --output=json'` + "`" + `
+
+Helm 2 including Tiller is not supported anymore, please use ` + "`" + `deployTool: helm3` + "`" + `.
+
+## Kustomize
+With ` + "`" + `deployTool: kustomize` + "`" + ` the ` + "`" + `images` + "`" + ` entries of the kustomization in [` + "`" + `kustomizationPath` + "`" + `](#kustomizationpath) matching the name of ` + "`" + `image` + "`" + ` are updated with ` + "`" + `<yourRegistry>/<yourImageName>` + "`" + ` and ` + "`" + `<yourImageTag>` + "`" + `.
+In case no entry matches, a new entry is added. Afterwards the overlay is deployed with ` + "`" + `kubectl apply --kustomize <kustomizationPath>` + "`" + `.
+
+## Rollout verification
+With [` + "`" + `verifyRollout` + "`" + `](#verifyrollout) the step waits till the rollout of all Deployments and StatefulSets of the helm release, of the ` + "`" + `appTemplate` + "`" + ` or of the rendered kustomization is completed.
+In case a rollout does not complete within [` + "`" + `rolloutTimeoutSeconds` + "`" + `](#rollouttimeoutseconds), warning events of the namespace and the logs of the pods of the failed rollout are written to the log.
+With [` + "`" + `rollbackOnFailure` + "`" + `](#rollbackonfailure) the deployment is rolled back afterwards: for helm via ` + "`" + `helm rollback` + "`" + ` to the previous revision of the release, for kubectl and kustomize by applying the previously applied manifest again.
+The outcome of the deployment is written to the commonPipelineEnvironment as ` + "`" + `custom/kubernetesDeployStatus` + "`" + ` with the values ` + "`" + `success` + "`" + `, ` + "`" + `failure` + "`" + ` or ` + "`" + `rolledBack` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -107,8 +156,11 @@ helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespa
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -124,7 +176,7 @@ helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespa
 					GeneralConfig.HookConfig.SplunkConfig.Index,
 					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 			}
-			kubernetesDeploy(stepConfig, &telemetryData)
+			kubernetesDeploy(stepConfig, &telemetryData, &commonPipelineEnvironment)
 			telemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -138,13 +190,14 @@ func addKubernetesDeployFlags(cmd *cobra.Command, stepConfig *kubernetesDeployOp
 	cmd.Flags().StringSliceVar(&stepConfig.AdditionalParameters, "additionalParameters", []string{}, "Defines additional parameters for \"helm install\" or \"kubectl apply\" command.")
 	cmd.Flags().StringVar(&stepConfig.APIServer, "apiServer", os.Getenv("PIPER_apiServer"), "Defines the Url of the API Server of the Kubernetes cluster.")
 	cmd.Flags().StringVar(&stepConfig.AppTemplate, "appTemplate", os.Getenv("PIPER_appTemplate"), "Defines the filename for the kubernetes app template (e.g. k8s_apptemplate.yaml)")
-	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Defines the chart path for deployments using helm. It is a mandatory parameter when `deployTool:helm` or `deployTool:helm3`.")
+	cmd.Flags().StringVar(&stepConfig.KustomizationPath, "kustomizationPath", `.`, "Defines the directory containing the `kustomization.yaml` for deployments using kustomize.")
+	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Defines the chart path for deployments using helm. It is a mandatory parameter when `deployTool:helm3`.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryPassword, "containerRegistryPassword", os.Getenv("PIPER_containerRegistryPassword"), "Password for container registry access - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry where the image to deploy is located.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryUser, "containerRegistryUser", os.Getenv("PIPER_containerRegistryUser"), "Username for container registry access - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistrySecret, "containerRegistrySecret", `regsecret`, "Name of the container registry secret used for pulling containers from the registry.")
 	cmd.Flags().BoolVar(&stepConfig.CreateDockerRegistrySecret, "createDockerRegistrySecret", false, "Only for `deployTool:kubectl`: Toggle to turn on `containerRegistrySecret` creation.")
-	cmd.Flags().StringVar(&stepConfig.DeploymentName, "deploymentName", os.Getenv("PIPER_deploymentName"), "Defines the name of the deployment. It is a mandatory parameter when `deployTool:helm` or `deployTool:helm3`.")
+	cmd.Flags().StringVar(&stepConfig.DeploymentName, "deploymentName", os.Getenv("PIPER_deploymentName"), "Defines the name of the deployment. It is a mandatory parameter when `deployTool:helm3`.")
 	cmd.Flags().StringVar(&stepConfig.DeployTool, "deployTool", `kubectl`, "Defines the tool which should be used for deployment.")
 	cmd.Flags().BoolVar(&stepConfig.ForceUpdates, "forceUpdates", true, "Helm only: force resource updates with helm parameter `--force`")
 	cmd.Flags().IntVar(&stepConfig.HelmDeployWaitSeconds, "helmDeployWaitSeconds", 300, "Number of seconds before helm deploy returns.")
@@ -156,7 +209,10 @@ func addKubernetesDeployFlags(cmd *cobra.Command, stepConfig *kubernetesDeployOp
 	cmd.Flags().StringVar(&stepConfig.KubeContext, "kubeContext", os.Getenv("PIPER_kubeContext"), "Defines the context to use from the \"kubeconfig\" file.")
 	cmd.Flags().StringVar(&stepConfig.KubeToken, "kubeToken", os.Getenv("PIPER_kubeToken"), "Contains the id_token used by kubectl for authentication. Consider using kubeConfig parameter instead.")
 	cmd.Flags().StringVar(&stepConfig.Namespace, "namespace", `default`, "Defines the target Kubernetes namespace for the deployment.")
-	cmd.Flags().StringVar(&stepConfig.TillerNamespace, "tillerNamespace", os.Getenv("PIPER_tillerNamespace"), "Defines optional tiller namespace for deployments using helm.")
+	cmd.Flags().BoolVar(&stepConfig.VerifyRollout, "verifyRollout", false, "`kubectl`, `kustomize` and `helm3` only: waits till the rollout of all Deployments and StatefulSets of the deployment is completed.")
+	cmd.Flags().IntVar(&stepConfig.RolloutTimeoutSeconds, "rolloutTimeoutSeconds", 300, "Number of seconds to wait for the rollout of each Deployment or StatefulSet in case of `verifyRollout:true`.")
+	cmd.Flags().BoolVar(&stepConfig.RollbackOnFailure, "rollbackOnFailure", true, "Rolls the deployment back in case the rollout verification fails. For `helm3` with `verifyRollout: false` or `rollbackOnFailure: false` a failed upgrade is rolled back by helm (`--atomic`) unless `keepFailedDeployments` is set.")
+	cmd.Flags().StringVar(&stepConfig.RenderedManifestFile, "renderedManifestFile", os.Getenv("PIPER_renderedManifestFile"), "`kubectl`, `kustomize` and `helm3` only: path in the workspace where the rendered manifest is written to before it is deployed, e.g. for evaluating it with step [`policyEvaluate`](policyEvaluate.md). For `helm3` it contains the output of `helm template` which may include secrets rendered by the chart, e.g. the container registry secret.")
 	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
 
 	cmd.MarkFlagRequired("containerRegistryUrl")
@@ -211,6 +267,15 @@ func kubernetesDeployMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "k8sAppTemplate"}},
 						Default:     os.Getenv("PIPER_appTemplate"),
 					},
+					{
+						Name:        "kustomizationPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.`,
+					},
 					{
 						Name:        "chartPath",
 						ResourceRef: []config.ResourceReference{},
@@ -292,13 +357,14 @@ func kubernetesDeployMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_deploymentName"),
 					},
 					{
-						Name:        "deployTool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     `kubectl`,
+						Name:           "deployTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        `kubectl`,
+						PossibleValues: []interface{}{"kubectl", "helm3", "kustomize"},
 					},
 					{
 						Name:        "forceUpdates",
@@ -412,13 +478,40 @@ func kubernetesDeployMetadata() config.StepData {
 						Default:     `default`,
 					},
 					{
-						Name:        "tillerNamespace",
+						Name:        "verifyRollout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "rolloutTimeoutSeconds",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     300,
+					},
+					{
+						Name:        "rollbackOnFailure",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "renderedManifestFile",
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
 						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "helmTillerNamespace"}},
-						Default:     os.Getenv("PIPER_tillerNamespace"),
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_renderedManifestFile"),
 					},
 					{
 						Name: "dockerConfigJSON",
@@ -449,9 +542,20 @@ func kubernetesDeployMetadata() config.StepData {
 			},
 			Containers: []config.Container{
 				{Image: "dtzar/helm-kubectl:3.4.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "helm3"}}}}},
-				{Image: "dtzar/helm-kubectl:2.17.0", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "helm"}}}}},
+				{Image: "dtzar/helm-kubectl:3.4.1", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "kustomize"}}}}},
 				{Image: "dtzar/helm-kubectl:2.17.0", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "deployTool", Value: "kubectl"}}}}},
 			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"Name": "custom/kubernetesDeployStatus"},
+						},
+					},
+				},
+			},
 		},
 	}
 	return theMetaData
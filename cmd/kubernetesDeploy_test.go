@@ -8,79 +8,39 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunKubernetesDeploy(t *testing.T) {
 
-	t.Run("test helm", func(t *testing.T) {
+	t.Run("test helm - not supported anymore", func(t *testing.T) {
 		opts := kubernetesDeployOptions{
-			ContainerRegistryURL:      "https://my.registry:55555",
-			ContainerRegistryUser:     "registryUser",
-			ContainerRegistryPassword: "********",
-			ContainerRegistrySecret:   "testSecret",
-			ChartPath:                 "path/to/chart",
-			DeploymentName:            "deploymentName",
-			DeployTool:                "helm",
-			ForceUpdates:              true,
-			HelmDeployWaitSeconds:     400,
-			IngressHosts:              []string{"ingress.host1", "ingress.host2"},
-			Image:                     "path/to/Image:latest",
-			AdditionalParameters:      []string{"--testParam", "testValue"},
-			KubeContext:               "testCluster",
-			Namespace:                 "deploymentNamespace",
-		}
-
-		dockerConfigJSON := `{"kind": "Secret","data":{".dockerconfigjson": "ThisIsOurBase64EncodedSecret=="}}`
-
-		e := mock.ExecMockRunner{
-			StdoutReturn: map[string]string{
-				`kubectl create secret --insecure-skip-tls-verify=true --dry-run=true --output=json docker-registry testSecret --docker-server=my.registry:55555 --docker-username=registryUser --docker-password=\*\*\*\*\*\*\*\*`: dockerConfigJSON,
-			},
+			ChartPath:      "path/to/chart",
+			DeploymentName: "deploymentName",
+			DeployTool:     "helm",
 		}
+		e := mock.ExecMockRunner{}
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
 
-		var stdout bytes.Buffer
-
-		runKubernetesDeploy(opts, &e, &stdout)
-
-		assert.Equal(t, "helm", e.Calls[0].Exec, "Wrong init command")
-		assert.Equal(t, []string{"init", "--client-only"}, e.Calls[0].Params, "Wrong init parameters")
-
-		assert.Equal(t, "kubectl", e.Calls[1].Exec, "Wrong secret creation command")
-		assert.Equal(t, []string{"create", "secret", "--insecure-skip-tls-verify=true", "--dry-run=true", "--output=json", "docker-registry", "testSecret", "--docker-server=my.registry:55555", "--docker-username=registryUser", "--docker-password=********"}, e.Calls[1].Params, "Wrong secret creation parameters")
+		err := runKubernetesDeploy(opts, &e, &bytes.Buffer{}, &cpe)
 
-		assert.Equal(t, "helm", e.Calls[2].Exec, "Wrong upgrade command")
-		assert.Equal(t, []string{
-			"upgrade",
-			"deploymentName",
-			"path/to/chart",
-			"--install",
-			"--namespace",
-			"deploymentNamespace",
-			"--set",
-			"image.repository=my.registry:55555/path/to/Image,image.tag=latest,secret.name=testSecret,secret.dockerconfigjson=ThisIsOurBase64EncodedSecret==,imagePullSecrets[0].name=testSecret,ingress.hosts[0]=ingress.host1,ingress.hosts[1]=ingress.host2",
-			"--force",
-			"--wait",
-			"--timeout",
-			"400",
-			"--atomic",
-			"--kube-context",
-			"testCluster",
-			"--testParam",
-			"testValue",
-		}, e.Calls[2].Params, "Wrong upgrade parameters")
+		assert.EqualError(t, err, "deployTool 'helm' (Helm 2 with Tiller) is not supported anymore, please switch to deployTool 'helm3'")
+		assert.Equal(t, "failure", cpe.custom.kubernetesDeployStatus)
+		assert.Len(t, e.Calls, 0)
 	})
 
-	t.Run("test helm - docker config.json path passed as parameter", func(t *testing.T) {
+	t.Run("test helm v3 - docker config.json path passed as parameter", func(t *testing.T) {
 		opts := kubernetesDeployOptions{
 			ContainerRegistryURL:    "https://my.registry:55555",
 			DockerConfigJSON:        "/path/to/.docker/config.json",
 			ContainerRegistrySecret: "testSecret",
 			ChartPath:               "path/to/chart",
 			DeploymentName:          "deploymentName",
-			DeployTool:              "helm",
+			DeployTool:              "helm3",
 			ForceUpdates:            true,
 			HelmDeployWaitSeconds:   400,
 			IngressHosts:            []string{"ingress.host1", "ingress.host2"},
@@ -99,13 +59,10 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		err := runKubernetesDeploy(opts, &e, &stdout)
+		err := runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 		assert.NoError(t, err)
 
-		assert.Equal(t, "helm", e.Calls[0].Exec, "Wrong init command")
-		assert.Equal(t, []string{"init", "--client-only"}, e.Calls[0].Params, "Wrong init parameters")
-
-		assert.Equal(t, "kubectl", e.Calls[1].Exec, "Wrong secret creation command")
+		assert.Equal(t, "kubectl", e.Calls[0].Exec, "Wrong secret creation command")
 		assert.Equal(t, []string{
 			"create",
 			"secret",
@@ -116,9 +73,9 @@ func TestRunKubernetesDeploy(t *testing.T) {
 			"testSecret",
 			"--from-file=.dockerconfigjson=/path/to/.docker/config.json",
 			`--type="kubernetes.io/dockerconfigjson"`,
-		}, e.Calls[1].Params, "Wrong secret creation parameters")
+		}, e.Calls[0].Params, "Wrong secret creation parameters")
 
-		assert.Equal(t, "helm", e.Calls[2].Exec, "Wrong upgrade command")
+		assert.Equal(t, "helm", e.Calls[1].Exec, "Wrong upgrade command")
 		assert.Equal(t, []string{
 			"upgrade",
 			"deploymentName",
@@ -131,71 +88,13 @@ func TestRunKubernetesDeploy(t *testing.T) {
 			"--force",
 			"--wait",
 			"--timeout",
-			"400",
+			"400s",
 			"--atomic",
 			"--kube-context",
 			"testCluster",
 			"--testParam",
 			"testValue",
-		}, e.Calls[2].Params, "Wrong upgrade parameters")
-	})
-
-	t.Run("test helm -- keep failed deployment", func(t *testing.T) {
-		opts := kubernetesDeployOptions{
-			ContainerRegistryURL:      "https://my.registry:55555",
-			ContainerRegistryUser:     "registryUser",
-			ContainerRegistryPassword: "********",
-			ContainerRegistrySecret:   "testSecret",
-			ChartPath:                 "path/to/chart",
-			DeploymentName:            "deploymentName",
-			DeployTool:                "helm",
-			ForceUpdates:              true,
-			HelmDeployWaitSeconds:     400,
-			IngressHosts:              []string{"ingress.host1", "ingress.host2"},
-			Image:                     "path/to/Image:latest",
-			AdditionalParameters:      []string{"--testParam", "testValue"},
-			KubeContext:               "testCluster",
-			Namespace:                 "deploymentNamespace",
-			KeepFailedDeployments:     true,
-		}
-
-		k8sSecretSpec := `{"kind": "Secret","data":{".dockerconfigjson": "ThisIsOurBase64EncodedSecret=="}}`
-
-		e := mock.ExecMockRunner{
-			StdoutReturn: map[string]string{
-				`kubectl create secret --insecure-skip-tls-verify=true --dry-run=true --output=json docker-registry testSecret --docker-server=my.registry:55555 --docker-username=registryUser --docker-password=\*\*\*\*\*\*\*\*`: k8sSecretSpec,
-			},
-		}
-
-		var stdout bytes.Buffer
-
-		runKubernetesDeploy(opts, &e, &stdout)
-
-		assert.Equal(t, "helm", e.Calls[0].Exec, "Wrong init command")
-		assert.Equal(t, []string{"init", "--client-only"}, e.Calls[0].Params, "Wrong init parameters")
-
-		assert.Equal(t, "kubectl", e.Calls[1].Exec, "Wrong secret creation command")
-		assert.Equal(t, []string{"create", "secret", "--insecure-skip-tls-verify=true", "--dry-run=true", "--output=json", "docker-registry", "testSecret", "--docker-server=my.registry:55555", "--docker-username=registryUser", "--docker-password=********"}, e.Calls[1].Params, "Wrong secret creation parameters")
-
-		assert.Equal(t, "helm", e.Calls[2].Exec, "Wrong upgrade command")
-		assert.Equal(t, []string{
-			"upgrade",
-			"deploymentName",
-			"path/to/chart",
-			"--install",
-			"--namespace",
-			"deploymentNamespace",
-			"--set",
-			"image.repository=my.registry:55555/path/to/Image,image.tag=latest,secret.name=testSecret,secret.dockerconfigjson=ThisIsOurBase64EncodedSecret==,imagePullSecrets[0].name=testSecret,ingress.hosts[0]=ingress.host1,ingress.hosts[1]=ingress.host2",
-			"--force",
-			"--wait",
-			"--timeout",
-			"400",
-			"--kube-context",
-			"testCluster",
-			"--testParam",
-			"testValue",
-		}, e.Calls[2].Params, "Wrong upgrade parameters")
+		}, e.Calls[1].Params, "Wrong upgrade parameters")
 	})
 
 	t.Run("test helm v3", func(t *testing.T) {
@@ -226,7 +125,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, "kubectl", e.Calls[0].Exec, "Wrong secret creation command")
 		assert.Equal(t, []string{"create", "secret", "--insecure-skip-tls-verify=true", "--dry-run=true", "--output=json", "docker-registry", "testSecret", "--docker-server=my.registry:55555", "--docker-username=registryUser", "--docker-password=********"}, e.Calls[0].Params, "Wrong secret creation parameters")
@@ -286,7 +185,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, "kubectl", e.Calls[0].Exec, "Wrong secret creation command")
 		assert.Equal(t, []string{"create", "secret", "--insecure-skip-tls-verify=true", "--dry-run=true", "--output=json", "docker-registry", "testSecret", "--docker-server=my.registry:55555", "--docker-username=registryUser", "--docker-password=********"}, e.Calls[0].Params, "Wrong secret creation parameters")
@@ -335,7 +234,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, 1, len(e.Calls), "Wrong number of upgrade commands")
 		assert.Equal(t, "helm", e.Calls[0].Exec, "Wrong upgrade command")
@@ -378,7 +277,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		err := runKubernetesDeploy(opts, &e, &stdout)
+		err := runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "chart path has not been set, please configure chartPath parameter")
 	})
 
@@ -400,7 +299,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		err := runKubernetesDeploy(opts, &e, &stdout)
+		err := runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 		assert.EqualError(t, err, "deployment name has not been set, please configure deploymentName parameter")
 	})
 
@@ -422,7 +321,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		var stdout bytes.Buffer
 
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 		assert.Equal(t, []string{
 			"upgrade",
 			"deploymentName",
@@ -477,7 +376,7 @@ spec:
 			},
 		}
 		var stdout bytes.Buffer
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, e.Env, []string{"KUBECONFIG=This is my kubeconfig"})
 
@@ -556,7 +455,7 @@ spec:
 			},
 		}
 		var stdout bytes.Buffer
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, e.Env, []string{"KUBECONFIG=This is my kubeconfig"})
 
@@ -596,7 +495,7 @@ spec:
 		e := mock.ExecMockRunner{}
 
 		var stdout bytes.Buffer
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, "kubectl", e.Calls[0].Exec, "Wrong secret lookup command")
 		assert.Equal(t, []string{
@@ -641,7 +540,7 @@ spec:
 			ShouldFailOnCommand: map[string]error{},
 		}
 		var stdout bytes.Buffer
-		runKubernetesDeploy(opts, &e, &stdout)
+		runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
 
 		assert.Equal(t, "kubectl", e.Calls[0].Exec, "Wrong apply command")
 		assert.Equal(t, []string{
@@ -656,6 +555,538 @@ spec:
 	})
 }
 
+func TestRunKubernetesDeployRolloutVerification(t *testing.T) {
+	helmManifest := `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: app
+      app.kubernetes.io/instance: release
+`
+	helmOpts := kubernetesDeployOptions{
+		ContainerRegistryURL:  "https://my.registry:55555",
+		ChartPath:             "path/to/chart",
+		DeploymentName:        "deploymentName",
+		DeployTool:            "helm3",
+		HelmDeployWaitSeconds: 400,
+		Image:                 "path/to/Image:latest",
+		KubeContext:           "testCluster",
+		Namespace:             "deploymentNamespace",
+		VerifyRollout:         true,
+		RolloutTimeoutSeconds: 120,
+		RollbackOnFailure:     true,
+	}
+	rolloutStatusCall := "kubectl --namespace=deploymentNamespace --context=testCluster rollout status deployment/app --timeout=120s"
+
+	t.Run("test helm v3 - successful rollout", func(t *testing.T) {
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm get manifest deploymentName": helmManifest,
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(helmOpts, &e, &stdout, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "success", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 3)
+		assert.Equal(t, []string{
+			"upgrade",
+			"deploymentName",
+			"path/to/chart",
+			"--install",
+			"--namespace",
+			"deploymentNamespace",
+			"--set",
+			"image.repository=my.registry:55555/path/to/Image,image.tag=latest",
+			"--timeout",
+			"400s",
+			"--kube-context",
+			"testCluster",
+		}, e.Calls[0].Params, "Wrong upgrade parameters")
+		assert.Equal(t, mock.ExecCall{Exec: "helm", Params: []string{"get", "manifest", "deploymentName", "--namespace", "deploymentNamespace", "--kube-context", "testCluster"}}, e.Calls[1])
+		assert.Equal(t, mock.ExecCall{Exec: "kubectl", Params: []string{"--namespace=deploymentNamespace", "--context=testCluster", "rollout", "status", "deployment/app", "--timeout=120s"}}, e.Calls[2])
+		assert.NotContains(t, stdout.String(), "Source: app/templates")
+	})
+
+	t.Run("test helm v3 - failed rollout is rolled back", func(t *testing.T) {
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm get manifest deploymentName": helmManifest,
+			},
+			ShouldFailOnCommand: map[string]error{
+				rolloutStatusCall: fmt.Errorf("timed out waiting for the condition"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(helmOpts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "deployment has been rolled back: rollout of deployment/app did not complete within 120 seconds: timed out waiting for the condition")
+		assert.Equal(t, "rolledBack", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 7)
+		assert.Equal(t, []string{"--namespace=deploymentNamespace", "--context=testCluster", "get", "events", "--field-selector=type=Warning", "--sort-by=.lastTimestamp"}, e.Calls[3].Params)
+		assert.Equal(t, []string{"--namespace=deploymentNamespace", "--context=testCluster", "get", "pods", "--selector=app.kubernetes.io/instance=release,app.kubernetes.io/name=app", "--output=wide"}, e.Calls[4].Params)
+		assert.Equal(t, []string{"--namespace=deploymentNamespace", "--context=testCluster", "logs", "--selector=app.kubernetes.io/instance=release,app.kubernetes.io/name=app", "--all-containers=true", "--prefix=true", "--tail=100"}, e.Calls[5].Params)
+		assert.Equal(t, mock.ExecCall{Exec: "helm", Params: []string{"rollback", "deploymentName", "--namespace", "deploymentNamespace", "--kube-context", "testCluster", "--wait", "--timeout", "400s"}}, e.Calls[6])
+	})
+
+	t.Run("test helm v3 - failed rollout without rollback", func(t *testing.T) {
+		opts := helmOpts
+		opts.RollbackOnFailure = false
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm get manifest deploymentName": helmManifest,
+			},
+			ShouldFailOnCommand: map[string]error{
+				rolloutStatusCall: fmt.Errorf("timed out waiting for the condition"),
+				"kubectl .* logs": fmt.Errorf("no pods found"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "rollout of deployment/app did not complete within 120 seconds: timed out waiting for the condition")
+		assert.Equal(t, "failure", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 6)
+		assert.Equal(t, "logs", e.Calls[5].Params[2])
+	})
+
+	t.Run("test helm v3 - failed rollback", func(t *testing.T) {
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm get manifest deploymentName": helmManifest,
+			},
+			ShouldFailOnCommand: map[string]error{
+				rolloutStatusCall: fmt.Errorf("timed out waiting for the condition"),
+				"helm rollback":   fmt.Errorf("release has no 0 version"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(helmOpts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "rollback failed (release has no 0 version): rollout of deployment/app did not complete within 120 seconds: timed out waiting for the condition")
+		assert.Equal(t, "failure", cpe.custom.kubernetesDeployStatus)
+	})
+
+	t.Run("test helm v3 - failed upgrade is rolled back", func(t *testing.T) {
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{
+				"helm upgrade": fmt.Errorf("upgrade failed"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(helmOpts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "deployment has been rolled back: Helm upgrade call failed: upgrade failed")
+		assert.Equal(t, "rolledBack", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 2)
+		assert.Equal(t, mock.ExecCall{Exec: "helm", Params: []string{"rollback", "deploymentName", "--namespace", "deploymentNamespace", "--kube-context", "testCluster", "--wait", "--timeout", "400s"}}, e.Calls[1])
+	})
+
+	t.Run("test helm v3 - failed upgrade without rollback", func(t *testing.T) {
+		opts := helmOpts
+		opts.RollbackOnFailure = false
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{
+				"helm upgrade": fmt.Errorf("upgrade failed"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "Helm upgrade call failed: upgrade failed")
+		assert.Equal(t, "failure", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 1)
+		// without rollback by the step helm takes care of it
+		assert.Contains(t, e.Calls[0].Params, "--atomic")
+	})
+
+	t.Run("test helm v3 - failed upgrade without rollback keeps failed deployment", func(t *testing.T) {
+		opts := helmOpts
+		opts.RollbackOnFailure = false
+		opts.KeepFailedDeployments = true
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{
+				"helm upgrade": fmt.Errorf("upgrade failed"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "Helm upgrade call failed: upgrade failed")
+		require.Len(t, e.Calls, 1)
+		assert.NotContains(t, e.Calls[0].Params, "--atomic")
+	})
+
+	kubeYaml := `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  selector:
+    matchLabels:
+      app: db
+  template:
+    spec:
+      containers:
+      - image: <image-name>
+`
+	previousYaml := `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+`
+	newKubectlOpts := func(t *testing.T) kubernetesDeployOptions {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		opts := kubernetesDeployOptions{
+			AppTemplate:           filepath.Join(dir, "test.yaml"),
+			ContainerRegistryURL:  "https://my.registry:55555",
+			DeployTool:            "kubectl",
+			Image:                 "path/to/Image:latest",
+			KubeConfig:            "This is my kubeconfig",
+			Namespace:             "deploymentNamespace",
+			VerifyRollout:         true,
+			RolloutTimeoutSeconds: 60,
+			RollbackOnFailure:     true,
+		}
+		require.NoError(t, ioutil.WriteFile(opts.AppTemplate, []byte(kubeYaml), 0755))
+		return opts
+	}
+
+	t.Run("test kubectl - successful rollout", func(t *testing.T) {
+		opts := newKubectlOpts(t)
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"kubectl .* apply view-last-applied": previousYaml,
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "success", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 3)
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "view-last-applied", "--filename", opts.AppTemplate, "--output", "yaml"}, e.Calls[0].Params)
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "--filename", opts.AppTemplate}, e.Calls[1].Params)
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "rollout", "status", "statefulset/db", "--timeout=60s"}, e.Calls[2].Params)
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("test kubectl - failed rollout re-applies previous manifest", func(t *testing.T) {
+		defer log.SetErrorCategory(log.ErrorUndefined)
+		opts := newKubectlOpts(t)
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"kubectl .* apply view-last-applied": previousYaml,
+			},
+			ShouldFailOnCommand: map[string]error{
+				"kubectl .* rollout status": fmt.Errorf("timed out waiting for the condition"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "deployment has been rolled back: rollout of statefulset/db did not complete within 60 seconds: timed out waiting for the condition")
+		assert.Equal(t, "rolledBack", cpe.custom.kubernetesDeployStatus)
+		require.Len(t, e.Calls, 7)
+		require.Len(t, e.Calls[6].Params, 5)
+		assert.Equal(t, []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "apply", "--filename"}, e.Calls[6].Params[:4])
+		// the previous manifest is written to a temporary directory outside of the workspace which is removed afterwards
+		previousManifestFile := e.Calls[6].Params[4]
+		assert.Equal(t, "previous-manifest.yaml", filepath.Base(previousManifestFile))
+		assert.NotEqual(t, filepath.Dir(opts.AppTemplate), filepath.Dir(previousManifestFile))
+		assert.NoFileExists(t, previousManifestFile)
+		files, err := ioutil.ReadDir(filepath.Dir(opts.AppTemplate))
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+		assert.Equal(t, log.ErrorInfrastructure, log.GetErrorCategory())
+	})
+
+	t.Run("test kubectl - failed rollout of first deployment", func(t *testing.T) {
+		opts := newKubectlOpts(t)
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{
+				"kubectl .* apply view-last-applied": fmt.Errorf("not found"),
+				"kubectl .* rollout status":          fmt.Errorf("timed out waiting for the condition"),
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, "rollback failed (no previously applied manifest available): rollout of statefulset/db did not complete within 60 seconds: timed out waiting for the condition")
+		assert.Equal(t, "failure", cpe.custom.kubernetesDeployStatus)
+		assert.Len(t, e.Calls, 6)
+	})
+}
+
+func TestRunKubernetesDeployWithKustomize(t *testing.T) {
+	newOpts := func(t *testing.T) kubernetesDeployOptions {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte("resources:\n- ../base\nimages:\n- name: path/to/Image\n  newTag: \"1.0\"\n"), 0755))
+		return kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:1.1",
+			KubeConfig:           "This is my kubeconfig",
+			KubeContext:          "testCluster",
+			KustomizationPath:    dir,
+			Namespace:            "deploymentNamespace",
+		}
+	}
+
+	t.Run("test kustomize", func(t *testing.T) {
+		opts := newOpts(t)
+		e := mock.ExecMockRunner{}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "success", cpe.custom.kubernetesDeployStatus)
+		assert.Equal(t, []string{"KUBECONFIG=This is my kubeconfig"}, e.Env)
+		require.Len(t, e.Calls, 1)
+		assert.Equal(t, mock.ExecCall{Exec: "kubectl", Params: []string{
+			"--insecure-skip-tls-verify=true",
+			"--namespace=deploymentNamespace",
+			"--context=testCluster",
+			"apply",
+			"--kustomize",
+			opts.KustomizationPath,
+		}}, e.Calls[0])
+		kustomization, err := ioutil.ReadFile(filepath.Join(opts.KustomizationPath, "kustomization.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "resources:\n  - ../base\nimages:\n  - name: path/to/Image\n    newTag: \"1.1\"\n    newName: my.registry:55555/path/to/Image\n", string(kustomization))
+	})
+
+	t.Run("test kustomize - rollout verification", func(t *testing.T) {
+		opts := newOpts(t)
+		opts.VerifyRollout = true
+		opts.RolloutTimeoutSeconds = 60
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"kubectl kustomize": "kind: Deployment\nmetadata:\n  name: app\n",
+			},
+		}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.NoError(t, err)
+		require.Len(t, e.Calls, 3)
+		assert.Equal(t, mock.ExecCall{Exec: "kubectl", Params: []string{"kustomize", opts.KustomizationPath}}, e.Calls[0])
+		assert.Equal(t, []string{"apply", "--kustomize", opts.KustomizationPath}, e.Calls[1].Params[3:])
+		assert.Equal(t, []string{"rollout", "status", "deployment/app", "--timeout=60s"}, e.Calls[2].Params[3:])
+	})
+
+	t.Run("test kustomize - missing kustomization", func(t *testing.T) {
+		opts := newOpts(t)
+		opts.KustomizationPath = filepath.Join(opts.KustomizationPath, "overlay")
+		e := mock.ExecMockRunner{}
+		var stdout bytes.Buffer
+		cpe := kubernetesDeployCommonPipelineEnvironment{}
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &cpe)
+
+		assert.EqualError(t, err, fmt.Sprintf("no kustomization found in '%v'", opts.KustomizationPath))
+		assert.Equal(t, "failure", cpe.custom.kubernetesDeployStatus)
+		assert.Len(t, e.Calls, 0)
+	})
+}
+
+func TestRunKubernetesDeployRenderedManifest(t *testing.T) {
+	newDir := func(t *testing.T) string {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		return dir
+	}
+
+	t.Run("test helm v3", func(t *testing.T) {
+		dir := newDir(t)
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL:  "https://my.registry:55555",
+			ChartPath:             "path/to/chart",
+			DeploymentName:        "deploymentName",
+			DeployTool:            "helm3",
+			HelmDeployWaitSeconds: 400,
+			HelmValues:            []string{"values.yaml"},
+			Image:                 "path/to/Image:latest",
+			Namespace:             "deploymentNamespace",
+			RenderedManifestFile:  filepath.Join(dir, "rendered", "manifest.yaml"),
+		}
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"helm template": "kind: Deployment\n",
+			},
+		}
+		var stdout bytes.Buffer
+
+		err := runKubernetesDeploy(opts, &e, &stdout, &kubernetesDeployCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		require.Len(t, e.Calls, 2)
+		assert.Equal(t, mock.ExecCall{Exec: "helm", Params: []string{
+			"template",
+			"deploymentName",
+			"path/to/chart",
+			"--values",
+			"values.yaml",
+			"--namespace",
+			"deploymentNamespace",
+			"--set",
+			"image.repository=my.registry:55555/path/to/Image,image.tag=latest",
+		}}, e.Calls[0])
+		assert.Equal(t, "upgrade", e.Calls[1].Params[0])
+		rendered, err := ioutil.ReadFile(opts.RenderedManifestFile)
+		require.NoError(t, err)
+		assert.Equal(t, "kind: Deployment\n", string(rendered))
+		assert.NotContains(t, stdout.String(), "kind: Deployment")
+	})
+
+	t.Run("test helm v3 - failed rendering", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			ChartPath:            "path/to/chart",
+			DeploymentName:       "deploymentName",
+			DeployTool:           "helm3",
+			Image:                "path/to/Image:latest",
+			RenderedManifestFile: filepath.Join(newDir(t), "manifest.yaml"),
+		}
+		e := mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{
+				"helm template": fmt.Errorf("template error"),
+			},
+		}
+
+		err := runKubernetesDeploy(opts, &e, &bytes.Buffer{}, &kubernetesDeployCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to render chart 'path/to/chart': template error")
+		assert.Len(t, e.Calls, 1)
+	})
+
+	t.Run("test kubectl", func(t *testing.T) {
+		dir := newDir(t)
+		opts := kubernetesDeployOptions{
+			AppTemplate:          filepath.Join(dir, "test.yaml"),
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kubectl",
+			Image:                "path/to/Image:latest",
+			KubeConfig:           "This is my kubeconfig",
+			Namespace:            "deploymentNamespace",
+			RenderedManifestFile: filepath.Join(dir, "rendered.yaml"),
+		}
+		require.NoError(t, ioutil.WriteFile(opts.AppTemplate, []byte("containers:\n- image: <image-name>\n"), 0644))
+		e := mock.ExecMockRunner{}
+
+		err := runKubernetesDeploy(opts, &e, &bytes.Buffer{}, &kubernetesDeployCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		rendered, err := ioutil.ReadFile(opts.RenderedManifestFile)
+		require.NoError(t, err)
+		assert.Equal(t, "containers:\n- image: my.registry:55555/path/to/Image:latest\n", string(rendered))
+	})
+
+	t.Run("test kustomize", func(t *testing.T) {
+		dir := newDir(t)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte("resources:\n- ../base\n"), 0644))
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "kustomize",
+			Image:                "path/to/Image:1.1",
+			KubeConfig:           "This is my kubeconfig",
+			KustomizationPath:    dir,
+			Namespace:            "deploymentNamespace",
+			RenderedManifestFile: filepath.Join(dir, "rendered.yaml"),
+		}
+		e := mock.ExecMockRunner{
+			StdoutReturn: map[string]string{
+				"kubectl kustomize": "kind: Deployment\n",
+			},
+		}
+
+		err := runKubernetesDeploy(opts, &e, &bytes.Buffer{}, &kubernetesDeployCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		require.Len(t, e.Calls, 2)
+		assert.Equal(t, mock.ExecCall{Exec: "kubectl", Params: []string{"kustomize", opts.KustomizationPath}}, e.Calls[0])
+		assert.Equal(t, []string{"apply", "--kustomize", opts.KustomizationPath}, e.Calls[1].Params[2:])
+		rendered, err := ioutil.ReadFile(opts.RenderedManifestFile)
+		require.NoError(t, err)
+		assert.Equal(t, "kind: Deployment\n", string(rendered))
+	})
+}
+
+func TestRolloutWorkloads(t *testing.T) {
+	t.Run("manifest", func(t *testing.T) {
+		workloads, err := rolloutWorkloads([]byte(`kind: Deployment
+metadata:
+  name: app
+---
+kind: ConfigMap
+metadata:
+  name: config
+---
+kind: List
+items:
+- kind: StatefulSet
+  metadata:
+    name: db
+  spec:
+    selector:
+      matchLabels:
+        tier: db
+        app: shop
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []rolloutWorkload{
+			{kind: "Deployment", name: "app"},
+			{kind: "StatefulSet", name: "db", selector: "app=shop,tier=db"},
+		}, workloads)
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		_, err := rolloutWorkloads([]byte("kind: [Deployment"))
+		assert.Error(t, err)
+	})
+}
+
 func TestSplitRegistryURL(t *testing.T) {
 	tt := []struct {
 		in          string
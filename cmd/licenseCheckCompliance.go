@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/license"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	ws "github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/pkg/errors"
+)
+
+const licenseCheckComplianceReport = "licenseCheckCompliance.md"
+
+type licenseCheckComplianceUtils interface {
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	DirExists(path string) (bool, error)
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// licenseWhitesource is the part of the WhiteSource API providing the licenses of a product
+type licenseWhitesource interface {
+	GetProductByName(productName string) (ws.Product, error)
+	GetProjectsMetaInfo(productToken string) ([]ws.Project, error)
+	GetProjectToken(productToken, projectName string) (string, error)
+	GetProjectLicenses(projectToken string) ([]ws.Library, error)
+}
+
+func licenseCheckCompliance(config licenseCheckComplianceOptions, telemetryData *telemetry.CustomData) {
+	utils := &piperutils.Files{}
+	sys := ws.NewSystem(config.ServiceURL, config.OrgToken, config.UserToken, time.Duration(config.Timeout)*time.Second)
+
+	err := runLicenseCheckCompliance(&config, utils, sys)
+	// the report is also archived in case of denied licenses
+	reports := []piperutils.Path{}
+	if exists, _ := piperutils.FileExists(licenseCheckComplianceReport); exists {
+		reports = append(reports, piperutils.Path{Name: "License Compliance", Target: licenseCheckComplianceReport})
+	}
+	if exists, _ := piperutils.FileExists(config.AttributionFile); exists {
+		reports = append(reports, piperutils.Path{Name: "Attribution File", Target: config.AttributionFile})
+	}
+	piperutils.PersistReportsAndLinks("licenseCheckCompliance", "", reports, nil)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runLicenseCheckCompliance(config *licenseCheckComplianceOptions, utils licenseCheckComplianceUtils, sys licenseWhitesource) error {
+	policy, err := readLicensePolicy(config.LicensePolicyFile, utils)
+	if err != nil {
+		return err
+	}
+
+	var components []license.Component
+	switch config.ScanTool {
+	case "whitesource":
+		components, err = whitesourceLicenseComponents(config, sys)
+	case "protecode":
+		components, err = protecodeLicenseComponents(config, utils)
+	default:
+		log.SetErrorCategory(log.ErrorConfiguration)
+		err = fmt.Errorf("scan tool '%v' not supported", config.ScanTool)
+	}
+	if err != nil {
+		return err
+	}
+
+	results := policy.Evaluate(components)
+	denied, review := license.Count(results, license.Deny), license.Count(results, license.Review)
+	log.Entry().Infof("Evaluated the licenses of %v components: %v denied, %v requiring a review", len(results), denied, review)
+
+	if err := utils.FileWrite(config.AttributionFile, license.Notice(config.ProductName, results), 0666); err != nil {
+		return errors.Wrapf(err, "failed to write attribution file '%v'", config.AttributionFile)
+	}
+	log.Entry().Infof("Attribution file written to '%v'", config.AttributionFile)
+
+	if err := writeLicenseReport(licenseReport(config, results), utils); err != nil {
+		return err
+	}
+
+	if denied > 0 && config.FailOnDeniedLicenses {
+		names := []string{}
+		for _, result := range results {
+			if result.Decision == license.Deny {
+				licenses := strings.Join(result.Licenses, " AND ")
+				if len(licenses) == 0 {
+					licenses = "unknown license"
+				}
+				names = append(names, fmt.Sprintf("%v %v (%v)", result.Component.Name, result.Component.Version, licenses))
+			}
+		}
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v component(s) with denied licenses: %v", denied, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func readLicensePolicy(path string, utils licenseCheckComplianceUtils) (*license.Policy, error) {
+	if exists, _ := utils.FileExists(path); !exists {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("license policy '%v' not found", path)
+	}
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read license policy '%v'", path)
+	}
+	policy, err := license.ReadPolicy(content)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "invalid license policy '%v'", path)
+	}
+	return policy, nil
+}
+
+func whitesourceLicenseComponents(config *licenseCheckComplianceOptions, sys licenseWhitesource) ([]license.Component, error) {
+	if len(config.OrgToken) == 0 || len(config.UserToken) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("the parameters orgToken and userToken are required for scan tool 'whitesource'")
+	}
+	productToken := config.ProductToken
+	if len(productToken) == 0 {
+		if len(config.ProductName) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("one of the parameters productName and productToken is required for scan tool 'whitesource'")
+		}
+		product, err := sys.GetProductByName(config.ProductName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve WhiteSource product '%v'", config.ProductName)
+		}
+		productToken = product.Token
+	}
+
+	projectTokens := []string{}
+	if len(config.ProjectNames) > 0 {
+		for _, name := range config.ProjectNames {
+			token, err := sys.GetProjectToken(productToken, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to retrieve WhiteSource project '%v'", name)
+			}
+			if len(token) == 0 {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, fmt.Errorf("WhiteSource project '%v' not found", name)
+			}
+			projectTokens = append(projectTokens, token)
+		}
+	} else {
+		projects, err := sys.GetProjectsMetaInfo(productToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve WhiteSource projects")
+		}
+		for _, project := range projects {
+			projectTokens = append(projectTokens, project.Token)
+		}
+	}
+
+	components := []license.Component{}
+	for _, token := range projectTokens {
+		libraries, err := sys.GetProjectLicenses(token)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve licenses from WhiteSource")
+		}
+		for _, library := range libraries {
+			component := license.Component{Name: library.Name, Version: library.Version}
+			if len(component.Name) == 0 {
+				component.Name = library.ArtifactID
+			}
+			for _, l := range library.Licenses {
+				name := l.SPDXName
+				if len(name) == 0 {
+					name = l.Name
+				}
+				component.Licenses = append(component.Licenses, name)
+				if len(l.URL) > 0 {
+					component.LicenseURLs = append(component.LicenseURLs, l.URL)
+				}
+			}
+			for _, reference := range library.CopyrightReferences {
+				component.Copyrights = append(component.Copyrights, reference.Copyright)
+			}
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
+
+// protecodeLicenseComponents reads the components from the Protecode result, it contains no copyright statements
+func protecodeLicenseComponents(config *licenseCheckComplianceOptions, utils licenseCheckComplianceUtils) ([]license.Component, error) {
+	content, err := utils.FileRead(config.ProtecodeResultFile)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to read Protecode result '%v', please run step protecodeExecuteScan before", config.ProtecodeResultFile)
+	}
+	result := protecode.ResultData{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Protecode result '%v'", config.ProtecodeResultFile)
+	}
+	components := []license.Component{}
+	for _, c := range result.Result.Components {
+		component := license.Component{Name: c.Lib, Version: c.Version}
+		if c.License != nil && len(c.License.Name) > 0 {
+			component.Licenses = []string{c.License.Name}
+			if len(c.License.URL) > 0 {
+				component.LicenseURLs = []string{c.License.URL}
+			}
+		}
+		components = append(components, component)
+	}
+	log.Entry().Info("The Protecode result contains no copyright statements, the attribution file only lists the licenses of the components")
+	return components, nil
+}
+
+// licenseReport lists denied components first, followed by the components requiring a review
+func licenseReport(config *licenseCheckComplianceOptions, results []license.Result) reporting.ScanReport {
+	denied, review := license.Count(results, license.Deny), license.Count(results, license.Review)
+	report := reporting.ScanReport{
+		StepName: "licenseCheckCompliance",
+		Title:    "License Compliance",
+		Subheaders: []reporting.Subheader{
+			{Description: "Scan tool", Details: config.ScanTool},
+			{Description: "License policy", Details: config.LicensePolicyFile},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Components", Details: fmt.Sprint(len(results))},
+			{Description: "Denied licenses", Details: fmt.Sprint(denied)},
+			{Description: "Licenses requiring a review", Details: fmt.Sprint(review)},
+		},
+		SuccessfulScan: denied == 0,
+		DetailTable: reporting.ScanDetailTable{
+			Headers:       []string{"Component", "Version", "Declared licenses", "Decision", "Decisive licenses", "Justification"},
+			NoRowsMessage: "No components found",
+			WithCounter:   true,
+			CounterHeader: "Entry #",
+		},
+	}
+	if denied > 0 {
+		report.Overview[1].Style = reporting.Red
+	}
+	if review > 0 {
+		report.Overview[2].Style = reporting.Yellow
+	}
+
+	order := map[license.Decision]int{license.Deny: 0, license.Review: 1, license.Allow: 2}
+	sorted := append([]license.Result{}, results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return order[sorted[i].Decision] < order[sorted[j].Decision]
+	})
+	for _, result := range sorted {
+		style := reporting.ColumnStyle(0)
+		switch result.Decision {
+		case license.Deny:
+			style = reporting.Red
+		case license.Review:
+			style = reporting.Yellow
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(result.Component.Name, 0)
+		row.AddColumn(result.Component.Version, 0)
+		row.AddColumn(strings.Join(result.Component.Licenses, ", "), 0)
+		row.AddColumn(string(result.Decision), style)
+		row.AddColumn(strings.Join(result.Licenses, " AND "), 0)
+		row.AddColumn(result.Justification, 0)
+		report.DetailTable.Rows = append(report.DetailTable.Rows, row)
+	}
+	return report
+}
+
+func writeLicenseReport(report reporting.ScanReport, utils licenseCheckComplianceUtils) error {
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	mdReport, _ := report.ToMarkdown()
+	if err := utils.FileWrite(licenseCheckComplianceReport, mdReport, 0666); err != nil {
+		log.Entry().WithError(err).Warn("failed to write license report")
+	}
+
+	// JSON reports are used by step pipelineCreateScanSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := report.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return errors.Wrap(err, "failed to create reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "licenseCheckCompliance.json"), jsonReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	return nil
+}
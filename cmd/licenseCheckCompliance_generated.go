@@ -0,0 +1,306 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type licenseCheckComplianceOptions struct {
+	ScanTool             string   `json:"scanTool,omitempty"`
+	LicensePolicyFile    string   `json:"licensePolicyFile,omitempty"`
+	FailOnDeniedLicenses bool     `json:"failOnDeniedLicenses,omitempty"`
+	AttributionFile      string   `json:"attributionFile,omitempty"`
+	ProtecodeResultFile  string   `json:"protecodeResultFile,omitempty"`
+	ServiceURL           string   `json:"serviceUrl,omitempty"`
+	OrgToken             string   `json:"orgToken,omitempty"`
+	UserToken            string   `json:"userToken,omitempty"`
+	ProductName          string   `json:"productName,omitempty"`
+	ProductToken         string   `json:"productToken,omitempty"`
+	ProjectNames         []string `json:"projectNames,omitempty"`
+	Timeout              int      `json:"timeout,omitempty"`
+}
+
+// LicenseCheckComplianceCommand Evaluates the licenses of the product's components against a license policy and creates the attribution file
+func LicenseCheckComplianceCommand() *cobra.Command {
+	const STEP_NAME = "licenseCheckCompliance"
+
+	metadata := licenseCheckComplianceMetadata()
+	var stepConfig licenseCheckComplianceOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+
+	var createLicenseCheckComplianceCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Evaluates the licenses of the product's components against a license policy and creates the attribution file",
+		Long: `This step checks the licenses of the open source components of your product against a license policy and creates the attribution file (NOTICE) which is shipped with the product.
+
+The components and their licenses are taken from the results of a WhiteSource scan (step [` + "`" + `whitesourceExecuteScan` + "`" + `](whitesourceExecuteScan.md)) or a Protecode scan (step [` + "`" + `protecodeExecuteScan` + "`" + `](protecodeExecuteScan.md)), see parameter ` + "`" + `scanTool` + "`" + `.
+For WhiteSource the licenses and copyrights of all projects of the product are retrieved via the WhiteSource API, for Protecode the scan result written by step protecodeExecuteScan is read.
+Since the Protecode scan result does not contain copyright statements, the attribution file only lists the licenses of the components in this case.
+License names like ` + "`" + `Apache License, Version 2.0` + "`" + ` or ` + "`" + `GNU GPL v2 or later` + "`" + ` are mapped to their SPDX identifiers before they are evaluated against the policy.
+
+The license policy is a YAML file in the repository, by default ` + "`" + `.pipeline/licensePolicy.yml` + "`" + `:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+allow:
+  - Apache-2.0
+  - MIT
+  - BSD-*
+  - GPL-2.0-only WITH Classpath-exception-2.0
+review:
+  - LGPL-*
+  - MPL-2.0
+deny:
+  - GPL-*
+  - AGPL-*
+# applies to licenses not listed above and to components without license information
+default: review
+# components which are allowed regardless of their license, the version is optional
+exceptions:
+  - component: mysql-connector-java
+    version: 8.0.28
+    justification: approved by legal for internal tooling
+` + "`" + `` + "`" + `` + "`" + `
+
+Licenses are listed as [SPDX identifiers](https://spdx.org/licenses/) or patterns, entries without wildcard take precedence over patterns.
+License expressions are evaluated according to the SPDX expression syntax: for dual licenses like ` + "`" + `MIT OR GPL-3.0-only` + "`" + ` the most permissive alternative decides, licenses combined with ` + "`" + `AND` + "`" + ` all apply.
+Multiple licenses which a scan tool reports for one component are considered to all apply.
+
+The decisions are written into a report which is picked up by step [` + "`" + `pipelineCreateScanSummary` + "`" + `](pipelineCreateScanSummary.md).
+Licenses requiring a review are reported as warnings, the step fails in case of denied licenses unless ` + "`" + `failOnDeniedLicenses` + "`" + ` is set to ` + "`" + `false` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.OrgToken)
+			log.RegisterSecret(stepConfig.UserToken)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
+				telemetry.Send(&telemetryData)
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunk.Send(&telemetryData, logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunk.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			licenseCheckCompliance(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addLicenseCheckComplianceFlags(createLicenseCheckComplianceCmd, &stepConfig)
+	return createLicenseCheckComplianceCmd
+}
+
+func addLicenseCheckComplianceFlags(cmd *cobra.Command, stepConfig *licenseCheckComplianceOptions) {
+	cmd.Flags().StringVar(&stepConfig.ScanTool, "scanTool", os.Getenv("PIPER_scanTool"), "Scan tool providing the components and their licenses.")
+	cmd.Flags().StringVar(&stepConfig.LicensePolicyFile, "licensePolicyFile", `.pipeline/licensePolicy.yml`, "Path of the license policy.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnDeniedLicenses, "failOnDeniedLicenses", true, "Whether to fail the step in case of components with denied licenses.")
+	cmd.Flags().StringVar(&stepConfig.AttributionFile, "attributionFile", `NOTICE`, "Path of the attribution file listing the components with their licenses and copyrights.")
+	cmd.Flags().StringVar(&stepConfig.ProtecodeResultFile, "protecodeResultFile", `protecodescan_vulns.json`, "For `scanTool: protecode`: Path of the scan result written by step protecodeExecuteScan.")
+	cmd.Flags().StringVar(&stepConfig.ServiceURL, "serviceUrl", `https://saas.whitesourcesoftware.com/api`, "For `scanTool: whitesource`: URL to the WhiteSource API endpoint.")
+	cmd.Flags().StringVar(&stepConfig.OrgToken, "orgToken", os.Getenv("PIPER_orgToken"), "For `scanTool: whitesource`: WhiteSource token identifying your organization.")
+	cmd.Flags().StringVar(&stepConfig.UserToken, "userToken", os.Getenv("PIPER_userToken"), "For `scanTool: whitesource`: User token to access WhiteSource. In Jenkins use case this is automatically filled through the credentials.")
+	cmd.Flags().StringVar(&stepConfig.ProductName, "productName", os.Getenv("PIPER_productName"), "Name of the product, it is used as title of the attribution file. For `scanTool` `whitesource` it identifies the WhiteSource product unless `productToken` is provided.")
+	cmd.Flags().StringVar(&stepConfig.ProductToken, "productToken", os.Getenv("PIPER_productToken"), "For `scanTool: whitesource`: Token of the WhiteSource product, can be provided as an alternative to `productName`.")
+	cmd.Flags().StringSliceVar(&stepConfig.ProjectNames, "projectNames", []string{}, "For `scanTool: whitesource`: Names of the WhiteSource projects to evaluate, by default all projects of the product are evaluated.")
+	cmd.Flags().IntVar(&stepConfig.Timeout, "timeout", 900, "For `scanTool: whitesource`: Timeout in seconds until an HTTP call is forcefully terminated.")
+
+	cmd.MarkFlagRequired("scanTool")
+}
+
+// retrieve step metadata
+func licenseCheckComplianceMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "licenseCheckCompliance",
+			Aliases:     []config.Alias{},
+			Description: "Evaluates the licenses of the product's components against a license policy and creates the attribution file",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "userTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing Whitesource user token.", Type: "jenkins", Aliases: []config.Alias{{Name: "whitesourceUserTokenCredentialsId", Deprecated: false}}},
+					{Name: "orgAdminUserTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing Whitesource org admin token.", Type: "jenkins", Aliases: []config.Alias{{Name: "whitesourceOrgAdminUserTokenCredentialsId", Deprecated: false}}},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:           "scanTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_scanTool"),
+						PossibleValues: []interface{}{"whitesource", "protecode"},
+					},
+					{
+						Name:        "licensePolicyFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/licensePolicy.yml`,
+					},
+					{
+						Name:        "failOnDeniedLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "attributionFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `NOTICE`,
+					},
+					{
+						Name:        "protecodeResultFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `protecodescan_vulns.json`,
+					},
+					{
+						Name:        "serviceUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "whitesourceServiceUrl"}},
+						Default:     `https://saas.whitesourcesoftware.com/api`,
+					},
+					{
+						Name: "orgToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "orgAdminUserTokenCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "whitesourceOrgToken"}},
+						Default:   os.Getenv("PIPER_orgToken"),
+					},
+					{
+						Name: "userToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "userTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/whitesource", "$(vaultBasePath)/$(vaultPipelineName)/whitesource", "$(vaultBasePath)/GROUP-SECRETS/whitesource"},
+								Type:  "vaultSecret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_userToken"),
+					},
+					{
+						Name:        "productName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "whitesourceProductName"}},
+						Default:     os.Getenv("PIPER_productName"),
+					},
+					{
+						Name:        "productToken",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "whitesourceProductToken"}},
+						Default:     os.Getenv("PIPER_productToken"),
+					},
+					{
+						Name:        "projectNames",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "timeout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     900,
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
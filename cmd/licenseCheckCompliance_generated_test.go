@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicenseCheckComplianceCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := LicenseCheckComplianceCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "licenseCheckCompliance", testCmd.Use, "command name incorrect")
+
+}
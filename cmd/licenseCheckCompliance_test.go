@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	ws "github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLicensePolicy = `
+allow: [Apache-2.0, MIT, BSD-*]
+review: [LGPL-*]
+deny: [GPL-*, AGPL-*]
+`
+
+const testProtecodeResult = `{"results": {"product_id": 1, "components": [
+  {"lib": "commons-io", "version": "2.11.0", "license": {"name": "Apache License 2.0", "type": "permissive"}},
+  {"lib": "openssl", "version": "1.1.1k", "license": {"name": "OpenSSL", "type": "permissive"}},
+  {"lib": "readline", "version": "8.1", "license": {"name": "GPL-3.0-only", "type": "copyleft", "url": "https://www.gnu.org/licenses/gpl-3.0.html"}},
+  {"lib": "zlib", "version": "1.2.11", "license": {"name": "Zlib OR MIT"}}
+]}}`
+
+func newLicenseCheckComplianceTestsUtils() *mock.FilesMock {
+	utils := &mock.FilesMock{}
+	utils.AddFile(".pipeline/licensePolicy.yml", []byte(testLicensePolicy))
+	return utils
+}
+
+func newLicenseWhitesourceMock() *ws.SystemMock {
+	sys := ws.NewSystemMock("2021-10-01 12:00:00 +0000")
+	sys.Libraries = []ws.Library{
+		{
+			Name:                "jackson-databind-2.13.1.jar",
+			Version:             "2.13.1",
+			Licenses:            []ws.License{{Name: "Apache 2.0", URL: "http://apache.org/licenses/LICENSE-2.0"}},
+			CopyrightReferences: []ws.CopyrightReference{{Copyright: "Copyright 2007 FasterXML"}},
+		},
+		{Name: "logback-core-1.2.10.jar", Version: "1.2.10", Licenses: []ws.License{{Name: "EPL 1.0", SPDXName: "EPL-1.0"}, {Name: "LGPL 2.1", SPDXName: "LGPL-2.1-only"}}},
+	}
+	return sys
+}
+
+func TestRunLicenseCheckCompliance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("whitesource", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "whitesource", LicensePolicyFile: ".pipeline/licensePolicy.yml", AttributionFile: "NOTICE", FailOnDeniedLicenses: true,
+			OrgToken: "org-token", UserToken: "user-token", ProductName: "mock-product"}
+		utils := newLicenseCheckComplianceTestsUtils()
+
+		err := runLicenseCheckCompliance(&config, utils, newLicenseWhitesourceMock())
+
+		require.NoError(t, err)
+		notice, err := utils.FileRead("NOTICE")
+		require.NoError(t, err)
+		assert.Contains(t, string(notice), "mock-product - Third-party software notices")
+		assert.Contains(t, string(notice), "jackson-databind-2.13.1.jar 2.13.1\n\nLicense: Apache-2.0\nLicense URL: http://apache.org/licenses/LICENSE-2.0\n\nCopyright 2007 FasterXML\n")
+		assert.Contains(t, string(notice), "License: EPL-1.0 AND LGPL-2.1-only\n")
+		assert.True(t, utils.HasWrittenFile(filepath.Join(reporting.StepReportDirectory, "licenseCheckCompliance.json")))
+		report, err := utils.FileRead(licenseCheckComplianceReport)
+		require.NoError(t, err)
+		assert.Contains(t, string(report), "logback-core-1.2.10.jar")
+		assert.Contains(t, string(report), "review")
+	})
+
+	t.Run("whitesource project not found", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "whitesource", LicensePolicyFile: ".pipeline/licensePolicy.yml", AttributionFile: "NOTICE",
+			OrgToken: "org-token", UserToken: "user-token", ProductToken: "mock-product-token", ProjectNames: []string{"other-project"}}
+
+		err := runLicenseCheckCompliance(&config, newLicenseCheckComplianceTestsUtils(), newLicenseWhitesourceMock())
+
+		assert.EqualError(t, err, "WhiteSource project 'other-project' not found")
+	})
+
+	t.Run("whitesource tokens missing", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "whitesource", LicensePolicyFile: ".pipeline/licensePolicy.yml", ProductName: "mock-product"}
+
+		err := runLicenseCheckCompliance(&config, newLicenseCheckComplianceTestsUtils(), newLicenseWhitesourceMock())
+
+		assert.EqualError(t, err, "the parameters orgToken and userToken are required for scan tool 'whitesource'")
+	})
+
+	t.Run("protecode with denied license", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "protecode", LicensePolicyFile: ".pipeline/licensePolicy.yml", AttributionFile: "NOTICE",
+			ProtecodeResultFile: "protecodescan_vulns.json", FailOnDeniedLicenses: true}
+		utils := newLicenseCheckComplianceTestsUtils()
+		utils.AddFile("protecodescan_vulns.json", []byte(testProtecodeResult))
+
+		err := runLicenseCheckCompliance(&config, utils, nil)
+
+		assert.EqualError(t, err, "1 component(s) with denied licenses: readline 8.1 (GPL-3.0-only)")
+		// attribution file and report are written nevertheless
+		notice, err := utils.FileRead("NOTICE")
+		require.NoError(t, err)
+		assert.Contains(t, string(notice), "zlib 1.2.11\n\nLicense: MIT (chosen from Zlib OR MIT)\n")
+		assert.Contains(t, string(notice), "commons-io 2.11.0\n\nLicense: Apache-2.0\n")
+		assert.True(t, utils.HasWrittenFile(licenseCheckComplianceReport))
+	})
+
+	t.Run("protecode with denied license not failing", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "protecode", LicensePolicyFile: ".pipeline/licensePolicy.yml", AttributionFile: "NOTICE",
+			ProtecodeResultFile: "protecodescan_vulns.json"}
+		utils := newLicenseCheckComplianceTestsUtils()
+		utils.AddFile("protecodescan_vulns.json", []byte(testProtecodeResult))
+
+		err := runLicenseCheckCompliance(&config, utils, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("protecode result missing", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "protecode", LicensePolicyFile: ".pipeline/licensePolicy.yml", ProtecodeResultFile: "protecodescan_vulns.json"}
+
+		err := runLicenseCheckCompliance(&config, newLicenseCheckComplianceTestsUtils(), nil)
+
+		assert.Contains(t, err.Error(), "failed to read Protecode result 'protecodescan_vulns.json', please run step protecodeExecuteScan before")
+	})
+
+	t.Run("policy missing", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "protecode", LicensePolicyFile: ".pipeline/licensePolicy.yml"}
+
+		err := runLicenseCheckCompliance(&config, &mock.FilesMock{}, nil)
+
+		assert.EqualError(t, err, "license policy '.pipeline/licensePolicy.yml' not found")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		t.Parallel()
+		config := licenseCheckComplianceOptions{ScanTool: "protecode", LicensePolicyFile: "policy.yml"}
+		utils := &mock.FilesMock{}
+		utils.AddFile("policy.yml", []byte("default: reject"))
+
+		err := runLicenseCheckCompliance(&config, utils, nil)
+
+		assert.EqualError(t, err, "invalid license policy 'policy.yml': invalid default decision 'reject', expected one of allow, review, deny")
+	})
+}
@@ -17,16 +17,29 @@ import (
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 )
 
-func mavenBuild(config mavenBuildOptions, telemetryData *telemetry.CustomData) {
+// mavenBOMDefines configure the CycloneDX maven plugin to write the BOM to target/bom.xml and target/bom.json
+var mavenBOMDefines = []string{
+	"-DschemaVersion=1.2",
+	"-DincludeBomSerialNumber=true",
+	"-DincludeCompileScope=true",
+	"-DincludeProvidedScope=true",
+	"-DincludeRuntimeScope=true",
+	"-DincludeSystemScope=true",
+	"-DincludeTestScope=false",
+	"-DincludeLicenseText=false",
+	"-DoutputFormat=all",
+}
+
+func mavenBuild(config mavenBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) {
 	utils := maven.NewUtilsBundle()
 
-	err := runMavenBuild(&config, telemetryData, utils)
+	err := runMavenBuild(&config, telemetryData, utils, commonPipelineEnvironment)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomData, utils maven.Utils) error {
+func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomData, utils maven.Utils, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) error {
 	downloadClient := &piperhttp.Client{}
 
 	var flags = []string{"-update-snapshots", "--batch-mode"}
@@ -52,18 +65,7 @@ func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomDat
 
 	if config.CreateBOM {
 		goals = append(goals, "org.cyclonedx:cyclonedx-maven-plugin:makeAggregateBom")
-		createBOMConfig := []string{
-			"-DschemaVersion=1.2",
-			"-DincludeBomSerialNumber=true",
-			"-DincludeCompileScope=true",
-			"-DincludeProvidedScope=true",
-			"-DincludeRuntimeScope=true",
-			"-DincludeSystemScope=true",
-			"-DincludeTestScope=false",
-			"-DincludeLicenseText=false",
-			"-DoutputFormat=xml",
-		}
-		defines = append(defines, createBOMConfig...)
+		defines = append(defines, mavenBOMDefines...)
 	}
 
 	if config.Verify {
@@ -85,6 +87,11 @@ func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomDat
 
 	_, err := maven.Execute(&mavenOptions, utils)
 
+	if err == nil && config.CreateBOM {
+		commonPipelineEnvironment.custom.sbomPath = filepath.Join(filepath.Dir(config.PomPath), "target", "bom.xml")
+		piperutils.AddStepArtifacts(piperutils.Path{Name: "bom", Target: commonPipelineEnvironment.custom.sbomPath})
+	}
+
 	if err == nil {
 		if config.Publish && !config.Verify {
 			log.Entry().Infof("publish detected, running mvn deploy")
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,10 +10,11 @@ import (
 func TestMavenBuild(t *testing.T) {
 	t.Run("mavenBuild should install the artifact", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Equal(t, mockedUtils.Calls[0].Exec, "mvn")
@@ -21,11 +23,12 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild should skip integration tests", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 		mockedUtils.AddFile("integration-tests/pom.xml", []byte{})
 
 		config := mavenBuildOptions{}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Equal(t, mockedUtils.Calls[0].Exec, "mvn")
@@ -34,10 +37,11 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild should flatten", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{Flatten: true}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[0].Params, "flatten:flatten")
@@ -47,10 +51,11 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild should run only verify", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{Verify: true}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[0].Params, "verify")
@@ -59,10 +64,11 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild should createBOM", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
-		config := mavenBuildOptions{CreateBOM: true}
+		config := mavenBuildOptions{CreateBOM: true, PomPath: "pom.xml"}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[0].Params, "org.cyclonedx:cyclonedx-maven-plugin:makeAggregateBom")
@@ -74,15 +80,17 @@ func TestMavenBuild(t *testing.T) {
 		assert.Contains(t, mockedUtils.Calls[0].Params, "-DincludeSystemScope=true")
 		assert.Contains(t, mockedUtils.Calls[0].Params, "-DincludeTestScope=false")
 		assert.Contains(t, mockedUtils.Calls[0].Params, "-DincludeLicenseText=false")
-		assert.Contains(t, mockedUtils.Calls[0].Params, "-DoutputFormat=xml")
+		assert.Contains(t, mockedUtils.Calls[0].Params, "-DoutputFormat=all")
+		assert.Equal(t, filepath.Join("target", "bom.xml"), cpe.custom.sbomPath)
 	})
 
 	t.Run("mavenBuild include install and deploy when publish is true", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{Publish: true, Verify: false}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[0].Params, "install")
@@ -93,10 +101,11 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild with deploy must skip build, install and test", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{Publish: true, Verify: false}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[1].Params, "-Dmaven.main.skip=true")
@@ -107,10 +116,11 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild with deploy must include alt repo id and url when passed as parameter", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{Publish: true, Verify: false, AltDeploymentRepositoryID: "ID", AltDeploymentRepositoryURL: "http://sampleRepo.com"}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[1].Params, "-DaltDeploymentRepository=ID::default::http://sampleRepo.com")
@@ -118,10 +128,11 @@ func TestMavenBuild(t *testing.T) {
 
 	t.Run("mavenBuild accepts profiles", func(t *testing.T) {
 		mockedUtils := newMavenMockUtils()
+		cpe := mavenBuildCommonPipelineEnvironment{}
 
 		config := mavenBuildOptions{Profiles: []string{"profile1", "profile2"}}
 
-		err := runMavenBuild(&config, nil, &mockedUtils)
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
 
 		assert.Nil(t, err)
 		assert.Contains(t, mockedUtils.Calls[0].Params, "--activate-profiles")
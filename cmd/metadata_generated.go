@@ -21,6 +21,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"abapEnvironmentCreateSystem":               abapEnvironmentCreateSystemMetadata(),
 		"abapEnvironmentPullGitRepo":                abapEnvironmentPullGitRepoMetadata(),
 		"abapEnvironmentRunATCCheck":                abapEnvironmentRunATCCheckMetadata(),
+		"artifactCreateSBOM":                        artifactCreateSBOMMetadata(),
 		"batsExecuteTests":                          batsExecuteTestsMetadata(),
 		"checkChangeInDevelopment":                  checkChangeInDevelopmentMetadata(),
 		"checkmarxExecuteScan":                      checkmarxExecuteScanMetadata(),
@@ -43,6 +44,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"githubCommentIssue":                        githubCommentIssueMetadata(),
 		"githubCreateIssue":                         githubCreateIssueMetadata(),
 		"githubCreatePullRequest":                   githubCreatePullRequestMetadata(),
+		"githubPublishScanResults":                  githubPublishScanResultsMetadata(),
 		"githubPublishRelease":                      githubPublishReleaseMetadata(),
 		"githubSetCommitStatus":                     githubSetCommitStatusMetadata(),
 		"gitopsUpdateDeployment":                    gitopsUpdateDeploymentMetadata(),
@@ -55,10 +57,12 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"integrationArtifactTriggerIntegrationTest": integrationArtifactTriggerIntegrationTestMetadata(),
 		"integrationArtifactUpdateConfiguration":    integrationArtifactUpdateConfigurationMetadata(),
 		"integrationArtifactUpload":                 integrationArtifactUploadMetadata(),
+		"jenkinsTriggerJob":                         jenkinsTriggerJobMetadata(),
 		"jsonApplyPatch":                            jsonApplyPatchMetadata(),
 		"kanikoExecute":                             kanikoExecuteMetadata(),
 		"karmaExecuteTests":                         karmaExecuteTestsMetadata(),
 		"kubernetesDeploy":                          kubernetesDeployMetadata(),
+		"licenseCheckCompliance":                    licenseCheckComplianceMetadata(),
 		"malwareExecuteScan":                        malwareExecuteScanMetadata(),
 		"mavenBuild":                                mavenBuildMetadata(),
 		"mavenExecute":                              mavenExecuteMetadata(),
@@ -69,7 +73,9 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"nexusUpload":                               nexusUploadMetadata(),
 		"npmExecuteLint":                            npmExecuteLintMetadata(),
 		"npmExecuteScripts":                         npmExecuteScriptsMetadata(),
+		"osvExecuteScan":                            osvExecuteScanMetadata(),
 		"pipelineCreateScanSummary":                 pipelineCreateScanSummaryMetadata(),
+		"policyEvaluate":                            policyEvaluateMetadata(),
 		"protecodeExecuteScan":                      protecodeExecuteScanMetadata(),
 		"containerSaveImage":                        containerSaveImageMetadata(),
 		"sonarExecuteScan":                          sonarExecuteScanMetadata(),
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"text/template"
 	"time"
@@ -13,12 +14,15 @@ import (
 	"github.com/SAP/jenkins-library/pkg/npm"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	yamlv2 "gopkg.in/yaml.v2"
 )
 
 const templateMtaYml = `_schema-version: "3.1"
@@ -88,6 +92,7 @@ type mtaBuildUtils interface {
 
 	SetNpmRegistries(defaultNpmRegistry string) error
 	InstallAllDependencies(defaultNpmRegistry string) error
+	CreateNpmBOM(packageJSONFiles []string) error
 }
 
 type mtaBuildUtilsBundle struct {
@@ -108,6 +113,12 @@ func (bundle *mtaBuildUtilsBundle) InstallAllDependencies(defaultNpmRegistry str
 	return npmExecutor.InstallAllDependencies(npmExecutor.FindPackageJSONFiles())
 }
 
+func (bundle *mtaBuildUtilsBundle) CreateNpmBOM(packageJSONFiles []string) error {
+	npmExecutorOptions := npm.ExecutorOptions{ExecRunner: bundle}
+	npmExecutor := npm.NewExecutor(npmExecutorOptions)
+	return npmExecutor.CreateBOM(packageJSONFiles)
+}
+
 func (bundle *mtaBuildUtilsBundle) DownloadAndCopySettingsFiles(globalSettingsFile string, projectSettingsFile string) error {
 	return maven.DownloadAndCopySettingsFiles(globalSettingsFile, projectSettingsFile, bundle)
 }
@@ -220,6 +231,7 @@ func runMtaBuild(config mtaBuildOptions,
 	}
 
 	commonPipelineEnvironment.mtarFilePath = mtarName
+	piperutils.AddStepArtifacts(piperutils.Path{Name: "mtar", Target: mtarName})
 
 	if config.InstallArtifacts {
 		// install maven artifacts in local maven repo because `mbt build` executes `mvn package -B`
@@ -233,9 +245,106 @@ func runMtaBuild(config mtaBuildOptions,
 			return err
 		}
 	}
+
+	if config.CreateBOM {
+		if err := createMtaBOM(mtaYamlFile, config, utils); err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.sbomPath = mtaBOMFile
+	}
 	return err
 }
 
+// mtaBOMFile is the BOM of the whole MTA, its JSON representation is written to bom.json
+const mtaBOMFile = "bom.xml"
+
+type mtaDescriptor struct {
+	ID      string      `yaml:"ID"`
+	Version string      `yaml:"version"`
+	Modules []mtaModule `yaml:"modules"`
+}
+
+type mtaModule struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	Path string `yaml:"path"`
+}
+
+// createMtaBOM creates the BOMs of all npm and maven modules of the MTA and merges them into one BOM for the MTA
+func createMtaBOM(mtaYamlFile string, config mtaBuildOptions, utils mtaBuildUtils) error {
+	content, err := utils.FileRead(mtaYamlFile)
+	if err != nil {
+		return err
+	}
+	// parsed without conversion to JSON to keep versions like 1.0
+	var mta mtaDescriptor
+	if err := yamlv2.Unmarshal(content, &mta); err != nil {
+		return errors.Wrapf(err, "failed to parse '%v'", mtaYamlFile)
+	}
+
+	packageJSONFiles := []string{}
+	mavenBOMFiles := []string{}
+	for _, module := range mta.Modules {
+		if len(module.Path) == 0 {
+			continue
+		}
+		if exists, _ := utils.FileExists(filepath.Join(module.Path, "package.json")); exists {
+			packageJSONFiles = append(packageJSONFiles, filepath.Join(module.Path, "package.json"))
+			continue
+		}
+		pomPath := filepath.Join(module.Path, "pom.xml")
+		if exists, _ := utils.FileExists(pomPath); exists {
+			log.Entry().Infof("Creating BOM of maven module '%v'", module.Name)
+			mavenOptions := maven.ExecuteOptions{
+				PomPath:             pomPath,
+				Goals:               []string{"org.cyclonedx:cyclonedx-maven-plugin:makeAggregateBom"},
+				Defines:             mavenBOMDefines,
+				Flags:               []string{"--batch-mode"},
+				ProjectSettingsFile: config.ProjectSettingsFile,
+				GlobalSettingsFile:  config.GlobalSettingsFile,
+				M2Path:              config.M2Path,
+			}
+			if _, err := maven.Execute(&mavenOptions, utils); err != nil {
+				log.SetErrorCategory(log.ErrorBuild)
+				return errors.Wrapf(err, "failed to create BOM of maven module '%v'", module.Name)
+			}
+			mavenBOMFiles = append(mavenBOMFiles, filepath.Join(module.Path, "target", "bom.xml"))
+			continue
+		}
+		log.Entry().Infof("No BOM created for module '%v' of type '%v'", module.Name, module.Type)
+	}
+
+	boms := []*cyclonedx.BOM{}
+	if len(packageJSONFiles) > 0 {
+		log.Entry().Infof("Creating BOM of npm modules %v", packageJSONFiles)
+		if err := utils.CreateNpmBOM(packageJSONFiles); err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return errors.Wrap(err, "failed to create BOM of npm modules")
+		}
+		// the npm BOM is written to the BOM file of the MTA which is overwritten by the merged BOM
+		bom, err := cyclonedx.ReadBOMFile(mtaBOMFile, utils)
+		if err != nil {
+			return err
+		}
+		boms = append(boms, bom)
+	}
+	for _, bomFile := range mavenBOMFiles {
+		bom, err := cyclonedx.ReadBOMFile(bomFile, utils)
+		if err != nil {
+			return err
+		}
+		boms = append(boms, bom)
+	}
+
+	component := cyclonedx.Component{Type: cyclonedx.ComponentTypeApplication, BOMRef: mta.ID + "@" + mta.Version, Name: mta.ID, Version: mta.Version}
+	if err := cyclonedx.WriteBOM(cyclonedx.Merge(&component, boms...), mtaBOMFile, utils); err != nil {
+		return err
+	}
+	log.Entry().Infof("BOM of %v modules written to '%v'", len(boms), mtaBOMFile)
+	piperutils.AddStepArtifacts(piperutils.Path{Name: "bom", Target: mtaBOMFile})
+	return nil
+}
+
 func installMavenArtifacts(utils mtaBuildUtils, config mtaBuildOptions) error {
 	pomXMLExists, err := utils.FileExists("pom.xml")
 	if err != nil {
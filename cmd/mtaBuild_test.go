@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/ghodss/yaml"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,7 @@ type mtaBuildTestUtilsBundle struct {
 	projectSettingsFile            string
 	globalSettingsFile             string
 	registryUsedInSetNpmRegistries string
+	npmBOMPackageJSONFiles         []string
 }
 
 func (m *mtaBuildTestUtilsBundle) SetNpmRegistries(defaultNpmRegistry string) error {
@@ -28,6 +30,15 @@ func (m *mtaBuildTestUtilsBundle) InstallAllDependencies(defaultNpmRegistry stri
 	return errors.New("Test should not install dependencies.") //TODO implement test
 }
 
+func (m *mtaBuildTestUtilsBundle) CreateNpmBOM(packageJSONFiles []string) error {
+	m.npmBOMPackageJSONFiles = packageJSONFiles
+	m.AddFile("bom.xml", []byte(`<bom xmlns="http://cyclonedx.org/schema/bom/1.2" version="1">
+  <metadata><component type="library" bom-ref="pkg:npm/ui@1.0.0"><name>ui</name><version>1.0.0</version><purl>pkg:npm/ui@1.0.0</purl></component></metadata>
+  <components><component type="library" bom-ref="pkg:npm/lodash@4.17.21"><name>lodash</name><version>4.17.21</version><purl>pkg:npm/lodash@4.17.21</purl></component></components>
+</bom>`))
+	return nil
+}
+
 func (m *mtaBuildTestUtilsBundle) DownloadAndCopySettingsFiles(globalSettingsFile string, projectSettingsFile string) error {
 	m.projectSettingsFile = projectSettingsFile
 	m.globalSettingsFile = globalSettingsFile
@@ -249,4 +260,54 @@ func TestMarBuild(t *testing.T) {
 			assert.Equal(t, "", utilsMock.globalSettingsFile)
 		})
 	})
+
+	t.Run("Create BOM", func(t *testing.T) {
+		utilsMock := newMtaBuildTestUtilsBundle()
+		utilsMock.AddFile("mta.yaml", []byte(`ID: myMta
+version: 1.0
+modules:
+  - name: ui
+    type: html5
+    path: ui
+  - name: srv
+    type: java
+    path: srv
+  - name: db
+    type: hdb
+    path: db
+`))
+		utilsMock.AddFile(filepath.Join("ui", "package.json"), []byte(`{"name": "ui", "version": "1.0.0"}`))
+		utilsMock.AddFile(filepath.Join("srv", "pom.xml"), []byte("<project/>"))
+		// simulate the BOM written by the CycloneDX maven plugin
+		utilsMock.AddFile(filepath.Join("srv", "target", "bom.xml"), []byte(`<bom xmlns="http://cyclonedx.org/schema/bom/1.2" version="1">
+  <metadata><component type="library" bom-ref="pkg:maven/com.sap/srv@1.0.0"><group>com.sap</group><name>srv</name><version>1.0.0</version><purl>pkg:maven/com.sap/srv@1.0.0</purl></component></metadata>
+  <components><component type="library" bom-ref="pkg:maven/org.slf4j/slf4j-api@1.7.32"><group>org.slf4j</group><name>slf4j-api</name><version>1.7.32</version><purl>pkg:maven/org.slf4j/slf4j-api@1.7.32</purl></component></components>
+</bom>`))
+		cpe := mtaBuildCommonPipelineEnvironment{}
+
+		options := mtaBuildOptions{Platform: "CF", MtarName: "myName.mtar", CreateBOM: true}
+
+		err := runMtaBuild(options, &cpe, utilsMock)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{filepath.Join("ui", "package.json")}, utilsMock.npmBOMPackageJSONFiles)
+			if assert.Len(t, utilsMock.Calls, 2) {
+				assert.Equal(t, "mvn", utilsMock.Calls[1].Exec)
+				assert.Contains(t, utilsMock.Calls[1].Params, "org.cyclonedx:cyclonedx-maven-plugin:makeAggregateBom")
+				assert.Contains(t, utilsMock.Calls[1].Params, filepath.Join("srv", "pom.xml"))
+			}
+			assert.Equal(t, "bom.xml", cpe.custom.sbomPath)
+
+			bom, err := cyclonedx.ReadBOMFile("bom.json", utilsMock)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "myMta", bom.Metadata.Component.Name)
+				assert.Equal(t, "1.0", bom.Metadata.Component.Version)
+				names := []string{}
+				for _, component := range bom.Components {
+					names = append(names, component.Name)
+				}
+				assert.Equal(t, []string{"ui", "lodash", "srv", "slf4j-api"}, names)
+			}
+		}
+	})
 }
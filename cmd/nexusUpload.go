@@ -13,6 +13,7 @@ import (
 	b64 "encoding/base64"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/nexus"
@@ -187,6 +188,12 @@ func uploadMTA(utils nexusUploadUtils, uploader nexus.Uploader, options *nexusUp
 		log.Entry().Debugf("mtar file path: '%s'", mtarFilePath)
 		err = addArtifact(utils, uploader, mtarFilePath, "", "mtar")
 	}
+	if err == nil {
+		sbomPath := utils.getEnvParameter(".pipeline/commonPipelineEnvironment", "custom/sbomPath")
+		if len(sbomPath) > 0 {
+			err = addSBOMArtifacts(utils, uploader, sbomPath)
+		}
+	}
 	if err == nil {
 		err = uploadArtifacts(utils, uploader, options, false)
 	}
@@ -373,6 +380,19 @@ func addArtifact(utils nexusUploadUtils, uploader nexus.Uploader, filePath, clas
 	return uploader.AddArtifact(artifact)
 }
 
+// addSBOMArtifacts adds the BOM in XML format and its JSON representation if they exist
+func addSBOMArtifacts(utils nexusUploadUtils, uploader nexus.Uploader, xmlPath string) error {
+	for _, sbom := range []struct{ path, fileType string }{{xmlPath, "xml"}, {cyclonedx.JSONPath(xmlPath), "json"}} {
+		if exists, _ := utils.FileExists(sbom.path); !exists {
+			continue
+		}
+		if err := addArtifact(utils, uploader, sbom.path, "cyclonedx", sbom.fileType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var errPomNotFound = errors.New("pom.xml not found")
 
 func uploadMaven(utils nexusUploadUtils, uploader nexus.Uploader, options *nexusUploadOptions) error {
@@ -442,6 +462,9 @@ func uploadMavenArtifacts(utils nexusUploadUtils, uploader nexus.Uploader, optio
 	if err == nil && packaging != "pom" {
 		err = addMavenTargetArtifacts(utils, uploader, pomFile, targetFolder, finalBuildName, packaging)
 	}
+	if err == nil {
+		err = addSBOMArtifacts(utils, uploader, filepath.Join(targetFolder, "bom.xml"))
+	}
 	if err == nil {
 		err = uploadArtifacts(utils, uploader, options, true)
 	}
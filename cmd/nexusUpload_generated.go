@@ -9,6 +9,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
@@ -50,6 +51,9 @@ To upload Maven projects, you need a pom.xml in the project root and set the mav
 To upload MTA projects, you need a mta.yaml in the project root and set the mavenRepository option.
 To upload npm projects, you need a package.json in the project root and set the npmRepository option.
 
+The bill of materials (BOM) of the project is uploaded with classifier 'cyclonedx' in XML and JSON format if it exists.
+For Maven modules the BOM is expected in 'target/bom.xml', for MTA projects the BOM referenced in the commonPipelineEnvironment as 'custom/sbomPath' is used.
+
 If the 'format' option is set, the 'URL' can contain the full path including the repository ID. Providing the 'npmRepository' or the 'mavenRepository' parameter(s) is not necessary.
 
 npm:
@@ -94,8 +98,10 @@ If an image for mavenExecute is configured, and npm packages are to be published
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -156,13 +162,14 @@ func nexusUploadMetadata() config.StepData {
 				},
 				Parameters: []config.StepParameters{
 					{
-						Name:        "version",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "nexus/version"}},
-						Default:     `nexus3`,
+						Name:           "version",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "nexus/version"}},
+						Default:        `nexus3`,
+						PossibleValues: []interface{}{"nexus2", "nexus3"},
 					},
 					{
 						Name: "format",
@@ -172,11 +179,12 @@ func nexusUploadMetadata() config.StepData {
 								Param: "custom/repositoryFormat",
 							},
 						},
-						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_format"),
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_format"),
+						PossibleValues: []interface{}{"maven", "npm"},
 					},
 					{
 						Name: "url",
@@ -198,7 +206,7 @@ func nexusUploadMetadata() config.StepData {
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
 						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "nexus/mavenRepository"}, {Name: "nexus/repository"}},
+						Aliases:     []config.Alias{{Name: "nexus/mavenRepository"}, {Name: "nexus/repository", Deprecated: true}},
 						Default:     os.Getenv("PIPER_mavenRepository"),
 					},
 					{
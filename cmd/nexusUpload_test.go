@@ -263,6 +263,27 @@ func TestUploadMTAProjects(t *testing.T) {
 			assert.Equal(t, "mtar", artifacts[1].Type)
 		}
 	})
+	t.Run("Test uploading mta.yaml project with BOM works", func(t *testing.T) {
+		t.Parallel()
+		utils := newMockUtilsBundle(true, false, false)
+		utils.AddFile("mta.yaml", testMtaYml)
+		utils.AddFile("test.mtar", []byte("contentsOfMtar"))
+		utils.AddFile("bom.xml", []byte("<bom/>"))
+		utils.AddFile("bom.json", []byte("{}"))
+		utils.cpe[".pipeline/commonPipelineEnvironment/mtarFilePath"] = "test.mtar"
+		utils.cpe[".pipeline/commonPipelineEnvironment/custom/sbomPath"] = "bom.xml"
+		uploader := mockUploader{}
+		options := createOptions()
+
+		err := runNexusUpload(utils, &uploader, &options)
+		assert.NoError(t, err, "expected mta.yaml project upload to work")
+
+		artifacts := uploader.uploadedArtifacts
+		if assert.Equal(t, 4, len(artifacts)) {
+			assert.Equal(t, nexus.ArtifactDescription{File: "bom.xml", Type: "xml", Classifier: "cyclonedx"}, artifacts[2])
+			assert.Equal(t, nexus.ArtifactDescription{File: "bom.json", Type: "json", Classifier: "cyclonedx"}, artifacts[3])
+		}
+	})
 	t.Run("Test uploading mta.yml project works", func(t *testing.T) {
 		t.Parallel()
 		utils := newMockUtilsBundle(true, false, false)
@@ -442,6 +463,30 @@ func TestUploadMavenProjects(t *testing.T) {
 			assert.Equal(t, "pom", artifacts[0].Type)
 		}
 	})
+	t.Run("Test uploading Maven project with BOM works", func(t *testing.T) {
+		t.Parallel()
+		utils := newMockUtilsBundle(false, true, false)
+		utils.setProperty("pom.xml", "project.version", "1.0")
+		utils.setProperty("pom.xml", "project.groupId", "com.mycompany.app")
+		utils.setProperty("pom.xml", "project.artifactId", "my-app")
+		utils.setProperty("pom.xml", "project.packaging", "pom")
+		utils.setProperty("pom.xml", "project.build.finalName", "my-app-1.0")
+		utils.AddFile("pom.xml", testPomXml)
+		utils.AddFile(filepath.Join("target", "bom.xml"), []byte("<bom/>"))
+		utils.AddFile(filepath.Join("target", "bom.json"), []byte("{}"))
+		uploader := mockUploader{}
+		options := createOptions()
+
+		err := runNexusUpload(utils, &uploader, &options)
+		assert.NoError(t, err, "expected Maven upload to work")
+
+		artifacts := uploader.uploadedArtifacts
+		if assert.Equal(t, 3, len(artifacts)) {
+			assert.Equal(t, "pom.xml", artifacts[0].File)
+			assert.Equal(t, nexus.ArtifactDescription{File: filepath.Join("target", "bom.xml"), Type: "xml", Classifier: "cyclonedx"}, artifacts[1])
+			assert.Equal(t, nexus.ArtifactDescription{File: filepath.Join("target", "bom.json"), Type: "json", Classifier: "cyclonedx"}, artifacts[2])
+		}
+	})
 	t.Run("Test uploading Maven project with JAR packaging fails without main target", func(t *testing.T) {
 		t.Parallel()
 		utils := newMockUtilsBundle(false, true, false)
@@ -33,7 +33,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, PackagesList: config.BuildDescriptorList}}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		assert.NoError(t, err)
 	})
@@ -45,7 +45,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, ExcludeList: config.BuildDescriptorExcludeList}}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		assert.NoError(t, err)
 	})
@@ -57,7 +57,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, ScriptOptions: config.ScriptOptions}}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		assert.NoError(t, err)
 	})
@@ -69,7 +69,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		assert.NoError(t, err)
 	})
@@ -81,7 +81,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		assert.NoError(t, err)
 	})
@@ -93,7 +93,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, VirtualFrameBuffer: config.VirtualFrameBuffer}}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		assert.NoError(t, err)
 	})
@@ -109,7 +109,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 
 		npmExecutor := npm.Execute{Utils: &utils, Options: options}
 
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &npmExecuteScriptsCommonPipelineEnvironment{})
 
 		if assert.NoError(t, err) {
 			if assert.Equal(t, 4, len(utils.execRunner.Calls)) {
@@ -128,10 +128,14 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils := newNpmMockUtilsBundle()
 		utils.AddFile("package.json", []byte("{\"name\": \"Test\" }"))
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
+		utils.AddFile("bom.xml", []byte(`<bom xmlns="http://cyclonedx.org/schema/bom/1.2" version="1"/>`))
+		cpe := npmExecuteScriptsCommonPipelineEnvironment{}
 
 		npmExecutor := npm.Execute{Utils: &utils, Options: options}
-		err := runNpmExecuteScripts(&npmExecutor, &config)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
 
 		assert.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile("bom.json"))
+		assert.Equal(t, "bom.xml", cpe.custom.sbomPath)
 	})
 }
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+	"github.com/pkg/errors"
+)
+
+const osvExecuteScanReport = "osvExecuteScan.md"
+
+type osvExecuteScanUtils interface {
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	DirExists(path string) (bool, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+}
+
+func osvExecuteScan(config osvExecuteScanOptions, telemetryData *telemetry.CustomData, influx *osvExecuteScanInflux) {
+	utils := &piperutils.Files{}
+
+	influx.step_data.fields.osv = false
+	err := runOsvExecuteScan(&config, utils, influx, time.Now())
+	// the report is also archived in case of severe vulnerabilities
+	if exists, _ := piperutils.FileExists(osvExecuteScanReport); exists {
+		piperutils.PersistReportsAndLinks("osvExecuteScan", "", []piperutils.Path{{Name: "OSV Vulnerability Report", Target: osvExecuteScanReport}}, nil)
+	}
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+	influx.step_data.fields.osv = true
+}
+
+func runOsvExecuteScan(config *osvExecuteScanOptions, utils osvExecuteScanUtils, influx *osvExecuteScanInflux, now time.Time) error {
+	bom, err := cyclonedx.ReadBOMFile(config.SbomPath, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to read BOM '%v'", config.SbomPath)
+	}
+
+	db, err := osv.ReadDatabase(config.AdvisoryDatabasePath, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	if db.Len() == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no advisories found in '%v'", config.AdvisoryDatabasePath)
+	}
+
+	matches := db.Match(bom)
+	log.Entry().Infof("Matched %v components against %v advisories: %v vulnerabilities found", len(bom.Components), db.Len(), len(matches))
+
+	// exemptions of the central exemptions file are applied in addition to excludeCVEs
+	findings, exemptionErr := vulnerability.ApplyExemptions(osv.ConvertToFindings(matches, config.ExcludeCVEs, config.SbomPath), utils, now)
+	if _, err := vulnerability.WriteFindings(findings, "osvExecuteScan", utils); err != nil {
+		log.Entry().WithError(err).Warn("failed to write findings")
+	}
+
+	result := osv.ParseResultForInflux(findings)
+	influx.osv_data.fields.historical_vulnerabilities = result["historical_vulnerabilities"]
+	influx.osv_data.fields.triaged_vulnerabilities = result["triaged_vulnerabilities"]
+	influx.osv_data.fields.excluded_vulnerabilities = result["excluded_vulnerabilities"]
+	influx.osv_data.fields.minor_vulnerabilities = result["minor_vulnerabilities"]
+	influx.osv_data.fields.major_vulnerabilities = result["major_vulnerabilities"]
+	influx.osv_data.fields.vulnerabilities = result["vulnerabilities"]
+
+	severe := vulnerability.Severe(findings, vulnerability.DefaultSeverityThreshold)
+	report := osvReport(config, db, findings, len(severe))
+	if err := writeOsvReport(report, utils); err != nil {
+		return err
+	}
+
+	if exemptionErr != nil {
+		return exemptionErr
+	}
+	if config.FailOnSevereVulnerabilities && len(severe) > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v severe vulnerabilities found", len(severe))
+	}
+	return nil
+}
+
+// osvReport lists the findings including the versions fixing them
+func osvReport(config *osvExecuteScanOptions, db *osv.Database, findings []vulnerability.Finding, severe int) reporting.ScanReport {
+	open := 0
+	for _, finding := range findings {
+		if finding.IsOpen() {
+			open++
+		}
+	}
+	report := reporting.ScanReport{
+		StepName: "osvExecuteScan",
+		Title:    "OSV Vulnerability Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "BOM", Details: config.SbomPath},
+			{Description: "Advisory database", Details: config.AdvisoryDatabasePath},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Advisories", Details: fmt.Sprint(db.Len())},
+			{Description: "Severe vulnerabilities", Details: fmt.Sprint(severe)},
+			{Description: "Open vulnerabilities", Details: fmt.Sprint(open)},
+			{Description: "Suppressed vulnerabilities", Details: fmt.Sprint(len(findings) - open)},
+		},
+		SuccessfulScan: severe == 0,
+		DetailTable: reporting.ScanDetailTable{
+			Headers:       []string{"Component", "Version", "Vulnerability", "Severity", "CVSS", "Fixed in", "Status"},
+			NoRowsMessage: "No vulnerabilities found",
+			WithCounter:   true,
+			CounterHeader: "Entry #",
+		},
+	}
+	if severe > 0 {
+		report.Overview[1].Style = reporting.Red
+	}
+	for _, finding := range findings {
+		severityStyle := reporting.ColumnStyle(0)
+		if finding.IsOpen() && finding.IsSevere(vulnerability.DefaultSeverityThreshold) {
+			severityStyle = reporting.Red
+		} else if finding.IsOpen() {
+			severityStyle = reporting.Yellow
+		}
+		name := finding.Component.Name
+		if len(finding.Component.Group) > 0 {
+			name = finding.Component.Group + ":" + name
+		}
+		status := finding.Status
+		if len(finding.Justification) > 0 {
+			status += ": " + finding.Justification
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(name, 0)
+		row.AddColumn(finding.Component.Version, 0)
+		row.AddColumn(fmt.Sprintf("[%v](%v)", finding.ID, finding.URL), 0)
+		row.AddColumn(finding.EffectiveSeverity(), severityStyle)
+		row.AddColumn(finding.CVSS, 0)
+		row.AddColumn(strings.Join(finding.FixedVersions, ", "), 0)
+		row.AddColumn(status, 0)
+		report.DetailTable.Rows = append(report.DetailTable.Rows, row)
+	}
+	return report
+}
+
+func writeOsvReport(report reporting.ScanReport, utils osvExecuteScanUtils) error {
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	mdReport, _ := report.ToMarkdown()
+	if err := utils.FileWrite(osvExecuteScanReport, mdReport, 0666); err != nil {
+		log.Entry().WithError(err).Warn("failed to write vulnerability report")
+	}
+
+	// JSON reports are used by step pipelineCreateScanSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := report.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return errors.Wrap(err, "failed to create reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "osvExecuteScan.json"), jsonReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	return nil
+}
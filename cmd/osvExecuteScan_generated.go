@@ -0,0 +1,241 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type osvExecuteScanOptions struct {
+	SbomPath                    string   `json:"sbomPath,omitempty"`
+	AdvisoryDatabasePath        string   `json:"advisoryDatabasePath,omitempty"`
+	ExcludeCVEs                 []string `json:"excludeCVEs,omitempty"`
+	FailOnSevereVulnerabilities bool     `json:"failOnSevereVulnerabilities,omitempty"`
+}
+
+type osvExecuteScanInflux struct {
+	step_data struct {
+		fields struct {
+			osv bool
+		}
+		tags struct {
+		}
+	}
+	osv_data struct {
+		fields struct {
+			excluded_vulnerabilities   int
+			historical_vulnerabilities int
+			major_vulnerabilities      int
+			minor_vulnerabilities      int
+			triaged_vulnerabilities    int
+			vulnerabilities            int
+		}
+		tags struct {
+		}
+	}
+}
+
+func (i *osvExecuteScanInflux) persist(path, resourceName string) {
+	measurementContent := []struct {
+		measurement string
+		valType     string
+		name        string
+		value       interface{}
+	}{
+		{valType: config.InfluxField, measurement: "step_data", name: "osv", value: i.step_data.fields.osv},
+		{valType: config.InfluxField, measurement: "osv_data", name: "excluded_vulnerabilities", value: i.osv_data.fields.excluded_vulnerabilities},
+		{valType: config.InfluxField, measurement: "osv_data", name: "historical_vulnerabilities", value: i.osv_data.fields.historical_vulnerabilities},
+		{valType: config.InfluxField, measurement: "osv_data", name: "major_vulnerabilities", value: i.osv_data.fields.major_vulnerabilities},
+		{valType: config.InfluxField, measurement: "osv_data", name: "minor_vulnerabilities", value: i.osv_data.fields.minor_vulnerabilities},
+		{valType: config.InfluxField, measurement: "osv_data", name: "triaged_vulnerabilities", value: i.osv_data.fields.triaged_vulnerabilities},
+		{valType: config.InfluxField, measurement: "osv_data", name: "vulnerabilities", value: i.osv_data.fields.vulnerabilities},
+	}
+
+	errCount := 0
+	for _, metric := range measurementContent {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting influx environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Influx environment")
+	}
+}
+
+// OsvExecuteScanCommand Matches the CycloneDX bill of materials against a local OSV advisory database
+func OsvExecuteScanCommand() *cobra.Command {
+	const STEP_NAME = "osvExecuteScan"
+
+	metadata := osvExecuteScanMetadata()
+	var stepConfig osvExecuteScanOptions
+	var startTime time.Time
+	var influx osvExecuteScanInflux
+	var logCollector *log.CollectorHook
+
+	var createOsvExecuteScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Matches the CycloneDX bill of materials against a local OSV advisory database",
+		Long: `This step checks the components of a [CycloneDX](https://cyclonedx.org/) bill of materials (BOM) for known vulnerabilities without access to an online scan service.
+The vulnerabilities are read from a locally mirrored advisory database in the [Open Source Vulnerability (OSV)](https://ossf.github.io/osv-schema/) format, e.g. an export of [osv.dev](https://osv.dev) or of the GitHub advisory database.
+This allows scanning in restricted networks in which WhiteSource or Protecode are not reachable.
+
+The components are identified by their package URL, components of the ecosystems ` + "`" + `Maven` + "`" + `, ` + "`" + `npm` + "`" + `, ` + "`" + `Go` + "`" + ` and ` + "`" + `PyPI` + "`" + ` are matched.
+The affected version ranges of an advisory are evaluated according to the versioning scheme of the ecosystem, e.g. Maven qualifiers or PEP 440 pre-releases.
+
+The BOM is created by the build steps, see parameter ` + "`" + `createBOM` + "`" + ` of the steps mavenBuild, mtaBuild, npmExecuteScripts and kanikoExecute, or by step [` + "`" + `artifactCreateSBOM` + "`" + `](artifactCreateSBOM.md).
+The findings are written into a report which is picked up by step [` + "`" + `pipelineCreateScanSummary` + "`" + `](pipelineCreateScanSummary.md), exemptions of the central exemptions file are applied.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				influx.persist(GeneralConfig.EnvRootPath, "influx")
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
+				telemetry.Send(&telemetryData)
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunk.Send(&telemetryData, logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunk.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			osvExecuteScan(stepConfig, &telemetryData, &influx)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addOsvExecuteScanFlags(createOsvExecuteScanCmd, &stepConfig)
+	return createOsvExecuteScanCmd
+}
+
+func addOsvExecuteScanFlags(cmd *cobra.Command, stepConfig *osvExecuteScanOptions) {
+	cmd.Flags().StringVar(&stepConfig.SbomPath, "sbomPath", os.Getenv("PIPER_sbomPath"), "Path of the CycloneDX BOM in XML or JSON format.")
+	cmd.Flags().StringVar(&stepConfig.AdvisoryDatabasePath, "advisoryDatabasePath", os.Getenv("PIPER_advisoryDatabasePath"), "Path of the directory containing the advisories in OSV format as JSON files, sub-directories are searched as well.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludeCVEs, "excludeCVEs", []string{}, "List of vulnerability ids or CVEs which are excluded from the result, prefer exemptions with justification in the central exemptions file.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnSevereVulnerabilities, "failOnSevereVulnerabilities", true, "Whether to fail the step on open vulnerabilities with a CVSS score of 7.0 or higher respectively a high or critical severity.")
+
+	cmd.MarkFlagRequired("sbomPath")
+	cmd.MarkFlagRequired("advisoryDatabasePath")
+}
+
+// retrieve step metadata
+func osvExecuteScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "osvExecuteScan",
+			Aliases:     []config.Alias{},
+			Description: "Matches the CycloneDX bill of materials against a local OSV advisory database",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name: "sbomPath",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/sbomPath",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_sbomPath"),
+					},
+					{
+						Name:        "advisoryDatabasePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_advisoryDatabasePath"),
+					},
+					{
+						Name:        "excludeCVEs",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "failOnSevereVulnerabilities",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "influx",
+						Type: "influx",
+						Parameters: []map[string]interface{}{
+							{"Name": "step_data"}, {"fields": []map[string]string{{"name": "osv"}}},
+							{"Name": "osv_data"}, {"fields": []map[string]string{{"name": "excluded_vulnerabilities"}, {"name": "historical_vulnerabilities"}, {"name": "major_vulnerabilities"}, {"name": "minor_vulnerabilities"}, {"name": "triaged_vulnerabilities"}, {"name": "vulnerabilities"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
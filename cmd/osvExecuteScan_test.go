@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const osvTestBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"type": "library", "group": "org.apache.logging.log4j", "name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+    {"type": "library", "name": "minimist", "version": "1.2.5", "purl": "pkg:npm/minimist@1.2.5"},
+    {"type": "library", "name": "flask", "version": "2.0.1", "purl": "pkg:pypi/flask@2.0.1"}
+  ]
+}`
+
+const osvTestLog4jAdvisory = `{
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.0-beta9"}, {"fixed": "2.15.0"}]}]
+  }]
+}`
+
+const osvTestMinimistAdvisory = `{
+  "id": "GHSA-xvch-5gv4-984h",
+  "aliases": ["CVE-2021-44906"],
+  "summary": "Prototype Pollution in minimist",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "minimist"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.6"}]}]
+  }],
+  "database_specific": {"severity": "MODERATE"}
+}`
+
+func newOsvExecuteScanTestsUtils() *mock.FilesMock {
+	utils := &mock.FilesMock{}
+	utils.AddFile("bom.json", []byte(osvTestBOM))
+	utils.AddFile("advisories/maven/GHSA-jfh8-c2jp-5v3q.json", []byte(osvTestLog4jAdvisory))
+	utils.AddFile("advisories/npm/GHSA-xvch-5gv4-984h.json", []byte(osvTestMinimistAdvisory))
+	return utils
+}
+
+func TestRunOsvExecuteScan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("severe vulnerabilities", func(t *testing.T) {
+		t.Parallel()
+		config := osvExecuteScanOptions{SbomPath: "bom.json", AdvisoryDatabasePath: "advisories", FailOnSevereVulnerabilities: true}
+		influx := osvExecuteScanInflux{}
+		utils := newOsvExecuteScanTestsUtils()
+
+		err := runOsvExecuteScan(&config, utils, &influx, time.Now())
+
+		assert.EqualError(t, err, "1 severe vulnerabilities found")
+		assert.Equal(t, 2, influx.osv_data.fields.vulnerabilities)
+		assert.Equal(t, 1, influx.osv_data.fields.major_vulnerabilities)
+		assert.Equal(t, 1, influx.osv_data.fields.minor_vulnerabilities)
+		assert.True(t, utils.HasWrittenFile(osvExecuteScanReport))
+		assert.True(t, utils.HasWrittenFile(filepath.Join(reporting.StepReportDirectory, "osvExecuteScan.json")))
+
+		content, err := utils.FileRead(filepath.Join(vulnerability.FindingsDirectory, "osvExecuteScan.json"))
+		require.NoError(t, err)
+		findings, err := vulnerability.ReadFindings(content)
+		require.NoError(t, err)
+		if assert.Len(t, findings, 2) {
+			assert.Equal(t, "CVE-2021-44228", findings[0].CVE)
+			assert.Equal(t, 10.0, findings[0].CVSS)
+			assert.Equal(t, []string{"2.15.0"}, findings[0].FixedVersions)
+			assert.Equal(t, "medium", findings[1].Severity)
+		}
+		report, err := utils.FileRead(osvExecuteScanReport)
+		require.NoError(t, err)
+		assert.Contains(t, string(report), "org.apache.logging.log4j:log4j-core")
+		assert.Contains(t, string(report), "<td>2.15.0</td>")
+	})
+
+	t.Run("excluded vulnerability", func(t *testing.T) {
+		t.Parallel()
+		config := osvExecuteScanOptions{SbomPath: "bom.json", AdvisoryDatabasePath: "advisories", ExcludeCVEs: []string{"CVE-2021-44228"}, FailOnSevereVulnerabilities: true}
+		influx := osvExecuteScanInflux{}
+
+		err := runOsvExecuteScan(&config, newOsvExecuteScanTestsUtils(), &influx, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, influx.osv_data.fields.vulnerabilities)
+		assert.Equal(t, 1, influx.osv_data.fields.excluded_vulnerabilities)
+	})
+
+	t.Run("exempted vulnerability", func(t *testing.T) {
+		t.Parallel()
+		config := osvExecuteScanOptions{SbomPath: "bom.json", AdvisoryDatabasePath: "advisories", FailOnSevereVulnerabilities: true}
+		influx := osvExecuteScanInflux{}
+		utils := newOsvExecuteScanTestsUtils()
+		utils.AddFile(vulnerability.ExemptionsFile, []byte(`{"exemptions": [{"id": "CVE-2021-44228", "justification": "JndiLookup class removed", "owner": "security-team", "expires": "2099-12-31"}]}`))
+
+		err := runOsvExecuteScan(&config, utils, &influx, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, influx.osv_data.fields.triaged_vulnerabilities)
+	})
+
+	t.Run("severe vulnerabilities not failing", func(t *testing.T) {
+		t.Parallel()
+		config := osvExecuteScanOptions{SbomPath: "bom.json", AdvisoryDatabasePath: "advisories"}
+
+		err := runOsvExecuteScan(&config, newOsvExecuteScanTestsUtils(), &osvExecuteScanInflux{}, time.Now())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("empty advisory database", func(t *testing.T) {
+		t.Parallel()
+		config := osvExecuteScanOptions{SbomPath: "bom.json", AdvisoryDatabasePath: "missing"}
+
+		err := runOsvExecuteScan(&config, newOsvExecuteScanTestsUtils(), &osvExecuteScanInflux{}, time.Now())
+
+		assert.EqualError(t, err, "no advisories found in 'missing'")
+	})
+
+	t.Run("missing BOM", func(t *testing.T) {
+		t.Parallel()
+		config := osvExecuteScanOptions{SbomPath: "bom.xml", AdvisoryDatabasePath: "advisories"}
+
+		err := runOsvExecuteScan(&config, newOsvExecuteScanTestsUtils(), &osvExecuteScanInflux{}, time.Now())
+
+		assert.Contains(t, err.Error(), "failed to read BOM 'bom.xml'")
+	})
+}
@@ -4,15 +4,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	"github.com/pkg/errors"
 )
 
 type pipelineCreateScanSummaryUtils interface {
+	FileExists(path string) (bool, error)
 	FileRead(path string) ([]byte, error)
 	FileWrite(path string, content []byte, perm os.FileMode) error
 	Glob(pattern string) (matches []string, err error)
@@ -39,6 +46,35 @@ func pipelineCreateScanSummary(config pipelineCreateScanSummaryOptions, telemetr
 }
 
 func runPipelineCreateScanSummary(config *pipelineCreateScanSummaryOptions, telemetryData *telemetry.CustomData, utils pipelineCreateScanSummaryUtils) error {
+	findings, err := readVulnerabilityFindings(utils)
+	if err != nil {
+		return err
+	}
+	exemptions, err := vulnerability.LoadExemptions(utils)
+	if err != nil {
+		return err
+	}
+	// exemptions are applied again in case a scan step did not apply them
+	findings = exemptions.Apply(findings)
+
+	switch config.OutputFormat {
+	case "sarif":
+		err = createSarifSummary(config, utils)
+	case "json":
+		err = createVulnerabilitySummary(config, findings, utils)
+	default:
+		err = createMarkdownSummary(config, findings, exemptions, utils)
+	}
+	if err != nil {
+		return err
+	}
+	if err := exemptions.CheckExpired(time.Now()); err != nil {
+		return err
+	}
+	return checkVulnerabilityQualityGate(config, findings)
+}
+
+func createMarkdownSummary(config *pipelineCreateScanSummaryOptions, findings []vulnerability.Finding, exemptions *vulnerability.Exemptions, utils pipelineCreateScanSummaryUtils) error {
 
 	pattern := reporting.StepReportDirectory + "/*.json"
 	reports, _ := utils.Glob(pattern)
@@ -68,6 +104,12 @@ func runPipelineCreateScanSummary(config *pipelineCreateScanSummaryOptions, tele
 			output = append(output, mdReport...)
 		}
 	}
+	if len(findings) > 0 {
+		output = append(output, vulnerabilitiesToMarkdown(findings)...)
+	}
+	if len(exemptions.Exemptions) > 0 {
+		output = append(output, exemptionsToMarkdown(exemptions, findings, time.Now())...)
+	}
 
 	if err := utils.FileWrite(config.OutputFilePath, output, 0666); err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
@@ -76,3 +118,147 @@ func runPipelineCreateScanSummary(config *pipelineCreateScanSummaryOptions, tele
 
 	return nil
 }
+
+// createSarifSummary merges the SARIF logs of all scan steps into one SARIF log
+func createSarifSummary(config *pipelineCreateScanSummaryOptions, utils pipelineCreateScanSummaryUtils) error {
+	pattern := reporting.StepReportDirectory + "/*.sarif"
+	reports, _ := utils.Glob(pattern)
+
+	sarifLogs := []format.SARIF{}
+	for _, report := range reports {
+		log.Entry().Debugf("reading file %v", report)
+		reportContent, err := utils.FileRead(report)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to read report %v", report)
+		}
+		sarifLog, err := format.ReadSARIF(reportContent)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse report %v", report)
+		}
+		sarifLogs = append(sarifLogs, sarifLog)
+	}
+
+	merged := format.MergeSARIF(sarifLogs...)
+	output, err := merged.ToJSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF log")
+	}
+	if err := utils.FileWrite(config.OutputFilePath, output, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to write %v", config.OutputFilePath)
+	}
+	return nil
+}
+
+// readVulnerabilityFindings reads the findings of all scan steps and removes duplicates reported by several scanners
+func readVulnerabilityFindings(utils pipelineCreateScanSummaryUtils) ([]vulnerability.Finding, error) {
+	files, _ := utils.Glob(vulnerability.FindingsDirectory + "/*.json")
+
+	findings := []vulnerability.Finding{}
+	for _, file := range files {
+		log.Entry().Debugf("reading file %v", file)
+		content, err := utils.FileRead(file)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to read findings %v", file)
+		}
+		stepFindings, err := vulnerability.ReadFindings(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse findings %v", file)
+		}
+		findings = append(findings, stepFindings...)
+	}
+
+	consolidated := vulnerability.Deduplicate(findings)
+	log.Entry().Debugf("%v findings consolidated into %v", len(findings), len(consolidated))
+	// most severe findings first
+	sort.SliceStable(consolidated, func(i, j int) bool {
+		return consolidated[i].CVSS > consolidated[j].CVSS
+	})
+	return consolidated, nil
+}
+
+// createVulnerabilitySummary writes the consolidated findings of all scan steps
+func createVulnerabilitySummary(config *pipelineCreateScanSummaryOptions, findings []vulnerability.Finding, utils pipelineCreateScanSummaryUtils) error {
+	output, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal findings")
+	}
+	if err := utils.FileWrite(config.OutputFilePath, output, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to write %v", config.OutputFilePath)
+	}
+	return nil
+}
+
+func vulnerabilitiesToMarkdown(findings []vulnerability.Finding) []byte {
+	var markdown strings.Builder
+	markdown.WriteString("## Vulnerabilities\n\n")
+	markdown.WriteString("| Vulnerability | Component / Location | CVSS | Severity | Tools | Status |\n")
+	markdown.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, finding := range findings {
+		location := finding.Location.File
+		if finding.Component != nil {
+			location = strings.TrimSpace(fmt.Sprintf("%v %v", finding.Component.Name, finding.Component.Version))
+		} else if finding.Location.Line > 0 {
+			location = fmt.Sprintf("%v:%v", location, finding.Location.Line)
+		}
+		cvss := ""
+		if finding.CVSS > 0 {
+			cvss = fmt.Sprintf("%.1f", finding.CVSS)
+		}
+		markdown.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | %v |\n", finding.ID, location, cvss, finding.EffectiveSeverity(), strings.Join(finding.Tools, ", "), finding.Status))
+	}
+	markdown.WriteString("\n")
+	return []byte(markdown.String())
+}
+
+// exemptionsToMarkdown creates the audit report of the exemptions and the findings they are applied to
+func exemptionsToMarkdown(exemptions *vulnerability.Exemptions, findings []vulnerability.Finding, now time.Time) []byte {
+	applied := map[*vulnerability.Exemption][]string{}
+	for _, finding := range findings {
+		if len(finding.ExemptedBy) == 0 {
+			continue
+		}
+		if exemption := exemptions.Match(&finding); exemption != nil {
+			applied[exemption] = append(applied[exemption], finding.ID)
+		}
+	}
+
+	var markdown strings.Builder
+	markdown.WriteString(fmt.Sprintf("## Exemptions\n\nExemptions as defined in `%v`.\n\n", vulnerability.ExemptionsFile))
+	markdown.WriteString("| Exemption | Component | Owner | Expires | Justification | Exempted findings |\n")
+	markdown.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for i := range exemptions.Exemptions {
+		exemption := &exemptions.Exemptions[i]
+		component := strings.TrimSpace(fmt.Sprintf("%v %v", exemption.Component, exemption.Version))
+		expires := exemption.Expires
+		if exemption.IsExpired(now) {
+			expires += " (expired)"
+		}
+		markdown.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | %v |\n", exemption.ID, component, exemption.Owner, expires, exemption.Justification, len(applied[exemption])))
+	}
+	markdown.WriteString("\n")
+	return []byte(markdown.String())
+}
+
+// checkVulnerabilityQualityGate fails in case open consolidated findings reach the cvssSeverityLimit
+func checkVulnerabilityQualityGate(config *pipelineCreateScanSummaryOptions, findings []vulnerability.Finding) error {
+	if len(config.CvssSeverityLimit) == 0 {
+		return nil
+	}
+	cvssSeverityLimit, err := strconv.ParseFloat(config.CvssSeverityLimit, 64)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("failed to parse parameter cvssSeverityLimit (%v) as floating point number: %w", config.CvssSeverityLimit, err)
+	}
+	if cvssSeverityLimit < 0 {
+		return nil
+	}
+	if severe := vulnerability.Severe(findings, cvssSeverityLimit); len(severe) > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v open vulnerabilities with CVSS score greater or equal to %.1f detected", len(severe), cvssSeverityLimit)
+	}
+	return nil
+}
@@ -9,15 +9,18 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
 type pipelineCreateScanSummaryOptions struct {
-	FailedOnly     bool   `json:"failedOnly,omitempty"`
-	OutputFilePath string `json:"outputFilePath,omitempty"`
-	PipelineLink   string `json:"pipelineLink,omitempty"`
+	CvssSeverityLimit string `json:"cvssSeverityLimit,omitempty"`
+	FailedOnly        bool   `json:"failedOnly,omitempty"`
+	OutputFilePath    string `json:"outputFilePath,omitempty"`
+	OutputFormat      string `json:"outputFormat,omitempty"`
+	PipelineLink      string `json:"pipelineLink,omitempty"`
 }
 
 // PipelineCreateScanSummaryCommand Collect scan result information anc create a summary report
@@ -34,7 +37,15 @@ func PipelineCreateScanSummaryCommand() *cobra.Command {
 		Short: "Collect scan result information anc create a summary report",
 		Long: `This step allows you to create a summary report of your scan results.
 
-It is for example used to create a markdown file which can be used to create a GitHub issue.`,
+It is for example used to create a markdown file which can be used to create a GitHub issue.
+
+With ` + "`" + `outputFormat: sarif` + "`" + ` the SARIF logs written by the scan steps (e.g. fortifyExecuteScan, checkmarxExecuteScan, sonarExecuteScan, whitesourceExecuteScan, protecodeExecuteScan, detectExecuteScan) are merged into one SARIF 2.1.0 log.
+Runs of the same tool are combined, the tool descriptors contain the metadata of all rules.
+The merged log can for example be uploaded to GitHub code scanning.
+
+The vulnerabilities found by the scan steps are consolidated into one list: a vulnerability reported by several scanners for the same CVE and component is listed only once.
+The consolidated list is added to the markdown summary, ` + "`" + `outputFormat: json` + "`" + ` writes it as JSON.
+With ` + "`" + `cvssSeverityLimit` + "`" + ` the step acts as quality gate across all scanners.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -67,8 +78,10 @@ It is for example used to create a markdown file which can be used to create a G
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -95,8 +108,10 @@ It is for example used to create a markdown file which can be used to create a G
 }
 
 func addPipelineCreateScanSummaryFlags(cmd *cobra.Command, stepConfig *pipelineCreateScanSummaryOptions) {
+	cmd.Flags().StringVar(&stepConfig.CvssSeverityLimit, "cvssSeverityLimit", `-1`, "Limit of the CVSS score for the consolidated vulnerabilities of all scan steps. The step fails in case an open vulnerability reaches the limit. Findings without CVSS score fail in case of a high or critical severity. A negative value disables the check.")
 	cmd.Flags().BoolVar(&stepConfig.FailedOnly, "failedOnly", false, "Defines if only failed scans should be included into the summary.")
 	cmd.Flags().StringVar(&stepConfig.OutputFilePath, "outputFilePath", `scanSummary.md`, "Defines the filepath to the target file which will be created by the step.")
+	cmd.Flags().StringVar(&stepConfig.OutputFormat, "outputFormat", `markdown`, "Defines the format of the summary. `sarif` merges the SARIF logs of the scan steps, `json` writes the consolidated vulnerabilities of the scan steps. In these cases `outputFilePath` should be set accordingly, e.g. to `scanSummary.sarif`.")
 	cmd.Flags().StringVar(&stepConfig.PipelineLink, "pipelineLink", os.Getenv("PIPER_pipelineLink"), "Link to the pipeline (e.g. Jenkins job url) for reference in the scan summary.")
 
 }
@@ -112,6 +127,15 @@ func pipelineCreateScanSummaryMetadata() config.StepData {
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
 				Parameters: []config.StepParameters{
+					{
+						Name:        "cvssSeverityLimit",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `-1`,
+					},
 					{
 						Name:        "failedOnly",
 						ResourceRef: []config.ResourceReference{},
@@ -130,6 +154,16 @@ func pipelineCreateScanSummaryMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `scanSummary.md`,
 					},
+					{
+						Name:           "outputFormat",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `markdown`,
+						PossibleValues: []interface{}{"json", "markdown", "sarif"},
+					},
 					{
 						Name:        "pipelineLink",
 						ResourceRef: []config.ResourceReference{},
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type pipelineCreateScanSummaryMockUtils struct {
@@ -127,4 +130,222 @@ func TestRunPipelineCreateScanSummary(t *testing.T) {
 		// so far mock cannot create error
 	})
 
+	t.Run("success - sarif", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.sarif",
+			OutputFormat:   "sarif",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step1.json", []byte(`{"title":"Title Scan 1"}`))
+		utils.AddFile(".pipeline/stepReports/whitesourceExecuteScan_oss_1.sarif", []byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"WhiteSource","rules":[{"id":"CVE-1"}]}},"results":[{"ruleId":"CVE-1","ruleIndex":0,"message":{"text":"CVE-1 in lib"}}]}]}`))
+		utils.AddFile(".pipeline/stepReports/whitesourceExecuteScan_oss_2.sarif", []byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"WhiteSource","rules":[{"id":"CVE-2"}]}},"results":[{"ruleId":"CVE-2","ruleIndex":0,"message":{"text":"CVE-2 in lib"}}]}]}`))
+		utils.AddFile(".pipeline/stepReports/sonarExecuteScan.sarif", []byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"SonarQube","rules":[{"id":"go:S1234"}]}},"results":[]}]}`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, err := utils.FileRead("scanSummary.sarif")
+		require.NoError(t, err)
+		sarif, err := format.ReadSARIF(fileContent)
+		require.NoError(t, err)
+		assert.Equal(t, format.SarifSchema, sarif.Schema)
+		require.Len(t, sarif.Runs, 2)
+		assert.Equal(t, "SonarQube", sarif.Runs[0].Tool.Driver.Name)
+		assert.Equal(t, "WhiteSource", sarif.Runs[1].Tool.Driver.Name)
+		assert.Len(t, sarif.Runs[1].Tool.Driver.Rules, 2)
+		require.Len(t, sarif.Runs[1].Results, 2)
+		assert.Equal(t, 1, sarif.Runs[1].Results[1].RuleIndex)
+	})
+
+	t.Run("error - unmarshal sarif", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.sarif",
+			OutputFormat:   "sarif",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step1.sarif", []byte(`{"version":"2.1.0"`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse report .pipeline/stepReports/step1.sarif")
+	})
+
+	t.Run("success - consolidated vulnerabilities", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.md",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step1.json", []byte(`{"title":"Title Scan 1"}`))
+		utils.AddFile(".pipeline/vulnerabilities/whitesourceExecuteScan_oss_1.json", []byte(testFindingsWhitesource))
+		utils.AddFile(".pipeline/vulnerabilities/protecodeExecuteScan.json", []byte(testFindingsProtecode))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, _ := utils.FileRead("scanSummary.md")
+		fileContentString := string(fileContent)
+		assert.Contains(t, fileContentString, "Title Scan 1")
+		assert.Contains(t, fileContentString, "## Vulnerabilities")
+		assert.Contains(t, fileContentString, "| CVE-2021-44228 | log4j-core 2.14.1 | 10.0 | critical | Protecode, WhiteSource | open |")
+		assert.Contains(t, fileContentString, "| CVE-2020-1234 | lodash 4.17.15 | 5.3 | medium | WhiteSource | suppressed |")
+	})
+
+	t.Run("success - json", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "vulnerabilities.json",
+			OutputFormat:   "json",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/vulnerabilities/whitesourceExecuteScan_oss_1.json", []byte(testFindingsWhitesource))
+		utils.AddFile(".pipeline/vulnerabilities/protecodeExecuteScan.json", []byte(testFindingsProtecode))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, err := utils.FileRead("vulnerabilities.json")
+		require.NoError(t, err)
+		findings, err := vulnerability.ReadFindings(fileContent)
+		require.NoError(t, err)
+		require.Len(t, findings, 2)
+		assert.Equal(t, "CVE-2021-44228", findings[0].ID)
+		assert.Equal(t, []string{"Protecode", "WhiteSource"}, findings[0].Tools)
+		assert.Equal(t, "CVE-2020-1234", findings[1].ID)
+	})
+
+	t.Run("error - cvssSeverityLimit exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:    "scanSummary.md",
+			CvssSeverityLimit: "7",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/vulnerabilities/whitesourceExecuteScan_oss_1.json", []byte(testFindingsWhitesource))
+		utils.AddFile(".pipeline/vulnerabilities/protecodeExecuteScan.json", []byte(testFindingsProtecode))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.EqualError(t, err, "1 open vulnerabilities with CVSS score greater or equal to 7.0 detected")
+		reportExists, _ := utils.FileExists("scanSummary.md")
+		assert.True(t, reportExists)
+	})
+
+	t.Run("success - cvssSeverityLimit not exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:    "scanSummary.md",
+			CvssSeverityLimit: "5",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/vulnerabilities/whitesourceExecuteScan_oss_1.json", []byte(`[{"id":"CVE-2020-1234","cvss":5.3,"tools":["WhiteSource"],"status":"suppressed"}]`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - invalid cvssSeverityLimit", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:    "scanSummary.md",
+			CvssSeverityLimit: "high",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse parameter cvssSeverityLimit (high)")
+	})
+
+	t.Run("success - exemptions", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:    "scanSummary.md",
+			CvssSeverityLimit: "7",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/vulnerabilities/whitesourceExecuteScan_oss_1.json", []byte(testFindingsWhitesource))
+		utils.AddFile(".pipeline/vulnerabilities/protecodeExecuteScan.json", []byte(testFindingsProtecode))
+		utils.AddFile(".pipeline/exemptions.yml", []byte(`exemptions:
+  - id: CVE-2021-44228
+    component: log4j-core
+    justification: JNDI lookup disabled
+    owner: security-team
+    expires: 2099-12-31
+  - id: CVE-2019-0001
+    justification: not reachable
+    owner: jane.doe
+    expires: 2099-12-31
+`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, _ := utils.FileRead("scanSummary.md")
+		fileContentString := string(fileContent)
+		assert.Contains(t, fileContentString, "| CVE-2021-44228 | log4j-core 2.14.1 | 10.0 | critical | Protecode, WhiteSource | suppressed |")
+		assert.Contains(t, fileContentString, "## Exemptions")
+		assert.Contains(t, fileContentString, "| CVE-2021-44228 | log4j-core | security-team | 2099-12-31 | JNDI lookup disabled | 1 |")
+		assert.Contains(t, fileContentString, "| CVE-2019-0001 |  | jane.doe | 2099-12-31 | not reachable | 0 |")
+	})
+
+	t.Run("error - expired exemptions", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.md",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/exemptions.yml", []byte("exemptions:\n  - id: CVE-2019-0001\n    justification: not reachable\n    owner: jane.doe\n    expires: 2020-01-31\n"))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.EqualError(t, err, "1 exemptions in '.pipeline/exemptions.yml' are expired, please review them: CVE-2019-0001 (owner: jane.doe, expired: 2020-01-31)")
+		fileContent, _ := utils.FileRead("scanSummary.md")
+		assert.Contains(t, string(fileContent), "| CVE-2019-0001 |  | jane.doe | 2020-01-31 (expired) | not reachable | 0 |")
+	})
+
+	t.Run("error - unmarshal findings", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.md",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/vulnerabilities/step1.json", []byte(`[{"id":"CVE-1"`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse findings .pipeline/vulnerabilities/step1.json")
+	})
 }
+
+const testFindingsWhitesource = `[
+	{"id":"CVE-2020-1234","cve":"CVE-2020-1234","component":{"name":"lodash","version":"4.17.15"},"cvss":5.3,"tools":["WhiteSource"],"status":"suppressed"},
+	{"id":"CVE-2021-44228","cve":"CVE-2021-44228","component":{"group":"org.apache.logging.log4j","name":"log4j-core","version":"2.14.1"},"cvss":9.8,"tools":["WhiteSource"],"status":"open"}
+]`
+
+const testFindingsProtecode = `[
+	{"id":"CVE-2021-44228","cve":"CVE-2021-44228","component":{"name":"log4j-core","version":"2.14.1"},"cvss":10.0,"tools":["Protecode"],"status":"open"}
+]`
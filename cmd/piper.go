@@ -77,6 +77,8 @@ func Execute() {
 
 	rootCmd.AddCommand(ArtifactPrepareVersionCommand())
 	rootCmd.AddCommand(ConfigCommand())
+	rootCmd.AddCommand(CheckConfigCommand())
+	rootCmd.AddCommand(EncryptSecretCommand())
 	rootCmd.AddCommand(ContainerSaveImageCommand())
 	rootCmd.AddCommand(CommandLineCompletionCommand())
 	rootCmd.AddCommand(VersionCommand())
@@ -92,6 +94,7 @@ func Execute() {
 	rootCmd.AddCommand(GithubCreateIssueCommand())
 	rootCmd.AddCommand(GithubCreatePullRequestCommand())
 	rootCmd.AddCommand(GithubPublishReleaseCommand())
+	rootCmd.AddCommand(GithubPublishScanResultsCommand())
 	rootCmd.AddCommand(GithubSetCommitStatusCommand())
 	rootCmd.AddCommand(GitopsUpdateDeploymentCommand())
 	rootCmd.AddCommand(CloudFoundryDeleteServiceCommand())
@@ -157,6 +160,11 @@ func Execute() {
 	rootCmd.AddCommand(WritePipelineEnv())
 	rootCmd.AddCommand(ReadPipelineEnv())
 	rootCmd.AddCommand(InfluxWriteDataCommand())
+	rootCmd.AddCommand(PolicyEvaluateCommand())
+	rootCmd.AddCommand(JenkinsTriggerJobCommand())
+	rootCmd.AddCommand(ArtifactCreateSBOMCommand())
+	rootCmd.AddCommand(OsvExecuteScanCommand())
+	rootCmd.AddCommand(LicenseCheckComplianceCommand())
 
 	addRootFlags(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
@@ -262,6 +270,7 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 		GeneralConfig.VaultToken = os.Getenv("PIPER_vaultToken")
 	}
 	myConfig.SetVaultCredentials(GeneralConfig.VaultRoleID, GeneralConfig.VaultRoleSecretID, GeneralConfig.VaultToken)
+	myConfig.SetCommonPipelineEnvironmentPath(filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment"))
 
 	if len(GeneralConfig.StepConfigJSON) != 0 {
 		// ignore config & defaults in favor of passed stepConfigJSON
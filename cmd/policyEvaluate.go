@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/policy"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const policyEvaluateReport = "policyEvaluate.md"
+
+// input kinds, policies apply to the input kind equal to the first segment of their package
+const (
+	policyInputTerraform    = "terraform"
+	policyInputKubernetes   = "kubernetes"
+	policyInputCloudFoundry = "cloudfoundry"
+)
+
+type policyEvaluateUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	DirExists(path string) (bool, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+	ReadManifest(name string) (cloudfoundry.Manifest, error)
+}
+
+type policyEvaluateUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (p *policyEvaluateUtilsBundle) ReadManifest(name string) (cloudfoundry.Manifest, error) {
+	return cloudfoundry.ReadManifest(name)
+}
+
+// policyInput defines a document policies are evaluated against
+type policyInput struct {
+	kind     string
+	source   string
+	document interface{}
+}
+
+func newPolicyEvaluateUtils() policyEvaluateUtils {
+	return &policyEvaluateUtilsBundle{Files: &piperutils.Files{}}
+}
+
+func policyEvaluate(config policyEvaluateOptions, telemetryData *telemetry.CustomData) {
+	utils := newPolicyEvaluateUtils()
+
+	err := runPolicyEvaluate(&config, telemetryData, utils)
+	// the report is also archived in case of violations
+	if exists, _ := piperutils.FileExists(policyEvaluateReport); exists {
+		piperutils.PersistReportsAndLinks("policyEvaluate", "", []piperutils.Path{{Name: "Policy Evaluation", Target: policyEvaluateReport}}, nil)
+	}
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runPolicyEvaluate(config *policyEvaluateOptions, _ *telemetry.CustomData, utils policyEvaluateUtils) error {
+	modules, err := readPolicies(config.Policies, utils)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := readPolicyInputs(config, utils)
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		log.Entry().Warn("No terraform plans, Kubernetes manifests or Cloud Foundry manifests found to evaluate policies against")
+	}
+
+	findings := map[*policyInput][]policy.Finding{}
+	for i := range inputs {
+		input := &inputs[i]
+		for _, module := range modules {
+			if !policyAppliesTo(module.Package, input.kind) {
+				continue
+			}
+			moduleFindings, err := module.Evaluate(input.document)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return errors.Wrapf(err, "failed to evaluate policies against '%v'", input.source)
+			}
+			findings[input] = append(findings[input], moduleFindings...)
+		}
+	}
+
+	violations, warnings := 0, 0
+	for _, inputFindings := range findings {
+		for _, finding := range inputFindings {
+			if finding.Decision == policy.Deny {
+				violations++
+			} else {
+				warnings++
+			}
+		}
+	}
+	log.Entry().Infof("Evaluated %v policies against %v inputs: %v violations, %v warnings", len(modules), len(inputs), violations, warnings)
+
+	report := policyReport(config, modules, inputs, findings, violations, warnings)
+	if err := writePolicyReport(report, utils); err != nil {
+		return err
+	}
+
+	if violations > 0 && config.Mode == "enforce" {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v policy violation(s) found", violations)
+	}
+	return nil
+}
+
+func readPolicies(patterns []string, utils policyEvaluateUtils) ([]*policy.Module, error) {
+	files, err := globFiles(patterns, utils)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no policies found for patterns %v", patterns)
+	}
+	modules := []*policy.Module{}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read policy '%v'", file)
+		}
+		module, err := policy.ParseModule(file, string(content))
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+func readPolicyInputs(config *policyEvaluateOptions, utils policyEvaluateUtils) ([]policyInput, error) {
+	inputs := []policyInput{}
+
+	planFiles, err := globFiles(config.TerraformPlanFiles, utils)
+	if err != nil {
+		return nil, err
+	}
+	for _, planFile := range planFiles {
+		content, err := utils.FileRead(planFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read terraform plan '%v'", planFile)
+		}
+		var document interface{}
+		if err := json.Unmarshal(content, &document); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to parse terraform plan '%v'", planFile)
+		}
+		inputs = append(inputs, policyInput{kind: policyInputTerraform, source: planFile, document: document})
+	}
+
+	manifestFiles, err := globFiles(config.KubernetesManifests, utils)
+	if err != nil {
+		return nil, err
+	}
+	for _, manifestFile := range manifestFiles {
+		content, err := utils.FileRead(manifestFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read Kubernetes manifest '%v'", manifestFile)
+		}
+		documents, err := splitYAMLDocuments(content)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to parse Kubernetes manifest '%v'", manifestFile)
+		}
+		for i, document := range documents {
+			source := manifestFile
+			if len(documents) > 1 {
+				source = fmt.Sprintf("%v (document %v)", manifestFile, i+1)
+			}
+			inputs = append(inputs, policyInput{kind: policyInputKubernetes, source: source, document: document})
+		}
+	}
+
+	cfManifestFiles, err := globFiles(config.CfManifests, utils)
+	if err != nil {
+		return nil, err
+	}
+	for _, cfManifestFile := range cfManifestFiles {
+		manifest, err := utils.ReadManifest(cfManifestFile)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to read Cloud Foundry manifest '%v'", cfManifestFile)
+		}
+		applications, err := manifest.GetApplications()
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to read applications of Cloud Foundry manifest '%v'", cfManifestFile)
+		}
+		apps := []interface{}{}
+		for _, application := range applications {
+			apps = append(apps, application)
+		}
+		inputs = append(inputs, policyInput{kind: policyInputCloudFoundry, source: cfManifestFile, document: map[string]interface{}{"applications": apps}})
+	}
+	return inputs, nil
+}
+
+func globFiles(patterns []string, utils policyEvaluateUtils) ([]string, error) {
+	files := []string{}
+	for _, pattern := range patterns {
+		if len(pattern) == 0 {
+			continue
+		}
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "invalid file pattern '%v'", pattern)
+		}
+		for _, match := range matches {
+			if !piperutils.ContainsString(files, match) {
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+// splitYAMLDocuments converts the documents of a multi document YAML file into JSON compatible values, empty documents are skipped
+func splitYAMLDocuments(content []byte) ([]interface{}, error) {
+	documents := []interface{}{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+		// the JSON round trip converts e.g. integers into float64 as for terraform plans
+		jsonContent, err := json.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		var jsonDocument interface{}
+		if err := json.Unmarshal(jsonContent, &jsonDocument); err != nil {
+			return nil, err
+		}
+		documents = append(documents, jsonDocument)
+	}
+	return documents, nil
+}
+
+func policyAppliesTo(packageName, inputKind string) bool {
+	kind := strings.SplitN(packageName, ".", 2)[0]
+	switch kind {
+	case policyInputTerraform, policyInputKubernetes, policyInputCloudFoundry:
+		return kind == inputKind
+	}
+	return true
+}
+
+func policyReport(config *policyEvaluateOptions, modules []*policy.Module, inputs []policyInput, findings map[*policyInput][]policy.Finding, violations, warnings int) reporting.ScanReport {
+	report := reporting.ScanReport{
+		StepName: "policyEvaluate",
+		Title:    "Policy Evaluation",
+		Subheaders: []reporting.Subheader{
+			{Description: "Mode", Details: config.Mode},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Policies evaluated", Details: fmt.Sprint(len(modules))},
+			{Description: "Inputs evaluated", Details: fmt.Sprint(len(inputs))},
+			{Description: "Violations", Details: fmt.Sprint(violations)},
+			{Description: "Warnings", Details: fmt.Sprint(warnings)},
+		},
+		SuccessfulScan: violations == 0,
+		DetailTable: reporting.ScanDetailTable{
+			Headers:       []string{"Input", "Policy", "Decision", "Message"},
+			NoRowsMessage: "No policy violations",
+			WithCounter:   true,
+			CounterHeader: "Entry #",
+		},
+	}
+	if violations > 0 {
+		report.Overview[2].Style = reporting.Red
+	}
+	if warnings > 0 {
+		report.Overview[3].Style = reporting.Yellow
+	}
+	for i := range inputs {
+		for _, finding := range findings[&inputs[i]] {
+			style := reporting.ColumnStyle(reporting.Yellow)
+			if finding.Decision == policy.Deny {
+				style = reporting.Red
+			}
+			row := reporting.ScanRow{}
+			row.AddColumn(inputs[i].source, 0)
+			row.AddColumn(finding.Module, 0)
+			row.AddColumn(string(finding.Decision), style)
+			row.AddColumn(finding.Message, 0)
+			report.DetailTable.Rows = append(report.DetailTable.Rows, row)
+		}
+	}
+	return report
+}
+
+func writePolicyReport(report reporting.ScanReport, utils policyEvaluateUtils) error {
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	mdReport, _ := report.ToMarkdown()
+	if err := utils.FileWrite(policyEvaluateReport, mdReport, 0666); err != nil {
+		log.Entry().WithError(err).Warn("failed to write policy report")
+	}
+
+	// JSON reports are used by step pipelineCreateScanSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := report.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return errors.Wrap(err, "failed to create reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "policyEvaluate.json"), jsonReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type policyEvaluateOptions struct {
+	Policies            []string `json:"policies,omitempty"`
+	TerraformPlanFiles  []string `json:"terraformPlanFiles,omitempty"`
+	KubernetesManifests []string `json:"kubernetesManifests,omitempty"`
+	CfManifests         []string `json:"cfManifests,omitempty"`
+	Mode                string   `json:"mode,omitempty"`
+}
+
+// PolicyEvaluateCommand Evaluates policies written in the Rego policy language against infrastructure and deployment descriptors
+func PolicyEvaluateCommand() *cobra.Command {
+	const STEP_NAME = "policyEvaluate"
+
+	metadata := policyEvaluateMetadata()
+	var stepConfig policyEvaluateOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+
+	var createPolicyEvaluateCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Evaluates policies written in the Rego policy language against infrastructure and deployment descriptors",
+		Long: `This step evaluates policies as code against the descriptors of your infrastructure and deployments, e.g. as a gate before they are applied.
+
+The policies are written in the [Rego](https://www.openpolicyagent.org/docs/latest/policy-language/) policy language and are evaluated with the Open Policy Agent library embedded into the step, no Open Policy Agent server is required.
+Following the conventions of conftest, the rules ` + "`" + `deny` + "`" + ` and ` + "`" + `violation` + "`" + ` report violations of a policy while the rule ` + "`" + `warn` + "`" + ` reports warnings.
+
+Policies are evaluated against following inputs, depending on the package of the policy:
+
+* packages starting with ` + "`" + `terraform` + "`" + `: the JSON representation of terraform plans, as written by step [` + "`" + `terraformExecute` + "`" + `](terraformExecute.md)
+* packages starting with ` + "`" + `kubernetes` + "`" + `: every document of the rendered Kubernetes manifests, e.g. the ` + "`" + `renderedManifestFile` + "`" + ` of step [` + "`" + `kubernetesDeploy` + "`" + `](kubernetesDeploy.md) or the ` + "`" + `renderedManifestFile` + "`" + ` of step [` + "`" + `gitopsUpdateDeployment` + "`" + `](gitopsUpdateDeployment.md)
+* packages starting with ` + "`" + `cloudfoundry` + "`" + `: Cloud Foundry manifests in the form ` + "`" + `{"applications": [...]}` + "`" + `
+* all other packages: all of the inputs above
+
+The findings are written into a report which is picked up by step [` + "`" + `pipelineCreateScanSummary` + "`" + `](pipelineCreateScanSummary.md).
+With ` + "`" + `mode: enforce` + "`" + ` the step fails in case of violations, with ` + "`" + `mode: warn` + "`" + ` violations are only reported.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
+				telemetry.Send(&telemetryData)
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunk.Send(&telemetryData, logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunk.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			policyEvaluate(stepConfig, &telemetryData)
+			telemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addPolicyEvaluateFlags(createPolicyEvaluateCmd, &stepConfig)
+	return createPolicyEvaluateCmd
+}
+
+func addPolicyEvaluateFlags(cmd *cobra.Command, stepConfig *policyEvaluateOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.Policies, "policies", []string{`.pipeline/policies/*.rego`}, "List of file patterns of the policies to evaluate.")
+	cmd.Flags().StringSliceVar(&stepConfig.TerraformPlanFiles, "terraformPlanFiles", []string{`terraformPlan.json`}, "List of file patterns of terraform plans in JSON format.")
+	cmd.Flags().StringSliceVar(&stepConfig.KubernetesManifests, "kubernetesManifests", []string{}, "List of file patterns of rendered Kubernetes manifests, files may contain multiple YAML documents.")
+	cmd.Flags().StringSliceVar(&stepConfig.CfManifests, "cfManifests", []string{}, "List of file patterns of Cloud Foundry manifests.")
+	cmd.Flags().StringVar(&stepConfig.Mode, "mode", `enforce`, "Defines whether violations fail the step (`enforce`) or are only reported (`warn`).")
+
+}
+
+// retrieve step metadata
+func policyEvaluateMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "policyEvaluate",
+			Aliases:     []config.Alias{},
+			Description: "Evaluates policies written in the Rego policy language against infrastructure and deployment descriptors",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "policies",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`.pipeline/policies/*.rego`},
+					},
+					{
+						Name:        "terraformPlanFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`terraformPlan.json`},
+					},
+					{
+						Name:        "kubernetesManifests",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "cfManifests",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:           "mode",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `enforce`,
+						PossibleValues: []interface{}{"enforce", "warn"},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
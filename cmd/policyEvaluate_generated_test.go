@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyEvaluateCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := PolicyEvaluateCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "policyEvaluate", testCmd.Use, "command name incorrect")
+
+}
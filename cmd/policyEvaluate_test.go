@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type policyEvaluateMockUtils struct {
+	*mock.FilesMock
+	cfApplications map[string][]map[string]interface{}
+}
+
+type cfManifestMock struct {
+	cloudfoundry.Manifest
+	applications []map[string]interface{}
+}
+
+func (m cfManifestMock) GetApplications() ([]map[string]interface{}, error) {
+	return m.applications, nil
+}
+
+func (p *policyEvaluateMockUtils) ReadManifest(name string) (cloudfoundry.Manifest, error) {
+	return cfManifestMock{applications: p.cfApplications[name]}, nil
+}
+
+func newPolicyEvaluateTestsUtils() *policyEvaluateMockUtils {
+	utils := policyEvaluateMockUtils{
+		FilesMock:      &mock.FilesMock{},
+		cfApplications: map[string][]map[string]interface{}{},
+	}
+	return &utils
+}
+
+const terraformPolicy = `package terraform.storage
+
+deny[msg] {
+	change := input.resource_changes[_]
+	change.type == "aws_s3_bucket"
+	change.change.after.acl == "public-read"
+	msg := sprintf("bucket '%s' must not be public", [change.address])
+}
+`
+
+const kubernetesPolicy = `package kubernetes.images
+
+deny[msg] {
+	container := input.spec.template.spec.containers[_]
+	endswith(container.image, ":latest")
+	msg := sprintf("%s: container '%s' uses the latest tag", [input.metadata.name, container.name])
+}
+
+warn[msg] {
+	input.kind == "Deployment"
+	input.spec.replicas < 2
+	msg := sprintf("%s: less than 2 replicas", [input.metadata.name])
+}
+`
+
+const cloudFoundryPolicy = `package cloudfoundry
+
+deny[msg] {
+	app := input.applications[_]
+	not app.health_check_type
+	msg := sprintf("%s: health check type is missing", [app.name])
+}
+`
+
+const generalPolicy = `package general
+
+warn[msg] {
+	input.metadata.labels.team == "unknown"
+	msg := "team is unknown"
+}
+`
+
+func TestRunPolicyEvaluate(t *testing.T) {
+	t.Parallel()
+
+	newUtils := func() *policyEvaluateMockUtils {
+		utils := newPolicyEvaluateTestsUtils()
+		utils.AddFile(".pipeline/policies/terraform.rego", []byte(terraformPolicy))
+		utils.AddFile(".pipeline/policies/kubernetes.rego", []byte(kubernetesPolicy))
+		utils.AddFile(".pipeline/policies/cloudfoundry.rego", []byte(cloudFoundryPolicy))
+		utils.AddFile(".pipeline/policies/general.rego", []byte(generalPolicy))
+		utils.AddFile("terraformPlan.json", []byte(`{"resource_changes": [
+			{"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {"acl": "public-read"}}}
+		]}`))
+		utils.AddFile("manifests/app.yaml", []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  labels:
+    team: unknown
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:latest
+---
+`))
+		utils.AddFile("manifest.yml", []byte("applications: []"))
+		utils.cfApplications["manifest.yml"] = []map[string]interface{}{
+			{"name": "app-a", "health_check_type": "http"},
+			{"name": "app-b"},
+		}
+		return utils
+	}
+	defaultConfig := policyEvaluateOptions{
+		Policies:            []string{".pipeline/policies/*.rego"},
+		TerraformPlanFiles:  []string{"terraformPlan.json"},
+		KubernetesManifests: []string{"manifests/*.yaml"},
+		CfManifests:         []string{"manifest.yml"},
+		Mode:                "enforce",
+	}
+
+	t.Run("violations in enforce mode", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig
+		utils := newUtils()
+
+		err := runPolicyEvaluate(&config, nil, utils)
+
+		assert.EqualError(t, err, "3 policy violation(s) found")
+
+		reportContent, err := utils.FileRead(filepath.Join(reporting.StepReportDirectory, "policyEvaluate.json"))
+		require.NoError(t, err)
+		report := reporting.ScanReport{}
+		require.NoError(t, json.Unmarshal(reportContent, &report))
+		assert.Equal(t, "policyEvaluate", report.StepName)
+		assert.False(t, report.SuccessfulScan)
+		assert.Equal(t, "4", report.Overview[0].Details)
+		assert.Equal(t, "4", report.Overview[1].Details)
+		assert.Equal(t, "3", report.Overview[2].Details)
+		assert.Equal(t, "2", report.Overview[3].Details)
+
+		messages := []string{}
+		for _, row := range report.DetailTable.Rows {
+			messages = append(messages, row.Columns[0].Content+": "+row.Columns[3].Content)
+		}
+		assert.Equal(t, []string{
+			"terraformPlan.json: bucket 'aws_s3_bucket.logs' must not be public",
+			"manifests/app.yaml (document 1): team is unknown",
+			"manifests/app.yaml (document 2): app: container 'app' uses the latest tag",
+			"manifests/app.yaml (document 2): app: less than 2 replicas",
+			"manifest.yml: app-b: health check type is missing",
+		}, messages)
+
+		mdReport, err := utils.FileRead(policyEvaluateReport)
+		require.NoError(t, err)
+		assert.Contains(t, string(mdReport), "Policy Evaluation")
+	})
+
+	t.Run("violations in warn mode", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig
+		config.Mode = "warn"
+		utils := newUtils()
+
+		err := runPolicyEvaluate(&config, nil, utils)
+
+		assert.NoError(t, err)
+		exists, _ := utils.FileExists(filepath.Join(reporting.StepReportDirectory, "policyEvaluate.json"))
+		assert.True(t, exists)
+	})
+
+	t.Run("no violations", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig
+		config.TerraformPlanFiles = []string{"missing.json"}
+		config.KubernetesManifests = nil
+		config.CfManifests = nil
+		utils := newUtils()
+
+		err := runPolicyEvaluate(&config, nil, utils)
+
+		assert.NoError(t, err)
+		reportContent, err := utils.FileRead(filepath.Join(reporting.StepReportDirectory, "policyEvaluate.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(reportContent), `"successfulScan":true`)
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig
+		config.Policies = []string{"policies/*.rego"}
+
+		err := runPolicyEvaluate(&config, nil, newUtils())
+
+		assert.EqualError(t, err, "no policies found for patterns [policies/*.rego]")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig
+		utils := newUtils()
+		utils.AddFile(".pipeline/policies/invalid.rego", []byte("deny { true }"))
+
+		err := runPolicyEvaluate(&config, nil, utils)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse policy '.pipeline/policies/invalid.rego'")
+		assert.Contains(t, err.Error(), "package expected")
+	})
+
+	t.Run("invalid terraform plan", func(t *testing.T) {
+		t.Parallel()
+		config := defaultConfig
+		utils := newUtils()
+		utils.AddFile("terraformPlan.json", []byte("no json"))
+
+		err := runPolicyEvaluate(&config, nil, utils)
+
+		assert.Contains(t, err.Error(), "failed to parse terraform plan 'terraformPlan.json'")
+	})
+}
+
+func TestPolicyAppliesTo(t *testing.T) {
+	t.Parallel()
+	assert.True(t, policyAppliesTo("terraform", policyInputTerraform))
+	assert.True(t, policyAppliesTo("terraform.aws", policyInputTerraform))
+	assert.False(t, policyAppliesTo("terraform.aws", policyInputKubernetes))
+	assert.False(t, policyAppliesTo("kubernetes", policyInputCloudFoundry))
+	assert.True(t, policyAppliesTo("cloudfoundry", policyInputCloudFoundry))
+	assert.True(t, policyAppliesTo("main", policyInputKubernetes))
+	assert.True(t, policyAppliesTo("terraformish", policyInputKubernetes))
+}
+
+func TestSplitYAMLDocuments(t *testing.T) {
+	t.Parallel()
+	t.Run("multiple documents", func(t *testing.T) {
+		documents, err := splitYAMLDocuments([]byte("---\na: 1\n---\n# empty\n---\nb: [x]\nc: |\n  text\n  ---\n  more\n...\n---\nd: {e: true}\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"a": 1.0},
+			map[string]interface{}{"b": []interface{}{"x"}, "c": "text\n---\nmore\n"},
+			map[string]interface{}{"d": map[string]interface{}{"e": true}},
+		}, documents)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := splitYAMLDocuments([]byte("a: 1\n---\nb: [x\n"))
+		assert.Error(t, err)
+	})
+}
@@ -17,12 +17,16 @@ import (
 
 	"github.com/GoogleContainerTools/container-diff/pkg/util"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	StepResults "github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 )
 
 const (
@@ -57,6 +61,15 @@ func runProtecodeScan(config *protecodeExecuteScanOptions, influx *protecodeExec
 	//create client for sending api request
 	log.Entry().Debug("Create protecode client")
 	client := createClient(config)
+	if config.ScanSBOM && len(config.FetchURL) == 0 && len(config.FilePath) == 0 {
+		if len(config.SbomPath) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("no BOM available for scanSBOM, please create it during the build or configure sbomPath")
+		}
+		// the BOM lists the components of the image, so the image does not need to be downloaded
+		(*config).FilePath = cyclonedx.JSONPath(config.SbomPath)
+		log.Entry().Infof("Scanning BOM '%v'", config.FilePath)
+	}
 	if len(config.FetchURL) == 0 && len(config.FilePath) == 0 {
 		log.Entry().Debugf("Get docker image: %v, %v, %v, %v", config.ScanImage, config.DockerRegistryURL, config.FilePath, config.IncludeLayers)
 		fileName, filePath, err = getDockerImage(dClient, config)
@@ -231,9 +244,24 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 		reports = append(reports, StepResults.Path{Target: toolRecordFileName})
 	}
 
+	sarifPath := filepath.Join(reporting.StepReportDirectory, "protecodeExecuteScan.sarif")
+	if err := format.WriteSARIF(protecode.ConvertResultToSarif(result.Result, config.ExcludeCVEs, fileName), sarifPath, &StepResults.Files{}); err != nil {
+		log.Entry().WithError(err).Warn("failed to write SARIF report")
+	} else {
+		reports = append(reports, StepResults.Path{Name: "Protecode SARIF Report", Target: sarifPath})
+	}
+	// exemptions of the central exemptions file are applied in addition to excludeCVEs and the triaging in Protecode
+	findings, exemptionErr := vulnerability.ApplyExemptions(protecode.ConvertResultToFindings(result.Result, config.ExcludeCVEs, fileName), &StepResults.Files{}, time.Now())
+	if _, err := vulnerability.WriteFindings(findings, "protecodeExecuteScan", &StepResults.Files{}); err != nil {
+		log.Entry().WithError(err).Warn("failed to write findings")
+	}
+
 	StepResults.PersistReportsAndLinks("protecodeExecuteScan", "", reports, links)
 
-	if config.FailOnSevereVulnerabilities && protecode.HasSevereVulnerabilities(result.Result, config.ExcludeCVEs) {
+	if exemptionErr != nil {
+		return exemptionErr
+	}
+	if config.FailOnSevereVulnerabilities && len(vulnerability.Severe(findings, vulnerability.DefaultSeverityThreshold)) > 0 {
 		log.SetErrorCategory(log.ErrorCompliance)
 		return fmt.Errorf("the product is not compliant")
 	}
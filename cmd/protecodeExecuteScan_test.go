@@ -87,6 +87,7 @@ func (c *DockerClientMock) TarImage(writer io.Writer, image pkgutil.Image) error
 
 func TestRunProtecodeScan(t *testing.T) {
 	requestURI := ""
+	uploadedFiles := []string{}
 	dir, err := ioutil.TempDir("", "t")
 	require.NoError(t, err, "Failed to create temporary directory")
 	// clean up tmp dir
@@ -120,7 +121,8 @@ func TestRunProtecodeScan(t *testing.T) {
 
 		} else if requestURI == "/api/product/4486/pdf-report" {
 
-		} else if requestURI == "/api/upload/t.tar" {
+		} else if requestURI == "/api/upload/t.tar" || requestURI == "/api/upload/bom-docker.json" {
+			uploadedFiles = append(uploadedFiles, strings.TrimPrefix(requestURI, "/api/upload/"))
 			response := protecode.ResultData{Result: protecode.Result{ProductID: 4486, ReportURL: requestURI}}
 
 			var b bytes.Buffer
@@ -159,6 +161,24 @@ func TestRunProtecodeScan(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("With BOM", func(t *testing.T) {
+		bomDir, err := ioutil.TempDir("", "bom")
+		require.NoError(t, err)
+		defer func() { _ = os.RemoveAll(bomDir) }()
+		require.NoError(t, ioutil.WriteFile(filepath.Join(bomDir, "bom-docker.json"), []byte(`{"bomFormat": "CycloneDX"}`), 0644))
+		uploadedFiles = []string{}
+
+		config := protecodeExecuteScanOptions{ServerURL: server.URL, ScanImage: "t", ScanSBOM: true, SbomPath: filepath.Join(bomDir, "bom-docker.xml"), TimeoutMinutes: "1", VerifyOnly: false, CleanupMode: "none", Group: "13", ExcludeCVEs: "CVE-2018-1, CVE-2017-1000382", ReportFileName: "./cache/report-file.txt"}
+		err = runProtecodeScan(&config, &influx, dClient)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bom-docker.json"}, uploadedFiles)
+	})
+
+	t.Run("With BOM - no BOM available", func(t *testing.T) {
+		config := protecodeExecuteScanOptions{ServerURL: server.URL, ScanImage: "t", ScanSBOM: true, TimeoutMinutes: "1", CleanupMode: "none", Group: "13"}
+		err := runProtecodeScan(&config, &influx, dClient)
+		assert.EqualError(t, err, "no BOM available for scanSBOM, please create it during the build or configure sbomPath")
+	})
 }
 
 func TestHandleArtifactVersion(t *testing.T) {
@@ -14,12 +14,14 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	FileUtils "github.com/SAP/jenkins-library/pkg/piperutils"
 	SliceUtils "github.com/SAP/jenkins-library/pkg/piperutils"
 	StepResults "github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	SonarUtils "github.com/SAP/jenkins-library/pkg/sonar"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -232,6 +234,15 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 		return err
 	}
 	log.Entry().Debugf("Influx values: %v", influx.sonarqube_data.fields)
+
+	if issues, err := issueService.GetAllIssues(); err != nil {
+		log.Entry().WithError(err).Warn("failed to fetch issues, no SARIF report created")
+	} else {
+		sarif := SonarUtils.ConvertIssuesToSarif(issues, taskReport.ServerURL, taskReport.ProjectKey)
+		if err := format.WriteSARIF(sarif, filepath.Join(reporting.StepReportDirectory, "sonarExecuteScan.sarif"), &FileUtils.Files{}); err != nil {
+			log.Entry().WithError(err).Warn("failed to write SARIF report")
+		}
+	}
 	err = SonarUtils.WriteReport(SonarUtils.ReportData{
 		ServerURL:    taskReport.ServerURL,
 		ProjectKey:   taskReport.ProjectKey,
@@ -1,17 +1,29 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
 )
 
+const terraformPlanReport = "terraformPlan.md"
+
+// terraformPlanJSONFile contains the plan in JSON format, e.g. as input for step policyEvaluate
+const terraformPlanJSONFile = "terraformPlan.json"
+
 type terraformExecuteUtils interface {
 	command.ExecRunner
 
 	FileExists(filename string) (bool, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
 }
 
 type terraformExecuteUtilsBundle struct {
@@ -19,6 +31,26 @@ type terraformExecuteUtilsBundle struct {
 	*piperutils.Files
 }
 
+// terraformPlan defines the relevant parts of the JSON output of 'terraform show -json <planFile>'
+type terraformPlan struct {
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+}
+
+type terraformResourceChange struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Change  struct {
+		Actions []string `json:"actions"`
+	} `json:"change"`
+}
+
+// terraformPlanSummary defines the number of resources affected by a plan
+type terraformPlanSummary struct {
+	Add     int
+	Change  int
+	Destroy int
+}
+
 func newTerraformExecuteUtils() terraformExecuteUtils {
 	utils := terraformExecuteUtilsBundle{
 		Command: &command.Command{},
@@ -30,31 +62,193 @@ func newTerraformExecuteUtils() terraformExecuteUtils {
 	return &utils
 }
 
-func terraformExecute(config terraformExecuteOptions, telemetryData *telemetry.CustomData) {
+func terraformExecute(config terraformExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *terraformExecuteCommonPipelineEnvironment) {
 	utils := newTerraformExecuteUtils()
 
-	err := runTerraformExecute(&config, telemetryData, utils)
+	err := runTerraformExecute(&config, telemetryData, utils, commonPipelineEnvironment)
+	// the plan report is also archived in case the plan is rejected, e.g. due to resources to destroy
+	if len(commonPipelineEnvironment.custom.terraformPlanFile) > 0 {
+		if exists, _ := utils.FileExists(terraformPlanReport); exists {
+			piperutils.PersistReportsAndLinks("terraformExecute", "", []piperutils.Path{{Name: "Terraform Plan", Target: terraformPlanReport}}, nil)
+		}
+	}
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runTerraformExecute(config *terraformExecuteOptions, telemetryData *telemetry.CustomData, utils terraformExecuteUtils) error {
+func runTerraformExecute(config *terraformExecuteOptions, telemetryData *telemetry.CustomData, utils terraformExecuteUtils, commonPipelineEnvironment *terraformExecuteCommonPipelineEnvironment) error {
+	if config.Init || config.Command == "init" {
+		if err := runTerraformInit(config, utils); err != nil {
+			return err
+		}
+		if config.Command == "init" {
+			return nil
+		}
+	}
+
+	if len(config.Workspace) > 0 {
+		if err := selectTerraformWorkspace(config.Workspace, utils); err != nil {
+			return err
+		}
+	}
+
 	args := []string{config.Command}
 
 	if config.Command == "apply" {
 		args = append(args, "-auto-approve")
 	}
 
-	if (config.Command == "apply" || config.Command == "plan") && config.TerraformSecrets != "" {
+	// variables are contained in a saved plan and must not be passed again when applying it
+	applyPlan := config.Command == "apply" && len(config.PlanFile) > 0
+	if (config.Command == "apply" || config.Command == "plan") && config.TerraformSecrets != "" && !applyPlan {
 		args = append(args, fmt.Sprintf("-var-file=%s", config.TerraformSecrets))
 	}
 
+	if config.Command == "plan" && len(config.PlanFile) > 0 {
+		args = append(args, fmt.Sprintf("-out=%s", config.PlanFile))
+	}
+
 	if config.AdditionalArgs != nil {
 		args = append(args, config.AdditionalArgs...)
 	}
 
-	utils.RunExecutable("terraform", args...)
+	if applyPlan {
+		if exists, _ := utils.FileExists(config.PlanFile); !exists {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("plan file '%s' does not exist", config.PlanFile)
+		}
+		args = append(args, config.PlanFile)
+	}
+
+	if err := utils.RunExecutable("terraform", args...); err != nil {
+		return errors.Wrapf(err, "failed to execute 'terraform %s'", config.Command)
+	}
+
+	if config.Command == "plan" && len(config.PlanFile) > 0 {
+		commonPipelineEnvironment.custom.terraformPlanFile = config.PlanFile
+		return reportTerraformPlan(config, utils, commonPipelineEnvironment)
+	}
+	return nil
+}
+
+func runTerraformInit(config *terraformExecuteOptions, utils terraformExecuteUtils) error {
+	args := []string{"init", "-input=false"}
+	if len(config.TerraformBackendConfig) > 0 {
+		args = append(args, fmt.Sprintf("-backend-config=%s", config.TerraformBackendConfig))
+	}
+	if config.Command == "init" && config.AdditionalArgs != nil {
+		args = append(args, config.AdditionalArgs...)
+	}
+	if err := utils.RunExecutable("terraform", args...); err != nil {
+		return errors.Wrap(err, "failed to execute 'terraform init'")
+	}
+	return nil
+}
+
+// selectTerraformWorkspace selects the workspace and creates it in case it does not exist yet
+func selectTerraformWorkspace(workspace string, utils terraformExecuteUtils) error {
+	if err := utils.RunExecutable("terraform", "workspace", "select", workspace); err != nil {
+		log.Entry().Infof("Workspace '%s' could not be selected, creating it", workspace)
+		if err := utils.RunExecutable("terraform", "workspace", "new", workspace); err != nil {
+			return errors.Wrapf(err, "failed to create workspace '%s'", workspace)
+		}
+	}
+	return nil
+}
+
+func reportTerraformPlan(config *terraformExecuteOptions, utils terraformExecuteUtils, commonPipelineEnvironment *terraformExecuteCommonPipelineEnvironment) error {
+	var planJSON bytes.Buffer
+	utils.Stdout(&planJSON)
+	err := utils.RunExecutable("terraform", "show", "-json", config.PlanFile)
+	utils.Stdout(log.Writer())
+	if err != nil {
+		return errors.Wrapf(err, "failed to show plan file '%s'", config.PlanFile)
+	}
+
+	if err := utils.FileWrite(terraformPlanJSONFile, planJSON.Bytes(), 0666); err != nil {
+		return errors.Wrapf(err, "failed to write plan to '%s'", terraformPlanJSONFile)
+	}
 
+	plan := terraformPlan{}
+	if err := json.Unmarshal(planJSON.Bytes(), &plan); err != nil {
+		return errors.Wrapf(err, "failed to parse plan file '%s'", config.PlanFile)
+	}
+
+	summary := plan.summary()
+	commonPipelineEnvironment.custom.terraformPlanAdd = summary.Add
+	commonPipelineEnvironment.custom.terraformPlanChange = summary.Change
+	commonPipelineEnvironment.custom.terraformPlanDestroy = summary.Destroy
+	log.Entry().Infof("Plan: %d to add, %d to change, %d to destroy.", summary.Add, summary.Change, summary.Destroy)
+
+	report := plan.toReport(summary)
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	mdReport, _ := report.ToMarkdown()
+	if err := utils.FileWrite(terraformPlanReport, mdReport, 0666); err != nil {
+		log.Entry().WithError(err).Warn("failed to write plan report")
+	}
+
+	if config.FailOnDestroy && summary.Destroy > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("plan contains %d resources to destroy", summary.Destroy)
+	}
 	return nil
 }
+
+// summary counts the affected resources the same way terraform does, i.e. a replacement counts as add and destroy
+func (p *terraformPlan) summary() terraformPlanSummary {
+	summary := terraformPlanSummary{}
+	for _, resourceChange := range p.ResourceChanges {
+		for _, action := range resourceChange.Change.Actions {
+			switch action {
+			case "create":
+				summary.Add++
+			case "update":
+				summary.Change++
+			case "delete":
+				summary.Destroy++
+			}
+		}
+	}
+	return summary
+}
+
+func (p *terraformPlan) toReport(summary terraformPlanSummary) reporting.ScanReport {
+	report := reporting.ScanReport{
+		StepName: "terraformExecute",
+		Title:    "Terraform Plan",
+		Overview: []reporting.OverviewRow{
+			{Description: "Resources to add", Details: fmt.Sprint(summary.Add)},
+			{Description: "Resources to change", Details: fmt.Sprint(summary.Change)},
+			{Description: "Resources to destroy", Details: fmt.Sprint(summary.Destroy)},
+		},
+		SuccessfulScan: summary.Destroy == 0,
+		DetailTable: reporting.ScanDetailTable{
+			Headers:       []string{"Resource", "Type", "Actions"},
+			NoRowsMessage: "No changes",
+			WithCounter:   true,
+			CounterHeader: "Entry #",
+		},
+	}
+	if summary.Destroy > 0 {
+		report.Overview[2].Style = reporting.Red
+	}
+	for _, resourceChange := range p.ResourceChanges {
+		actions := resourceChange.Change.Actions
+		if len(actions) == 0 || actions[0] == "no-op" || actions[0] == "read" {
+			continue
+		}
+		style := reporting.ColumnStyle(reporting.Green)
+		if piperutils.ContainsString(actions, "delete") {
+			style = reporting.Red
+		} else if piperutils.ContainsString(actions, "update") {
+			style = reporting.Yellow
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(resourceChange.Address, 0)
+		row.AddColumn(resourceChange.Type, 0)
+		row.AddColumn(fmt.Sprint(actions), style)
+		report.DetailTable.Rows = append(report.DetailTable.Rows, row)
+	}
+	return report
+}
@@ -5,19 +5,61 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
 type terraformExecuteOptions struct {
-	Command          string   `json:"command,omitempty"`
-	TerraformSecrets string   `json:"terraformSecrets,omitempty"`
-	AdditionalArgs   []string `json:"additionalArgs,omitempty"`
+	Command                string   `json:"command,omitempty"`
+	TerraformSecrets       string   `json:"terraformSecrets,omitempty"`
+	AdditionalArgs         []string `json:"additionalArgs,omitempty"`
+	Init                   bool     `json:"init,omitempty"`
+	TerraformBackendConfig string   `json:"terraformBackendConfig,omitempty"`
+	Workspace              string   `json:"workspace,omitempty"`
+	PlanFile               string   `json:"planFile,omitempty"`
+	FailOnDestroy          bool     `json:"failOnDestroy,omitempty"`
+}
+
+type terraformExecuteCommonPipelineEnvironment struct {
+	custom struct {
+		terraformPlanFile    string
+		terraformPlanAdd     int
+		terraformPlanChange  int
+		terraformPlanDestroy int
+	}
+}
+
+func (p *terraformExecuteCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "terraformPlanFile", value: p.custom.terraformPlanFile},
+		{category: "custom", name: "terraformPlanAdd", value: p.custom.terraformPlanAdd},
+		{category: "custom", name: "terraformPlanChange", value: p.custom.terraformPlanChange},
+		{category: "custom", name: "terraformPlanDestroy", value: p.custom.terraformPlanDestroy},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Fatal("failed to persist Piper environment")
+	}
 }
 
 // TerraformExecuteCommand Executes Terraform
@@ -27,12 +69,19 @@ func TerraformExecuteCommand() *cobra.Command {
 	metadata := terraformExecuteMetadata()
 	var stepConfig terraformExecuteOptions
 	var startTime time.Time
+	var commonPipelineEnvironment terraformExecuteCommonPipelineEnvironment
 	var logCollector *log.CollectorHook
 
 	var createTerraformExecuteCmd = &cobra.Command{
 		Use:   STEP_NAME,
 		Short: "Executes Terraform",
-		Long:  `This step executes the terraform binary with the given command, and is able to fetch additional variables from vault.`,
+		Long: `This step executes the terraform binary with the given command, and is able to fetch additional variables from vault.
+
+Optionally ` + "`" + `terraform init` + "`" + ` is executed before the command, e.g. with a backend configuration fetched from vault, and a workspace is selected or created.
+
+In case a [` + "`" + `planFile` + "`" + `](#planfile) is defined, the ` + "`" + `plan` + "`" + ` command saves the plan into this file and the ` + "`" + `apply` + "`" + ` command applies exactly this plan.
+The saved plan is evaluated via ` + "`" + `terraform show -json` + "`" + `: the number of resources to add, change and destroy is written to the commonPipelineEnvironment as well as into a markdown report.
+The JSON representation of the plan is written to ` + "`" + `terraformPlan.json` + "`" + `, e.g. for evaluating it with step [` + "`" + `policyEvaluate` + "`" + `](policyEvaluate.md).`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -65,8 +114,11 @@ func TerraformExecuteCommand() *cobra.Command {
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -82,7 +134,7 @@ func TerraformExecuteCommand() *cobra.Command {
 					GeneralConfig.HookConfig.SplunkConfig.Index,
 					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 			}
-			terraformExecute(stepConfig, &telemetryData)
+			terraformExecute(stepConfig, &telemetryData, &commonPipelineEnvironment)
 			telemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -93,9 +145,14 @@ func TerraformExecuteCommand() *cobra.Command {
 }
 
 func addTerraformExecuteFlags(cmd *cobra.Command, stepConfig *terraformExecuteOptions) {
-	cmd.Flags().StringVar(&stepConfig.Command, "command", `plan`, "")
-	cmd.Flags().StringVar(&stepConfig.TerraformSecrets, "terraformSecrets", os.Getenv("PIPER_terraformSecrets"), "")
-	cmd.Flags().StringSliceVar(&stepConfig.AdditionalArgs, "additionalArgs", []string{}, "")
+	cmd.Flags().StringVar(&stepConfig.Command, "command", `plan`, "The terraform command to execute, e.g. `plan` or `apply`.")
+	cmd.Flags().StringVar(&stepConfig.TerraformSecrets, "terraformSecrets", os.Getenv("PIPER_terraformSecrets"), "File containing the variables used for `plan` and `apply`, usually fetched from vault.")
+	cmd.Flags().StringSliceVar(&stepConfig.AdditionalArgs, "additionalArgs", []string{}, "Additional arguments passed to the terraform command.")
+	cmd.Flags().BoolVar(&stepConfig.Init, "init", false, "Executes `terraform init` before the command.")
+	cmd.Flags().StringVar(&stepConfig.TerraformBackendConfig, "terraformBackendConfig", os.Getenv("PIPER_terraformBackendConfig"), "File containing the backend configuration used for `terraform init`, usually fetched from vault.")
+	cmd.Flags().StringVar(&stepConfig.Workspace, "workspace", os.Getenv("PIPER_workspace"), "Name of the terraform workspace to use. The workspace is created in case it does not exist.")
+	cmd.Flags().StringVar(&stepConfig.PlanFile, "planFile", os.Getenv("PIPER_planFile"), "File for saving the plan with command `plan` and for applying the saved plan with command `apply`.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnDestroy, "failOnDestroy", false, "Fails the step in case the saved plan contains resources to be destroyed.")
 
 }
 
@@ -143,11 +200,81 @@ func terraformExecuteMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "init",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "terraformBackendConfig",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "",
+								Paths: []string{"$(vaultPath)/terraformExecute", "$(vaultBasePath)/$(vaultPipelineName)/terraformExecute", "$(vaultBasePath)/GROUP-SECRETS/terraformExecute"},
+								Type:  "vaultSecretFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_terraformBackendConfig"),
+					},
+					{
+						Name:        "workspace",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_workspace"),
+					},
+					{
+						Name: "planFile",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/terraformPlanFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_planFile"),
+					},
+					{
+						Name:        "failOnDestroy",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 				},
 			},
 			Containers: []config.Container{
 				{Name: "terraform", Image: "hashicorp/terraform:0.14.7"},
 			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"Name": "custom/terraformPlanFile"},
+							{"Name": "custom/terraformPlanAdd"},
+							{"Name": "custom/terraformPlanChange"},
+							{"Name": "custom/terraformPlanDestroy"},
+						},
+					},
+				},
+			},
 		},
 	}
 	return theMetaData
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"testing"
+
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 type terraformExecuteMockUtils struct {
@@ -66,6 +68,7 @@ func TestRunTerraformExecute(t *testing.T) {
 	}
 
 	for i, test := range tt {
+		test := test
 		t.Run(fmt.Sprintf("That arguemtns are correct %d", i), func(t *testing.T) {
 			t.Parallel()
 			// init
@@ -73,7 +76,7 @@ func TestRunTerraformExecute(t *testing.T) {
 			utils := newTerraformExecuteTestsUtils()
 
 			// test
-			err := runTerraformExecute(&config, nil, utils)
+			err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
 
 			// assert
 			assert.NoError(t, err)
@@ -81,3 +84,138 @@ func TestRunTerraformExecute(t *testing.T) {
 		})
 	}
 }
+
+const terraformPlanJSON = `{
+	"format_version": "0.1",
+	"resource_changes": [
+		{"address": "aws_instance.web", "type": "aws_instance", "change": {"actions": ["create"]}},
+		{"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "change": {"actions": ["update"]}},
+		{"address": "aws_instance.db", "type": "aws_instance", "change": {"actions": ["delete", "create"]}},
+		{"address": "aws_iam_role.unchanged", "type": "aws_iam_role", "change": {"actions": ["no-op"]}}
+	]
+}`
+
+func TestRunTerraformExecuteWorkflow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("init with backend config and new workspace", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{
+			Command:                "plan",
+			Init:                   true,
+			TerraformBackendConfig: "/tmp/backend",
+			Workspace:              "dev",
+		}
+		utils := newTerraformExecuteTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"terraform workspace select dev": fmt.Errorf("workspace does not exist")}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "terraform", Params: []string{"init", "-input=false", "-backend-config=/tmp/backend"}},
+			{Exec: "terraform", Params: []string{"workspace", "select", "dev"}},
+			{Exec: "terraform", Params: []string{"workspace", "new", "dev"}},
+			{Exec: "terraform", Params: []string{"plan"}},
+		}, utils.Calls)
+	})
+
+	t.Run("init command", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "init", AdditionalArgs: []string{"-upgrade"}}
+		utils := newTerraformExecuteTestsUtils()
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "terraform", Params: []string{"init", "-input=false", "-upgrade"}}}, utils.Calls)
+	})
+
+	t.Run("plan with plan file", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "plan", TerraformSecrets: "/tmp/test", PlanFile: "tfplan"}
+		utils := newTerraformExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{"terraform show -json tfplan": terraformPlanJSON}
+		cpe := terraformExecuteCommonPipelineEnvironment{}
+
+		err := runTerraformExecute(&config, nil, utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "terraform", Params: []string{"plan", "-var-file=/tmp/test", "-out=tfplan"}},
+			{Exec: "terraform", Params: []string{"show", "-json", "tfplan"}},
+		}, utils.Calls)
+		assert.Equal(t, "tfplan", cpe.custom.terraformPlanFile)
+		assert.Equal(t, 2, cpe.custom.terraformPlanAdd)
+		assert.Equal(t, 1, cpe.custom.terraformPlanChange)
+		assert.Equal(t, 1, cpe.custom.terraformPlanDestroy)
+
+		report, err := utils.FileRead(terraformPlanReport)
+		require.NoError(t, err)
+		assert.Contains(t, string(report), "Terraform Plan")
+		assert.Contains(t, string(report), "aws_instance.db")
+		assert.NotContains(t, string(report), "aws_iam_role.unchanged")
+
+		planJSON, err := utils.FileRead(terraformPlanJSONFile)
+		require.NoError(t, err)
+		assert.Equal(t, terraformPlanJSON, string(planJSON))
+	})
+
+	t.Run("plan with destroys", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "plan", PlanFile: "tfplan", FailOnDestroy: true}
+		utils := newTerraformExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{"terraform show -json tfplan": terraformPlanJSON}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "plan contains 1 resources to destroy")
+		exists, _ := utils.FileExists(terraformPlanReport)
+		assert.True(t, exists)
+	})
+
+	t.Run("invalid plan output", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "plan", PlanFile: "tfplan"}
+		utils := newTerraformExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{"terraform show -json tfplan": "no json"}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse plan file 'tfplan'")
+	})
+
+	t.Run("apply saved plan", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "apply", TerraformSecrets: "/tmp/test", PlanFile: "tfplan"}
+		utils := newTerraformExecuteTestsUtils()
+		utils.AddFile("tfplan", []byte("plan"))
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "terraform", Params: []string{"apply", "-auto-approve", "tfplan"}}}, utils.Calls)
+	})
+
+	t.Run("apply missing plan", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "apply", PlanFile: "tfplan"}
+		utils := newTerraformExecuteTestsUtils()
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "plan file 'tfplan' does not exist")
+		assert.Empty(t, utils.Calls)
+	})
+
+	t.Run("terraform fails", func(t *testing.T) {
+		t.Parallel()
+		config := terraformExecuteOptions{Command: "apply"}
+		utils := newTerraformExecuteTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"terraform apply -auto-approve": fmt.Errorf("exit status 1")}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to execute 'terraform apply': exit status 1")
+	})
+}
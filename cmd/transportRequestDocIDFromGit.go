@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	pipergit "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 )
@@ -20,6 +21,25 @@ func runTransportRequestDocIDFromGit(config *transportRequestDocIDFromGitOptions
 	trUtils gitIDInRangeFinder,
 	commonPipelineEnvironment *transportRequestDocIDFromGitCommonPipelineEnvironment) error {
 
+	if config.GitFetch {
+		err := fetchOrigin(trUtils, pipergit.AuthOptions{
+			Method:                  config.GitAuthMethod,
+			Username:                config.Username,
+			Password:                config.Password,
+			SSHKeyPath:              config.GitSshKeyPath,
+			SSHKeyPassphrase:        config.GitSshKeyPassphrase,
+			SSHKnownHostsPath:       config.GitSshKnownHostsPath,
+			Token:                   config.GitToken,
+			GitHubAppID:             config.GithubAppID,
+			GitHubAppInstallationID: config.GithubAppInstallationID,
+			GitHubAppPrivateKeyPath: config.GithubAppPrivateKeyPath,
+			GitHubAPIURL:            config.GithubAPIURL,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	cdID, err := getChangeDocumentID(config, trUtils)
 	if err != nil {
 		return err
@@ -31,6 +31,20 @@ func TestTrGitGetChangeDocumentID(t *testing.T) {
 				assert.Equal(t, cpe.custom.changeDocumentID, "56781234")
 			}
 		})
+		t.Run("runTransportRequestDocIDFromGit with fetch", func(t *testing.T) {
+			configMock := newCdIDConfigMock()
+			configMock.config.GitFetch = true
+			cpe := &transportRequestDocIDFromGitCommonPipelineEnvironment{}
+			utilsMock := &transportRequestUtilsMock{cdID: "56781234"}
+
+			err := runTransportRequestDocIDFromGit(configMock.config, nil, utilsMock, cpe)
+
+			if assert.NoError(t, err) {
+				assert.True(t, utilsMock.fetched)
+				assert.Nil(t, utilsMock.auth)
+				assert.Equal(t, cpe.custom.changeDocumentID, "56781234")
+			}
+		})
 
 	})
 	t.Run("bad", func(t *testing.T) {
@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	pipergit "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/transportrequest"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
 )
 
 // mocking framework. Allows to redirect the containing methods
 type gitIDInRangeFinder interface {
 	FindIDInRange(label, from, to string) (string, error)
+	FetchOrigin(auth transport.AuthMethod) error
 }
 
 type gitIDInRange struct {
@@ -18,6 +22,10 @@ func (*gitIDInRange) FindIDInRange(label, from, to string) (string, error) {
 	return transportrequest.FindIDInRange(label, from, to)
 }
 
+func (*gitIDInRange) FetchOrigin(auth transport.AuthMethod) error {
+	return transportrequest.FetchOrigin(auth)
+}
+
 func transportRequestReqIDFromGit(config transportRequestReqIDFromGitOptions,
 	telemetryData *telemetry.CustomData,
 	commonPipelineEnvironment *transportRequestReqIDFromGitCommonPipelineEnvironment) {
@@ -33,6 +41,25 @@ func runTransportRequestReqIDFromGit(config *transportRequestReqIDFromGitOptions
 	trUtils gitIDInRangeFinder,
 	commonPipelineEnvironment *transportRequestReqIDFromGitCommonPipelineEnvironment) error {
 
+	if config.GitFetch {
+		err := fetchOrigin(trUtils, pipergit.AuthOptions{
+			Method:                  config.GitAuthMethod,
+			Username:                config.Username,
+			Password:                config.Password,
+			SSHKeyPath:              config.GitSshKeyPath,
+			SSHKeyPassphrase:        config.GitSshKeyPassphrase,
+			SSHKnownHostsPath:       config.GitSshKnownHostsPath,
+			Token:                   config.GitToken,
+			GitHubAppID:             config.GithubAppID,
+			GitHubAppInstallationID: config.GithubAppInstallationID,
+			GitHubAppPrivateKeyPath: config.GithubAppPrivateKeyPath,
+			GitHubAPIURL:            config.GithubAPIURL,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	trID, err := getTransportRequestID(config, trUtils)
 	if err != nil {
 		return err
@@ -50,3 +77,13 @@ func getTransportRequestID(config *transportRequestReqIDFromGitOptions,
 
 	return trUtils.FindIDInRange(config.TransportRequestLabel, config.GitFrom, config.GitTo)
 }
+
+// fetchOrigin makes the commits of remote origin available before the commit range is evaluated
+func fetchOrigin(trUtils gitIDInRangeFinder, authOptions pipergit.AuthOptions) error {
+	auth, err := pipergit.AuthMethod(authOptions)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to determine git authentication")
+	}
+	return trUtils.FetchOrigin(auth)
+}
@@ -11,15 +11,28 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
 type transportRequestReqIDFromGitOptions struct {
-	GitFrom               string `json:"gitFrom,omitempty"`
-	GitTo                 string `json:"gitTo,omitempty"`
-	TransportRequestLabel string `json:"transportRequestLabel,omitempty"`
+	GitFrom                 string `json:"gitFrom,omitempty"`
+	GitTo                   string `json:"gitTo,omitempty"`
+	TransportRequestLabel   string `json:"transportRequestLabel,omitempty"`
+	GitFetch                bool   `json:"gitFetch,omitempty"`
+	Username                string `json:"username,omitempty"`
+	Password                string `json:"password,omitempty"`
+	GitAuthMethod           string `json:"gitAuthMethod,omitempty"`
+	GitSshKeyPassphrase     string `json:"gitSshKeyPassphrase,omitempty"`
+	GitSshKeyPath           string `json:"gitSshKeyPath,omitempty"`
+	GitSshKnownHostsPath    string `json:"gitSshKnownHostsPath,omitempty"`
+	GitToken                string `json:"gitToken,omitempty"`
+	GithubAPIURL            string `json:"githubApiUrl,omitempty"`
+	GithubAppID             string `json:"githubAppId,omitempty"`
+	GithubAppInstallationID string `json:"githubAppInstallationId,omitempty"`
+	GithubAppPrivateKeyPath string `json:"githubAppPrivateKeyPath,omitempty"`
 }
 
 type transportRequestReqIDFromGitCommonPipelineEnvironment struct {
@@ -79,6 +92,12 @@ It is primarily made for the transportRequestUploadSOLMAN step to provide the tr
 				log.SetErrorCategory(log.ErrorConfiguration)
 				return err
 			}
+			log.RegisterSecret(stepConfig.Username)
+			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.GitSshKeyPassphrase)
+			log.RegisterSecret(stepConfig.GitSshKeyPath)
+			log.RegisterSecret(stepConfig.GitToken)
+			log.RegisterSecret(stepConfig.GithubAppPrivateKeyPath)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -97,9 +116,11 @@ It is primarily made for the transportRequestUploadSOLMAN step to provide the tr
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult(GeneralConfig.EnvRootPath, GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -129,6 +150,18 @@ func addTransportRequestReqIDFromGitFlags(cmd *cobra.Command, stepConfig *transp
 	cmd.Flags().StringVar(&stepConfig.GitFrom, "gitFrom", `origin/master`, "GIT starting point for retrieving the transport request ID")
 	cmd.Flags().StringVar(&stepConfig.GitTo, "gitTo", `HEAD`, "GIT ending point for retrieving the transport request ID")
 	cmd.Flags().StringVar(&stepConfig.TransportRequestLabel, "transportRequestLabel", `TransportRequest`, "Pattern used for identifying lines holding the transport request ID. The GIT commit log messages are scanned for this label")
+	cmd.Flags().BoolVar(&stepConfig.GitFetch, "gitFetch", false, "Fetches the branches of remote `origin` before the commit range is evaluated, e.g. in case the repository has been cloned partially. The authentication is defined by [`gitAuthMethod`](#gitauthmethod).")
+	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for `gitAuthMethod: basic`.")
+	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for `gitAuthMethod: basic`.")
+	cmd.Flags().StringVar(&stepConfig.GitAuthMethod, "gitAuthMethod", os.Getenv("PIPER_gitAuthMethod"), "Method for the authentication against the git repository: `basic` uses [`username`](#username) and [`password`](#password), `ssh` uses the private key [`gitSshKeyPath`](#gitsshkeypath) or the keys of the ssh agent, `token` sends [`gitToken`](#gittoken) as bearer token and `githubApp` uses an installation access token of the GitHub App [`githubAppId`](#githubappid).")
+	cmd.Flags().StringVar(&stepConfig.GitSshKeyPassphrase, "gitSshKeyPassphrase", os.Getenv("PIPER_gitSshKeyPassphrase"), "Passphrase of the private key [`gitSshKeyPath`](#gitsshkeypath).")
+	cmd.Flags().StringVar(&stepConfig.GitSshKeyPath, "gitSshKeyPath", os.Getenv("PIPER_gitSshKeyPath"), "Path to the private ssh key for `gitAuthMethod: ssh`. In case no key is provided, the keys of the ssh agent are used, e.g. provided via [`gitSshKeyCredentialsId`](#gitsshkeycredentialsid) on Jenkins.")
+	cmd.Flags().StringVar(&stepConfig.GitSshKnownHostsPath, "gitSshKnownHostsPath", os.Getenv("PIPER_gitSshKnownHostsPath"), "Path to the known_hosts file used to verify the host key of the git server for `gitAuthMethod: ssh`. By default the files referenced by the environment variable `SSH_KNOWN_HOSTS` or `~/.ssh/known_hosts` and `/etc/ssh/ssh_known_hosts` are used. Unknown hosts are always rejected.")
+	cmd.Flags().StringVar(&stepConfig.GitToken, "gitToken", os.Getenv("PIPER_gitToken"), "Token sent as bearer token for `gitAuthMethod: token`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "GitHub API url used to create the installation access token for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppID, "githubAppId", os.Getenv("PIPER_githubAppId"), "ID of the GitHub App for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppInstallationID, "githubAppInstallationId", os.Getenv("PIPER_githubAppInstallationId"), "ID of the installation of the GitHub App in the organization or account owning the git repository for `gitAuthMethod: githubApp`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAppPrivateKeyPath, "githubAppPrivateKeyPath", os.Getenv("PIPER_githubAppPrivateKeyPath"), "Path to the private key of the GitHub App for `gitAuthMethod: githubApp`.")
 
 }
 
@@ -142,6 +175,12 @@ func transportRequestReqIDFromGitMetadata() config.StepData {
 		},
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "gitHttpsCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repository.", Type: "jenkins"},
+					{Name: "gitSshKeyCredentialsId", Description: "Jenkins 'SSH Username with private key' credentials ID for `gitAuthMethod: ssh`, the key is provided via the ssh agent.", Type: "jenkins"},
+					{Name: "gitTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the token for `gitAuthMethod: token`.", Type: "jenkins"},
+					{Name: "githubAppPrivateKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key of the GitHub App for `gitAuthMethod: githubApp`.", Type: "jenkins"},
+				},
 				Parameters: []config.StepParameters{
 					{
 						Name:        "gitFrom",
@@ -170,6 +209,137 @@ func transportRequestReqIDFromGitMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "changeManagement/transportRequestLabel"}},
 						Default:     `TransportRequest`,
 					},
+					{
+						Name:        "gitFetch",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "username",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "gitHttpsCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_username"),
+					},
+					{
+						Name: "password",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "gitHttpsCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_password"),
+					},
+					{
+						Name:           "gitAuthMethod",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_gitAuthMethod"),
+						PossibleValues: []interface{}{"basic", "ssh", "token", "githubApp"},
+					},
+					{
+						Name:        "gitSshKeyPassphrase",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKeyPassphrase"),
+					},
+					{
+						Name:        "gitSshKeyPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKeyPath"),
+					},
+					{
+						Name:        "gitSshKnownHostsPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_gitSshKnownHostsPath"),
+					},
+					{
+						Name: "gitToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "gitTokenCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_gitToken"),
+					},
+					{
+						Name:        "githubApiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name:        "githubAppId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_githubAppId"),
+					},
+					{
+						Name:        "githubAppInstallationId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_githubAppInstallationId"),
+					},
+					{
+						Name: "githubAppPrivateKeyPath",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubAppPrivateKeyCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_githubAppPrivateKeyPath"),
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
@@ -3,15 +3,25 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
 )
 
 type transportRequestUtilsMock struct {
-	err  error
-	trID string
-	cdID string
+	err     error
+	trID    string
+	cdID    string
+	fetched bool
+	auth    transport.AuthMethod
+}
+
+func (m *transportRequestUtilsMock) FetchOrigin(auth transport.AuthMethod) error {
+	m.fetched = true
+	m.auth = auth
+	return nil
 }
 
 func (m *transportRequestUtilsMock) FindIDInRange(label, from, to string) (string, error) {
@@ -53,6 +63,22 @@ func TestTrGitGetTransportRequestID(t *testing.T) {
 				assert.Equal(t, cpe.custom.transportRequestID, "43218765")
 			}
 		})
+		t.Run("runTransportRequestReqIDFromGit with fetch", func(t *testing.T) {
+			configMock := newTrIDConfigMock()
+			configMock.config.GitFetch = true
+			configMock.config.GitAuthMethod = "token"
+			configMock.config.GitToken = "theToken"
+			cpe := &transportRequestReqIDFromGitCommonPipelineEnvironment{}
+			utilsMock := &transportRequestUtilsMock{trID: "43218765"}
+
+			err := runTransportRequestReqIDFromGit(configMock.config, nil, utilsMock, cpe)
+
+			if assert.NoError(t, err) {
+				assert.True(t, utilsMock.fetched)
+				assert.Equal(t, &http.TokenAuth{Token: "theToken"}, utilsMock.auth)
+				assert.Equal(t, cpe.custom.transportRequestID, "43218765")
+			}
+		})
 	})
 	t.Run("bad", func(t *testing.T) {
 		t.Parallel()
@@ -65,6 +91,18 @@ func TestTrGitGetTransportRequestID(t *testing.T) {
 
 			assert.EqualError(t, err, "fail")
 		})
+		t.Run("runTransportRequestReqIDFromGit with incomplete authentication", func(t *testing.T) {
+			configMock := newTrIDConfigMock()
+			configMock.config.GitFetch = true
+			configMock.config.GitAuthMethod = "token"
+			cpe := &transportRequestReqIDFromGitCommonPipelineEnvironment{}
+			utilsMock := &transportRequestUtilsMock{trID: "43218765"}
+
+			err := runTransportRequestReqIDFromGit(configMock.config, nil, utilsMock, cpe)
+
+			assert.EqualError(t, err, "failed to determine git authentication: a token is required for token authentication")
+			assert.False(t, utilsMock.fetched)
+		})
 
 	})
 }
@@ -17,6 +17,8 @@ import (
 
 	"github.com/360EntSecGroup-Skylar/excelize/v2"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/npm"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -24,6 +26,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	"github.com/pkg/errors"
 )
 
@@ -205,12 +208,58 @@ func runWhitesourceScan(config *ScanOptions, scan *ws.Scan, utils whitesourceUti
 	paths, err := checkAndReportScanResults(config, scan, utils, sys, influx)
 	piperutils.PersistReportsAndLinks("whitesourceExecuteScan", "", paths, nil)
 	persistScannedProjects(config, scan, commonPipelineEnvironment)
+	if len(config.SbomPath) > 0 {
+		checkSBOMCoverage(config, scan, sys, utils)
+	}
 	if err != nil {
 		return errors.Wrapf(err, "failed to check and report scan results")
 	}
 	return nil
 }
 
+// checkSBOMCoverage compares the components of the SBOM created by the build with the libraries found by the scan.
+// Components which are not covered by the scan are reported as warning, e.g. since the scan resolved dependencies differently.
+func checkSBOMCoverage(config *ScanOptions, scan *ws.Scan, sys whitesource, utils whitesourceUtils) {
+	bom, err := cyclonedx.ReadBOMFile(config.SbomPath, utils)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to read SBOM, skipping comparison with the scanned libraries")
+		return
+	}
+
+	scanned := map[string]bool{}
+	for _, project := range scan.ScannedProjects() {
+		libraries, err := sys.GetProjectLibraryLocations(project.Token)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("failed to get libraries of project '%v', skipping comparison with the SBOM", project.Name)
+			return
+		}
+		for _, library := range libraries {
+			for _, name := range []string{library.ArtifactID, library.Name} {
+				if len(name) > 0 {
+					scanned[strings.ToLower(name)] = true
+					scanned[strings.ToLower(name+"@"+library.Version)] = true
+				}
+			}
+		}
+	}
+
+	missing := []string{}
+	for _, component := range bom.Components {
+		key := strings.ToLower(component.Name)
+		if len(component.Version) > 0 {
+			key += "@" + strings.ToLower(component.Version)
+		}
+		if !scanned[key] {
+			missing = append(missing, strings.TrimPrefix(component.Group+":"+key, ":"))
+		}
+	}
+	if len(missing) > 0 {
+		log.Entry().Warnf("%v of %v components of SBOM '%v' are not contained in the scan results: %v", len(missing), len(bom.Components), config.SbomPath, strings.Join(missing, ", "))
+		return
+	}
+	log.Entry().Infof("All %v components of SBOM '%v' are contained in the scan results", len(bom.Components), config.SbomPath)
+}
+
 func checkAndReportScanResults(config *ScanOptions, scan *ws.Scan, utils whitesourceUtils, sys whitesource, influx *whitesourceExecuteScanInflux) ([]piperutils.Path, error) {
 	reportPaths := []piperutils.Path{}
 	if !config.Reporting && !config.SecurityVulnerabilities {
@@ -538,11 +587,20 @@ func checkSecurityViolations(config *ScanOptions, scan *ws.Scan, sys whitesource
 			"as floating point number: %w", config.CvssSeverityLimit, err)
 	}
 
+	// alerts exempted via the central exemptions file don't fail the build
+	exemptions, err := vulnerability.LoadExemptions(utils)
+	if err != nil {
+		return reportPaths, err
+	}
+	if err := exemptions.CheckExpired(utils.Now()); err != nil {
+		return reportPaths, err
+	}
+
 	if config.ProjectToken != "" {
 		project := ws.Project{Name: config.ProjectName, Token: config.ProjectToken}
 		// ToDo: see if HTML report generation is really required here
 		// we anyway need to do some refactoring here since config.ProjectToken != "" essentially indicates an aggregated project
-		if _, _, err := checkProjectSecurityViolations(cvssSeverityLimit, project, sys, influx); err != nil {
+		if _, _, err := checkProjectSecurityViolations(cvssSeverityLimit, project, sys, influx, exemptions); err != nil {
 			return reportPaths, err
 		}
 	} else {
@@ -551,7 +609,7 @@ func checkSecurityViolations(config *ScanOptions, scan *ws.Scan, sys whitesource
 		allAlerts := []ws.Alert{}
 		for _, project := range scan.ScannedProjects() {
 			// collect errors and aggregate vulnerabilities from all projects
-			if vulCount, alerts, err := checkProjectSecurityViolations(cvssSeverityLimit, project, sys, influx); err != nil {
+			if vulCount, alerts, err := checkProjectSecurityViolations(cvssSeverityLimit, project, sys, influx, exemptions); err != nil {
 				allAlerts = append(allAlerts, alerts...)
 				vulnerabilitiesCount += vulCount
 				errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -564,6 +622,18 @@ func checkSecurityViolations(config *ScanOptions, scan *ws.Scan, sys whitesource
 			errorsOccured = append(errorsOccured, fmt.Sprint(err))
 		}
 
+		// SARIF reports are used by step pipelineCreateScanSummary in order to e.g. prepare an upload to GitHub code scanning
+		sarifReportPath := filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("whitesourceExecuteScan_oss_%v.sarif", reportSha(config, scan)))
+		if err := format.WriteSARIF(ws.ConvertAlertsToSarif(allAlerts, cvssSeverityLimit), sarifReportPath, utils); err != nil {
+			log.Entry().WithError(err).Warn("failed to write SARIF report")
+		} else {
+			reportPaths = append(reportPaths, piperutils.Path{Name: "WhiteSource SARIF Report", Target: sarifReportPath})
+		}
+		// findings are consolidated with the ones of other scanners by step pipelineCreateScanSummary
+		if _, err := vulnerability.WriteFindings(exemptions.Apply(ws.ConvertAlertsToFindings(allAlerts)), fmt.Sprintf("whitesourceExecuteScan_oss_%v", reportSha(config, scan)), utils); err != nil {
+			log.Entry().WithError(err).Warn("failed to write findings")
+		}
+
 		if len(errorsOccured) > 0 {
 			if vulnerabilitiesCount > 0 {
 				log.SetErrorCategory(log.ErrorCompliance)
@@ -575,14 +645,15 @@ func checkSecurityViolations(config *ScanOptions, scan *ws.Scan, sys whitesource
 }
 
 // checkSecurityViolations checks security violations and returns an error if the configured severity limit is crossed.
-func checkProjectSecurityViolations(cvssSeverityLimit float64, project ws.Project, sys whitesource, influx *whitesourceExecuteScanInflux) (int, []ws.Alert, error) {
+func checkProjectSecurityViolations(cvssSeverityLimit float64, project ws.Project, sys whitesource, influx *whitesourceExecuteScanInflux, exemptions *vulnerability.Exemptions) (int, []ws.Alert, error) {
 	// get project alerts (vulnerabilities)
 	alerts, err := sys.GetProjectAlertsByType(project.Token, "SECURITY_VULNERABILITY")
 	if err != nil {
 		return 0, alerts, fmt.Errorf("failed to retrieve project alerts from WhiteSource: %w", err)
 	}
 
-	severeVulnerabilities, nonSevereVulnerabilities := countSecurityVulnerabilities(&alerts, cvssSeverityLimit)
+	openAlerts := withoutExemptedAlerts(alerts, exemptions)
+	severeVulnerabilities, nonSevereVulnerabilities := countSecurityVulnerabilities(&openAlerts, cvssSeverityLimit)
 	influx.whitesource_data.fields.minor_vulnerabilities = nonSevereVulnerabilities
 	influx.whitesource_data.fields.major_vulnerabilities = severeVulnerabilities
 	influx.whitesource_data.fields.vulnerabilities = nonSevereVulnerabilities + severeVulnerabilities
@@ -604,6 +675,19 @@ func checkProjectSecurityViolations(cvssSeverityLimit float64, project ws.Projec
 	return 0, alerts, nil
 }
 
+// withoutExemptedAlerts removes the alerts which are exempted via the central exemptions file
+func withoutExemptedAlerts(alerts []ws.Alert, exemptions *vulnerability.Exemptions) []ws.Alert {
+	openAlerts := []ws.Alert{}
+	for i, finding := range ws.ConvertAlertsToFindings(alerts) {
+		if exemption := exemptions.Match(&finding); exemption != nil {
+			log.Entry().Infof("vulnerability %v exempted by %v: %v", finding.ID, exemption.Owner, exemption.Justification)
+			continue
+		}
+		openAlerts = append(openAlerts, alerts[i])
+	}
+	return openAlerts
+}
+
 func countSecurityVulnerabilities(alerts *[]ws.Alert, cvssSeverityLimit float64) (int, int) {
 	severeVulnerabilities := 0
 	for _, alert := range *alerts {
@@ -741,10 +825,8 @@ func writeCustomVulnerabilityReports(config *ScanOptions, scan *ws.Scan, scanRep
 }
 
 func vulnerabilityScore(alert ws.Alert) float64 {
-	if alert.Vulnerability.CVSS3Score > 0 {
-		return alert.Vulnerability.CVSS3Score
-	}
-	return alert.Vulnerability.Score
+	score, _ := vulnerability.Score(alert.Vulnerability.CVSS3Score, alert.Vulnerability.Score)
+	return score
 }
 
 func reportSha(config *ScanOptions, scan *ws.Scan) string {
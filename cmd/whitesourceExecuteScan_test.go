@@ -1,16 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	ws "github.com/SAP/jenkins-library/pkg/whitesource"
 	"github.com/stretchr/testify/assert"
 )
@@ -495,6 +498,26 @@ func TestCheckSecurityViolations(t *testing.T) {
 		fileContent, err := utilsMock.FileRead(reportPaths[0].Target)
 		assert.NoError(t, err)
 		assert.True(t, len(fileContent) > 0)
+		assert.Equal(t, "WhiteSource SARIF Report", reportPaths[1].Name)
+	})
+
+	t.Run("success - non-aggregated, SARIF report not written", func(t *testing.T) {
+		config := ScanOptions{
+			CvssSeverityLimit: "7",
+		}
+		scan := newWhitesourceScan(&config)
+		scan.AppendScannedProject("testProject1")
+		systemMock := ws.NewSystemMock("ignored")
+		utilsMock := newWhitesourceUtilsMock()
+		sarifReportPath := filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("whitesourceExecuteScan_oss_%v.sarif", reportSha(&config, scan)))
+		utilsMock.FileWriteErrors = map[string]error{sarifReportPath: fmt.Errorf("write error")}
+		influx := whitesourceExecuteScanInflux{}
+
+		reportPaths, err := checkSecurityViolations(&config, scan, systemMock, utilsMock, &influx)
+		assert.NoError(t, err)
+		for _, reportPath := range reportPaths {
+			assert.NotEqual(t, sarifReportPath, reportPath.Target)
+		}
 	})
 
 	t.Run("success - aggregated", func(t *testing.T) {
@@ -515,6 +538,18 @@ func TestCheckSecurityViolations(t *testing.T) {
 		assert.Equal(t, 0, len(reportPaths))
 	})
 
+	t.Run("error - expired exemptions", func(t *testing.T) {
+		config := ScanOptions{CvssSeverityLimit: "7"}
+		scan := newWhitesourceScan(&config)
+		systemMock := ws.NewSystemMock("ignored")
+		utilsMock := newWhitesourceUtilsMock()
+		utilsMock.AddFile(vulnerability.ExemptionsFile, []byte("exemptions:\n  - id: CVE-2021-44228\n    justification: not used\n    owner: security-team\n    expires: 2010-05-09\n"))
+		influx := whitesourceExecuteScanInflux{}
+
+		_, err := checkSecurityViolations(&config, scan, systemMock, utilsMock, &influx)
+		assert.Contains(t, fmt.Sprint(err), "1 exemptions in '.pipeline/exemptions.yml' are expired, please review them: CVE-2021-44228 (owner: security-team, expired: 2010-05-09)")
+	})
+
 	t.Run("error - wrong limit", func(t *testing.T) {
 		config := ScanOptions{CvssSeverityLimit: "x"}
 		scan := newWhitesourceScan(&config)
@@ -545,6 +580,10 @@ func TestCheckSecurityViolations(t *testing.T) {
 		fileContent, err := utilsMock.FileRead(reportPaths[0].Target)
 		assert.NoError(t, err)
 		assert.True(t, len(fileContent) > 0)
+		assert.Equal(t, "WhiteSource SARIF Report", reportPaths[1].Name)
+		sarifContent, err := utilsMock.FileRead(reportPaths[1].Target)
+		assert.NoError(t, err)
+		assert.Contains(t, string(sarifContent), `"ruleId": "vul1"`)
 	})
 
 	t.Run("error - aggregated", func(t *testing.T) {
@@ -574,7 +613,7 @@ func TestCheckProjectSecurityViolations(t *testing.T) {
 		systemMock.Alerts = []ws.Alert{}
 		influx := whitesourceExecuteScanInflux{}
 
-		severeVulnerabilities, alerts, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx)
+		severeVulnerabilities, alerts, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, 0, severeVulnerabilities)
 		assert.Equal(t, 0, len(alerts))
@@ -588,18 +627,36 @@ func TestCheckProjectSecurityViolations(t *testing.T) {
 		}
 		influx := whitesourceExecuteScanInflux{}
 
-		severeVulnerabilities, alerts, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx)
+		severeVulnerabilities, alerts, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx, nil)
 		assert.Contains(t, fmt.Sprint(err), "1 Open Source Software Security vulnerabilities")
 		assert.Equal(t, 1, severeVulnerabilities)
 		assert.Equal(t, 2, len(alerts))
 	})
 
+	t.Run("success - exempted vulnerabilities", func(t *testing.T) {
+		systemMock := ws.NewSystemMock("ignored")
+		systemMock.Alerts = []ws.Alert{
+			{Library: ws.Library{ArtifactID: "log4j-core", Version: "2.14.1"}, Vulnerability: ws.Vulnerability{Name: "CVE-2021-44228", CVSS3Score: 10}},
+			{Vulnerability: ws.Vulnerability{CVSS3Score: 6}},
+		}
+		influx := whitesourceExecuteScanInflux{}
+		exemptions := &vulnerability.Exemptions{Exemptions: []vulnerability.Exemption{
+			{ID: "CVE-2021-44228", Component: "log4j-core", Justification: "JNDI lookup disabled", Owner: "security-team", Expires: "2010-12-31"},
+		}}
+
+		severeVulnerabilities, alerts, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx, exemptions)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, severeVulnerabilities)
+		assert.Equal(t, 2, len(alerts))
+		assert.Equal(t, 1, influx.whitesource_data.fields.vulnerabilities)
+	})
+
 	t.Run("error - WhiteSource failure", func(t *testing.T) {
 		systemMock := ws.NewSystemMock("ignored")
 		systemMock.AlertError = fmt.Errorf("failed to read alerts")
 		influx := whitesourceExecuteScanInflux{}
 
-		_, _, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx)
+		_, _, err := checkProjectSecurityViolations(7.0, project, systemMock, &influx, nil)
 		assert.Contains(t, fmt.Sprint(err), "failed to retrieve project alerts from WhiteSource")
 	})
 
@@ -811,6 +868,53 @@ func TestAggregateVersionWideVulnerabilities(t *testing.T) {
 	})
 }
 
+func TestCheckSBOMCoverage(t *testing.T) {
+	bom := `{"bomFormat": "CycloneDX", "specVersion": "1.4", "version": 1, "components": [
+		{"type": "library", "group": "org.slf4j", "name": "slf4j-api", "version": "1.7.32"},
+		{"type": "library", "name": "mock-library", "version": "mock-library-version"},
+		{"type": "library", "name": "lodash", "version": "4.17.21"}
+	]}`
+
+	runCheck := func(t *testing.T, config *ScanOptions, libraries []ws.Library) string {
+		logBuffer := new(bytes.Buffer)
+		logOutput := log.Entry().Logger.Out
+		log.Entry().Logger.Out = logBuffer
+		defer func() { log.Entry().Logger.Out = logOutput }()
+
+		scan := newWhitesourceScan(config)
+		scan.AppendScannedProject("testProject1")
+		systemMock := ws.NewSystemMock("ignored")
+		systemMock.Libraries = libraries
+		utilsMock := newWhitesourceUtilsMock()
+		utilsMock.AddFile("bom.json", []byte(bom))
+
+		checkSBOMCoverage(config, scan, systemMock, utilsMock)
+		return logBuffer.String()
+	}
+
+	t.Run("components missing in scan results", func(t *testing.T) {
+		output := runCheck(t, &ScanOptions{SbomPath: "bom.json"}, []ws.Library{
+			{Name: "mock-library", Version: "mock-library-version"},
+			{ArtifactID: "slf4j-api", GroupID: "org.slf4j", Version: "1.7.32"},
+		})
+		assert.Contains(t, output, "1 of 3 components of SBOM 'bom.json' are not contained in the scan results: lodash@4.17.21")
+	})
+
+	t.Run("all components contained in scan results", func(t *testing.T) {
+		output := runCheck(t, &ScanOptions{SbomPath: "bom.json"}, []ws.Library{
+			{Name: "mock-library", Version: "mock-library-version"},
+			{ArtifactID: "slf4j-api", GroupID: "org.slf4j", Version: "1.7.32"},
+			{ArtifactID: "lodash", Version: "4.17.21"},
+		})
+		assert.Contains(t, output, "All 3 components of SBOM 'bom.json' are contained in the scan results")
+	})
+
+	t.Run("SBOM missing", func(t *testing.T) {
+		output := runCheck(t, &ScanOptions{SbomPath: "missing.json"}, nil)
+		assert.Contains(t, output, "failed to read SBOM, skipping comparison with the scanned libraries")
+	})
+}
+
 func TestPersistScannedProjects(t *testing.T) {
 	t.Parallel()
 	t.Run("write 1 scanned projects", func(t *testing.T) {
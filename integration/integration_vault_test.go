@@ -63,6 +63,118 @@ func TestGetVaultSecret(t *testing.T) {
 	assert.Equal(t, "value1", secret["key1"])
 	assert.Equal(t, "value2", secret["key2"])
 
+	// pin a version of a secret in a KV engine (v2)
+	err = client.WriteKvSecret("secret/test", map[string]string{"key1": "newValue1"})
+	assert.NoError(t, err)
+	secret, err = client.GetKvSecret("secret/test?version=1")
+	assert.NoError(t, err)
+	assert.Equal(t, "value1", secret["key1"])
+	secret, err = client.GetKvSecret("secret/test")
+	assert.NoError(t, err)
+	assert.Equal(t, "newValue1", secret["key1"])
+
+	_, err = client.GetKvSecret("kv/test?version=1")
+	assert.EqualError(t, err, "Reading version 1 of secret 'kv/test' requires KV Engine in version 2")
+}
+
+func TestGetVaultDynamicSecret(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const testToken = "vault-token"
+
+	// the database secrets engine issues credentials for a postgres database
+	postgresReq := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			AlwaysPullImage: true,
+			Image:           "postgres:13",
+			ExposedPorts:    []string{"5432/tcp"},
+			Env:             map[string]string{"POSTGRES_PASSWORD": "postgres"},
+			WaitingFor:      wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(60 * time.Second)},
+		Started: true,
+	}
+	postgresContainer, err := testcontainers.GenericContainer(ctx, postgresReq)
+	assert.NoError(t, err)
+	defer postgresContainer.Terminate(ctx)
+	postgresIP, err := postgresContainer.ContainerIP(ctx)
+	assert.NoError(t, err)
+
+	vaultReq := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			AlwaysPullImage: true,
+			Image:           "vault:1.4.3",
+			ExposedPorts:    []string{"8200/tcp"},
+			Env:             map[string]string{"VAULT_DEV_ROOT_TOKEN_ID": testToken},
+			WaitingFor:      wait.ForLog("Vault server started!").WithStartupTimeout(20 * time.Second)},
+		Started: true,
+	}
+	vaultContainer, err := testcontainers.GenericContainer(ctx, vaultReq)
+	assert.NoError(t, err)
+	defer vaultContainer.Terminate(ctx)
+
+	ip, err := vaultContainer.Host(ctx)
+	assert.NoError(t, err)
+	port, err := vaultContainer.MappedPort(ctx, "8200")
+	host := fmt.Sprintf("http://%s:%s", ip, port.Port())
+	config := &vault.Config{Config: &api.Config{Address: host}}
+	setupVaultDatabaseEngine(t, config, testToken, fmt.Sprintf("%s:5432", postgresIP))
+
+	adminClient, err := api.NewClient(config.Config)
+	assert.NoError(t, err)
+	adminClient.SetToken(testToken)
+	leases := func() []interface{} {
+		secret, err := adminClient.Logical().List("sys/leases/lookup/database/creds/readonly")
+		assert.NoError(t, err)
+		if secret == nil {
+			return nil
+		}
+		keys, _ := secret.Data["keys"].([]interface{})
+		return keys
+	}
+
+	client, err := vault.NewClient(config, testToken)
+	assert.NoError(t, err)
+	secret, err := client.GetDynamicSecret("database/creds/readonly")
+	assert.NoError(t, err)
+	assert.Contains(t, secret["username"], "v-token-readonly-")
+	assert.NotEmpty(t, secret["password"])
+
+	// credentials are requested only once per path
+	secondSecret, err := client.GetDynamicSecret("database/creds/readonly")
+	assert.NoError(t, err)
+	assert.Equal(t, secret, secondSecret)
+	assert.Len(t, leases(), 1)
+
+	// the token of the dev server must not be revoked, thus only the leases are revoked
+	err = client.RevokeLeases()
+	assert.NoError(t, err)
+	assert.Empty(t, leases())
+}
+
+func setupVaultDatabaseEngine(t *testing.T, config *vault.Config, token, postgresAddress string) {
+	t.Helper()
+	client, err := api.NewClient(config.Config)
+	assert.NoError(t, err)
+	client.SetToken(token)
+
+	_, err = client.Logical().Write("sys/mounts/database", SecretData{"type": "database"})
+	assert.NoError(t, err)
+
+	_, err = client.Logical().Write("database/config/postgres", SecretData{
+		"plugin_name":    "postgresql-database-plugin",
+		"allowed_roles":  "readonly",
+		"connection_url": fmt.Sprintf("postgresql://{{username}}:{{password}}@%s/postgres?sslmode=disable", postgresAddress),
+		"username":       "postgres",
+		"password":       "postgres",
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Logical().Write("database/roles/readonly", SecretData{
+		"db_name":             "postgres",
+		"creation_statements": `CREATE ROLE "{{name}}" WITH LOGIN PASSWORD '{{password}}' VALID UNTIL '{{expiration}}';`,
+		"default_ttl":         "1h",
+		"max_ttl":             "24h",
+	})
+	assert.NoError(t, err)
 }
 
 func TestWriteVaultSecret(t *testing.T) {
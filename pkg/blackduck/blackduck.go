@@ -0,0 +1,198 @@
+package blackduck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/pkg/errors"
+)
+
+// Media types of the Black Duck REST API
+const (
+	HEADER_USER_V4    = "application/vnd.blackducksoftware.user-4+json"
+	HEADER_PROJECT_V4 = "application/vnd.blackducksoftware.project-detail-4+json"
+	HEADER_BOM_V6     = "application/vnd.blackducksoftware.bill-of-materials-6+json"
+)
+
+// Client is the client communicating with the Black Duck backend
+type Client struct {
+	serverURL   string
+	token       string
+	bearerToken string
+	httpClient  piperhttp.Sender
+}
+
+// Meta defines the metadata of a Black Duck resource
+type Meta struct {
+	Href string `json:"href,omitempty"`
+}
+
+// Projects defines the response of a project search
+type Projects struct {
+	TotalCount int       `json:"totalCount,omitempty"`
+	Items      []Project `json:"items,omitempty"`
+}
+
+// Project defines a Black Duck project
+type Project struct {
+	Name     string `json:"name,omitempty"`
+	Metadata Meta   `json:"_meta,omitempty"`
+}
+
+// ProjectVersions defines the response of a project version search
+type ProjectVersions struct {
+	TotalCount int              `json:"totalCount,omitempty"`
+	Items      []ProjectVersion `json:"items,omitempty"`
+}
+
+// ProjectVersion defines a version of a Black Duck project
+type ProjectVersion struct {
+	Name     string `json:"versionName,omitempty"`
+	Metadata Meta   `json:"_meta,omitempty"`
+}
+
+// Vulnerabilities defines the vulnerable components of a project version
+type Vulnerabilities struct {
+	TotalCount int             `json:"totalCount,omitempty"`
+	Items      []Vulnerability `json:"items,omitempty"`
+}
+
+// Vulnerability defines a vulnerability of a component of a project version
+type Vulnerability struct {
+	Name                         string `json:"componentName,omitempty"`
+	Version                      string `json:"componentVersionName,omitempty"`
+	VulnerabilityWithRemediation `json:"vulnerabilityWithRemediation,omitempty"`
+}
+
+// VulnerabilityWithRemediation defines the details and the remediation status of a vulnerability
+type VulnerabilityWithRemediation struct {
+	VulnerabilityName string  `json:"vulnerabilityName,omitempty"`
+	BaseScore         float64 `json:"baseScore,omitempty"`
+	OverallScore      float64 `json:"overallScore,omitempty"`
+	Severity          string  `json:"severity,omitempty"`
+	RemediationStatus string  `json:"remediationStatus,omitempty"`
+	Description       string  `json:"description,omitempty"`
+}
+
+// NewClient creates a new Black Duck client authenticating with the given API token
+func NewClient(token, serverURL string, httpClient piperhttp.Sender) Client {
+	return Client{
+		serverURL:  strings.TrimSuffix(serverURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// GetProject returns the project with the given name
+func (b *Client) GetProject(projectName string) (*Project, error) {
+	projects := Projects{}
+	if err := b.get(b.apiURL("/api/projects", "name:"+projectName), HEADER_PROJECT_V4, &projects); err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch project '%v'", projectName)
+	}
+	for _, project := range projects.Items {
+		if project.Name == projectName {
+			return &project, nil
+		}
+	}
+	return nil, errors.Errorf("project '%v' not found", projectName)
+}
+
+// GetProjectVersion returns the version of the project with the given names
+func (b *Client) GetProjectVersion(projectName, versionName string) (*ProjectVersion, error) {
+	project, err := b.GetProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+	versions := ProjectVersions{}
+	if err := b.get(b.resourceURL(project.Metadata.Href, "/versions", "versionName:"+versionName), HEADER_PROJECT_V4, &versions); err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch version '%v' of project '%v'", versionName, projectName)
+	}
+	for _, version := range versions.Items {
+		if version.Name == versionName {
+			return &version, nil
+		}
+	}
+	return nil, errors.Errorf("version '%v' of project '%v' not found", versionName, projectName)
+}
+
+// GetVulnerabilities returns the vulnerabilities of all components of the project version
+func (b *Client) GetVulnerabilities(projectName, versionName string) (*Vulnerabilities, error) {
+	version, err := b.GetProjectVersion(projectName, versionName)
+	if err != nil {
+		return nil, err
+	}
+	vulnerabilities := Vulnerabilities{}
+	if err := b.get(b.resourceURL(version.Metadata.Href, "/vulnerable-bom-components", ""), HEADER_BOM_V6, &vulnerabilities); err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch vulnerabilities of version '%v' of project '%v'", versionName, projectName)
+	}
+	return &vulnerabilities, nil
+}
+
+func (b *Client) apiURL(path, query string) string {
+	return b.resourceURL(b.serverURL+path, "", query)
+}
+
+func (b *Client) resourceURL(href, path, query string) string {
+	values := url.Values{}
+	values.Set("limit", "999")
+	if len(query) > 0 {
+		values.Set("q", query)
+	}
+	return fmt.Sprintf("%v%v?%v", href, path, values.Encode())
+}
+
+func (b *Client) get(resourceURL, mediaType string, result interface{}) error {
+	if err := b.authenticate(); err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Add("Accept", mediaType)
+	header.Add("Authorization", "Bearer "+b.bearerToken)
+	response, err := b.httpClient.SendRequest(http.MethodGet, resourceURL, nil, header, nil)
+	if err != nil {
+		return errors.Wrap(err, "request to Black Duck failed")
+	}
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+	if err := json.Unmarshal(content, result); err != nil {
+		return errors.Wrap(err, "failed to parse response")
+	}
+	return nil
+}
+
+// authenticate exchanges the API token for a bearer token
+func (b *Client) authenticate() error {
+	if len(b.bearerToken) > 0 {
+		return nil
+	}
+	header := http.Header{}
+	header.Add("Accept", HEADER_USER_V4)
+	header.Add("Authorization", "token "+b.token)
+	b.httpClient.SetOptions(piperhttp.ClientOptions{TransportTimeout: time.Minute})
+	response, err := b.httpClient.SendRequest(http.MethodPost, b.serverURL+"/api/tokens/authenticate", nil, header, nil)
+	if err != nil {
+		return errors.Wrap(err, "authentication to Black Duck failed")
+	}
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read authentication response")
+	}
+	var auth struct {
+		BearerToken string `json:"bearerToken"`
+	}
+	if err := json.Unmarshal(content, &auth); err != nil || len(auth.BearerToken) == 0 {
+		return errors.New("authentication to Black Duck failed: no bearer token received")
+	}
+	b.bearerToken = auth.BearerToken
+	return nil
+}
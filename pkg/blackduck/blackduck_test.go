@@ -0,0 +1,81 @@
+package blackduck
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/tokens/authenticate" {
+			assert.Equal(t, http.MethodPost, req.Method)
+			if req.Header.Get("Authorization") != "token apiToken" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			rw.Write([]byte(`{"bearerToken":"bearer"}`))
+			return
+		}
+		assert.Equal(t, "Bearer bearer", req.Header.Get("Authorization"))
+		switch req.URL.Path {
+		case "/api/projects":
+			if req.URL.Query().Get("q") != "name:SHC-PiperTest" {
+				rw.Write([]byte(`{"totalCount":0,"items":[]}`))
+				return
+			}
+			fmt.Fprintf(rw, `{"totalCount":2,"items":[{"name":"SHC-PiperTest-Other","_meta":{"href":"%[1]v/api/projects/2"}},{"name":"SHC-PiperTest","_meta":{"href":"%[1]v/api/projects/1"}}]}`, server.URL)
+		case "/api/projects/1/versions":
+			if req.URL.Query().Get("q") != "versionName:1.0" {
+				rw.Write([]byte(`{"totalCount":0,"items":[]}`))
+				return
+			}
+			fmt.Fprintf(rw, `{"totalCount":1,"items":[{"versionName":"1.0","_meta":{"href":"%v/api/projects/1/versions/3"}}]}`, server.URL)
+		case "/api/projects/1/versions/3/vulnerable-bom-components":
+			assert.Equal(t, HEADER_BOM_V6, req.Header.Get("Accept"))
+			rw.Write([]byte(`{"totalCount":1,"items":[{"componentName":"log4j","componentVersionName":"2.14.1","vulnerabilityWithRemediation":{"vulnerabilityName":"CVE-2021-44228","baseScore":10.0,"overallScore":10.0,"severity":"CRITICAL","remediationStatus":"NEW","description":"JNDI lookup"}}]}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestGetVulnerabilities(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		client := NewClient("apiToken", server.URL+"/", &piperhttp.Client{})
+		vulnerabilities, err := client.GetVulnerabilities("SHC-PiperTest", "1.0")
+		require.NoError(t, err)
+		require.Len(t, vulnerabilities.Items, 1)
+		assert.Equal(t, "log4j", vulnerabilities.Items[0].Name)
+		assert.Equal(t, "CVE-2021-44228", vulnerabilities.Items[0].VulnerabilityName)
+		assert.Equal(t, 10.0, vulnerabilities.Items[0].OverallScore)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		client := NewClient("apiToken", server.URL, &piperhttp.Client{})
+		_, err := client.GetProject("SHC-PiperTest-Unknown")
+		assert.EqualError(t, err, "project 'SHC-PiperTest-Unknown' not found")
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		client := NewClient("apiToken", server.URL, &piperhttp.Client{})
+		_, err := client.GetProjectVersion("SHC-PiperTest", "2.0")
+		assert.EqualError(t, err, "version '2.0' of project 'SHC-PiperTest' not found")
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		client := NewClient("wrongToken", server.URL, &piperhttp.Client{})
+		_, err := client.GetProject("SHC-PiperTest")
+		assert.Contains(t, fmt.Sprint(err), "failed to fetch project 'SHC-PiperTest': authentication to Black Duck failed")
+	})
+}
@@ -0,0 +1,40 @@
+package blackduck
+
+import (
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+)
+
+// ConvertVulnerabilitiesToFindings maps the vulnerabilities of a Black Duck project version to the common finding model.
+// Vulnerabilities which are e.g. ignored or patched are suppressed.
+func ConvertVulnerabilitiesToFindings(vulnerabilities *Vulnerabilities) []vulnerability.Finding {
+	findings := []vulnerability.Finding{}
+	if vulnerabilities == nil {
+		return findings
+	}
+	for _, item := range vulnerabilities.Items {
+		details := item.VulnerabilityWithRemediation
+		// the overall score contains the temporal metrics and is preferred over the base score
+		score := details.OverallScore
+		if score <= 0 {
+			score = details.BaseScore
+		}
+		finding := vulnerability.Finding{
+			ID:          details.VulnerabilityName,
+			CVE:         vulnerability.CVEFromID(details.VulnerabilityName),
+			Component:   &vulnerability.Component{Name: item.Name, Version: item.Version},
+			CVSS:        score,
+			Severity:    details.Severity,
+			Description: details.Description,
+			URL:         vulnerabilityURL(details.VulnerabilityName),
+			Tools:       []string{"Black Duck Detect"},
+			Status:      vulnerability.StatusOpen,
+		}
+		if suppressedRemediationStates[strings.ToUpper(details.RemediationStatus)] {
+			finding.Status, finding.Justification = vulnerability.StatusSuppressed, details.RemediationStatus
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
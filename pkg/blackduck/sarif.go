@@ -0,0 +1,72 @@
+package blackduck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// remediation states of Black Duck vulnerabilities which are reported as suppressed results
+var suppressedRemediationStates = map[string]bool{
+	"IGNORED":              true,
+	"MITIGATED":            true,
+	"PATCHED":              true,
+	"REMEDIATION_COMPLETE": true,
+}
+
+// ConvertVulnerabilitiesToSarif converts the vulnerabilities of a Black Duck project version into a SARIF log
+func ConvertVulnerabilitiesToSarif(vulnerabilities *Vulnerabilities, projectName, versionName string) format.SARIF {
+	run := format.NewRun("Black Duck Detect", "", "https://www.synopsys.com/software-integrity/security-testing/software-composition-analysis.html")
+	if vulnerabilities == nil {
+		return format.NewSARIF(run)
+	}
+	for _, vulnerability := range vulnerabilities.Items {
+		details := vulnerability.VulnerabilityWithRemediation
+		score := details.OverallScore
+		if score <= 0 {
+			score = details.BaseScore
+		}
+
+		run.AddRule(format.SarifRule{
+			ID:               details.VulnerabilityName,
+			Name:             details.VulnerabilityName,
+			ShortDescription: &format.Message{Text: details.VulnerabilityName},
+			FullDescription:  &format.Message{Text: details.Description},
+			HelpURI:          vulnerabilityURL(details.VulnerabilityName),
+			Properties:       &format.SarifRuleProperties{Tags: []string{"security", "vulnerability"}, SecuritySeverity: format.SecuritySeverity(score)},
+		})
+
+		level := format.SarifLevelFromCVSS(score)
+		if score <= 0 {
+			level = format.SarifLevelFromSeverity(details.Severity)
+		}
+		component := fmt.Sprintf("%v:%v", vulnerability.Name, vulnerability.Version)
+		result := format.Results{
+			RuleID:              details.VulnerabilityName,
+			Level:               level,
+			Message:             format.Message{Text: fmt.Sprintf("%v in %v", details.VulnerabilityName, component)},
+			PartialFingerprints: map[string]string{"blackduckVulnerability": fmt.Sprintf("%v/%v/%v/%v", projectName, versionName, component, details.VulnerabilityName)},
+			Properties: map[string]string{
+				"project":           projectName,
+				"version":           versionName,
+				"component":         component,
+				"severity":          details.Severity,
+				"cvssScore":         fmt.Sprint(score),
+				"remediationStatus": details.RemediationStatus,
+			},
+		}
+		if suppressedRemediationStates[strings.ToUpper(details.RemediationStatus)] {
+			result.Suppressions = []format.Suppression{{Kind: "external", Status: "accepted", Justification: details.RemediationStatus}}
+		}
+		run.AddResult(result)
+	}
+	return format.NewSARIF(run)
+}
+
+func vulnerabilityURL(name string) string {
+	if strings.HasPrefix(name, "CVE-") {
+		return "https://nvd.nist.gov/vuln/detail/" + name
+	}
+	return ""
+}
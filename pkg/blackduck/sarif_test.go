@@ -0,0 +1,48 @@
+package blackduck
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertVulnerabilitiesToSarif(t *testing.T) {
+	t.Run("vulnerabilities", func(t *testing.T) {
+		vulnerabilities := Vulnerabilities{Items: []Vulnerability{
+			{Name: "log4j", Version: "2.14.1", VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "CVE-2021-44228", BaseScore: 10.0, Severity: "CRITICAL", RemediationStatus: "NEW", Description: "JNDI lookup"}},
+			{Name: "commons-text", Version: "1.9", VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "BDSA-2022-2771", OverallScore: 5.3, BaseScore: 9.8, Severity: "HIGH", RemediationStatus: "IGNORED"}},
+			{Name: "lodash", Version: "4.17.20", VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "CVE-2021-23337", Severity: "LOW"}},
+		}}
+
+		sarif := ConvertVulnerabilitiesToSarif(&vulnerabilities, "SHC-PiperTest", "1.0")
+
+		require.Len(t, sarif.Runs, 1)
+		run := sarif.Runs[0]
+		assert.Equal(t, "Black Duck Detect", run.Tool.Driver.Name)
+		require.Len(t, run.Tool.Driver.Rules, 3)
+		assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-44228", run.Tool.Driver.Rules[0].HelpURI)
+		assert.Equal(t, "10.0", run.Tool.Driver.Rules[0].Properties.SecuritySeverity)
+		assert.Equal(t, "", run.Tool.Driver.Rules[1].HelpURI)
+		// the overall score takes precedence over the base score
+		assert.Equal(t, "5.3", run.Tool.Driver.Rules[1].Properties.SecuritySeverity)
+
+		require.Len(t, run.Results, 3)
+		assert.Equal(t, format.SarifLevelError, run.Results[0].Level)
+		assert.Equal(t, "CVE-2021-44228 in log4j:2.14.1", run.Results[0].Message.Text)
+		assert.Equal(t, "SHC-PiperTest/1.0/log4j:2.14.1/CVE-2021-44228", run.Results[0].PartialFingerprints["blackduckVulnerability"])
+		assert.Empty(t, run.Results[0].Suppressions)
+		assert.Equal(t, format.SarifLevelWarning, run.Results[1].Level)
+		assert.Equal(t, []format.Suppression{{Kind: "external", Status: "accepted", Justification: "IGNORED"}}, run.Results[1].Suppressions)
+		// the severity is used in case no score is available
+		assert.Equal(t, format.SarifLevelNote, run.Results[2].Level)
+		assert.Equal(t, 2, run.Results[2].RuleIndex)
+	})
+
+	t.Run("no vulnerabilities", func(t *testing.T) {
+		sarif := ConvertVulnerabilitiesToSarif(nil, "SHC-PiperTest", "1.0")
+		require.Len(t, sarif.Runs, 1)
+		assert.Empty(t, sarif.Runs[0].Results)
+	})
+}
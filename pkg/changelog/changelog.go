@@ -0,0 +1,270 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// Sections of a release as defined by Keep a Changelog (https://keepachangelog.com/en/1.0.0/)
+const (
+	SectionAdded      = "Added"
+	SectionChanged    = "Changed"
+	SectionDeprecated = "Deprecated"
+	SectionRemoved    = "Removed"
+	SectionFixed      = "Fixed"
+	SectionSecurity   = "Security"
+)
+
+var sectionOrder = []string{SectionAdded, SectionChanged, SectionDeprecated, SectionRemoved, SectionFixed, SectionSecurity}
+
+const changelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+
+// references in the header like "(#12)" as created by GitHub for squashed pull requests
+var headerReference = regexp.MustCompile(`\s*\(#(\d+)\)`)
+var footerReference = regexp.MustCompile(`(?mi)^(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?|refs?)[: ]\s*#(\d+)`)
+var linkDefinition = regexp.MustCompile(`(?m)^\[[^\]]+\]: `)
+var releaseHeading = regexp.MustCompile(`(?m)^## .*$`)
+
+// Entry is a single change of a release
+type Entry struct {
+	Scope       string
+	Description string
+	Breaking    bool
+	Commit      string
+	// References contains the numbers of the referenced issues or pull requests
+	References []int
+}
+
+// Release contains the changes of a version grouped by the sections of Keep a Changelog
+type Release struct {
+	Version      string
+	Date         time.Time
+	Sections     map[string][]Entry
+	Contributors []string
+	// RepositoryURL is used to link commits, issues and pull requests, e.g. https://github.com/org/repo
+	// No links are created in case it is empty.
+	RepositoryURL string
+	// CompareURL links the changes since the previous release, e.g. https://github.com/org/repo/compare/1.0.0...1.1.0
+	CompareURL string
+}
+
+// NewRelease groups the commits following the Conventional Commits specification by the sections of Keep a Changelog.
+// Commits of other types like "docs" or "chore" as well as commits not following the specification are not listed,
+// their authors are contained in the contributors nonetheless.
+func NewRelease(version string, date time.Time, commits object.CommitIter) (*Release, error) {
+	release := &Release{Version: version, Date: date, Sections: map[string][]Entry{}}
+	contributors := map[string]bool{}
+
+	err := commits.ForEach(func(c *object.Commit) error {
+		if len(c.Author.Name) > 0 {
+			contributors[c.Author.Name] = true
+		}
+		commit, ok := versioning.ParseConventionalCommit(c.Message)
+		if !ok {
+			return nil
+		}
+		section := sectionOf(commit)
+		if len(section) == 0 {
+			return nil
+		}
+		release.Sections[section] = append(release.Sections[section], newEntry(commit, c.Hash.String()))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect changes")
+	}
+
+	for contributor := range contributors {
+		release.Contributors = append(release.Contributors, contributor)
+	}
+	sort.Strings(release.Contributors)
+	return release, nil
+}
+
+func sectionOf(commit versioning.ConventionalCommit) string {
+	switch {
+	case commit.Type == "security" || commit.Scope == "security":
+		return SectionSecurity
+	case commit.Type == "feat":
+		return SectionAdded
+	case commit.Type == "fix":
+		return SectionFixed
+	case commit.Type == "deprecate":
+		return SectionDeprecated
+	case commit.Type == "remove":
+		return SectionRemoved
+	case commit.Type == "perf" || commit.Type == "refactor" || commit.Type == "revert" || commit.Breaking:
+		return SectionChanged
+	}
+	return ""
+}
+
+func newEntry(commit versioning.ConventionalCommit, hash string) Entry {
+	entry := Entry{Scope: commit.Scope, Breaking: commit.Breaking, Commit: hash}
+	for _, match := range headerReference.FindAllStringSubmatch(commit.Description, -1) {
+		entry.References = appendReference(entry.References, match[1])
+	}
+	for _, match := range footerReference.FindAllStringSubmatch(commit.Body, -1) {
+		entry.References = appendReference(entry.References, match[1])
+	}
+	entry.Description = strings.TrimSpace(headerReference.ReplaceAllString(commit.Description, ""))
+	return entry
+}
+
+func appendReference(references []int, reference string) []int {
+	number, err := strconv.Atoi(reference)
+	if err != nil {
+		return references
+	}
+	for _, existing := range references {
+		if existing == number {
+			return references
+		}
+	}
+	return append(references, number)
+}
+
+// Empty returns true in case the release does not contain any listed change
+func (r *Release) Empty() bool {
+	for _, entries := range r.Sections {
+		if len(entries) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Notes renders the sections and contributors of the release as markdown, e.g. for the body of a GitHub release
+func (r *Release) Notes() string {
+	var notes strings.Builder
+	notes.WriteString(r.changes())
+	if len(r.Contributors) > 0 {
+		notes.WriteString("### Contributors\n\n")
+		for _, contributor := range r.Contributors {
+			notes.WriteString(fmt.Sprintf("- %v\n", contributor))
+		}
+		notes.WriteString("\n")
+	}
+	return notes.String()
+}
+
+func (r *Release) changes() string {
+	var notes strings.Builder
+	for _, section := range sectionOrder {
+		if len(r.Sections[section]) == 0 {
+			continue
+		}
+		notes.WriteString(fmt.Sprintf("### %v\n\n", section))
+		for _, entry := range r.Sections[section] {
+			notes.WriteString(r.renderEntry(entry) + "\n")
+		}
+		notes.WriteString("\n")
+	}
+	return notes.String()
+}
+
+// Markdown renders the release as section of a changelog in the format of Keep a Changelog.
+// The contributors are not contained since they are not part of the format.
+func (r *Release) Markdown() string {
+	title := r.Version
+	if len(r.CompareURL) > 0 {
+		title = fmt.Sprintf("[%v]", r.Version)
+	}
+	return fmt.Sprintf("## %v - %v\n\n%v", title, r.Date.Format("2006-01-02"), r.changes())
+}
+
+func (r *Release) renderEntry(entry Entry) string {
+	text := "- "
+	if entry.Breaking {
+		text += "**BREAKING** "
+	}
+	if len(entry.Scope) > 0 {
+		text += fmt.Sprintf("**%v:** ", entry.Scope)
+	}
+	text += entry.Description
+	for _, reference := range entry.References {
+		text += " (" + r.link(fmt.Sprintf("#%v", reference), fmt.Sprintf("issues/%v", reference)) + ")"
+	}
+	if len(entry.Commit) >= 7 {
+		text += " (" + r.link(entry.Commit[:7], "commit/"+entry.Commit) + ")"
+	}
+	return text
+}
+
+func (r *Release) link(text, path string) string {
+	if len(r.RepositoryURL) == 0 {
+		return text
+	}
+	// GitHub redirects issue links to pull requests if required
+	return fmt.Sprintf("[%v](%v/%v)", text, strings.TrimSuffix(r.RepositoryURL, "/"), path)
+}
+
+// UpdateChangelog adds the release to the content of a changelog in the format of Keep a Changelog.
+// The release is inserted above the latest release, a new changelog is created in case the content is empty.
+// An existing entry of the same version is replaced, e.g. when the release is published again.
+func UpdateChangelog(changelog []byte, release *Release) []byte {
+	content := string(changelog)
+	if len(strings.TrimSpace(content)) == 0 {
+		content = changelogHeader
+	}
+
+	// the entry ends with the next release or the link definitions at the end
+	end := len(content)
+	if definition := linkDefinition.FindStringIndex(content); definition != nil {
+		end = definition[0]
+	}
+	existingHeading := regexp.MustCompile(fmt.Sprintf(`(?m)^## (?:\[%[1]v\]|%[1]v)(?: .*)?$`, regexp.QuoteMeta(release.Version)))
+	if heading := existingHeading.FindStringIndex(content); heading != nil && heading[0] < end {
+		if next := releaseHeading.FindStringIndex(content[heading[1]:]); next != nil && heading[1]+next[0] < end {
+			end = heading[1] + next[0]
+		}
+		log.Entry().Infof("Replacing existing entry of version %v in changelog", release.Version)
+		content = strings.TrimRight(content[:heading[0]], "\n") + "\n\n" + release.Markdown() + strings.TrimLeft(content[end:], "\n")
+	} else {
+		// insert above the latest release, otherwise above the link definitions at the end
+		position := end
+		for _, heading := range releaseHeading.FindAllStringIndex(content, -1) {
+			if !strings.HasPrefix(content[heading[0]:heading[1]], "## [Unreleased]") && heading[0] < position {
+				position = heading[0]
+				break
+			}
+		}
+		prefix := strings.TrimRight(content[:position], "\n") + "\n\n"
+		content = prefix + release.Markdown() + strings.TrimLeft(content[position:], "\n")
+	}
+
+	if len(release.CompareURL) > 0 {
+		definition := fmt.Sprintf("[%v]: %v\n", release.Version, release.CompareURL)
+		existingDefinition := regexp.MustCompile(fmt.Sprintf(`(?m)^\[%v\]: .*\n?`, regexp.QuoteMeta(release.Version)))
+		if existing := existingDefinition.FindStringIndex(content); existing != nil {
+			content = content[:existing[0]] + definition + content[existing[1]:]
+		} else if existing := linkDefinition.FindStringIndex(content); existing != nil {
+			// keep the link of the unreleased changes first
+			position := existing[0]
+			if strings.HasPrefix(content[position:], "[Unreleased]: ") {
+				position += strings.Index(content[position:], "\n") + 1
+			}
+			content = content[:position] + definition + content[position:]
+		} else {
+			content = strings.TrimRight(content, "\n") + "\n\n" + definition
+		}
+	}
+	return []byte(strings.TrimRight(content, "\n") + "\n")
+}
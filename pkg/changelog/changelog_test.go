@@ -0,0 +1,233 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commitIterMock struct {
+	commits []*object.Commit
+}
+
+func (c *commitIterMock) Next() (*object.Commit, error) {
+	panic("implement me")
+}
+
+func (c *commitIterMock) ForEach(cb func(*object.Commit) error) error {
+	for _, commit := range c.commits {
+		if err := cb(commit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *commitIterMock) Close() {}
+
+func commit(hash, author, message string) *object.Commit {
+	return &object.Commit{Hash: plumbing.NewHash(hash), Author: object.Signature{Name: author}, Message: message}
+}
+
+func testCommits() *commitIterMock {
+	return &commitIterMock{commits: []*object.Commit{
+		commit("1111111111111111111111111111111111111111", "Jane Doe", "feat(parser): support arrays (#12)\n\nCloses #10"),
+		commit("2222222222222222222222222222222222222222", "John Doe", "fix: handle empty input"),
+		commit("3333333333333333333333333333333333333333", "John Doe", "docs: update readme"),
+		commit("4444444444444444444444444444444444444444", "Alice", "Merge branch 'main'"),
+		commit("5555555555555555555555555555555555555555", "Jane Doe", "refactor(api)!: rename endpoints"),
+		commit("6666666666666666666666666666666666666666", "Jane Doe", "fix(security): escape user input\n\nFixes #7"),
+	}}
+}
+
+func TestNewRelease(t *testing.T) {
+	release, err := NewRelease("1.1.0", time.Date(2020, 10, 12, 0, 0, 0, 0, time.UTC), testCommits())
+
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{{Scope: "parser", Description: "support arrays", Commit: "1111111111111111111111111111111111111111", References: []int{12, 10}}}, release.Sections[SectionAdded])
+	assert.Equal(t, []Entry{{Description: "handle empty input", Commit: "2222222222222222222222222222222222222222"}}, release.Sections[SectionFixed])
+	assert.Equal(t, []Entry{{Scope: "api", Description: "rename endpoints", Breaking: true, Commit: "5555555555555555555555555555555555555555"}}, release.Sections[SectionChanged])
+	assert.Equal(t, []Entry{{Scope: "security", Description: "escape user input", Commit: "6666666666666666666666666666666666666666", References: []int{7}}}, release.Sections[SectionSecurity])
+	assert.Equal(t, []string{"Alice", "Jane Doe", "John Doe"}, release.Contributors)
+	assert.False(t, release.Empty())
+
+	empty, err := NewRelease("1.1.1", time.Now(), &commitIterMock{commits: []*object.Commit{commit("3333333333333333333333333333333333333333", "John Doe", "docs: update readme")}})
+	require.NoError(t, err)
+	assert.True(t, empty.Empty())
+}
+
+func TestMarkdown(t *testing.T) {
+	release, err := NewRelease("1.1.0", time.Date(2020, 10, 12, 0, 0, 0, 0, time.UTC), testCommits())
+	require.NoError(t, err)
+
+	t.Run("with links", func(t *testing.T) {
+		release.RepositoryURL = "https://github.com/SAP/jenkins-library"
+		release.CompareURL = "https://github.com/SAP/jenkins-library/compare/1.0.0...1.1.0"
+
+		assert.Equal(t, `## [1.1.0] - 2020-10-12
+
+### Added
+
+- **parser:** support arrays ([#12](https://github.com/SAP/jenkins-library/issues/12)) ([#10](https://github.com/SAP/jenkins-library/issues/10)) ([1111111](https://github.com/SAP/jenkins-library/commit/1111111111111111111111111111111111111111))
+
+### Changed
+
+- **BREAKING** **api:** rename endpoints ([5555555](https://github.com/SAP/jenkins-library/commit/5555555555555555555555555555555555555555))
+
+### Fixed
+
+- handle empty input ([2222222](https://github.com/SAP/jenkins-library/commit/2222222222222222222222222222222222222222))
+
+### Security
+
+- **security:** escape user input ([#7](https://github.com/SAP/jenkins-library/issues/7)) ([6666666](https://github.com/SAP/jenkins-library/commit/6666666666666666666666666666666666666666))
+
+`, release.Markdown())
+	})
+
+	t.Run("without links", func(t *testing.T) {
+		release.RepositoryURL = ""
+		release.CompareURL = ""
+
+		notes := release.Markdown()
+		assert.Contains(t, notes, "## 1.1.0 - 2020-10-12\n")
+		assert.Contains(t, notes, "- **parser:** support arrays (#12) (#10) (1111111)\n")
+	})
+}
+
+func TestNotes(t *testing.T) {
+	release, err := NewRelease("1.1.0", time.Date(2020, 10, 12, 0, 0, 0, 0, time.UTC), testCommits())
+	require.NoError(t, err)
+
+	notes := release.Notes()
+
+	assert.True(t, strings.HasPrefix(notes, "### Added\n\n- **parser:** support arrays (#12) (#10) (1111111)\n"))
+	assert.True(t, strings.HasSuffix(notes, "### Contributors\n\n- Alice\n- Jane Doe\n- John Doe\n\n"))
+}
+
+func TestUpdateChangelog(t *testing.T) {
+	release := &Release{
+		Version:    "1.1.0",
+		Date:       time.Date(2020, 10, 12, 0, 0, 0, 0, time.UTC),
+		Sections:   map[string][]Entry{SectionFixed: {{Description: "handle empty input"}}},
+		CompareURL: "https://github.com/org/repo/compare/1.0.0...1.1.0",
+	}
+
+	t.Run("new changelog", func(t *testing.T) {
+		changelog := UpdateChangelog(nil, release)
+
+		assert.Equal(t, `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+
+## [1.1.0] - 2020-10-12
+
+### Fixed
+
+- handle empty input
+
+[1.1.0]: https://github.com/org/repo/compare/1.0.0...1.1.0
+`, string(changelog))
+	})
+
+	t.Run("existing changelog", func(t *testing.T) {
+		existing := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- work in progress
+
+## [1.0.0] - 2020-09-01
+
+### Added
+
+- initial release
+
+[Unreleased]: https://github.com/org/repo/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/org/repo/releases/tag/1.0.0
+`
+		changelog := UpdateChangelog([]byte(existing), release)
+
+		assert.Equal(t, `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- work in progress
+
+## [1.1.0] - 2020-10-12
+
+### Fixed
+
+- handle empty input
+
+## [1.0.0] - 2020-09-01
+
+### Added
+
+- initial release
+
+[Unreleased]: https://github.com/org/repo/compare/1.0.0...HEAD
+[1.1.0]: https://github.com/org/repo/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/org/repo/releases/tag/1.0.0
+`, string(changelog))
+	})
+
+	t.Run("version already contained", func(t *testing.T) {
+		existing := `# Changelog
+
+## [1.1.0] - 2020-10-10
+
+### Fixed
+
+- outdated entry
+
+## [1.0.0] - 2020-09-01
+
+### Added
+
+- initial release
+
+[1.1.0]: https://github.com/org/repo/compare/0.9.0...1.1.0
+[1.0.0]: https://github.com/org/repo/releases/tag/1.0.0
+`
+		changelog := UpdateChangelog([]byte(existing), release)
+
+		assert.Equal(t, `# Changelog
+
+## [1.1.0] - 2020-10-12
+
+### Fixed
+
+- handle empty input
+
+## [1.0.0] - 2020-09-01
+
+### Added
+
+- initial release
+
+[1.1.0]: https://github.com/org/repo/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/org/repo/releases/tag/1.0.0
+`, string(changelog))
+	})
+
+	t.Run("version already contained as latest entry", func(t *testing.T) {
+		changelog := UpdateChangelog([]byte("# Changelog\n\n## 1.1.0 - 2020-10-10\n\n### Fixed\n\n- outdated entry\n"), release)
+
+		assert.Equal(t, "# Changelog\n\n## [1.1.0] - 2020-10-12\n\n### Fixed\n\n- handle empty input\n\n[1.1.0]: https://github.com/org/repo/compare/1.0.0...1.1.0\n", string(changelog))
+	})
+}
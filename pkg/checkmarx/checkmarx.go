@@ -159,16 +159,35 @@ type DetailedResult struct {
 
 // Query - Query Structure
 type Query struct {
-	XMLName xml.Name `xml:"Query"`
-	Results []Result `xml:"Result"`
+	XMLName  xml.Name `xml:"Query"`
+	ID       string   `xml:"id,attr"`
+	Name     string   `xml:"name,attr"`
+	Group    string   `xml:"group,attr"`
+	Language string   `xml:"Language,attr"`
+	Severity string   `xml:"Severity,attr"`
+	CweID    string   `xml:"cweId,attr"`
+	Results  []Result `xml:"Result"`
 }
 
 // Result - Result Structure
 type Result struct {
-	XMLName       xml.Name `xml:"Result"`
-	State         string   `xml:"state,attr"`
-	Severity      string   `xml:"Severity,attr"`
-	FalsePositive string   `xml:"FalsePositive,attr"`
+	XMLName       xml.Name   `xml:"Result"`
+	State         string     `xml:"state,attr"`
+	Severity      string     `xml:"Severity,attr"`
+	FalsePositive string     `xml:"FalsePositive,attr"`
+	FileName      string     `xml:"FileName,attr"`
+	Line          int        `xml:"Line,attr"`
+	Column        int        `xml:"Column,attr"`
+	Status        string     `xml:"Status,attr"`
+	DeepLink      string     `xml:"DeepLink,attr"`
+	Path          ResultPath `xml:"Path"`
+}
+
+// ResultPath - ResultPath Structure
+type ResultPath struct {
+	ResultID     string `xml:"ResultId,attr"`
+	PathID       string `xml:"PathId,attr"`
+	SimilarityID string `xml:"SimilarityId,attr"`
 }
 
 // SystemInstance is the client communicating with the Checkmarx backend
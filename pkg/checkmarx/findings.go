@@ -0,0 +1,38 @@
+package checkmarx
+
+import (
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+)
+
+// ConvertCxxmlToFindings maps the detailed XML results of a Checkmarx scan to the common finding model.
+// Results audited as false positive or not exploitable are suppressed.
+func ConvertCxxmlToFindings(detailedResult DetailedResult) []vulnerability.Finding {
+	findings := []vulnerability.Finding{}
+	for _, query := range detailedResult.Queries {
+		for _, result := range query.Results {
+			findings = append(findings, ConvertResultToFinding(query, result))
+		}
+	}
+	return findings
+}
+
+// ConvertResultToFinding maps a single result of a query to the common finding model
+func ConvertResultToFinding(query Query, result Result) vulnerability.Finding {
+	cwe := ""
+	if len(query.CweID) > 0 && query.CweID != "0" {
+		cwe = "CWE-" + query.CweID
+	}
+	finding := vulnerability.Finding{
+		ID:       query.Name,
+		CWE:      cwe,
+		Severity: result.Severity,
+		URL:      result.DeepLink,
+		Location: vulnerability.Location{File: result.FileName, Line: result.Line},
+		Tools:    []string{"Checkmarx"},
+		Status:   vulnerability.StatusOpen,
+	}
+	if result.FalsePositive == "True" || result.State == "1" {
+		finding.Status, finding.Justification = vulnerability.StatusSuppressed, AuditState(result.State)
+	}
+	return finding
+}
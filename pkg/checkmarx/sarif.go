@@ -0,0 +1,80 @@
+package checkmarx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// ConvertCxxmlToSarif converts the detailed XML results of a Checkmarx scan into a SARIF log.
+// Every query is represented as rule, results audited as false positive or not exploitable are marked as suppressed.
+func ConvertCxxmlToSarif(detailedResult DetailedResult) format.SARIF {
+	run := format.NewRun("Checkmarx", detailedResult.CheckmarxVersion, "https://checkmarx.com/product/cxsast-source-code-scanning/")
+	for _, query := range detailedResult.Queries {
+		ruleID := query.ID
+		if len(ruleID) == 0 {
+			ruleID = query.Name
+		}
+		description := strings.ReplaceAll(query.Name, "_", " ")
+		rule := format.SarifRule{
+			ID:                   ruleID,
+			Name:                 query.Name,
+			ShortDescription:     &format.Message{Text: description},
+			FullDescription:      &format.Message{Text: fmt.Sprintf("%v (%v, %v)", description, query.Language, query.Group)},
+			DefaultConfiguration: &format.DefaultConfiguration{Level: format.SarifLevelFromSeverity(query.Severity)},
+			Properties:           &format.SarifRuleProperties{Tags: []string{"security"}},
+		}
+		if len(query.CweID) > 0 && query.CweID != "0" {
+			rule.HelpURI = fmt.Sprintf("https://cwe.mitre.org/data/definitions/%v.html", query.CweID)
+			rule.Properties.Tags = append(rule.Properties.Tags, fmt.Sprintf("external/cwe/cwe-%v", query.CweID))
+		}
+		run.AddRule(rule)
+
+		for _, result := range query.Results {
+			sarifResult := format.Results{
+				RuleID: ruleID,
+				Level:  format.SarifLevelFromSeverity(result.Severity),
+				Message: format.Message{
+					Text:     fmt.Sprintf("%v in %v", description, result.FileName),
+					Markdown: fmt.Sprintf("[%v](%v) in %v", description, result.DeepLink, result.FileName),
+				},
+				Properties: map[string]string{
+					"auditState":    AuditState(result.State),
+					"falsePositive": result.FalsePositive,
+					"status":        result.Status,
+				},
+			}
+			if len(result.FileName) > 0 {
+				location := format.Location{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: result.FileName}}}
+				if result.Line > 0 {
+					location.PhysicalLocation.Region = &format.Region{StartLine: result.Line, StartColumn: result.Column}
+				}
+				sarifResult.Locations = []format.Location{location}
+			}
+			if len(result.Path.SimilarityID) > 0 {
+				sarifResult.PartialFingerprints = map[string]string{"checkmarxSimilarityId": result.Path.SimilarityID}
+			}
+			if result.FalsePositive == "True" || result.State == "1" {
+				sarifResult.Suppressions = []format.Suppression{{Kind: "external", Status: "accepted", Justification: AuditState(result.State)}}
+			}
+			run.AddResult(sarifResult)
+		}
+	}
+	return format.NewSARIF(run)
+}
+
+// AuditState maps the numeric state of a result to the audit state shown in the Checkmarx UI
+func AuditState(state string) string {
+	switch state {
+	case "1":
+		return "NotExploitable"
+	case "2":
+		return "Confirmed"
+	case "3":
+		return "Urgent"
+	case "4":
+		return "ProposedNotExploitable"
+	}
+	return "ToVerify"
+}
@@ -0,0 +1,68 @@
+package checkmarx
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertCxxmlToSarif(t *testing.T) {
+	data := `<?xml version="1.0" encoding="utf-8"?>
+<CxXMLResults InitiatorName="admin" Owner="admin" ScanId="1000005" ProjectId="2" ProjectName="Project 1" CheckmarxVersion="8.6.0" ScanType="Incremental">
+	<Query id="430" cweId="89" name="SQL_Injection" group="CSharp_High_Risk" Severity="High" Language="CSharp">
+		<Result NodeId="10000050002" FileName="bookstore/Login.cs" Status="Recurrent" Line="179" Column="103" FalsePositive="False" Severity="High" state="0" DeepLink="http://cx/ViewerMain.aspx?scanid=1000005&amp;projectid=2&amp;pathid=2">
+			<Path ResultId="1000005" PathId="2" SimilarityId="1765812516"/>
+		</Result>
+		<Result NodeId="10000050003" FileName="bookstore/Login.cs" Status="New" Line="180" Column="10" FalsePositive="False" Severity="High" state="1" DeepLink="http://cx/ViewerMain.aspx?scanid=1000005&amp;projectid=2&amp;pathid=3">
+			<Path ResultId="1000005" PathId="3" SimilarityId="-123"/>
+		</Result>
+	</Query>
+	<Query id="512" cweId="0" name="Unused_Variable" group="CSharp_Low_Visibility" Severity="Information" Language="CSharp">
+		<Result NodeId="10000050004" FileName="bookstore/Util.cs" Status="New" Line="0" Column="0" FalsePositive="True" Severity="Information" state="2">
+			<Path ResultId="1000005" PathId="4" SimilarityId="42"/>
+		</Result>
+	</Query>
+</CxXMLResults>`
+	var detailedResult DetailedResult
+	require.NoError(t, xml.Unmarshal([]byte(data), &detailedResult))
+
+	sarif := ConvertCxxmlToSarif(detailedResult)
+
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	assert.Equal(t, "Checkmarx", run.Tool.Driver.Name)
+	assert.Equal(t, "8.6.0", run.Tool.Driver.Version)
+
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	rule := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "430", rule.ID)
+	assert.Equal(t, "SQL_Injection", rule.Name)
+	assert.Equal(t, "SQL Injection (CSharp, CSharp_High_Risk)", rule.FullDescription.Text)
+	assert.Equal(t, "https://cwe.mitre.org/data/definitions/89.html", rule.HelpURI)
+	assert.Equal(t, []string{"security", "external/cwe/cwe-89"}, rule.Properties.Tags)
+	assert.Equal(t, format.SarifLevelError, rule.DefaultConfiguration.Level)
+	assert.Equal(t, "", run.Tool.Driver.Rules[1].HelpURI)
+
+	require.Len(t, run.Results, 3)
+	first := run.Results[0]
+	assert.Equal(t, "430", first.RuleID)
+	assert.Equal(t, format.SarifLevelError, first.Level)
+	assert.Equal(t, "SQL Injection in bookstore/Login.cs", first.Message.Text)
+	assert.Equal(t, &format.Region{StartLine: 179, StartColumn: 103}, first.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, map[string]string{"checkmarxSimilarityId": "1765812516"}, first.PartialFingerprints)
+	assert.Equal(t, "ToVerify", first.Properties["auditState"])
+	assert.Empty(t, first.Suppressions)
+
+	// not exploitable
+	assert.Equal(t, []format.Suppression{{Kind: "external", Status: "accepted", Justification: "NotExploitable"}}, run.Results[1].Suppressions)
+
+	// false positive
+	third := run.Results[2]
+	assert.Equal(t, 1, third.RuleIndex)
+	assert.Equal(t, format.SarifLevelNote, third.Level)
+	assert.Nil(t, third.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, []format.Suppression{{Kind: "external", Status: "accepted", Justification: "Confirmed"}}, third.Suppressions)
+}
@@ -0,0 +1,401 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Severities of configuration findings
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding describes a problem detected when checking a configuration against the step metadata
+type Finding struct {
+	Severity  string `json:"severity"`
+	Section   string `json:"section"`
+	Parameter string `json:"parameter,omitempty"`
+	Message   string `json:"message"`
+}
+
+// parameterDefinition bundles the metadata of a parameter as defined by one step
+type parameterDefinition struct {
+	step      string
+	param     StepParameters
+	deprecate string // name of the parameter in case the definition is reached via a deprecated alias
+	scopes    []string
+	// untyped definitions (context parameters, condition values, deep aliases) are not validated any further
+	untyped bool
+}
+
+type checkContext struct {
+	// definitions of all parameters, aliases and context parameters across all steps
+	definitions map[string][]parameterDefinition
+	stepAliases map[string]Alias
+	stepNames   []string
+	findings    []Finding
+}
+
+// Check validates the content of the configuration against the metadata of all available steps.
+// It reports unknown steps, unknown parameters, parameters used outside of their scope,
+// values of the wrong type, values which are not part of the possible values as well as deprecated aliases.
+//
+// Parameters within the sections general and stages may be consumed by steps which are not part of the metadata
+// (e.g. pure Jenkins library steps), thus unknown parameters are only reported as warning there.
+func (c *Config) Check(metadata map[string]StepData) []Finding {
+	ctx := newCheckContext(metadata)
+
+	ctx.checkSection(sectionGeneral, c.General, "", "GENERAL")
+
+	for _, stageName := range sortedKeys(c.Stages) {
+		ctx.checkSection(sectionStages+stageName, c.Stages[stageName], "", "STAGES")
+	}
+
+	for _, stepName := range sortedKeys(c.Steps) {
+		section := sectionSteps + stepName
+		targetStep := stepName
+		if _, ok := metadata[stepName]; !ok {
+			if alias, ok := ctx.stepAliases[stepName]; ok {
+				targetStep = alias.Name
+				if alias.Deprecated {
+					ctx.add(SeverityWarning, section, "", fmt.Sprintf("step '%v' is deprecated, use '%v' instead", stepName, targetStep))
+				}
+			} else {
+				message := fmt.Sprintf("unknown step '%v'", stepName)
+				if suggestion := closestMatch(stepName, ctx.stepNames); suggestion != "" {
+					message += fmt.Sprintf(", did you mean '%v'?", suggestion)
+				}
+				ctx.add(SeverityWarning, section, "", message)
+				continue
+			}
+		}
+		ctx.checkSection(section, c.Steps[stepName], targetStep, "STEPS")
+	}
+
+	return ctx.findings
+}
+
+func newCheckContext(metadata map[string]StepData) *checkContext {
+	ctx := checkContext{
+		definitions: map[string][]parameterDefinition{},
+		stepAliases: map[string]Alias{},
+	}
+	allScopes := []string{"GENERAL", "STAGES", "STEPS"}
+	// iterate in a stable order to get reproducible findings
+	for _, stepName := range sortedKeys(metadata) {
+		stepData := metadata[stepName]
+		ctx.stepNames = append(ctx.stepNames, stepName)
+		for _, alias := range stepData.Metadata.Aliases {
+			ctx.stepAliases[alias.Name] = Alias{Name: stepName, Deprecated: alias.Deprecated}
+		}
+		for _, param := range stepData.Spec.Inputs.Parameters {
+			ctx.define(param.Name, parameterDefinition{step: stepName, param: param, scopes: param.Scope})
+			for _, alias := range param.Aliases {
+				parts := strings.Split(alias.Name, "/")
+				definition := parameterDefinition{step: stepName, param: param, scopes: param.Scope, untyped: len(parts) > 1}
+				if alias.Deprecated {
+					definition.deprecate = param.Name
+				}
+				ctx.define(parts[0], definition)
+			}
+			for _, condition := range param.Conditions {
+				for _, dependentParam := range condition.Params {
+					ctx.define(dependentParam.Value, parameterDefinition{step: stepName, scopes: param.Scope, untyped: true})
+				}
+			}
+		}
+		contextFilters := stepData.GetContextParameterFilters()
+		for _, name := range contextFilters.Steps {
+			ctx.define(name, parameterDefinition{step: stepName, scopes: allScopes, untyped: true})
+		}
+		for _, secret := range stepData.Spec.Inputs.Secrets {
+			for _, alias := range secret.Aliases {
+				definition := parameterDefinition{step: stepName, scopes: allScopes, untyped: true}
+				if alias.Deprecated {
+					definition.deprecate = secret.Name
+				}
+				ctx.define(alias.Name, definition)
+			}
+		}
+	}
+	ctx.define("verbose", parameterDefinition{scopes: allScopes, untyped: true})
+	// vault and secret provider configuration is consumed by the configuration layer itself
+	for _, name := range vaultFilter {
+		ctx.define(name, parameterDefinition{scopes: allScopes, untyped: true})
+	}
+	return &ctx
+}
+
+func (ctx *checkContext) define(name string, definition parameterDefinition) {
+	ctx.definitions[name] = append(ctx.definitions[name], definition)
+}
+
+func (ctx *checkContext) add(severity, section, parameter, message string) {
+	ctx.findings = append(ctx.findings, Finding{Severity: severity, Section: section, Parameter: parameter, Message: message})
+}
+
+// checkSection validates all entries of a configuration section.
+// If stepName is empty the entries are validated against the parameters of all steps.
+func (ctx *checkContext) checkSection(section string, values map[string]interface{}, stepName, scope string) {
+	unknownSeverity := SeverityWarning
+	if stepName != "" {
+		unknownSeverity = SeverityError
+	}
+
+	for _, key := range sortedKeys(values) {
+		definitions := []parameterDefinition{}
+		for _, definition := range ctx.definitions[key] {
+			if stepName == "" || definition.step == stepName || definition.step == "" {
+				definitions = append(definitions, definition)
+			}
+		}
+
+		if len(definitions) == 0 {
+			message := fmt.Sprintf("unknown parameter '%v'", key)
+			if stepName != "" {
+				message = fmt.Sprintf("unknown parameter '%v' for step '%v'", key, stepName)
+			}
+			if suggestion := closestMatch(key, ctx.parameterNames(stepName)); suggestion != "" {
+				message += fmt.Sprintf(", did you mean '%v'?", suggestion)
+			}
+			ctx.add(unknownSeverity, section, key, message)
+			continue
+		}
+
+		inScope := []parameterDefinition{}
+		for _, definition := range definitions {
+			if sliceContains(definition.scopes, scope) {
+				inScope = append(inScope, definition)
+			}
+		}
+		if len(inScope) == 0 {
+			ctx.add(unknownSeverity, section, key, fmt.Sprintf("parameter '%v' is not supported in section '%v' and will be ignored", key, strings.ToLower(scope)))
+			continue
+		}
+
+		// only report deprecations if no step supports the name without deprecation
+		deprecated := ""
+		for _, definition := range inScope {
+			if definition.deprecate == "" {
+				deprecated = ""
+				break
+			}
+			deprecated = definition.deprecate
+		}
+		if deprecated != "" {
+			ctx.add(SeverityWarning, section, key, fmt.Sprintf("parameter '%v' is deprecated, use '%v' instead", key, deprecated))
+		}
+
+		ctx.checkValue(section, key, values[key], inScope, stepName != "")
+	}
+}
+
+// checkValue validates type and possible values of a parameter.
+// The value is accepted as soon as one of the definitions accepts it.
+func (ctx *checkContext) checkValue(section, key string, value interface{}, definitions []parameterDefinition, strict bool) {
+	typed := []parameterDefinition{}
+	for _, definition := range definitions {
+		if !definition.untyped && definition.param.Type != "" {
+			typed = append(typed, definition)
+		}
+	}
+	if len(typed) == 0 || isReference(value) {
+		return
+	}
+	definitions = typed
+
+	var typeMismatch, valueMismatch *parameterDefinition
+	for i, definition := range definitions {
+		if !matchesType(value, definition.param.Type) {
+			typeMismatch = &definitions[i]
+			continue
+		}
+		if len(definition.param.PossibleValues) > 0 && !matchesPossibleValues(value, definition.param.PossibleValues) {
+			valueMismatch = &definitions[i]
+			continue
+		}
+		return
+	}
+
+	severity := SeverityError
+	if !strict && len(definitions) > 1 {
+		severity = SeverityWarning
+	}
+	if valueMismatch != nil {
+		ctx.add(severity, section, key, fmt.Sprintf("value '%v' of parameter '%v' is not one of the possible values %v", value, key, formatPossibleValues(valueMismatch.param.PossibleValues)))
+		return
+	}
+	ctx.add(severity, section, key, fmt.Sprintf("parameter '%v' is of type '%v' but value '%v' has type '%v'", key, typeMismatch.param.Type, value, valueTypeName(value)))
+}
+
+func (ctx *checkContext) parameterNames(stepName string) []string {
+	names := []string{}
+	for name, definitions := range ctx.definitions {
+		for _, definition := range definitions {
+			if stepName == "" || definition.step == stepName {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isReference checks if a value is (or contains) a reference which is resolved during interpolation
+func isReference(value interface{}) bool {
+	str, ok := value.(string)
+	return ok && strings.Contains(str, "$(")
+}
+
+func matchesType(value interface{}, paramType string) bool {
+	if value == nil {
+		return true
+	}
+	switch paramType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "int":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "[]string":
+		// a single string is accepted as well and converted into a list with one entry
+		if _, ok := value.(string); ok {
+			return true
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, entry := range list {
+			if _, ok := entry.(string); !ok {
+				return false
+			}
+		}
+		return true
+	case "map[string]interface{}":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "[]map[string]interface{}":
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, entry := range list {
+			if _, ok := entry.(map[string]interface{}); !ok {
+				return false
+			}
+		}
+		return true
+	case "[]interface{}":
+		_, ok := value.([]interface{})
+		return ok
+	}
+	// unknown types are not validated
+	return true
+}
+
+func matchesPossibleValues(value interface{}, possibleValues []interface{}) bool {
+	if list, ok := value.([]interface{}); ok {
+		for _, entry := range list {
+			if !matchesPossibleValues(entry, possibleValues) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, possibleValue := range possibleValues {
+		if reflect.DeepEqual(value, possibleValue) || fmt.Sprint(value) == fmt.Sprint(possibleValue) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPossibleValues(possibleValues []interface{}) string {
+	values := []string{}
+	for _, v := range possibleValues {
+		values = append(values, fmt.Sprint(v))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func valueTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64:
+		if v == float64(int64(v)) {
+			return "int"
+		}
+		return "float"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// closestMatch returns the candidate with the smallest edit distance if it is close enough to be a likely typo
+func closestMatch(name string, candidates []string) string {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if bestDistance < 0 || bestDistance > 2 || bestDistance > len(name)/2 {
+		return ""
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
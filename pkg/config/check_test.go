@@ -0,0 +1,118 @@
+package config
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	metadata := map[string]StepData{
+		"mavenBuild": {
+			Metadata: StepMetadata{Name: "mavenBuild", Aliases: []Alias{{Name: "mavenInstall", Deprecated: true}}},
+			Spec: StepSpec{Inputs: StepInputs{Parameters: []StepParameters{
+				{Name: "flatten", Type: "bool", Scope: []string{"PARAMETERS"}},
+				{Name: "goals", Type: "[]string", Scope: []string{"GENERAL", "STAGES", "STEPS"}, Aliases: []Alias{{Name: "mavenGoals", Deprecated: true}}},
+				{Name: "buildTool", Type: "string", Scope: []string{"GENERAL", "STAGES", "STEPS"}, PossibleValues: []interface{}{"maven"}},
+				{Name: "retries", Type: "int", Scope: []string{"GENERAL", "STAGES", "STEPS"}},
+			}}},
+		},
+		"npmBuild": {
+			Metadata: StepMetadata{Name: "npmBuild"},
+			Spec: StepSpec{Inputs: StepInputs{Parameters: []StepParameters{
+				{Name: "buildTool", Type: "string", Scope: []string{"GENERAL", "STAGES", "STEPS"}, PossibleValues: []interface{}{"npm"}},
+				{Name: "installArgs", Type: "map[string]interface{}", Scope: []string{"STEPS"}},
+			}}},
+		},
+	}
+
+	check := func(t *testing.T, configuration string) []Finding {
+		var c Config
+		err := c.ReadConfig(ioutil.NopCloser(strings.NewReader(configuration)))
+		assert.NoError(t, err)
+		return c.Check(metadata)
+	}
+
+	t.Run("valid configuration", func(t *testing.T) {
+		findings := check(t, `general:
+  buildTool: npm
+  verbose: true
+stages:
+  Build:
+    retries: 3
+steps:
+  mavenBuild:
+    goals: [clean, install]
+    buildTool: maven
+  npmBuild:
+    installArgs:
+      foo: bar
+    buildTool: $(env.BUILD_TOOL)
+`)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("unknown steps and parameters", func(t *testing.T) {
+		findings := check(t, `general:
+  someGroovyParameter: true
+steps:
+  mavenBiuld:
+    goals: install
+  mavenBuild:
+    golas: install
+  customStep:
+    foo: bar
+`)
+		assert.Equal(t, []Finding{
+			{Severity: SeverityWarning, Section: "general", Parameter: "someGroovyParameter", Message: "unknown parameter 'someGroovyParameter'"},
+			{Severity: SeverityWarning, Section: "steps/customStep", Message: "unknown step 'customStep'"},
+			{Severity: SeverityWarning, Section: "steps/mavenBiuld", Message: "unknown step 'mavenBiuld', did you mean 'mavenBuild'?"},
+			{Severity: SeverityError, Section: "steps/mavenBuild", Parameter: "golas", Message: "unknown parameter 'golas' for step 'mavenBuild', did you mean 'goals'?"},
+		}, findings)
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		findings := check(t, `general:
+  installArgs: {}
+steps:
+  mavenBuild:
+    flatten: false
+`)
+		assert.Equal(t, []Finding{
+			{Severity: SeverityWarning, Section: "general", Parameter: "installArgs", Message: "parameter 'installArgs' is not supported in section 'general' and will be ignored"},
+			{Severity: SeverityError, Section: "steps/mavenBuild", Parameter: "flatten", Message: "parameter 'flatten' is not supported in section 'steps' and will be ignored"},
+		}, findings)
+	})
+
+	t.Run("types and possible values", func(t *testing.T) {
+		findings := check(t, `general:
+  buildTool: gradle
+stages:
+  Build:
+    retries: 1.5
+steps:
+  mavenBuild:
+    goals: [clean, 1]
+    buildTool: npm
+`)
+		assert.Equal(t, []Finding{
+			{Severity: SeverityWarning, Section: "general", Parameter: "buildTool", Message: "value 'gradle' of parameter 'buildTool' is not one of the possible values [npm]"},
+			{Severity: SeverityError, Section: "stages/Build", Parameter: "retries", Message: "parameter 'retries' is of type 'int' but value '1.5' has type 'float'"},
+			{Severity: SeverityError, Section: "steps/mavenBuild", Parameter: "buildTool", Message: "value 'npm' of parameter 'buildTool' is not one of the possible values [maven]"},
+			{Severity: SeverityError, Section: "steps/mavenBuild", Parameter: "goals", Message: "parameter 'goals' is of type '[]string' but value '[clean 1]' has type 'list'"},
+		}, findings)
+	})
+
+	t.Run("deprecations", func(t *testing.T) {
+		findings := check(t, `steps:
+  mavenInstall:
+    mavenGoals: install
+`)
+		assert.Equal(t, []Finding{
+			{Severity: SeverityWarning, Section: "steps/mavenInstall", Message: "step 'mavenInstall' is deprecated, use 'mavenBuild' instead"},
+			{Severity: SeverityWarning, Section: "steps/mavenInstall", Parameter: "mavenGoals", Message: "parameter 'mavenGoals' is deprecated, use 'goals' instead"},
+		}, findings)
+	})
+}
@@ -9,8 +9,10 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/config/interpolation"
 	"github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 
 	"github.com/ghodss/yaml"
 	"github.com/google/go-cmp/cmp"
@@ -28,12 +30,19 @@ type Config struct {
 	initialized      bool
 	openFile         func(s string) (io.ReadCloser, error)
 	vaultCredentials VaultCredentials
+	cpePath          string
+	// provenance tracking, see TrackProvenance
+	trackProvenance bool
+	source          string
+	aliasOrigins    map[string]map[string]string
 }
 
 // StepConfig defines the structure for merged step configuration
 type StepConfig struct {
 	Config     map[string]interface{}
 	HookConfig map[string]interface{}
+	// Provenance contains the origins of every parameter value, only available if tracking is enabled via Config.TrackProvenance
+	Provenance map[string][]ValueOrigin
 }
 
 // ReadConfig loads config and returns its content
@@ -49,6 +58,9 @@ func (c *Config) ReadConfig(configuration io.ReadCloser) error {
 	if err != nil {
 		return NewParseError(fmt.Sprintf("format of configuration is invalid %q: %v", content, err))
 	}
+	if c != nil {
+		c.source = sourceName(configuration, "project configuration")
+	}
 	return nil
 }
 
@@ -58,27 +70,35 @@ func (c *Config) ApplyAliasConfig(parameters []StepParameters, secrets []StepSec
 	if len(stepAliases) > 0 {
 		c.copyStepAliasConfig(stepName, stepAliases)
 	}
+	var alias string
 	for _, p := range parameters {
-		c.General = setParamValueFromAlias(stepName, c.General, filters.General, p.Name, p.Aliases)
+		c.General, alias = setParamValueFromAlias(stepName, c.General, filters.General, p.Name, p.Aliases)
+		c.recordAlias(sectionGeneral, p.Name, alias)
 		if c.Stages[stageName] != nil {
-			c.Stages[stageName] = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, p.Name, p.Aliases)
+			c.Stages[stageName], alias = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, p.Name, p.Aliases)
+			c.recordAlias(sectionStages+stageName, p.Name, alias)
 		}
 		if c.Steps[stepName] != nil {
-			c.Steps[stepName] = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, p.Name, p.Aliases)
+			c.Steps[stepName], alias = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, p.Name, p.Aliases)
+			c.recordAlias(sectionSteps+stepName, p.Name, alias)
 		}
 	}
 	for _, s := range secrets {
-		c.General = setParamValueFromAlias(stepName, c.General, filters.General, s.Name, s.Aliases)
+		c.General, alias = setParamValueFromAlias(stepName, c.General, filters.General, s.Name, s.Aliases)
+		c.recordAlias(sectionGeneral, s.Name, alias)
 		if c.Stages[stageName] != nil {
-			c.Stages[stageName] = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, s.Name, s.Aliases)
+			c.Stages[stageName], alias = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, s.Name, s.Aliases)
+			c.recordAlias(sectionStages+stageName, s.Name, alias)
 		}
 		if c.Steps[stepName] != nil {
-			c.Steps[stepName] = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, s.Name, s.Aliases)
+			c.Steps[stepName], alias = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, s.Name, s.Aliases)
+			c.recordAlias(sectionSteps+stepName, s.Name, alias)
 		}
 	}
 }
 
-func setParamValueFromAlias(stepName string, configMap map[string]interface{}, filter []string, name string, aliases []Alias) map[string]interface{} {
+// setParamValueFromAlias returns the updated configMap together with the name of the alias which has been applied
+func setParamValueFromAlias(stepName string, configMap map[string]interface{}, filter []string, name string, aliases []Alias) (map[string]interface{}, string) {
 	if configMap != nil && configMap[name] == nil && sliceContains(filter, name) {
 		for _, a := range aliases {
 			aliasVal := getDeepAliasValue(configMap, a.Name)
@@ -89,11 +109,11 @@ func setParamValueFromAlias(stepName string, configMap map[string]interface{}, f
 				}
 			}
 			if configMap[name] != nil {
-				return configMap
+				return configMap, a.Name
 			}
 		}
 	}
-	return configMap
+	return configMap, ""
 }
 
 func getDeepAliasValue(configMap map[string]interface{}, key string) interface{} {
@@ -158,6 +178,15 @@ func (c *Config) InitializeConfig(configuration io.ReadCloser, defaults []io.Rea
 	if err := c.defaults.ReadPipelineDefaults(defaults); err != nil {
 		return errors.Wrap(err, "failed to read default configuration")
 	}
+	// custom defaults are appended at the end, hence their names are known
+	if !ignoreCustomDefaults {
+		offset := len(c.defaults.Defaults) - len(c.CustomDefaults)
+		for i, name := range c.CustomDefaults {
+			if offset+i >= 0 {
+				c.defaults.Defaults[offset+i].source = name
+			}
+		}
+	}
 	c.initialized = true
 	return nil
 }
@@ -174,31 +203,50 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		}
 	}
 
+	if c.trackProvenance {
+		stepConfig.Provenance = map[string][]ValueOrigin{}
+		c.aliasOrigins = map[string]map[string]string{}
+		for i := range c.defaults.Defaults {
+			c.defaults.Defaults[i].aliasOrigins = map[string]map[string]string{}
+		}
+	}
+
 	c.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
 
 	// initialize with defaults from step.yaml
 	stepConfig.mixInStepDefaults(parameters)
 
 	// merge parameters provided by Piper environment
-	stepConfig.mixIn(envParameters, filters.All)
+	stepConfig.mixInFrom(envParameters, filters.All, ValueOrigin{Layer: LayerCommonPipelineEnvironment}, nil)
 
 	// read defaults & merge general -> steps (-> general -> steps ...)
-	for _, def := range c.defaults.Defaults {
+	for i, def := range c.defaults.Defaults {
 		def.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
-		stepConfig.mixIn(def.General, filters.General)
-		stepConfig.mixIn(def.Steps[stepName], filters.Steps)
-		stepConfig.mixIn(def.Stages[stageName], filters.Steps)
+		source := def.source
+		if len(source) == 0 {
+			source = fmt.Sprintf("defaults #%v", i+1)
+		}
+		stepConfig.mixInFrom(def.General, filters.General, ValueOrigin{Layer: LayerDefaults, Source: source, Section: sectionGeneral}, def.aliasOrigins[sectionGeneral])
+		stepConfig.mixInFrom(def.Steps[stepName], filters.Steps, ValueOrigin{Layer: LayerDefaults, Source: source, Section: sectionSteps + stepName}, def.aliasOrigins[sectionSteps+stepName])
+		stepConfig.mixInFrom(def.Stages[stageName], filters.Steps, ValueOrigin{Layer: LayerDefaults, Source: source, Section: sectionStages + stageName}, def.aliasOrigins[sectionStages+stageName])
 		stepConfig.mixinVaultConfig(def.General, def.Steps[stepName], def.Stages[stageName])
+		stepConfig.recordOrigins(def.General, vaultFilter, ValueOrigin{Layer: LayerDefaults, Source: source, Section: sectionGeneral}, nil)
+		stepConfig.recordOrigins(def.Steps[stepName], vaultFilter, ValueOrigin{Layer: LayerDefaults, Source: source, Section: sectionSteps + stepName}, nil)
+		stepConfig.recordOrigins(def.Stages[stageName], vaultFilter, ValueOrigin{Layer: LayerDefaults, Source: source, Section: sectionStages + stageName}, nil)
 		stepConfig.mixInHookConfig(def.Hooks)
 	}
 
 	// read config & merge - general -> steps -> stages
-	stepConfig.mixIn(c.General, filters.General)
-	stepConfig.mixIn(c.Steps[stepName], filters.Steps)
-	stepConfig.mixIn(c.Stages[stageName], filters.Stages)
+	stepConfig.mixInFrom(c.General, filters.General, ValueOrigin{Layer: LayerConfig, Source: c.source, Section: sectionGeneral}, c.aliasOrigins[sectionGeneral])
+	stepConfig.mixInFrom(c.Steps[stepName], filters.Steps, ValueOrigin{Layer: LayerConfig, Source: c.source, Section: sectionSteps + stepName}, c.aliasOrigins[sectionSteps+stepName])
+	stepConfig.mixInFrom(c.Stages[stageName], filters.Stages, ValueOrigin{Layer: LayerConfig, Source: c.source, Section: sectionStages + stageName}, c.aliasOrigins[sectionStages+stageName])
+	// only values of the project configuration are interpolated, see resolveReferences
+	configValues := merge(merge(filterMap(c.General, filters.General), filterMap(c.Steps[stepName], filters.Steps)), filterMap(c.Stages[stageName], filters.Stages))
 
 	// merge parameters provided via env vars
-	stepConfig.mixIn(envValues(filters.All), filters.All)
+	for param, value := range envValues(filters.All) {
+		stepConfig.mixInFrom(map[string]interface{}{param: value}, filters.All, ValueOrigin{Layer: LayerEnvironment, Source: "PIPER_" + param}, nil)
+	}
 
 	// if parameters are provided in JSON format merge them
 	if len(paramJSON) != 0 {
@@ -208,20 +256,33 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 			log.Entry().Warnf("failed to parse parameters from environment: %v", err)
 		} else {
 			//apply aliases
+			aliases := map[string]string{}
+			var alias string
 			for _, p := range parameters {
-				params = setParamValueFromAlias(stepName, params, filters.Parameters, p.Name, p.Aliases)
+				params, alias = setParamValueFromAlias(stepName, params, filters.Parameters, p.Name, p.Aliases)
+				if len(alias) > 0 {
+					aliases[p.Name] = alias
+				}
 			}
 			for _, s := range secrets {
-				params = setParamValueFromAlias(stepName, params, filters.Parameters, s.Name, s.Aliases)
+				params, alias = setParamValueFromAlias(stepName, params, filters.Parameters, s.Name, s.Aliases)
+				if len(alias) > 0 {
+					aliases[s.Name] = alias
+				}
 			}
 
-			stepConfig.mixIn(params, filters.Parameters)
+			stepConfig.mixInFrom(params, filters.Parameters, ValueOrigin{Layer: LayerParametersJSON}, aliases)
 		}
 	}
 
 	// merge command line flags
 	if flagValues != nil {
-		stepConfig.mixIn(flagValues, filters.Parameters)
+		stepConfig.mixInFrom(flagValues, filters.Parameters, ValueOrigin{Layer: LayerFlag}, nil)
+	}
+
+	// resolve references like $(env.HOME) or $(cpe.artifactVersion)
+	if err := c.resolveReferences(&stepConfig, configValues); err != nil {
+		return StepConfig{}, errors.Wrapf(err, "failed to interpolate configuration of step '%v'", stepName)
 	}
 
 	if verbose, ok := stepConfig.Config["verbose"].(bool); ok && verbose {
@@ -231,19 +292,37 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 	}
 
 	stepConfig.mixinVaultConfig(c.General, c.Steps[stepName], c.Stages[stageName])
+	stepConfig.recordOrigins(c.General, vaultFilter, ValueOrigin{Layer: LayerConfig, Source: c.source, Section: sectionGeneral}, nil)
+	stepConfig.recordOrigins(c.Steps[stepName], vaultFilter, ValueOrigin{Layer: LayerConfig, Source: c.source, Section: sectionSteps + stepName}, nil)
+	stepConfig.recordOrigins(c.Stages[stageName], vaultFilter, ValueOrigin{Layer: LayerConfig, Source: c.source, Section: sectionStages + stageName}, nil)
+	// dynamic secrets can be requested via the step metadata and via the configuration
+	secretParameters := append(append([]StepParameters{}, parameters...), dynamicSecretParameters(&stepConfig, parameters)...)
 	// check whether vault should be skipped
+	var client vaultClient
 	if skip, ok := stepConfig.Config["skipVault"].(bool); !ok || !skip {
 		// fetch secrets from vault
-		vaultClient, err := getVaultClientFromConfig(stepConfig, c.vaultCredentials)
+		client, err = getVaultClientFromConfig(stepConfig, c.vaultCredentials)
 		if err != nil {
 			return StepConfig{}, err
 		}
-		if vaultClient != nil {
-			defer vaultClient.MustRevokeToken()
-			resolveAllVaultReferences(&stepConfig, vaultClient, parameters)
-			resolveVaultTestCredentials(&stepConfig, vaultClient)
+		if client != nil {
+			if hasDynamicSecretReferences(secretParameters) {
+				// leases of dynamic secrets are bound to the token, thus it has to stay valid until the step finished
+				vaultClientsToRevoke = append(vaultClientsToRevoke, client)
+			} else {
+				defer client.MustRevokeToken()
+			}
 		}
 	}
+	// resolve secrets from all configured secret providers (vault, mounted directory, encrypted file)
+	secretProviders, err := getSecretProviders(stepConfig, client)
+	if err != nil {
+		return StepConfig{}, err
+	}
+	if len(secretProviders) > 0 {
+		resolveAllVaultReferences(&stepConfig, secretProviders, secretParameters)
+		resolveVaultTestCredentials(&stepConfig, secretProviders)
+	}
 
 	// finally do the condition evaluation post processing
 	for _, p := range parameters {
@@ -259,6 +338,7 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 						subMap, ok := stepConfig.Config[dependentValue.(string)].(map[string]interface{})
 						if ok && subMap[p.Name] != nil {
 							stepConfig.Config[p.Name] = subMap[p.Name]
+							stepConfig.recordOrigin(p.Name, ValueOrigin{Layer: LayerCondition, Source: fmt.Sprintf("%v=%v", param.Name, param.Value), Value: subMap[p.Name]})
 						}
 					}
 				}
@@ -279,6 +359,50 @@ func (c *Config) SetVaultCredentials(appRoleID, appRoleSecretID string, vaultTok
 	}
 }
 
+// SetCommonPipelineEnvironmentPath sets the path of the commonPipelineEnvironment
+// which values can be referenced in the configuration via $(cpe.<name>)
+func (c *Config) SetCommonPipelineEnvironmentPath(path string) {
+	c.cpePath = path
+}
+
+// resolveReferences interpolates the values of the step configuration which are taken from the project configuration.
+// Values of all other layers, e.g. defaults, flags, env vars or the commonPipelineEnvironment, are taken as they are,
+// since interpolating them would allow to inject references to arbitrary environment variables.
+// Expressions which cannot be parsed as a reference, e.g. shell expressions like $(git rev-parse HEAD), are kept.
+func (c *Config) resolveReferences(stepConfig *StepConfig, configValues map[string]interface{}) error {
+	cpe := piperenv.CPEMap{}
+	if len(c.cpePath) > 0 {
+		if err := cpe.LoadFromDisk(c.cpePath); err != nil {
+			return errors.Wrapf(err, "failed to read commonPipelineEnvironment from '%v'", c.cpePath)
+		}
+	}
+	var unresolved map[string]interface{}
+	if stepConfig.Provenance != nil {
+		unresolved = map[string]interface{}{}
+		for key, value := range stepConfig.Config {
+			if str, ok := value.(string); ok {
+				unresolved[key] = str
+			}
+		}
+	}
+	literal := map[string]bool{}
+	for key, value := range stepConfig.Config {
+		if configValue, ok := configValues[key]; !ok || !cmp.Equal(configValue, value) {
+			literal[key] = true
+		}
+	}
+	resolver := interpolation.Resolver{Config: stepConfig.Config, CPE: cpe, Lenient: true, Literal: literal}
+	if err := resolver.ResolveMap(stepConfig.Config); err != nil {
+		return err
+	}
+	for key, value := range unresolved {
+		if !cmp.Equal(value, stepConfig.Config[key]) {
+			stepConfig.recordOrigin(key, ValueOrigin{Layer: LayerInterpolation, Source: value.(string), Value: stepConfig.Config[key]})
+		}
+	}
+	return nil
+}
+
 // GetStepConfigWithJSON provides merged step configuration using a provided stepConfigJSON with additional flags provided
 func GetStepConfigWithJSON(flagValues map[string]interface{}, stepConfigJSON string, filters StepFilters) StepConfig {
 	var stepConfig StepConfig
@@ -335,6 +459,12 @@ func envValues(filter []string) map[string]interface{} {
 	return vals
 }
 
+// mixInFrom merges the data like mixIn and records the origin of the merged values in case provenance is tracked
+func (s *StepConfig) mixInFrom(mergeData map[string]interface{}, filter []string, origin ValueOrigin, aliases map[string]string) {
+	s.mixIn(mergeData, filter)
+	s.recordOrigins(mergeData, filter, origin, aliases)
+}
+
 func (s *StepConfig) mixIn(mergeData map[string]interface{}, filter []string) {
 
 	if s.Config == nil {
@@ -365,6 +495,7 @@ func (s *StepConfig) mixInStepDefaults(stepParams []StepParameters) {
 		if p.Default != nil {
 			if len(p.Conditions) == 0 {
 				s.Config[p.Name] = p.Default
+				s.recordOrigin(p.Name, ValueOrigin{Layer: LayerStepDefault, Value: p.Default})
 			} else {
 				for _, cond := range p.Conditions {
 					for _, param := range cond.Params {
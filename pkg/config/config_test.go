@@ -338,6 +338,71 @@ steps:
 		assert.EqualError(t, err, "failed to read default configuration: error unmarshalling \"invalid defaults\": error unmarshaling JSON: while decoding JSON: json: cannot unmarshal string into Go value of type config.Config", "default error expected")
 	})
 
+	t.Run("Resolve references", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal("Failed to create temporary directory")
+		}
+		defer os.RemoveAll(dir)
+		cpe := piperenv.CPEMap{"artifactVersion": "1.2.3-SNAPSHOT"}
+		if err := cpe.WriteToDisk(dir); err != nil {
+			t.Fatal("Failed to write commonPipelineEnvironment")
+		}
+		os.Setenv("PIPER_TEST_INTERPOLATION", "Piper")
+		defer os.Unsetenv("PIPER_TEST_INTERPOLATION")
+
+		var c Config
+		c.SetCommonPipelineEnvironmentPath(dir)
+		testConf := `general:
+  p0: "$(lower(env.PIPER_TEST_INTERPOLATION))"
+steps:
+  step1:
+    p1: "$(trimSuffix(cpe.artifactVersion, '-SNAPSHOT'))"
+    p2: "$(p0)-$(missing:-default)"
+`
+		stepConfig, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader(testConf)), nil, false, StepFilters{General: []string{"p0"}, Steps: []string{"p0", "p1", "p2"}}, nil, nil, nil, "stage1", "step1", []Alias{})
+
+		assert.NoError(t, err, "Error occurred but no error expected")
+		assert.Equal(t, "piper", stepConfig.Config["p0"])
+		assert.Equal(t, "1.2.3", stepConfig.Config["p1"])
+		assert.Equal(t, "piper-default", stepConfig.Config["p2"])
+	})
+
+	t.Run("Failure case unresolved references", func(t *testing.T) {
+		var c Config
+		testConf := "steps:\n  step1:\n    p0: $(cpe.missing)\n    p1: $(env.PIPER_TEST_MISSING)"
+		_, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader(testConf)), nil, false, StepFilters{Steps: []string{"p0", "p1"}}, nil, nil, nil, "stage1", "step1", []Alias{})
+		assert.EqualError(t, err, "failed to interpolate configuration of step 'step1': failed to resolve 2 reference(s): $(cpe.missing) (parameter 'p0'), $(env.PIPER_TEST_MISSING) (parameter 'p1')")
+	})
+
+	t.Run("Failure case unresolved property references", func(t *testing.T) {
+		var c Config
+		testConf := "steps:\n  step1:\n    dockerOptions: ['-v $(pwd):/project']\n    p0: $(lower(p1))\n    p1: $(upper(missing))"
+		_, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader(testConf)), nil, false, StepFilters{Steps: []string{"dockerOptions", "p0", "p1"}}, nil, nil, nil, "stage1", "step1", []Alias{})
+		assert.EqualError(t, err, "failed to interpolate configuration of step 'step1': failed to resolve 3 reference(s): $(pwd) (parameter 'dockerOptions'), $(missing) (parameter 'p0'), $(missing) (parameter 'p1')")
+	})
+
+	t.Run("Keep shell expressions and values which are not from the project configuration", func(t *testing.T) {
+		os.Setenv("PIPER_p1", "$(env.PIPER_TEST_SECRET)")
+		defer os.Unsetenv("PIPER_p1")
+		os.Setenv("PIPER_TEST_SECRET", "secret")
+		defer os.Unsetenv("PIPER_TEST_SECRET")
+
+		var c Config
+		testConf := "steps:\n  step1:\n    dockerOptions: ['-v $$(pwd):/project']\n    script: echo $(git rev-parse HEAD)\n    p3: overridden"
+		defaults := "steps:\n  step1:\n    p4: echo $(p3)"
+		flags := map[string]interface{}{"p3": "echo $(script)"}
+		stepConfig, err := c.GetStepConfig(flags, "", ioutil.NopCloser(strings.NewReader(testConf)), []io.ReadCloser{ioutil.NopCloser(strings.NewReader(defaults))}, false, StepFilters{All: []string{"p1", "p2"}, Steps: []string{"dockerOptions", "script", "p3", "p4"}, Parameters: []string{"p3"}}, nil, nil, map[string]interface{}{"p2": "$(env.PIPER_TEST_SECRET)"}, "stage1", "step1", []Alias{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"-v $(pwd):/project"}, stepConfig.Config["dockerOptions"])
+		assert.Equal(t, "echo $(git rev-parse HEAD)", stepConfig.Config["script"])
+		assert.Equal(t, "$(env.PIPER_TEST_SECRET)", stepConfig.Config["p1"])
+		assert.Equal(t, "$(env.PIPER_TEST_SECRET)", stepConfig.Config["p2"])
+		assert.Equal(t, "echo $(script)", stepConfig.Config["p3"])
+		assert.Equal(t, "echo $(p3)", stepConfig.Config["p4"])
+	})
+
 	//ToDo: test merging of env and parameters/flags
 }
 
@@ -1,8 +1,11 @@
 package interpolation
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -10,53 +13,436 @@ import (
 
 const (
 	maxLookupDepth = 10
+
+	envPrefix = "env."
+	cpePrefix = "cpe."
+
+	defaultSeparator = ":-"
+	escapedStart     = "$$("
+	expressionStart  = "$("
 )
 
 var (
-	lookupRegex   *regexp.Regexp = regexp.MustCompile(`\$\((?P<property>[a-zA-Z0-9\.]*)\)`)
-	captureGroups                = setupCaptureGroups(lookupRegex.SubexpNames())
+	referenceRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-\./]+$`)
+	functionRegex  = regexp.MustCompile(`^(?P<name>[a-zA-Z][a-zA-Z0-9]*)\((?P<args>.*)\)$`)
+	captureGroups  = setupCaptureGroups(functionRegex.SubexpNames())
 )
 
+// functions contains the functions which can be used inside of an expression, e.g. $(lower(env.USER))
+var functions = map[string]func(args []string) (string, error){
+	"lower": func(args []string) (string, error) {
+		if err := checkArgCount("lower", args, 1); err != nil {
+			return "", err
+		}
+		return strings.ToLower(args[0]), nil
+	},
+	"upper": func(args []string) (string, error) {
+		if err := checkArgCount("upper", args, 1); err != nil {
+			return "", err
+		}
+		return strings.ToUpper(args[0]), nil
+	},
+	"replace": func(args []string) (string, error) {
+		if err := checkArgCount("replace", args, 3); err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(args[0], args[1], args[2]), nil
+	},
+	"trimPrefix": func(args []string) (string, error) {
+		if err := checkArgCount("trimPrefix", args, 2); err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(args[0], args[1]), nil
+	},
+	"trimSuffix": func(args []string) (string, error) {
+		if err := checkArgCount("trimSuffix", args, 2); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(args[0], args[1]), nil
+	},
+}
+
+// Resolver resolves expressions of the form $(...) against the configured sources.
+//
+// Supported expressions are
+//
+//	$(property)            a property of Config, nested keys are separated by a dot: $(foo.bar)
+//	$(env.HOME)            an environment variable
+//	$(cpe.artifactVersion) a value of the commonPipelineEnvironment, e.g. $(cpe.git/branch) or $(cpe.git.branch)
+//	$(foo:-bar)            a default which is used in case foo cannot be resolved or is empty
+//	$(lower(env.USER))     a function call, arguments are expressions or quoted strings: $(replace(foo, "-", "_"))
+//
+// A literal '$(' can be written as '$$('.
+// Values of environment variables and the commonPipelineEnvironment are inserted as they are, expressions inside of them are not resolved.
+type Resolver struct {
+	Config map[string]interface{}
+	CPE    map[string]interface{}
+	// LookupEnv is used to read environment variables, defaults to os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+	// Lenient keeps expressions unchanged which cannot be parsed as a reference or a function call, e.g. shell expressions like $(git rev-parse HEAD).
+	// A warning is logged for every kept expression, references which cannot be resolved are still reported.
+	Lenient bool
+	// Literal contains the properties of Config which are taken as they are, e.g. since they were provided via environment variables
+	Literal map[string]bool
+}
+
+// ResolveError contains all references which could not be resolved
+type ResolveError struct {
+	Problems []string
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("failed to resolve %d reference(s): %s", len(e.Problems), strings.Join(e.Problems, ", "))
+}
+
 // ResolveMap interpolates every string value of a map and tries to lookup references to other properties of that map
 func ResolveMap(config map[string]interface{}) bool {
-	for key, value := range config {
-		if str, ok := value.(string); ok {
-			resolvedStr, ok := ResolveString(str, config)
-			if !ok {
-				return false
+	r := Resolver{Config: config}
+	if err := r.ResolveMap(config); err != nil {
+		log.Entry().Debug(err.Error())
+		return false
+	}
+	return true
+}
+
+// ResolveString takes a string and replaces all references inside of it with values from the given lookupMap.
+// This is being done recursively until the maxLookupDepth is reached.
+func ResolveString(str string, lookupMap map[string]interface{}) (string, bool) {
+	r := Resolver{Config: lookupMap}
+	resolved, err := r.ResolveString(str)
+	if err != nil {
+		log.Entry().Debugf("Can't interpolate '%s': %v", str, err)
+		return "", false
+	}
+	return resolved, true
+}
+
+// ResolveMap interpolates all string values of the given map including nested maps and lists.
+// A value consisting of a single reference to a non-string value keeps the type of the referenced value.
+// All references which cannot be resolved are reported together in a ResolveError.
+func (r *Resolver) ResolveMap(config map[string]interface{}) error {
+	problems := []string{}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		if !r.Literal[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		resolved, valueProblems := r.resolveValue(config[key])
+		if len(valueProblems) > 0 {
+			for _, problem := range valueProblems {
+				problems = append(problems, fmt.Sprintf("%s (parameter '%s')", problem, key))
 			}
-			config[key] = resolvedStr
+			continue
 		}
+		config[key] = resolved
 	}
-	return true
+	if len(problems) > 0 {
+		return &ResolveError{Problems: problems}
+	}
+	return nil
+}
+
+// ResolveString replaces all expressions inside of the given string.
+func (r *Resolver) ResolveString(str string) (string, error) {
+	resolved, problems := r.resolveString(str, 0)
+	if len(problems) > 0 {
+		return "", &ResolveError{Problems: problems}
+	}
+	return resolved, nil
+}
+
+func (r *Resolver) resolveValue(value interface{}) (interface{}, []string) {
+	switch v := value.(type) {
+	case string:
+		if typed, ok, problems := r.resolveSingleReference(v); ok || len(problems) > 0 {
+			return typed, problems
+		}
+		return r.resolveString(v, 0)
+	case map[string]interface{}:
+		problems := []string{}
+		for key, entry := range v {
+			resolved, entryProblems := r.resolveValue(entry)
+			problems = append(problems, entryProblems...)
+			v[key] = resolved
+		}
+		return v, problems
+	case []interface{}:
+		problems := []string{}
+		for i, entry := range v {
+			resolved, entryProblems := r.resolveValue(entry)
+			problems = append(problems, entryProblems...)
+			v[i] = resolved
+		}
+		return v, problems
+	default:
+		return value, nil
+	}
+}
+
+// resolveSingleReference resolves strings which consist of exactly one reference to a non-string value
+func (r *Resolver) resolveSingleReference(str string) (interface{}, bool, []string) {
+	if !strings.HasPrefix(str, expressionStart) {
+		return nil, false, nil
+	}
+	begin, end, err := findExpression(str, 0)
+	if err != nil || begin != 0 || end != len(str)-1 {
+		return nil, false, nil
+	}
+	value, resolved, problems := r.evaluate(str[begin+2:end], 0)
+	if len(problems) > 0 {
+		return nil, false, problems
+	}
+	if _, isString := value.(string); isString || value == nil || !resolved {
+		return nil, false, nil
+	}
+	return value, true, nil
 }
 
-func resolveString(str string, lookupMap map[string]interface{}, n int) (string, bool) {
-	matches := lookupRegex.FindAllStringSubmatch(str, -1)
-	if len(matches) == 0 {
-		return str, true
+// resolveString replaces the expressions of the string in a single pass, only properties of Config and defaults are resolved recursively
+func (r *Resolver) resolveString(str string, n int) (string, []string) {
+	if _, _, err := findExpression(str, 0); err == errNoExpression {
+		return unescape(str), nil
 	}
 	if n == maxLookupDepth {
-		log.Entry().Errorf("Property could not be resolved with a depth of %d. '%s' is still left to resolve", n, str)
-		return "", false
+		return "", []string{fmt.Sprintf("'%s' could not be resolved with a depth of %d", str, n)}
 	}
-	for _, match := range matches {
-		property := match[captureGroups["property"]]
-		if propVal, ok := lookupMap[property]; ok {
-			str = strings.ReplaceAll(str, fmt.Sprintf("$(%s)", property), propVal.(string))
+
+	problems := []string{}
+	var result strings.Builder
+	position := 0
+	for {
+		begin, end, err := findExpression(str, position)
+		if err == errNoExpression {
+			break
+		}
+		if err != nil {
+			if !r.Lenient {
+				problems = append(problems, fmt.Sprintf("%v in '%s'", err, str))
+			} else {
+				log.Entry().Warnf("Keeping '%s' unchanged: %v", str, err)
+			}
+			break
+		}
+		result.WriteString(unescape(str[position:begin]))
+		value, resolved, exprProblems := r.evaluate(str[begin+2:end], n)
+		problems = append(problems, exprProblems...)
+		if resolved {
+			result.WriteString(toString(value))
 		} else {
-			// value not found
-			log.Entry().Debugf("Can't interploate '%s'. Missing property '%s'", str, property)
-			return "", false
+			result.WriteString(str[begin : end+1])
 		}
+		position = end + 1
 	}
-	return resolveString(str, lookupMap, n+1)
+	if len(problems) > 0 {
+		return "", problems
+	}
+	result.WriteString(unescape(str[position:]))
+	return result.String(), nil
 }
 
-// ResolveString takes a string and replaces all references inside of it with values from the given lookupMap.
-// This is being done recursively until the maxLookupDepth is reached.
-func ResolveString(str string, lookupMap map[string]interface{}) (string, bool) {
-	return resolveString(str, lookupMap, 0)
+// evaluate evaluates the content of an expression, i.e. the part between '$(' and ')'.
+// An expression which is not resolved without problems is kept unchanged, this is only the case in lenient mode for expressions which are no reference.
+func (r *Resolver) evaluate(expression string, n int) (interface{}, bool, []string) {
+	term, defaultValue, hasDefault := splitDefault(expression)
+	value, resolved, problems := r.evaluateTerm(strings.TrimSpace(term), n)
+	if hasDefault && (len(problems) > 0 || !resolved || value == nil || value == "") {
+		resolvedDefault, defaultProblems := r.resolveString(defaultValue, n+1)
+		return resolvedDefault, len(defaultProblems) == 0, defaultProblems
+	}
+	return value, resolved, problems
+}
+
+func (r *Resolver) evaluateTerm(term string, n int) (interface{}, bool, []string) {
+	if literal, ok := unquote(term); ok {
+		return literal, true, nil
+	}
+	if matches := functionRegex.FindStringSubmatch(term); matches != nil {
+		name := matches[captureGroups["name"]]
+		value, problems := r.call(name, matches[captureGroups["args"]], n)
+		return value, len(problems) == 0, problems
+	}
+	if !referenceRegex.MatchString(term) {
+		if r.Lenient {
+			log.Entry().Warnf("Keeping '$(%s)' unchanged since it is no valid reference, use '$$(' for a literal '$('", term)
+			return nil, false, nil
+		}
+		return nil, false, []string{fmt.Sprintf("invalid expression '$(%s)'", term)}
+	}
+	value, ok := r.lookup(term)
+	if !ok {
+		return nil, false, []string{fmt.Sprintf("$(%s)", term)}
+	}
+	// properties of the configuration may reference other properties
+	if str, isString := value.(string); isString && !strings.HasPrefix(term, envPrefix) && !strings.HasPrefix(term, cpePrefix) && !r.Literal[term] {
+		resolved, problems := r.resolveString(str, n+1)
+		return resolved, len(problems) == 0, problems
+	}
+	return value, true, nil
+}
+
+func (r *Resolver) call(name, arguments string, n int) (interface{}, []string) {
+	function, ok := functions[name]
+	if !ok {
+		return nil, []string{fmt.Sprintf("unknown function '%s'", name)}
+	}
+	args := []string{}
+	problems := []string{}
+	for _, arg := range splitTopLevel(arguments, ",") {
+		value, resolved, argProblems := r.evaluate(strings.TrimSpace(arg), n)
+		problems = append(problems, argProblems...)
+		if !resolved && len(argProblems) == 0 {
+			problems = append(problems, fmt.Sprintf("$(%s)", strings.TrimSpace(arg)))
+		}
+		args = append(args, toString(value))
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	result, err := function(args)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	return result, nil
+}
+
+func (r *Resolver) lookup(reference string) (interface{}, bool) {
+	if strings.HasPrefix(reference, envPrefix) {
+		lookupEnv := r.LookupEnv
+		if lookupEnv == nil {
+			lookupEnv = os.LookupEnv
+		}
+		return lookupEnv(strings.TrimPrefix(reference, envPrefix))
+	}
+	if strings.HasPrefix(reference, cpePrefix) {
+		key := strings.TrimPrefix(reference, cpePrefix)
+		if value, ok := r.CPE[key]; ok {
+			return value, true
+		}
+		value, ok := r.CPE[strings.ReplaceAll(key, ".", "/")]
+		return value, ok
+	}
+	if value, ok := r.Config[reference]; ok {
+		return value, true
+	}
+	return lookupNested(r.Config, strings.Split(reference, "."))
+}
+
+func lookupNested(m map[string]interface{}, path []string) (interface{}, bool) {
+	value, ok := m[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	subMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupNested(subMap, path[1:])
+}
+
+var errNoExpression = fmt.Errorf("no expression found")
+
+// findExpression returns the position of the next '$(' and its matching ')' starting at the given offset
+func findExpression(str string, offset int) (int, int, error) {
+	for i := offset; i < len(str); i++ {
+		if strings.HasPrefix(str[i:], escapedStart) {
+			i += len(escapedStart) - 1
+			continue
+		}
+		if !strings.HasPrefix(str[i:], expressionStart) {
+			continue
+		}
+		depth := 0
+		var quote byte
+		for j := i + 1; j < len(str); j++ {
+			switch c := str[j]; {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+				if depth == 0 {
+					return i, j, nil
+				}
+			}
+		}
+		return 0, 0, fmt.Errorf("unterminated expression starting at position %d", i)
+	}
+	return 0, 0, errNoExpression
+}
+
+// splitTopLevel splits the string at every separator which is neither quoted nor inside of parentheses
+func splitTopLevel(str, separator string) []string {
+	parts := []string{}
+	depth := 0
+	var quote byte
+	last := 0
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(str[i:], separator):
+			parts = append(parts, str[last:i])
+			i += len(separator) - 1
+			last = i + 1
+		}
+	}
+	return append(parts, str[last:])
+}
+
+func splitDefault(expression string) (string, string, bool) {
+	parts := splitTopLevel(expression, defaultSeparator)
+	if len(parts) == 1 {
+		return expression, "", false
+	}
+	return parts[0], strings.Join(parts[1:], defaultSeparator), true
+}
+
+func unquote(term string) (string, bool) {
+	if len(term) >= 2 && (term[0] == '"' || term[0] == '\'') && term[len(term)-1] == term[0] {
+		return term[1 : len(term)-1], true
+	}
+	return "", false
+}
+
+func unescape(str string) string {
+	return strings.ReplaceAll(str, escapedStart, expressionStart)
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		if jsonValue, err := json.Marshal(v); err == nil {
+			return string(jsonValue)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func checkArgCount(name string, args []string, expected int) error {
+	if len(args) != expected {
+		return fmt.Errorf("function '%s' expects %d argument(s) but got %d", name, expected, len(args))
+	}
+	return nil
 }
 
 func setupCaptureGroups(captureGroupsList []string) map[string]int {
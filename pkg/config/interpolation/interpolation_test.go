@@ -43,3 +43,204 @@ func TestResolveMap(t *testing.T) {
 	})
 
 }
+
+func TestResolveString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("That lookup works", func(t *testing.T) {
+		resolved, ok := ResolveString("$(vaultBasePath)/$(vaultPipelineName)/github", map[string]interface{}{
+			"vaultBasePath":     "piper",
+			"vaultPipelineName": "pipeline",
+		})
+		assert.True(t, ok)
+		assert.Equal(t, "piper/pipeline/github", resolved)
+	})
+
+	t.Run("That non-string values are converted", func(t *testing.T) {
+		resolved, ok := ResolveString("$(org)-$(space)", map[string]interface{}{
+			"org":   42,
+			"space": true,
+		})
+		assert.True(t, ok)
+		assert.Equal(t, "42-true", resolved)
+	})
+
+	t.Run("That lookup fails when property is not found", func(t *testing.T) {
+		_, ok := ResolveString("$(vaultPath)/github", map[string]interface{}{})
+		assert.False(t, ok)
+	})
+}
+
+func TestResolver(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"HOME":  "/home/piper",
+		"USER":  "PIPER",
+		"EMPTY": "",
+	}
+	r := Resolver{
+		Config: map[string]interface{}{
+			"prop1": "val1",
+			"nested": map[string]interface{}{
+				"key": "nestedValue",
+			},
+			"list": []interface{}{"a", "b"},
+			"flag": true,
+		},
+		CPE: map[string]interface{}{
+			"artifactVersion": "1.2.3-SNAPSHOT",
+			"git/branch":      "main",
+		},
+		LookupEnv: func(key string) (string, bool) {
+			val, ok := env[key]
+			return val, ok
+		},
+	}
+
+	tt := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "no reference", input: "plain", expected: "plain"},
+		{name: "property", input: "$(prop1)", expected: "val1"},
+		{name: "nested property", input: "$(nested.key)", expected: "nestedValue"},
+		{name: "environment variable", input: "$(env.HOME)/.m2", expected: "/home/piper/.m2"},
+		{name: "cpe value", input: "v$(cpe.artifactVersion)", expected: "v1.2.3-SNAPSHOT"},
+		{name: "nested cpe value with slash", input: "$(cpe.git/branch)", expected: "main"},
+		{name: "nested cpe value with dot", input: "$(cpe.git.branch)", expected: "main"},
+		{name: "default for missing value", input: "$(missing:-fallback)", expected: "fallback"},
+		{name: "default for empty value", input: "$(env.EMPTY:-fallback)", expected: "fallback"},
+		{name: "default not used", input: "$(prop1:-fallback)", expected: "val1"},
+		{name: "default with reference", input: "$(missing:-$(prop1))", expected: "val1"},
+		{name: "lower", input: "$(lower(env.USER))", expected: "piper"},
+		{name: "upper", input: "$(upper(prop1))", expected: "VAL1"},
+		{name: "replace", input: `$(replace(cpe.artifactVersion, ".", "_"))`, expected: "1_2_3-SNAPSHOT"},
+		{name: "trimSuffix", input: `$(trimSuffix(cpe.artifactVersion, "-SNAPSHOT"))`, expected: "1.2.3"},
+		{name: "trimPrefix", input: `$(trimPrefix(env.HOME, '/home/'))`, expected: "piper"},
+		{name: "nested functions", input: `$(lower(trimSuffix(cpe.artifactVersion, "-SNAPSHOT")))`, expected: "1.2.3"},
+		{name: "function with default argument", input: `$(upper(missing:-abc))`, expected: "ABC"},
+		{name: "list value", input: "$(list)", expected: `["a","b"]`},
+		{name: "escaped expression", input: "$$(pwd)/$(prop1)", expected: "$(pwd)/val1"},
+	}
+
+	for _, test := range tt {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			resolved, err := r.ResolveString(test.input)
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, resolved)
+			}
+		})
+	}
+
+	t.Run("That all unresolved references are reported", func(t *testing.T) {
+		_, err := r.ResolveString("$(missing)/$(env.MISSING)/$(cpe.missing)")
+		assert.EqualError(t, err, "failed to resolve 3 reference(s): $(missing), $(env.MISSING), $(cpe.missing)")
+	})
+
+	t.Run("That unknown functions are reported", func(t *testing.T) {
+		_, err := r.ResolveString("$(foo(prop1))")
+		assert.EqualError(t, err, "failed to resolve 1 reference(s): unknown function 'foo'")
+	})
+
+	t.Run("That wrong argument counts are reported", func(t *testing.T) {
+		_, err := r.ResolveString("$(replace(prop1))")
+		assert.EqualError(t, err, "failed to resolve 1 reference(s): function 'replace' expects 3 argument(s) but got 1")
+	})
+
+	t.Run("That unterminated expressions are reported", func(t *testing.T) {
+		_, err := r.ResolveString("$(prop1")
+		assert.EqualError(t, err, "failed to resolve 1 reference(s): unterminated expression starting at position 0 in '$(prop1'")
+	})
+}
+
+func TestResolverResolveMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("That nested values are resolved and types are kept", func(t *testing.T) {
+		config := map[string]interface{}{
+			"flag":    true,
+			"flagRef": "$(flag)",
+			"name":    "piper",
+			"env": map[string]interface{}{
+				"NAME": "$(name)",
+			},
+			"options": []interface{}{"--name=$(name)", 42},
+		}
+		r := Resolver{Config: config}
+
+		err := r.ResolveMap(config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, true, config["flagRef"])
+		assert.Equal(t, map[string]interface{}{"NAME": "piper"}, config["env"])
+		assert.Equal(t, []interface{}{"--name=piper", 42}, config["options"])
+	})
+
+	t.Run("That every unresolved reference is listed", func(t *testing.T) {
+		config := map[string]interface{}{
+			"prop1": "$(missing1)",
+			"prop2": "$(prop1)/$(cpe.missing2)",
+		}
+		r := Resolver{Config: config}
+
+		err := r.ResolveMap(config)
+
+		assert.EqualError(t, err, "failed to resolve 3 reference(s): $(missing1) (parameter 'prop1'), $(missing1) (parameter 'prop2'), $(cpe.missing2) (parameter 'prop2')")
+	})
+
+	t.Run("That expressions which are no reference are kept in lenient mode", func(t *testing.T) {
+		config := map[string]interface{}{
+			"dockerOptions": []interface{}{"-v $$(pwd):/project", "--name $(name)"},
+			"script":        "cd $(git rev-parse --show-toplevel) && echo $(missing:-default) $(unterminated",
+			"name":          "piper",
+		}
+		r := Resolver{Config: config, Lenient: true}
+
+		err := r.ResolveMap(config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"-v $(pwd):/project", "--name piper"}, config["dockerOptions"])
+		assert.Equal(t, "cd $(git rev-parse --show-toplevel) && echo default $(unterminated", config["script"])
+	})
+
+	t.Run("That unresolved references and unknown functions are reported in lenient mode", func(t *testing.T) {
+		config := map[string]interface{}{
+			"prop1": "$(env.PIPER_MISSING)",
+			"prop2": "$(lower(missing))",
+			"prop3": "-v $(pwd):/project",
+			"prop4": "$(basename(prop3))",
+		}
+		r := Resolver{Config: config, Lenient: true, LookupEnv: func(string) (string, bool) { return "", false }}
+
+		err := r.ResolveMap(config)
+
+		assert.EqualError(t, err, "failed to resolve 4 reference(s): $(env.PIPER_MISSING) (parameter 'prop1'), $(missing) (parameter 'prop2'), $(pwd) (parameter 'prop3'), unknown function 'basename' (parameter 'prop4')")
+	})
+
+	t.Run("That values from env, cpe and literal properties are not resolved again", func(t *testing.T) {
+		config := map[string]interface{}{
+			"fromEnv":     "$(env.INJECTED)",
+			"fromCpe":     "$(cpe.injected)",
+			"literal":     "$(env.SECRET)",
+			"referencing": "$(literal)",
+		}
+		env := map[string]string{"INJECTED": "$(env.SECRET)", "SECRET": "secret"}
+		r := Resolver{
+			Config:    config,
+			CPE:       map[string]interface{}{"injected": "$(env.SECRET)"},
+			Literal:   map[string]bool{"literal": true},
+			LookupEnv: func(key string) (string, bool) { val, ok := env[key]; return val, ok },
+		}
+
+		err := r.ResolveMap(config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "$(env.SECRET)", config["fromEnv"])
+		assert.Equal(t, "$(env.SECRET)", config["fromCpe"])
+		assert.Equal(t, "$(env.SECRET)", config["literal"])
+		assert.Equal(t, "$(env.SECRET)", config["referencing"])
+	})
+}
@@ -32,6 +32,29 @@ func (_m *VaultMock) GetKvSecret(_a0 string) (map[string]string, error) {
 	return r0, r1
 }
 
+// GetDynamicSecret provides a mock function with given fields: _a0
+func (_m *VaultMock) GetDynamicSecret(_a0 string) (map[string]string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // MustRevokeToken provides a mock function with given fields:
 func (_m *VaultMock) MustRevokeToken() {
 	_m.Called()
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"io"
+	"sort"
+)
+
+// Layers of the configuration hierarchy which can set a parameter value, in order of their precedence
+const (
+	LayerStepDefault               = "stepDefault"
+	LayerCommonPipelineEnvironment = "commonPipelineEnvironment"
+	LayerDefaults                  = "defaults"
+	LayerConfig                    = "config"
+	LayerEnvironment               = "environment"
+	LayerParametersJSON            = "parametersJSON"
+	LayerFlag                      = "flag"
+	LayerInterpolation             = "interpolation"
+	LayerVault                     = "vault"
+	LayerCondition                 = "condition"
+)
+
+const (
+	sectionGeneral = "general"
+	sectionSteps   = "steps/"
+	sectionStages  = "stages/"
+
+	maskedValue = "****"
+)
+
+// ValueOrigin describes a configuration layer which set or overrode a parameter value
+type ValueOrigin struct {
+	Layer   string      `json:"layer"`
+	Source  string      `json:"source,omitempty"`
+	Section string      `json:"section,omitempty"`
+	Alias   string      `json:"alias,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+// ParameterProvenance contains the final value of a parameter and all layers which set it, the last one wins
+type ParameterProvenance struct {
+	Name    string        `json:"name"`
+	Value   interface{}   `json:"value"`
+	Secret  bool          `json:"secret,omitempty"`
+	Origins []ValueOrigin `json:"origins"`
+}
+
+// TrackProvenance enables the recording of the origin of every parameter value in GetStepConfig
+func (c *Config) TrackProvenance() {
+	c.trackProvenance = true
+}
+
+// GetProvenance returns the provenance of all parameters sorted by name.
+// Values of secret parameters, parameters listed in secretNames and values retrieved from vault are masked.
+func (s *StepConfig) GetProvenance(parameters []StepParameters, secrets []StepSecrets, secretNames ...string) []ParameterProvenance {
+	secretParams := map[string]bool{}
+	for _, p := range parameters {
+		if p.Secret || p.GetReference("vaultSecret") != nil || p.GetReference("vaultSecretFile") != nil {
+			secretParams[p.Name] = true
+		}
+	}
+	for _, sec := range secrets {
+		secretParams[sec.Name] = true
+	}
+	for _, name := range secretNames {
+		secretParams[name] = true
+	}
+
+	names := []string{}
+	for name := range s.Config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []ParameterProvenance{}
+	for _, name := range names {
+		secret := secretParams[name]
+		origins := []ValueOrigin{}
+		for _, origin := range s.Provenance[name] {
+			if isSecretProvider(origin.Layer) {
+				secret = true
+			}
+			origins = append(origins, origin)
+		}
+		value := s.Config[name]
+		if secret {
+			value = maskValue(value)
+			for i := range origins {
+				origins[i].Value = maskValue(origins[i].Value)
+			}
+		}
+		result = append(result, ParameterProvenance{Name: name, Value: value, Secret: secret, Origins: origins})
+	}
+	return result
+}
+
+func (s *StepConfig) recordOrigins(data map[string]interface{}, filter []string, origin ValueOrigin, aliases map[string]string) {
+	if s.Provenance == nil {
+		return
+	}
+	for key, value := range filterMap(data, filter) {
+		o := origin
+		o.Value = value
+		o.Alias = aliases[key]
+		s.Provenance[key] = append(s.Provenance[key], o)
+	}
+}
+
+func (s *StepConfig) recordOrigin(name string, origin ValueOrigin) {
+	if s.Provenance == nil {
+		return
+	}
+	s.Provenance[name] = append(s.Provenance[name], origin)
+}
+
+func (c *Config) recordAlias(section, name, alias string) {
+	if c.aliasOrigins == nil || len(alias) == 0 {
+		return
+	}
+	if c.aliasOrigins[section] == nil {
+		c.aliasOrigins[section] = map[string]string{}
+	}
+	c.aliasOrigins[section][name] = alias
+}
+
+// sourceName returns the name of a configuration source if available, e.g. for files
+func sourceName(source io.ReadCloser, fallback string) string {
+	if named, ok := source.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fallback
+}
+
+func maskValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return maskedValue
+}
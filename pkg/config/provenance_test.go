@@ -0,0 +1,123 @@
+package config
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStepConfigProvenance(t *testing.T) {
+	parameters := []StepParameters{
+		{Name: "p0", Default: "p0_step_default"},
+		{Name: "p1", Aliases: []Alias{{Name: "p1Alias"}}},
+		{Name: "p2"},
+		{Name: "p3"},
+		{Name: "p4"},
+		{Name: "password", Secret: true},
+	}
+	filters := StepFilters{
+		All:        []string{"p0", "p1", "p2", "p3", "p4", "password"},
+		General:    []string{"p0", "p1", "p2", "p3", "p4", "password"},
+		Steps:      []string{"p0", "p1", "p2", "p3", "p4", "password"},
+		Stages:     []string{"p0", "p1", "p2", "p3", "p4", "password"},
+		Parameters: []string{"p0", "p1", "p2", "p3", "p4", "password"},
+		Env:        []string{"p0", "p1", "p2", "p3", "p4", "password"},
+	}
+	testConfig := `general:
+  p0: p0_general
+  password: secret_general
+steps:
+  step1:
+    p1Alias: p1_alias
+stages:
+  stage1:
+    p2: p2_stage
+`
+	testDefaults := `general:
+  p0: p0_defaults
+  p3: p3_defaults
+`
+	os.Setenv("PIPER_p3", "p3_env")
+	defer os.Unsetenv("PIPER_p3")
+
+	var c Config
+	c.TrackProvenance()
+	defaults := []io.ReadCloser{ioutil.NopCloser(strings.NewReader(testDefaults))}
+	stepConfig, err := c.GetStepConfig(map[string]interface{}{"p4": "p4_flag"}, `{"p2": "p2_json"}`, ioutil.NopCloser(strings.NewReader(testConfig)), defaults, false, filters, parameters, nil, nil, "stage1", "step1", []Alias{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ValueOrigin{
+		{Layer: LayerStepDefault, Value: "p0_step_default"},
+		{Layer: LayerDefaults, Source: "defaults #1", Section: "general", Value: "p0_defaults"},
+		{Layer: LayerConfig, Source: "project configuration", Section: "general", Value: "p0_general"},
+	}, stepConfig.Provenance["p0"])
+	assert.Equal(t, []ValueOrigin{
+		{Layer: LayerConfig, Source: "project configuration", Section: "steps/step1", Alias: "p1Alias", Value: "p1_alias"},
+	}, stepConfig.Provenance["p1"])
+	assert.Equal(t, []ValueOrigin{
+		{Layer: LayerConfig, Source: "project configuration", Section: "stages/stage1", Value: "p2_stage"},
+		{Layer: LayerParametersJSON, Value: "p2_json"},
+	}, stepConfig.Provenance["p2"])
+	assert.Equal(t, []ValueOrigin{
+		{Layer: LayerDefaults, Source: "defaults #1", Section: "general", Value: "p3_defaults"},
+		{Layer: LayerEnvironment, Source: "PIPER_p3", Value: "p3_env"},
+	}, stepConfig.Provenance["p3"])
+	assert.Equal(t, []ValueOrigin{
+		{Layer: LayerFlag, Value: "p4_flag"},
+	}, stepConfig.Provenance["p4"])
+
+	t.Run("GetProvenance", func(t *testing.T) {
+		provenance := stepConfig.GetProvenance(parameters, nil)
+
+		names := []string{}
+		for _, p := range provenance {
+			names = append(names, p.Name)
+		}
+		assert.Equal(t, []string{"p0", "p1", "p2", "p3", "p4", "password"}, names)
+		assert.Equal(t, "p2_json", provenance[2].Value)
+		assert.Equal(t, ParameterProvenance{
+			Name:    "password",
+			Value:   "****",
+			Secret:  true,
+			Origins: []ValueOrigin{{Layer: LayerConfig, Source: "project configuration", Section: "general", Value: "****"}},
+		}, provenance[5])
+	})
+}
+
+func TestGetStepConfigWithoutProvenance(t *testing.T) {
+	var c Config
+	stepConfig, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader("general:\n  p0: p0_general")), nil, false, StepFilters{General: []string{"p0"}}, nil, nil, nil, "stage1", "step1", []Alias{})
+	assert.NoError(t, err)
+	assert.Nil(t, stepConfig.Provenance)
+}
+
+func TestResolveVaultReferenceProvenance(t *testing.T) {
+	stepConfig := StepConfig{Config: map[string]interface{}{"vaultPath": "team1"}, Provenance: map[string][]ValueOrigin{}}
+	param := StepParameters{Name: "token", ResourceRef: []ResourceReference{{Type: "vaultSecret", Paths: []string{"$(vaultPath)/github"}}}}
+	client := &mockVaultProvenanceClient{secrets: map[string]map[string]string{"team1/github": {"token": "secret"}}}
+
+	resolveAllVaultReferences(&stepConfig, client, []StepParameters{param})
+
+	assert.Equal(t, []ValueOrigin{{Layer: LayerVault, Source: "team1/github", Value: "secret"}}, stepConfig.Provenance["token"])
+	provenance := stepConfig.GetProvenance([]StepParameters{{Name: "vaultPath"}}, nil)
+	assert.Equal(t, "****", provenance[0].Value)
+	assert.Equal(t, "team1", provenance[1].Value)
+}
+
+type mockVaultProvenanceClient struct {
+	secrets map[string]map[string]string
+}
+
+func (m *mockVaultProvenanceClient) GetKvSecret(path string) (map[string]string, error) {
+	return m.secrets[path], nil
+}
+
+func (m *mockVaultProvenanceClient) GetDynamicSecret(path string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (m *mockVaultProvenanceClient) MustRevokeToken() {}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Names of the available secret providers. They are used in the configuration parameter secretProviders,
+// in the providers of a resource reference and as layer in the provenance of a resolved secret.
+const (
+	SecretProviderVault         = LayerVault
+	SecretProviderDirectory     = "secretDirectory"
+	SecretProviderEncryptedFile = "encryptedSecretFile"
+)
+
+// EncryptedSecretFileKeyEnv defines the environment variable holding the base64 encoded AES-256 key of the encrypted secret file
+const EncryptedSecretFileKeyEnv = "PIPER_encryptedSecretFileKey"
+
+// isSecretProvider checks if a provenance layer denotes a secret provider
+func isSecretProvider(layer string) bool {
+	return layer == SecretProviderVault || layer == SecretProviderDirectory || layer == SecretProviderEncryptedFile
+}
+
+// secretStore is implemented by all secret providers, e.g. the vault client
+type secretStore interface {
+	GetKvSecret(string) (map[string]string, error)
+}
+
+type namedSecretStore struct {
+	name  string
+	store secretStore
+}
+
+// secretProviderChain resolves secrets by asking the providers in the configured order
+type secretProviderChain []namedSecretStore
+
+// GetKvSecret returns the first secret found at the given path
+func (chain secretProviderChain) GetKvSecret(path string) (map[string]string, error) {
+	secret, _, err := chain.lookup(path)
+	return secret, err
+}
+
+// lookup returns the first secret found at the given path together with the name of the provider which provided it
+func (chain secretProviderChain) lookup(path string) (map[string]string, string, error) {
+	var lastErr error
+	for _, provider := range chain {
+		secret, err := provider.store.GetKvSecret(path)
+		if err != nil {
+			log.Entry().WithError(err).Debugf("Secret provider '%v' couldn't fetch secret at '%v'", provider.name, path)
+			lastErr = err
+			continue
+		}
+		if secret != nil {
+			return secret, provider.name, nil
+		}
+	}
+	return nil, "", lastErr
+}
+
+// restrict returns the providers contained in names, an empty list of names does not restrict the chain
+func (chain secretProviderChain) restrict(names []string) secretProviderChain {
+	if len(names) == 0 {
+		return chain
+	}
+	restricted := secretProviderChain{}
+	for _, provider := range chain {
+		if sliceContains(names, provider.name) {
+			restricted = append(restricted, provider)
+		}
+	}
+	return restricted
+}
+
+// lookupSecret fetches a secret from a store and returns the name of the provider serving it
+func lookupSecret(store secretStore, path string) (map[string]string, string, error) {
+	if chain, ok := store.(secretProviderChain); ok {
+		return chain.lookup(path)
+	}
+	secret, err := store.GetKvSecret(path)
+	return secret, SecretProviderVault, err
+}
+
+// getSecretProviders creates the chain of secret providers according to the configuration.
+// Without parameter secretProviders all providers which are configured are used in the order vault, secretDirectory, encryptedSecretFile.
+func getSecretProviders(config StepConfig, vault vaultClient) (secretProviderChain, error) {
+	names := []string{}
+	if configured, ok := config.Config["secretProviders"].([]interface{}); ok {
+		for _, name := range configured {
+			names = append(names, fmt.Sprint(name))
+		}
+	} else {
+		names = []string{SecretProviderVault, SecretProviderDirectory, SecretProviderEncryptedFile}
+	}
+
+	chain := secretProviderChain{}
+	for _, name := range names {
+		switch name {
+		case SecretProviderVault:
+			if vault != nil {
+				chain = append(chain, namedSecretStore{name: name, store: vault})
+			}
+		case SecretProviderDirectory:
+			if dir, ok := config.Config[SecretProviderDirectory].(string); ok && dir != "" {
+				log.Entry().Infof("Fetching secrets from directory %s", dir)
+				chain = append(chain, namedSecretStore{name: name, store: &directorySecretStore{root: dir}})
+			}
+		case SecretProviderEncryptedFile:
+			if file, ok := config.Config[SecretProviderEncryptedFile].(string); ok && file != "" {
+				store, err := newEncryptedFileSecretStore(file, os.Getenv(EncryptedSecretFileKeyEnv))
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to initialize secret provider '%v'", name)
+				}
+				log.Entry().Infof("Fetching secrets from encrypted file %s", file)
+				chain = append(chain, namedSecretStore{name: name, store: store})
+			}
+		default:
+			return nil, errors.Errorf("unknown secret provider '%v', supported providers: %v, %v, %v", name, SecretProviderVault, SecretProviderDirectory, SecretProviderEncryptedFile)
+		}
+	}
+	return chain, nil
+}
+
+// directorySecretStore reads secrets from a directory structure like it is created when mounting Kubernetes secrets.
+// A secret at path 'team1/github' is either the directory <root>/team1/github containing one file per key
+// or a file <root>/team1/github.json or <root>/team1/github.yaml containing the keys as map.
+type directorySecretStore struct {
+	root string
+}
+
+// GetKvSecret reads the secret at path, a missing secret is not an error
+func (d *directorySecretStore) GetKvSecret(path string) (map[string]string, error) {
+	secretPath := filepath.Join(d.root, filepath.FromSlash(path))
+	if rel, err := filepath.Rel(d.root, secretPath); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, errors.Errorf("secret path '%v' is outside of the secret directory", path)
+	}
+
+	info, err := os.Stat(secretPath)
+	if err == nil && info.IsDir() {
+		return readSecretDirectory(secretPath)
+	}
+
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		content, err := ioutil.ReadFile(secretPath + ext)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read secret file '%v'", secretPath+ext)
+		}
+		secret := map[string]string{}
+		if err := yaml.Unmarshal(content, &secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse secret file '%v'", secretPath+ext)
+		}
+		return secret, nil
+	}
+	return nil, nil
+}
+
+func readSecretDirectory(dir string) (map[string]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret directory '%v'", dir)
+	}
+	secret := map[string]string{}
+	for _, entry := range entries {
+		// Kubernetes creates hidden directories (e.g. ..data) and symlinks to them for mounted secrets
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		filePath := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(filePath)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read secret file '%v'", filePath)
+		}
+		secret[entry.Name()] = strings.TrimSuffix(string(content), "\n")
+	}
+	return secret, nil
+}
+
+// encryptedValuePattern matches values in the format PIPER_SECRET[v1,data:<base64>,iv:<base64>,tag:<base64>]
+var encryptedValuePattern = regexp.MustCompile(`^PIPER_SECRET\[v1,data:([^,]*),iv:([^,]+),tag:([^,\]]+)\]$`)
+
+// sopsValuePattern matches values encrypted by SOPS, which are not supported
+var sopsValuePattern = regexp.MustCompile(`^ENC\[[A-Z0-9_]+,`)
+
+// encryptedFileSecretStore reads secrets from a piper secret file, a local yaml file with encrypted values as written by 'piper encryptSecret'.
+// The file contains the secrets below the key 'secrets', every value is encrypted with AES-256-GCM
+// using the secret path and key as additional data ('<path>:<key>:').
+// The format is specific to piper, files encrypted by SOPS are rejected.
+//
+//	secrets:
+//	  team1/github:
+//	    token: PIPER_SECRET[v1,data:...,iv:...,tag:...]
+type encryptedFileSecretStore struct {
+	secrets map[string]map[string]string
+	aead    cipher.AEAD
+}
+
+// encryptedSecretFile is the content of an encrypted secret file
+type encryptedSecretFile struct {
+	Secrets map[string]map[string]string `json:"secrets"`
+	// Sops is only read in order to detect files encrypted by SOPS
+	Sops interface{} `json:"sops,omitempty"`
+}
+
+func parseEncryptedSecretFile(content []byte) (encryptedSecretFile, error) {
+	var encryptedFile encryptedSecretFile
+	if err := yaml.Unmarshal(content, &encryptedFile); err != nil {
+		return encryptedSecretFile{}, err
+	}
+	if encryptedFile.Sops != nil {
+		return encryptedSecretFile{}, errors.New("files encrypted by SOPS are not supported, please use a file written by 'piper encryptSecret'")
+	}
+	return encryptedFile, nil
+}
+
+func newEncryptedFileSecretStore(file, key string) (*encryptedFileSecretStore, error) {
+	if key == "" {
+		return nil, errors.Errorf("no key provided, please set environment variable %v", EncryptedSecretFileKeyEnv)
+	}
+	aead, err := newSecretFileCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read encrypted secret file '%v'", file)
+	}
+	encryptedFile, err := parseEncryptedSecretFile(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse encrypted secret file '%v'", file)
+	}
+	return &encryptedFileSecretStore{secrets: encryptedFile.Secrets, aead: aead}, nil
+}
+
+func newSecretFileCipher(key string) (cipher.AEAD, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode key of encrypted secret file")
+	}
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid key of encrypted secret file")
+	}
+	return cipher.NewGCM(block)
+}
+
+// GetKvSecret decrypts all values of the secret at path, a missing secret is not an error
+func (e *encryptedFileSecretStore) GetKvSecret(path string) (map[string]string, error) {
+	encrypted, ok := e.secrets[path]
+	if !ok {
+		return nil, nil
+	}
+	secret := map[string]string{}
+	for key, value := range encrypted {
+		decrypted, err := e.decrypt(value, fmt.Sprintf("%v:%v:", path, key))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt key '%v' of secret '%v'", key, path)
+		}
+		secret[key] = decrypted
+	}
+	return secret, nil
+}
+
+func (e *encryptedFileSecretStore) decrypt(value, additionalData string) (string, error) {
+	match := encryptedValuePattern.FindStringSubmatch(value)
+	if match == nil {
+		if sopsValuePattern.MatchString(value) {
+			return "", errors.New("values encrypted by SOPS are not supported")
+		}
+		return "", errors.New("value is not encrypted")
+	}
+	parts := [][]byte{}
+	for _, encoded := range match[1:] {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", errors.Wrap(err, "invalid encoding")
+		}
+		parts = append(parts, decoded)
+	}
+	data, iv, tag := parts[0], parts[1], parts[2]
+	if len(iv) != e.aead.NonceSize() {
+		return "", errors.Errorf("invalid iv length %v", len(iv))
+	}
+	plain, err := e.aead.Open(nil, iv, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return "", errors.Wrap(err, "authentication failed")
+	}
+	return string(plain), nil
+}
+
+// EncryptSecretFileValue encrypts a value for the usage in an encrypted secret file at the given path and key
+func EncryptSecretFileValue(key, path, secretKey, value string, nonce []byte) (string, error) {
+	aead, err := newSecretFileCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return "", errors.Errorf("nonce must have a length of %v bytes", aead.NonceSize())
+	}
+	sealed := aead.Seal(nil, nonce, []byte(value), []byte(fmt.Sprintf("%v:%v:", path, secretKey)))
+	data, tag := sealed[:len(sealed)-aead.Overhead()], sealed[len(sealed)-aead.Overhead():]
+	return fmt.Sprintf("PIPER_SECRET[v1,data:%v,iv:%v,tag:%v]",
+		base64.StdEncoding.EncodeToString(data),
+		base64.StdEncoding.EncodeToString(nonce),
+		base64.StdEncoding.EncodeToString(tag)), nil
+}
+
+// GenerateSecretFileKey generates a random base64 encoded AES-256 key for an encrypted secret file
+func GenerateSecretFileKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", errors.Wrap(err, "failed to generate key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// SetEncryptedSecret encrypts the value with a random nonce and adds it to the content of an encrypted secret file.
+// An existing value at the same path and key is replaced, the other values are kept as they are.
+func SetEncryptedSecret(content []byte, key, path, secretKey, value string) ([]byte, error) {
+	encryptedFile, err := parseEncryptedSecretFile(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse encrypted secret file")
+	}
+	if encryptedFile.Secrets == nil {
+		encryptedFile.Secrets = map[string]map[string]string{}
+	}
+	if encryptedFile.Secrets[path] == nil {
+		encryptedFile.Secrets[path] = map[string]string{}
+	}
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	encrypted, err := EncryptSecretFileValue(key, path, secretKey, value, nonce)
+	if err != nil {
+		return nil, err
+	}
+	encryptedFile.Secrets[path][secretKey] = encrypted
+	return yaml.Marshal(encryptedFile)
+}
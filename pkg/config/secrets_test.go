@@ -0,0 +1,230 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecretFileKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+func TestDirectorySecretStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "team1", "github", "..data"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "team1", "github", "token"), []byte("secretToken\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "team1", "github", ".hidden"), []byte("hidden"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "team1", "sonar.json"), []byte(`{"token": "sonarToken"}`), 0600))
+
+	store := &directorySecretStore{root: dir}
+
+	t.Run("directory with one file per key", func(t *testing.T) {
+		secret, err := store.GetKvSecret("team1/github")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secretToken"}, secret)
+	})
+
+	t.Run("secret file", func(t *testing.T) {
+		secret, err := store.GetKvSecret("team1/sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "sonarToken"}, secret)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		secret, err := store.GetKvSecret("team1/notExisting")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("path outside of directory", func(t *testing.T) {
+		_, err := store.GetKvSecret("../etc")
+		assert.EqualError(t, err, "secret path '../etc' is outside of the secret directory")
+	})
+}
+
+func TestEncryptedFileSecretStore(t *testing.T) {
+	nonce := []byte("123456789012")
+	encrypted, err := EncryptSecretFileValue(testSecretFileKey, "team1/github", "token", "secretToken", nonce)
+	require.NoError(t, err)
+	wrongContext, err := EncryptSecretFileValue(testSecretFileKey, "team1/other", "token", "secretToken", nonce)
+	require.NoError(t, err)
+
+	file := writeEncryptedSecretFile(t, fmt.Sprintf("secrets:\n  team1/github:\n    token: %v\n  team1/moved:\n    token: %v\n  team1/plain:\n    token: plain\n", encrypted, wrongContext))
+	defer os.Remove(file)
+
+	t.Run("success", func(t *testing.T) {
+		store, err := newEncryptedFileSecretStore(file, testSecretFileKey)
+		require.NoError(t, err)
+
+		secret, err := store.GetKvSecret("team1/github")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secretToken"}, secret)
+
+		secret, err = store.GetKvSecret("team1/notExisting")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("error - value moved to other path", func(t *testing.T) {
+		store, err := newEncryptedFileSecretStore(file, testSecretFileKey)
+		require.NoError(t, err)
+		_, err = store.GetKvSecret("team1/moved")
+		assert.EqualError(t, err, "failed to decrypt key 'token' of secret 'team1/moved': authentication failed: cipher: message authentication failed")
+	})
+
+	t.Run("error - value not encrypted", func(t *testing.T) {
+		store, err := newEncryptedFileSecretStore(file, testSecretFileKey)
+		require.NoError(t, err)
+		_, err = store.GetKvSecret("team1/plain")
+		assert.EqualError(t, err, "failed to decrypt key 'token' of secret 'team1/plain': value is not encrypted")
+	})
+
+	t.Run("error - value encrypted by SOPS", func(t *testing.T) {
+		sopsValueFile := writeEncryptedSecretFile(t, "secrets:\n  team1/github:\n    token: ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]\n")
+		defer os.Remove(sopsValueFile)
+		store, err := newEncryptedFileSecretStore(sopsValueFile, testSecretFileKey)
+		require.NoError(t, err)
+		_, err = store.GetKvSecret("team1/github")
+		assert.EqualError(t, err, "failed to decrypt key 'token' of secret 'team1/github': values encrypted by SOPS are not supported")
+	})
+
+	t.Run("error - file encrypted by SOPS", func(t *testing.T) {
+		sopsFile := writeEncryptedSecretFile(t, "secrets:\n  team1/github:\n    token: ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]\nsops:\n  version: 3.7.1\n")
+		defer os.Remove(sopsFile)
+		_, err := newEncryptedFileSecretStore(sopsFile, testSecretFileKey)
+		assert.Contains(t, fmt.Sprint(err), "files encrypted by SOPS are not supported")
+	})
+
+	t.Run("error - wrong key", func(t *testing.T) {
+		store, err := newEncryptedFileSecretStore(file, base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+		require.NoError(t, err)
+		_, err = store.GetKvSecret("team1/github")
+		assert.Error(t, err)
+	})
+
+	t.Run("error - missing key", func(t *testing.T) {
+		_, err := newEncryptedFileSecretStore(file, "")
+		assert.EqualError(t, err, "no key provided, please set environment variable PIPER_encryptedSecretFileKey")
+	})
+}
+
+func TestGetSecretProviders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := writeEncryptedSecretFile(t, "secrets: {}")
+	defer os.Remove(file)
+	os.Setenv(EncryptedSecretFileKeyEnv, testSecretFileKey)
+	defer os.Unsetenv(EncryptedSecretFileKeyEnv)
+
+	names := func(chain secretProviderChain) []string {
+		result := []string{}
+		for _, provider := range chain {
+			result = append(result, provider.name)
+		}
+		return result
+	}
+
+	t.Run("default order of configured providers", func(t *testing.T) {
+		chain, err := getSecretProviders(StepConfig{Config: map[string]interface{}{"secretDirectory": dir, "encryptedSecretFile": file}}, &mocks.VaultMock{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"vault", "secretDirectory", "encryptedSecretFile"}, names(chain))
+	})
+
+	t.Run("explicit order", func(t *testing.T) {
+		chain, err := getSecretProviders(StepConfig{Config: map[string]interface{}{"secretDirectory": dir, "secretProviders": []interface{}{"secretDirectory", "vault"}}}, &mocks.VaultMock{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"secretDirectory", "vault"}, names(chain))
+	})
+
+	t.Run("nothing configured", func(t *testing.T) {
+		chain, err := getSecretProviders(StepConfig{Config: map[string]interface{}{}}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, chain)
+	})
+
+	t.Run("error - unknown provider", func(t *testing.T) {
+		_, err := getSecretProviders(StepConfig{Config: map[string]interface{}{"secretProviders": []interface{}{"keychain"}}}, nil)
+		assert.EqualError(t, err, "unknown secret provider 'keychain', supported providers: vault, secretDirectory, encryptedSecretFile")
+	})
+}
+
+func TestResolveSecretReferencesWithProviders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "team1"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "team1", "pipelineA.yaml"), []byte("token: dirToken\npassword: dirPassword"), 0600))
+
+	vaultMock := &mocks.VaultMock{}
+	vaultMock.On("GetKvSecret", "team1/pipelineA").Return(map[string]string{"password": "vaultPassword"}, nil)
+
+	chain := secretProviderChain{
+		{name: SecretProviderVault, store: vaultMock},
+		{name: SecretProviderDirectory, store: &directorySecretStore{root: dir}},
+	}
+	stepConfig := StepConfig{Config: map[string]interface{}{"vaultPath": "team1"}, Provenance: map[string][]ValueOrigin{}}
+	tokenParam := stepParam("token", "vaultSecret", "$(vaultPath)/pipelineA")
+	tokenParam.ResourceRef[0].Providers = []string{SecretProviderDirectory}
+	params := []StepParameters{
+		tokenParam,
+		stepParam("password", "vaultSecret", "$(vaultPath)/pipelineA"),
+	}
+
+	resolveAllVaultReferences(&stepConfig, chain, params)
+
+	assert.Equal(t, "dirToken", stepConfig.Config["token"])
+	assert.Equal(t, "vaultPassword", stepConfig.Config["password"])
+	assert.Equal(t, []ValueOrigin{{Layer: SecretProviderDirectory, Source: "team1/pipelineA", Value: "dirToken"}}, stepConfig.Provenance["token"])
+	// only the directory provider is allowed for the token
+	vaultMock.AssertNumberOfCalls(t, "GetKvSecret", 1)
+}
+
+func TestSetEncryptedSecret(t *testing.T) {
+	key, err := GenerateSecretFileKey()
+	require.NoError(t, err)
+
+	content, err := SetEncryptedSecret(nil, key, "team1/github", "token", "secretToken")
+	require.NoError(t, err)
+	content, err = SetEncryptedSecret(content, key, "team1/github", "username", "piper")
+	require.NoError(t, err)
+	content, err = SetEncryptedSecret(content, key, "team1/sonar", "token", "sonarToken")
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "secretToken")
+
+	file := writeEncryptedSecretFile(t, string(content))
+	defer os.Remove(file)
+	store, err := newEncryptedFileSecretStore(file, key)
+	require.NoError(t, err)
+
+	secret, err := store.GetKvSecret("team1/github")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": "secretToken", "username": "piper"}, secret)
+	secret, err = store.GetKvSecret("team1/sonar")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": "sonarToken"}, secret)
+
+	t.Run("error - invalid key", func(t *testing.T) {
+		_, err := SetEncryptedSecret(nil, "invalid", "team1/github", "token", "secretToken")
+		assert.Contains(t, err.Error(), "failed to decode key of encrypted secret file")
+	})
+}
+
+func writeEncryptedSecretFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "secrets*.yaml")
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.WriteString(strings.TrimSpace(content))
+	require.NoError(t, err)
+	return file.Name()
+}
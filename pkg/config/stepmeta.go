@@ -66,6 +66,8 @@ type ResourceReference struct {
 	Param   string   `json:"param,omitempty"`
 	Paths   []string `json:"paths,omitempty"`
 	Aliases []Alias  `json:"aliases,omitempty"`
+	// Providers restricts the secret providers used to resolve secret references, all configured providers are used if empty
+	Providers []string `json:"providers,omitempty"`
 }
 
 // Alias defines a step input parameter alias
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/config/interpolation"
@@ -29,12 +32,20 @@ var (
 		"vaultPath",
 		"skipVault",
 		"vaultDisableOverwrite",
+		"vaultSecretVersions",
+		"vaultDynamicSecrets",
 		vaultTestCredentialPath,
 		vaultTestCredentialKeys,
+		"secretProviders",
+		SecretProviderDirectory,
+		SecretProviderEncryptedFile,
 	}
 
 	// VaultSecretFileDirectory holds the directory for the current step run to temporarily store secret files fetched from vault
 	VaultSecretFileDirectory = ""
+
+	// vaultClientsToRevoke holds vault clients whose token must stay valid until the step finished, see RevokeVaultCredentials
+	vaultClientsToRevoke []vaultClient
 )
 
 // VaultCredentials hold all the auth information needed to fetch configuration from vault
@@ -46,10 +57,16 @@ type VaultCredentials struct {
 
 // vaultClient interface for mocking
 type vaultClient interface {
-	GetKvSecret(string) (map[string]string, error)
+	secretStore
+	dynamicSecretStore
 	MustRevokeToken()
 }
 
+// dynamicSecretStore provides credentials of dynamic secrets engines which are bound to a lease
+type dynamicSecretStore interface {
+	GetDynamicSecret(string) (map[string]string, error)
+}
+
 func (s *StepConfig) mixinVaultConfig(configs ...map[string]interface{}) {
 	for _, config := range configs {
 		s.mixIn(config, vaultFilter)
@@ -89,7 +106,8 @@ func getVaultClientFromConfig(config StepConfig, creds VaultCredentials) (vaultC
 	return client, nil
 }
 
-func resolveAllVaultReferences(config *StepConfig, client vaultClient, params []StepParameters) {
+// resolveAllVaultReferences resolves all vaultSecret and vaultSecretFile references with the given secret store
+func resolveAllVaultReferences(config *StepConfig, client secretStore, params []StepParameters) {
 	for _, param := range params {
 		if ref := param.GetReference("vaultSecret"); ref != nil {
 			resolveVaultReference(ref, config, client, param)
@@ -97,16 +115,162 @@ func resolveAllVaultReferences(config *StepConfig, client vaultClient, params []
 		if ref := param.GetReference("vaultSecretFile"); ref != nil {
 			resolveVaultReference(ref, config, client, param)
 		}
+		if ref := param.GetReference("vaultDynamicSecret"); ref != nil {
+			resolveDynamicSecretReference(ref, config, client, param)
+		}
+	}
+}
+
+// hasDynamicSecretReferences checks if credentials of dynamic secrets engines are requested for one of the parameters
+func hasDynamicSecretReferences(params []StepParameters) bool {
+	for _, param := range params {
+		if param.GetReference("vaultDynamicSecret") != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// dynamicSecretParameters returns parameters referencing dynamic secrets as requested via the configuration, e.g.
+//
+//	vaultDynamicSecrets:
+//	  dbUser:
+//	    path: database/creds/$(vaultDatabaseRole)
+//	    field: username
+//
+// The field defaults to the parameter name, only parameters of the step can be requested.
+func dynamicSecretParameters(config *StepConfig, params []StepParameters) []StepParameters {
+	if config.Config["vaultDynamicSecrets"] == nil {
+		return nil
+	}
+	requested, ok := config.Config["vaultDynamicSecrets"].(map[string]interface{})
+	if !ok {
+		log.Entry().Warnf("Ignoring vaultDynamicSecrets since it is not a map of parameter names: '%v'", config.Config["vaultDynamicSecrets"])
+		return nil
+	}
+	names := make([]string, 0, len(requested))
+	for name := range requested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []StepParameters{}
+	for _, name := range names {
+		if !isStepParameter(name, params) {
+			log.Entry().Warnf("Ignoring dynamic secret requested for '%s' since it is not a parameter of the step", name)
+			continue
+		}
+		settings, _ := requested[name].(map[string]interface{})
+		path, _ := settings["path"].(string)
+		if path == "" {
+			log.Entry().Warnf("Ignoring dynamic secret requested for '%s' since no path is configured", name)
+			continue
+		}
+		field, _ := settings["field"].(string)
+		result = append(result, StepParameters{
+			Name:        name,
+			Type:        "string",
+			ResourceRef: []ResourceReference{{Type: "vaultDynamicSecret", Param: field, Paths: []string{path}}},
+		})
+	}
+	return result
+}
+
+func isStepParameter(name string, params []StepParameters) bool {
+	for _, param := range params {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDynamicSecretReference requests credentials from a dynamic secrets engine, e.g. 'database/creds/$(vaultDatabaseRole)'.
+// The field of the response is defined via the param of the reference and defaults to the parameter name.
+func resolveDynamicSecretReference(ref *ResourceReference, config *StepConfig, client secretStore, param StepParameters) {
+	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
+	if _, ok := config.Config[param.Name].(string); vaultDisableOverwrite && ok {
+		log.Entry().Debugf("Not fetching '%s' from vault since it has already been set", param.Name)
+		return
+	}
+
+	var store dynamicSecretStore
+	if chain, ok := client.(secretProviderChain); ok {
+		for _, provider := range chain {
+			if dynamicStore, ok := provider.store.(dynamicSecretStore); ok {
+				store = dynamicStore
+				break
+			}
+		}
+	} else if dynamicStore, ok := client.(dynamicSecretStore); ok {
+		store = dynamicStore
+	}
+	if store == nil {
+		log.Entry().Warnf("Could not resolve param '%s' since dynamic secrets are only supported with vault", param.Name)
+		return
 	}
+
+	field := ref.Param
+	if field == "" {
+		field = param.Name
+	}
+	for _, path := range ref.Paths {
+		dynamicPath, ok := interpolation.ResolveString(path, config.Config)
+		if !ok {
+			continue
+		}
+		secret, err := store.GetDynamicSecret(dynamicPath)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Couldn't fetch dynamic secret at '%s'", dynamicPath)
+			continue
+		}
+		if value := secret[field]; value != "" {
+			log.RegisterSecret(value)
+			log.Entry().Debugf("Resolved param '%s' with dynamic secret at '%s'", param.Name, dynamicPath)
+			config.Config[param.Name] = value
+			config.recordOrigin(param.Name, ValueOrigin{Layer: LayerVault, Source: dynamicPath, Value: value})
+			return
+		}
+	}
+	log.Entry().Warnf("Could not resolve param '%s' from dynamic secrets", param.Name)
+}
+
+// secretVersion returns the version of the secret configured for a parameter via vaultSecretVersions
+func secretVersion(config *StepConfig, paramName string) int {
+	versions, ok := config.Config["vaultSecretVersions"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	switch version := versions[paramName].(type) {
+	case float64:
+		return int(version)
+	case int:
+		return version
+	case string:
+		v, _ := strconv.Atoi(version)
+		return v
+	}
+	return 0
 }
 
-func resolveVaultReference(ref *ResourceReference, config *StepConfig, client vaultClient, param StepParameters) {
+func resolveVaultReference(ref *ResourceReference, config *StepConfig, client secretStore, param StepParameters) {
 	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
 	if _, ok := config.Config[param.Name].(string); vaultDisableOverwrite && ok {
 		log.Entry().Debugf("Not fetching '%s' from vault since it has already been set", param.Name)
 		return
 	}
 
+	// references can be restricted to dedicated secret providers
+	if chain, ok := client.(secretProviderChain); ok {
+		client = chain.restrict(ref.Providers)
+	}
+
+	// a dedicated version of a secret can only be requested from vault
+	version := secretVersion(config, param.Name)
+	if chain, ok := client.(secretProviderChain); ok && version > 0 {
+		client = chain.restrict([]string{SecretProviderVault})
+	}
+
 	var secretValue *string
 	for _, vaultPath := range ref.Paths {
 		// it should be possible to configure the root path were the secret is stored
@@ -114,10 +278,14 @@ func resolveVaultReference(ref *ResourceReference, config *StepConfig, client va
 		if !ok {
 			continue
 		}
+		if version > 0 {
+			vaultPath = fmt.Sprintf("%v?version=%v", vaultPath, version)
+		}
 
-		secretValue = lookupPath(client, vaultPath, &param)
+		var provider string
+		secretValue, provider = lookupPath(client, vaultPath, &param)
 		if secretValue != nil {
-			log.Entry().Debugf("Resolved param '%s' with vault path '%s'", param.Name, vaultPath)
+			log.Entry().Debugf("Resolved param '%s' with %s path '%s'", param.Name, provider, vaultPath)
 			if ref.Type == "vaultSecret" {
 				config.Config[param.Name] = *secretValue
 			} else if ref.Type == "vaultSecretFile" {
@@ -128,6 +296,7 @@ func resolveVaultReference(ref *ResourceReference, config *StepConfig, client va
 				}
 				config.Config[param.Name] = filePath
 			}
+			config.recordOrigin(param.Name, ValueOrigin{Layer: provider, Source: vaultPath, Value: config.Config[param.Name]})
 			break
 		}
 	}
@@ -137,7 +306,7 @@ func resolveVaultReference(ref *ResourceReference, config *StepConfig, client va
 }
 
 // resolve test credential keys and expose as environment variables
-func resolveVaultTestCredentials(config *StepConfig, client vaultClient) {
+func resolveVaultTestCredentials(config *StepConfig, client secretStore) {
 	credPath, pathOk := config.Config[vaultTestCredentialPath].(string)
 	keys := getTestCredentialKeys(config)
 	if !(pathOk && keys != nil) || credPath == "" || len(keys) == 0 {
@@ -219,6 +388,15 @@ func convertEnvVar(s string) string {
 	return replacedString
 }
 
+// RevokeVaultCredentials revokes leases of dynamic secrets and the vault token once the step finished.
+// Tokens of clients which did not request dynamic secrets are already revoked after the configuration has been resolved.
+func RevokeVaultCredentials() {
+	for _, client := range vaultClientsToRevoke {
+		client.MustRevokeToken()
+	}
+	vaultClientsToRevoke = nil
+}
+
 // RemoveVaultSecretFiles removes all secret files that have been created during execution
 func RemoveVaultSecretFiles() {
 	if VaultSecretFileDirectory != "" {
@@ -247,21 +425,22 @@ func createTemporarySecretFile(namePattern string, content string) (string, erro
 	return file.Name(), nil
 }
 
-func lookupPath(client vaultClient, path string, param *StepParameters) *string {
+// lookupPath returns the value of the parameter at the given path together with the name of the secret provider
+func lookupPath(client secretStore, path string, param *StepParameters) (*string, string) {
 	log.Entry().Debugf("Trying to resolve vault parameter '%s' at '%s'", param.Name, path)
-	secret, err := client.GetKvSecret(path)
+	secret, provider, err := lookupSecret(client, path)
 	if err != nil {
 		log.Entry().WithError(err).Warnf("Couldn't fetch secret at '%s'", path)
-		return nil
+		return nil, ""
 	}
 	if secret == nil {
-		return nil
+		return nil, ""
 	}
 
 	field := secret[param.Name]
 	if field != "" {
 		log.RegisterSecret(field)
-		return &field
+		return &field, provider
 	}
 	log.Entry().Debugf("Secret did not contain a field name '%s'", param.Name)
 	// try parameter aliases
@@ -273,8 +452,8 @@ func lookupPath(client vaultClient, path string, param *StepParameters) *string
 			if alias.Deprecated {
 				log.Entry().WithField("package", "SAP/jenkins-library/pkg/config").Warningf("DEPRECATION NOTICE: old step config key '%s' used in vault. Please switch to '%s'!", alias.Name, param.Name)
 			}
-			return &field
+			return &field, provider
 		}
 	}
-	return nil
+	return nil, ""
 }
@@ -201,6 +201,128 @@ func TestMixinVault(t *testing.T) {
 
 }
 
+func TestVaultSecretVersions(t *testing.T) {
+	t.Run("Pinned version is requested", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"vaultPath":           "team1",
+			"vaultSecretVersions": map[string]interface{}{"token": float64(3)},
+		}}
+		stepParams := []StepParameters{stepParam("token", "vaultSecret", "$(vaultPath)/pipelineA"), stepParam("user", "vaultSecret", "$(vaultPath)/pipelineA")}
+		vaultMock.On("GetKvSecret", "team1/pipelineA?version=3").Return(map[string]string{"token": "oldToken"}, nil)
+		vaultMock.On("GetKvSecret", "team1/pipelineA").Return(map[string]string{"user": "latestUser"}, nil)
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+
+		assert.Equal(t, "oldToken", stepConfig.Config["token"])
+		assert.Equal(t, "latestUser", stepConfig.Config["user"])
+	})
+
+	t.Run("Pinned version is only requested from vault", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		otherProvider := &mocks.VaultMock{}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"vaultPath":           "team1",
+			"vaultSecretVersions": map[string]interface{}{"token": "2"},
+		}}
+		chain := secretProviderChain{{name: SecretProviderDirectory, store: otherProvider}, {name: SecretProviderVault, store: vaultMock}}
+		vaultMock.On("GetKvSecret", "team1/pipelineA?version=2").Return(map[string]string{"token": "oldToken"}, nil)
+
+		resolveAllVaultReferences(&stepConfig, chain, []StepParameters{stepParam("token", "vaultSecret", "$(vaultPath)/pipelineA")})
+
+		assert.Equal(t, "oldToken", stepConfig.Config["token"])
+		otherProvider.AssertNotCalled(t, "GetKvSecret", mock.Anything)
+	})
+}
+
+func TestVaultDynamicSecrets(t *testing.T) {
+	dynamicParam := func(name, field string, paths ...string) StepParameters {
+		param := stepParam(name, "vaultDynamicSecret", paths...)
+		param.ResourceRef[0].Param = field
+		return param
+	}
+
+	t.Run("Credentials are resolved from dynamic secrets engine", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		stepConfig := StepConfig{Config: map[string]interface{}{"dbRole": "readonly"}, Provenance: map[string][]ValueOrigin{}}
+		stepParams := []StepParameters{
+			dynamicParam("dbUser", "username", "database/creds/$(dbRole)"),
+			dynamicParam("dbPassword", "password", "database/creds/$(dbRole)"),
+		}
+		vaultMock.On("GetDynamicSecret", "database/creds/readonly").Return(map[string]string{"username": "v-readonly", "password": "secret"}, nil)
+
+		resolveAllVaultReferences(&stepConfig, secretProviderChain{{name: SecretProviderVault, store: vaultMock}}, stepParams)
+
+		assert.Equal(t, "v-readonly", stepConfig.Config["dbUser"])
+		assert.Equal(t, "secret", stepConfig.Config["dbPassword"])
+		assert.Equal(t, []ValueOrigin{{Layer: LayerVault, Source: "database/creds/readonly", Value: "secret"}}, stepConfig.Provenance["dbPassword"])
+		assert.True(t, hasDynamicSecretReferences(stepParams))
+	})
+
+	t.Run("Field defaults to parameter name", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		stepConfig := StepConfig{Config: map[string]interface{}{}}
+		vaultMock.On("GetDynamicSecret", "aws/creds/deploy").Return(map[string]string{"access_key": "AKIA"}, nil)
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, []StepParameters{dynamicParam("access_key", "", "aws/creds/deploy")})
+
+		assert.Equal(t, "AKIA", stepConfig.Config["access_key"])
+	})
+
+	t.Run("Dynamic secrets require vault", func(t *testing.T) {
+		stepConfig := StepConfig{Config: map[string]interface{}{}}
+
+		resolveAllVaultReferences(&stepConfig, secretProviderChain{{name: SecretProviderDirectory, store: &directorySecretStore{root: "."}}}, []StepParameters{dynamicParam("dbUser", "username", "database/creds/readonly")})
+
+		assert.Nil(t, stepConfig.Config["dbUser"])
+	})
+
+	t.Run("Dynamic secrets requested via configuration", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"dbRole": "readonly",
+			"vaultDynamicSecrets": map[string]interface{}{
+				"dbUser":     map[string]interface{}{"path": "database/creds/$(dbRole)", "field": "username"},
+				"password":   map[string]interface{}{"path": "database/creds/$(dbRole)"},
+				"unknown":    map[string]interface{}{"path": "database/creds/$(dbRole)"},
+				"dbPassword": map[string]interface{}{"field": "password"},
+			},
+		}}
+		stepParams := []StepParameters{{Name: "dbUser"}, {Name: "password"}, {Name: "dbPassword"}}
+		vaultMock.On("GetDynamicSecret", "database/creds/readonly").Return(map[string]string{"username": "v-readonly", "password": "secret"}, nil)
+
+		dynamicParams := dynamicSecretParameters(&stepConfig, stepParams)
+		resolveAllVaultReferences(&stepConfig, vaultMock, dynamicParams)
+
+		assert.Equal(t, []StepParameters{
+			{Name: "dbUser", Type: "string", ResourceRef: []ResourceReference{{Type: "vaultDynamicSecret", Param: "username", Paths: []string{"database/creds/$(dbRole)"}}}},
+			{Name: "password", Type: "string", ResourceRef: []ResourceReference{{Type: "vaultDynamicSecret", Paths: []string{"database/creds/$(dbRole)"}}}},
+		}, dynamicParams)
+		assert.True(t, hasDynamicSecretReferences(dynamicParams))
+		assert.Equal(t, "v-readonly", stepConfig.Config["dbUser"])
+		assert.Equal(t, "secret", stepConfig.Config["password"])
+		assert.Nil(t, stepConfig.Config["dbPassword"])
+	})
+
+	t.Run("Invalid configuration of dynamic secrets", func(t *testing.T) {
+		stepConfig := StepConfig{Config: map[string]interface{}{"vaultDynamicSecrets": "database/creds/readonly"}}
+
+		assert.Empty(t, dynamicSecretParameters(&stepConfig, []StepParameters{{Name: "dbUser"}}))
+		assert.Empty(t, dynamicSecretParameters(&StepConfig{Config: map[string]interface{}{}}, []StepParameters{{Name: "dbUser"}}))
+	})
+
+	t.Run("Token is revoked once the step finished", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		vaultMock.On("MustRevokeToken").Return()
+		vaultClientsToRevoke = []vaultClient{vaultMock}
+
+		RevokeVaultCredentials()
+
+		vaultMock.AssertCalled(t, "MustRevokeToken")
+		assert.Empty(t, vaultClientsToRevoke)
+	})
+}
+
 func stepParam(name string, refType string, refPaths ...string) StepParameters {
 	return StepParameters{
 		Name:    name,
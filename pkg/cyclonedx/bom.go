@@ -0,0 +1,360 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// SpecVersion is the version of the CycloneDX specification of the BOMs written by piper
+const SpecVersion = "1.4"
+
+// Component types as defined by the CycloneDX specification
+const (
+	ComponentTypeApplication = "application"
+	ComponentTypeContainer   = "container"
+	ComponentTypeLibrary     = "library"
+)
+
+// BOM is a CycloneDX bill of materials which can be read from and written to its JSON as well as XML representation
+type BOM struct {
+	XMLName      xml.Name     `json:"-" xml:"bom"`
+	XMLNS        string       `json:"-" xml:"xmlns,attr"`
+	BOMFormat    string       `json:"bomFormat" xml:"-"`
+	SpecVersion  string       `json:"specVersion" xml:"-"`
+	SerialNumber string       `json:"serialNumber,omitempty" xml:"serialNumber,attr,omitempty"`
+	Version      int          `json:"version" xml:"version,attr"`
+	Metadata     *Metadata    `json:"metadata,omitempty" xml:"metadata,omitempty"`
+	Components   []Component  `json:"components,omitempty" xml:"components>component,omitempty"`
+	Dependencies []Dependency `json:"dependencies,omitempty" xml:"dependencies>dependency,omitempty"`
+}
+
+// Metadata describes the BOM and the component it is created for
+type Metadata struct {
+	Timestamp string     `json:"timestamp,omitempty" xml:"timestamp,omitempty"`
+	Tools     Tools      `json:"tools,omitempty" xml:"tools>tool,omitempty"`
+	Component *Component `json:"component,omitempty" xml:"component,omitempty"`
+}
+
+// Tool is a tool used to create the BOM
+type Tool struct {
+	Vendor  string `json:"vendor,omitempty" xml:"vendor,omitempty"`
+	Name    string `json:"name" xml:"name"`
+	Version string `json:"version,omitempty" xml:"version,omitempty"`
+}
+
+// Tools are the tools used to create the BOM
+type Tools []Tool
+
+// UnmarshalJSON supports the tools as list as well as the object introduced with CycloneDX 1.5
+func (t *Tools) UnmarshalJSON(data []byte) error {
+	tools := []Tool{}
+	if err := json.Unmarshal(data, &tools); err == nil {
+		*t = tools
+		return nil
+	}
+	toolsObject := struct {
+		Components []Tool `json:"components"`
+	}{}
+	if err := json.Unmarshal(data, &toolsObject); err != nil {
+		return err
+	}
+	*t = toolsObject.Components
+	return nil
+}
+
+// Component is a software component contained in the BOM
+type Component struct {
+	Type       string      `json:"type" xml:"type,attr"`
+	BOMRef     string      `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Group      string      `json:"group,omitempty" xml:"group,omitempty"`
+	Name       string      `json:"name" xml:"name"`
+	Version    string      `json:"version,omitempty" xml:"version,omitempty"`
+	Hashes     []Hash      `json:"hashes,omitempty" xml:"hashes>hash,omitempty"`
+	Licenses   Licenses    `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	PURL       string      `json:"purl,omitempty" xml:"purl,omitempty"`
+	Components []Component `json:"components,omitempty" xml:"components>component,omitempty"`
+}
+
+// Hash is a hash of a component
+type Hash struct {
+	Algorithm string `json:"alg" xml:"alg,attr"`
+	Value     string `json:"content" xml:",chardata"`
+}
+
+// License is a license identified by its SPDX id or by its name
+type License struct {
+	ID   string `json:"id,omitempty" xml:"id,omitempty"`
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+	URL  string `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+// LicenseChoice is either a license or an SPDX license expression
+type LicenseChoice struct {
+	License    *License `json:"license,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+}
+
+// Licenses are the licenses of a component
+type Licenses []LicenseChoice
+
+// MarshalXML writes the licenses as sequence of license and expression elements
+func (l Licenses) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, choice := range l {
+		if choice.License != nil {
+			if err := e.EncodeElement(choice.License, xml.StartElement{Name: xml.Name{Local: "license"}}); err != nil {
+				return err
+			}
+		} else if len(choice.Expression) > 0 {
+			if err := e.EncodeElement(choice.Expression, xml.StartElement{Name: xml.Name{Local: "expression"}}); err != nil {
+				return err
+			}
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML reads the sequence of license and expression elements
+func (l *Licenses) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	licenses := struct {
+		Licenses    []License `xml:"license"`
+		Expressions []string  `xml:"expression"`
+	}{}
+	if err := d.DecodeElement(&licenses, &start); err != nil {
+		return err
+	}
+	for i := range licenses.Licenses {
+		*l = append(*l, LicenseChoice{License: &licenses.Licenses[i]})
+	}
+	for _, expression := range licenses.Expressions {
+		*l = append(*l, LicenseChoice{Expression: strings.TrimSpace(expression)})
+	}
+	return nil
+}
+
+// Dependency lists the components a component directly depends on
+type Dependency struct {
+	Ref       string   `json:"ref" xml:"ref,attr"`
+	DependsOn []string `json:"dependsOn,omitempty" xml:"-"`
+	// DependsOnXML is the XML representation of DependsOn
+	DependsOnXML []dependencyRef `json:"-" xml:"dependency,omitempty"`
+}
+
+type dependencyRef struct {
+	Ref string `xml:"ref,attr"`
+}
+
+// key identifies a component across BOMs
+func (c *Component) key() string {
+	if len(c.PURL) > 0 {
+		return strings.ToLower(c.PURL)
+	}
+	if len(c.BOMRef) > 0 {
+		return c.BOMRef
+	}
+	return strings.ToLower(fmt.Sprintf("%v/%v@%v", c.Group, c.Name, c.Version))
+}
+
+// ReadBOM parses a BOM in its JSON or XML representation
+func ReadBOM(content []byte) (*BOM, error) {
+	bom := &BOM{}
+	trimmed := bytes.TrimSpace(content)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, bom); err != nil {
+			return nil, errors.Wrap(err, "failed to parse BOM in JSON format")
+		}
+		return bom, nil
+	}
+	if err := xml.Unmarshal(trimmed, bom); err != nil {
+		return nil, errors.Wrap(err, "failed to parse BOM in XML format")
+	}
+	bom.BOMFormat = "CycloneDX"
+	bom.SpecVersion = strings.TrimPrefix(bom.XMLNS, "http://cyclonedx.org/schema/bom/")
+	for i := range bom.Dependencies {
+		for _, ref := range bom.Dependencies[i].DependsOnXML {
+			bom.Dependencies[i].DependsOn = append(bom.Dependencies[i].DependsOn, ref.Ref)
+		}
+		bom.Dependencies[i].DependsOnXML = nil
+	}
+	return bom, nil
+}
+
+// JSON returns the JSON representation of the BOM
+func (b *BOM) JSON() ([]byte, error) {
+	bom := *b
+	bom.BOMFormat = "CycloneDX"
+	bom.SpecVersion = SpecVersion
+	return json.MarshalIndent(bom, "", "  ")
+}
+
+// XML returns the XML representation of the BOM
+func (b *BOM) XML() ([]byte, error) {
+	bom := *b
+	bom.XMLNS = "http://cyclonedx.org/schema/bom/" + SpecVersion
+	bom.Dependencies = make([]Dependency, len(b.Dependencies))
+	for i, dependency := range b.Dependencies {
+		bom.Dependencies[i] = Dependency{Ref: dependency.Ref}
+		for _, ref := range dependency.DependsOn {
+			bom.Dependencies[i].DependsOnXML = append(bom.Dependencies[i].DependsOnXML, dependencyRef{Ref: ref})
+		}
+	}
+	content, err := xml.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// now allows to mock the timestamp of BOMs in tests
+var now = time.Now
+
+// NewBOM creates an empty BOM for the given component
+func NewBOM(component *Component) *BOM {
+	return &BOM{
+		SerialNumber: "urn:uuid:" + uuid.New().String(),
+		Version:      1,
+		Metadata: &Metadata{
+			Timestamp: now().UTC().Format(time.RFC3339),
+			Tools:     Tools{{Vendor: "SAP", Name: "piper"}},
+			Component: component,
+		},
+	}
+}
+
+// Merge combines several BOMs, e.g. of the modules of a multi-module project, into one BOM for the given component.
+// Components contained in several BOMs are only listed once, the components the BOMs were created for become
+// components of the merged BOM and direct dependencies of the given component.
+func Merge(component *Component, boms ...*BOM) *BOM {
+	merged := NewBOM(component)
+	components := map[string]int{}
+	dependencies := map[string]int{}
+
+	addComponent := func(c Component) {
+		if index, ok := components[c.key()]; ok {
+			if len(merged.Components[index].Licenses) == 0 {
+				merged.Components[index].Licenses = c.Licenses
+			}
+			return
+		}
+		components[c.key()] = len(merged.Components)
+		merged.Components = append(merged.Components, c)
+	}
+	addDependency := func(ref string, dependsOn ...string) {
+		index, ok := dependencies[ref]
+		if !ok {
+			index = len(merged.Dependencies)
+			dependencies[ref] = index
+			merged.Dependencies = append(merged.Dependencies, Dependency{Ref: ref})
+		}
+		for _, dependency := range dependsOn {
+			if !contains(merged.Dependencies[index].DependsOn, dependency) {
+				merged.Dependencies[index].DependsOn = append(merged.Dependencies[index].DependsOn, dependency)
+			}
+		}
+	}
+
+	rootRef := ""
+	if component != nil {
+		rootRef = component.BOMRef
+	}
+	for _, bom := range boms {
+		if bom == nil {
+			continue
+		}
+		if bom.Metadata != nil && bom.Metadata.Component != nil {
+			moduleComponent := *bom.Metadata.Component
+			moduleComponent.Components = nil
+			if component == nil || moduleComponent.key() != component.key() {
+				addComponent(moduleComponent)
+				if len(rootRef) > 0 && len(moduleComponent.BOMRef) > 0 {
+					addDependency(rootRef, moduleComponent.BOMRef)
+				}
+			}
+		}
+		for _, c := range bom.Components {
+			addComponent(c)
+		}
+		for _, dependency := range bom.Dependencies {
+			addDependency(dependency.Ref, dependency.DependsOn...)
+		}
+	}
+	return merged
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// FileUtils defines the file system functions required for reading and writing BOMs
+type FileUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// ReadBOMFile reads a BOM in JSON or XML format
+func ReadBOMFile(path string, utils FileUtils) (*BOM, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read BOM '%v'", path)
+	}
+	bom, err := ReadBOM(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid BOM '%v'", path)
+	}
+	return bom, nil
+}
+
+// JSONPath returns the path of the JSON representation of a BOM in XML format, e.g. bom.json for bom.xml
+func JSONPath(xmlPath string) string {
+	return strings.TrimSuffix(xmlPath, ".xml") + ".json"
+}
+
+// WriteBOM writes the BOM in XML format to the given path and in JSON format next to it
+func WriteBOM(bom *BOM, xmlPath string, utils FileUtils) error {
+	xmlContent, err := bom.XML()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal BOM to XML")
+	}
+	if err := utils.FileWrite(xmlPath, xmlContent, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write BOM '%v'", xmlPath)
+	}
+	jsonContent, err := bom.JSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal BOM to JSON")
+	}
+	if err := utils.FileWrite(JSONPath(xmlPath), jsonContent, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write BOM '%v'", JSONPath(xmlPath))
+	}
+	return nil
+}
+
+// ConvertToJSON writes the JSON representation of a BOM in XML format next to it
+func ConvertToJSON(xmlPath string, utils FileUtils) error {
+	bom, err := ReadBOMFile(xmlPath, utils)
+	if err != nil {
+		return err
+	}
+	content, err := bom.JSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal BOM to JSON")
+	}
+	if err := utils.FileWrite(JSONPath(xmlPath), content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write BOM '%v'", JSONPath(xmlPath))
+	}
+	return nil
+}
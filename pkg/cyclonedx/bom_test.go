@@ -0,0 +1,207 @@
+package cyclonedx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMavenBOM = `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" serialNumber="urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79" version="1">
+  <metadata>
+    <timestamp>2022-01-01T10:00:00Z</timestamp>
+    <tools>
+      <tool>
+        <vendor>OWASP Foundation</vendor>
+        <name>CycloneDX Maven plugin</name>
+        <version>2.7.1</version>
+      </tool>
+    </tools>
+    <component type="library" bom-ref="pkg:maven/com.sap/module-a@1.0.0?type=jar">
+      <group>com.sap</group>
+      <name>module-a</name>
+      <version>1.0.0</version>
+      <purl>pkg:maven/com.sap/module-a@1.0.0?type=jar</purl>
+    </component>
+  </metadata>
+  <components>
+    <component type="library" bom-ref="pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1?type=jar">
+      <group>org.apache.logging.log4j</group>
+      <name>log4j-core</name>
+      <version>2.17.1</version>
+      <hashes>
+        <hash alg="SHA-1">779f60f3844dadc3ef597976fcb1e5127b1f343d</hash>
+      </hashes>
+      <licenses>
+        <license>
+          <id>Apache-2.0</id>
+        </license>
+      </licenses>
+      <purl>pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1?type=jar</purl>
+    </component>
+    <component type="library" bom-ref="pkg:maven/javax.mail/mail@1.4.7?type=jar">
+      <group>javax.mail</group>
+      <name>mail</name>
+      <version>1.4.7</version>
+      <licenses>
+        <expression>CDDL-1.0 OR GPL-2.0-with-classpath-exception</expression>
+      </licenses>
+      <purl>pkg:maven/javax.mail/mail@1.4.7?type=jar</purl>
+    </component>
+  </components>
+  <dependencies>
+    <dependency ref="pkg:maven/com.sap/module-a@1.0.0?type=jar">
+      <dependency ref="pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1?type=jar"/>
+      <dependency ref="pkg:maven/javax.mail/mail@1.4.7?type=jar"/>
+    </dependency>
+  </dependencies>
+</bom>`
+
+const testNpmBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "metadata": {
+    "tools": {"components": [{"type": "application", "name": "cyclonedx-npm"}]},
+    "component": {"type": "application", "bom-ref": "pkg:npm/ui@1.0.0", "name": "ui", "version": "1.0.0", "purl": "pkg:npm/ui@1.0.0"}
+  },
+  "components": [
+    {"type": "library", "bom-ref": "pkg:npm/lodash@4.17.21", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"},
+    {"type": "library", "bom-ref": "pkg:maven/javax.mail/mail@1.4.7?type=jar", "group": "javax.mail", "name": "mail", "version": "1.4.7", "purl": "pkg:maven/javax.mail/mail@1.4.7?type=jar"}
+  ],
+  "dependencies": [
+    {"ref": "pkg:npm/ui@1.0.0", "dependsOn": ["pkg:npm/lodash@4.17.21"]}
+  ]
+}`
+
+func TestReadBOM(t *testing.T) {
+	t.Run("XML", func(t *testing.T) {
+		bom, err := ReadBOM([]byte(testMavenBOM))
+		require.NoError(t, err)
+
+		assert.Equal(t, "CycloneDX", bom.BOMFormat)
+		assert.Equal(t, "1.4", bom.SpecVersion)
+		assert.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", bom.SerialNumber)
+		require.NotNil(t, bom.Metadata)
+		assert.Equal(t, Tools{{Vendor: "OWASP Foundation", Name: "CycloneDX Maven plugin", Version: "2.7.1"}}, bom.Metadata.Tools)
+		require.NotNil(t, bom.Metadata.Component)
+		assert.Equal(t, "module-a", bom.Metadata.Component.Name)
+
+		require.Len(t, bom.Components, 2)
+		assert.Equal(t, "log4j-core", bom.Components[0].Name)
+		assert.Equal(t, []Hash{{Algorithm: "SHA-1", Value: "779f60f3844dadc3ef597976fcb1e5127b1f343d"}}, bom.Components[0].Hashes)
+		assert.Equal(t, Licenses{{License: &License{ID: "Apache-2.0"}}}, bom.Components[0].Licenses)
+		assert.Equal(t, Licenses{{Expression: "CDDL-1.0 OR GPL-2.0-with-classpath-exception"}}, bom.Components[1].Licenses)
+
+		require.Len(t, bom.Dependencies, 1)
+		assert.Equal(t, "pkg:maven/com.sap/module-a@1.0.0?type=jar", bom.Dependencies[0].Ref)
+		assert.Equal(t, []string{"pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1?type=jar", "pkg:maven/javax.mail/mail@1.4.7?type=jar"}, bom.Dependencies[0].DependsOn)
+		assert.Empty(t, bom.Dependencies[0].DependsOnXML)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bom, err := ReadBOM([]byte(testNpmBOM))
+		require.NoError(t, err)
+
+		assert.Equal(t, "1.5", bom.SpecVersion)
+		assert.Equal(t, Tools{{Name: "cyclonedx-npm"}}, bom.Metadata.Tools)
+		assert.Len(t, bom.Components, 2)
+		assert.Equal(t, []string{"pkg:npm/lodash@4.17.21"}, bom.Dependencies[0].DependsOn)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ReadBOM([]byte("{ invalid"))
+		assert.EqualError(t, err, "failed to parse BOM in JSON format: invalid character 'i' looking for beginning of object key string")
+
+		_, err = ReadBOM([]byte("<bom"))
+		assert.Contains(t, err.Error(), "failed to parse BOM in XML format")
+	})
+}
+
+func TestBOMRoundtrip(t *testing.T) {
+	bom, err := ReadBOM([]byte(testMavenBOM))
+	require.NoError(t, err)
+
+	xmlContent, err := bom.XML()
+	require.NoError(t, err)
+	assert.Contains(t, string(xmlContent), `<bom xmlns="http://cyclonedx.org/schema/bom/1.4"`)
+	fromXML, err := ReadBOM(xmlContent)
+	require.NoError(t, err)
+	assert.Equal(t, bom.Components, fromXML.Components)
+	assert.Equal(t, bom.Dependencies, fromXML.Dependencies)
+
+	jsonContent, err := bom.JSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonContent), `"specVersion": "1.4"`)
+	fromJSON, err := ReadBOM(jsonContent)
+	require.NoError(t, err)
+	assert.Equal(t, bom.Components, fromJSON.Components)
+	assert.Equal(t, bom.Dependencies, fromJSON.Dependencies)
+}
+
+func TestMerge(t *testing.T) {
+	now = func() time.Time { return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	mavenBOM, err := ReadBOM([]byte(testMavenBOM))
+	require.NoError(t, err)
+	npmBOM, err := ReadBOM([]byte(testNpmBOM))
+	require.NoError(t, err)
+
+	mta := Component{Type: ComponentTypeApplication, BOMRef: "mta-app@1.0.0", Name: "mta-app", Version: "1.0.0"}
+	merged := Merge(&mta, mavenBOM, nil, npmBOM)
+
+	assert.Equal(t, "2022-01-02T03:04:05Z", merged.Metadata.Timestamp)
+	assert.Equal(t, &mta, merged.Metadata.Component)
+
+	names := []string{}
+	for _, component := range merged.Components {
+		names = append(names, component.Name)
+	}
+	assert.Equal(t, []string{"module-a", "log4j-core", "mail", "ui", "lodash"}, names)
+	assert.Equal(t, Licenses{{Expression: "CDDL-1.0 OR GPL-2.0-with-classpath-exception"}}, merged.Components[2].Licenses)
+
+	require.Len(t, merged.Dependencies, 3)
+	assert.Equal(t, Dependency{Ref: "mta-app@1.0.0", DependsOn: []string{"pkg:maven/com.sap/module-a@1.0.0?type=jar", "pkg:npm/ui@1.0.0"}}, merged.Dependencies[0])
+	assert.Equal(t, "pkg:maven/com.sap/module-a@1.0.0?type=jar", merged.Dependencies[1].Ref)
+	assert.Equal(t, Dependency{Ref: "pkg:npm/ui@1.0.0", DependsOn: []string{"pkg:npm/lodash@4.17.21"}}, merged.Dependencies[2])
+}
+
+func TestWriteBOM(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		bom := NewBOM(&Component{Type: ComponentTypeApplication, Name: "app"})
+
+		require.NoError(t, WriteBOM(bom, "bom.xml", utils))
+
+		assert.True(t, utils.HasWrittenFile("bom.xml"))
+		assert.True(t, utils.HasWrittenFile("bom.json"))
+		written, err := ReadBOMFile("bom.json", utils)
+		require.NoError(t, err)
+		assert.Equal(t, bom.SerialNumber, written.SerialNumber)
+	})
+
+	t.Run("convert to JSON", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("target/bom.xml", []byte(testMavenBOM))
+
+		require.NoError(t, ConvertToJSON("target/bom.xml", utils))
+
+		bom, err := ReadBOMFile("target/bom.json", utils)
+		require.NoError(t, err)
+		assert.Len(t, bom.Components, 2)
+	})
+
+	t.Run("missing BOM", func(t *testing.T) {
+		err := ConvertToJSON("bom.xml", &mock.FilesMock{})
+		assert.EqualError(t, err, "failed to read BOM 'bom.xml': could not read 'bom.xml'")
+	})
+}
+
+func TestJSONPath(t *testing.T) {
+	assert.Equal(t, "target/bom.json", JSONPath("target/bom.xml"))
+	assert.Equal(t, "bom-docker.json", JSONPath("bom-docker.xml"))
+}
@@ -0,0 +1,154 @@
+package cyclonedx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// PackageURL creates the package URL (purl) identifying a component, see https://github.com/package-url/purl-spec
+func PackageURL(packageType, namespace, name, version string) string {
+	purl := "pkg:" + packageType + "/"
+	if len(namespace) > 0 {
+		purl += namespace + "/"
+	}
+	purl += name
+	if len(version) > 0 {
+		purl += "@" + version
+	}
+	return purl
+}
+
+// newComponent creates a component which is referenced by its package URL
+func newComponent(componentType, packageType, group, name, version string) Component {
+	purl := PackageURL(packageType, group, name, version)
+	return Component{Type: componentType, BOMRef: purl, Group: group, Name: name, Version: version, PURL: purl}
+}
+
+// goModule is a module as listed by "go list -m -json all"
+type goModule struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Replace  *goModule
+}
+
+// FromGoModuleList creates the BOM of a Go module based on the output of "go list -m -json all" which contains the complete module graph.
+// All modules of the build list are contained as components, only direct requirements are dependencies of the main module.
+// Replacements are applied by go already, modules replaced by a local directory are contained without version.
+func FromGoModuleList(goList []byte, version string) (*BOM, error) {
+	var main *goModule
+	components := []Component{}
+	direct := []string{}
+
+	decoder := json.NewDecoder(bytes.NewReader(goList))
+	for {
+		module := goModule{}
+		if err := decoder.Decode(&module); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to parse module list")
+		}
+		if module.Main {
+			if main == nil {
+				main = &module
+			}
+			continue
+		}
+		path, moduleVersion := module.Path, module.Version
+		if module.Replace != nil && len(module.Replace.Version) > 0 {
+			path, moduleVersion = module.Replace.Path, module.Replace.Version
+		} else if module.Replace != nil {
+			// replacement by a local directory
+			moduleVersion = ""
+		}
+		component := newComponent(ComponentTypeLibrary, "golang", "", path, moduleVersion)
+		components = append(components, component)
+		if !module.Indirect {
+			direct = append(direct, component.BOMRef)
+		}
+	}
+	if main == nil {
+		return nil, fmt.Errorf("no main module found in module list")
+	}
+
+	component := newComponent(ComponentTypeApplication, "golang", "", main.Path, version)
+	bom := NewBOM(&component)
+	bom.Components = components
+	bom.Dependencies = []Dependency{{Ref: component.BOMRef, DependsOn: direct}}
+	return bom, nil
+}
+
+var pipRequirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*(===|==|~=|>=|<=|>|<|!=)?\s*([^\s;,]*)`)
+var pipNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePipName normalizes a Python package name according to PEP 503
+func normalizePipName(name string) string {
+	return strings.ToLower(pipNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// FromPipFreeze creates the BOM of a Python project based on the packages installed in its environment as listed by "pip freeze".
+// All installed packages are contained as components, the requirements of the requirements file are the dependencies of the project.
+// Packages installed from a URL are contained without version, editable installs are skipped.
+func FromPipFreeze(freeze, requirementsTxt []byte, name, version string) (*BOM, error) {
+	components := []Component{}
+	installed := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(freeze))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		match := pipRequirement.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		packageVersion := ""
+		if match[3] == "==" || match[3] == "===" {
+			packageVersion = match[4]
+		}
+		packageName := normalizePipName(match[1])
+		component := newComponent(ComponentTypeLibrary, "pypi", "", packageName, packageVersion)
+		components = append(components, component)
+		installed[packageName] = component.BOMRef
+	}
+
+	direct := []string{}
+	scanner = bufio.NewScanner(bytes.NewReader(requirementsTxt))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		// references to other files and editable installs are skipped
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		match := pipRequirement.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		ref, ok := installed[normalizePipName(match[1])]
+		if !ok {
+			// e.g. due to an environment marker
+			log.Entry().Warnf("Requirement '%v' is not installed, it is not contained in the BOM", line)
+			continue
+		}
+		direct = append(direct, ref)
+	}
+
+	component := newComponent(ComponentTypeApplication, "pypi", "", normalizePipName(name), version)
+	bom := NewBOM(&component)
+	bom.Components = components
+	bom.Dependencies = []Dependency{{Ref: component.BOMRef, DependsOn: direct}}
+	return bom, nil
+}
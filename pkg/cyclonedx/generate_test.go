@@ -0,0 +1,128 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageURL(t *testing.T) {
+	assert.Equal(t, "pkg:maven/com.sap/app@1.0.0", PackageURL("maven", "com.sap", "app", "1.0.0"))
+	assert.Equal(t, "pkg:pypi/requests", PackageURL("pypi", "", "requests", ""))
+}
+
+func TestFromGoModuleList(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		goList := `{
+	"Path": "github.com/SAP/jenkins-library",
+	"Main": true,
+	"Dir": "/src/jenkins-library",
+	"GoMod": "/src/jenkins-library/go.mod"
+}
+{
+	"Path": "github.com/pkg/errors",
+	"Version": "v0.9.1"
+}
+{
+	"Path": "github.com/google/uuid",
+	"Version": "v1.3.0",
+	"Replace": {
+		"Path": "../uuid",
+		"Dir": "/src/uuid"
+	}
+}
+{
+	"Path": "github.com/stretchr/testify",
+	"Version": "v1.7.0",
+	"Replace": {
+		"Path": "github.com/stretchr/testify",
+		"Version": "v1.7.1"
+	}
+}
+{
+	"Path": "gopkg.in/yaml.v3",
+	"Version": "v3.0.0-20210107192922-496545a6307b",
+	"Indirect": true
+}
+`
+		bom, err := FromGoModuleList([]byte(goList), "1.2.3")
+		require.NoError(t, err)
+
+		assert.Equal(t, "pkg:golang/github.com/SAP/jenkins-library@1.2.3", bom.Metadata.Component.PURL)
+		refs := []string{}
+		for _, component := range bom.Components {
+			refs = append(refs, component.BOMRef)
+		}
+		assert.Equal(t, []string{
+			"pkg:golang/github.com/pkg/errors@v0.9.1",
+			"pkg:golang/github.com/google/uuid",
+			"pkg:golang/github.com/stretchr/testify@v1.7.1",
+			"pkg:golang/gopkg.in/yaml.v3@v3.0.0-20210107192922-496545a6307b",
+		}, refs)
+		assert.Equal(t, []Dependency{{
+			Ref:       "pkg:golang/github.com/SAP/jenkins-library@1.2.3",
+			DependsOn: []string{"pkg:golang/github.com/pkg/errors@v0.9.1", "pkg:golang/github.com/google/uuid", "pkg:golang/github.com/stretchr/testify@v1.7.1"},
+		}}, bom.Dependencies)
+	})
+
+	t.Run("no main module", func(t *testing.T) {
+		_, err := FromGoModuleList([]byte(`{"Path": "github.com/pkg/errors", "Version": "v0.9.1"}`), "1.2.3")
+		assert.EqualError(t, err, "no main module found in module list")
+	})
+
+	t.Run("invalid module list", func(t *testing.T) {
+		_, err := FromGoModuleList([]byte("go: updates to go.mod needed"), "1.2.3")
+		assert.Contains(t, err.Error(), "failed to parse module list")
+	})
+}
+
+func TestFromPipFreeze(t *testing.T) {
+	freeze := `-e git+https://github.com/org/project.git@1a2b3c#egg=project
+certifi==2021.10.8
+Flask==2.0.1
+requests==2.26.0
+zope.interface==5.4.0
+Django-REST-framework==3.12.4
+mylib @ file:///tmp/mylib
+`
+	requirements := `# production dependencies
+-r base.txt
+-e git+https://github.com/org/project.git#egg=project
+Flask==2.0.1
+requests[security] >= 2.25
+zope.interface===5.4.0 ; python_version >= "3.6"
+Django_REST_framework==3.12.4  # api
+pywin32==302 ; sys_platform == "win32"
+`
+	logBuffer := new(bytes.Buffer)
+	logOutput := log.Entry().Logger.Out
+	log.Entry().Logger.Out = logBuffer
+	defer func() { log.Entry().Logger.Out = logOutput }()
+
+	bom, err := FromPipFreeze([]byte(freeze), []byte(requirements), "My_App", "1.0.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "pkg:pypi/my-app@1.0.0", bom.Metadata.Component.PURL)
+	refs := []string{}
+	for _, component := range bom.Components {
+		refs = append(refs, component.PURL)
+	}
+	assert.Equal(t, []string{
+		"pkg:pypi/certifi@2021.10.8",
+		"pkg:pypi/flask@2.0.1",
+		"pkg:pypi/requests@2.26.0",
+		"pkg:pypi/zope-interface@5.4.0",
+		"pkg:pypi/django-rest-framework@3.12.4",
+		"pkg:pypi/mylib",
+	}, refs)
+	assert.Equal(t, []string{
+		"pkg:pypi/flask@2.0.1",
+		"pkg:pypi/requests@2.26.0",
+		"pkg:pypi/zope-interface@5.4.0",
+		"pkg:pypi/django-rest-framework@3.12.4",
+	}, bom.Dependencies[0].DependsOn)
+	assert.Contains(t, logBuffer.String(), `Requirement 'pywin32==302 ; sys_platform == \"win32\"' is not installed`)
+}
@@ -0,0 +1,275 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SarifSchema defines the JSON schema of SARIF logs in version 2.1.0
+const SarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// SarifVersion defines the SARIF version
+const SarifVersion = "2.1.0"
+
+// Levels of SARIF results
+const (
+	SarifLevelError   = "error"
+	SarifLevelWarning = "warning"
+	SarifLevelNote    = "note"
+	SarifLevelNone    = "none"
+)
+
+// SARIF defines a static analysis results interchange format (SARIF) log, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type SARIF struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Runs `json:"runs"`
+}
+
+// Runs defines the results of a single invocation of an analysis tool
+type Runs struct {
+	Tool    Tool      `json:"tool"`
+	Results []Results `json:"results"`
+}
+
+// Tool defines the analysis tool which produced the results of a run
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver defines the tool descriptor including the metadata of the rules
+type Driver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule defines the metadata of a rule, e.g. a query or a vulnerability
+type SarifRule struct {
+	ID                   string                `json:"id"`
+	Name                 string                `json:"name,omitempty"`
+	ShortDescription     *Message              `json:"shortDescription,omitempty"`
+	FullDescription      *Message              `json:"fullDescription,omitempty"`
+	Help                 *Message              `json:"help,omitempty"`
+	HelpURI              string                `json:"helpUri,omitempty"`
+	DefaultConfiguration *DefaultConfiguration `json:"defaultConfiguration,omitempty"`
+	Properties           *SarifRuleProperties  `json:"properties,omitempty"`
+}
+
+// DefaultConfiguration defines the default level of the results of a rule
+type DefaultConfiguration struct {
+	Level string `json:"level,omitempty"`
+}
+
+// SarifRuleProperties defines additional properties of a rule
+// security-severity is evaluated e.g. by GitHub code scanning in order to rate security findings
+type SarifRuleProperties struct {
+	Tags             []string `json:"tags,omitempty"`
+	Precision        string   `json:"precision,omitempty"`
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+}
+
+// Results defines a single finding
+type Results struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level,omitempty"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Suppressions        []Suppression     `json:"suppressions,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+// Message defines a text with an optional markdown representation
+type Message struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// Location defines the location of a finding
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation defines the file and region of a finding
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation defines the file of a finding
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region defines the region within a file, lines and columns start with 1
+type Region struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// Suppression defines that a finding was e.g. audited as not being an issue
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// NewSARIF creates a SARIF log containing the given runs
+func NewSARIF(runs ...Runs) SARIF {
+	if runs == nil {
+		runs = []Runs{}
+	}
+	return SARIF{Schema: SarifSchema, Version: SarifVersion, Runs: runs}
+}
+
+// NewRun creates a run for the tool with the given name
+func NewRun(toolName, toolVersion, informationURI string) Runs {
+	return Runs{
+		Tool:    Tool{Driver: Driver{Name: toolName, Version: toolVersion, InformationURI: informationURI, Rules: []SarifRule{}}},
+		Results: []Results{},
+	}
+}
+
+// AddRule adds the rule to the tool descriptor of the run in case no rule with the same id exists and returns the index of the rule
+func (r *Runs) AddRule(rule SarifRule) int {
+	if index := r.ruleIndex(rule.ID); index >= 0 {
+		return index
+	}
+	r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, rule)
+	return len(r.Tool.Driver.Rules) - 1
+}
+
+// AddResult adds a result to the run, the rule of the result needs to be added to the run before
+func (r *Runs) AddResult(result Results) error {
+	index := r.ruleIndex(result.RuleID)
+	if index < 0 {
+		return errors.Errorf("rule '%v' is not defined for tool '%v'", result.RuleID, r.Tool.Driver.Name)
+	}
+	result.RuleIndex = index
+	r.Results = append(r.Results, result)
+	return nil
+}
+
+func (r *Runs) ruleIndex(id string) int {
+	for i, rule := range r.Tool.Driver.Rules {
+		if rule.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// MergeSARIF merges SARIF logs into one log.
+// Runs of the same tool (name and version) are combined into one run containing the rules and results of all of them.
+func MergeSARIF(logs ...SARIF) SARIF {
+	merged := NewSARIF()
+	for _, log := range logs {
+		for _, run := range log.Runs {
+			index := -1
+			for i, mergedRun := range merged.Runs {
+				if mergedRun.Tool.Driver.Name == run.Tool.Driver.Name && mergedRun.Tool.Driver.Version == run.Tool.Driver.Version {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				merged.Runs = append(merged.Runs, NewRun(run.Tool.Driver.Name, run.Tool.Driver.Version, run.Tool.Driver.InformationURI))
+				index = len(merged.Runs) - 1
+			}
+			mergedRun := &merged.Runs[index]
+			for _, rule := range run.Tool.Driver.Rules {
+				mergedRun.AddRule(rule)
+			}
+			for _, result := range run.Results {
+				if len(result.RuleID) == 0 && result.RuleIndex >= 0 && result.RuleIndex < len(run.Tool.Driver.Rules) {
+					result.RuleID = run.Tool.Driver.Rules[result.RuleIndex].ID
+				}
+				if mergedRun.ruleIndex(result.RuleID) < 0 {
+					mergedRun.AddRule(SarifRule{ID: result.RuleID})
+				}
+				mergedRun.AddResult(result)
+			}
+		}
+	}
+	return merged
+}
+
+// SarifLevelFromCVSS maps a CVSS score to the level of a result
+func SarifLevelFromCVSS(score float64) string {
+	switch {
+	case score >= 7.0:
+		return SarifLevelError
+	case score >= 4.0:
+		return SarifLevelWarning
+	case score > 0:
+		return SarifLevelNote
+	}
+	return SarifLevelNone
+}
+
+// SarifLevelFromSeverity maps the severities used by the various scanners to the level of a result
+func SarifLevelFromSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high", "blocker":
+		return SarifLevelError
+	case "medium", "major":
+		return SarifLevelWarning
+	case "low", "minor", "info", "information", "informational":
+		return SarifLevelNote
+	}
+	return SarifLevelWarning
+}
+
+// SecuritySeverity formats a CVSS score as security-severity rule property, an empty string is returned if no score is available
+func SecuritySeverity(score float64) string {
+	if score <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", score)
+}
+
+// ToJSON returns the SARIF log in JSON format
+func (s *SARIF) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// ReadSARIF parses a SARIF log
+func ReadSARIF(content []byte) (SARIF, error) {
+	var sarif SARIF
+	if err := json.Unmarshal(content, &sarif); err != nil {
+		return sarif, errors.Wrap(err, "failed to parse SARIF log")
+	}
+	return sarif, nil
+}
+
+// SarifFileUtils defines the file system functions required for writing a SARIF log
+type SarifFileUtils interface {
+	MkdirAll(path string, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// WriteSARIF writes the SARIF log to the given path and creates the parent directory if required.
+// SARIF reports are optional, thus steps only log a warning in case of an error instead of failing.
+func WriteSARIF(sarif SARIF, path string, utils SarifFileUtils) error {
+	content, err := sarif.ToJSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF log")
+	}
+	if err := utils.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrapf(err, "failed to create directory for SARIF log '%v'", path)
+	}
+	if err := utils.FileWrite(path, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write SARIF log '%v'", path)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuns(t *testing.T) {
+	t.Run("add rules and results", func(t *testing.T) {
+		run := NewRun("tool", "1.0", "https://tool.example.com")
+		assert.Equal(t, 0, run.AddRule(SarifRule{ID: "rule1"}))
+		assert.Equal(t, 1, run.AddRule(SarifRule{ID: "rule2"}))
+		// rules are only added once
+		assert.Equal(t, 0, run.AddRule(SarifRule{ID: "rule1", Name: "other"}))
+
+		require.NoError(t, run.AddResult(Results{RuleID: "rule2", Message: Message{Text: "finding"}}))
+		assert.Len(t, run.Tool.Driver.Rules, 2)
+		assert.Equal(t, "", run.Tool.Driver.Rules[0].Name)
+		assert.Equal(t, 1, run.Results[0].RuleIndex)
+	})
+
+	t.Run("result with unknown rule", func(t *testing.T) {
+		run := NewRun("tool", "1.0", "")
+		assert.EqualError(t, run.AddResult(Results{RuleID: "unknown"}), "rule 'unknown' is not defined for tool 'tool'")
+	})
+}
+
+func TestMergeSARIF(t *testing.T) {
+	newLog := func(toolName, toolVersion string, ruleIDs ...string) SARIF {
+		run := NewRun(toolName, toolVersion, "")
+		for _, id := range ruleIDs {
+			run.AddRule(SarifRule{ID: id})
+			run.AddResult(Results{RuleID: id, Message: Message{Text: toolName + " " + id}})
+		}
+		return NewSARIF(run)
+	}
+
+	t.Run("runs of the same tool are combined", func(t *testing.T) {
+		merged := MergeSARIF(newLog("WhiteSource", "", "CVE-1", "CVE-2"), newLog("WhiteSource", "", "CVE-2", "CVE-3"), newLog("Fortify", "20.1", "SQL Injection"))
+
+		assert.Equal(t, SarifSchema, merged.Schema)
+		assert.Equal(t, SarifVersion, merged.Version)
+		require.Len(t, merged.Runs, 2)
+
+		ws := merged.Runs[0]
+		assert.Equal(t, "WhiteSource", ws.Tool.Driver.Name)
+		assert.Equal(t, []SarifRule{{ID: "CVE-1"}, {ID: "CVE-2"}, {ID: "CVE-3"}}, ws.Tool.Driver.Rules)
+		require.Len(t, ws.Results, 4)
+		// the rule index is adapted to the merged rules
+		assert.Equal(t, "CVE-3", ws.Results[3].RuleID)
+		assert.Equal(t, 2, ws.Results[3].RuleIndex)
+
+		assert.Equal(t, "Fortify", merged.Runs[1].Tool.Driver.Name)
+		assert.Len(t, merged.Runs[1].Results, 1)
+	})
+
+	t.Run("different tool versions are kept separate", func(t *testing.T) {
+		merged := MergeSARIF(newLog("Fortify", "20.1", "a"), newLog("Fortify", "20.2", "a"))
+		assert.Len(t, merged.Runs, 2)
+	})
+
+	t.Run("results referencing rules by index only", func(t *testing.T) {
+		log := newLog("tool", "", "a", "b")
+		log.Runs[0].Results[1].RuleID = ""
+		merged := MergeSARIF(log)
+		assert.Equal(t, "b", merged.Runs[0].Results[1].RuleID)
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		merged := MergeSARIF()
+		assert.Equal(t, []Runs{}, merged.Runs)
+	})
+}
+
+func TestSarifLevels(t *testing.T) {
+	assert.Equal(t, SarifLevelError, SarifLevelFromCVSS(9.8))
+	assert.Equal(t, SarifLevelError, SarifLevelFromCVSS(7.0))
+	assert.Equal(t, SarifLevelWarning, SarifLevelFromCVSS(5.5))
+	assert.Equal(t, SarifLevelNote, SarifLevelFromCVSS(2.1))
+	assert.Equal(t, SarifLevelNone, SarifLevelFromCVSS(0))
+
+	assert.Equal(t, SarifLevelError, SarifLevelFromSeverity("BLOCKER"))
+	assert.Equal(t, SarifLevelError, SarifLevelFromSeverity("High"))
+	assert.Equal(t, SarifLevelWarning, SarifLevelFromSeverity("Medium"))
+	assert.Equal(t, SarifLevelNote, SarifLevelFromSeverity("Information"))
+	assert.Equal(t, SarifLevelWarning, SarifLevelFromSeverity("unknown"))
+
+	assert.Equal(t, "7.5", SecuritySeverity(7.46))
+	assert.Equal(t, "", SecuritySeverity(0))
+}
+
+func TestWriteAndReadSARIF(t *testing.T) {
+	utils := &mock.FilesMock{}
+	run := NewRun("tool", "", "")
+	run.AddRule(SarifRule{ID: "rule", Properties: &SarifRuleProperties{SecuritySeverity: "9.8"}})
+	run.AddResult(Results{RuleID: "rule", Level: SarifLevelError, Message: Message{Text: "finding"}, Locations: []Location{{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: "src/main.go"}, Region: &Region{StartLine: 12}}}}})
+
+	require.NoError(t, WriteSARIF(NewSARIF(run), ".pipeline/stepReports/tool.sarif", utils))
+
+	content, err := utils.FileRead(".pipeline/stepReports/tool.sarif")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"$schema": "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"`)
+	assert.Contains(t, string(content), `"security-severity": "9.8"`)
+
+	sarif, err := ReadSARIF(content)
+	require.NoError(t, err)
+	assert.Equal(t, NewSARIF(run), sarif)
+
+	_, err = ReadSARIF([]byte("{"))
+	assert.EqualError(t, err, "failed to parse SARIF log: unexpected end of JSON input")
+}
@@ -0,0 +1,37 @@
+package fortify
+
+import (
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+	"github.com/piper-validation/fortify-client-go/models"
+)
+
+// ConvertIssuesToFindings maps the issues of a Fortify project version to the common finding model.
+// Removed issues are skipped, suppressed issues are suppressed findings.
+func ConvertIssuesToFindings(issues []*models.ProjectVersionIssue) []vulnerability.Finding {
+	findings := []vulnerability.Finding{}
+	for _, issue := range issues {
+		if issue == nil || boolValue(issue.Removed) {
+			continue
+		}
+		findings = append(findings, ConvertIssueToFinding(issue))
+	}
+	return findings
+}
+
+// ConvertIssueToFinding maps a single issue to the common finding model
+func ConvertIssueToFinding(issue *models.ProjectVersionIssue) vulnerability.Finding {
+	finding := vulnerability.Finding{
+		ID:       stringValue(issue.IssueName),
+		Severity: stringValue(issue.Friority),
+		Location: vulnerability.Location{File: stringValue(issue.FullFileName)},
+		Tools:    []string{"Fortify"},
+		Status:   vulnerability.StatusOpen,
+	}
+	if issue.LineNumber != nil {
+		finding.Location.Line = int(*issue.LineNumber)
+	}
+	if boolValue(issue.Suppressed) {
+		finding.Status, finding.Justification = vulnerability.StatusSuppressed, stringValue(issue.PrimaryTag)
+	}
+	return finding
+}
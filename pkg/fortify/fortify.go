@@ -19,6 +19,7 @@ import (
 	"github.com/piper-validation/fortify-client-go/fortify/file_token_controller"
 	"github.com/piper-validation/fortify-client-go/fortify/filter_set_of_project_version_controller"
 	"github.com/piper-validation/fortify-client-go/fortify/issue_group_of_project_version_controller"
+	"github.com/piper-validation/fortify-client-go/fortify/issue_of_project_version_controller"
 	"github.com/piper-validation/fortify-client-go/fortify/issue_selector_set_of_project_version_controller"
 	"github.com/piper-validation/fortify-client-go/fortify/issue_statistics_of_project_version_controller"
 	"github.com/piper-validation/fortify-client-go/fortify/project_controller"
@@ -61,6 +62,7 @@ type System interface {
 	GetProjectIssuesByIDAndFilterSetGroupedBySelector(id int64, filter, filterSetGUID string, issueFilterSelectorSet *models.IssueFilterSelectorSet) ([]*models.ProjectVersionIssueGroup, error)
 	ReduceIssueFilterSelectorSet(issueFilterSelectorSet *models.IssueFilterSelectorSet, names []string, options []string) *models.IssueFilterSelectorSet
 	GetIssueStatisticsOfProjectVersion(id int64) ([]*models.IssueStatistics, error)
+	GetAllIssueDetails(projectVersionID int64) ([]*models.ProjectVersionIssue, error)
 	GenerateQGateReport(projectID, projectVersionID, reportTemplateID int64, projectName, projectVersionName, reportFormat string) (*models.SavedReport, error)
 	GetReportDetails(id int64) (*models.SavedReport, error)
 	UploadResultFile(endpoint, file string, projectVersionID int64) error
@@ -599,6 +601,19 @@ func (sys *SystemInstance) GetIssueStatisticsOfProjectVersion(id int64) ([]*mode
 	return result.GetPayload().Data, nil
 }
 
+// GetAllIssueDetails returns all issues of the project version addressed with projectVersionID including suppressed ones
+func (sys *SystemInstance) GetAllIssueDetails(projectVersionID int64) ([]*models.ProjectVersionIssue, error) {
+	enable := true
+	limit := int32(-1)
+	params := &issue_of_project_version_controller.ListIssueOfProjectVersionParams{ParentID: projectVersionID, Showsuppressed: &enable, Limit: &limit}
+	params.WithTimeout(sys.timeout)
+	result, err := sys.client.IssueOfProjectVersionController.ListIssueOfProjectVersion(params, sys)
+	if err != nil {
+		return nil, err
+	}
+	return result.GetPayload().Data, nil
+}
+
 // GenerateQGateReport returns the issue statistics related to the project version addressed with id
 func (sys *SystemInstance) GenerateQGateReport(projectID, projectVersionID, reportTemplateID int64, projectName, projectVersionName, reportFormat string) (*models.SavedReport, error) {
 	paramIdentifier := "projectVersionId"
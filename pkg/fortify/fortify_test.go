@@ -837,6 +837,35 @@ func TestGetIssueStatisticsOfProjectVersion(t *testing.T) {
 	})
 }
 
+func TestGetAllIssueDetails(t *testing.T) {
+	// Start a local HTTP server
+	response := `{"data": [{"id": 1234, "issueName": "SQL Injection", "friority": "Critical", "fullFileName": "src/main/java/App.java", "lineNumber": 42},
+				{"id": 1235, "issueName": "Dead Code", "friority": "Low", "suppressed": true}],"count": 2,"responseCode": 200}`
+	var query string
+	sys, server := spinUpServer(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/projectVersions/10172/issues" {
+			query = req.URL.RawQuery
+			header := rw.Header()
+			header.Add("Content-type", "application/json")
+			rw.Write([]byte(response))
+			return
+		}
+	})
+	// Close the server when test finishes
+	defer server.Close()
+
+	t.Run("test success", func(t *testing.T) {
+		result, err := sys.GetAllIssueDetails(10172)
+		assert.NoError(t, err, "GetAllIssueDetails call not successful")
+		assert.Equal(t, 2, len(result), "Different result content expected")
+		assert.Equal(t, int64(1234), result[0].ID, "Different result content expected")
+		assert.Equal(t, "src/main/java/App.java", *result[0].FullFileName, "Different result content expected")
+		assert.Equal(t, true, *result[1].Suppressed, "Different result content expected")
+		assert.Contains(t, query, "limit=-1")
+		assert.Contains(t, query, "showsuppressed=true")
+	})
+}
+
 func TestGenerateQGateReport(t *testing.T) {
 	// Start a local HTTP server
 	data := ""
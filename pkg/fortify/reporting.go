@@ -7,9 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
 	"github.com/piper-validation/fortify-client-go/models"
 
 	"github.com/pkg/errors"
@@ -111,6 +113,22 @@ func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectVer
 	return reportPaths, nil
 }
 
+// WriteSarif writes the SARIF log of the project version into the step report directory
+func WriteSarif(sarif format.SARIF, projectName, projectVersion string) (piperutils.Path, error) {
+	utils := piperutils.Files{}
+	sarifPath := filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("fortifyExecuteScan_sast_%v.sarif", reportShaFortify([]string{projectName, projectVersion})))
+	if err := format.WriteSARIF(sarif, sarifPath, &utils); err != nil {
+		return piperutils.Path{}, err
+	}
+	return piperutils.Path{Name: "Fortify SARIF Report", Target: sarifPath}, nil
+}
+
+// WriteFindings writes the issues mapped to the common finding model, they are consolidated with the ones of other scanners by step pipelineCreateScanSummary
+func WriteFindings(findings []vulnerability.Finding, projectName, projectVersion string) error {
+	_, err := vulnerability.WriteFindings(findings, fmt.Sprintf("fortifyExecuteScan_sast_%v", reportShaFortify([]string{projectName, projectVersion})), &piperutils.Files{})
+	return err
+}
+
 func reportShaFortify(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
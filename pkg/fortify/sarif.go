@@ -0,0 +1,72 @@
+package fortify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/piper-validation/fortify-client-go/models"
+)
+
+// ConvertIssuesToSarif converts the issues of a Fortify project version into a SARIF log.
+// Every issue category is represented as rule, suppressed issues are marked as suppressed results.
+func ConvertIssuesToSarif(issues []*models.ProjectVersionIssue, serverURL string, projectVersionID int64) format.SARIF {
+	run := format.NewRun("Fortify", "", "https://www.microfocus.com/en-us/cyberres/application-security/static-code-analyzer")
+	for _, issue := range issues {
+		if issue == nil || boolValue(issue.Removed) {
+			continue
+		}
+		category := stringValue(issue.IssueName)
+		rule := format.SarifRule{
+			ID:               category,
+			ShortDescription: &format.Message{Text: category},
+			Properties:       &format.SarifRuleProperties{Tags: []string{"security"}},
+		}
+		if kingdom := stringValue(issue.Kingdom); len(kingdom) > 0 {
+			rule.Properties.Tags = append(rule.Properties.Tags, kingdom)
+		}
+		run.AddRule(rule)
+
+		issueURL := fmt.Sprintf("%v/html/ssc/index.jsp#!/version/%v/fix/%v/", strings.TrimSuffix(serverURL, "/"), projectVersionID, issue.ID)
+		result := format.Results{
+			RuleID: category,
+			Level:  format.SarifLevelFromSeverity(stringValue(issue.Friority)),
+			Message: format.Message{
+				Text:     fmt.Sprintf("%v in %v", category, stringValue(issue.PrimaryLocation)),
+				Markdown: fmt.Sprintf("[%v](%v) in %v", category, issueURL, stringValue(issue.PrimaryLocation)),
+			},
+			Properties: map[string]string{
+				"friority": stringValue(issue.Friority),
+				"analysis": stringValue(issue.PrimaryTag),
+				"audited":  fmt.Sprint(issue.Audited),
+				"issueURL": issueURL,
+			},
+		}
+		if fileName := stringValue(issue.FullFileName); len(fileName) > 0 {
+			location := format.Location{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: fileName}}}
+			if issue.LineNumber != nil && *issue.LineNumber > 0 {
+				location.PhysicalLocation.Region = &format.Region{StartLine: int(*issue.LineNumber)}
+			}
+			result.Locations = []format.Location{location}
+		}
+		if instanceID := stringValue(issue.IssueInstanceID); len(instanceID) > 0 {
+			result.PartialFingerprints = map[string]string{"fortifyIssueInstanceId": instanceID}
+		}
+		if boolValue(issue.Suppressed) {
+			result.Suppressions = []format.Suppression{{Kind: "external", Status: "accepted", Justification: stringValue(issue.PrimaryTag)}}
+		}
+		run.AddResult(result)
+	}
+	return format.NewSARIF(run)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
@@ -0,0 +1,53 @@
+package fortify
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertIssuesToSarif(t *testing.T) {
+	str := func(value string) *string { return &value }
+	line := int32(42)
+	suppressed := true
+	removed := true
+
+	issues := []*models.ProjectVersionIssue{
+		{ID: 1, IssueName: str("SQL Injection"), Kingdom: str("Input Validation and Representation"), Friority: str("Critical"), FullFileName: str("src/App.java"), PrimaryLocation: str("App.java"), LineNumber: &line, IssueInstanceID: str("ABC123")},
+		{ID: 2, IssueName: str("SQL Injection"), Friority: str("High"), FullFileName: str("src/Dao.java"), PrimaryLocation: str("Dao.java")},
+		{ID: 3, IssueName: str("Dead Code"), Friority: str("Low"), PrimaryLocation: str("Util.java"), Suppressed: &suppressed, PrimaryTag: str("Not an Issue")},
+		{ID: 4, IssueName: str("Removed"), Friority: str("Low"), Removed: &removed},
+		nil,
+	}
+
+	sarif := ConvertIssuesToSarif(issues, "https://fortify.example.com/ssc/", 10172)
+
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	assert.Equal(t, "Fortify", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "SQL Injection", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, []string{"security", "Input Validation and Representation"}, run.Tool.Driver.Rules[0].Properties.Tags)
+	assert.Equal(t, "Dead Code", run.Tool.Driver.Rules[1].ID)
+
+	require.Len(t, run.Results, 3)
+	first := run.Results[0]
+	assert.Equal(t, format.SarifLevelError, first.Level)
+	assert.Equal(t, "SQL Injection in App.java", first.Message.Text)
+	assert.Equal(t, "[SQL Injection](https://fortify.example.com/ssc/html/ssc/index.jsp#!/version/10172/fix/1/) in App.java", first.Message.Markdown)
+	assert.Equal(t, []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: "src/App.java"}, Region: &format.Region{StartLine: 42}}}}, first.Locations)
+	assert.Equal(t, map[string]string{"fortifyIssueInstanceId": "ABC123"}, first.PartialFingerprints)
+	assert.Empty(t, first.Suppressions)
+
+	assert.Equal(t, 0, run.Results[1].RuleIndex)
+	assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+
+	third := run.Results[2]
+	assert.Equal(t, 1, third.RuleIndex)
+	assert.Equal(t, format.SarifLevelNote, third.Level)
+	assert.Empty(t, third.Locations)
+	assert.Equal(t, []format.Suppression{{Kind: "external", Status: "accepted", Justification: "Not an Issue"}}, third.Suppressions)
+}
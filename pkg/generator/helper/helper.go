@@ -59,6 +59,7 @@ import (
 	{{ if .OutputResources -}}
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	{{ end -}}
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/spf13/cobra"
@@ -127,10 +128,12 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultCredentials()
 				{{- range $notused, $oRes := .OutputResources }}
 				{{ index $oRes "name" }}.persist({{if $.ExportPrefix}}{{ $.ExportPrefix }}.{{end}}GeneralConfig.EnvRootPath, "{{ index $oRes "name" }}"){{ end }}
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				telemetryData.ErrorCategory = log.GetErrorCategory().String()
+				piperutils.PersistStepResult({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.EnvRootPath, {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.StageName, STEP_NAME, telemetryData.ErrorCode == "0", startTime, {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.CorrelationID)
 				telemetry.Send(&telemetryData)
 				if len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
 					splunk.Send(&telemetryData, logCollector)
@@ -176,6 +179,9 @@ func {{.FlagsFunc}}(cmd *cobra.Command, stepConfig *{{.StepName}}Options) {
 								{{- if .Type }}
 								Type: "{{ .Type }}",
 								{{- end }}
+								{{- if .Providers }}
+								Providers: []string{{ "{" }}{{ range $_, $provider := .Providers }}"{{$provider}}",{{ end }}{{"}"}},
+								{{- end }}
 							{{ "}" }},
 							{{- nindent 24 ""}}
 {{- end -}}
@@ -220,8 +226,9 @@ func {{ .StepName }}Metadata() config.StepData {
 						Scope:     []string{{ "{" }}{{ range $notused, $scope := $value.Scope }}"{{ $scope }}",{{ end }}{{ "}" }},
 						Type:      "{{ $value.Type }}",
 						Mandatory: {{ $value.Mandatory }},
-						Aliases:   []config.Alias{{ "{" }}{{ range $notused, $alias := $value.Aliases }}{{ "{" }}Name: "{{ $alias.Name }}"{{ "}" }},{{ end }}{{ "}" }},
-						{{ if $value.Default -}} Default:   {{ $value.Default }}, {{- end}}{{ if $value.Conditions }}
+						Aliases:   []config.Alias{{ "{" }}{{ range $notused, $alias := $value.Aliases }}{{ "{" }}Name: "{{ $alias.Name }}"{{ if $alias.Deprecated }}, Deprecated: true{{ end }}{{ "}" }},{{ end }}{{ "}" }},
+						{{ if $value.Default -}} Default:   {{ $value.Default }}, {{- end}}{{ if $value.PossibleValues }}
+						PossibleValues: {{ possibleValues $value.PossibleValues }},{{- end }}{{ if $value.Conditions }}
 						Conditions: []config.Condition{ {{- range $i, $cond := $value.Conditions }} {ConditionRef: "{{$cond.ConditionRef}}", Params: []config.Param{ {{- range $j, $p := $cond.Params}} { Name: "{{$p.Name}}", Value: "{{$p.Value}}" }, {{end -}} } }, {{ end -}} },{{- end }}
 					},{{ end }}
 				},
@@ -701,6 +708,7 @@ func stepTemplate(myStepInfo stepInfo, templateName, goTemplate string) []byte {
 	funcMap["longName"] = longName
 	funcMap["uniqueName"] = mustUniqName
 	funcMap["isCLIParam"] = isCLIParam
+	funcMap["possibleValues"] = possibleValues
 
 	return generateCode(myStepInfo, templateName, goTemplate, funcMap)
 }
@@ -724,6 +732,15 @@ func generateCode(dataObject interface{}, templateName, goTemplate string, funcM
 	return generatedCode.Bytes()
 }
 
+// possibleValues renders the possible values of a parameter as Go literal
+func possibleValues(values []interface{}) string {
+	literals := []string{}
+	for _, v := range values {
+		literals = append(literals, fmt.Sprintf("%#v", v))
+	}
+	return fmt.Sprintf("[]interface{}{%v}", strings.Join(literals, ", "))
+}
+
 func longName(long string) string {
 	l := strings.ReplaceAll(long, "`", "` + \"`\" + `")
 	l = strings.TrimSpace(l)
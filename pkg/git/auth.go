@@ -0,0 +1,133 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+)
+
+// Supported methods for the authentication against remote git repositories
+const (
+	AuthMethodBasic     = "basic"
+	AuthMethodSSH       = "ssh"
+	AuthMethodToken     = "token"
+	AuthMethodGitHubApp = "githubApp"
+)
+
+// sshUser is the user for ssh access, git hosting services identify the account by the key
+const sshUser = "git"
+
+var sshAgentAuth = ssh.NewSSHAgentAuth
+var installationToken = piperGithub.NewInstallationToken
+
+// AuthOptions defines the authentication against a remote git repository
+type AuthOptions struct {
+	// Method is one of basic, ssh, token or githubApp.
+	// In case no method is defined basic authentication is used if a username is available.
+	Method   string
+	Username string
+	Password string
+	// SSHKeyPath is the path to the private key, in case it is empty the keys of the ssh agent are used
+	SSHKeyPath       string
+	SSHKeyPassphrase string
+	// SSHKnownHostsPath is the path to the known_hosts file used to verify the host key,
+	// in case it is empty the files defined by SSH_KNOWN_HOSTS or the default known_hosts files are used
+	SSHKnownHostsPath string
+	// Token is sent as bearer token
+	Token                   string
+	GitHubAppID             string
+	GitHubAppInstallationID string
+	GitHubAppPrivateKeyPath string
+	GitHubAPIURL            string
+}
+
+// AuthMethod provides the go-git authentication for the options.
+// No authentication (nil) is returned in case neither a method nor a username is defined.
+func AuthMethod(options AuthOptions) (transport.AuthMethod, error) {
+	switch options.Method {
+	case "":
+		if len(options.Username) == 0 {
+			return nil, nil
+		}
+		return &http.BasicAuth{Username: options.Username, Password: options.Password}, nil
+	case AuthMethodBasic:
+		if len(options.Username) == 0 || len(options.Password) == 0 {
+			return nil, errors.New("username and password are required for basic authentication")
+		}
+		return &http.BasicAuth{Username: options.Username, Password: options.Password}, nil
+	case AuthMethodSSH:
+		return sshAuthMethod(options)
+	case AuthMethodToken:
+		if len(options.Token) == 0 {
+			return nil, errors.New("a token is required for token authentication")
+		}
+		return &http.TokenAuth{Token: options.Token}, nil
+	case AuthMethodGitHubApp:
+		return gitHubAppAuthMethod(options)
+	}
+	return nil, fmt.Errorf("git authentication method '%v' not supported, use one of %v, %v, %v or %v", options.Method, AuthMethodBasic, AuthMethodSSH, AuthMethodToken, AuthMethodGitHubApp)
+}
+
+// IsSSH returns true in case the authentication requires an ssh url of the remote repository
+func IsSSH(auth transport.AuthMethod) bool {
+	switch auth.(type) {
+	case *ssh.PublicKeys, *ssh.PublicKeysCallback:
+		return true
+	}
+	return false
+}
+
+func sshAuthMethod(options AuthOptions) (transport.AuthMethod, error) {
+	knownHosts := []string{}
+	if len(options.SSHKnownHostsPath) > 0 {
+		knownHosts = append(knownHosts, options.SSHKnownHostsPath)
+	}
+	// the host key is always verified, ssh.InsecureIgnoreHostKey is deliberately not supported
+	hostKeyCallback, err := ssh.NewKnownHostsCallback(knownHosts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read known_hosts")
+	}
+
+	if len(options.SSHKeyPath) == 0 {
+		auth, err := sshAgentAuth(sshUser)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve ssh authentication from ssh agent")
+		}
+		auth.HostKeyCallback = hostKeyCallback
+		return auth, nil
+	}
+
+	auth, err := ssh.NewPublicKeysFromFile(sshUser, options.SSHKeyPath, options.SSHKeyPassphrase)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ssh private key '%v'", options.SSHKeyPath)
+	}
+	auth.HostKeyCallback = hostKeyCallback
+	return auth, nil
+}
+
+func gitHubAppAuthMethod(options AuthOptions) (transport.AuthMethod, error) {
+	appID, err := strconv.ParseInt(options.GitHubAppID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App ID '%v'", options.GitHubAppID)
+	}
+	installationID, err := strconv.ParseInt(options.GitHubAppInstallationID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App installation ID '%v'", options.GitHubAppInstallationID)
+	}
+	privateKey, err := ioutil.ReadFile(options.GitHubAppPrivateKeyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read GitHub App private key '%v'", options.GitHubAppPrivateKeyPath)
+	}
+	token, err := installationToken(options.GitHubAPIURL, appID, installationID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	// GitHub expects installation tokens for git operations as password of the user x-access-token
+	return &http.BasicAuth{Username: "x-access-token", Password: token}, nil
+}
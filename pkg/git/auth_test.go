@@ -0,0 +1,135 @@
+package git
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T, dir string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "id_rsa")
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+	return path
+}
+
+func TestAuthMethod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gitAuth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyPath := writePrivateKey(t, dir)
+	knownHostsPath := filepath.Join(dir, "known_hosts")
+	require.NoError(t, ioutil.WriteFile(knownHostsPath, []byte{}, 0600))
+
+	t.Run("no authentication", func(t *testing.T) {
+		auth, err := AuthMethod(AuthOptions{})
+		assert.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("basic authentication by default", func(t *testing.T) {
+		auth, err := AuthMethod(AuthOptions{Username: "user", Password: "password"})
+		assert.NoError(t, err)
+		assert.Equal(t, &http.BasicAuth{Username: "user", Password: "password"}, auth)
+	})
+
+	t.Run("basic authentication", func(t *testing.T) {
+		auth, err := AuthMethod(AuthOptions{Method: "basic", Username: "user", Password: "password"})
+		assert.NoError(t, err)
+		assert.Equal(t, &http.BasicAuth{Username: "user", Password: "password"}, auth)
+
+		_, err = AuthMethod(AuthOptions{Method: "basic", Username: "user"})
+		assert.EqualError(t, err, "username and password are required for basic authentication")
+	})
+
+	t.Run("token authentication", func(t *testing.T) {
+		auth, err := AuthMethod(AuthOptions{Method: "token", Token: "theToken"})
+		assert.NoError(t, err)
+		assert.Equal(t, &http.TokenAuth{Token: "theToken"}, auth)
+		assert.False(t, IsSSH(auth))
+
+		_, err = AuthMethod(AuthOptions{Method: "token"})
+		assert.EqualError(t, err, "a token is required for token authentication")
+	})
+
+	t.Run("ssh private key", func(t *testing.T) {
+		auth, err := AuthMethod(AuthOptions{Method: "ssh", SSHKeyPath: keyPath, SSHKnownHostsPath: knownHostsPath})
+		require.NoError(t, err)
+		if assert.IsType(t, &ssh.PublicKeys{}, auth) {
+			publicKeys := auth.(*ssh.PublicKeys)
+			assert.Equal(t, "git", publicKeys.User)
+			assert.NotNil(t, publicKeys.HostKeyCallback)
+		}
+		assert.True(t, IsSSH(auth))
+	})
+
+	t.Run("ssh private key not found", func(t *testing.T) {
+		_, err := AuthMethod(AuthOptions{Method: "ssh", SSHKeyPath: filepath.Join(dir, "missing"), SSHKnownHostsPath: knownHostsPath})
+		assert.Contains(t, err.Error(), "failed to read ssh private key")
+	})
+
+	t.Run("ssh known_hosts not found", func(t *testing.T) {
+		_, err := AuthMethod(AuthOptions{Method: "ssh", SSHKeyPath: keyPath, SSHKnownHostsPath: filepath.Join(dir, "missing")})
+		assert.Contains(t, err.Error(), "failed to read known_hosts")
+	})
+
+	t.Run("ssh agent", func(t *testing.T) {
+		defer func() { sshAgentAuth = ssh.NewSSHAgentAuth }()
+		sshAgentAuth = func(user string) (*ssh.PublicKeysCallback, error) {
+			return &ssh.PublicKeysCallback{User: user}, nil
+		}
+
+		auth, err := AuthMethod(AuthOptions{Method: "ssh", SSHKnownHostsPath: knownHostsPath})
+		require.NoError(t, err)
+		if assert.IsType(t, &ssh.PublicKeysCallback{}, auth) {
+			assert.NotNil(t, auth.(*ssh.PublicKeysCallback).HostKeyCallback)
+		}
+
+		sshAgentAuth = func(string) (*ssh.PublicKeysCallback, error) {
+			return nil, errors.New("SSH_AUTH_SOCK not-specified")
+		}
+		_, err = AuthMethod(AuthOptions{Method: "ssh", SSHKnownHostsPath: knownHostsPath})
+		assert.EqualError(t, err, "failed to retrieve ssh authentication from ssh agent: SSH_AUTH_SOCK not-specified")
+	})
+
+	t.Run("GitHub App", func(t *testing.T) {
+		defer func() { installationToken = piperGithub.NewInstallationToken }()
+		var appID, installationID int64
+		var apiURL string
+		installationToken = func(url string, app, installation int64, privateKey []byte) (string, error) {
+			apiURL, appID, installationID = url, app, installation
+			return "v1.installationToken", nil
+		}
+
+		auth, err := AuthMethod(AuthOptions{Method: "githubApp", GitHubAppID: "4711", GitHubAppInstallationID: "42", GitHubAppPrivateKeyPath: keyPath, GitHubAPIURL: "https://api.github.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &http.BasicAuth{Username: "x-access-token", Password: "v1.installationToken"}, auth)
+		assert.Equal(t, "https://api.github.com", apiURL)
+		assert.Equal(t, int64(4711), appID)
+		assert.Equal(t, int64(42), installationID)
+	})
+
+	t.Run("GitHub App with invalid ID", func(t *testing.T) {
+		_, err := AuthMethod(AuthOptions{Method: "githubApp", GitHubAppID: "myApp", GitHubAppInstallationID: "42"})
+		assert.EqualError(t, err, "invalid GitHub App ID 'myApp'")
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		_, err := AuthMethod(AuthOptions{Method: "kerberos"})
+		assert.EqualError(t, err, "git authentication method 'kerberos' not supported, use one of basic, ssh, token or githubApp")
+	})
+}
@@ -1,11 +1,13 @@
 package git
 
 import (
+	"fmt"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/pkg/errors"
 	"time"
 )
@@ -23,6 +25,17 @@ type utilsRepository interface {
 	Push(o *git.PushOptions) error
 }
 
+// utilsFetcher interface abstraction of git.Repository to enable tests
+type utilsFetcher interface {
+	Fetch(o *git.FetchOptions) error
+}
+
+// CommitResolver interface abstraction of git.Repository to resolve revisions into commits
+type CommitResolver interface {
+	ResolveRevision(rev plumbing.Revision) (*plumbing.Hash, error)
+	CommitObject(h plumbing.Hash) (*object.Commit, error)
+}
+
 // utilsGit interface abstraction of git to enable tests
 type utilsGit interface {
 	plainClone(path string, isBare bool, o *git.CloneOptions) (*git.Repository, error)
@@ -52,14 +65,15 @@ func commitSingleFile(filePath, commitMessage, author string, worktree utilsWork
 	return commit, nil
 }
 
-// PushChangesToRepository Pushes all committed changes in the repository to the remote repository
-func PushChangesToRepository(username, password string, repository *git.Repository) error {
-	return pushChangesToRepository(username, password, repository)
+// PushChangesToRepository Pushes all committed changes in the repository to the remote repository.
+// The authentication is provided by AuthMethod.
+func PushChangesToRepository(auth transport.AuthMethod, repository *git.Repository) error {
+	return pushChangesToRepository(auth, repository)
 }
 
-func pushChangesToRepository(username, password string, repository utilsRepository) error {
+func pushChangesToRepository(auth transport.AuthMethod, repository utilsRepository) error {
 	pushOptions := &git.PushOptions{
-		Auth: &http.BasicAuth{Username: username, Password: password},
+		Auth: auth,
 	}
 	err := repository.Push(pushOptions)
 	if err != nil {
@@ -68,15 +82,55 @@ func pushChangesToRepository(username, password string, repository utilsReposito
 	return nil
 }
 
-// PlainClone Clones a non-bare repository to the provided directory
-func PlainClone(username, password, serverURL, directory string) (*git.Repository, error) {
+// PushBranchToRepository Pushes the committed changes of the local branch to the branch with the same name in the remote repository.
+// Other local branches are not pushed.
+func PushBranchToRepository(auth transport.AuthMethod, branchName string, repository *git.Repository) error {
+	return pushBranchToRepository(auth, branchName, repository)
+}
+
+func pushBranchToRepository(auth transport.AuthMethod, branchName string, repository utilsRepository) error {
+	if branchName == "" {
+		return errors.New("no branch name provided")
+	}
+	pushOptions := &git.PushOptions{
+		Auth:     auth,
+		RefSpecs: []config.RefSpec{config.RefSpec(fmt.Sprintf("refs/heads/%[1]v:refs/heads/%[1]v", branchName))},
+	}
+	err := repository.Push(pushOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push branch '%v'", branchName)
+	}
+	return nil
+}
+
+// FetchFromRemote Fetches the branches of the remote repository, a repository which is already up-to-date is not considered an error.
+// The authentication is provided by AuthMethod.
+func FetchFromRemote(auth transport.AuthMethod, remoteName string, repository *git.Repository) error {
+	return fetchFromRemote(auth, remoteName, repository)
+}
+
+func fetchFromRemote(auth transport.AuthMethod, remoteName string, repository utilsFetcher) error {
+	fetchOptions := &git.FetchOptions{
+		Auth:       auth,
+		RemoteName: remoteName,
+	}
+	err := repository.Fetch(fetchOptions)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return errors.Wrapf(err, "failed to fetch from remote '%v'", remoteName)
+	}
+	return nil
+}
+
+// PlainClone Clones a non-bare repository to the provided directory.
+// The authentication is provided by AuthMethod, nil is used for public repositories.
+func PlainClone(auth transport.AuthMethod, serverURL, directory string) (*git.Repository, error) {
 	abstractedGit := &abstractionGit{}
-	return plainClone(username, password, serverURL, directory, abstractedGit)
+	return plainClone(auth, serverURL, directory, abstractedGit)
 }
 
-func plainClone(username, password, serverURL, directory string, abstractionGit utilsGit) (*git.Repository, error) {
+func plainClone(auth transport.AuthMethod, serverURL, directory string, abstractionGit utilsGit) (*git.Repository, error) {
 	gitCloneOptions := git.CloneOptions{
-		Auth: &http.BasicAuth{Username: username, Password: password},
+		Auth: auth,
 		URL:  serverURL,
 	}
 	repository, err := abstractionGit.plainClone(directory, false, &gitCloneOptions)
@@ -131,7 +185,7 @@ func changeBranch(branchName string, worktree utilsWorkTree) error {
 
 // LogRange Returns a CommitIterator providing all commits reachable from 'to', but
 // not reachable by 'from'.
-func LogRange(repo *git.Repository, from, to string) (object.CommitIter, error) {
+func LogRange(repo CommitResolver, from, to string) (object.CommitIter, error) {
 
 	cTo, err := getCommitObject(to, repo)
 	if err != nil {
@@ -157,7 +211,7 @@ func LogRange(repo *git.Repository, from, to string) (object.CommitIter, error)
 	return object.NewCommitPreorderIter(cTo, map[plumbing.Hash]bool{}, ignore), nil
 }
 
-func getCommitObject(ref string, repo *git.Repository) (*object.Commit, error) {
+func getCommitObject(ref string, repo CommitResolver) (*object.Commit, error) {
 	if len(ref) == 0 {
 		// with go-git v5.1.0 we panic otherwise inside ResolveRevision
 		return nil, errors.New("Cannot get a commit for an empty ref")
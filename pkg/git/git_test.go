@@ -6,8 +6,10 @@ import (
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -42,11 +44,35 @@ func TestCommit(t *testing.T) {
 	})
 }
 
+func TestPushBranchToRepository(t *testing.T) {
+	t.Parallel()
+	t.Run("successful push", func(t *testing.T) {
+		t.Parallel()
+		repository := &RepositoryPushRecorder{}
+		err := pushBranchToRepository(&http.BasicAuth{Username: "user", Password: "password"}, "gitops/app-1.2.3", repository)
+		assert.NoError(t, err)
+		assert.Equal(t, "http-basic-auth - user:*******", repository.pushOptions.Auth.String())
+		assert.Equal(t, []config.RefSpec{"refs/heads/gitops/app-1.2.3:refs/heads/gitops/app-1.2.3"}, repository.pushOptions.RefSpecs)
+	})
+
+	t.Run("no branch name", func(t *testing.T) {
+		t.Parallel()
+		err := pushBranchToRepository(&http.BasicAuth{Username: "user", Password: "password"}, "", &RepositoryPushRecorder{})
+		assert.EqualError(t, err, "no branch name provided")
+	})
+
+	t.Run("error pushing", func(t *testing.T) {
+		t.Parallel()
+		err := pushBranchToRepository(&http.BasicAuth{Username: "user", Password: "password"}, "feature", RepositoryMockError{})
+		assert.EqualError(t, err, "failed to push branch 'feature': error on push commits")
+	})
+}
+
 func TestPushChangesToRepository(t *testing.T) {
 	t.Parallel()
 	t.Run("successful push", func(t *testing.T) {
 		t.Parallel()
-		err := pushChangesToRepository("user", "password", RepositoryMock{
+		err := pushChangesToRepository(&http.BasicAuth{Username: "user", Password: "password"}, RepositoryMock{
 			test: t,
 		})
 		assert.NoError(t, err)
@@ -54,17 +80,41 @@ func TestPushChangesToRepository(t *testing.T) {
 
 	t.Run("error pushing", func(t *testing.T) {
 		t.Parallel()
-		err := pushChangesToRepository("user", "password", RepositoryMockError{})
+		err := pushChangesToRepository(&http.BasicAuth{Username: "user", Password: "password"}, RepositoryMockError{})
 		assert.EqualError(t, err, "failed to push commit: error on push commits")
 	})
 }
 
+func TestFetchFromRemote(t *testing.T) {
+	t.Parallel()
+	t.Run("successful fetch", func(t *testing.T) {
+		t.Parallel()
+		repository := &RepositoryFetchRecorder{}
+		err := fetchFromRemote(&http.TokenAuth{Token: "token"}, "origin", repository)
+		assert.NoError(t, err)
+		assert.Equal(t, "origin", repository.fetchOptions.RemoteName)
+		assert.Equal(t, "http-token-auth - *******", repository.fetchOptions.Auth.String())
+	})
+
+	t.Run("already up-to-date", func(t *testing.T) {
+		t.Parallel()
+		err := fetchFromRemote(nil, "origin", &RepositoryFetchRecorder{err: git.NoErrAlreadyUpToDate})
+		assert.NoError(t, err)
+	})
+
+	t.Run("error fetching", func(t *testing.T) {
+		t.Parallel()
+		err := fetchFromRemote(nil, "origin", &RepositoryFetchRecorder{err: errors.New("repository not found")})
+		assert.EqualError(t, err, "failed to fetch from remote 'origin': repository not found")
+	})
+}
+
 func TestPlainClone(t *testing.T) {
 	t.Parallel()
 	t.Run("successful clone", func(t *testing.T) {
 		t.Parallel()
 		abstractedGit := &UtilsGitMock{}
-		_, err := plainClone("user", "password", "URL", "directory", abstractedGit)
+		_, err := plainClone(&http.BasicAuth{Username: "user", Password: "password"}, "URL", "directory", abstractedGit)
 		assert.NoError(t, err)
 		assert.Equal(t, "directory", abstractedGit.path)
 		assert.False(t, abstractedGit.isBare)
@@ -75,7 +125,7 @@ func TestPlainClone(t *testing.T) {
 	t.Run("error on cloning", func(t *testing.T) {
 		t.Parallel()
 		abstractedGit := UtilsGitMockError{}
-		_, err := plainClone("user", "password", "URL", "directory", abstractedGit)
+		_, err := plainClone(&http.BasicAuth{Username: "user", Password: "password"}, "URL", "directory", abstractedGit)
 		assert.EqualError(t, err, "failed to clone git: error during clone")
 	})
 }
@@ -345,6 +395,29 @@ func (r RepositoryMock) Push(o *git.PushOptions) error {
 	return nil
 }
 
+type RepositoryPushRecorder struct {
+	pushOptions *git.PushOptions
+}
+
+func (r *RepositoryPushRecorder) Worktree() (*git.Worktree, error) {
+	return &git.Worktree{}, nil
+}
+
+func (r *RepositoryPushRecorder) Push(o *git.PushOptions) error {
+	r.pushOptions = o
+	return nil
+}
+
+type RepositoryFetchRecorder struct {
+	fetchOptions *git.FetchOptions
+	err          error
+}
+
+func (r *RepositoryFetchRecorder) Fetch(o *git.FetchOptions) error {
+	r.fetchOptions = o
+	return r.err
+}
+
 type RepositoryMockError struct{}
 
 func (RepositoryMockError) Worktree() (*git.Worktree, error) {
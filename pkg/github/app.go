@@ -0,0 +1,89 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NewInstallationToken creates an access token for an installation of a GitHub App.
+// The App authenticates with a JSON Web Token signed by its private key, the resulting token is valid for one hour.
+func NewInstallationToken(apiURL string, appID, installationID int64, privateKey []byte) (string, error) {
+	appToken, err := appJWT(appID, privateKey, time.Now())
+	if err != nil {
+		return "", err
+	}
+	ctx, client, err := NewClient(appToken, apiURL, "")
+	if err != nil {
+		return "", err
+	}
+	token, _, err := client.Apps.CreateInstallationToken(ctx, installationID, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create access token for installation %v of GitHub App %v", installationID, appID)
+	}
+	return token.GetToken(), nil
+}
+
+// appJWT creates the RS256 signed JSON Web Token authenticating a GitHub App.
+// The issue time is backdated to allow for clock drift, GitHub accepts an expiration of at most ten minutes.
+func appJWT(appID int64, privateKey []byte, now time.Time) (string, error) {
+	key, err := parseRSAPrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := strings.Join([]string{encodeSegment(header), encodeSegment(claims)}, ".")
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign GitHub App token")
+	}
+	return unsigned + "." + encodeSegment(signature), nil
+}
+
+// parseRSAPrivateKey supports the PKCS #1 keys generated by GitHub as well as PKCS #8 keys
+func parseRSAPrivateKey(privateKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode GitHub App private key: no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse GitHub App private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func encodeSegment(segment []byte) string {
+	return base64.RawURLEncoding.EncodeToString(segment)
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generatePrivateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestAppJWT(t *testing.T) {
+	key, keyPEM := generatePrivateKey(t)
+	now := time.Unix(1600000000, 0)
+
+	t.Run("success", func(t *testing.T) {
+		token, err := appJWT(4711, keyPEM, now)
+		require.NoError(t, err)
+
+		segments := strings.Split(token, ".")
+		require.Len(t, segments, 3)
+		claims, err := base64.RawURLEncoding.DecodeString(segments[1])
+		require.NoError(t, err)
+		claimValues := map[string]int64{}
+		require.NoError(t, json.Unmarshal(claims, &claimValues))
+		assert.Equal(t, map[string]int64{"iat": 1599999940, "exp": 1600000540, "iss": 4711}, claimValues)
+
+		signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+	})
+
+	t.Run("PKCS #8 key", func(t *testing.T) {
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		_, err = appJWT(4711, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := appJWT(4711, []byte("no key"), now)
+		assert.EqualError(t, err, "failed to decode GitHub App private key: no PEM data found")
+	})
+}
+
+func TestNewInstallationToken(t *testing.T) {
+	_, keyPEM := generatePrivateKey(t)
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/app/installations/42/access_tokens", req.URL.Path)
+			assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ey"))
+			rw.WriteHeader(http.StatusCreated)
+			rw.Write([]byte(`{"token":"v1.installationToken","expires_at":"2020-09-13T13:26:40Z"}`))
+		}))
+		defer server.Close()
+
+		token, err := NewInstallationToken(server.URL, 4711, 42, keyPEM)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "v1.installationToken", token)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+			rw.Write([]byte(`{"message":"Not Found"}`))
+		}))
+		defer server.Close()
+
+		_, err := NewInstallationToken(server.URL, 4711, 42, keyPEM)
+
+		assert.Contains(t, err.Error(), "failed to create access token for installation 42 of GitHub App 4711")
+	})
+}
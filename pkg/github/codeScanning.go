@@ -0,0 +1,74 @@
+package github
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+// SarifAnalysis defines the payload for uploading a SARIF log to GitHub code scanning
+type SarifAnalysis struct {
+	CommitSHA   string `json:"commit_sha"`
+	Ref         string `json:"ref"`
+	Sarif       string `json:"sarif"`
+	CheckoutURI string `json:"checkout_uri,omitempty"`
+	ToolName    string `json:"tool_name,omitempty"`
+}
+
+// SarifID defines the identifier of an uploaded SARIF log
+type SarifID struct {
+	ID  string `json:"id,omitempty"`
+	URL string `json:"url,omitempty"`
+}
+
+// CodeScanningService uploads SARIF logs to GitHub code scanning.
+// The CodeScanningService of go-github only supports reading alerts.
+type CodeScanningService struct {
+	client *github.Client
+}
+
+// NewCodeScanningService creates a new CodeScanningService based on a GitHub client
+func NewCodeScanningService(client *github.Client) *CodeScanningService {
+	return &CodeScanningService{client: client}
+}
+
+// UploadSarif uploads a SARIF log to GitHub code scanning, the SARIF log needs to be encoded using EncodeSarif
+func (s *CodeScanningService) UploadSarif(ctx context.Context, owner, repo string, analysis *SarifAnalysis) (*SarifID, *github.Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/code-scanning/sarifs", owner, repo)
+	req, err := s.client.NewRequest("POST", u, analysis)
+	if err != nil {
+		return nil, nil, err
+	}
+	sarifID := &SarifID{}
+	resp, err := s.client.Do(ctx, req, sarifID)
+	if err != nil {
+		// uploads are processed asynchronously, GitHub responds with 202 Accepted which go-github treats as error
+		accepted, ok := err.(*github.AcceptedError)
+		if !ok {
+			return nil, resp, err
+		}
+		if err := json.Unmarshal(accepted.Raw, sarifID); err != nil {
+			return nil, resp, errors.Wrap(err, "failed to parse upload response")
+		}
+	}
+	return sarifID, resp, nil
+}
+
+// EncodeSarif compresses a SARIF log using gzip and encodes it in Base64 as expected by GitHub code scanning
+func EncodeSarif(sarif []byte) (string, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(sarif); err != nil {
+		return "", errors.Wrap(err, "failed to compress SARIF log")
+	}
+	if err := writer.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to compress SARIF log")
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadSarif(t *testing.T) {
+	var request SarifAnalysis
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/repos/SAP/jenkins-library/code-scanning/sarifs", req.URL.Path)
+		assert.Equal(t, "Bearer theToken", req.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+		rw.WriteHeader(http.StatusAccepted)
+		rw.Write([]byte(`{"id":"47177e22-5596-11eb-80a1-c1e54ef945c6","url":"https://api.github.com/repos/SAP/jenkins-library/code-scanning/sarifs/47177e22-5596-11eb-80a1-c1e54ef945c6"}`))
+	}))
+	defer server.Close()
+
+	ctx, client, err := NewClient("theToken", server.URL, "")
+	require.NoError(t, err)
+
+	sarif, err := EncodeSarif([]byte(`{"version":"2.1.0"}`))
+	require.NoError(t, err)
+	sarifID, _, err := NewCodeScanningService(client).UploadSarif(ctx, "SAP", "jenkins-library", &SarifAnalysis{CommitSHA: "4b2e2b8", Ref: "refs/heads/master", Sarif: sarif})
+
+	require.NoError(t, err)
+	assert.Equal(t, "47177e22-5596-11eb-80a1-c1e54ef945c6", sarifID.ID)
+	assert.Equal(t, "4b2e2b8", request.CommitSHA)
+	assert.Equal(t, "refs/heads/master", request.Ref)
+	assert.Equal(t, sarif, request.Sarif)
+}
+
+func TestEncodeSarif(t *testing.T) {
+	encoded, err := EncodeSarif([]byte(`{"version":"2.1.0"}`))
+	require.NoError(t, err)
+
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	decoded, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":"2.1.0"}`, string(decoded))
+}
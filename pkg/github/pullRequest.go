@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
+)
+
+const enableAutoMergeMutation = `mutation($pullRequestId: ID!, $mergeMethod: PullRequestMergeMethod) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $pullRequestId, mergeMethod: $mergeMethod}) {
+    clientMutationId
+  }
+}`
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors,omitempty"`
+}
+
+// EnablePullRequestAutoMerge enables auto-merge for a pull request, the pull request is merged with the merge method
+// (MERGE, SQUASH or REBASE) as soon as all requirements of the base branch are met.
+// The REST API of GitHub does not support auto-merge, the pull request is thus identified by its node ID for the GraphQL API.
+func EnablePullRequestAutoMerge(ctx context.Context, client *github.Client, pullRequestNodeID, mergeMethod string) error {
+	request := graphQLRequest{
+		Query: enableAutoMergeMutation,
+		Variables: map[string]interface{}{
+			"pullRequestId": pullRequestNodeID,
+			"mergeMethod":   strings.ToUpper(mergeMethod),
+		},
+	}
+	req, err := client.NewRequest("POST", graphQLURL(client.BaseURL), request)
+	if err != nil {
+		return err
+	}
+	response := graphQLResponse{}
+	if _, err := client.Do(ctx, req, &response); err != nil {
+		return errors.Wrap(err, "failed to enable auto-merge")
+	}
+	// GraphQL reports errors with status code 200
+	if len(response.Errors) > 0 {
+		messages := []string{}
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("failed to enable auto-merge: %v", strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+// graphQLURL derives the GraphQL endpoint from the REST API url, e.g. https://github.example.com/api/v3/ serves GraphQL at https://github.example.com/api/graphql
+func graphQLURL(apiURL *url.URL) string {
+	u := *apiURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/graphql"
+	}
+	return u.String()
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnablePullRequestAutoMerge(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var request graphQLRequest
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/graphql", req.URL.Path)
+			assert.Equal(t, "Bearer theToken", req.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+			rw.Write([]byte(`{"data":{"enablePullRequestAutoMerge":{"clientMutationId":null}}}`))
+		}))
+		defer server.Close()
+		ctx, client, err := NewClient("theToken", server.URL, "")
+		require.NoError(t, err)
+
+		err = EnablePullRequestAutoMerge(ctx, client, "MDExOlB1bGxSZXF1ZXN0MQ==", "squash")
+
+		assert.NoError(t, err)
+		assert.Contains(t, request.Query, "enablePullRequestAutoMerge")
+		assert.Equal(t, map[string]interface{}{"pullRequestId": "MDExOlB1bGxSZXF1ZXN0MQ==", "mergeMethod": "SQUASH"}, request.Variables)
+	})
+
+	t.Run("GraphQL error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Write([]byte(`{"data":null,"errors":[{"message":"Pull request is in clean status"}]}`))
+		}))
+		defer server.Close()
+		ctx, client, err := NewClient("theToken", server.URL, "")
+		require.NoError(t, err)
+
+		err = EnablePullRequestAutoMerge(ctx, client, "MDExOlB1bGxSZXF1ZXN0MQ==", "merge")
+
+		assert.EqualError(t, err, "failed to enable auto-merge: Pull request is in clean status")
+	})
+}
+
+func TestGraphQLURL(t *testing.T) {
+	for apiURL, expected := range map[string]string{
+		"https://api.github.com/":            "https://api.github.com/graphql",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/graphql",
+	} {
+		u, err := url.Parse(apiURL)
+		require.NoError(t, err)
+		assert.Equal(t, expected, graphQLURL(u))
+	}
+}
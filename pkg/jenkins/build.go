@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/bndr/gojenkins"
 )
 
@@ -14,15 +16,131 @@ import (
 type Build interface {
 	GetArtifacts() []gojenkins.Artifact
 	IsRunning(ctx context.Context) bool
+	Poll(ctx context.Context) (int, error)
+	IsBuilding() bool
+	GetResult() string
+	GetUrl() string
+	GetBuildNumber() int64
+	GetConsoleOutputFromIndex(ctx context.Context, startID int64) (content string, offset int64, hasMoreText bool, err error)
 }
 
+// BuildImpl is a wrapper struct for gojenkins.Build that respects the Build interface.
+type BuildImpl struct {
+	Build *gojenkins.Build
+}
+
+// GetArtifacts refers to the gojenkins.Build.GetArtifacts function.
+func (b *BuildImpl) GetArtifacts() []gojenkins.Artifact {
+	return b.Build.GetArtifacts()
+}
+
+// IsRunning refers to the gojenkins.Build.IsRunning function.
+func (b *BuildImpl) IsRunning(ctx context.Context) bool {
+	return b.Build.IsRunning(ctx)
+}
+
+// Poll refers to the gojenkins.Build.Poll function.
+func (b *BuildImpl) Poll(ctx context.Context) (int, error) {
+	return b.Build.Poll(ctx)
+}
+
+// IsBuilding returns whether the build was running when it has been polled the last time.
+func (b *BuildImpl) IsBuilding() bool {
+	return b.Build.Raw.Building
+}
+
+// GetResult refers to the gojenkins.Build.GetResult function.
+func (b *BuildImpl) GetResult() string {
+	return b.Build.GetResult()
+}
+
+// GetUrl refers to the gojenkins.Build.GetUrl function.
+func (b *BuildImpl) GetUrl() string {
+	return b.Build.GetUrl()
+}
+
+// GetBuildNumber refers to the gojenkins.Build.GetBuildNumber function.
+func (b *BuildImpl) GetBuildNumber() int64 {
+	return b.Build.GetBuildNumber()
+}
+
+// GetConsoleOutputFromIndex refers to the gojenkins.Build.GetConsoleOutputFromIndex function.
+// It returns the console output starting at startID and the offset to request the next chunk of the output from.
+func (b *BuildImpl) GetConsoleOutputFromIndex(ctx context.Context, startID int64) (string, int64, bool, error) {
+	output, err := b.Build.GetConsoleOutputFromIndex(ctx, startID)
+	return output.Content, output.Offset, output.HasMoreText, err
+}
+
+// maxFailedPolls is the number of consecutive failures when polling a build after which waiting for the build is aborted
+const maxFailedPolls = 3
+
 // WaitForBuildToFinish waits till a build is finished.
-func WaitForBuildToFinish(ctx context.Context, build Build, pollInterval time.Duration) {
-	//TODO: handle timeout?
-	for build.IsRunning(ctx) {
-		time.Sleep(pollInterval)
-		//TODO: build.Poll() needed?
+// Fails if the context is done before, e.g. due to a timeout, or if polling the build fails repeatedly.
+func WaitForBuildToFinish(ctx context.Context, build Build, pollInterval time.Duration) error {
+	return waitForBuild(ctx, build, pollInterval, nil)
+}
+
+// WaitForBuildToFinishAndStreamOutput waits till a build is finished and writes the console output of the build while it is running.
+// Fails if the context is done before, e.g. due to a timeout, or if polling the build fails repeatedly.
+func WaitForBuildToFinishAndStreamOutput(ctx context.Context, build Build, pollInterval time.Duration, out io.Writer) error {
+	return waitForBuild(ctx, build, pollInterval, out)
+}
+
+func waitForBuild(ctx context.Context, build Build, pollInterval time.Duration, out io.Writer) error {
+	// the offset is kept across polls in order to only request new output (progressiveText)
+	var offset int64
+	streamOutput := func() bool {
+		if out == nil {
+			return false
+		}
+		content, nextOffset, hasMoreText, err := build.GetConsoleOutputFromIndex(ctx, offset)
+		if err != nil {
+			// the output is requested again from the same offset with the next poll
+			log.Entry().WithError(err).Warn("failed to fetch console output of build")
+			return false
+		}
+		io.WriteString(out, content)
+		offset = nextOffset
+		return hasMoreText
+	}
+
+	failedPolls := 0
+	for {
+		// checked explicitly since select picks randomly if the context is done and the poll interval elapsed
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to wait for build to finish: %w", ctx.Err())
+		}
+		if _, err := build.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("failed to wait for build to finish: %w", ctx.Err())
+			}
+			failedPolls++
+			if failedPolls >= maxFailedPolls {
+				return fmt.Errorf("failed to poll build status: %w", err)
+			}
+			log.Entry().WithError(err).Warnf("failed to poll build status, retrying (%v/%v)", failedPolls, maxFailedPolls)
+		} else {
+			failedPolls = 0
+			if !build.IsBuilding() {
+				break
+			}
+			streamOutput()
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for build to finish: %w", ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+	// fetch the remaining output, Jenkins may still write output after the build finished
+	for streamOutput() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
 	}
+	return nil
 }
 
 // FetchBuildArtifact is fetching a build artifact from a finished build with a certain name.
@@ -1,6 +1,7 @@
 package jenkins
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"testing"
@@ -17,13 +18,106 @@ func TestWaitForBuildToFinish(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		// init
 		build := &mocks.Build{}
+		build.On("Poll", ctx).Return(200, nil)
 		build.
-			On("IsRunning", ctx).Return(true).Once().
-			On("IsRunning", ctx).Return(false)
+			On("IsBuilding").Return(true).Once().
+			On("IsBuilding").Return(false)
 		// test
-		WaitForBuildToFinish(ctx, build, time.Millisecond)
+		err := WaitForBuildToFinish(ctx, build, time.Millisecond)
 		// asserts
 		build.AssertExpectations(t)
+		build.AssertNumberOfCalls(t, "Poll", 2)
+		assert.NoError(t, err)
+	})
+	t.Run("success - poll error retried", func(t *testing.T) {
+		// init
+		build := &mocks.Build{}
+		build.
+			On("Poll", ctx).Return(500, fmt.Errorf("connection reset")).Twice().
+			On("Poll", ctx).Return(200, nil)
+		build.On("IsBuilding").Return(false)
+		// test
+		err := WaitForBuildToFinish(ctx, build, time.Millisecond)
+		// asserts
+		build.AssertExpectations(t)
+		build.AssertNumberOfCalls(t, "Poll", 3)
+		assert.NoError(t, err)
+	})
+	t.Run("error - poll fails repeatedly", func(t *testing.T) {
+		// init
+		build := &mocks.Build{}
+		build.On("Poll", ctx).Return(500, fmt.Errorf("connection reset"))
+		// test
+		err := WaitForBuildToFinish(ctx, build, time.Millisecond)
+		// asserts
+		build.AssertExpectations(t)
+		build.AssertNumberOfCalls(t, "Poll", maxFailedPolls)
+		assert.EqualError(t, err, "failed to poll build status: connection reset")
+	})
+	t.Run("error - timeout", func(t *testing.T) {
+		// init
+		timeoutCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		build := &mocks.Build{}
+		// test
+		err := WaitForBuildToFinish(timeoutCtx, build, time.Millisecond)
+		// asserts
+		build.AssertNotCalled(t, "Poll", timeoutCtx)
+		assert.EqualError(t, err, "failed to wait for build to finish: context canceled")
+	})
+	t.Run("error - timeout while building", func(t *testing.T) {
+		// init
+		timeoutCtx, cancel := context.WithCancel(ctx)
+		build := &mocks.Build{}
+		build.On("Poll", timeoutCtx).Return(200, nil)
+		build.On("IsBuilding").Return(true).Run(func(mock.Arguments) { cancel() })
+		// test
+		err := WaitForBuildToFinish(timeoutCtx, build, time.Hour)
+		// asserts
+		build.AssertExpectations(t)
+		assert.EqualError(t, err, "failed to wait for build to finish: context canceled")
+	})
+}
+
+func TestWaitForBuildToFinishAndStreamOutput(t *testing.T) {
+	ctx := context.Background()
+	t.Run("success", func(t *testing.T) {
+		// init
+		build := &mocks.Build{}
+		build.On("Poll", ctx).Return(200, nil)
+		build.
+			On("IsBuilding").Return(true).Twice().
+			On("IsBuilding").Return(false)
+		build.
+			On("GetConsoleOutputFromIndex", ctx, int64(0)).Return("line 1\n", int64(7), false, nil).Once().
+			On("GetConsoleOutputFromIndex", ctx, int64(7)).Return("line 2\n", int64(14), false, nil).Once().
+			On("GetConsoleOutputFromIndex", ctx, int64(14)).Return("finished\n", int64(23), true, nil).Once().
+			On("GetConsoleOutputFromIndex", ctx, int64(23)).Return("done\n", int64(28), false, nil).Once()
+		var out bytes.Buffer
+		// test
+		err := WaitForBuildToFinishAndStreamOutput(ctx, build, time.Millisecond, &out)
+		// asserts
+		build.AssertExpectations(t)
+		assert.NoError(t, err)
+		assert.Equal(t, "line 1\nline 2\nfinished\ndone\n", out.String())
+	})
+	t.Run("success - console output error retried", func(t *testing.T) {
+		// init
+		build := &mocks.Build{}
+		build.On("Poll", ctx).Return(200, nil)
+		build.
+			On("IsBuilding").Return(true).Once().
+			On("IsBuilding").Return(false)
+		build.
+			On("GetConsoleOutputFromIndex", ctx, int64(0)).Return("", int64(0), false, fmt.Errorf("connection reset")).Once().
+			On("GetConsoleOutputFromIndex", ctx, int64(0)).Return("line 1\n", int64(7), false, nil).Once()
+		var out bytes.Buffer
+		// test
+		err := WaitForBuildToFinishAndStreamOutput(ctx, build, time.Millisecond, &out)
+		// asserts
+		build.AssertExpectations(t)
+		assert.NoError(t, err)
+		assert.Equal(t, "line 1\n", out.String())
 	})
 }
 
@@ -13,7 +13,7 @@ import (
 
 func TestInterfaceCompatibility(t *testing.T) {
 	var _ Jenkins = new(gojenkins.Jenkins)
-	var _ Build = new(gojenkins.Build)
+	var _ Build = &BuildImpl{}
 }
 
 func TestTriggerJob(t *testing.T) {
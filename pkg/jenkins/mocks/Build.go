@@ -31,6 +31,97 @@ func (_m *Build) GetArtifacts() []gojenkins.Artifact {
 	return r0
 }
 
+// GetBuildNumber provides a mock function with given fields:
+func (_m *Build) GetBuildNumber() int64 {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// GetConsoleOutputFromIndex provides a mock function with given fields: ctx, startID
+func (_m *Build) GetConsoleOutputFromIndex(ctx context.Context, startID int64) (string, int64, bool, error) {
+	ret := _m.Called(ctx, startID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, int64) string); ok {
+		r0 = rf(ctx, startID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, int64) int64); ok {
+		r1 = rf(ctx, startID)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 bool
+	if rf, ok := ret.Get(2).(func(context.Context, int64) bool); ok {
+		r2 = rf(ctx, startID)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, int64) error); ok {
+		r3 = rf(ctx, startID)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// GetResult provides a mock function with given fields:
+func (_m *Build) GetResult() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetUrl provides a mock function with given fields:
+func (_m *Build) GetUrl() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// IsBuilding provides a mock function with given fields:
+func (_m *Build) IsBuilding() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // IsRunning provides a mock function with given fields: ctx
 func (_m *Build) IsRunning(ctx context.Context) bool {
 	ret := _m.Called(ctx)
@@ -44,3 +135,24 @@ func (_m *Build) IsRunning(ctx context.Context) bool {
 
 	return r0
 }
+
+// Poll provides a mock function with given fields: ctx
+func (_m *Build) Poll(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// KustomizationFileNames contains the file names kustomize recognizes as kustomization within a directory
+var KustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// KustomizeImage defines the override of a container image within the images section of a kustomization
+type KustomizeImage struct {
+	// Name is the image name as referenced in the resources of the kustomization
+	Name    string
+	NewName string
+	NewTag  string
+}
+
+// SetKustomizeImage updates all entries of the images section of a kustomization whose name or newName matches the image.
+// In case no entry matches a new entry is added. Only the images section is modified, the order of the remaining content
+// as well as comments are retained.
+func SetKustomizeImage(kustomization []byte, image KustomizeImage) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(kustomization, &document); err != nil {
+		return nil, errors.Wrap(err, "failed to parse kustomization")
+	}
+	if document.Kind == 0 {
+		// empty kustomization
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	content := document.Content[0]
+	if content.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("kustomization is not a map")
+	}
+
+	images := mappingValue(content, "images")
+	if images == nil || (images.Kind == yaml.ScalarNode && images.Tag == "!!null") {
+		images = setMappingValue(content, "images", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"})
+	}
+	if images.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("images of kustomization is not a list")
+	}
+
+	updated := false
+	for _, entry := range images.Content {
+		if entry.Kind != yaml.MappingNode {
+			continue
+		}
+		name := scalarValue(entry, "name")
+		newName := scalarValue(entry, "newName")
+		if name != image.Name && name != image.NewName && (len(newName) == 0 || newName != image.NewName) {
+			continue
+		}
+		setImage(entry, name, image)
+		updated = true
+	}
+	if !updated {
+		entry := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(entry, "name", stringNode(image.Name))
+		setImage(entry, image.Name, image)
+		images.Content = append(images.Content, entry)
+	}
+
+	var result bytes.Buffer
+	encoder := yaml.NewEncoder(&result)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, errors.Wrap(err, "failed to write kustomization")
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to write kustomization")
+	}
+	return result.Bytes(), nil
+}
+
+func setImage(entry *yaml.Node, name string, image KustomizeImage) {
+	if len(image.NewName) > 0 && image.NewName != name {
+		setMappingValue(entry, "newName", stringNode(image.NewName))
+	} else {
+		removeMappingValue(entry, "newName")
+	}
+	if len(image.NewTag) > 0 {
+		setMappingValue(entry, "newTag", stringNode(image.NewTag))
+	} else {
+		removeMappingValue(entry, "newTag")
+	}
+	// a digest takes precedence over the tag
+	removeMappingValue(entry, "digest")
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// mappingValue returns the value node of the key within the mapping node, the content of a mapping node alternates between keys and values
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalarValue(mapping *yaml.Node, key string) string {
+	if value := mappingValue(mapping, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// setMappingValue replaces the value of the key while keeping the comments of the existing value, or appends the key
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			existing := mapping.Content[i+1]
+			value.HeadComment, value.LineComment, value.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+			if value.Kind == yaml.ScalarNode && existing.Kind == yaml.ScalarNode {
+				value.Style = existing.Style
+			}
+			mapping.Content[i+1] = value
+			return value
+		}
+	}
+	mapping.Content = append(mapping.Content, stringNode(key), value)
+	return value
+}
+
+func removeMappingValue(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
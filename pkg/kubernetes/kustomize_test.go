@@ -0,0 +1,91 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetKustomizeImage(t *testing.T) {
+	t.Parallel()
+	image := KustomizeImage{Name: "app", NewName: "my.registry/app", NewTag: "1.2.3"}
+
+	t.Run("update matching entries", func(t *testing.T) {
+		t.Parallel()
+		kustomization := `# production overlay
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../../base # shared base
+images:
+# the application image
+- name: app
+  newTag: 1.2.2 # updated by the pipeline
+- name: other
+  newTag: "2"
+- name: my.registry/app
+  digest: sha256:abc
+- name: legacy
+  newName: my.registry/app
+  newTag: "1.0"
+namespace: production
+`
+		result, err := SetKustomizeImage([]byte(kustomization), image)
+
+		require.NoError(t, err)
+		assert.Equal(t, `# production overlay
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - ../../base # shared base
+images:
+  # the application image
+  - name: app
+    newTag: 1.2.3 # updated by the pipeline
+    newName: my.registry/app
+  - name: other
+    newTag: "2"
+  - name: my.registry/app
+    newTag: 1.2.3
+  - name: legacy
+    newName: my.registry/app
+    newTag: "1.2.3"
+namespace: production
+`, string(result))
+	})
+
+	t.Run("add entry", func(t *testing.T) {
+		t.Parallel()
+		result, err := SetKustomizeImage([]byte("resources:\n- deployment.yaml\n"), KustomizeImage{Name: "app", NewName: "app", NewTag: "1337"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "resources:\n  - deployment.yaml\nimages:\n  - name: app\n    newTag: \"1337\"\n", string(result))
+	})
+
+	t.Run("add entry to empty kustomization", func(t *testing.T) {
+		t.Parallel()
+		result, err := SetKustomizeImage([]byte(""), KustomizeImage{Name: "app", NewTag: "1337"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "images:\n  - name: app\n    newTag: \"1337\"\n", string(result))
+	})
+
+	t.Run("invalid kustomization", func(t *testing.T) {
+		t.Parallel()
+		_, err := SetKustomizeImage([]byte("resources: [deployment.yaml"), image)
+		assert.Contains(t, err.Error(), "failed to parse kustomization")
+	})
+
+	t.Run("invalid images", func(t *testing.T) {
+		t.Parallel()
+		_, err := SetKustomizeImage([]byte("images: app"), image)
+		assert.EqualError(t, err, "images of kustomization is not a list")
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		t.Parallel()
+		_, err := SetKustomizeImage([]byte("- app"), image)
+		assert.EqualError(t, err, "kustomization is not a map")
+	})
+}
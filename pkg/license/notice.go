@@ -0,0 +1,76 @@
+package license
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const noticeSeparator = "--------------------------------------------------------------------------------"
+
+// Notice creates the attribution file of the product listing the third-party components with their licenses and copyrights.
+// Components reported several times, e.g. by multiple projects, are listed once.
+func Notice(product string, results []Result) []byte {
+	sorted := append([]Result{}, results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		nameI, nameJ := strings.ToLower(sorted[i].Component.Name), strings.ToLower(sorted[j].Component.Name)
+		if nameI != nameJ {
+			return nameI < nameJ
+		}
+		return sorted[i].Component.Version < sorted[j].Component.Version
+	})
+
+	var notice strings.Builder
+	title := "Third-party software notices"
+	if len(product) > 0 {
+		title = fmt.Sprintf("%v - %v", product, title)
+	}
+	notice.WriteString(title + "\n\n")
+	notice.WriteString("This product includes the following third-party components.\n")
+
+	listed := map[string]bool{}
+	for _, result := range sorted {
+		component := result.Component
+		key := strings.ToLower(component.Name) + "@" + component.Version
+		if listed[key] {
+			continue
+		}
+		listed[key] = true
+
+		notice.WriteString("\n" + noticeSeparator + "\n")
+		notice.WriteString(strings.TrimSpace(component.Name+" "+component.Version) + "\n\n")
+		notice.WriteString("License: " + licenseInfo(result) + "\n")
+		for _, url := range unique(component.LicenseURLs) {
+			notice.WriteString("License URL: " + url + "\n")
+		}
+		if copyrights := unique(component.Copyrights); len(copyrights) > 0 {
+			notice.WriteString("\n" + strings.Join(copyrights, "\n") + "\n")
+		}
+	}
+	return []byte(notice.String())
+}
+
+// licenseInfo states the licenses the component is used under and, for dual licenses, the declared expression
+func licenseInfo(result Result) string {
+	if len(result.Licenses) == 0 {
+		return "unknown"
+	}
+	info := strings.Join(result.Licenses, " AND ")
+	if declared := result.Component.expression().String(); declared != info {
+		info += fmt.Sprintf(" (chosen from %v)", declared)
+	}
+	return info
+}
+
+func unique(values []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) > 0 && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotice(t *testing.T) {
+	results := []Result{
+		{
+			Component: Component{Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}, Copyrights: []string{"Copyright OpenJS Foundation and other contributors"}},
+			Decision:  Allow,
+			Licenses:  []string{"MIT"},
+		},
+		{
+			Component: Component{Name: "jackson-databind", Version: "2.13.1", Licenses: []string{"Apache 2.0"}, LicenseURLs: []string{"http://apache.org/licenses/LICENSE-2.0", "http://apache.org/licenses/LICENSE-2.0"}},
+			Decision:  Allow,
+			Licenses:  []string{"Apache-2.0"},
+		},
+		{
+			Component: Component{Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+			Decision:  Allow,
+			Licenses:  []string{"MIT"},
+		},
+		{
+			Component: Component{Name: "dual", Version: "1.0.0", Licenses: []string{"GPL-3.0-only OR MIT"}, Copyrights: []string{"Copyright 2021 Jane Doe", " Copyright 2021 Jane Doe "}},
+			Decision:  Allow,
+			Licenses:  []string{"MIT"},
+		},
+		{
+			Component: Component{Name: "unknown"},
+			Decision:  Review,
+		},
+	}
+
+	notice := Notice("My Product", results)
+
+	assert.Equal(t, `My Product - Third-party software notices
+
+This product includes the following third-party components.
+
+--------------------------------------------------------------------------------
+dual 1.0.0
+
+License: MIT (chosen from GPL-3.0-only OR MIT)
+
+Copyright 2021 Jane Doe
+
+--------------------------------------------------------------------------------
+jackson-databind 2.13.1
+
+License: Apache-2.0
+License URL: http://apache.org/licenses/LICENSE-2.0
+
+--------------------------------------------------------------------------------
+lodash 4.17.21
+
+License: MIT
+
+Copyright OpenJS Foundation and other contributors
+
+--------------------------------------------------------------------------------
+unknown
+
+License: unknown
+`, string(notice))
+}
@@ -0,0 +1,182 @@
+package license
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Decision is the result of evaluating a license against the policy
+type Decision string
+
+// Decisions of a license policy
+const (
+	Allow  Decision = "allow"
+	Review Decision = "review"
+	Deny   Decision = "deny"
+)
+
+func (d Decision) rank() int {
+	switch d {
+	case Allow:
+		return 0
+	case Review:
+		return 1
+	}
+	return 2
+}
+
+// Policy lists the licenses which are allowed, require a review by the legal department or are denied.
+// Entries are SPDX identifiers or patterns like 'GPL-*', entries without wildcard take precedence over patterns.
+type Policy struct {
+	Allow  []string `json:"allow,omitempty"`
+	Review []string `json:"review,omitempty"`
+	Deny   []string `json:"deny,omitempty"`
+	// Default applies to licenses not listed in the policy and to components without license information
+	Default Decision `json:"default,omitempty"`
+	// Exceptions allow single components regardless of their license, e.g. after a legal review
+	Exceptions []Exception `json:"exceptions,omitempty"`
+}
+
+// Exception allows a component, optionally only in a specific version
+type Exception struct {
+	Component     string `json:"component"`
+	Version       string `json:"version,omitempty"`
+	Justification string `json:"justification"`
+}
+
+// ReadPolicy parses and validates a license policy
+func ReadPolicy(content []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.Unmarshal(content, policy); err != nil {
+		return nil, errors.Wrap(err, "failed to parse license policy")
+	}
+	switch policy.Default {
+	case "":
+		policy.Default = Review
+	case Allow, Review, Deny:
+	default:
+		return nil, fmt.Errorf("invalid default decision '%v', expected one of allow, review, deny", policy.Default)
+	}
+	for _, entry := range append(append(append([]string{}, policy.Allow...), policy.Review...), policy.Deny...) {
+		if _, err := path.Match(entry, ""); err != nil {
+			return nil, fmt.Errorf("invalid license pattern '%v'", entry)
+		}
+	}
+	for i, exception := range policy.Exceptions {
+		if len(exception.Component) == 0 || len(exception.Justification) == 0 {
+			return nil, fmt.Errorf("exception %v (%v) requires the fields component and justification", i+1, exception.Component)
+		}
+	}
+	return policy, nil
+}
+
+// Decide returns the decision on a single license
+func (p *Policy) Decide(license string) Decision {
+	decision, _ := p.match(license)
+	return decision
+}
+
+// match returns the decision on a license and whether the license is listed in the policy
+func (p *Policy) match(license string) (Decision, bool) {
+	lists := []struct {
+		decision Decision
+		entries  []string
+	}{{Deny, p.Deny}, {Review, p.Review}, {Allow, p.Allow}}
+	for _, list := range lists {
+		for _, entry := range list.entries {
+			if strings.EqualFold(entry, license) {
+				return list.decision, true
+			}
+		}
+	}
+	for _, list := range lists {
+		for _, entry := range list.entries {
+			if matched, _ := path.Match(strings.ToLower(entry), strings.ToLower(license)); matched {
+				return list.decision, true
+			}
+		}
+	}
+	return p.defaultDecision(), false
+}
+
+func (p *Policy) defaultDecision() Decision {
+	if len(p.Default) == 0 {
+		return Review
+	}
+	return p.Default
+}
+
+// exception returns the exception of a component
+func (p *Policy) exception(component Component) *Exception {
+	for i, exception := range p.Exceptions {
+		if strings.EqualFold(exception.Component, component.Name) && (len(exception.Version) == 0 || exception.Version == component.Version) {
+			return &p.Exceptions[i]
+		}
+	}
+	return nil
+}
+
+// Component is a component of the shipped product with its licenses as reported by a scan tool
+type Component struct {
+	Name    string
+	Version string
+	// Licenses are SPDX expressions or license names, all of them apply to the component
+	Licenses    []string
+	LicenseURLs []string
+	Copyrights  []string
+}
+
+// expression combines the licenses of the component, a component without licenses results in an empty expression
+func (c Component) expression() Expression {
+	if len(c.Licenses) == 1 {
+		return Parse(c.Licenses[0])
+	}
+	operands := []Expression{}
+	for _, license := range c.Licenses {
+		operands = append(operands, Parse(license))
+	}
+	return &compound{operator: "AND", operands: operands}
+}
+
+// Result is the decision on the licenses of a component
+type Result struct {
+	Component Component
+	Decision  Decision
+	// Licenses are the licenses the decision is based on, i.e. for dual licenses the most permissive alternative
+	Licenses      []string
+	Justification string
+}
+
+// Evaluate decides on the licenses of the components
+func (p *Policy) Evaluate(components []Component) []Result {
+	results := []Result{}
+	for _, component := range components {
+		result := Result{Component: component}
+		if len(component.Licenses) == 0 {
+			result.Decision, result.Justification = p.defaultDecision(), "no license information"
+		} else {
+			e := component.expression().evaluate(p)
+			result.Decision, result.Licenses = e.decision, e.licenses
+		}
+		if exception := p.exception(component); exception != nil {
+			result.Decision, result.Justification = Allow, exception.Justification
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Count returns the number of results with the decision
+func Count(results []Result, decision Decision) int {
+	count := 0
+	for _, result := range results {
+		if result.Decision == decision {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,121 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+allow:
+  - Apache-2.0
+  - MIT
+  - BSD-*
+  - GPL-2.0-only WITH Classpath-exception-2.0
+review:
+  - LGPL-*
+  - MPL-2.0
+deny:
+  - GPL-*
+  - AGPL-*
+default: review
+exceptions:
+  - component: mysql-connector-java
+    version: 8.0.28
+    justification: approved by legal for internal tooling
+`
+
+func TestReadPolicy(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		policy, err := ReadPolicy([]byte(testPolicy))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Apache-2.0", "MIT", "BSD-*", "GPL-2.0-only WITH Classpath-exception-2.0"}, policy.Allow)
+		assert.Equal(t, Review, policy.Default)
+		assert.Equal(t, []Exception{{Component: "mysql-connector-java", Version: "8.0.28", Justification: "approved by legal for internal tooling"}}, policy.Exceptions)
+	})
+
+	t.Run("default decision", func(t *testing.T) {
+		policy, err := ReadPolicy([]byte("allow: [MIT]"))
+
+		require.NoError(t, err)
+		assert.Equal(t, Review, policy.Default)
+	})
+
+	t.Run("invalid default decision", func(t *testing.T) {
+		_, err := ReadPolicy([]byte("default: reject"))
+
+		assert.EqualError(t, err, "invalid default decision 'reject', expected one of allow, review, deny")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := ReadPolicy([]byte("deny: ['GPL-[']"))
+
+		assert.EqualError(t, err, "invalid license pattern 'GPL-['")
+	})
+
+	t.Run("exception without justification", func(t *testing.T) {
+		_, err := ReadPolicy([]byte("exceptions: [{component: lodash}]"))
+
+		assert.EqualError(t, err, "exception 1 (lodash) requires the fields component and justification")
+	})
+}
+
+func TestDecide(t *testing.T) {
+	policy, err := ReadPolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	for license, expected := range map[string]Decision{
+		"MIT":           Allow,
+		"mit":           Allow,
+		"BSD-3-Clause":  Allow,
+		"LGPL-2.1-only": Review,
+		"GPL-3.0-only":  Deny,
+		"AGPL-3.0-only": Deny,
+		"WTFPL":         Review,
+		"GPL-2.0-only WITH Classpath-exception-2.0": Allow,
+	} {
+		assert.Equal(t, expected, policy.Decide(license), license)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy, err := ReadPolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	results := policy.Evaluate([]Component{
+		{Name: "jackson-databind", Version: "2.13.1", Licenses: []string{"Apache 2.0"}},
+		{Name: "dual-licensed", Version: "1.0.0", Licenses: []string{"GPL-3.0-only OR MIT"}},
+		{Name: "review-or-deny", Version: "1.0.0", Licenses: []string{"GPL-3.0-only OR LGPL-3.0-only"}},
+		{Name: "combined", Version: "1.0.0", Licenses: []string{"MIT", "GPL-2.0-or-later"}},
+		{Name: "openjdk", Version: "11", Licenses: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}},
+		{Name: "readline", Version: "8.1", Licenses: []string{"GPL-3.0-only WITH GCC-exception-3.1"}},
+		{Name: "unknown", Version: "0.1.0"},
+		{Name: "mysql-connector-java", Version: "8.0.28", Licenses: []string{"GPL-2.0-only"}},
+		{Name: "mysql-connector-java", Version: "8.0.30", Licenses: []string{"GPL-2.0-only"}},
+	})
+
+	expected := []struct {
+		decision      Decision
+		licenses      []string
+		justification string
+	}{
+		{decision: Allow, licenses: []string{"Apache-2.0"}},
+		{decision: Allow, licenses: []string{"MIT"}},
+		{decision: Review, licenses: []string{"LGPL-3.0-only"}},
+		{decision: Deny, licenses: []string{"MIT", "GPL-2.0-or-later"}},
+		{decision: Allow, licenses: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}},
+		{decision: Deny, licenses: []string{"GPL-3.0-only WITH GCC-exception-3.1"}},
+		{decision: Review, justification: "no license information"},
+		{decision: Allow, licenses: []string{"GPL-2.0-only"}, justification: "approved by legal for internal tooling"},
+		{decision: Deny, licenses: []string{"GPL-2.0-only"}},
+	}
+	require.Len(t, results, len(expected))
+	for i, result := range results {
+		assert.Equal(t, expected[i].decision, result.Decision, result.Component.Name)
+		assert.Equal(t, expected[i].licenses, result.Licenses, result.Component.Name)
+		assert.Equal(t, expected[i].justification, result.Justification, result.Component.Name)
+	}
+	assert.Equal(t, 3, Count(results, Deny))
+}
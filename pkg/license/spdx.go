@@ -0,0 +1,282 @@
+package license
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Expression is a parsed SPDX license expression, see https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+type Expression interface {
+	String() string
+	evaluate(policy *Policy) evaluation
+}
+
+// evaluation is the decision on an expression and the licenses it is based on
+type evaluation struct {
+	decision Decision
+	licenses []string
+}
+
+// licenseRef is a single license, optionally with an exception like 'GPL-2.0-only WITH Classpath-exception-2.0'
+type licenseRef struct {
+	id        string
+	exception string
+}
+
+func (l *licenseRef) String() string {
+	if len(l.exception) > 0 {
+		return l.id + " WITH " + l.exception
+	}
+	return l.id
+}
+
+// evaluate decides on the license including its exception, the license alone is decisive unless the exception is listed explicitly
+func (l *licenseRef) evaluate(policy *Policy) evaluation {
+	if len(l.exception) > 0 {
+		if decision, listed := policy.match(l.String()); listed {
+			return evaluation{decision: decision, licenses: []string{l.String()}}
+		}
+	}
+	decision, _ := policy.match(l.id)
+	return evaluation{decision: decision, licenses: []string{l.String()}}
+}
+
+// compound combines expressions with AND or OR
+type compound struct {
+	operator string
+	operands []Expression
+}
+
+func (c *compound) String() string {
+	operands := []string{}
+	for _, operand := range c.operands {
+		if _, ok := operand.(*compound); ok {
+			operands = append(operands, "("+operand.String()+")")
+		} else {
+			operands = append(operands, operand.String())
+		}
+	}
+	return strings.Join(operands, " "+c.operator+" ")
+}
+
+// evaluate returns the most permissive alternative of an OR expression respectively the most restrictive decision
+// of an AND expression, since all licenses combined with AND apply
+func (c *compound) evaluate(policy *Policy) evaluation {
+	var result *evaluation
+	for _, operand := range c.operands {
+		e := operand.evaluate(policy)
+		switch {
+		case result == nil:
+			result = &e
+		case c.operator == "OR" && e.decision.rank() < result.decision.rank():
+			result = &e
+		case c.operator == "AND":
+			if e.decision.rank() > result.decision.rank() {
+				result.decision = e.decision
+			}
+			result.licenses = append(result.licenses, e.licenses...)
+		}
+	}
+	return *result
+}
+
+var spdxTokens = regexp.MustCompile(`\(|\)|[^\s()]+`)
+
+// ParseExpression parses an SPDX license expression, the operators AND, OR and WITH are case-insensitive
+func ParseExpression(expression string) (Expression, error) {
+	p := &parser{tokens: spdxTokens.FindAllString(expression, -1)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression '%v': %v", expression, err)
+	}
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression '%v': unexpected '%v'", expression, p.tokens[p.position])
+	}
+	return result, nil
+}
+
+type parser struct {
+	tokens   []string
+	position int
+}
+
+func (p *parser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *parser) isOperator(operator string) bool {
+	return strings.EqualFold(p.peek(), operator)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	return p.parseCompound("OR", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	return p.parseCompound("AND", p.parseWith)
+}
+
+func (p *parser) parseCompound(operator string, parseOperand func() (Expression, error)) (Expression, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []Expression{operand}
+	for p.isOperator(operator) {
+		p.position++
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operand, nil
+	}
+	return &compound{operator: operator, operands: operands}, nil
+}
+
+func (p *parser) parseWith() (Expression, error) {
+	if p.peek() == "(" {
+		p.position++
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.position++
+		return expression, nil
+	}
+	id, err := p.parseID()
+	if err != nil {
+		return nil, err
+	}
+	license := &licenseRef{id: id}
+	if p.isOperator("WITH") {
+		p.position++
+		if license.exception, err = p.parseID(); err != nil {
+			return nil, err
+		}
+	}
+	return license, nil
+}
+
+func (p *parser) parseID() (string, error) {
+	token := p.peek()
+	if len(token) == 0 {
+		return "", fmt.Errorf("unexpected end")
+	}
+	for _, reserved := range []string{"(", ")", "AND", "OR", "WITH"} {
+		if strings.EqualFold(token, reserved) {
+			return "", fmt.Errorf("unexpected '%v'", token)
+		}
+	}
+	p.position++
+	return token, nil
+}
+
+var (
+	licenseNameSeparators = regexp.MustCompile(`[\s,_()"-]+`)
+	// version prefixes like 'v2', 'v 1.0', 'version 2.0' or 'GPLv3'
+	licenseVersionPrefix = regexp.MustCompile(`(^| |gpl)(?:version|v) ?(\d)`)
+	// GNU licenses like 'GNU GPL v2 or later', 'GNU Lesser General Public License v2.1' or 'GPL-2.0+'
+	gnuLicenseName = regexp.MustCompile(`^(?:gnu )?(affero |lesser |library )?(general public license|gpl|lgpl|agpl) ?(\d)(?:\.(\d))? ?(only|\+|or later|or any later version)?( with (?:the )?classpath exception(?: 2\.0)?)?$`)
+)
+
+// licenseNames maps the license names commonly reported by the scan tools, normalized by normalizeLicenseName, to SPDX identifiers
+var licenseNames = []struct {
+	name *regexp.Regexp
+	id   string
+}{
+	{name: regexp.MustCompile(`^(?:apache|asl)(?: software)?(?: license)? 2(?:\.0)?$`), id: "Apache-2.0"},
+	{name: regexp.MustCompile(`^(?:apache|asl)(?: software)?(?: license)? 1\.1$`), id: "Apache-1.1"},
+	{name: regexp.MustCompile(`^(?:mit|expat)(?: license)?$`), id: "MIT"},
+	{name: regexp.MustCompile(`^(?:bsd ?2(?: clause)?(?: simplified)?|simplified bsd|freebsd)(?: license)?$`), id: "BSD-2-Clause"},
+	{name: regexp.MustCompile(`^(?:bsd ?3(?: clause)?(?: new or revised)?|new bsd|revised bsd|modified bsd)(?: license)?$`), id: "BSD-3-Clause"},
+	{name: regexp.MustCompile(`^isc(?: license)?$`), id: "ISC"},
+	{name: regexp.MustCompile(`^(?:mozilla public license|mpl) 1\.1$`), id: "MPL-1.1"},
+	{name: regexp.MustCompile(`^(?:mozilla public license|mpl) 2(?:\.0)?$`), id: "MPL-2.0"},
+	{name: regexp.MustCompile(`^(?:eclipse public license|epl) 1(?:\.0)?$`), id: "EPL-1.0"},
+	{name: regexp.MustCompile(`^(?:eclipse public license|epl) 2(?:\.0)?$`), id: "EPL-2.0"},
+	{name: regexp.MustCompile(`^(?:common development and distribution license|cddl) 1(?:\.0)?$`), id: "CDDL-1.0"},
+	{name: regexp.MustCompile(`^(?:common development and distribution license|cddl) 1\.1$`), id: "CDDL-1.1"},
+}
+
+// normalizeLicenseName maps a license name to the SPDX identifier, nil is returned for unknown names
+func normalizeLicenseName(name string) *licenseRef {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "licence", "license")
+	normalized = strings.TrimSpace(licenseNameSeparators.ReplaceAllString(normalized, " "))
+	normalized = strings.TrimPrefix(normalized, "the ")
+	normalized = licenseVersionPrefix.ReplaceAllString(normalized, "$1 $2")
+	normalized = strings.ReplaceAll(normalized, "  ", " ")
+
+	for _, license := range licenseNames {
+		if license.name.MatchString(normalized) {
+			return &licenseRef{id: license.id}
+		}
+	}
+
+	match := gnuLicenseName.FindStringSubmatch(normalized)
+	if match == nil {
+		return nil
+	}
+	family := "GPL"
+	switch {
+	case strings.HasPrefix(match[1], "affero") || match[2] == "agpl":
+		family = "AGPL"
+	case len(match[1]) > 0 || match[2] == "lgpl":
+		family = "LGPL"
+	}
+	minor := match[4]
+	if len(minor) == 0 {
+		minor = "0"
+	}
+	suffix := "only"
+	if len(match[5]) > 0 && match[5] != "only" {
+		suffix = "or-later"
+	}
+	license := &licenseRef{id: fmt.Sprintf("%v-%v.%v-%v", family, match[3], minor, suffix)}
+	if len(match[6]) > 0 {
+		license.exception = "Classpath-exception-2.0"
+	}
+	return license
+}
+
+// normalize maps the names of all licenses contained in the expression to SPDX identifiers where possible
+func normalize(expression Expression) Expression {
+	switch e := expression.(type) {
+	case *licenseRef:
+		if license := normalizeLicenseName(e.id); license != nil && len(license.exception) == 0 {
+			license.exception = e.exception
+			return license
+		}
+	case *compound:
+		for i, operand := range e.operands {
+			e.operands[i] = normalize(operand)
+		}
+	}
+	return expression
+}
+
+// Parse returns the expression of a license as reported by a scan tool.
+// Well-known license names like 'Apache License, Version 2.0' or 'GNU GPL v2 or later' are mapped to SPDX identifiers,
+// names which are no valid SPDX expression are treated as a single license.
+func Parse(license string) Expression {
+	license = strings.TrimSpace(license)
+	if normalized := normalizeLicenseName(license); normalized != nil {
+		return normalized
+	}
+	if expression, err := ParseExpression(license); err == nil {
+		return normalize(expression)
+	}
+	return &licenseRef{id: license}
+}
@@ -0,0 +1,92 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	tt := []struct {
+		expression string
+		expected   string
+	}{
+		{expression: "MIT", expected: "MIT"},
+		{expression: "MIT OR Apache-2.0", expected: "MIT OR Apache-2.0"},
+		{expression: "mit or apache-2.0 and bsd-3-clause", expected: "mit OR (apache-2.0 AND bsd-3-clause)"},
+		{expression: "(MIT OR Apache-2.0) AND BSD-3-Clause", expected: "(MIT OR Apache-2.0) AND BSD-3-Clause"},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", expected: "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT"},
+		{expression: "LicenseRef-proprietary", expected: "LicenseRef-proprietary"},
+	}
+	for _, test := range tt {
+		t.Run(test.expression, func(t *testing.T) {
+			expression, err := ParseExpression(test.expression)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, expression.String())
+		})
+	}
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		_, err := ParseExpression("MIT OR")
+		assert.EqualError(t, err, "invalid license expression 'MIT OR': unexpected end")
+		_, err = ParseExpression("(MIT OR Apache-2.0")
+		assert.EqualError(t, err, "invalid license expression '(MIT OR Apache-2.0': missing ')'")
+		_, err = ParseExpression("Apache 2.0")
+		assert.EqualError(t, err, "invalid license expression 'Apache 2.0': unexpected '2.0'")
+		_, err = ParseExpression(" ")
+		assert.EqualError(t, err, "empty license expression")
+	})
+}
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		license  string
+		expected string
+	}{
+		// names as declared in Maven POMs, npm packages and reported by WhiteSource and Protecode
+		{license: "The Apache Software License, Version 2.0", expected: "Apache-2.0"},
+		{license: "Apache License, Version 2.0", expected: "Apache-2.0"},
+		{license: "Apache License 2.0", expected: "Apache-2.0"},
+		{license: "Apache 2.0", expected: "Apache-2.0"},
+		{license: "Apache-2.0", expected: "Apache-2.0"},
+		{license: "ASL 2.0", expected: "Apache-2.0"},
+		{license: "The MIT License", expected: "MIT"},
+		{license: "MIT License", expected: "MIT"},
+		{license: "BSD 3", expected: "BSD-3-Clause"},
+		{license: "BSD 3-Clause \"New\" or \"Revised\" License", expected: "BSD-3-Clause"},
+		{license: "BSD 2-Clause \"Simplified\" License", expected: "BSD-2-Clause"},
+		{license: "New BSD License", expected: "BSD-3-Clause"},
+		{license: "BSD-2-Clause", expected: "BSD-2-Clause"},
+		{license: "Eclipse Public License - v 1.0", expected: "EPL-1.0"},
+		{license: "Eclipse Public License v2.0", expected: "EPL-2.0"},
+		{license: "Mozilla Public License, Version 2.0", expected: "MPL-2.0"},
+		{license: "CDDL 1.1", expected: "CDDL-1.1"},
+		{license: "Common Development and Distribution License 1.0", expected: "CDDL-1.0"},
+		{license: "GPL 2.0", expected: "GPL-2.0-only"},
+		{license: "GPL-2.0", expected: "GPL-2.0-only"},
+		{license: "GPLv3", expected: "GPL-3.0-only"},
+		{license: "GPL-2.0+", expected: "GPL-2.0-or-later"},
+		{license: "GNU GPL v2 or later", expected: "GPL-2.0-or-later"},
+		{license: "GNU General Public License v3.0 or later", expected: "GPL-3.0-or-later"},
+		{license: "GNU General Public License, version 2, with the Classpath Exception", expected: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{license: "GNU Lesser General Public License v2.1 or later", expected: "LGPL-2.1-or-later"},
+		{license: "GNU Library General Public License v2", expected: "LGPL-2.0-only"},
+		{license: "LGPL 2.1", expected: "LGPL-2.1-only"},
+		{license: "LGPLv2.1+", expected: "LGPL-2.1-or-later"},
+		{license: "GNU Affero General Public License v3", expected: "AGPL-3.0-only"},
+		{license: "AGPL-3.0-or-later", expected: "AGPL-3.0-or-later"},
+		// expressions
+		{license: "MIT OR GPL-2.0-only", expected: "MIT OR GPL-2.0-only"},
+		{license: "(MIT OR GPL-2.0+) AND Apache-2.0", expected: "(MIT OR GPL-2.0-or-later) AND Apache-2.0"},
+		{license: "GPL-2.0 WITH Classpath-exception-2.0", expected: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		// unknown names are kept
+		{license: " Bouncy Castle Licence ", expected: "Bouncy Castle Licence"},
+		{license: "LicenseRef-proprietary", expected: "LicenseRef-proprietary"},
+	}
+	for _, test := range tt {
+		t.Run(test.license, func(t *testing.T) {
+			assert.Equal(t, test.expected, Parse(test.license).String())
+		})
+	}
+}
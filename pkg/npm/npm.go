@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 )
@@ -59,6 +61,7 @@ type Utils interface {
 	Chdir(path string) error
 	FileExists(filename string) (bool, error)
 	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
 	Getwd() (string, error)
 	Glob(pattern string) (matches []string, err error)
 
@@ -391,6 +394,8 @@ func (exec *Execute) CreateBOM(packageJSONFiles []string) error {
 				return err
 			}
 		}
+		// Provide the BOM in JSON format as well
+		return cyclonedx.ConvertToJSON("bom.xml", exec.Utils)
 	}
 	return nil
 }
@@ -345,6 +345,8 @@ func TestNpm(t *testing.T) {
 		utils.AddFile("package-lock.json", []byte("{}"))
 		utils.AddFile(filepath.Join("src", "package.json"), []byte("{\"scripts\": { \"ci-lint\": \"exit 0\" } }"))
 		utils.AddFile(filepath.Join("src", "package-lock.json"), []byte("{}"))
+		// simulate the BOM written by cyclonedx-bom
+		utils.AddFile("bom.xml", []byte(`<bom xmlns="http://cyclonedx.org/schema/bom/1.2" version="1"><components><component type="library"><name>lodash</name><version>4.17.21</version></component></components></bom>`))
 
 		options := ExecutorOptions{}
 		options.DefaultNpmRegistry = "foo.bar"
@@ -363,6 +365,7 @@ func TestNpm(t *testing.T) {
 				assert.Equal(t, mock.ExecCall{Exec: "npx", Params: []string{"cyclonedx-bom", "src", "--append", "bom.xml",
 					"--schema", "1.2", "--include-license-text", "false", "--include-dev", "false", "--output", "bom.xml"}}, utils.execRunner.Calls[2])
 			}
+			assert.True(t, utils.HasWrittenFile("bom.json"))
 		}
 	})
 }
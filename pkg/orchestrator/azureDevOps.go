@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"os"
 	"strings"
+	"time"
 )
 
 type AzureDevOpsConfigProvider struct{}
@@ -24,6 +25,30 @@ func (a *AzureDevOpsConfigProvider) GetRepoUrl() string {
 	return os.Getenv("BUILD_REPOSITORY_URI")
 }
 
+func (a *AzureDevOpsConfigProvider) GetJobName() string {
+	return os.Getenv("BUILD_DEFINITIONNAME")
+}
+
+func (a *AzureDevOpsConfigProvider) GetJobUrl() string {
+	return os.Getenv("SYSTEM_TEAMFOUNDATIONCOLLECTIONURI") + os.Getenv("SYSTEM_TEAMPROJECT") + "/_build?definitionId=" + os.Getenv("SYSTEM_DEFINITIONID")
+}
+
+func (a *AzureDevOpsConfigProvider) GetBuildId() string {
+	return os.Getenv("BUILD_BUILDID")
+}
+
+func (a *AzureDevOpsConfigProvider) GetStageName() string {
+	return os.Getenv("SYSTEM_STAGEDISPLAYNAME")
+}
+
+func (a *AzureDevOpsConfigProvider) GetActor() string {
+	return os.Getenv("BUILD_REQUESTEDFOREMAIL")
+}
+
+func (a *AzureDevOpsConfigProvider) GetPipelineStartTime() time.Time {
+	return parseTimeFromEnv("SYSTEM_PIPELINESTARTTIME")
+}
+
 func (a *AzureDevOpsConfigProvider) GetPullRequestConfig() PullRequestConfig {
 	return PullRequestConfig{
 		Branch: os.Getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"),
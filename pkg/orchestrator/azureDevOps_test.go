@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +14,7 @@ func TestAzure(t *testing.T) {
 		os.Clearenv()
 		os.Setenv("AZURE_HTTP_USER_AGENT", "FOO BAR BAZ")
 		os.Setenv("BUILD_SOURCEBRANCH", "refs/heads/feat/test-azure")
-		os.Setenv("SYSTEM_TEAMFOUNDATIONCOLLECTIONURI", "https://pogo.foo")
+		os.Setenv("SYSTEM_TEAMFOUNDATIONCOLLECTIONURI", "https://pogo.foo/")
 		os.Setenv("SYSTEM_TEAMPROJECT", "bar")
 		os.Setenv("BUILD_BUILDID", "42")
 		os.Setenv("BUILD_SOURCEVERSION", "abcdef42713")
@@ -23,7 +24,7 @@ func TestAzure(t *testing.T) {
 
 		assert.False(t, p.IsPullRequest())
 		assert.Equal(t, "feat/test-azure", p.GetBranch())
-		assert.Equal(t, "https://pogo.foobar/_build/results?buildId=42", p.GetBuildUrl())
+		assert.Equal(t, "https://pogo.foo/bar/_build/results?buildId=42", p.GetBuildUrl())
 		assert.Equal(t, "abcdef42713", p.GetCommit())
 		assert.Equal(t, "github.com/foo/bar", p.GetRepoUrl())
 	})
@@ -55,4 +56,26 @@ func TestAzure(t *testing.T) {
 
 		assert.Equal(t, Orchestrator(Unknown), o)
 	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("BUILD_DEFINITIONNAME", "foo-bar")
+		os.Setenv("SYSTEM_TEAMFOUNDATIONCOLLECTIONURI", "https://pogo.foo/")
+		os.Setenv("SYSTEM_TEAMPROJECT", "bar")
+		os.Setenv("SYSTEM_DEFINITIONID", "7")
+		os.Setenv("BUILD_BUILDID", "42")
+		os.Setenv("SYSTEM_STAGEDISPLAYNAME", "Build")
+		os.Setenv("BUILD_REQUESTEDFOREMAIL", "jdoe@example.com")
+		os.Setenv("SYSTEM_PIPELINESTARTTIME", "2021-08-11 09:27:58+02:00")
+
+		p := AzureDevOpsConfigProvider{}
+
+		assert.Equal(t, "foo-bar", p.GetJobName())
+		assert.Equal(t, "https://pogo.foo/bar/_build?definitionId=7", p.GetJobUrl())
+		assert.Equal(t, "42", p.GetBuildId())
+		assert.Equal(t, "Build", p.GetStageName())
+		assert.Equal(t, "jdoe@example.com", p.GetActor())
+		assert.Equal(t, time.Date(2021, 8, 11, 7, 27, 58, 0, time.UTC), p.GetPipelineStartTime())
+	})
 }
@@ -0,0 +1,67 @@
+package orchestrator
+
+import (
+	"os"
+	"time"
+)
+
+type BitbucketPipelinesConfigProvider struct{}
+
+func (b *BitbucketPipelinesConfigProvider) GetBranch() string {
+	return os.Getenv("BITBUCKET_BRANCH")
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetBuildUrl() string {
+	return b.GetRepoUrl() + "/addon/pipelines/home#!/results/" + os.Getenv("BITBUCKET_BUILD_NUMBER")
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetCommit() string {
+	return os.Getenv("BITBUCKET_COMMIT")
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetRepoUrl() string {
+	return os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN")
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetJobName() string {
+	return os.Getenv("BITBUCKET_REPO_FULL_NAME")
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetJobUrl() string {
+	return b.GetRepoUrl() + "/addon/pipelines/home"
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetBuildId() string {
+	return os.Getenv("BITBUCKET_BUILD_NUMBER")
+}
+
+// GetStageName returns the deployment environment since Bitbucket Pipelines does not expose step names
+func (b *BitbucketPipelinesConfigProvider) GetStageName() string {
+	return os.Getenv("BITBUCKET_DEPLOYMENT_ENVIRONMENT")
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetActor() string {
+	return os.Getenv("BITBUCKET_STEP_TRIGGERER_UUID")
+}
+
+// GetPipelineStartTime returns a zero time since Bitbucket Pipelines does not expose the start time via environment variables
+func (b *BitbucketPipelinesConfigProvider) GetPipelineStartTime() time.Time {
+	return time.Time{}
+}
+
+func (b *BitbucketPipelinesConfigProvider) GetPullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: os.Getenv("BITBUCKET_BRANCH"),
+		Base:   os.Getenv("BITBUCKET_PR_DESTINATION_BRANCH"),
+		Key:    os.Getenv("BITBUCKET_PR_ID"),
+	}
+}
+
+func (b *BitbucketPipelinesConfigProvider) IsPullRequest() bool {
+	return truthy("BITBUCKET_PR_ID")
+}
+
+func isBitbucketPipelines() bool {
+	envVars := []string{"BITBUCKET_BUILD_NUMBER", "BITBUCKET_PIPELINE_UUID"}
+	return areIndicatingEnvVarsSet(envVars)
+}
@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitbucketPipelines(t *testing.T) {
+	t.Run("BranchBuild", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("BITBUCKET_BUILD_NUMBER", "42")
+		os.Setenv("BITBUCKET_BRANCH", "feat/test-bitbucket")
+		os.Setenv("BITBUCKET_COMMIT", "abcdef42713")
+		os.Setenv("BITBUCKET_GIT_HTTP_ORIGIN", "http://bitbucket.org/foo/bar")
+
+		p, _ := NewOrchestratorSpecificConfigProvider()
+
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "http://bitbucket.org/foo/bar/addon/pipelines/home#!/results/42", p.GetBuildUrl())
+		assert.Equal(t, "feat/test-bitbucket", p.GetBranch())
+		assert.Equal(t, "abcdef42713", p.GetCommit())
+		assert.Equal(t, "http://bitbucket.org/foo/bar", p.GetRepoUrl())
+	})
+
+	t.Run("PR", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("BITBUCKET_BRANCH", "feat/test-bitbucket")
+		os.Setenv("BITBUCKET_PR_DESTINATION_BRANCH", "main")
+		os.Setenv("BITBUCKET_PR_ID", "42")
+
+		p := BitbucketPipelinesConfigProvider{}
+		c := p.GetPullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-bitbucket", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "42", c.Key)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("BITBUCKET_REPO_FULL_NAME", "foo/bar")
+		os.Setenv("BITBUCKET_GIT_HTTP_ORIGIN", "http://bitbucket.org/foo/bar")
+		os.Setenv("BITBUCKET_BUILD_NUMBER", "42")
+		os.Setenv("BITBUCKET_DEPLOYMENT_ENVIRONMENT", "production")
+		os.Setenv("BITBUCKET_STEP_TRIGGERER_UUID", "{1234}")
+
+		p := BitbucketPipelinesConfigProvider{}
+
+		assert.Equal(t, "foo/bar", p.GetJobName())
+		assert.Equal(t, "http://bitbucket.org/foo/bar/addon/pipelines/home", p.GetJobUrl())
+		assert.Equal(t, "42", p.GetBuildId())
+		assert.Equal(t, "production", p.GetStageName())
+		assert.Equal(t, "{1234}", p.GetActor())
+		assert.True(t, p.GetPipelineStartTime().IsZero())
+	})
+}
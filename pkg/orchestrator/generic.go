@@ -0,0 +1,68 @@
+package orchestrator
+
+import (
+	"os"
+	"time"
+)
+
+// GenericConfigProvider reads the pipeline run information from PIPER_CI_* environment variables.
+// It allows any CI system which is not supported natively to provide the information to the steps.
+type GenericConfigProvider struct{}
+
+func (g *GenericConfigProvider) GetBranch() string {
+	return os.Getenv("PIPER_CI_BRANCH")
+}
+
+func (g *GenericConfigProvider) GetBuildUrl() string {
+	return os.Getenv("PIPER_CI_BUILD_URL")
+}
+
+func (g *GenericConfigProvider) GetCommit() string {
+	return os.Getenv("PIPER_CI_COMMIT")
+}
+
+func (g *GenericConfigProvider) GetRepoUrl() string {
+	return os.Getenv("PIPER_CI_REPO_URL")
+}
+
+func (g *GenericConfigProvider) GetJobName() string {
+	return os.Getenv("PIPER_CI_JOB_NAME")
+}
+
+func (g *GenericConfigProvider) GetJobUrl() string {
+	return os.Getenv("PIPER_CI_JOB_URL")
+}
+
+func (g *GenericConfigProvider) GetBuildId() string {
+	return os.Getenv("PIPER_CI_BUILD_ID")
+}
+
+func (g *GenericConfigProvider) GetStageName() string {
+	return os.Getenv("PIPER_CI_STAGE_NAME")
+}
+
+func (g *GenericConfigProvider) GetActor() string {
+	return os.Getenv("PIPER_CI_ACTOR")
+}
+
+// GetPipelineStartTime expects PIPER_CI_PIPELINE_START_TIME in RFC 3339 format
+func (g *GenericConfigProvider) GetPipelineStartTime() time.Time {
+	return parseTimeFromEnv("PIPER_CI_PIPELINE_START_TIME")
+}
+
+func (g *GenericConfigProvider) GetPullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: os.Getenv("PIPER_CI_PULL_REQUEST_BRANCH"),
+		Base:   os.Getenv("PIPER_CI_PULL_REQUEST_BASE"),
+		Key:    os.Getenv("PIPER_CI_PULL_REQUEST_KEY"),
+	}
+}
+
+func (g *GenericConfigProvider) IsPullRequest() bool {
+	return truthy("PIPER_CI_PULL_REQUEST_KEY")
+}
+
+func isGeneric() bool {
+	envVars := []string{"PIPER_CI", "PIPER_CI_BRANCH", "PIPER_CI_COMMIT", "PIPER_CI_PULL_REQUEST_KEY"}
+	return areIndicatingEnvVarsSet(envVars)
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneric(t *testing.T) {
+	t.Run("BranchBuild", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_CI_BRANCH", "feat/test-generic")
+		os.Setenv("PIPER_CI_BUILD_URL", "https://ci.example.com/foo/bar/42")
+		os.Setenv("PIPER_CI_COMMIT", "abcdef42713")
+		os.Setenv("PIPER_CI_REPO_URL", "https://github.com/foo/bar")
+
+		p, _ := NewOrchestratorSpecificConfigProvider()
+
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "https://ci.example.com/foo/bar/42", p.GetBuildUrl())
+		assert.Equal(t, "feat/test-generic", p.GetBranch())
+		assert.Equal(t, "abcdef42713", p.GetCommit())
+		assert.Equal(t, "https://github.com/foo/bar", p.GetRepoUrl())
+	})
+
+	t.Run("PR", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_CI_PULL_REQUEST_BRANCH", "feat/test-generic")
+		os.Setenv("PIPER_CI_PULL_REQUEST_BASE", "main")
+		os.Setenv("PIPER_CI_PULL_REQUEST_KEY", "42")
+
+		p, _ := NewOrchestratorSpecificConfigProvider()
+		c := p.GetPullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-generic", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "42", c.Key)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_CI_JOB_NAME", "foo/bar")
+		os.Setenv("PIPER_CI_JOB_URL", "https://ci.example.com/foo/bar")
+		os.Setenv("PIPER_CI_BUILD_ID", "42")
+		os.Setenv("PIPER_CI_STAGE_NAME", "Build")
+		os.Setenv("PIPER_CI_ACTOR", "jdoe")
+		os.Setenv("PIPER_CI_PIPELINE_START_TIME", "2021-08-11T11:27:58+02:00")
+
+		p := GenericConfigProvider{}
+
+		assert.Equal(t, "foo/bar", p.GetJobName())
+		assert.Equal(t, "https://ci.example.com/foo/bar", p.GetJobUrl())
+		assert.Equal(t, "42", p.GetBuildId())
+		assert.Equal(t, "Build", p.GetStageName())
+		assert.Equal(t, "jdoe", p.GetActor())
+		assert.Equal(t, time.Date(2021, 8, 11, 9, 27, 58, 0, time.UTC), p.GetPipelineStartTime())
+	})
+}
@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"os"
 	"strings"
+	"time"
 )
 
 type GitHubActionsConfigProvider struct{}
@@ -20,7 +21,32 @@ func (g *GitHubActionsConfigProvider) GetCommit() string {
 }
 
 func (g *GitHubActionsConfigProvider) GetRepoUrl() string {
-	return os.Getenv("GITHUB_SERVER_URL") + os.Getenv("GITHUB_REPOSITORY")
+	return strings.TrimSuffix(os.Getenv("GITHUB_SERVER_URL"), "/") + "/" + os.Getenv("GITHUB_REPOSITORY")
+}
+
+func (g *GitHubActionsConfigProvider) GetJobName() string {
+	return os.Getenv("GITHUB_WORKFLOW")
+}
+
+func (g *GitHubActionsConfigProvider) GetJobUrl() string {
+	return g.GetRepoUrl() + "/actions"
+}
+
+func (g *GitHubActionsConfigProvider) GetBuildId() string {
+	return os.Getenv("GITHUB_RUN_ID")
+}
+
+func (g *GitHubActionsConfigProvider) GetStageName() string {
+	return os.Getenv("GITHUB_JOB")
+}
+
+func (g *GitHubActionsConfigProvider) GetActor() string {
+	return os.Getenv("GITHUB_ACTOR")
+}
+
+// GetPipelineStartTime returns a zero time since GitHub Actions does not expose the start time via environment variables
+func (g *GitHubActionsConfigProvider) GetPipelineStartTime() time.Time {
+	return time.Time{}
 }
 
 func (g *GitHubActionsConfigProvider) GetPullRequestConfig() PullRequestConfig {
@@ -43,4 +43,24 @@ func TestGitHubActions(t *testing.T) {
 		assert.Equal(t, "main", c.Base)
 		assert.Equal(t, "42", c.Key)
 	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("GITHUB_WORKFLOW", "CI")
+		os.Setenv("GITHUB_SERVER_URL", "https://github.com")
+		os.Setenv("GITHUB_REPOSITORY", "foo/bar")
+		os.Setenv("GITHUB_RUN_ID", "42")
+		os.Setenv("GITHUB_JOB", "build")
+		os.Setenv("GITHUB_ACTOR", "jdoe")
+
+		p := GitHubActionsConfigProvider{}
+
+		assert.Equal(t, "CI", p.GetJobName())
+		assert.Equal(t, "https://github.com/foo/bar/actions", p.GetJobUrl())
+		assert.Equal(t, "42", p.GetBuildId())
+		assert.Equal(t, "build", p.GetStageName())
+		assert.Equal(t, "jdoe", p.GetActor())
+		assert.True(t, p.GetPipelineStartTime().IsZero())
+	})
 }
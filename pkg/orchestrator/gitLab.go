@@ -0,0 +1,65 @@
+package orchestrator
+
+import (
+	"os"
+	"time"
+)
+
+type GitLabConfigProvider struct{}
+
+func (g *GitLabConfigProvider) GetBranch() string {
+	return os.Getenv("CI_COMMIT_REF_NAME")
+}
+
+func (g *GitLabConfigProvider) GetBuildUrl() string {
+	return os.Getenv("CI_PIPELINE_URL")
+}
+
+func (g *GitLabConfigProvider) GetCommit() string {
+	return os.Getenv("CI_COMMIT_SHA")
+}
+
+func (g *GitLabConfigProvider) GetRepoUrl() string {
+	return os.Getenv("CI_PROJECT_URL")
+}
+
+func (g *GitLabConfigProvider) GetJobName() string {
+	return os.Getenv("CI_PROJECT_PATH")
+}
+
+func (g *GitLabConfigProvider) GetJobUrl() string {
+	return g.GetRepoUrl() + "/-/pipelines"
+}
+
+func (g *GitLabConfigProvider) GetBuildId() string {
+	return os.Getenv("CI_PIPELINE_ID")
+}
+
+func (g *GitLabConfigProvider) GetStageName() string {
+	return os.Getenv("CI_JOB_STAGE")
+}
+
+func (g *GitLabConfigProvider) GetActor() string {
+	return os.Getenv("GITLAB_USER_LOGIN")
+}
+
+func (g *GitLabConfigProvider) GetPipelineStartTime() time.Time {
+	return parseTimeFromEnv("CI_PIPELINE_CREATED_AT")
+}
+
+func (g *GitLabConfigProvider) GetPullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"),
+		Base:   os.Getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME"),
+		Key:    os.Getenv("CI_MERGE_REQUEST_IID"),
+	}
+}
+
+func (g *GitLabConfigProvider) IsPullRequest() bool {
+	return truthy("CI_MERGE_REQUEST_IID")
+}
+
+func isGitLab() bool {
+	envVars := []string{"GITLAB_CI"}
+	return areIndicatingEnvVarsSet(envVars)
+}
@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"os"
+	"time"
 )
 
 type JenkinsConfigProvider struct{}
@@ -22,6 +23,32 @@ func (j *JenkinsConfigProvider) GetRepoUrl() string {
 	return os.Getenv("GIT_URL")
 }
 
+func (j *JenkinsConfigProvider) GetJobName() string {
+	return os.Getenv("JOB_NAME")
+}
+
+func (j *JenkinsConfigProvider) GetJobUrl() string {
+	return os.Getenv("JOB_URL")
+}
+
+func (j *JenkinsConfigProvider) GetBuildId() string {
+	return os.Getenv("BUILD_NUMBER")
+}
+
+func (j *JenkinsConfigProvider) GetStageName() string {
+	return os.Getenv("STAGE_NAME")
+}
+
+// GetActor returns the user who triggered the build, it requires the Jenkins build-user-vars plugin
+func (j *JenkinsConfigProvider) GetActor() string {
+	return os.Getenv("BUILD_USER_ID")
+}
+
+// GetPipelineStartTime returns a zero time since Jenkins does not expose the start time via environment variables
+func (j *JenkinsConfigProvider) GetPipelineStartTime() time.Time {
+	return time.Time{}
+}
+
 func (j *JenkinsConfigProvider) GetPullRequestConfig() PullRequestConfig {
 	return PullRequestConfig{
 		Branch: os.Getenv("CHANGE_BRANCH"),
@@ -42,4 +42,23 @@ func TestJenkins(t *testing.T) {
 		assert.Equal(t, "main", c.Base)
 		assert.Equal(t, "42", c.Key)
 	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("JOB_NAME", "foo/bar/main")
+		os.Setenv("JOB_URL", "jaas.com/foo/bar/main")
+		os.Setenv("BUILD_NUMBER", "42")
+		os.Setenv("STAGE_NAME", "Build")
+		os.Setenv("BUILD_USER_ID", "jdoe")
+
+		p := JenkinsConfigProvider{}
+
+		assert.Equal(t, "foo/bar/main", p.GetJobName())
+		assert.Equal(t, "jaas.com/foo/bar/main", p.GetJobUrl())
+		assert.Equal(t, "42", p.GetBuildId())
+		assert.Equal(t, "Build", p.GetStageName())
+		assert.Equal(t, "jdoe", p.GetActor())
+		assert.True(t, p.GetPipelineStartTime().IsZero())
+	})
 }
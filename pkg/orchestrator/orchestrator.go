@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"errors"
 	"os"
+	"time"
 )
 
 type Orchestrator int
@@ -12,14 +13,24 @@ const (
 	AzureDevOps
 	GitHubActions
 	Jenkins
+	GitLab
+	BitbucketPipelines
+	Tekton
+	Generic
 )
 
 type OrchestratorSpecificConfigProviding interface {
+	GetActor() string
 	GetBranch() string
+	GetBuildId() string
 	GetBuildUrl() string
 	GetCommit() string
+	GetJobName() string
+	GetJobUrl() string
+	GetPipelineStartTime() time.Time
 	GetPullRequestConfig() PullRequestConfig
 	GetRepoUrl() string
+	GetStageName() string
 	IsPullRequest() bool
 }
 
@@ -37,10 +48,18 @@ func NewOrchestratorSpecificConfigProvider() (OrchestratorSpecificConfigProvidin
 		return &GitHubActionsConfigProvider{}, nil
 	case Jenkins:
 		return &JenkinsConfigProvider{}, nil
+	case GitLab:
+		return &GitLabConfigProvider{}, nil
+	case BitbucketPipelines:
+		return &BitbucketPipelinesConfigProvider{}, nil
+	case Tekton:
+		return &TektonConfigProvider{}, nil
+	case Generic:
+		return &GenericConfigProvider{}, nil
 	case Unknown:
 		fallthrough
 	default:
-		return nil, errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins)")
+		return nil, errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins, GitLab CI, Bitbucket Pipelines, Tekton, generic via PIPER_CI_* environment variables)")
 	}
 }
 
@@ -51,13 +70,22 @@ func DetectOrchestrator() Orchestrator {
 		return Orchestrator(GitHubActions)
 	} else if isJenkins() {
 		return Orchestrator(Jenkins)
+	} else if isGitLab() {
+		return Orchestrator(GitLab)
+	} else if isBitbucketPipelines() {
+		return Orchestrator(BitbucketPipelines)
+	} else if isTekton() {
+		return Orchestrator(Tekton)
+	} else if isGeneric() {
+		// generic detection comes last, so that natively supported orchestrators take precedence
+		return Orchestrator(Generic)
 	} else {
 		return Orchestrator(Unknown)
 	}
 }
 
 func (o Orchestrator) String() string {
-	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins"}[o]
+	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins", "GitLab", "BitbucketPipelines", "Tekton", "Generic"}[o]
 }
 
 func areIndicatingEnvVarsSet(envVars []string) bool {
@@ -69,6 +97,28 @@ func areIndicatingEnvVarsSet(envVars []string) bool {
 	return false
 }
 
+// pipelineStartTimeLayouts contains the timestamp formats used by the orchestrators
+var pipelineStartTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z0700",
+}
+
+// parseTimeFromEnv parses the timestamp contained in the given environment variable,
+// a zero time is returned in case the variable is not set or contains an unknown format
+func parseTimeFromEnv(key string) time.Time {
+	val := os.Getenv(key)
+	if len(val) == 0 {
+		return time.Time{}
+	}
+	for _, layout := range pipelineStartTimeLayouts {
+		if t, err := time.Parse(layout, val); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
 // Checks if var is set and neither empty nor false
 func truthy(key string) bool {
 	val, exists := os.LookupEnv(key)
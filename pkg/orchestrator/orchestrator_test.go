@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,7 +15,7 @@ func TestOrchestrator(t *testing.T) {
 
 		_, err := NewOrchestratorSpecificConfigProvider()
 
-		assert.EqualError(t, err, "unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins)")
+		assert.EqualError(t, err, "unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins, GitLab CI, Bitbucket Pipelines, Tekton, generic via PIPER_CI_* environment variables)")
 	})
 
 	t.Run("Test orchestrator.toString()", func(t *testing.T) {
@@ -28,6 +29,18 @@ func TestOrchestrator(t *testing.T) {
 		assert.Equal(t, "AzureDevOps", o.String())
 	})
 
+	t.Run("Native orchestrator takes precedence over generic", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		os.Setenv("GITLAB_CI", "true")
+		os.Setenv("PIPER_CI_BRANCH", "main")
+
+		o := DetectOrchestrator()
+
+		assert.Equal(t, "GitLab", o.String())
+	})
+
 	t.Run("Test areIndicatingEnvVarsSet", func(t *testing.T) {
 		defer resetEnv(os.Environ())
 		os.Clearenv()
@@ -54,3 +67,28 @@ func TestOrchestrator(t *testing.T) {
 		assert.False(t, tmp)
 	})
 }
+
+func TestParseTimeFromEnv(t *testing.T) {
+	t.Run("Not set", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		assert.True(t, parseTimeFromEnv("PIPELINE_START").IsZero())
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPELINE_START", "yesterday")
+
+		assert.True(t, parseTimeFromEnv("PIPELINE_START").IsZero())
+	})
+
+	t.Run("RFC 3339", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPELINE_START", "2021-08-11T09:27:58Z")
+
+		assert.Equal(t, time.Date(2021, 8, 11, 9, 27, 58, 0, time.UTC), parseTimeFromEnv("PIPELINE_START"))
+	})
+}
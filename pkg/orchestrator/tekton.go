@@ -0,0 +1,77 @@
+package orchestrator
+
+import (
+	"os"
+	"time"
+)
+
+// TektonConfigProvider reads the pipeline run information from environment variables.
+// Tekton does not expose its context to the task containers by default, hence the
+// TEKTON_* variables need to be mapped from the Tekton context and the results of
+// the git-clone task, e.g. TEKTON_PIPELINE_RUN: $(context.pipelineRun.name).
+type TektonConfigProvider struct{}
+
+func (t *TektonConfigProvider) GetBranch() string {
+	return os.Getenv("TEKTON_GIT_BRANCH")
+}
+
+func (t *TektonConfigProvider) GetBuildUrl() string {
+	dashboardURL := os.Getenv("TEKTON_DASHBOARD_URL")
+	if len(dashboardURL) == 0 {
+		return ""
+	}
+	return dashboardURL + "/#/namespaces/" + os.Getenv("TEKTON_NAMESPACE") + "/pipelineruns/" + os.Getenv("TEKTON_PIPELINE_RUN")
+}
+
+func (t *TektonConfigProvider) GetCommit() string {
+	return os.Getenv("TEKTON_GIT_COMMIT")
+}
+
+func (t *TektonConfigProvider) GetRepoUrl() string {
+	return os.Getenv("TEKTON_GIT_URL")
+}
+
+func (t *TektonConfigProvider) GetJobName() string {
+	return os.Getenv("TEKTON_PIPELINE")
+}
+
+func (t *TektonConfigProvider) GetJobUrl() string {
+	dashboardURL := os.Getenv("TEKTON_DASHBOARD_URL")
+	if len(dashboardURL) == 0 {
+		return ""
+	}
+	return dashboardURL + "/#/namespaces/" + os.Getenv("TEKTON_NAMESPACE") + "/pipelines/" + os.Getenv("TEKTON_PIPELINE")
+}
+
+func (t *TektonConfigProvider) GetBuildId() string {
+	return os.Getenv("TEKTON_PIPELINE_RUN")
+}
+
+func (t *TektonConfigProvider) GetStageName() string {
+	return os.Getenv("TEKTON_PIPELINE_TASK")
+}
+
+func (t *TektonConfigProvider) GetActor() string {
+	return os.Getenv("TEKTON_TRIGGERED_BY")
+}
+
+func (t *TektonConfigProvider) GetPipelineStartTime() time.Time {
+	return parseTimeFromEnv("TEKTON_PIPELINE_START_TIME")
+}
+
+func (t *TektonConfigProvider) GetPullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: os.Getenv("TEKTON_PULL_REQUEST_BRANCH"),
+		Base:   os.Getenv("TEKTON_PULL_REQUEST_BASE"),
+		Key:    os.Getenv("TEKTON_PULL_REQUEST_KEY"),
+	}
+}
+
+func (t *TektonConfigProvider) IsPullRequest() bool {
+	return truthy("TEKTON_PULL_REQUEST_KEY")
+}
+
+func isTekton() bool {
+	envVars := []string{"TEKTON_PIPELINE_RUN"}
+	return areIndicatingEnvVarsSet(envVars)
+}
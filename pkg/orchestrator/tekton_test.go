@@ -0,0 +1,77 @@
+package orchestrator
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTekton(t *testing.T) {
+	t.Run("BranchBuild", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PIPELINE_RUN", "build-42")
+		os.Setenv("TEKTON_NAMESPACE", "ci")
+		os.Setenv("TEKTON_DASHBOARD_URL", "https://tekton.example.com")
+		os.Setenv("TEKTON_GIT_BRANCH", "feat/test-tekton")
+		os.Setenv("TEKTON_GIT_COMMIT", "abcdef42713")
+		os.Setenv("TEKTON_GIT_URL", "https://github.com/foo/bar")
+
+		p, _ := NewOrchestratorSpecificConfigProvider()
+
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelineruns/build-42", p.GetBuildUrl())
+		assert.Equal(t, "feat/test-tekton", p.GetBranch())
+		assert.Equal(t, "abcdef42713", p.GetCommit())
+		assert.Equal(t, "https://github.com/foo/bar", p.GetRepoUrl())
+	})
+
+	t.Run("No dashboard", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PIPELINE_RUN", "build-42")
+
+		p := TektonConfigProvider{}
+
+		assert.Equal(t, "", p.GetBuildUrl())
+	})
+
+	t.Run("PR", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PULL_REQUEST_BRANCH", "feat/test-tekton")
+		os.Setenv("TEKTON_PULL_REQUEST_BASE", "main")
+		os.Setenv("TEKTON_PULL_REQUEST_KEY", "42")
+
+		p := TektonConfigProvider{}
+		c := p.GetPullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-tekton", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "42", c.Key)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PIPELINE", "build")
+		os.Setenv("TEKTON_PIPELINE_RUN", "build-42")
+		os.Setenv("TEKTON_NAMESPACE", "ci")
+		os.Setenv("TEKTON_DASHBOARD_URL", "https://tekton.example.com")
+		os.Setenv("TEKTON_PIPELINE_TASK", "unit-tests")
+		os.Setenv("TEKTON_TRIGGERED_BY", "jdoe")
+		os.Setenv("TEKTON_PIPELINE_START_TIME", "2021-08-11T09:27:58Z")
+
+		p := TektonConfigProvider{}
+
+		assert.Equal(t, "build", p.GetJobName())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelines/build", p.GetJobUrl())
+		assert.Equal(t, "build-42", p.GetBuildId())
+		assert.Equal(t, "unit-tests", p.GetStageName())
+		assert.Equal(t, "jdoe", p.GetActor())
+		assert.Equal(t, time.Date(2021, 8, 11, 9, 27, 58, 0, time.UTC), p.GetPipelineStartTime())
+	})
+}
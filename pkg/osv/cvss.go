@@ -0,0 +1,136 @@
+package osv
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Types of severities which are evaluated, other types like CVSS_V4 are ignored
+const (
+	SeverityCVSSv3 = "CVSS_V3"
+	SeverityCVSSv2 = "CVSS_V2"
+)
+
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+var cvss2Weights = map[string]map[string]float64{
+	"AV": {"L": 0.395, "A": 0.646, "N": 1.0},
+	"AC": {"H": 0.35, "M": 0.61, "L": 0.71},
+	"Au": {"M": 0.45, "S": 0.56, "N": 0.704},
+	"C":  {"N": 0, "P": 0.275, "C": 0.660},
+	"I":  {"N": 0, "P": 0.275, "C": 0.660},
+	"A":  {"N": 0, "P": 0.275, "C": 0.660},
+}
+
+// Scores returns the CVSS v3 and v2 base scores of the vulnerability, missing or invalid vectors result in a score of zero
+func (v *Vulnerability) Scores() (cvss3, cvss2 float64) {
+	for _, severity := range v.Severity {
+		switch severity.Type {
+		case SeverityCVSSv3:
+			cvss3, _ = CVSSv3BaseScore(severity.Score)
+		case SeverityCVSSv2:
+			cvss2, _ = CVSSv2BaseScore(severity.Score)
+		}
+	}
+	return cvss3, cvss2
+}
+
+// QualitativeSeverity returns the severity provided by the database in lower case, MODERATE is mapped to medium
+func (v *Vulnerability) QualitativeSeverity() string {
+	severity := strings.ToLower(v.DatabaseSpecific.Severity)
+	if severity == "moderate" {
+		return "medium"
+	}
+	return severity
+}
+
+// CVSSv3BaseScore calculates the base score of a CVSS v3.0 or v3.1 vector, see https://www.first.org/cvss/v3.1/specification-document
+func CVSSv3BaseScore(vector string) (float64, error) {
+	if !strings.HasPrefix(vector, "CVSS:3.") {
+		return 0, fmt.Errorf("invalid CVSS v3 vector '%v'", vector)
+	}
+	metrics, err := parseVector(strings.SplitN(vector, "/", 2)[1], cvss3Weights, "S")
+	if err != nil {
+		return 0, err
+	}
+	changed := metrics["S"] == "C"
+	weight := func(metric string) float64 {
+		return cvss3Weights[metric][metrics[metric]]
+	}
+	privileges := weight("PR")
+	if changed && metrics["PR"] == "L" {
+		privileges = 0.68
+	} else if changed && metrics["PR"] == "H" {
+		privileges = 0.5
+	}
+
+	iss := 1 - (1-weight("C"))*(1-weight("I"))*(1-weight("A"))
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	exploitability := 8.22 * weight("AV") * weight("AC") * privileges * weight("UI")
+	if impact <= 0 {
+		return 0, nil
+	}
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// CVSSv2BaseScore calculates the base score of a CVSS v2 vector, see https://www.first.org/cvss/v2/guide
+func CVSSv2BaseScore(vector string) (float64, error) {
+	metrics, err := parseVector(strings.TrimPrefix(strings.Trim(vector, "()"), "CVSS:2.0/"), cvss2Weights, "")
+	if err != nil {
+		return 0, err
+	}
+	weight := func(metric string) float64 {
+		return cvss2Weights[metric][metrics[metric]]
+	}
+	impact := 10.41 * (1 - (1-weight("C"))*(1-weight("I"))*(1-weight("A")))
+	exploitability := 20 * weight("AV") * weight("AC") * weight("Au")
+	if impact == 0 {
+		return 0, nil
+	}
+	return math.Round(((0.6*impact)+(0.4*exploitability)-1.5)*1.176*10) / 10, nil
+}
+
+// parseVector parses the base metrics of a vector, temporal and environmental metrics are ignored
+func parseVector(vector string, weights map[string]map[string]float64, additional string) (map[string]string, error) {
+	metrics := map[string]string{}
+	for _, part := range strings.Split(vector, "/") {
+		metric := strings.SplitN(part, ":", 2)
+		if len(metric) != 2 {
+			return nil, fmt.Errorf("invalid CVSS vector '%v'", vector)
+		}
+		metrics[metric[0]] = metric[1]
+	}
+	for metric, values := range weights {
+		if _, ok := values[metrics[metric]]; !ok {
+			return nil, fmt.Errorf("invalid CVSS vector '%v': metric '%v' missing or invalid", vector, metric)
+		}
+	}
+	if len(additional) > 0 && len(metrics[additional]) == 0 {
+		return nil, fmt.Errorf("invalid CVSS vector '%v': metric '%v' missing", vector, additional)
+	}
+	return metrics, nil
+}
+
+// roundUp returns the smallest number with one decimal place which is equal to or higher than the input
+func roundUp(value float64) float64 {
+	integer := int(math.Round(value * 100000))
+	if integer%10000 == 0 {
+		return float64(integer) / 100000
+	}
+	return (math.Floor(float64(integer)/10000) + 1) / 10
+}
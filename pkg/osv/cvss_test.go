@@ -0,0 +1,62 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCVSSv3BaseScore(t *testing.T) {
+	tt := []struct {
+		vector string
+		score  float64
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", score: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", score: 10.0},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", score: 6.1},
+		{vector: "CVSS:3.0/AV:L/AC:H/PR:H/UI:N/S:U/C:L/I:N/A:N", score: 1.9},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:N/A:N/E:P", score: 7.7},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", score: 0},
+	}
+	for _, test := range tt {
+		t.Run(test.vector, func(t *testing.T) {
+			score, err := CVSSv3BaseScore(test.vector)
+			require.NoError(t, err)
+			assert.Equal(t, test.score, score)
+		})
+	}
+
+	t.Run("invalid vector", func(t *testing.T) {
+		_, err := CVSSv3BaseScore("AV:N/AC:L/Au:N/C:P/I:P/A:P")
+		assert.EqualError(t, err, "invalid CVSS v3 vector 'AV:N/AC:L/Au:N/C:P/I:P/A:P'")
+		_, err = CVSSv3BaseScore("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/C:H/I:H/A:H")
+		assert.EqualError(t, err, "invalid CVSS vector 'AV:N/AC:L/PR:N/UI:N/C:H/I:H/A:H': metric 'S' missing")
+	})
+}
+
+func TestCVSSv2BaseScore(t *testing.T) {
+	score, err := CVSSv2BaseScore("AV:N/AC:L/Au:N/C:P/I:P/A:P")
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, score)
+
+	score, err = CVSSv2BaseScore("(AV:N/AC:M/Au:N/C:N/I:P/A:N)")
+	require.NoError(t, err)
+	assert.Equal(t, 4.3, score)
+
+	_, err = CVSSv2BaseScore("AV:N/AC:L/Au:N/C:P/I:P")
+	assert.EqualError(t, err, "invalid CVSS vector 'AV:N/AC:L/Au:N/C:P/I:P': metric 'A' missing or invalid")
+}
+
+func TestScores(t *testing.T) {
+	vulnerability := Vulnerability{Severity: []Severity{
+		{Type: SeverityCVSSv2, Score: "AV:N/AC:L/Au:N/C:P/I:P/A:P"},
+		{Type: SeverityCVSSv3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+		{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N"},
+	}}
+
+	cvss3, cvss2 := vulnerability.Scores()
+
+	assert.Equal(t, 9.8, cvss3)
+	assert.Equal(t, 7.5, cvss2)
+}
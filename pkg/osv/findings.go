@@ -0,0 +1,111 @@
+package osv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+)
+
+// ConvertToFindings maps the matches to the common finding model, excluded vulnerabilities are suppressed
+func ConvertToFindings(matches []Match, excludeCVEs []string, sbomPath string) []vulnerability.Finding {
+	findings := []vulnerability.Finding{}
+	for _, match := range matches {
+		cvss3, cvss2 := match.Vulnerability.Scores()
+		score, cvssVersion := vulnerability.Score(cvss3, cvss2)
+		finding := vulnerability.Finding{
+			ID:            match.Vulnerability.ID,
+			CVE:           match.Vulnerability.CVE(),
+			Component:     &vulnerability.Component{Group: match.Component.Group, Name: match.Component.Name, Version: match.Version},
+			CVSS:          score,
+			CVSSVersion:   cvssVersion,
+			Severity:      match.Vulnerability.QualitativeSeverity(),
+			Description:   match.Vulnerability.Summary,
+			URL:           match.Vulnerability.URL(),
+			Location:      vulnerability.Location{File: sbomPath},
+			FixedVersions: match.FixedVersions,
+			Tools:         []string{"OSV"},
+			Status:        vulnerability.StatusOpen,
+		}
+		if len(finding.Description) == 0 {
+			finding.Description = match.Vulnerability.Details
+		}
+		if isExcluded(match.Vulnerability, excludeCVEs) {
+			finding.Status, finding.Justification = vulnerability.StatusSuppressed, "excluded via configuration"
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// CVE returns the CVE identifier of the vulnerability, i.e. its id or one of its aliases
+func (v *Vulnerability) CVE() string {
+	for _, id := range append([]string{v.ID}, v.Aliases...) {
+		if cve := vulnerability.CVEFromID(id); len(cve) > 0 {
+			return cve
+		}
+	}
+	return ""
+}
+
+// URL returns the advisory reference of the vulnerability and falls back to its page on osv.dev
+func (v *Vulnerability) URL() string {
+	for _, reference := range v.References {
+		if reference.Type == "ADVISORY" {
+			return reference.URL
+		}
+	}
+	return fmt.Sprintf("https://osv.dev/vulnerability/%v", v.ID)
+}
+
+func isExcluded(v *Vulnerability, excludeCVEs []string) bool {
+	for _, excluded := range excludeCVEs {
+		for _, id := range append([]string{v.ID}, v.Aliases...) {
+			if strings.EqualFold(strings.TrimSpace(excluded), id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseResultForInflux counts the findings in the same categories as the Protecode scan.
+// Findings suppressed by an exemption are counted as triaged, the other suppressed findings are excluded via configuration.
+// Historical vulnerabilities do not exist in the advisory database.
+func ParseResultForInflux(findings []vulnerability.Finding) map[string]int {
+	m := map[string]int{
+		"count":                      0,
+		"cvss2GreaterOrEqualSeven":   0,
+		"cvss3GreaterOrEqualSeven":   0,
+		"historical_vulnerabilities": 0,
+		"triaged_vulnerabilities":    0,
+		"excluded_vulnerabilities":   0,
+		"minor_vulnerabilities":      0,
+		"major_vulnerabilities":      0,
+		"vulnerabilities":            0,
+	}
+	for _, finding := range findings {
+		if !finding.IsOpen() {
+			if len(finding.ExemptedBy) > 0 {
+				m["triaged_vulnerabilities"]++
+			} else {
+				m["excluded_vulnerabilities"]++
+			}
+			continue
+		}
+		m["count"]++
+		m["vulnerabilities"]++
+		if !finding.IsSevere(vulnerability.DefaultSeverityThreshold) {
+			m["minor_vulnerabilities"]++
+			continue
+		}
+		m["major_vulnerabilities"]++
+		switch finding.CVSSVersion {
+		case "3":
+			m["cvss3GreaterOrEqualSeven"]++
+		case "2":
+			m["cvss2GreaterOrEqualSeven"]++
+		}
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToFindings(t *testing.T) {
+	matches := []Match{
+		{
+			Component:     cyclonedx.Component{Group: "com.fasterxml.jackson.core", Name: "jackson-databind"},
+			Version:       "2.13.1",
+			FixedVersions: []string{"2.12.6.1", "2.13.2.1"},
+			Vulnerability: &Vulnerability{
+				ID:         "GHSA-57j2-w4cx-62h2",
+				Aliases:    []string{"CVE-2020-36518"},
+				Summary:    "Deeply nested json in jackson-databind",
+				Severity:   []Severity{{Type: SeverityCVSSv3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}},
+				References: []Reference{{Type: "WEB", URL: "https://github.com/FasterXML/jackson-databind/issues/2816"}, {Type: "ADVISORY", URL: "https://nvd.nist.gov/vuln/detail/CVE-2020-36518"}},
+			},
+		},
+		{
+			Component:     cyclonedx.Component{Name: "lodash"},
+			Version:       "4.17.15",
+			Vulnerability: &Vulnerability{ID: "GHSA-p6mc-m468-83gw", Details: "Prototype pollution", DatabaseSpecific: DatabaseSpecific{Severity: "MODERATE"}},
+		},
+	}
+
+	findings := ConvertToFindings(matches, []string{"GHSA-p6mc-m468-83gw"}, "bom.xml")
+
+	assert.Equal(t, []vulnerability.Finding{
+		{
+			ID:            "GHSA-57j2-w4cx-62h2",
+			CVE:           "CVE-2020-36518",
+			Component:     &vulnerability.Component{Group: "com.fasterxml.jackson.core", Name: "jackson-databind", Version: "2.13.1"},
+			CVSS:          7.5,
+			CVSSVersion:   "3",
+			Description:   "Deeply nested json in jackson-databind",
+			URL:           "https://nvd.nist.gov/vuln/detail/CVE-2020-36518",
+			Location:      vulnerability.Location{File: "bom.xml"},
+			FixedVersions: []string{"2.12.6.1", "2.13.2.1"},
+			Tools:         []string{"OSV"},
+			Status:        vulnerability.StatusOpen,
+		},
+		{
+			ID:            "GHSA-p6mc-m468-83gw",
+			Component:     &vulnerability.Component{Name: "lodash", Version: "4.17.15"},
+			Severity:      "medium",
+			Description:   "Prototype pollution",
+			URL:           "https://osv.dev/vulnerability/GHSA-p6mc-m468-83gw",
+			Location:      vulnerability.Location{File: "bom.xml"},
+			Tools:         []string{"OSV"},
+			Status:        vulnerability.StatusSuppressed,
+			Justification: "excluded via configuration",
+		},
+	}, findings)
+}
+
+func TestParseResultForInflux(t *testing.T) {
+	findings := []vulnerability.Finding{
+		{ID: "CVE-1", CVSS: 9.8, CVSSVersion: "3", Status: vulnerability.StatusOpen},
+		{ID: "CVE-2", CVSS: 7.5, CVSSVersion: "2", Status: vulnerability.StatusOpen},
+		{ID: "GHSA-1", Severity: "high", Status: vulnerability.StatusOpen},
+		{ID: "CVE-3", CVSS: 5.3, CVSSVersion: "3", Status: vulnerability.StatusOpen},
+		{ID: "CVE-4", CVSS: 9.8, CVSSVersion: "3", Status: vulnerability.StatusSuppressed, ExemptedBy: "security-team"},
+		{ID: "CVE-5", CVSS: 9.8, CVSSVersion: "3", Status: vulnerability.StatusSuppressed},
+	}
+
+	result := ParseResultForInflux(findings)
+
+	assert.Equal(t, map[string]int{
+		"count":                      4,
+		"vulnerabilities":            4,
+		"cvss3GreaterOrEqualSeven":   1,
+		"cvss2GreaterOrEqualSeven":   1,
+		"major_vulnerabilities":      3,
+		"minor_vulnerabilities":      1,
+		"triaged_vulnerabilities":    1,
+		"excluded_vulnerabilities":   1,
+		"historical_vulnerabilities": 0,
+	}, result)
+}
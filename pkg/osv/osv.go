@@ -0,0 +1,375 @@
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Ecosystems of the advisory database which are supported for matching
+const (
+	EcosystemMaven = "Maven"
+	EcosystemNpm   = "npm"
+	EcosystemGo    = "Go"
+	EcosystemPyPI  = "PyPI"
+)
+
+// Types of affected version ranges, GIT ranges refer to commits and are not considered for matching
+const (
+	RangeSemver    = "SEMVER"
+	RangeEcosystem = "ECOSYSTEM"
+	RangeGit       = "GIT"
+)
+
+// Vulnerability is an advisory in the Open Source Vulnerability format, see https://ossf.github.io/osv-schema/
+type Vulnerability struct {
+	ID               string           `json:"id"`
+	Modified         string           `json:"modified,omitempty"`
+	Withdrawn        string           `json:"withdrawn,omitempty"`
+	Aliases          []string         `json:"aliases,omitempty"`
+	Summary          string           `json:"summary,omitempty"`
+	Details          string           `json:"details,omitempty"`
+	Severity         []Severity       `json:"severity,omitempty"`
+	Affected         []Affected       `json:"affected,omitempty"`
+	References       []Reference      `json:"references,omitempty"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific,omitempty"`
+}
+
+// Severity is a CVSS vector of a vulnerability
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected describes the affected versions of a package
+type Affected struct {
+	Package  Package  `json:"package"`
+	Ranges   []Range  `json:"ranges,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Package identifies a package within its ecosystem
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl,omitempty"`
+}
+
+// Range is a range of affected versions defined by its events
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event introduces or ends a range of affected versions, exactly one of its fields is set
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference is a link to further information about a vulnerability
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// DatabaseSpecific contains the fields of the database specific information which are evaluated
+type DatabaseSpecific struct {
+	// Severity is the qualitative severity, e.g. as provided by the GitHub advisory database
+	Severity string `json:"severity,omitempty"`
+}
+
+// FileUtils provides the file access required to read the advisory database
+type FileUtils interface {
+	Glob(pattern string) (matches []string, err error)
+	FileRead(path string) ([]byte, error)
+}
+
+// Database is a local mirror of an OSV advisory database indexed by package
+type Database struct {
+	packages map[string][]*Vulnerability
+	count    int
+}
+
+// ReadDatabase reads all advisories from the JSON files in the directory and its sub-directories.
+// Files may contain a single advisory or a list of advisories, withdrawn advisories are skipped.
+func ReadDatabase(path string, utils FileUtils) (*Database, error) {
+	files, err := utils.Glob(filepath.Join(path, "**", "*.json"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search advisories in '%v'", path)
+	}
+	db := &Database{packages: map[string][]*Vulnerability{}}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read advisory '%v'", file)
+		}
+		vulnerabilities := []Vulnerability{}
+		if trimmed := strings.TrimSpace(string(content)); strings.HasPrefix(trimmed, "[") {
+			err = json.Unmarshal(content, &vulnerabilities)
+		} else {
+			vulnerability := Vulnerability{}
+			err = json.Unmarshal(content, &vulnerability)
+			vulnerabilities = append(vulnerabilities, vulnerability)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse advisory '%v'", file)
+		}
+		for i := range vulnerabilities {
+			db.Add(vulnerabilities[i])
+		}
+	}
+	return db, nil
+}
+
+// Add adds an advisory to the database unless it is withdrawn
+func (db *Database) Add(vulnerability Vulnerability) {
+	if len(vulnerability.ID) == 0 || len(vulnerability.Withdrawn) > 0 {
+		return
+	}
+	if db.packages == nil {
+		db.packages = map[string][]*Vulnerability{}
+	}
+	added := map[string]bool{}
+	for _, affected := range vulnerability.Affected {
+		key := packageKey(affected.Package.Ecosystem, affected.Package.Name)
+		if added[key] {
+			continue
+		}
+		added[key] = true
+		db.packages[key] = append(db.packages[key], &vulnerability)
+	}
+	db.count++
+}
+
+// Len returns the number of advisories in the database
+func (db *Database) Len() int {
+	return db.count
+}
+
+// Vulnerabilities returns the advisories affecting any version of a package
+func (db *Database) Vulnerabilities(ecosystem, name string) []*Vulnerability {
+	return db.packages[packageKey(ecosystem, name)]
+}
+
+var pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// packageKey identifies a package independent of the ecosystem variant, e.g. 'Maven:https://repo.maven.apache.org/maven2/' is 'Maven'
+func packageKey(ecosystem, name string) string {
+	ecosystem = strings.SplitN(ecosystem, ":", 2)[0]
+	if ecosystem == EcosystemPyPI {
+		// see https://peps.python.org/pep-0503/#normalized-names
+		name = pypiNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return ecosystem + "/" + name
+}
+
+// Match is an advisory affecting a component of a BOM
+type Match struct {
+	Component     cyclonedx.Component
+	Ecosystem     string
+	Version       string
+	Vulnerability *Vulnerability
+	// FixedVersions are the versions fixing the vulnerability
+	FixedVersions []string
+}
+
+// Match returns the advisories affecting the components of the BOM.
+// Components without package URL or of unsupported ecosystems are skipped.
+func (db *Database) Match(bom *cyclonedx.BOM) []Match {
+	matches := []Match{}
+	for _, component := range flatten(bom.Components) {
+		ecosystem, name, version, err := ParsePURL(component.PURL)
+		if err != nil {
+			log.Entry().Debugf("skipping component '%v': %v", component.Name, err)
+			continue
+		}
+		if len(version) == 0 {
+			version = component.Version
+		}
+		vulnerabilities := db.Vulnerabilities(ecosystem, name)
+		if len(version) == 0 {
+			if len(vulnerabilities) > 0 {
+				log.Entry().Warnf("skipping component '%v' without version, %v advisories of the package can't be checked", name, len(vulnerabilities))
+			}
+			continue
+		}
+		for _, vulnerability := range vulnerabilities {
+			affected, fixed, err := vulnerability.affects(ecosystem, name, version)
+			if err != nil {
+				log.Entry().Warnf("failed to check if '%v' affects '%v@%v': %v", vulnerability.ID, name, version, err)
+				continue
+			}
+			if affected {
+				matches = append(matches, Match{Component: component, Ecosystem: ecosystem, Version: version, Vulnerability: vulnerability, FixedVersions: fixed})
+			}
+		}
+	}
+	return matches
+}
+
+func flatten(components []cyclonedx.Component) []cyclonedx.Component {
+	flat := []cyclonedx.Component{}
+	for _, component := range components {
+		flat = append(flat, component)
+		flat = append(flat, flatten(component.Components)...)
+	}
+	return flat
+}
+
+// affects checks if the version of a package is affected and returns the versions fixing the vulnerability
+func (v *Vulnerability) affects(ecosystem, name, version string) (bool, []string, error) {
+	key := packageKey(ecosystem, name)
+	isAffected, fixed := false, []string{}
+	for _, affected := range v.Affected {
+		if packageKey(affected.Package.Ecosystem, affected.Package.Name) != key {
+			continue
+		}
+		result, err := affected.IsAffected(ecosystem, version)
+		if err != nil {
+			return false, nil, err
+		}
+		isAffected = isAffected || result
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				if len(event.Fixed) > 0 {
+					fixed = append(fixed, event.Fixed)
+				}
+			}
+		}
+	}
+	return isAffected, fixed, nil
+}
+
+// IsAffected checks if the version is listed as affected or contained in one of the affected ranges
+func (a *Affected) IsAffected(ecosystem, version string) (bool, error) {
+	for _, affectedVersion := range a.Versions {
+		if strings.TrimPrefix(affectedVersion, "v") == strings.TrimPrefix(version, "v") {
+			return true, nil
+		}
+	}
+	for _, r := range a.Ranges {
+		if r.Type == RangeGit {
+			continue
+		}
+		compare, err := comparator(ecosystem, r.Type)
+		if err != nil {
+			return false, err
+		}
+		contained, err := r.contains(version, compare)
+		if err != nil || contained {
+			return contained, err
+		}
+	}
+	return false, nil
+}
+
+// contains evaluates the events of the range in the order of their versions as described in
+// https://ossf.github.io/osv-schema/#evaluation
+func (r *Range) contains(version string, compare compareFunc) (bool, error) {
+	events := append([]Event{}, r.Events...)
+	var compareErr error
+	less := func(a, b string) bool {
+		// introduced version 0 denotes the lowest version of the ecosystem
+		if a == "0" || b == "0" {
+			return a == "0" && b != "0"
+		}
+		c, err := compare(a, b)
+		if err != nil && compareErr == nil {
+			compareErr = err
+		}
+		return c < 0
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return less(events[i].version(), events[j].version())
+	})
+	if compareErr != nil {
+		return false, compareErr
+	}
+	if _, err := compare(version, version); err != nil {
+		return false, err
+	}
+
+	affected := false
+	for _, event := range events {
+		switch {
+		case len(event.Introduced) > 0:
+			if event.Introduced == "0" || !less(version, event.Introduced) {
+				affected = true
+			}
+		case len(event.Fixed) > 0:
+			if !less(version, event.Fixed) {
+				affected = false
+			}
+		case len(event.LastAffected) > 0:
+			if less(event.LastAffected, version) {
+				affected = false
+			}
+		case len(event.Limit) > 0:
+			if !less(version, event.Limit) {
+				affected = false
+			}
+		}
+	}
+	return affected, compareErr
+}
+
+func (e Event) version() string {
+	for _, version := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if len(version) > 0 {
+			return version
+		}
+	}
+	return ""
+}
+
+// ParsePURL returns the ecosystem, the package name as used by the advisory database and the version of a package URL,
+// see https://github.com/package-url/purl-spec
+func ParsePURL(purl string) (ecosystem, name, version string, err error) {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return "", "", "", fmt.Errorf("invalid package url '%v'", purl)
+	}
+	remainder := strings.TrimPrefix(purl, "pkg:")
+	remainder = strings.SplitN(remainder, "#", 2)[0]
+	remainder = strings.SplitN(remainder, "?", 2)[0]
+	if index := strings.LastIndex(remainder, "@"); index > strings.LastIndex(remainder, "/") {
+		remainder, version = remainder[:index], remainder[index+1:]
+		if version, err = url.PathUnescape(version); err != nil {
+			return "", "", "", errors.Wrapf(err, "invalid package url '%v'", purl)
+		}
+	}
+	segments := strings.Split(strings.Trim(remainder, "/"), "/")
+	if len(segments) < 2 {
+		return "", "", "", fmt.Errorf("invalid package url '%v'", purl)
+	}
+	for i := range segments {
+		if segments[i], err = url.PathUnescape(segments[i]); err != nil {
+			return "", "", "", errors.Wrapf(err, "invalid package url '%v'", purl)
+		}
+	}
+	packageType, path := strings.ToLower(segments[0]), segments[1:]
+	switch packageType {
+	case "maven":
+		if len(path) != 2 {
+			return "", "", "", fmt.Errorf("invalid maven package url '%v'", purl)
+		}
+		return EcosystemMaven, path[0] + ":" + path[1], version, nil
+	case "npm":
+		return EcosystemNpm, strings.Join(path, "/"), version, nil
+	case "golang":
+		return EcosystemGo, strings.Join(path, "/"), version, nil
+	case "pypi":
+		return EcosystemPyPI, pypiNameSeparators.ReplaceAllString(strings.ToLower(path[len(path)-1]), "-"), version, nil
+	}
+	return "", "", "", fmt.Errorf("package type '%v' not supported", packageType)
+}
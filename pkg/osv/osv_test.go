@@ -0,0 +1,222 @@
+package osv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cyclonedx"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jacksonAdvisory = `{
+  "id": "GHSA-57j2-w4cx-62h2",
+  "aliases": ["CVE-2020-36518"],
+  "summary": "Deeply nested json in jackson-databind",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "com.fasterxml.jackson.core:jackson-databind"},
+    "ranges": [
+      {"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.12.6.1"}]},
+      {"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.13.2.1"}]}
+    ]
+  }],
+  "references": [{"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2020-36518"}],
+  "database_specific": {"severity": "HIGH"}
+}`
+
+const npmAdvisories = `[
+  {
+    "id": "GHSA-p6mc-m468-83gw",
+    "summary": "Prototype Pollution in lodash",
+    "affected": [{
+      "package": {"ecosystem": "npm", "name": "lodash"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "3.7.0"}, {"last_affected": "4.17.19"}]}]
+    }],
+    "database_specific": {"severity": "MODERATE"}
+  },
+  {
+    "id": "GHSA-withdrawn",
+    "withdrawn": "2021-01-01T00:00:00Z",
+    "affected": [{"package": {"ecosystem": "npm", "name": "lodash"}, "versions": ["4.17.15"]}]
+  }
+]`
+
+func TestReadDatabase(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("advisories/maven/GHSA-57j2-w4cx-62h2.json", []byte(jacksonAdvisory))
+		utils.AddFile("advisories/npm.json", []byte(npmAdvisories))
+		utils.AddFile("advisories/README.md", []byte("# advisories"))
+
+		db, err := ReadDatabase("advisories", utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, db.Len())
+		if assert.Len(t, db.Vulnerabilities("Maven", "com.fasterxml.jackson.core:jackson-databind"), 1) {
+			assert.Equal(t, "GHSA-57j2-w4cx-62h2", db.Vulnerabilities("Maven", "com.fasterxml.jackson.core:jackson-databind")[0].ID)
+		}
+		assert.Len(t, db.Vulnerabilities("npm", "lodash"), 1)
+	})
+
+	t.Run("invalid advisory", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("advisories/invalid.json", []byte("{"))
+
+		_, err := ReadDatabase("advisories", utils)
+
+		assert.EqualError(t, err, "failed to parse advisory 'advisories/invalid.json': unexpected end of JSON input")
+	})
+}
+
+func TestIsAffected(t *testing.T) {
+	t.Run("ecosystem ranges", func(t *testing.T) {
+		affected := Affected{Ranges: []Range{
+			{Type: RangeEcosystem, Events: []Event{{Fixed: "2.12.6.1"}, {Introduced: "0"}}},
+			{Type: RangeEcosystem, Events: []Event{{Introduced: "2.13.0"}, {Fixed: "2.13.2.1"}}},
+		}}
+		for version, expected := range map[string]bool{
+			"2.9.10":   true,
+			"2.12.6":   true,
+			"2.12.6.1": false,
+			"2.12.7":   false,
+			"2.13.0":   true,
+			"2.13.2":   true,
+			"2.13.2.1": false,
+			"2.14.0":   false,
+		} {
+			result, err := affected.IsAffected(EcosystemMaven, version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result, version)
+		}
+	})
+
+	t.Run("last affected and open ranges", func(t *testing.T) {
+		affected := Affected{Ranges: []Range{
+			{Type: RangeSemver, Events: []Event{{Introduced: "1.0.0"}, {LastAffected: "1.2.0"}, {Introduced: "2.0.0"}}},
+		}}
+		for version, expected := range map[string]bool{
+			"0.9.0":       false,
+			"1.2.0":       true,
+			"1.2.1":       false,
+			"2.0.0-beta1": false,
+			"2.0.0":       true,
+			"3.5.1":       true,
+		} {
+			result, err := affected.IsAffected(EcosystemNpm, version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result, version)
+		}
+	})
+
+	t.Run("explicit versions", func(t *testing.T) {
+		affected := Affected{Versions: []string{"v1.4.0"}, Ranges: []Range{{Type: RangeGit, Events: []Event{{Introduced: "0"}, {Fixed: "abc123"}}}}}
+
+		result, err := affected.IsAffected(EcosystemGo, "1.4.0")
+		require.NoError(t, err)
+		assert.True(t, result)
+
+		result, err = affected.IsAffected(EcosystemGo, "1.4.1")
+		require.NoError(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		affected := Affected{Ranges: []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}, {Fixed: "2.0"}}}}}
+
+		_, err := affected.IsAffected(EcosystemPyPI, "latest")
+
+		assert.EqualError(t, err, "invalid python version 'latest'")
+	})
+}
+
+func TestParsePURL(t *testing.T) {
+	tt := []struct {
+		purl      string
+		ecosystem string
+		name      string
+		version   string
+	}{
+		{purl: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.1?type=jar", ecosystem: "Maven", name: "com.fasterxml.jackson.core:jackson-databind", version: "2.13.1"},
+		{purl: "pkg:npm/%40angular/core@12.0.0", ecosystem: "npm", name: "@angular/core", version: "12.0.0"},
+		{purl: "pkg:npm/lodash@4.17.15", ecosystem: "npm", name: "lodash", version: "4.17.15"},
+		{purl: "pkg:golang/github.com/pkg/errors@v0.9.1", ecosystem: "Go", name: "github.com/pkg/errors", version: "v0.9.1"},
+		{purl: "pkg:pypi/Flask_Cors@3.0.9", ecosystem: "PyPI", name: "flask-cors", version: "3.0.9"},
+		{purl: "pkg:npm/left-pad", ecosystem: "npm", name: "left-pad"},
+	}
+	for _, test := range tt {
+		t.Run(test.purl, func(t *testing.T) {
+			ecosystem, name, version, err := ParsePURL(test.purl)
+			require.NoError(t, err)
+			assert.Equal(t, test.ecosystem, ecosystem)
+			assert.Equal(t, test.name, name)
+			assert.Equal(t, test.version, version)
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, _, _, err := ParsePURL("pkg:cargo/serde@1.0.0")
+		assert.EqualError(t, err, "package type 'cargo' not supported")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, _, err := ParsePURL("com.fasterxml.jackson.core:jackson-databind")
+		assert.EqualError(t, err, "invalid package url 'com.fasterxml.jackson.core:jackson-databind'")
+	})
+}
+
+func TestMatch(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("db/jackson.json", []byte(jacksonAdvisory))
+	utils.AddFile("db/npm.json", []byte(npmAdvisories))
+	db, err := ReadDatabase("db", utils)
+	require.NoError(t, err)
+
+	bom := &cyclonedx.BOM{Components: []cyclonedx.Component{
+		{Group: "com.fasterxml.jackson.core", Name: "jackson-databind", Version: "2.13.1", PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.1"},
+		{Name: "app", PURL: "pkg:npm/app@1.0.0", Components: []cyclonedx.Component{
+			{Name: "lodash", PURL: "pkg:npm/lodash@4.17.15"},
+			{Name: "lodash", PURL: "pkg:npm/lodash@4.17.21"},
+		}},
+		{Name: "jackson-databind", Version: "2.13.1"},
+	}}
+
+	matches := db.Match(bom)
+
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "GHSA-57j2-w4cx-62h2", matches[0].Vulnerability.ID)
+		assert.Equal(t, EcosystemMaven, matches[0].Ecosystem)
+		assert.Equal(t, "2.13.1", matches[0].Version)
+		assert.Equal(t, []string{"2.12.6.1", "2.13.2.1"}, matches[0].FixedVersions)
+		assert.Equal(t, "GHSA-p6mc-m468-83gw", matches[1].Vulnerability.ID)
+		assert.Equal(t, "4.17.15", matches[1].Version)
+		assert.Empty(t, matches[1].FixedVersions)
+	}
+}
+
+func TestMatchWithoutVersion(t *testing.T) {
+	logBuffer := new(bytes.Buffer)
+	logOutput := log.Entry().Logger.Out
+	log.Entry().Logger.Out = logBuffer
+	defer func() { log.Entry().Logger.Out = logOutput }()
+
+	utils := &mock.FilesMock{}
+	utils.AddFile("db/npm.json", []byte(npmAdvisories))
+	db, err := ReadDatabase("db", utils)
+	require.NoError(t, err)
+
+	bom := &cyclonedx.BOM{Components: []cyclonedx.Component{
+		{Name: "lodash", PURL: "pkg:npm/lodash"},
+		{Name: "left-pad", PURL: "pkg:npm/left-pad"},
+	}}
+
+	matches := db.Match(bom)
+
+	assert.Empty(t, matches)
+	assert.Equal(t, 1, strings.Count(logBuffer.String(), "skipping component 'lodash' without version"))
+	assert.NotContains(t, logBuffer.String(), "left-pad")
+	assert.NotContains(t, logBuffer.String(), "failed to check")
+}
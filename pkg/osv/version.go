@@ -0,0 +1,293 @@
+package osv
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// compareFunc compares two versions and returns a negative number, zero or a positive number
+// in case the first version is lower than, equal to or greater than the second version
+type compareFunc func(a, b string) (int, error)
+
+// comparator returns the version comparison of an ecosystem, ranges of type SEMVER are always compared according to semantic versioning
+func comparator(ecosystem, rangeType string) (compareFunc, error) {
+	if rangeType == RangeSemver {
+		return compareSemver, nil
+	}
+	if rangeType != RangeEcosystem {
+		return nil, fmt.Errorf("range type '%v' not supported", rangeType)
+	}
+	switch ecosystem {
+	case EcosystemMaven:
+		return compareMaven, nil
+	case EcosystemNpm, EcosystemGo:
+		return compareSemver, nil
+	case EcosystemPyPI:
+		return comparePyPI, nil
+	}
+	return nil, fmt.Errorf("ecosystem '%v' not supported", ecosystem)
+}
+
+var semverPattern = regexp.MustCompile(`^v?=?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+type semver struct {
+	release    [3]int
+	prerelease []string
+}
+
+func parseSemver(version string) (semver, error) {
+	match := semverPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return semver{}, fmt.Errorf("invalid semantic version '%v'", version)
+	}
+	v := semver{}
+	for i := 0; i < 3; i++ {
+		if len(match[i+1]) > 0 {
+			v.release[i], _ = strconv.Atoi(match[i+1])
+		}
+	}
+	if len(match[4]) > 0 {
+		v.prerelease = strings.Split(match[4], ".")
+	}
+	return v, nil
+}
+
+// compareSemver compares versions according to https://semver.org, Go pseudo versions are pre-releases and compare correctly
+func compareSemver(a, b string) (int, error) {
+	va, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < 3; i++ {
+		if c := compareInt(va.release[i], vb.release[i]); c != 0 {
+			return c, nil
+		}
+	}
+	// a version without pre-release has a higher precedence
+	switch {
+	case len(va.prerelease) == 0 && len(vb.prerelease) == 0:
+		return 0, nil
+	case len(va.prerelease) == 0:
+		return 1, nil
+	case len(vb.prerelease) == 0:
+		return -1, nil
+	}
+	for i := 0; i < len(va.prerelease) && i < len(vb.prerelease); i++ {
+		na, errA := strconv.Atoi(va.prerelease[i])
+		nb, errB := strconv.Atoi(vb.prerelease[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareInt(na, nb)
+		case errA == nil:
+			// numeric identifiers have lower precedence than alphanumeric identifiers
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(va.prerelease[i], vb.prerelease[i])
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return compareInt(len(va.prerelease), len(vb.prerelease)), nil
+}
+
+// mavenQualifiers defines the order of well-known qualifiers, unknown qualifiers are ordered after them
+var mavenQualifiers = map[string]int{
+	"alpha":     1,
+	"a":         1,
+	"beta":      2,
+	"b":         2,
+	"milestone": 3,
+	"m":         3,
+	"rc":        4,
+	"cr":        4,
+	"snapshot":  5,
+	"":          6,
+	"ga":        6,
+	"final":     6,
+	"release":   6,
+	"sp":        7,
+}
+
+type mavenItem struct {
+	number    int
+	qualifier string
+	isNumber  bool
+}
+
+var mavenTokens = regexp.MustCompile(`\d+|[a-z]+`)
+
+func parseMaven(version string) []mavenItem {
+	items := []mavenItem{}
+	for _, token := range mavenTokens.FindAllString(strings.ToLower(version), -1) {
+		if number, err := strconv.Atoi(token); err == nil {
+			items = append(items, mavenItem{number: number, isNumber: true})
+		} else {
+			items = append(items, mavenItem{qualifier: token})
+		}
+	}
+	// trailing zeros and release qualifiers are not significant, i.e. 1.0.0 and 1-ga are equal to 1
+	for len(items) > 0 && items[len(items)-1].isNull() {
+		items = items[:len(items)-1]
+	}
+	return items
+}
+
+func (i mavenItem) isNull() bool {
+	if i.isNumber {
+		return i.number == 0
+	}
+	return i.qualifierRank() == mavenQualifiers[""]
+}
+
+func (i mavenItem) qualifierRank() int {
+	if rank, ok := mavenQualifiers[i.qualifier]; ok {
+		return rank
+	}
+	return len(mavenQualifiers)
+}
+
+// compareMaven compares versions similar to the ComparableVersion of Maven
+func compareMaven(a, b string) (int, error) {
+	itemsA, itemsB := parseMaven(a), parseMaven(b)
+	for i := 0; i < len(itemsA) || i < len(itemsB); i++ {
+		// missing items are compared like the release qualifier respectively zero
+		itemA, itemB := mavenItem{}, mavenItem{}
+		if i < len(itemsA) {
+			itemA = itemsA[i]
+		}
+		if i < len(itemsB) {
+			itemB = itemsB[i]
+		}
+		var c int
+		switch {
+		case itemA.isNumber && itemB.isNumber:
+			c = compareInt(itemA.number, itemB.number)
+		case itemA.isNumber:
+			// a number is greater than any qualifier but missing items equal zero
+			if i >= len(itemsB) {
+				c = compareInt(itemA.number, 0)
+			} else {
+				c = 1
+			}
+		case itemB.isNumber:
+			if i >= len(itemsA) {
+				c = compareInt(0, itemB.number)
+			} else {
+				c = -1
+			}
+		default:
+			c = compareInt(itemA.qualifierRank(), itemB.qualifierRank())
+			if c == 0 && itemA.qualifierRank() == len(mavenQualifiers) {
+				c = strings.Compare(itemA.qualifier, itemB.qualifier)
+			}
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+var pep440Pattern = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d*))?(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?([-_.]?dev[-_.]?(\d*))?(?:\+[a-z0-9.]*)?$`)
+
+type pep440 struct {
+	epoch   int
+	release []int
+	// pre-release, post-release and development release are combined into one sort key
+	suffix [4]float64
+}
+
+func parsePyPI(version string) (pep440, error) {
+	match := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if match == nil {
+		return pep440{}, fmt.Errorf("invalid python version '%v'", version)
+	}
+	v := pep440{}
+	v.epoch, _ = strconv.Atoi(match[1])
+	for _, segment := range strings.Split(match[2], ".") {
+		number, _ := strconv.Atoi(segment)
+		v.release = append(v.release, number)
+	}
+	number := func(value string) float64 {
+		n, _ := strconv.Atoi(value)
+		return float64(n)
+	}
+	preRelease, postRelease, devRelease := len(match[3]) > 0, len(match[5]) > 0 || len(match[6]) > 0, len(match[8]) > 0
+
+	switch {
+	case preRelease:
+		ranks := map[string]float64{"a": 0, "alpha": 0, "b": 1, "beta": 1, "c": 2, "rc": 2, "pre": 2, "preview": 2}
+		v.suffix[0], v.suffix[1] = ranks[match[3]], number(match[4])
+	case devRelease && !postRelease:
+		// development releases of a final release are ordered before its pre-releases
+		v.suffix[0] = -1
+	default:
+		v.suffix[0] = math.Inf(1)
+	}
+	v.suffix[2] = -1
+	if postRelease {
+		v.suffix[2] = number(match[5] + match[7])
+	}
+	v.suffix[3] = math.Inf(1)
+	if devRelease {
+		v.suffix[3] = number(match[9])
+	}
+	return v, nil
+}
+
+// comparePyPI compares versions according to PEP 440, local versions are ignored
+func comparePyPI(a, b string) (int, error) {
+	va, err := parsePyPI(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parsePyPI(b)
+	if err != nil {
+		return 0, err
+	}
+	if c := compareInt(va.epoch, vb.epoch); c != 0 {
+		return c, nil
+	}
+	for i := 0; i < len(va.release) || i < len(vb.release); i++ {
+		segmentA, segmentB := 0, 0
+		if i < len(va.release) {
+			segmentA = va.release[i]
+		}
+		if i < len(vb.release) {
+			segmentB = vb.release[i]
+		}
+		if c := compareInt(segmentA, segmentB); c != 0 {
+			return c, nil
+		}
+	}
+	for i := range va.suffix {
+		if va.suffix[i] < vb.suffix[i] {
+			return -1, nil
+		}
+		if va.suffix[i] > vb.suffix[i] {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
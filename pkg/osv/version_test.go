@@ -0,0 +1,90 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tt := []struct {
+		name    string
+		compare compareFunc
+		lower   string
+		higher  string
+	}{
+		{name: "semver patch", compare: compareSemver, lower: "1.2.3", higher: "1.2.10"},
+		{name: "semver pre-release", compare: compareSemver, lower: "1.0.0-rc.1", higher: "1.0.0"},
+		{name: "semver numeric pre-release", compare: compareSemver, lower: "1.0.0-alpha.2", higher: "1.0.0-alpha.10"},
+		{name: "semver alphanumeric pre-release", compare: compareSemver, lower: "1.0.0-alpha.1", higher: "1.0.0-alpha.beta"},
+		{name: "go pseudo version", compare: compareSemver, lower: "v0.0.0-20210817142637-7d9622a276b7", higher: "v0.0.1"},
+		{name: "maven numbers", compare: compareMaven, lower: "2.9", higher: "2.10.1"},
+		{name: "maven qualifier", compare: compareMaven, lower: "2.12.0-rc1", higher: "2.12.0"},
+		{name: "maven milestone", compare: compareMaven, lower: "5.3.0-M2", higher: "5.3.0-RC1"},
+		{name: "maven snapshot", compare: compareMaven, lower: "1.0-SNAPSHOT", higher: "1.0"},
+		{name: "maven service pack", compare: compareMaven, lower: "1.0", higher: "1.0-sp1"},
+		{name: "maven unknown qualifier", compare: compareMaven, lower: "1.0", higher: "1.0.jre8"},
+		{name: "pypi release", compare: comparePyPI, lower: "2.0", higher: "2.0.1"},
+		{name: "pypi pre-release", compare: comparePyPI, lower: "2.0b1", higher: "2.0rc1"},
+		{name: "pypi dev release", compare: comparePyPI, lower: "2.0.dev1", higher: "2.0a1"},
+		{name: "pypi post release", compare: comparePyPI, lower: "2.0", higher: "2.0.post1"},
+		{name: "pypi preview", compare: comparePyPI, lower: "2.0preview1", higher: "2.0"},
+		{name: "pypi epoch", compare: comparePyPI, lower: "2021.1", higher: "1!1.0"},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := test.compare(test.lower, test.higher)
+			require.NoError(t, err)
+			assert.Equal(t, -1, c)
+			c, err = test.compare(test.higher, test.lower)
+			require.NoError(t, err)
+			assert.Equal(t, 1, c)
+		})
+	}
+
+	t.Run("equal versions", func(t *testing.T) {
+		for _, versions := range []struct {
+			compare compareFunc
+			a, b    string
+		}{
+			{compareSemver, "v1.2.3", "1.2.3"},
+			{compareSemver, "1.2.3+build.1", "1.2.3"},
+			{compareMaven, "1.0.0", "1"},
+			{compareMaven, "1.0-GA", "1.0.FINAL"},
+			{comparePyPI, "1.0", "1.0.0"},
+			{comparePyPI, "1.0-post2", "1.0.post2"},
+			{comparePyPI, "1.0+local", "1.0"},
+		} {
+			c, err := versions.compare(versions.a, versions.b)
+			require.NoError(t, err)
+			assert.Equal(t, 0, c, "%v and %v", versions.a, versions.b)
+		}
+	})
+
+	t.Run("invalid versions", func(t *testing.T) {
+		_, err := compareSemver("latest", "1.0.0")
+		assert.EqualError(t, err, "invalid semantic version 'latest'")
+		_, err = comparePyPI("1.0", "master")
+		assert.EqualError(t, err, "invalid python version 'master'")
+	})
+}
+
+func TestComparator(t *testing.T) {
+	t.Run("semver ranges", func(t *testing.T) {
+		compare, err := comparator(EcosystemMaven, RangeSemver)
+		require.NoError(t, err)
+		c, _ := compare("1.0.0-rc1", "1.0.0")
+		assert.Equal(t, -1, c)
+	})
+
+	t.Run("unsupported ecosystem", func(t *testing.T) {
+		_, err := comparator("crates.io", RangeEcosystem)
+		assert.EqualError(t, err, "ecosystem 'crates.io' not supported")
+	})
+
+	t.Run("unsupported range type", func(t *testing.T) {
+		_, err := comparator(EcosystemNpm, "UNKNOWN")
+		assert.EqualError(t, err, "range type 'UNKNOWN' not supported")
+	})
+}
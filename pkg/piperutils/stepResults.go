@@ -3,6 +3,12 @@ package piperutils
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
@@ -24,6 +30,7 @@ func PersistReportsAndLinks(stepName, workspace string, reports, links []Path) {
 	if links == nil {
 		links = []Path{}
 	}
+	addStepReportsAndLinks(workspace, reports, links)
 
 	hasMandatoryReport := false
 	for _, report := range reports {
@@ -48,3 +55,110 @@ func PersistReportsAndLinks(stepName, workspace string, reports, links []Path) {
 		piperenv.SetParameter(workspace, fmt.Sprintf("%v_links.json", stepName), string(linkList))
 	}
 }
+
+// StepResultDirectory specifies the directory below the environment root path (e.g. .pipeline) containing the result files of all executed steps
+const StepResultDirectory = "stepResults"
+
+// unsafeFileNameChars matches all characters of a stage name which must not be part of a file name, e.g. path separators
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// StepResult - uniform machine-readable result of a step execution which allows aggregating step outcomes
+type StepResult struct {
+	StageName      string    `json:"stageName,omitempty"`
+	StepName       string    `json:"stepName"`
+	Status         string    `json:"status"`
+	StartTime      time.Time `json:"startTime"`
+	DurationMillis int64     `json:"durationMillis"`
+	ErrorCategory  string    `json:"errorCategory"`
+	CorrelationID  string    `json:"correlationId,omitempty"`
+	Artifacts      []Path    `json:"artifacts"`
+	Reports        []Path    `json:"reports"`
+	Links          []Path    `json:"links"`
+}
+
+// stepResultPaths collects the artifacts, reports and links reported during the step execution
+var stepResultPaths = struct {
+	sync.Mutex
+	artifacts, reports, links []Path
+}{}
+
+// AddStepArtifacts registers artifacts produced by the step in order to list them in the step result
+func AddStepArtifacts(artifacts ...Path) {
+	stepResultPaths.Lock()
+	defer stepResultPaths.Unlock()
+	stepResultPaths.artifacts = appendUniquePaths(stepResultPaths.artifacts, "", artifacts)
+}
+
+func addStepReportsAndLinks(workspace string, reports, links []Path) {
+	stepResultPaths.Lock()
+	defer stepResultPaths.Unlock()
+	stepResultPaths.reports = appendUniquePaths(stepResultPaths.reports, workspace, reports)
+	stepResultPaths.links = appendUniquePaths(stepResultPaths.links, "", links)
+}
+
+// appendUniquePaths appends paths whose target is not yet contained, relative targets are resolved against the workspace
+func appendUniquePaths(paths []Path, workspace string, additional []Path) []Path {
+	for _, path := range additional {
+		if len(workspace) > 0 && !filepath.IsAbs(path.Target) {
+			path.Target = filepath.Join(workspace, path.Target)
+		}
+		contained := false
+		for _, existing := range paths {
+			if existing.Target == path.Target {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// PersistStepResult stores the result of the step execution including the collected artifacts, reports and links
+// as <stageName>-<stepName>.json (or <stepName>.json without stage) in the StepResultDirectory below envRootPath
+func PersistStepResult(envRootPath, stageName, stepName string, successful bool, startTime time.Time, correlationID string) {
+	directory := filepath.Join(envRootPath, StepResultDirectory)
+	result := StepResult{
+		StageName:      stageName,
+		StepName:       stepName,
+		Status:         "failure",
+		StartTime:      startTime,
+		DurationMillis: time.Since(startTime).Milliseconds(),
+		ErrorCategory:  log.GetErrorCategory().String(),
+		CorrelationID:  correlationID,
+	}
+	if successful {
+		result.Status = "success"
+	}
+
+	stepResultPaths.Lock()
+	result.Artifacts = append([]Path{}, stepResultPaths.artifacts...)
+	result.Reports = append([]Path{}, stepResultPaths.reports...)
+	result.Links = append([]Path{}, stepResultPaths.links...)
+	stepResultPaths.Unlock()
+
+	resultJSON, err := json.Marshal(&result)
+	if err != nil {
+		log.Entry().WithError(err).Warn("Failed to marshal step result")
+		return
+	}
+	if err := os.MkdirAll(directory, 0777); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to create directory '%v' for step result", directory)
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(directory, StepResultFileName(stageName, stepName)), resultJSON, 0666); err != nil {
+		log.Entry().WithError(err).Warn("Failed to write step result")
+	}
+}
+
+// StepResultFileName returns the name of the step result file, the stage name is part of it
+// in order to distinguish executions of the same step in different stages.
+// Characters of the stage name which are not safe in a file name, e.g. '/' or spaces, are replaced by '_'.
+func StepResultFileName(stageName, stepName string) string {
+	if len(stageName) == 0 {
+		return fmt.Sprintf("%v.json", stepName)
+	}
+	return fmt.Sprintf("%v-%v.json", unsafeFileNameChars.ReplaceAllString(stageName, "_"), stepName)
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -79,3 +81,71 @@ func TestPersistReportAndLinks(t *testing.T) {
 		}
 	})
 }
+
+func TestPersistStepResult(t *testing.T) {
+	resetStepResultPaths := func() {
+		stepResultPaths.artifacts, stepResultPaths.reports, stepResultPaths.links = nil, nil, nil
+	}
+
+	readStepResult := func(t *testing.T, path string) StepResult {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		var result StepResult
+		require.NoError(t, json.Unmarshal(content, &result))
+		return result
+	}
+
+	t.Run("success with artifacts, reports and links", func(t *testing.T) {
+		resetStepResultPaths()
+		defer resetStepResultPaths()
+		workspace, err := ioutil.TempDir("", "stepResult")
+		require.NoError(t, err, "Failed to create temporary workspace directory")
+		defer os.RemoveAll(workspace)
+
+		AddStepArtifacts(Path{Name: "mtar", Target: "app.mtar"})
+		PersistReportsAndLinks("testStep", workspace, []Path{{Target: "report.json", Mandatory: true}}, []Path{{Name: "Weblink", Target: "https://1234568.com/test"}})
+		// reporting the same paths again must not lead to duplicates
+		PersistReportsAndLinks("testStep", workspace, []Path{{Target: "report.json", Mandatory: true}}, []Path{{Name: "Weblink", Target: "https://1234568.com/test"}})
+		startTime := time.Now().Add(-2 * time.Second)
+		PersistStepResult(workspace, "Build", "testStep", true, startTime, "correlation-1")
+
+		result := readStepResult(t, filepath.Join(workspace, "stepResults", "Build-testStep.json"))
+		assert.Equal(t, "Build", result.StageName)
+		assert.Equal(t, "testStep", result.StepName)
+		assert.Equal(t, "success", result.Status)
+		assert.Equal(t, "undefined", result.ErrorCategory)
+		assert.Equal(t, "correlation-1", result.CorrelationID)
+		assert.True(t, result.StartTime.Equal(startTime))
+		assert.GreaterOrEqual(t, result.DurationMillis, int64(2000))
+		assert.Equal(t, []Path{{Name: "mtar", Target: "app.mtar"}}, result.Artifacts)
+		assert.Equal(t, []Path{{Target: filepath.Join(workspace, "report.json"), Mandatory: true}}, result.Reports)
+		assert.Equal(t, []Path{{Name: "Weblink", Target: "https://1234568.com/test"}}, result.Links)
+	})
+
+	t.Run("failure with error category", func(t *testing.T) {
+		resetStepResultPaths()
+		workspace, err := ioutil.TempDir("", "stepResult")
+		require.NoError(t, err, "Failed to create temporary workspace directory")
+		defer os.RemoveAll(workspace)
+		log.SetErrorCategory(log.ErrorBuild)
+		defer log.SetErrorCategory(log.ErrorUndefined)
+
+		envRootPath := filepath.Join(workspace, ".pipeline")
+		PersistStepResult(envRootPath, "", "testStep", false, time.Now(), "")
+
+		result := readStepResult(t, filepath.Join(envRootPath, "stepResults", "testStep.json"))
+		assert.Empty(t, result.StageName)
+		assert.Equal(t, "failure", result.Status)
+		assert.Equal(t, "build", result.ErrorCategory)
+		assert.Equal(t, []Path{}, result.Artifacts)
+		assert.Equal(t, []Path{}, result.Reports)
+		assert.Equal(t, []Path{}, result.Links)
+	})
+}
+
+func TestStepResultFileName(t *testing.T) {
+	assert.Equal(t, "testStep.json", StepResultFileName("", "testStep"))
+	assert.Equal(t, "Build-testStep.json", StepResultFileName("Build", "testStep"))
+	assert.Equal(t, "Integration_Acceptance_Tests-testStep.json", StepResultFileName("Integration/Acceptance Tests", "testStep"))
+	assert.Equal(t, "_.._etc-testStep.json", StepResultFileName("/../etc", "testStep"))
+}
@@ -0,0 +1,148 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/pkg/errors"
+)
+
+// Decision defines how a finding of a policy affects the pipeline
+type Decision string
+
+const (
+	// Deny marks a finding which violates a policy
+	Deny Decision = "deny"
+	// Warn marks a finding which is only reported
+	Warn Decision = "warn"
+)
+
+// ruleDecisions maps the supported rule names to their decision, rule names follow the conventions of conftest
+var ruleDecisions = []struct {
+	rule     string
+	decision Decision
+}{
+	{"deny", Deny},
+	{"violation", Deny},
+	{"warn", Warn},
+}
+
+// Module defines a compiled policy module written in the Rego policy language
+type Module struct {
+	Name    string
+	Package string
+	queries map[string]rego.PreparedEvalQuery
+}
+
+// Finding defines a message produced by one of the deny, violation or warn rules of a policy module
+type Finding struct {
+	Decision Decision
+	Module   string
+	Package  string
+	Rule     string
+	Message  string
+}
+
+// ParseModule parses and compiles the source of a policy module, the name is used in error messages and findings
+func ParseModule(name, source string) (*Module, error) {
+	parsed, err := ast.ParseModule(name, source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse policy '%v'", name)
+	}
+	compiler := ast.NewCompiler()
+	if compiler.Compile(map[string]*ast.Module{name: parsed}); compiler.Failed() {
+		return nil, errors.Wrapf(compiler.Errors, "failed to compile policy '%v'", name)
+	}
+
+	module := &Module{
+		Name:    name,
+		Package: strings.TrimPrefix(parsed.Package.Path.String(), "data."),
+		queries: map[string]rego.PreparedEvalQuery{},
+	}
+	for _, ruleDecision := range ruleDecisions {
+		if !definesRule(parsed, ruleDecision.rule) {
+			continue
+		}
+		query, err := rego.New(
+			rego.Query(fmt.Sprintf("%v.%v", parsed.Package.Path, ruleDecision.rule)),
+			rego.Compiler(compiler),
+		).PrepareForEval(context.Background())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to prepare rule '%v' of policy '%v'", ruleDecision.rule, name)
+		}
+		module.queries[ruleDecision.rule] = query
+	}
+	return module, nil
+}
+
+func definesRule(module *ast.Module, name string) bool {
+	for _, rule := range module.Rules {
+		if rule.Head.Name.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate evaluates the deny, violation and warn rules of the module against the input document.
+// The input has to consist of JSON compatible types, i.e. as produced by json.Unmarshal into an interface{}.
+func (m *Module) Evaluate(input interface{}) ([]Finding, error) {
+	findings := []Finding{}
+	for _, ruleDecision := range ruleDecisions {
+		query, ok := m.queries[ruleDecision.rule]
+		if !ok {
+			continue
+		}
+		results, err := query.Eval(context.Background(), rego.EvalInput(input))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate policy '%v'", m.Name)
+		}
+		// an undefined rule, e.g. a boolean rule whose body is not satisfied, has no results
+		for _, result := range results {
+			for _, expression := range result.Expressions {
+				for _, message := range messages(expression.Value) {
+					findings = append(findings, Finding{
+						Decision: ruleDecision.decision,
+						Module:   m.Name,
+						Package:  m.Package,
+						Rule:     ruleDecision.rule,
+						Message:  message,
+					})
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// messages returns the messages of a rule value: the elements of a set, the msg field of objects or the value itself
+func messages(value interface{}) []string {
+	switch typed := value.(type) {
+	case bool:
+		if typed {
+			return []string{"policy violated"}
+		}
+		return nil
+	case string:
+		return []string{typed}
+	case []interface{}:
+		result := []string{}
+		for _, element := range typed {
+			result = append(result, messages(element)...)
+		}
+		return result
+	case map[string]interface{}:
+		if msg, ok := typed["msg"].(string); ok {
+			return []string{msg}
+		}
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return []string{fmt.Sprint(value)}
+	}
+	return []string{string(encoded)}
+}
@@ -0,0 +1,242 @@
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseInput(t *testing.T, content string) interface{} {
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(content), &input))
+	return input
+}
+
+func evaluate(t *testing.T, source, input string) []Finding {
+	module, err := ParseModule("test.rego", source)
+	require.NoError(t, err)
+	findings, err := module.Evaluate(parseInput(t, input))
+	require.NoError(t, err)
+	return findings
+}
+
+func findingMessages(findings []Finding, decision Decision) []string {
+	result := []string{}
+	for _, finding := range findings {
+		if finding.Decision == decision {
+			result = append(result, finding.Message)
+		}
+	}
+	return result
+}
+
+func TestParseModule(t *testing.T) {
+	t.Parallel()
+	t.Run("package and rules", func(t *testing.T) {
+		module, err := ParseModule("test.rego", `
+# comment
+package terraform.security
+
+default allow = false
+
+deny[msg] {
+	msg := "denied"
+}
+
+warn[msg] {
+	msg := "warned"
+}
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "test.rego", module.Name)
+		assert.Equal(t, "terraform.security", module.Package)
+		assert.Contains(t, module.queries, "deny")
+		assert.Contains(t, module.queries, "warn")
+		assert.NotContains(t, module.queries, "violation")
+	})
+
+	t.Run("missing package", func(t *testing.T) {
+		_, err := ParseModule("test.rego", "deny[msg] { msg := \"x\" }")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse policy 'test.rego'")
+		assert.Contains(t, err.Error(), "package expected")
+	})
+
+	t.Run("empty policy", func(t *testing.T) {
+		_, err := ParseModule("test.rego", "# nothing\n")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty module")
+	})
+
+	t.Run("unsafe variable", func(t *testing.T) {
+		_, err := ParseModule("test.rego", "package test\n\ndeny[msg] {\n\tmsg := unknown\n}\n")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to compile policy 'test.rego'")
+		assert.Contains(t, err.Error(), "var unknown is unsafe")
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		_, err := ParseModule("test.rego", "package test\n\ndeny[msg] {\n\tmsg := unknown(input)\n}\n")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to compile policy 'test.rego'")
+		assert.Contains(t, err.Error(), "undefined function unknown")
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	t.Run("terraform plan", func(t *testing.T) {
+		policy := `package terraform
+
+deny[msg] {
+	change := input.resource_changes[_]
+	change.type == "aws_s3_bucket"
+	change.change.after.acl == "public-read"
+	msg := sprintf("bucket '%s' must not be public", [change.address])
+}
+
+warn[msg] {
+	some i
+	input.resource_changes[i].change.actions[_] == "delete"
+	msg := sprintf("resource %d is destroyed", [i])
+}
+`
+		findings := evaluate(t, policy, `{"resource_changes": [
+			{"address": "aws_s3_bucket.a", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {"acl": "public-read"}}},
+			{"address": "aws_s3_bucket.b", "type": "aws_s3_bucket", "change": {"actions": ["delete"], "after": {"acl": "private"}}}
+		]}`)
+		assert.Equal(t, []string{"bucket 'aws_s3_bucket.a' must not be public"}, findingMessages(findings, Deny))
+		assert.Equal(t, []string{"resource 1 is destroyed"}, findingMessages(findings, Warn))
+		assert.Equal(t, "deny", findings[0].Rule)
+		assert.Equal(t, "terraform", findings[0].Package)
+		assert.Equal(t, "test.rego", findings[0].Module)
+	})
+
+	t.Run("kubernetes manifest", func(t *testing.T) {
+		policy := `package kubernetes
+
+privileged(container) {
+	container.securityContext.privileged == true
+}
+
+deny[msg] {
+	input.kind == "Deployment"
+	container := input.spec.template.spec.containers[_]
+	privileged(container)
+	msg := sprintf("container '%s' must not be privileged", [container.name])
+}
+
+deny[msg] {
+	container := input.spec.template.spec.containers[_]
+	endswith(container.image, ":latest")
+	msg := sprintf("container '%s' uses the latest tag", [container.name])
+}
+
+violation[{"msg": msg}] {
+	not input.metadata.labels.team
+	msg := "team label is missing"
+}
+`
+		findings := evaluate(t, policy, `{"kind": "Deployment", "metadata": {"name": "app", "labels": {}}, "spec": {"template": {"spec": {"containers": [
+			{"name": "a", "image": "app:latest", "securityContext": {"privileged": true}},
+			{"name": "b", "image": "app:1.0"}
+		]}}}}`)
+		assert.ElementsMatch(t, []string{
+			"container 'a' must not be privileged",
+			"container 'a' uses the latest tag",
+			"team label is missing",
+		}, findingMessages(findings, Deny))
+
+		findings = evaluate(t, policy, `{"kind": "Deployment", "metadata": {"name": "app", "labels": {"team": "x"}}, "spec": {"template": {"spec": {"containers": [
+			{"name": "b", "image": "app:1.0"}
+		]}}}}`)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("complete rules, defaults and functions", func(t *testing.T) {
+		policy := `package cloudfoundry
+
+default max_instances = 10
+
+max_instances = 2 {
+	input.space == "dev"
+}
+
+limit(app) = value {
+	value := object.get(app, "instances", 1)
+}
+
+memory_mb(app) = to_number(trim_suffix) {
+	trim_suffix := split(upper(app.memory), "M")[0]
+}
+
+deny[msg] {
+	app := input.applications[_]
+	limit(app) > max_instances
+	msg := sprintf("%s: %v instances exceed the limit of %v", [app.name, limit(app), max_instances])
+}
+
+deny[msg] {
+	app := input.applications[_]
+	memory_mb(app) * 2 >= 2048
+	msg := concat(" ", [app.name, "uses too much memory"])
+}
+
+warn[msg] {
+	app := input.applications[_]
+	not regex.match("^[a-z-]+$", app.name)
+	msg := sprintf("%s: invalid name", [app.name])
+}
+`
+		findings := evaluate(t, policy, `{"space": "dev", "applications": [
+			{"name": "app-a", "instances": 3, "memory": "1024m"},
+			{"name": "App_B", "memory": "256M"}
+		]}`)
+		assert.ElementsMatch(t, []string{"app-a: 3 instances exceed the limit of 2", "app-a uses too much memory"}, findingMessages(findings, Deny))
+		assert.Equal(t, []string{"App_B: invalid name"}, findingMessages(findings, Warn))
+
+		findings = evaluate(t, policy, `{"space": "prod", "applications": [{"name": "app-a", "instances": 3, "memory": "128M"}]}`)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("boolean rule", func(t *testing.T) {
+		findings := evaluate(t, "package test\n\ndeny {\n\tcount(input.items) > 1\n}\n", `{"items": [1, 2]}`)
+		assert.Equal(t, []string{"policy violated"}, findingMessages(findings, Deny))
+
+		findings = evaluate(t, "package test\n\ndeny {\n\tcount(input.items) > 1\n}\n", `{"items": [1]}`)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("helper sets", func(t *testing.T) {
+		policy := `package test
+
+allowed_regions := {"eu10", "us10"}
+
+regions[region] {
+	region := input.resources[_].region
+}
+
+deny[msg] {
+	region := regions[_]
+	not allowed_regions[region]
+	msg := sprintf("region %s is not allowed", [region])
+}
+`
+		findings := evaluate(t, policy, `{"resources": [{"region": "eu10"}, {"region": "ap10"}, {"region": "ap10"}]}`)
+		assert.Equal(t, []string{"region ap10 is not allowed"}, findingMessages(findings, Deny))
+	})
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	t.Parallel()
+	t.Run("conflicting values", func(t *testing.T) {
+		module, err := ParseModule("test.rego", "package test\n\nname = n {\n\tn := input.names[_]\n}\n\ndeny[name] {\n\ttrue\n}\n")
+		require.NoError(t, err)
+		_, err = module.Evaluate(parseInput(t, `{"names": ["a", "b"]}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to evaluate policy 'test.rego'")
+		assert.Contains(t, err.Error(), "complete rules must not produce multiple outputs")
+	})
+}
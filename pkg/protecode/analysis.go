@@ -1,9 +1,13 @@
 package protecode
 
-import "strconv"
+import (
+	"strconv"
+
+	piperVulnerability "github.com/SAP/jenkins-library/pkg/vulnerability"
+)
 
 const (
-	vulnerabilitySeverityThreshold = 7.0
+	vulnerabilitySeverityThreshold = piperVulnerability.DefaultSeverityThreshold
 )
 
 //HasFailed checks the return status of the provided result
@@ -29,12 +33,7 @@ func HasSevereVulnerabilities(result Result, excludeCVEs string) bool {
 
 func isSevere(vulnerability Vulnerability) bool {
 	cvss3, _ := strconv.ParseFloat(vulnerability.Vuln.Cvss3Score, 64)
-	if cvss3 >= vulnerabilitySeverityThreshold {
-		return true
-	}
 	// CVSS v3 not set, fallback to CVSS v2
-	if cvss3 == 0 && vulnerability.Vuln.Cvss >= vulnerabilitySeverityThreshold {
-		return true
-	}
-	return false
+	score, _ := piperVulnerability.Score(cvss3, vulnerability.Vuln.Cvss)
+	return score >= vulnerabilitySeverityThreshold
 }
@@ -0,0 +1,42 @@
+package protecode
+
+import (
+	"fmt"
+	"strconv"
+
+	piperVulnerability "github.com/SAP/jenkins-library/pkg/vulnerability"
+)
+
+// ConvertResultToFindings maps the vulnerabilities of a Protecode scan result to the common finding model.
+// Historic vulnerabilities are skipped, triaged and excluded vulnerabilities are suppressed.
+func ConvertResultToFindings(result Result, excludeCVEs, scannedFile string) []piperVulnerability.Finding {
+	findings := []piperVulnerability.Finding{}
+	for _, component := range result.Components {
+		for _, vulnerability := range component.Vulns {
+			if !isExact(vulnerability) {
+				continue
+			}
+			cvss3, _ := strconv.ParseFloat(vulnerability.Vuln.Cvss3Score, 64)
+			score, cvssVersion := piperVulnerability.Score(cvss3, vulnerability.Vuln.Cvss)
+			finding := piperVulnerability.Finding{
+				ID:          vulnerability.Vuln.Cve,
+				CVE:         piperVulnerability.CVEFromID(vulnerability.Vuln.Cve),
+				Component:   &piperVulnerability.Component{Name: component.Lib, Version: component.Version},
+				CVSS:        score,
+				CVSSVersion: cvssVersion,
+				Description: vulnerability.Vuln.Summary,
+				URL:         fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%v", vulnerability.Vuln.Cve),
+				Location:    piperVulnerability.Location{File: scannedFile},
+				Tools:       []string{"Protecode"},
+				Status:      piperVulnerability.StatusOpen,
+			}
+			if isTriaged(vulnerability) {
+				finding.Status, finding.Justification = piperVulnerability.StatusSuppressed, vulnerability.Triage[0].Description
+			} else if isExcluded(vulnerability, excludeCVEs) {
+				finding.Status, finding.Justification = piperVulnerability.StatusSuppressed, "excluded via configuration"
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,44 @@
+package protecode
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertResultToFindings(t *testing.T) {
+	result := Result{Components: []Component{{
+		Lib:     "log4j",
+		Version: "2.14.1",
+		Vulns: []Vulnerability{
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2021-44228", Cvss: 9.3, Cvss3Score: "10.0", Summary: "JNDI lookup"}},
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2021-45046", Cvss: 5.1}},
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2021-45105", Cvss3Score: "5.9"}, Triage: []Triage{{Description: "not used"}}},
+			{Exact: false, Vuln: Vuln{Cve: "CVE-2017-5645", Cvss3Score: "9.8"}},
+		},
+	}}}
+
+	findings := ConvertResultToFindings(result, "CVE-2021-45046", "app.jar")
+
+	require.Len(t, findings, 3)
+	assert.Equal(t, vulnerability.Finding{
+		ID:          "CVE-2021-44228",
+		CVE:         "CVE-2021-44228",
+		Component:   &vulnerability.Component{Name: "log4j", Version: "2.14.1"},
+		CVSS:        10.0,
+		CVSSVersion: "3",
+		Description: "JNDI lookup",
+		URL:         "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+		Location:    vulnerability.Location{File: "app.jar"},
+		Tools:       []string{"Protecode"},
+		Status:      vulnerability.StatusOpen,
+	}, findings[0])
+	assert.Equal(t, 5.1, findings[1].CVSS)
+	assert.Equal(t, "2", findings[1].CVSSVersion)
+	assert.Equal(t, vulnerability.StatusSuppressed, findings[1].Status)
+	assert.Equal(t, "excluded via configuration", findings[1].Justification)
+	assert.Equal(t, vulnerability.StatusSuppressed, findings[2].Status)
+	assert.Equal(t, "not used", findings[2].Justification)
+}
@@ -41,7 +41,17 @@ type Result struct {
 
 //Component the protecode component information
 type Component struct {
-	Vulns []Vulnerability `json:"vulns,omitempty"`
+	Lib     string          `json:"lib,omitempty"`
+	Version string          `json:"version,omitempty"`
+	License *License        `json:"license,omitempty"`
+	Vulns   []Vulnerability `json:"vulns,omitempty"`
+}
+
+//License the license of a component as identified by protecode
+type License struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
 }
 
 //Vulnerability the protecode vulnerability information
@@ -56,6 +66,7 @@ type Vuln struct {
 	Cve        string  `json:"cve,omitempty"`
 	Cvss       float64 `json:"cvss,omitempty"`
 	Cvss3Score string  `json:"cvss3_score,omitempty"`
+	Summary    string  `json:"summary,omitempty"`
 }
 
 //Triage holds the triaging information
@@ -0,0 +1,62 @@
+package protecode
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// ConvertResultToSarif converts the vulnerabilities of a Protecode scan result into a SARIF log.
+// Historic vulnerabilities are skipped, triaged and excluded vulnerabilities are marked as suppressed.
+// The scanned file is used as location of all vulnerabilities.
+func ConvertResultToSarif(result Result, excludeCVEs, scannedFile string) format.SARIF {
+	run := format.NewRun("Protecode", "", "https://www.synopsys.com/software-integrity/security-testing/software-composition-analysis.html")
+	for _, component := range result.Components {
+		for _, vulnerability := range component.Vulns {
+			if !isExact(vulnerability) {
+				continue
+			}
+			score, _ := strconv.ParseFloat(vulnerability.Vuln.Cvss3Score, 64)
+			if score == 0 {
+				score = vulnerability.Vuln.Cvss
+			}
+
+			cve := vulnerability.Vuln.Cve
+			run.AddRule(format.SarifRule{
+				ID:               cve,
+				Name:             cve,
+				ShortDescription: &format.Message{Text: cve},
+				FullDescription:  &format.Message{Text: vulnerability.Vuln.Summary},
+				HelpURI:          fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%v", cve),
+				Properties:       &format.SarifRuleProperties{Tags: []string{"security", "vulnerability"}, SecuritySeverity: format.SecuritySeverity(score)},
+			})
+
+			level := format.SarifLevelFromCVSS(score)
+			if level == format.SarifLevelError && !isSevere(vulnerability) {
+				level = format.SarifLevelWarning
+			}
+			library := fmt.Sprintf("%v %v", component.Lib, component.Version)
+			sarifResult := format.Results{
+				RuleID:              cve,
+				Level:               level,
+				Message:             format.Message{Text: fmt.Sprintf("%v in %v", cve, library)},
+				PartialFingerprints: map[string]string{"protecodeVulnerability": fmt.Sprintf("%v/%v/%v", component.Lib, component.Version, cve)},
+				Properties: map[string]string{
+					"library":   library,
+					"cvssScore": fmt.Sprint(score),
+				},
+			}
+			if len(scannedFile) > 0 {
+				sarifResult.Locations = []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: scannedFile}}}}
+			}
+			if isTriaged(vulnerability) {
+				sarifResult.Suppressions = []format.Suppression{{Kind: "external", Status: "accepted", Justification: vulnerability.Triage[0].Description}}
+			} else if isExcluded(vulnerability, excludeCVEs) {
+				sarifResult.Suppressions = []format.Suppression{{Kind: "external", Status: "accepted", Justification: "excluded via configuration"}}
+			}
+			run.AddResult(sarifResult)
+		}
+	}
+	return format.NewSARIF(run)
+}
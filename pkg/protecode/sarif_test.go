@@ -0,0 +1,54 @@
+package protecode
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertResultToSarif(t *testing.T) {
+	result := Result{Components: []Component{
+		{Lib: "openssl", Version: "1.1.1d", Vulns: []Vulnerability{
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2020-1967", Cvss: 5.0, Cvss3Score: "7.5", Summary: "Segmentation fault in SSL_check_chain"}},
+			{Exact: false, Vuln: Vuln{Cve: "CVE-2016-0001", Cvss: 9.0}},
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2020-1971", Cvss: 4.3, Cvss3Score: "5.9"}, Triage: []Triage{{Description: "not reachable"}}},
+		}},
+		{Lib: "zlib", Version: "1.2.11", Vulns: []Vulnerability{
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2018-25032", Cvss: 7.5}},
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2022-37434", Cvss3Score: "9.8"}},
+		}},
+	}}
+
+	sarif := ConvertResultToSarif(result, "CVE-2022-37434", "app.tar")
+
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	assert.Equal(t, "Protecode", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 4)
+	rule := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "CVE-2020-1967", rule.ID)
+	assert.Equal(t, "Segmentation fault in SSL_check_chain", rule.FullDescription.Text)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2020-1967", rule.HelpURI)
+	assert.Equal(t, "7.5", rule.Properties.SecuritySeverity)
+
+	// historic vulnerabilities are skipped
+	require.Len(t, run.Results, 4)
+	first := run.Results[0]
+	assert.Equal(t, format.SarifLevelError, first.Level)
+	assert.Equal(t, "CVE-2020-1967 in openssl 1.1.1d", first.Message.Text)
+	assert.Equal(t, "app.tar", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Empty(t, first.Suppressions)
+
+	// triaged
+	assert.Equal(t, format.SarifLevelWarning, run.Results[1].Level)
+	assert.Equal(t, []format.Suppression{{Kind: "external", Status: "accepted", Justification: "not reachable"}}, run.Results[1].Suppressions)
+
+	// CVSS v2 fallback
+	assert.Equal(t, "7.5", run.Results[2].Properties["cvssScore"])
+	assert.Equal(t, format.SarifLevelError, run.Results[2].Level)
+
+	// excluded
+	assert.Equal(t, []format.Suppression{{Kind: "external", Status: "accepted", Justification: "excluded via configuration"}}, run.Results[3].Suppressions)
+}
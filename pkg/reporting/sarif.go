@@ -0,0 +1,58 @@
+package reporting
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// Category returns the category of the report's findings which consists of the step name and the report title
+func (s *ScanReport) Category() string {
+	if len(s.StepName) == 0 {
+		return s.Title
+	}
+	return fmt.Sprintf("%v/%v", s.StepName, s.Title)
+}
+
+// ToSARIF converts the report into a SARIF log.
+// The report represents one rule, every row of the detail table is reported as one result.
+// The level of a result is derived from the styles of the row's cells.
+func (s *ScanReport) ToSARIF() format.SARIF {
+	toolName := s.StepName
+	if len(toolName) == 0 {
+		toolName = s.Title
+	}
+	run := format.NewRun(toolName, "", "")
+	run.AddRule(format.SarifRule{
+		ID:               s.Category(),
+		Name:             s.Title,
+		ShortDescription: &format.Message{Text: s.Title},
+	})
+	for _, row := range s.DetailTable.Rows {
+		texts := []string{}
+		level := format.SarifLevelNote
+		for i, cell := range row.Columns {
+			if i < len(s.DetailTable.Headers) && len(s.DetailTable.Headers[i]) > 0 {
+				texts = append(texts, fmt.Sprintf("%v: %v", s.DetailTable.Headers[i], cell.Content))
+			} else {
+				texts = append(texts, cell.Content)
+			}
+			switch {
+			case cell.Style == Red:
+				level = format.SarifLevelError
+			case cell.Style == Yellow && level != format.SarifLevelError:
+				level = format.SarifLevelWarning
+			}
+		}
+		message := strings.Join(texts, ", ")
+		run.AddResult(format.Results{
+			RuleID:              s.Category(),
+			Level:               level,
+			Message:             format.Message{Text: message},
+			PartialFingerprints: map[string]string{"scanReportFinding": fmt.Sprintf("%x", sha1.Sum([]byte(s.Category()+message)))},
+		})
+	}
+	return format.NewSARIF(run)
+}
@@ -0,0 +1,53 @@
+package reporting
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, "Fortify SAST Report", (&ScanReport{Title: "Fortify SAST Report"}).Category())
+	assert.Equal(t, "fortifyExecuteScan/Fortify SAST Report", (&ScanReport{StepName: "fortifyExecuteScan", Title: "Fortify SAST Report"}).Category())
+}
+
+func TestToSARIF(t *testing.T) {
+	t.Run("with findings", func(t *testing.T) {
+		scanReport := ScanReport{
+			StepName: "whitesourceExecuteScan",
+			Title:    "WhiteSource Security Vulnerability Report",
+			DetailTable: ScanDetailTable{
+				Headers: []string{"Vulnerability", "Severity"},
+				Rows: []ScanRow{
+					{Columns: []ScanCell{{Content: "CVE-1"}, {Content: "high", Style: Red}}},
+					{Columns: []ScanCell{{Content: "CVE-2"}, {Content: "medium", Style: Yellow}}},
+					{Columns: []ScanCell{{Content: "CVE-3"}, {Content: "low"}, {Content: "no header"}}},
+				},
+			},
+		}
+
+		sarif := scanReport.ToSARIF()
+
+		require.Len(t, sarif.Runs, 1)
+		run := sarif.Runs[0]
+		assert.Equal(t, "whitesourceExecuteScan", run.Tool.Driver.Name)
+		assert.Equal(t, []format.SarifRule{{ID: "whitesourceExecuteScan/WhiteSource Security Vulnerability Report", Name: "WhiteSource Security Vulnerability Report", ShortDescription: &format.Message{Text: "WhiteSource Security Vulnerability Report"}}}, run.Tool.Driver.Rules)
+		require.Len(t, run.Results, 3)
+		assert.Equal(t, format.SarifLevelError, run.Results[0].Level)
+		assert.Equal(t, "Vulnerability: CVE-1, Severity: high", run.Results[0].Message.Text)
+		assert.Equal(t, format.SarifLevelWarning, run.Results[1].Level)
+		assert.Equal(t, format.SarifLevelNote, run.Results[2].Level)
+		assert.Equal(t, "Vulnerability: CVE-3, Severity: low, no header", run.Results[2].Message.Text)
+		assert.NotEqual(t, run.Results[0].PartialFingerprints["scanReportFinding"], run.Results[1].PartialFingerprints["scanReportFinding"])
+	})
+
+	t.Run("without findings", func(t *testing.T) {
+		scanReport := ScanReport{Title: "Fortify SAST Report"}
+		sarif := scanReport.ToSARIF()
+		require.Len(t, sarif.Runs, 1)
+		assert.Equal(t, "Fortify SAST Report", sarif.Runs[0].Tool.Driver.Name)
+		assert.Empty(t, sarif.Runs[0].Results)
+	})
+}
@@ -1,6 +1,7 @@
 package sonar
 
 import (
+	"fmt"
 	"net/http"
 
 	sonargo "github.com/magicsong/sonargo/sonar"
@@ -44,12 +45,16 @@ func (service *IssueService) SearchIssues(options *IssuesSearchOption) (*sonargo
 	return result, response, nil
 }
 
-func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+// issuesPageSize is the maximum page size supported by the issues search API
+const issuesPageSize = 500
+
+// issuesSearchLimit is the maximum number of issues returned by the issues search API
+const issuesSearchLimit = 10000
+
+func (service *IssueService) unresolvedIssuesOption() *IssuesSearchOption {
 	options := &IssuesSearchOption{
 		ComponentKeys: service.Project,
-		Severities:    severity.ToString(),
 		Resolved:      "false",
-		Ps:            "1",
 	}
 	if len(service.Branch) > 0 {
 		options.Branch = service.Branch
@@ -60,6 +65,13 @@ func (service *IssueService) getIssueCount(severity issueSeverity) (int, error)
 	if len(service.PullRequest) > 0 {
 		options.PullRequest = service.PullRequest
 	}
+	return options
+}
+
+func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+	options := service.unresolvedIssuesOption()
+	options.Severities = severity.ToString()
+	options.Ps = "1"
 	result, _, err := service.SearchIssues(options)
 	if err != nil {
 		return -1, errors.Wrapf(err, "failed to fetch the numer of '%s' issues", severity)
@@ -67,6 +79,30 @@ func (service *IssueService) getIssueCount(severity issueSeverity) (int, error)
 	return result.Total, nil
 }
 
+// GetAllIssues returns all unresolved issues together with the rules and components referenced by them.
+// Since the search API is limited, at most 10000 issues are returned.
+func (service *IssueService) GetAllIssues() (*sonargo.IssuesSearchObject, error) {
+	all := &sonargo.IssuesSearchObject{}
+	for page := 1; page*issuesPageSize <= issuesSearchLimit; page++ {
+		options := service.unresolvedIssuesOption()
+		options.AdditionalFields = "rules"
+		options.P = fmt.Sprint(page)
+		options.Ps = fmt.Sprint(issuesPageSize)
+		result, _, err := service.SearchIssues(options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch issues")
+		}
+		all.Total = result.Total
+		all.Issues = append(all.Issues, result.Issues...)
+		all.Rules = append(all.Rules, result.Rules...)
+		all.Components = append(all.Components, result.Components...)
+		if len(result.Issues) == 0 || len(all.Issues) >= result.Total {
+			break
+		}
+	}
+	return all, nil
+}
+
 // GetNumberOfBlockerIssues returns the number of issue with BLOCKER severity.
 func (service *IssueService) GetNumberOfBlockerIssues() (int, error) {
 	return service.getIssueCount(blocker)
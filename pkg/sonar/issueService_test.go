@@ -70,6 +70,57 @@ func TestIssueService(t *testing.T) {
 	})
 }
 
+func TestGetAllIssues(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("success with paging", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		pages := []string{}
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch, func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			pages = append(pages, query.Get("p"))
+			assert.Equal(t, "rules", query.Get("additionalFields"))
+			assert.Equal(t, "false", query.Get("resolved"))
+			assert.Equal(t, "my-branch", query.Get("branch"))
+			if query.Get("p") == "1" {
+				return httpmock.NewStringResponse(http.StatusOK, `{"total": 2, "issues": [{"key": "issue1", "rule": "java:S1234"}], "rules": [{"key": "java:S1234", "name": "Rule 1234"}]}`), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{"total": 2, "issues": [{"key": "issue2", "rule": "java:S1234"}], "components": [{"key": "project:src/App.java", "path": "src/App.java"}]}`), nil
+		})
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "project", "", "my-branch", "", sender)
+		// test
+		result, err := serviceUnderTest.GetAllIssues()
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, pages)
+		assert.Equal(t, 2, result.Total)
+		assert.Len(t, result.Issues, 2)
+		assert.Len(t, result.Rules, 1)
+		assert.Len(t, result.Components, 1)
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch, httpmock.NewStringResponder(http.StatusNotFound, responseIssueSearchError))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "project", "", "", "", sender)
+		// test
+		_, err := serviceUnderTest.GetAllIssues()
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch issues")
+	})
+}
+
 const responseIssueSearchError = `{
   "errors": [
     {
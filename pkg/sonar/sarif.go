@@ -0,0 +1,86 @@
+package sonar
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	sonargo "github.com/magicsong/sonargo/sonar"
+)
+
+// ConvertIssuesToSarif converts the issues of a SonarQube project into a SARIF log
+func ConvertIssuesToSarif(issues *sonargo.IssuesSearchObject, serverURL, projectKey string) format.SARIF {
+	run := format.NewRun("SonarQube", "", "https://www.sonarqube.org")
+	if issues == nil {
+		return format.NewSARIF(run)
+	}
+	serverURL = strings.TrimSuffix(serverURL, "/")
+
+	rules := map[string]*sonargo.Rule{}
+	for _, rule := range issues.Rules {
+		if rule != nil {
+			rules[rule.Key] = rule
+		}
+	}
+	paths := map[string]string{}
+	for _, component := range issues.Components {
+		if component != nil && len(component.Path) > 0 {
+			paths[component.Key] = component.Path
+		}
+	}
+
+	for _, issue := range issues.Issues {
+		if issue == nil {
+			continue
+		}
+		rule := format.SarifRule{
+			ID:         issue.Rule,
+			HelpURI:    fmt.Sprintf("%v/coding_rules?open=%v&rule_key=%v", serverURL, url.QueryEscape(issue.Rule), url.QueryEscape(issue.Rule)),
+			Properties: &format.SarifRuleProperties{Tags: []string{strings.ToLower(issue.Type)}},
+		}
+		if sonarRule, ok := rules[issue.Rule]; ok {
+			rule.Name = sonarRule.Name
+			rule.ShortDescription = &format.Message{Text: sonarRule.Name}
+			if len(sonarRule.LangName) > 0 {
+				rule.Properties.Tags = append(rule.Properties.Tags, sonarRule.LangName)
+			}
+		}
+		if issue.Type == "VULNERABILITY" {
+			rule.Properties.Tags = append(rule.Properties.Tags, "security")
+		}
+		run.AddRule(rule)
+
+		result := format.Results{
+			RuleID:              issue.Rule,
+			Level:               format.SarifLevelFromSeverity(issue.Severity),
+			Message:             format.Message{Text: issue.Message},
+			PartialFingerprints: map[string]string{"sonarIssueKey": issue.Key},
+			Properties: map[string]string{
+				"severity": issue.Severity,
+				"type":     issue.Type,
+				"status":   issue.Status,
+			},
+		}
+		path, ok := paths[issue.Component]
+		if !ok {
+			path = strings.TrimPrefix(issue.Component, projectKey+":")
+		}
+		if len(path) > 0 && path != projectKey {
+			location := format.Location{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: path}}}
+			if issue.TextRange != nil && issue.TextRange.StartLine > 0 {
+				location.PhysicalLocation.Region = &format.Region{
+					StartLine:   issue.TextRange.StartLine,
+					StartColumn: issue.TextRange.StartOffset + 1,
+					EndLine:     issue.TextRange.EndLine,
+					EndColumn:   issue.TextRange.EndOffset + 1,
+				}
+			} else if issue.Line > 0 {
+				location.PhysicalLocation.Region = &format.Region{StartLine: issue.Line}
+			}
+			result.Locations = []format.Location{location}
+		}
+		run.AddResult(result)
+	}
+	return format.NewSARIF(run)
+}
@@ -0,0 +1,63 @@
+package sonar
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertIssuesToSarif(t *testing.T) {
+	t.Run("issues", func(t *testing.T) {
+		issues := &sonargo.IssuesSearchObject{
+			Issues: []*sonargo.Issue{
+				{Key: "AX1", Rule: "java:S2076", Severity: "CRITICAL", Type: "VULNERABILITY", Component: "my-project:src/main/java/App.java", Message: "Make sure that this command is safe.", TextRange: &sonargo.TextRange{StartLine: 10, StartOffset: 4, EndLine: 10, EndOffset: 20}},
+				{Key: "AX2", Rule: "java:S1481", Severity: "MINOR", Type: "CODE_SMELL", Component: "my-project:Util", Message: "Remove this unused variable.", Line: 7},
+				{Key: "AX3", Rule: "java:S1481", Severity: "INFO", Type: "CODE_SMELL", Component: "my-project", Message: "Project level issue."},
+			},
+			Rules: []*sonargo.Rule{
+				{Key: "java:S2076", Name: "OS commands should not be vulnerable to injection attacks", LangName: "Java"},
+			},
+			Components: []*sonargo.Component{
+				{Key: "my-project:Util", Path: "src/main/java/Util.java"},
+			},
+		}
+
+		sarif := ConvertIssuesToSarif(issues, "https://sonar.example.org/", "my-project")
+
+		require.Len(t, sarif.Runs, 1)
+		run := sarif.Runs[0]
+		assert.Equal(t, "SonarQube", run.Tool.Driver.Name)
+		require.Len(t, run.Tool.Driver.Rules, 2)
+		rule := run.Tool.Driver.Rules[0]
+		assert.Equal(t, "java:S2076", rule.ID)
+		assert.Equal(t, "OS commands should not be vulnerable to injection attacks", rule.Name)
+		assert.Equal(t, "https://sonar.example.org/coding_rules?open=java%3AS2076&rule_key=java%3AS2076", rule.HelpURI)
+		assert.Equal(t, []string{"vulnerability", "Java", "security"}, rule.Properties.Tags)
+
+		require.Len(t, run.Results, 3)
+		first := run.Results[0]
+		assert.Equal(t, format.SarifLevelError, first.Level)
+		assert.Equal(t, "Make sure that this command is safe.", first.Message.Text)
+		assert.Equal(t, "src/main/java/App.java", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, &format.Region{StartLine: 10, StartColumn: 5, EndLine: 10, EndColumn: 21}, first.Locations[0].PhysicalLocation.Region)
+		assert.Equal(t, map[string]string{"sonarIssueKey": "AX1"}, first.PartialFingerprints)
+
+		second := run.Results[1]
+		assert.Equal(t, format.SarifLevelNote, second.Level)
+		assert.Equal(t, "src/main/java/Util.java", second.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, &format.Region{StartLine: 7}, second.Locations[0].PhysicalLocation.Region)
+
+		// issues on project level have no location
+		assert.Empty(t, run.Results[2].Locations)
+		assert.Equal(t, 1, run.Results[2].RuleIndex)
+	})
+
+	t.Run("no issues", func(t *testing.T) {
+		sarif := ConvertIssuesToSarif(nil, "https://sonar.example.org", "my-project")
+		require.Len(t, sarif.Runs, 1)
+		assert.Empty(t, sarif.Runs[0].Results)
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/pkg/errors"
 
@@ -107,27 +108,32 @@ func readCommonPipelineEnvironment(filePath string) string {
 
 // MonitoringData definition for monitoring
 type MonitoringData struct {
-	PipelineUrlHash string `json:"PipelineUrlHash,omitempty"`
-	BuildUrlHash    string `json:"BuildUrlHash,omitempty"`
-	StageName       string `json:"StageName,omitempty"`
-	StepName        string `json:"StepName,omitempty"`
-	ExitCode        string `json:"ExitCode,omitempty"`
-	Duration        string `json:"Duration,omitempty"`
-	ErrorCode       string `json:"ErrorCode,omitempty"`
-	ErrorCategory   string `json:"ErrorCategory,omitempty"`
-	CorrelationID   string `json:"CorrelationID,omitempty"`
-	CommitHash      string `json:"CommitHash,omitempty"`
-	Branch          string `json:"Branch,omitempty"`
-	GitOwner        string `json:"GitOwner,omitempty"`
-	GitRepository   string `json:"GitRepository,omitempty"`
+	PipelineUrlHash   string `json:"PipelineUrlHash,omitempty"`
+	BuildUrlHash      string `json:"BuildUrlHash,omitempty"`
+	Orchestrator      string `json:"Orchestrator,omitempty"`
+	JobName           string `json:"JobName,omitempty"`
+	BuildID           string `json:"BuildID,omitempty"`
+	PipelineStartTime string `json:"PipelineStartTime,omitempty"`
+	StageName         string `json:"StageName,omitempty"`
+	StepName          string `json:"StepName,omitempty"`
+	ExitCode          string `json:"ExitCode,omitempty"`
+	Duration          string `json:"Duration,omitempty"`
+	ErrorCode         string `json:"ErrorCode,omitempty"`
+	ErrorCategory     string `json:"ErrorCategory,omitempty"`
+	CorrelationID     string `json:"CorrelationID,omitempty"`
+	CommitHash        string `json:"CommitHash,omitempty"`
+	Branch            string `json:"Branch,omitempty"`
+	GitOwner          string `json:"GitOwner,omitempty"`
+	GitRepository     string `json:"GitRepository,omitempty"`
 }
 
 func prepareTelemetry(customTelemetryData telemetry.CustomData) MonitoringData {
 	tData := telemetry.GetData(&customTelemetryData)
 
-	return MonitoringData{
+	monitoringData := MonitoringData{
 		PipelineUrlHash: tData.PipelineURLHash,
 		BuildUrlHash:    tData.BuildURLHash,
+		Orchestrator:    tData.Orchestrator,
 		StageName:       tData.StageName,
 		StepName:        tData.BaseData.StepName,
 		ExitCode:        tData.CustomData.ErrorCode,
@@ -140,6 +146,25 @@ func prepareTelemetry(customTelemetryData telemetry.CustomData) MonitoringData {
 		GitOwner:        readCommonPipelineEnvironment("github/owner"),
 		GitRepository:   readCommonPipelineEnvironment("github/repository"),
 	}
+
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Debug("Splunk data is sent without orchestrator information")
+		return monitoringData
+	}
+	monitoringData.JobName = provider.GetJobName()
+	monitoringData.BuildID = provider.GetBuildId()
+	if startTime := provider.GetPipelineStartTime(); !startTime.IsZero() {
+		monitoringData.PipelineStartTime = startTime.Format(time.RFC3339)
+	}
+	// the commonPipelineEnvironment takes precedence, the orchestrator is only used as fallback
+	if monitoringData.CommitHash == "N/A" && len(provider.GetCommit()) > 0 {
+		monitoringData.CommitHash = provider.GetCommit()
+	}
+	if monitoringData.Branch == "N/A" && len(provider.GetBranch()) > 0 {
+		monitoringData.Branch = provider.GetBranch()
+	}
+	return monitoringData
 }
 
 type Event struct {
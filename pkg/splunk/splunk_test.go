@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestInitialize(t *testing.T) {
@@ -320,6 +322,43 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func Test_prepareTelemetryWithOrchestrator(t *testing.T) {
+	defer resetEnv(os.Environ())
+	os.Clearenv()
+	os.Setenv("GITLAB_CI", "true")
+	os.Setenv("CI_PROJECT_PATH", "foo/bar")
+	os.Setenv("CI_PIPELINE_ID", "42")
+	os.Setenv("GITLAB_USER_LOGIN", "jdoe")
+	os.Setenv("CI_PIPELINE_CREATED_AT", "2021-08-11T09:27:58Z")
+	os.Setenv("CI_COMMIT_SHA", "abcdef42713")
+	os.Setenv("CI_COMMIT_REF_NAME", "main")
+
+	err := Initialize("Correlation-Test", "splunkUrl", "TOKEN", "index", false)
+	if err != nil {
+		t.Errorf("Error Initalizing Splunk. %v", err)
+	}
+	got := prepareTelemetry(telemetry.CustomData{ErrorCode: "0"})
+
+	assert.Equal(t, "foo/bar", got.JobName)
+	assert.Equal(t, "42", got.BuildID)
+	assert.Equal(t, "2021-08-11T09:27:58Z", got.PipelineStartTime)
+	assert.Equal(t, "abcdef42713", got.CommitHash)
+	assert.Equal(t, "main", got.Branch)
+	assert.Equal(t, "N/A", got.GitOwner)
+	// the user triggering the pipeline must not be sent to Splunk
+	payload, err := json.Marshal(got)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(payload), "jdoe")
+}
+
+func resetEnv(e []string) {
+	os.Clearenv()
+	for _, val := range e {
+		tmp := strings.SplitN(val, "=", 2)
+		os.Setenv(tmp[0], tmp[1])
+	}
+}
+
 func Test_prepareTelemetry(t *testing.T) {
 	type args struct {
 		customTelemetryData telemetry.CustomData
@@ -357,6 +396,8 @@ func Test_prepareTelemetry(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			defer resetEnv(os.Environ())
+			os.Clearenv()
 			err := Initialize("Correlation-Test", "splunkUrl", "TOKEN", "index", false)
 			if err != nil {
 				t.Errorf("Error Initalizing Splunk. %v", err)
@@ -0,0 +1,89 @@
+package syft
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// Install downloads the syft release archive and extracts the syft binary into the target directory.
+// The archive is only extracted in case its SHA-256 checksum matches the expected one.
+func Install(downloadURL, expectedSHA256, targetDir string, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils) (string, error) {
+	if len(expectedSHA256) == 0 {
+		return "", fmt.Errorf("no SHA-256 checksum provided for syft archive '%v'", downloadURL)
+	}
+	log.Entry().Infof("Downloading syft from %v", downloadURL)
+	response, err := httpClient.SendRequest(http.MethodGet, downloadURL, nil, nil, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to download syft from '%v'", downloadURL)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download syft from '%v': status code %v", downloadURL, response.StatusCode)
+	}
+
+	archive, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to download syft from '%v'", downloadURL)
+	}
+	checksum := sha256.Sum256(archive)
+	if actualSHA256 := hex.EncodeToString(checksum[:]); !strings.EqualFold(actualSHA256, expectedSHA256) {
+		return "", fmt.Errorf("checksum of syft archive '%v' does not match: expected %v, got %v", downloadURL, expectedSHA256, actualSHA256)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read syft archive")
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return "", fmt.Errorf("syft binary not found in archive '%v'", downloadURL)
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read syft archive")
+		}
+		if header.Typeflag != tar.TypeReg || path.Base(header.Name) != "syft" {
+			continue
+		}
+		binary, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to extract syft binary")
+		}
+		if err := fileUtils.MkdirAll(targetDir, 0755); err != nil {
+			return "", errors.Wrapf(err, "failed to create directory '%v'", targetDir)
+		}
+		syftPath := filepath.Join(targetDir, "syft")
+		if err := fileUtils.FileWrite(syftPath, binary, 0755); err != nil {
+			return "", errors.Wrapf(err, "failed to write syft binary '%v'", syftPath)
+		}
+		return syftPath, nil
+	}
+}
+
+// CreateBOM creates the CycloneDX BOM in XML format of an image which is pulled from its registry
+func CreateBOM(syftPath, image, bomFile string, execRunner command.ExecRunner) error {
+	log.Entry().Infof("Creating BOM of image '%v'", image)
+	if err := execRunner.RunExecutable(syftPath, "packages", "registry:"+image, "-o", "cyclonedx-xml", "--file", bomFile); err != nil {
+		return errors.Wrapf(err, "failed to create BOM of image '%v'", image)
+	}
+	return nil
+}
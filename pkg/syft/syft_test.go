@@ -0,0 +1,123 @@
+package syft
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpClientMock struct {
+	statusCode int
+	body       []byte
+	url        string
+}
+
+func (c *httpClientMock) SetOptions(opts piperhttp.ClientOptions) {}
+
+func (c *httpClientMock) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	c.url = url
+	return &http.Response{StatusCode: c.statusCode, Body: ioutil.NopCloser(bytes.NewReader(c.body))}, nil
+}
+
+func archive(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInstall(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := &httpClientMock{statusCode: http.StatusOK, body: archive(t, map[string]string{"README.md": "readme", "syft": "binary"})}
+		fileUtils := &mock.FilesMock{}
+
+		syftPath, err := Install("https://example.org/syft.tar.gz", strings.ToUpper(checksum(client.body)), "/kaniko/syft", client, fileUtils)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.org/syft.tar.gz", client.url)
+		assert.Equal(t, filepath.Join("/kaniko/syft", "syft"), syftPath)
+		content, err := fileUtils.FileRead(syftPath)
+		require.NoError(t, err)
+		assert.Equal(t, "binary", string(content))
+	})
+
+	t.Run("binary missing", func(t *testing.T) {
+		client := &httpClientMock{statusCode: http.StatusOK, body: archive(t, map[string]string{"README.md": "readme"})}
+
+		_, err := Install("https://example.org/syft.tar.gz", checksum(client.body), "/kaniko/syft", client, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "syft binary not found in archive 'https://example.org/syft.tar.gz'")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		client := &httpClientMock{statusCode: http.StatusOK, body: archive(t, map[string]string{"syft": "binary"})}
+		fileUtils := &mock.FilesMock{}
+
+		_, err := Install("https://example.org/syft.tar.gz", checksum([]byte("other")), "/kaniko/syft", client, fileUtils)
+
+		assert.EqualError(t, err, fmt.Sprintf("checksum of syft archive 'https://example.org/syft.tar.gz' does not match: expected %v, got %v", checksum([]byte("other")), checksum(client.body)))
+		assert.False(t, fileUtils.HasWrittenFile(filepath.Join("/kaniko/syft", "syft")))
+	})
+
+	t.Run("checksum missing", func(t *testing.T) {
+		client := &httpClientMock{statusCode: http.StatusOK, body: archive(t, map[string]string{"syft": "binary"})}
+
+		_, err := Install("https://example.org/syft.tar.gz", "", "/kaniko/syft", client, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "no SHA-256 checksum provided for syft archive 'https://example.org/syft.tar.gz'")
+		assert.Empty(t, client.url)
+	})
+
+	t.Run("download failed", func(t *testing.T) {
+		client := &httpClientMock{statusCode: http.StatusNotFound}
+
+		_, err := Install("https://example.org/syft.tar.gz", checksum(nil), "/kaniko/syft", client, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "failed to download syft from 'https://example.org/syft.tar.gz': status code 404")
+	})
+}
+
+func TestCreateBOM(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		execRunner := &mock.ExecMockRunner{}
+
+		err := CreateBOM("/kaniko/syft/syft", "my.registry.com/image:1.0", "bom-docker.xml", execRunner)
+
+		require.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "/kaniko/syft/syft", Params: []string{"packages", "registry:my.registry.com/image:1.0", "-o", "cyclonedx-xml", "--file", "bom-docker.xml"}}, execRunner.Calls[0])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		execRunner := &mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"/kaniko/syft/syft": fmt.Errorf("unauthorized")}}
+
+		err := CreateBOM("/kaniko/syft/syft", "my.registry.com/image:1.0", "bom-docker.xml", execRunner)
+
+		assert.EqualError(t, err, "failed to create BOM of image 'my.registry.com/image:1.0': unauthorized")
+	})
+}
@@ -16,6 +16,7 @@ type BaseData struct {
 	StageName       string `json:"e_10"`
 	PipelineURLHash string `json:"e_4"` // defaults to sha1 of env.JOB_URl
 	BuildURLHash    string `json:"e_5"` // defaults to sha1 of env.BUILD_URL
+	Orchestrator    string `json:"e_14"`
 }
 
 var baseData BaseData
@@ -27,6 +28,7 @@ type BaseMetaData struct {
 	StageNameLabel       string `json:"custom10"`
 	PipelineURLHashLabel string `json:"custom4"`
 	BuildURLHashLabel    string `json:"custom5"`
+	OrchestratorLabel    string `json:"custom14"`
 	DurationLabel        string `json:"custom11,omitempty"`
 	ExitCodeLabel        string `json:"custom12,omitempty"`
 	ErrorCategoryLabel   string `json:"custom13,omitempty"`
@@ -38,6 +40,7 @@ var baseMetaData BaseMetaData = BaseMetaData{
 	StageNameLabel:       "stageName",
 	PipelineURLHashLabel: "pipelineUrlHash",
 	BuildURLHashLabel:    "buildUrlHash",
+	OrchestratorLabel:    "orchestrator",
 	DurationLabel:        "duration",
 	ExitCodeLabel:        "exitCode",
 	ErrorCategoryLabel:   "errorCategory",
@@ -46,7 +49,7 @@ var baseMetaData BaseMetaData = BaseMetaData{
 // CustomData object definition containing the data that can be set by a step and it's mapping information
 type CustomData struct {
 	// SWA receives the fields custom1 - custom30 and e_a, e_2 - e_30 for custom values.
-	// Piper uses the values custom11 - custom25 & e_11 - e_25 for library related reporting
+	// Piper uses the values custom11 - custom25 & e_11 - e_25 for library related reporting (custom14 & e_14 are part of the base data)
 	// and custom26 - custom30 & e_26 - e_30 for step  related reporting.
 	Duration      string `json:"e_11,omitempty"`
 	ErrorCode     string `json:"e_12,omitempty"`
@@ -11,6 +11,7 @@ import (
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 )
 
 // eventType
@@ -52,25 +53,45 @@ func Initialize(telemetryDisabled bool, stepName string) {
 		SiteID = "827e8025-1e21-ae84-c3a3-3f62b70b0130"
 	}
 
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Debug("Telemetry data is reported without orchestrator information")
+	}
+
 	baseData = BaseData{
 		URL:             LibraryRepository,
 		ActionName:      actionName,
 		EventType:       eventType,
 		StepName:        stepName,
+		StageName:       getStageName(provider),
 		SiteID:          SiteID,
-		PipelineURLHash: getPipelineURLHash(), // http://server:port/jenkins/job/foo/
-		BuildURLHash:    getBuildURLHash(),    // http://server:port/jenkins/job/foo/15/
+		PipelineURLHash: getPipelineURLHash(provider), // http://server:port/jenkins/job/foo/
+		BuildURLHash:    getBuildURLHash(provider),    // http://server:port/jenkins/job/foo/15/
+		Orchestrator:    orchestrator.DetectOrchestrator().String(),
 	}
 	//ToDo: register Logrus Hook
 
 }
 
-func getPipelineURLHash() string {
-	return toSha1OrNA(os.Getenv("JOB_URL"))
+func getStageName(provider orchestrator.OrchestratorSpecificConfigProviding) string {
+	if provider == nil {
+		return ""
+	}
+	return provider.GetStageName()
 }
 
-func getBuildURLHash() string {
-	return toSha1OrNA(os.Getenv("BUILD_URL"))
+func getPipelineURLHash(provider orchestrator.OrchestratorSpecificConfigProviding) string {
+	if provider == nil {
+		return toSha1OrNA(os.Getenv("JOB_URL"))
+	}
+	return toSha1OrNA(provider.GetJobUrl())
+}
+
+func getBuildURLHash(provider orchestrator.OrchestratorSpecificConfigProviding) string {
+	if provider == nil {
+		return toSha1OrNA(os.Getenv("BUILD_URL"))
+	}
+	return toSha1OrNA(provider.GetBuildUrl())
 }
 
 func toSha1OrNA(input string) string {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
@@ -87,16 +88,21 @@ func TestSend(t *testing.T) {
 func TestEnvVars(t *testing.T) {
 	t.Run("without values", func(t *testing.T) {
 		// init
+		defer resetEnv(os.Environ())
+		os.Clearenv()
 		client = nil
 		// test
 		Initialize(false, "testStep")
 		// assert
 		assert.Equal(t, "n/a", baseData.PipelineURLHash)
 		assert.Equal(t, "n/a", baseData.BuildURLHash)
+		assert.Equal(t, "Unknown", baseData.Orchestrator)
 	})
 
 	t.Run("", func(t *testing.T) {
 		// init
+		defer resetEnv(os.Environ())
+		os.Clearenv()
 		os.Setenv("JOB_URL", "someValue")
 		os.Setenv("BUILD_URL", "someValue")
 		client = nil
@@ -105,10 +111,33 @@ func TestEnvVars(t *testing.T) {
 		// assert
 		assert.Equal(t, "c1353b55ce4db511684b8a3b7b5c4b3d99ee9dec", baseData.PipelineURLHash)
 		assert.Equal(t, "c1353b55ce4db511684b8a3b7b5c4b3d99ee9dec", baseData.BuildURLHash)
-		// cleanup
-		os.Unsetenv("JOB_URL")
-		os.Unsetenv("BUILD_URL")
 	})
+
+	t.Run("with orchestrator", func(t *testing.T) {
+		// init
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("GITLAB_CI", "true")
+		os.Setenv("CI_PROJECT_URL", "someValue")
+		os.Setenv("CI_PIPELINE_URL", "someValue")
+		os.Setenv("CI_JOB_STAGE", "build")
+		client = nil
+		// test
+		Initialize(false, "testStep")
+		// assert
+		assert.Equal(t, "7cacffb47e3e7833503c22ddc5669e6e83288447", baseData.PipelineURLHash)
+		assert.Equal(t, "c1353b55ce4db511684b8a3b7b5c4b3d99ee9dec", baseData.BuildURLHash)
+		assert.Equal(t, "build", baseData.StageName)
+		assert.Equal(t, "GitLab", baseData.Orchestrator)
+	})
+}
+
+func resetEnv(e []string) {
+	os.Clearenv()
+	for _, val := range e {
+		tmp := strings.SplitN(val, "=", 2)
+		os.Setenv(tmp[0], tmp[1])
+	}
 }
 
 func TestGetData(t *testing.T) {
@@ -144,6 +173,7 @@ func TestGetData(t *testing.T) {
 					StageNameLabel:       "stageName",
 					PipelineURLHashLabel: "pipelineUrlHash",
 					BuildURLHashLabel:    "buildUrlHash",
+					OrchestratorLabel:    "orchestrator",
 					DurationLabel:        "duration",
 					ExitCodeLabel:        "exitCode",
 					ErrorCategoryLabel:   "errorCategory",
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 )
 
 type keydataset struct {
@@ -17,6 +19,18 @@ type keydataset struct {
 	URL         string // direct URL to navigate to this key in the tool backend - optional
 }
 
+type pipelinedataset struct {
+	Orchestrator      string // orchestrator which executed the pipeline, e.g. Jenkins
+	JobName           string
+	JobURL            string
+	BuildID           string
+	BuildURL          string
+	StageName         string
+	Branch            string
+	CommitID          string
+	PipelineStartTime time.Time // zero if not provided by the orchestrator
+}
+
 // Toolrecord holds all data to locate a tool result
 // in the tool's backend
 type Toolrecord struct {
@@ -37,6 +51,9 @@ type Toolrecord struct {
 	// place for additional context information
 	Context map[string]interface{}
 
+	// pipeline run which created the record, filled via the orchestrator
+	Pipeline pipelinedataset
+
 	// internal - not exported to the json
 	workspace      string
 	reportFileName string
@@ -51,6 +68,7 @@ func New(workspace, toolName, toolInstance string) *Toolrecord {
 	tr.ToolInstance = toolInstance
 	tr.Keys = []keydataset{}
 	tr.Context = make(map[string]interface{})
+	tr.Pipeline = newPipelineData()
 
 	tr.workspace = workspace
 
@@ -65,6 +83,23 @@ func New(workspace, toolName, toolInstance string) *Toolrecord {
 	return &tr
 }
 
+func newPipelineData() pipelinedataset {
+	pipelineData := pipelinedataset{Orchestrator: orchestrator.DetectOrchestrator().String()}
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		return pipelineData
+	}
+	pipelineData.JobName = provider.GetJobName()
+	pipelineData.JobURL = provider.GetJobUrl()
+	pipelineData.BuildID = provider.GetBuildId()
+	pipelineData.BuildURL = provider.GetBuildUrl()
+	pipelineData.StageName = provider.GetStageName()
+	pipelineData.Branch = provider.GetBranch()
+	pipelineData.CommitID = provider.GetCommit()
+	pipelineData.PipelineStartTime = provider.GetPipelineStartTime()
+	return pipelineData
+}
+
 // AddKeyData - add one key to the current toolrecord
 // calls must follow the tool's hierachy ( e.g. org -> project)
 // as DisplayName & DisplayURL are based on the call sequence
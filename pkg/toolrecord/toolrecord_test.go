@@ -1,8 +1,10 @@
 package toolrecord_test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
@@ -35,3 +37,38 @@ func TestToolRecord(t *testing.T) {
 		assert.FileExists(t, tr.GetFileName(), "toolrecord not persisted %s")
 	})
 }
+
+func TestToolRecordPipelineData(t *testing.T) {
+	workspace, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary workspace directory")
+	}
+	defer os.RemoveAll(workspace)
+
+	environ := os.Environ()
+	defer func() {
+		os.Clearenv()
+		for _, val := range environ {
+			tmp := strings.SplitN(val, "=", 2)
+			os.Setenv(tmp[0], tmp[1])
+		}
+	}()
+	os.Clearenv()
+	os.Setenv("PIPER_CI_JOB_NAME", "foo/bar")
+	os.Setenv("PIPER_CI_BUILD_ID", "42")
+	os.Setenv("PIPER_CI_BRANCH", "main")
+	os.Setenv("PIPER_CI_COMMIT", "abcdef42713")
+	os.Setenv("PIPER_CI_ACTOR", "jdoe@example.com")
+
+	tr := toolrecord.New(workspace, "dummyTool", "dummyInstance")
+
+	assert.Equal(t, "Generic", tr.Pipeline.Orchestrator)
+	assert.Equal(t, "foo/bar", tr.Pipeline.JobName)
+	assert.Equal(t, "42", tr.Pipeline.BuildID)
+	assert.Equal(t, "main", tr.Pipeline.Branch)
+	assert.Equal(t, "abcdef42713", tr.Pipeline.CommitID)
+	// the user who triggered the pipeline must not be persisted
+	record, err := json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(record), "jdoe@example.com")
+}
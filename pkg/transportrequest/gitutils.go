@@ -7,6 +7,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/pkg/errors"
 	"os"
 	"regexp"
@@ -15,6 +16,7 @@ import (
 )
 
 var logRange = gitUtils.LogRange
+var fetchFromRemote = gitUtils.FetchFromRemote
 var findLabelsInCommits = FindLabelsInCommits
 
 type iTransportRequestGitUtils interface {
@@ -31,6 +33,27 @@ func (g *transportRequestGitUtils) PlainOpen(directory string) (*git.Repository,
 	return r, nil
 }
 
+// FetchOrigin fetches the branches of remote origin into the git repository in the current working directory.
+// This makes the commit range available in case the repository has been cloned partially.
+func FetchOrigin(auth transport.AuthMethod) error {
+	return fetchOrigin(auth, &transportRequestGitUtils{})
+}
+
+func fetchOrigin(auth transport.AuthMethod, trGitUtils iTransportRequestGitUtils) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return errors.Wrapf(err, "Cannot open git repo in current working directory '%s'", workdir)
+	}
+
+	r, err := trGitUtils.PlainOpen(workdir)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to open git repository at '%s'", workdir)
+	}
+
+	log.Entry().Info("Fetching commits from remote 'origin'")
+	return fetchFromRemote(auth, "origin", r)
+}
+
 // FindIDInRange finds a ID according to the label in a commit range <from>..<to>.
 // We assume the git repo is present in the current working directory.
 func FindIDInRange(label, from, to string) (string, error) {
@@ -1,12 +1,15 @@
 package transportrequest
 
 import (
+	"errors"
 	pipergit "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
 	"io"
@@ -230,7 +233,7 @@ func TestFindIDInRange(t *testing.T) {
 
 	// For these functions we have already tests. In order to avoid re-testing
 	// we set mocks for these functions.
-	logRange = func(repo *git.Repository, from, to string) (object.CommitIter, error) {
+	logRange = func(repo pipergit.CommitResolver, from, to string) (object.CommitIter, error) {
 		return &commitIteratorMock{}, nil
 	}
 
@@ -244,7 +247,7 @@ func TestFindIDInRange(t *testing.T) {
 		var receivedFrom, receivedTo string
 
 		oldLogRangeFunc := logRange
-		logRange = func(repo *git.Repository, from, to string) (object.CommitIter, error) {
+		logRange = func(repo pipergit.CommitResolver, from, to string) (object.CommitIter, error) {
 			receivedFrom = from
 			receivedTo = to
 			return &commitIteratorMock{}, nil
@@ -310,3 +313,39 @@ func TestFindIDInRange(t *testing.T) {
 	})
 
 }
+
+func TestFetchOrigin(t *testing.T) {
+
+	defer func() {
+		fetchFromRemote = pipergit.FetchFromRemote
+	}()
+
+	t.Run("origin is fetched with authentication", func(t *testing.T) {
+
+		var receivedAuth transport.AuthMethod
+		var receivedRemote string
+		fetchFromRemote = func(auth transport.AuthMethod, remoteName string, repository *git.Repository) error {
+			receivedAuth = auth
+			receivedRemote = remoteName
+			return nil
+		}
+
+		err := fetchOrigin(&http.TokenAuth{Token: "token"}, &TrGitUtilsMock{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "origin", receivedRemote)
+			assert.Equal(t, &http.TokenAuth{Token: "token"}, receivedAuth)
+		}
+	})
+
+	t.Run("fetch fails", func(t *testing.T) {
+
+		fetchFromRemote = func(transport.AuthMethod, string, *git.Repository) error {
+			return errors.New("failed to fetch from remote 'origin': authentication required")
+		}
+
+		err := fetchOrigin(nil, &TrGitUtilsMock{})
+
+		assert.EqualError(t, err, "failed to fetch from remote 'origin': authentication required")
+	})
+}
@@ -16,6 +16,17 @@ import (
 type Client struct {
 	lClient logicalClient
 	config  *Config
+	leases  *leaseStore
+}
+
+// leaseStore keeps track of the leases of dynamic secrets which have been requested by a client
+type leaseStore struct {
+	ids     []string
+	secrets map[string]map[string]string
+}
+
+func newLeaseStore() *leaseStore {
+	return &leaseStore{secrets: map[string]map[string]string{}}
 }
 
 // Config contains the vault client configuration
@@ -28,6 +39,7 @@ type Config struct {
 // logicalClient interface for mocking
 type logicalClient interface {
 	Read(string) (*api.Secret, error)
+	ReadWithData(string, map[string][]string) (*api.Secret, error)
 	Write(string, map[string]interface{}) (*api.Secret, error)
 }
 
@@ -47,7 +59,7 @@ func NewClient(config *Config, token string) (Client, error) {
 
 	client.SetToken(token)
 	log.Entry().Debugf("Login to vault %s in namespace %s successfull", config.Address, config.Namespace)
-	return Client{client.Logical(), config}, nil
+	return Client{client.Logical(), config, newLeaseStore()}, nil
 }
 
 // NewClientWithAppRole instantiates a new client and obtains a token via the AppRole auth method
@@ -101,8 +113,13 @@ func (v Client) GetSecret(path string) (*api.Secret, error) {
 }
 
 // GetKvSecret reads secret from the KV engine.
-// It Automatically transforms the logical path to the HTTP API Path for the corresponding KV Engine version
+// It Automatically transforms the logical path to the HTTP API Path for the corresponding KV Engine version.
+// A specific version of a secret stored in a KV engine of version 2 can be requested by adding the suffix '?version=<version>' to the path.
 func (v Client) GetKvSecret(path string) (map[string]string, error) {
+	path, secretVersion, err := splitVersionFromPath(path)
+	if err != nil {
+		return nil, err
+	}
 	path = sanitizePath(path)
 	mountpath, version, err := v.getKvInfo(path)
 	if err != nil {
@@ -112,9 +129,16 @@ func (v Client) GetKvSecret(path string) (map[string]string, error) {
 		path = addPrefixToKvPath(path, mountpath, "data")
 	} else if version != 1 {
 		return nil, fmt.Errorf("KV Engine in version %d is currently not supported", version)
+	} else if secretVersion > 0 {
+		return nil, fmt.Errorf("Reading version %d of secret '%s' requires KV Engine in version 2", secretVersion, path)
 	}
 
-	secret, err := v.GetSecret(path)
+	var secret *api.Secret
+	if secretVersion > 0 {
+		secret, err = v.lClient.ReadWithData(path, map[string][]string{"version": {strconv.Itoa(secretVersion)}})
+	} else {
+		secret, err = v.GetSecret(path)
+	}
 	if secret == nil || err != nil {
 		return nil, err
 
@@ -129,6 +153,10 @@ func (v Client) GetKvSecret(path string) (map[string]string, error) {
 		if !ok {
 			return nil, fmt.Errorf("Missing 'data' field in response: %v", rawData)
 		}
+		if rawData == nil && secretVersion > 0 {
+			// the requested version has been deleted or destroyed
+			return nil, nil
+		}
 	}
 
 	data, ok := rawData.(map[string]interface{})
@@ -146,6 +174,69 @@ func (v Client) GetKvSecret(path string) (map[string]string, error) {
 	return secretData, nil
 }
 
+// GetDynamicSecret requests credentials from a dynamic secrets engine (e.g. database, aws, azure or gcp) at the given path,
+// e.g. 'database/creds/my-role'. Credentials are requested only once per path and client.
+// The leases of the credentials are revoked with RevokeLeases or MustRevokeToken.
+func (v Client) GetDynamicSecret(path string) (map[string]string, error) {
+	path = sanitizePath(path)
+	if v.leases == nil {
+		return nil, fmt.Errorf("Client is not initialized for dynamic secrets")
+	}
+	if secretData, ok := v.leases.secrets[path]; ok {
+		return secretData, nil
+	}
+
+	secret, err := v.GetSecret(path)
+	if secret == nil || err != nil {
+		return nil, err
+	}
+	if secret.LeaseID != "" {
+		log.Entry().Debugf("Obtained lease %s for dynamic secret at %s", secret.LeaseID, path)
+		v.leases.ids = append(v.leases.ids, secret.LeaseID)
+	}
+
+	secretData := make(map[string]string, len(secret.Data))
+	for k, value := range secret.Data {
+		switch value := value.(type) {
+		case nil:
+		case string:
+			secretData[k] = value
+		default:
+			// some engines return structured credentials, e.g. gcp service account keys
+			valueJSON, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			secretData[k] = string(valueJSON)
+		}
+	}
+	for _, value := range secretData {
+		log.RegisterSecret(value)
+	}
+	v.leases.secrets[path] = secretData
+	return secretData, nil
+}
+
+// RevokeLeases revokes the leases of all dynamic secrets which have been requested via GetDynamicSecret
+func (v Client) RevokeLeases() error {
+	if v.leases == nil {
+		return nil
+	}
+	var failed []string
+	for _, leaseID := range v.leases.ids {
+		if _, err := v.lClient.Write("sys/leases/revoke", map[string]interface{}{"lease_id": leaseID}); err != nil {
+			log.Entry().WithError(err).Warnf("Could not revoke lease %s", leaseID)
+			failed = append(failed, leaseID)
+		}
+	}
+	v.leases.ids = failed
+	v.leases.secrets = map[string]map[string]string{}
+	if len(failed) > 0 {
+		return fmt.Errorf("Could not revoke lease(s) %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 // WriteKvSecret writes secret to kv engine
 func (v Client) WriteKvSecret(path string, newSecret map[string]string) error {
 	oldSecret, err := v.GetKvSecret(path)
@@ -254,8 +345,12 @@ func (v Client) RevokeToken() error {
 }
 
 // MustRevokeToken same as RevokeToken but the programm is terminated with an error if this fails.
+// The leases of dynamic secrets are revoked before the token.
 // Should be used in defer statements only.
 func (v Client) MustRevokeToken() {
+	if err := v.RevokeLeases(); err != nil {
+		log.Entry().WithError(err).Warn("Could not revoke leases of dynamic secrets")
+	}
 	if err := v.RevokeToken(); err != nil {
 		log.Entry().WithError(err).Fatal("Could not revoke token")
 	}
@@ -322,6 +417,19 @@ func sanitizePath(path string) string {
 	return path
 }
 
+// splitVersionFromPath separates an optional version suffix '?version=<version>' from a secret path
+func splitVersionFromPath(p string) (string, int, error) {
+	parts := strings.SplitN(p, "?version=", 2)
+	if len(parts) == 1 {
+		return p, 0, nil
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("Invalid version '%s' for secret '%s'", parts[1], parts[0])
+	}
+	return parts[0], version, nil
+}
+
 func addPrefixToKvPath(p, mountPath, apiPrefix string) string {
 	switch {
 	case p == mountPath, p == strings.TrimSuffix(mountPath, "/"):
@@ -31,7 +31,7 @@ func TestGetKV2Secret(t *testing.T) {
 
 	t.Run("Test missing secret", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		setupMockKvV2(vaultMock)
 		vaultMock.On("Read", "secret/data/notexist").Return(nil, nil)
 		secret, err := client.GetKvSecret("secret/notexist")
@@ -46,7 +46,7 @@ func TestGetKV2Secret(t *testing.T) {
 		t.Run("Getting secret from KV engine (v2)", func(t *testing.T) {
 			vaultMock := &mocks.VaultMock{}
 			setupMockKvV2(vaultMock)
-			client := Client{vaultMock, &Config{}}
+			client := Client{vaultMock, &Config{}, newLeaseStore()}
 			vaultMock.On("Read", secretAPIPath).Return(kv2Secret(SecretData{"key1": "value1"}), nil)
 			secret, err := client.GetKvSecret(secretName)
 			assert.NoError(t, err, "Expect GetKvSecret to succeed")
@@ -57,7 +57,7 @@ func TestGetKV2Secret(t *testing.T) {
 		t.Run("field ignored when 'data' field can't be parsed", func(t *testing.T) {
 			vaultMock := &mocks.VaultMock{}
 			setupMockKvV2(vaultMock)
-			client := Client{vaultMock, &Config{}}
+			client := Client{vaultMock, &Config{}, newLeaseStore()}
 			vaultMock.On("Read", secretAPIPath).Return(kv2Secret(SecretData{"key1": "value1", "key2": 5}), nil)
 			secret, err := client.GetKvSecret(secretName)
 			assert.NoError(t, err)
@@ -67,7 +67,7 @@ func TestGetKV2Secret(t *testing.T) {
 		t.Run("error is thrown when data field is missing", func(t *testing.T) {
 			vaultMock := &mocks.VaultMock{}
 			setupMockKvV2(vaultMock)
-			client := Client{vaultMock, &Config{}}
+			client := Client{vaultMock, &Config{}, newLeaseStore()}
 			vaultMock.On("Read", secretAPIPath).Return(kv1Secret(SecretData{"key1": "value1"}), nil)
 			secret, err := client.GetKvSecret(secretName)
 			assert.Error(t, err, "Expected to fail since 'data' field is missing")
@@ -83,7 +83,7 @@ func TestGetKV1Secret(t *testing.T) {
 	t.Run("Test missing secret", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
 		setupMockKvV1(vaultMock)
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 
 		vaultMock.On("Read", mock.AnythingOfType("string")).Return(nil, nil)
 		secret, err := client.GetKvSecret("secret/notexist")
@@ -94,7 +94,7 @@ func TestGetKV1Secret(t *testing.T) {
 	t.Run("Test parsing KV1 secrets", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
 		setupMockKvV1(vaultMock)
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 
 		vaultMock.On("Read", secretName).Return(kv1Secret(SecretData{"key1": "value1"}), nil)
 		secret, err := client.GetKvSecret(secretName)
@@ -106,7 +106,7 @@ func TestGetKV1Secret(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
 		setupMockKvV1(vaultMock)
 		vaultMock.On("Read", secretName).Return(kv1Secret(SecretData{"key1": 5}), nil)
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 
 		secret, err := client.GetKvSecret(secretName)
 		assert.NoError(t, err)
@@ -154,7 +154,7 @@ func TestWriteKvSecret(t *testing.T) {
 			core := cluster.Cores[0].Core
 			vault.TestWaitActive(t, core)
 			vaultClient := cluster.Cores[0].Client
-			client := Client{vaultClient.Logical(), &Config{}}
+			client := Client{vaultClient.Logical(), &Config{}, newLeaseStore()}
 			cluster.Start()
 			defer cluster.Cleanup()
 
@@ -180,7 +180,7 @@ func TestSecretIDGeneration(t *testing.T) {
 
 	t.Run("Test generating new secret-id", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		now := time.Now()
 		expiry := now.Add(5 * time.Hour).Format(time.RFC3339)
 		metadata := map[string]interface{}{
@@ -205,7 +205,7 @@ func TestSecretIDGeneration(t *testing.T) {
 
 	t.Run("Test with no secret-id returned", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		now := time.Now()
 		expiry := now.Add(5 * time.Hour).Format(time.RFC3339)
 		metadata := map[string]interface{}{
@@ -228,7 +228,7 @@ func TestSecretIDGeneration(t *testing.T) {
 
 	t.Run("Test with no new secret-id returned", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		now := time.Now()
 		expiry := now.Add(5 * time.Hour).Format(time.RFC3339)
 		metadata := map[string]interface{}{
@@ -258,7 +258,7 @@ func TestSecretIDTtl(t *testing.T) {
 
 	t.Run("Test fetching secreID TTL", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		now := time.Now()
 		expiry := now.Add(5 * time.Hour).Format(time.RFC3339)
 		vaultMock.On("Write", path.Join(appRolePath, "secret-id/lookup"), mapWith("secret_id", secretID)).Return(kv1Secret(SecretData{
@@ -272,7 +272,7 @@ func TestSecretIDTtl(t *testing.T) {
 
 	t.Run("Test with no expiration time", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Write", path.Join(appRolePath, "secret-id/lookup"), mapWith("secret_id", secretID)).Return(kv1Secret(SecretData{}), nil)
 		ttl, err := client.GetAppRoleSecretIDTtl(secretID, appRoleName)
 		assert.EqualError(t, err, fmt.Sprintf("Could not load secret-id information from path %s", appRolePath))
@@ -281,7 +281,7 @@ func TestSecretIDTtl(t *testing.T) {
 
 	t.Run("Test with wrong date format", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Write", path.Join(appRolePath, "secret-id/lookup"), mapWith("secret_id", secretID)).Return(kv1Secret(SecretData{
 			"expiration_time": time.Now().String(),
 		}), nil)
@@ -292,7 +292,7 @@ func TestSecretIDTtl(t *testing.T) {
 
 	t.Run("Test with expired secret-id", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		now := time.Now()
 		expiry := now.Add(-5 * time.Hour).Format(time.RFC3339)
 		vaultMock.On("Write", path.Join(appRolePath, "secret-id/lookup"), mapWith("secret_id", secretID)).Return(kv1Secret(SecretData{
@@ -311,7 +311,7 @@ func TestGetAppRoleName(t *testing.T) {
 
 	t.Run("Test that correct role name is returned", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Read", "auth/token/lookup-self").Return(kv1Secret(SecretData{
 			"meta": SecretData{
 				"role_name": "test",
@@ -325,7 +325,7 @@ func TestGetAppRoleName(t *testing.T) {
 
 	t.Run("Test without secret data", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Read", "auth/token/lookup-self").Return(kv1Secret(nil), nil)
 
 		appRoleName, err := client.GetAppRoleName()
@@ -335,7 +335,7 @@ func TestGetAppRoleName(t *testing.T) {
 
 	t.Run("Test without metadata data", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Read", "auth/token/lookup-self").Return(kv1Secret(SecretData{}), nil)
 
 		appRoleName, err := client.GetAppRoleName()
@@ -345,7 +345,7 @@ func TestGetAppRoleName(t *testing.T) {
 
 	t.Run("Test without role name in metadata", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Read", "auth/token/lookup-self").Return(kv1Secret(SecretData{
 			"meta": SecretData{},
 		}), nil)
@@ -357,7 +357,7 @@ func TestGetAppRoleName(t *testing.T) {
 
 	t.Run("Test that different role_name types are ignored", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Read", "auth/token/lookup-self").Return(kv1Secret(SecretData{
 			"meta": SecretData{
 				"role_name": 5,
@@ -374,7 +374,7 @@ func TestTokenRevocation(t *testing.T) {
 	t.Parallel()
 	t.Run("Test that revocation error is returned", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Write",
 			"auth/token/revoke-self",
 			mock.IsType(map[string]interface{}{})).Return(nil, errors.New("Test"))
@@ -385,7 +385,7 @@ func TestTokenRevocation(t *testing.T) {
 
 	t.Run("Test that revocation endpoint is called", func(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
-		client := Client{vaultMock, &Config{}}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
 		vaultMock.On("Write",
 			"auth/token/revoke-self",
 			mock.IsType(map[string]interface{}{})).Return(nil, nil)
@@ -394,9 +394,140 @@ func TestTokenRevocation(t *testing.T) {
 	})
 }
 
+func TestGetKvSecretVersion(t *testing.T) {
+	t.Run("read specific version from KV engine (v2)", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		setupMockKvV2(vaultMock)
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
+		vaultMock.On("ReadWithData", "secret/data/test", map[string][]string{"version": {"3"}}).Return(kv2Secret(SecretData{"key1": "value3"}), nil)
+
+		secret, err := client.GetKvSecret("secret/test?version=3")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"key1": "value3"}, secret)
+		vaultMock.AssertNotCalled(t, "Read", "secret/data/test")
+	})
+
+	t.Run("deleted version", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		setupMockKvV2(vaultMock)
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
+		vaultMock.On("ReadWithData", "secret/data/test", map[string][]string{"version": {"2"}}).Return(&api.Secret{Data: SecretData{"data": nil, "metadata": SecretData{"deletion_time": "2021-01-01T00:00:00Z"}}}, nil)
+
+		secret, err := client.GetKvSecret("secret/test?version=2")
+
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("error - version with KV engine (v1)", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		setupMockKvV1(vaultMock)
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
+
+		_, err := client.GetKvSecret("secret/test?version=2")
+
+		assert.EqualError(t, err, "Reading version 2 of secret 'secret/test' requires KV Engine in version 2")
+	})
+
+	t.Run("error - invalid version", func(t *testing.T) {
+		client := Client{&mocks.VaultMock{}, &Config{}, newLeaseStore()}
+
+		_, err := client.GetKvSecret("secret/test?version=latest")
+
+		assert.EqualError(t, err, "Invalid version 'latest' for secret 'secret/test'")
+	})
+}
+
+func TestGetKvSecretVersionWithDevServer(t *testing.T) {
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{
+		DevToken: "token",
+	}, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	core := cluster.Cores[0].Core
+	vault.TestWaitActive(t, core)
+	client := Client{cluster.Cores[0].Client.Logical(), &Config{}, newLeaseStore()}
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	assert.NoError(t, client.WriteKvSecret("secret/versioned", map[string]string{"key": "value1"}))
+	assert.NoError(t, client.WriteKvSecret("secret/versioned", map[string]string{"key": "value2"}))
+
+	secret, err := client.GetKvSecret("secret/versioned?version=1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "value1"}, secret)
+
+	secret, err = client.GetKvSecret("secret/versioned")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "value2"}, secret)
+}
+
+func TestGetDynamicSecret(t *testing.T) {
+	t.Run("credentials are requested once and leases are revoked", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
+		vaultMock.On("Read", "database/creds/readonly").Return(&api.Secret{
+			LeaseID:       "database/creds/readonly/abc",
+			LeaseDuration: 3600,
+			Data:          SecretData{"username": "v-token-readonly", "password": "secret", "ttl": nil},
+		}, nil).Once()
+		vaultMock.On("Read", "gcp/key/my-key").Return(&api.Secret{
+			LeaseID: "gcp/key/my-key/def",
+			Data:    SecretData{"private_key_data": "key", "key_algorithm": "KEY_ALG_RSA_2048", "scopes": []interface{}{"a"}},
+		}, nil).Once()
+		vaultMock.On("Write", "sys/leases/revoke", mapWith("lease_id", "database/creds/readonly/abc")).Return(nil, nil).Once()
+		vaultMock.On("Write", "sys/leases/revoke", mapWith("lease_id", "gcp/key/my-key/def")).Return(nil, nil).Once()
+
+		secret, err := client.GetDynamicSecret("/database/creds/readonly")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"username": "v-token-readonly", "password": "secret"}, secret)
+
+		secret, err = client.GetDynamicSecret("database/creds/readonly")
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", secret["password"])
+
+		secret, err = client.GetDynamicSecret("gcp/key/my-key")
+		assert.NoError(t, err)
+		assert.Equal(t, `["a"]`, secret["scopes"])
+
+		assert.NoError(t, client.RevokeLeases())
+		assert.NoError(t, client.RevokeLeases())
+		vaultMock.AssertExpectations(t)
+	})
+
+	t.Run("error - lease revocation fails", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
+		vaultMock.On("Read", "aws/creds/deploy").Return(&api.Secret{LeaseID: "aws/creds/deploy/xyz", Data: SecretData{"access_key": "AKIA"}}, nil)
+		vaultMock.On("Write", "sys/leases/revoke", mapWith("lease_id", "aws/creds/deploy/xyz")).Return(nil, errors.New("permission denied"))
+
+		_, err := client.GetDynamicSecret("aws/creds/deploy")
+		assert.NoError(t, err)
+
+		assert.EqualError(t, client.RevokeLeases(), "Could not revoke lease(s) aws/creds/deploy/xyz")
+	})
+
+	t.Run("leases are revoked together with the token", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{vaultMock, &Config{}, newLeaseStore()}
+		vaultMock.On("Read", "database/creds/readonly").Return(&api.Secret{LeaseID: "database/creds/readonly/abc", Data: SecretData{"password": "secret"}}, nil)
+		calls := []string{}
+		recordCall := func(args mock.Arguments) { calls = append(calls, args.String(0)) }
+		vaultMock.On("Write", "sys/leases/revoke", mapWith("lease_id", "database/creds/readonly/abc")).Return(nil, nil).Run(recordCall)
+		vaultMock.On("Write", "auth/token/revoke-self", mock.IsType(map[string]interface{}{})).Return(nil, nil).Run(recordCall)
+
+		_, err := client.GetDynamicSecret("database/creds/readonly")
+		assert.NoError(t, err)
+		client.MustRevokeToken()
+
+		assert.Equal(t, []string{"sys/leases/revoke", "auth/token/revoke-self"}, calls)
+	})
+}
+
 func TestUnknownKvVersion(t *testing.T) {
 	vaultMock := &mocks.VaultMock{}
-	client := Client{vaultMock, &Config{}}
+	client := Client{vaultMock, &Config{}, newLeaseStore()}
 
 	vaultMock.On("Read", "sys/internal/ui/mounts/secret/secret").Return(&api.Secret{
 		Data: map[string]interface{}{
@@ -413,7 +544,7 @@ func TestUnknownKvVersion(t *testing.T) {
 }
 
 func TestSetAppRoleMountPont(t *testing.T) {
-	client := Client{nil, &Config{}}
+	client := Client{nil, &Config{}, newLeaseStore()}
 	const newMountpoint = "auth/test"
 
 	client.SetAppRoleMountPoint("auth/test")
@@ -35,6 +35,29 @@ func (_m *VaultMock) Read(_a0 string) (*api.Secret, error) {
 	return r0, r1
 }
 
+// ReadWithData provides a mock function with given fields: _a0, _a1
+func (_m *VaultMock) ReadWithData(_a0 string, _a1 map[string][]string) (*api.Secret, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *api.Secret
+	if rf, ok := ret.Get(0).(func(string, map[string][]string) *api.Secret); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, map[string][]string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Write provides a mock function with given fields: _a0, _a1
 func (_m *VaultMock) Write(_a0 string, _a1 map[string]interface{}) (*api.Secret, error) {
 	ret := _m.Called(_a0, _a1)
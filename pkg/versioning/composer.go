@@ -0,0 +1,28 @@
+package versioning
+
+import (
+	"strings"
+)
+
+// Composer defines an artifact using a composer.json for versioning
+type Composer struct {
+	JSONfile
+}
+
+// GetCoordinates returns the coordinates, the package name <vendor>/<package> provides group and artifact id
+func (c *Composer) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	var err error
+	result.Version, err = c.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	name, _ := c.content["name"].(string)
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 {
+		result.GroupID = parts[0]
+		result.ArtifactID = parts[1]
+	} else {
+		result.ArtifactID = name
+	}
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposerGetCoordinates(t *testing.T) {
+	t.Run("vendor and package", func(t *testing.T) {
+		composer := Composer{JSONfile{
+			path: "composer.json",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte(`{"name": "acme/my-library", "version": "1.2.3"}`), nil
+			},
+		}}
+		coordinates, err := composer.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{GroupID: "acme", ArtifactID: "my-library", Version: "1.2.3"}, coordinates)
+	})
+
+	t.Run("no name", func(t *testing.T) {
+		composer := Composer{JSONfile{
+			path:     "composer.json",
+			readFile: func(filename string) ([]byte, error) { return []byte(`{"version": "1.2.3"}`), nil },
+		}}
+		coordinates, err := composer.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{Version: "1.2.3"}, coordinates)
+	})
+}
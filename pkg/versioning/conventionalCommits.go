@@ -0,0 +1,132 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionIncrement defines which part of a semantic version is increased
+type VersionIncrement int
+
+// The increments are ordered, a greater increment takes precedence
+const (
+	NoIncrement VersionIncrement = iota
+	PatchIncrement
+	MinorIncrement
+	MajorIncrement
+)
+
+// SemanticVersion represents the <major>.<minor>.<patch> part of a version
+type SemanticVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var semanticVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)([-+][0-9A-Za-z.+-]*)?$`)
+
+// conventionalCommitHeader matches e.g. "feat(parser)!: support arrays", the type is the first group, the breaking change marker the third
+var conventionalCommitHeader = regexp.MustCompile(`^([A-Za-z]+)(\([^()]*\))?(!)?: \S`)
+var breakingChangeFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// ParseSemanticVersion parses a version of the form <major>.<minor>.<patch>, a pre-release or build suffix is ignored
+func ParseSemanticVersion(version string) (SemanticVersion, error) {
+	match := semanticVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return SemanticVersion{}, fmt.Errorf("version '%v' is not a semantic version <major>.<minor>.<patch>", version)
+	}
+	// the pattern guarantees numbers, only an overflow can fail
+	major, err := strconv.Atoi(match[1])
+	if err != nil {
+		return SemanticVersion{}, fmt.Errorf("invalid major version in '%v'", version)
+	}
+	minor, err := strconv.Atoi(match[2])
+	if err != nil {
+		return SemanticVersion{}, fmt.Errorf("invalid minor version in '%v'", version)
+	}
+	patch, err := strconv.Atoi(match[3])
+	if err != nil {
+		return SemanticVersion{}, fmt.Errorf("invalid patch version in '%v'", version)
+	}
+	return SemanticVersion{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+// Increment returns the increased version, the lower parts are reset
+func (v SemanticVersion) Increment(increment VersionIncrement) SemanticVersion {
+	switch increment {
+	case MajorIncrement:
+		return SemanticVersion{Major: v.Major + 1}
+	case MinorIncrement:
+		return SemanticVersion{Major: v.Major, Minor: v.Minor + 1}
+	case PatchIncrement:
+		return SemanticVersion{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return v
+}
+
+// LessThan compares the versions according to their precedence
+func (v SemanticVersion) LessThan(other SemanticVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+func (v SemanticVersion) String() string {
+	return fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+}
+
+// ConventionalCommit contains the parts of a commit message following the Conventional Commits specification (https://www.conventionalcommits.org)
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Description string
+	// Body contains the message without the header, i.e. including the footers
+	Body     string
+	Breaking bool
+}
+
+// ParseConventionalCommit parses a commit message, false is returned in case the message does not follow the Conventional Commits specification.
+// Breaking changes are marked by "!" in the header or a BREAKING CHANGE footer.
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	parts := strings.SplitN(strings.TrimSpace(message), "\n", 2)
+	match := conventionalCommitHeader.FindStringSubmatch(parts[0])
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	commit := ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.Trim(match[2], "()"),
+		Description: strings.TrimSpace(parts[0][len(match[0])-1:]),
+		Breaking:    match[3] == "!" || breakingChangeFooter.MatchString(message),
+	}
+	if len(parts) > 1 {
+		commit.Body = strings.TrimSpace(parts[1])
+	}
+	return commit, true
+}
+
+// ConventionalCommitIncrement derives the version increment from a commit message following the Conventional Commits specification.
+// Breaking changes require a major increment, the type "feat" a minor increment and the types "fix" and "perf" a patch increment.
+// Other types as well as messages not following the specification do not require a new version.
+func ConventionalCommitIncrement(message string) VersionIncrement {
+	commit, ok := ParseConventionalCommit(message)
+	if !ok {
+		return NoIncrement
+	}
+	if commit.Breaking {
+		return MajorIncrement
+	}
+	switch commit.Type {
+	case "feat":
+		return MinorIncrement
+	case "fix", "perf":
+		return PatchIncrement
+	}
+	return NoIncrement
+}
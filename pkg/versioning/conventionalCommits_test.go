@@ -0,0 +1,85 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConventionalCommitIncrement(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected VersionIncrement
+	}{
+		{"feat: support arrays", MinorIncrement},
+		{"feat(parser): support arrays\n\nCloses #42", MinorIncrement},
+		{"fix: handle empty input", PatchIncrement},
+		{"perf(io): buffer writes", PatchIncrement},
+		{"feat!: drop support for yaml", MajorIncrement},
+		{"refactor(api)!: rename endpoints", MajorIncrement},
+		{"fix: correct default\n\nBREAKING CHANGE: the default is now false", MajorIncrement},
+		{"fix: correct default\n\nBREAKING-CHANGE: the default is now false", MajorIncrement},
+		{"docs: update readme", NoIncrement},
+		{"chore(deps): bump go-git", NoIncrement},
+		{"update readme\n\nBREAKING CHANGE: not a conventional commit", NoIncrement},
+		{"feature: not a type", NoIncrement},
+		{"Merge branch 'feat: x'", NoIncrement},
+		{"feat:missing space", NoIncrement},
+	}
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			assert.Equal(t, test.expected, ConventionalCommitIncrement(test.message))
+		})
+	}
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	t.Run("header with scope", func(t *testing.T) {
+		commit, ok := ParseConventionalCommit("feat(parser): support arrays (#12)\n\nArrays are parsed as lists.\n\nCloses #10")
+		assert.True(t, ok)
+		assert.Equal(t, ConventionalCommit{Type: "feat", Scope: "parser", Description: "support arrays (#12)", Body: "Arrays are parsed as lists.\n\nCloses #10"}, commit)
+	})
+
+	t.Run("breaking change", func(t *testing.T) {
+		commit, ok := ParseConventionalCommit("Refactor!: rename endpoints")
+		assert.True(t, ok)
+		assert.Equal(t, ConventionalCommit{Type: "refactor", Description: "rename endpoints", Breaking: true}, commit)
+	})
+
+	t.Run("no conventional commit", func(t *testing.T) {
+		_, ok := ParseConventionalCommit("Merge pull request #12 from feature/arrays")
+		assert.False(t, ok)
+	})
+}
+
+func TestSemanticVersion(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		version, err := ParseSemanticVersion("1.12.3")
+		assert.NoError(t, err)
+		assert.Equal(t, SemanticVersion{Major: 1, Minor: 12, Patch: 3}, version)
+
+		version, err = ParseSemanticVersion("2.0.0-SNAPSHOT")
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version.String())
+
+		_, err = ParseSemanticVersion("1.2")
+		assert.EqualError(t, err, "version '1.2' is not a semantic version <major>.<minor>.<patch>")
+
+		_, err = ParseSemanticVersion("1.2.3.20200101")
+		assert.Error(t, err)
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		version := SemanticVersion{Major: 1, Minor: 2, Patch: 3}
+		assert.Equal(t, "2.0.0", version.Increment(MajorIncrement).String())
+		assert.Equal(t, "1.3.0", version.Increment(MinorIncrement).String())
+		assert.Equal(t, "1.2.4", version.Increment(PatchIncrement).String())
+		assert.Equal(t, "1.2.3", version.Increment(NoIncrement).String())
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		assert.True(t, SemanticVersion{Major: 1, Minor: 9, Patch: 9}.LessThan(SemanticVersion{Major: 1, Minor: 10}))
+		assert.True(t, SemanticVersion{Major: 1, Minor: 2, Patch: 3}.LessThan(SemanticVersion{Major: 2}))
+		assert.False(t, SemanticVersion{Major: 1, Minor: 2, Patch: 3}.LessThan(SemanticVersion{Major: 1, Minor: 2, Patch: 3}))
+	})
+}
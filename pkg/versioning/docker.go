@@ -71,7 +71,7 @@ func (d *Docker) GetVersion() (string, error) {
 		}
 		d.versionSource = "custom"
 		fallthrough
-	case "custom", "dub", "golang", "maven", "mta", "npm", "pip", "sbt":
+	case "cargo", "composer", "custom", "dotnet", "dub", "golang", "helm", "maven", "mta", "npm", "pip", "sbt":
 		if d.options == nil {
 			d.options = &Options{}
 		}
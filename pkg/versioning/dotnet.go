@@ -0,0 +1,116 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dotnet defines an artifact using an MSBuild project file (*.csproj) or Directory.Build.props for versioning
+type Dotnet struct {
+	path      string
+	content   string
+	readFile  func(string) ([]byte, error)
+	writeFile func(string, []byte, os.FileMode) error
+}
+
+// msbuildVersionProperties are the properties defining the package version in the order of precedence
+var msbuildVersionProperties = []string{"Version", "VersionPrefix"}
+
+func msbuildProperty(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(<` + name + `>\s*)([^<]*?)(\s*</` + name + `>)`)
+}
+
+func (d *Dotnet) init() error {
+	if d.readFile == nil {
+		d.readFile = ioutil.ReadFile
+	}
+	if d.writeFile == nil {
+		d.writeFile = ioutil.WriteFile
+	}
+	if len(d.content) == 0 {
+		content, err := d.readFile(d.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", d.path)
+		}
+		d.content = string(content)
+	}
+	return nil
+}
+
+func (d *Dotnet) versionProperty() (*regexp.Regexp, error) {
+	err := d.init()
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range msbuildVersionProperties {
+		if pattern := msbuildProperty(property); pattern.MatchString(d.content) {
+			return pattern, nil
+		}
+	}
+	return nil, fmt.Errorf("no version property %v found in '%v'", msbuildVersionProperties, d.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme, NuGet supports semantic versions 2.0.0
+func (d *Dotnet) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the current version of the artifact with an MSBuild-based build descriptor
+func (d *Dotnet) GetVersion() (string, error) {
+	pattern, err := d.versionProperty()
+	if err != nil {
+		return "", err
+	}
+	return pattern.FindStringSubmatch(d.content)[2], nil
+}
+
+// SetVersion updates the version of the artifact with an MSBuild-based build descriptor
+func (d *Dotnet) SetVersion(version string) error {
+	pattern, err := d.versionProperty()
+	if err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+	// only the first occurrence is updated, further ones are usually conditional overrides
+	location := pattern.FindStringSubmatchIndex(d.content)
+	d.content = d.content[:location[4]] + version + d.content[location[5]:]
+
+	err = d.writeFile(d.path, []byte(d.content), 0700)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", d.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates, the artifact id is either the PackageId, the AssemblyName or derived from the file name
+func (d *Dotnet) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	var err error
+	result.Version, err = d.GetVersion()
+	if err != nil {
+		return result, err
+	}
+
+	for _, property := range []string{"PackageId", "AssemblyName"} {
+		if match := msbuildProperty(property).FindStringSubmatch(d.content); match != nil && len(match[2]) > 0 {
+			result.ArtifactID = match[2]
+			return result, nil
+		}
+	}
+	if filepath.Ext(d.path) == ".props" {
+		// Directory.Build.props applies to all projects of the directory
+		absolutePath, err := filepath.Abs(d.path)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to determine directory of '%v'", d.path)
+		}
+		result.ArtifactID = filepath.Base(filepath.Dir(absolutePath))
+		return result, nil
+	}
+	result.ArtifactID = strings.TrimSuffix(filepath.Base(d.path), filepath.Ext(d.path))
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>netcoreapp3.1</TargetFramework>
+    <AssemblyName>My.Library</AssemblyName>
+    <VersionPrefix>1.2.3</VersionPrefix>
+  </PropertyGroup>
+</Project>
+`
+
+func TestDotnetGetVersion(t *testing.T) {
+	t.Run("success case - Version", func(t *testing.T) {
+		dotnet := Dotnet{
+			path: "Directory.Build.props",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("<Project><PropertyGroup><Version> 2.0.0 </Version></PropertyGroup></Project>"), nil
+			},
+		}
+		version, err := dotnet.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("success case - VersionPrefix", func(t *testing.T) {
+		dotnet := Dotnet{
+			path:     "my.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte(csproj), nil },
+		}
+		version, err := dotnet.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		dotnet := Dotnet{
+			path:     "my.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte("<Project></Project>"), nil },
+		}
+		_, err := dotnet.GetVersion()
+		assert.EqualError(t, err, "no version property [Version VersionPrefix] found in 'my.csproj'")
+	})
+
+	t.Run("error case - read", func(t *testing.T) {
+		dotnet := Dotnet{
+			path:     "my.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte{}, fmt.Errorf("read error") },
+		}
+		_, err := dotnet.GetVersion()
+		assert.EqualError(t, err, "failed to read file 'my.csproj': read error")
+	})
+}
+
+func TestDotnetSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		dotnet := Dotnet{
+			path:      "my.csproj",
+			readFile:  func(filename string) ([]byte, error) { return []byte(csproj), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := dotnet.SetVersion("1.3.0")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "    <VersionPrefix>1.3.0</VersionPrefix>\n")
+		assert.Contains(t, string(content), "<AssemblyName>My.Library</AssemblyName>")
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		dotnet := Dotnet{
+			path:      "my.csproj",
+			readFile:  func(filename string) ([]byte, error) { return []byte(csproj), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := dotnet.SetVersion("1.3.0")
+		assert.EqualError(t, err, "failed to write file 'my.csproj': write error")
+	})
+}
+
+func TestDotnetGetCoordinates(t *testing.T) {
+	t.Run("assembly name", func(t *testing.T) {
+		dotnet := Dotnet{
+			path:     "my.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte(csproj), nil },
+		}
+		coordinates, err := dotnet.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "My.Library", Version: "1.2.3"}, coordinates)
+	})
+
+	t.Run("package id", func(t *testing.T) {
+		dotnet := Dotnet{
+			path: "my.csproj",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("<PackageId>My.Package</PackageId><Version>1.0.0</Version>"), nil
+			},
+		}
+		coordinates, err := dotnet.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, "My.Package", coordinates.ArtifactID)
+	})
+
+	t.Run("project file name", func(t *testing.T) {
+		dotnet := Dotnet{
+			path:     "src/My.Service.csproj",
+			readFile: func(filename string) ([]byte, error) { return []byte("<Version>1.0.0</Version>"), nil },
+		}
+		coordinates, err := dotnet.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, "My.Service", coordinates.ArtifactID)
+	})
+}
@@ -0,0 +1,186 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// tomlTable matches the headers of tables like [package] as well as of arrays of tables like [[bin]]
+var tomlTable = regexp.MustCompile(`^\s*(\[\[?)\s*([^\[\]]+?)\s*\]\]?\s*(#.*)?$`)
+
+// tomlDottedKey matches dotted keys like package.version = "1.0.0"
+var tomlDottedKey = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+(?:\s*\.\s*[A-Za-z0-9_-]+)+)\s*=`)
+
+// TOMLfile defines an artifact using a toml file for versioning, e.g. Cargo.toml or pyproject.toml
+// The file is modified line by line in order to keep comments and formatting.
+// Dotted keys like package.version = "1.0.0" are not supported for name and version.
+type TOMLfile struct {
+	path string
+	// sections are the tables which may contain name and version, the first table containing the version is used.
+	// An empty section refers to the root table.
+	sections         []string
+	versionField     string
+	versioningScheme string
+	content          []string
+	readFile         func(string) ([]byte, error)
+	writeFile        func(string, []byte, os.FileMode) error
+}
+
+func (t *TOMLfile) init() {
+	if len(t.versionField) == 0 {
+		t.versionField = "version"
+	}
+	if len(t.sections) == 0 {
+		t.sections = []string{""}
+	}
+	if t.readFile == nil {
+		t.readFile = ioutil.ReadFile
+	}
+	if t.writeFile == nil {
+		t.writeFile = ioutil.WriteFile
+	}
+}
+
+func (t *TOMLfile) readContent() error {
+	t.init()
+	if t.content != nil {
+		return nil
+	}
+	content, err := t.readFile(t.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%v'", t.path)
+	}
+	t.content = strings.Split(string(content), "\n")
+	return nil
+}
+
+// find returns the line index and the match of a string value of the key within the section, -1 in case it is not available
+func (t *TOMLfile) find(section, key string) (int, []string) {
+	keyValue := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(key) + `\s*=\s*)(["'])([^"']*)(["'].*)$`)
+	current := ""
+	for i, line := range t.content {
+		if table := tableName(line); table != nil {
+			current = *table
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if match := keyValue.FindStringSubmatch(line); match != nil {
+			return i, match
+		}
+	}
+	return -1, nil
+}
+
+// tableName returns the name of the table in case the line is a table header.
+// Arrays of tables are prefixed with "[[" since their keys never describe the artifact.
+func tableName(line string) *string {
+	match := tomlTable.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	name := strings.ReplaceAll(match[2], " ", "")
+	if match[1] == "[[" {
+		name = "[[" + name
+	}
+	return &name
+}
+
+// findDottedKey returns the line index of a dotted key referring to the key within the section, -1 in case it is not available
+func (t *TOMLfile) findDottedKey(section, key string) int {
+	qualifiedKey := key
+	if len(section) > 0 {
+		qualifiedKey = section + "." + key
+	}
+	current := ""
+	for i, line := range t.content {
+		if table := tableName(line); table != nil {
+			current = *table
+			continue
+		}
+		match := tomlDottedKey.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		dottedKey := strings.ReplaceAll(match[1], " ", "")
+		if len(current) > 0 {
+			dottedKey = current + "." + dottedKey
+		}
+		if dottedKey == qualifiedKey {
+			return i
+		}
+	}
+	return -1
+}
+
+// versionSection returns the first section containing the version
+func (t *TOMLfile) versionSection() (string, error) {
+	err := t.readContent()
+	if err != nil {
+		return "", err
+	}
+	for _, section := range t.sections {
+		if index, _ := t.find(section, t.versionField); index >= 0 {
+			return section, nil
+		}
+		if index := t.findDottedKey(section, t.versionField); index >= 0 {
+			return "", fmt.Errorf("dotted key '%v' in line %v of '%v' is not supported, please define the field '%v' within the table [%v]", strings.TrimSpace(t.content[index]), index+1, t.path, t.versionField, section)
+		}
+	}
+	return "", fmt.Errorf("no field '%v' found in sections %v of '%v'", t.versionField, t.sections, t.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (t *TOMLfile) VersioningScheme() string {
+	if len(t.versioningScheme) == 0 {
+		return "semver2"
+	}
+	return t.versioningScheme
+}
+
+// GetVersion returns the current version of the artifact with a TOML-based build descriptor
+func (t *TOMLfile) GetVersion() (string, error) {
+	section, err := t.versionSection()
+	if err != nil {
+		return "", err
+	}
+	_, match := t.find(section, t.versionField)
+	return match[3], nil
+}
+
+// SetVersion updates the version of the artifact with a TOML-based build descriptor
+func (t *TOMLfile) SetVersion(version string) error {
+	section, err := t.versionSection()
+	if err != nil {
+		return errors.Wrap(err, "failed to set version")
+	}
+	index, match := t.find(section, t.versionField)
+	t.content[index] = match[1] + match[2] + version + match[4]
+
+	err = t.writeFile(t.path, []byte(strings.Join(t.content, "\n")), 0700)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", t.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates, the name is taken from the section containing the version
+func (t *TOMLfile) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	section, err := t.versionSection()
+	if err != nil {
+		return result, err
+	}
+	if _, match := t.find(section, "name"); match != nil {
+		result.ArtifactID = match[3]
+	}
+	_, match := t.find(section, t.versionField)
+	result.Version = match[3]
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cargoToml = `[package]
+name = "my-crate" # the crate name
+version = "1.2.3"
+edition = "2018"
+
+[dependencies]
+serde = { version = "1.0" }
+rand = "0.7"
+`
+
+const poetryToml = `[build-system]
+requires = ["poetry-core>=1.0.0"]
+version = "0.0.0"
+
+[tool.poetry]
+name = 'my-project'
+version = '0.3.1'
+`
+
+func TestTOMLfileGetVersion(t *testing.T) {
+	t.Run("success case - Cargo.toml", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "Cargo.toml",
+			sections: []string{"package", "workspace.package"},
+			readFile: func(filename string) ([]byte, error) { return []byte(cargoToml), nil },
+		}
+		version, err := tomlfile.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("success case - pyproject.toml", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "pyproject.toml",
+			sections: []string{"project", "tool.poetry"},
+			readFile: func(filename string) ([]byte, error) { return []byte(poetryToml), nil },
+		}
+		version, err := tomlfile.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "0.3.1", version)
+	})
+
+	t.Run("success case - root table", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:         "my.toml",
+			versionField: "theversion",
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("theversion=\"2.0.0\"\n[other]\ntheversion=\"1.0.0\""), nil
+			},
+		}
+		version, err := tomlfile.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("success case - array of tables", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "Cargo.toml",
+			sections: []string{"package", "workspace.package"},
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("[package]\nname = \"my-crate\"\n\n[[example]]\nname = \"demo\"\nversion = \"0.0.1\"\n\n[workspace.package]\nversion = \"1.2.3\"\n"), nil
+			},
+		}
+		version, err := tomlfile.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("error case - dotted key", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "Cargo.toml",
+			sections: []string{"package"},
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("package.name = \"my-crate\"\npackage.version = \"1.2.3\"\n"), nil
+			},
+		}
+		_, err := tomlfile.GetVersion()
+		assert.EqualError(t, err, "dotted key 'package.version = \"1.2.3\"' in line 2 of 'Cargo.toml' is not supported, please define the field 'version' within the table [package]")
+	})
+
+	t.Run("error case - dotted key within table", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "pyproject.toml",
+			sections: []string{"tool.poetry"},
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("[tool]\npoetry.name = \"my-project\"\npoetry . version = \"0.3.1\"\n"), nil
+			},
+		}
+		_, err := tomlfile.GetVersion()
+		assert.EqualError(t, err, "dotted key 'poetry . version = \"0.3.1\"' in line 3 of 'pyproject.toml' is not supported, please define the field 'version' within the table [tool.poetry]")
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "pyproject.toml",
+			sections: []string{"project"},
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("[project]\nname = \"my-project\"\ndynamic = [\"version\"]"), nil
+			},
+		}
+		_, err := tomlfile.GetVersion()
+		assert.EqualError(t, err, "no field 'version' found in sections [project] of 'pyproject.toml'")
+	})
+
+	t.Run("error case - read", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:     "Cargo.toml",
+			readFile: func(filename string) ([]byte, error) { return []byte{}, fmt.Errorf("read error") },
+		}
+		_, err := tomlfile.GetVersion()
+		assert.EqualError(t, err, "failed to read file 'Cargo.toml': read error")
+	})
+}
+
+func TestTOMLfileSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		tomlfile := TOMLfile{
+			path:      "Cargo.toml",
+			sections:  []string{"package"},
+			readFile:  func(filename string) ([]byte, error) { return []byte(cargoToml), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := tomlfile.SetVersion("1.3.0")
+		assert.NoError(t, err)
+		assert.Equal(t, `[package]
+name = "my-crate" # the crate name
+version = "1.3.0"
+edition = "2018"
+
+[dependencies]
+serde = { version = "1.0" }
+rand = "0.7"
+`, string(content))
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		tomlfile := TOMLfile{
+			path:      "Cargo.toml",
+			sections:  []string{"package"},
+			readFile:  func(filename string) ([]byte, error) { return []byte(cargoToml), nil },
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") },
+		}
+		err := tomlfile.SetVersion("1.3.0")
+		assert.EqualError(t, err, "failed to write file 'Cargo.toml': write error")
+	})
+}
+
+func TestTOMLfileGetCoordinates(t *testing.T) {
+	tomlfile := TOMLfile{
+		path:     "pyproject.toml",
+		sections: []string{"project", "tool.poetry"},
+		readFile: func(filename string) ([]byte, error) { return []byte(poetryToml), nil },
+	}
+	coordinates, err := tomlfile.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "my-project", Version: "0.3.1"}, coordinates)
+}
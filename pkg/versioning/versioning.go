@@ -52,6 +52,7 @@ func (m *mvnRunner) Evaluate(options *maven.EvaluateOptions, expression string,
 }
 
 var fileExists func(string) (bool, error)
+var glob = filepath.Glob
 
 // GetArtifact returns the build tool specific implementation for retrieving version, etc. of an artifact
 func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils Utils) (Artifact, error) {
@@ -60,6 +61,22 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 		fileExists = piperutils.FileExists
 	}
 	switch buildTool {
+	case "cargo":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "Cargo.toml"
+		}
+		artifact = &TOMLfile{
+			path:     buildDescriptorFilePath,
+			sections: []string{"package", "workspace.package"},
+		}
+	case "composer":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "composer.json"
+		}
+		artifact = &Composer{JSONfile{
+			path:         buildDescriptorFilePath,
+			versionField: "version",
+		}}
 	case "custom":
 		var err error
 		artifact, err = customArtifact(buildDescriptorFilePath, opts.VersionField, opts.VersionSection, opts.VersioningScheme)
@@ -74,6 +91,15 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			versionSource:    opts.VersionSource,
 			versioningScheme: opts.VersioningScheme,
 		}
+	case "dotnet":
+		if len(buildDescriptorFilePath) == 0 {
+			var err error
+			buildDescriptorFilePath, err = searchDotnetDescriptor()
+			if err != nil {
+				return artifact, err
+			}
+		}
+		artifact = &Dotnet{path: buildDescriptorFilePath}
 	case "dub":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "dub.json"
@@ -106,6 +132,16 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 		default:
 			artifact = &Versionfile{path: buildDescriptorFilePath}
 		}
+	case "helm":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "Chart.yaml"
+		}
+		artifact = &YAMLfile{
+			path:                    buildDescriptorFilePath,
+			versionField:            "version",
+			artifactIDField:         "name",
+			additionalVersionFields: []string{"appVersion"},
+		}
 	case "maven":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "pom.xml"
@@ -140,11 +176,20 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 	case "pip":
 		if len(buildDescriptorFilePath) == 0 {
 			var err error
-			buildDescriptorFilePath, err = searchDescriptor([]string{"setup.py", "version.txt", "VERSION"}, fileExists)
+			buildDescriptorFilePath, err = searchDescriptor([]string{"setup.py", "version.txt", "VERSION", "pyproject.toml"}, fileExists)
 			if err != nil {
 				return artifact, err
 			}
 		}
+		if filepath.Base(buildDescriptorFilePath) == "pyproject.toml" {
+			// PEP 621 project metadata or Poetry
+			artifact = &TOMLfile{
+				path:             buildDescriptorFilePath,
+				sections:         []string{"project", "tool.poetry"},
+				versioningScheme: "pep440",
+			}
+			break
+		}
 		artifact = &Pip{
 			path:       buildDescriptorFilePath,
 			fileExists: fileExists,
@@ -183,6 +228,18 @@ func searchDescriptor(supported []string, existsFunc func(string) (bool, error))
 	return descriptor, nil
 }
 
+// searchDotnetDescriptor prefers Directory.Build.props over a single project file
+func searchDotnetDescriptor() (string, error) {
+	if exists, _ := fileExists("Directory.Build.props"); exists {
+		return "Directory.Build.props", nil
+	}
+	projects, err := glob("*.csproj")
+	if err != nil || len(projects) != 1 {
+		return "", fmt.Errorf("no unique build descriptor available, supported: Directory.Build.props or a single *.csproj file")
+	}
+	return projects[0], nil
+}
+
 func customArtifact(buildDescriptorFilePath, field, section, scheme string) (Artifact, error) {
 	switch filepath.Ext(buildDescriptorFilePath) {
 	case ".cfg", ".ini":
@@ -197,6 +254,13 @@ func customArtifact(buildDescriptorFilePath, field, section, scheme string) (Art
 			path:         buildDescriptorFilePath,
 			versionField: field,
 		}, nil
+	case ".toml":
+		return &TOMLfile{
+			path:             buildDescriptorFilePath,
+			sections:         []string{section},
+			versionField:     field,
+			versioningScheme: scheme,
+		}, nil
 	case ".yaml", ".yml":
 		return &YAMLfile{
 			path:         buildDescriptorFilePath,
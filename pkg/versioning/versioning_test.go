@@ -1,12 +1,36 @@
 package versioning
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetArtifact(t *testing.T) {
+	t.Run("cargo", func(t *testing.T) {
+		cargo, err := GetArtifact("cargo", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := cargo.(*TOMLfile)
+		assert.True(t, ok)
+		assert.Equal(t, "Cargo.toml", theType.path)
+		assert.Equal(t, []string{"package", "workspace.package"}, theType.sections)
+		assert.Equal(t, "semver2", cargo.VersioningScheme())
+	})
+
+	t.Run("composer", func(t *testing.T) {
+		composer, err := GetArtifact("composer", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := composer.(*Composer)
+		assert.True(t, ok)
+		assert.Equal(t, "composer.json", theType.path)
+		assert.Equal(t, "semver2", composer.VersioningScheme())
+	})
+
 	t.Run("custom", func(t *testing.T) {
 		custom, err := GetArtifact("custom", "test.ini", &Options{VersionField: "theversion", VersionSection: "test"}, nil)
 
@@ -33,6 +57,32 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "docker", docker.VersioningScheme())
 	})
 
+	t.Run("dotnet", func(t *testing.T) {
+		fileExists = func(string) (bool, error) { return true, nil }
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := dotnet.(*Dotnet)
+		assert.True(t, ok)
+		assert.Equal(t, "Directory.Build.props", theType.path)
+		assert.Equal(t, "semver2", dotnet.VersioningScheme())
+	})
+
+	t.Run("dotnet - project file", func(t *testing.T) {
+		defer func() { glob = filepath.Glob }()
+		fileExists = func(string) (bool, error) { return false, nil }
+		glob = func(string) ([]string, error) { return []string{"my.csproj"}, nil }
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "my.csproj", dotnet.(*Dotnet).path)
+
+		glob = func(string) ([]string, error) { return []string{"a.csproj", "b.csproj"}, nil }
+		_, err = GetArtifact("dotnet", "", &Options{}, nil)
+		assert.EqualError(t, err, "no unique build descriptor available, supported: Directory.Build.props or a single *.csproj file")
+	})
+
 	t.Run("dub", func(t *testing.T) {
 		dub, err := GetArtifact("dub", "", &Options{VersionField: "theversion"}, nil)
 
@@ -76,6 +126,19 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "semver2", gradle.VersioningScheme())
 	})
 
+	t.Run("helm", func(t *testing.T) {
+		helm, err := GetArtifact("helm", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := helm.(*YAMLfile)
+		assert.True(t, ok)
+		assert.Equal(t, "Chart.yaml", theType.path)
+		assert.Equal(t, "name", theType.artifactIDField)
+		assert.Equal(t, []string{"appVersion"}, theType.additionalVersionFields)
+		assert.Equal(t, "semver2", helm.VersioningScheme())
+	})
+
 	t.Run("maven", func(t *testing.T) {
 		opts := Options{
 			ProjectSettingsFile: "projectsettings.xml",
@@ -142,11 +205,24 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "pep440", pip.VersioningScheme())
 	})
 
+	t.Run("pip - pyproject.toml", func(t *testing.T) {
+		fileExists = func(path string) (bool, error) { return path == "pyproject.toml", nil }
+		pip, err := GetArtifact("pip", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := pip.(*TOMLfile)
+		assert.True(t, ok)
+		assert.Equal(t, "pyproject.toml", theType.path)
+		assert.Equal(t, []string{"project", "tool.poetry"}, theType.sections)
+		assert.Equal(t, "pep440", pip.VersioningScheme())
+	})
+
 	t.Run("pip - error", func(t *testing.T) {
 		fileExists = func(string) (bool, error) { return false, nil }
 		_, err := GetArtifact("pip", "", &Options{}, nil)
 
-		assert.EqualError(t, err, "no build descriptor available, supported: [setup.py version.txt VERSION]")
+		assert.EqualError(t, err, "no build descriptor available, supported: [setup.py version.txt VERSION pyproject.toml]")
 	})
 
 	t.Run("sbt", func(t *testing.T) {
@@ -182,6 +258,7 @@ func TestCustomArtifact(t *testing.T) {
 		{file: "test.ini", field: "testField", section: "testSection", expected: &INIfile{path: "test.ini", versionField: "testField", versionSection: "testSection"}},
 		{file: "test.ini", field: "testField", section: "testSection", scheme: "maven", expected: &INIfile{path: "test.ini", versionField: "testField", versionSection: "testSection", versioningScheme: "maven"}},
 		{file: "test.json", field: "testField", expected: &JSONfile{path: "test.json", versionField: "testField"}},
+		{file: "test.toml", field: "testField", section: "testSection", expected: &TOMLfile{path: "test.toml", versionField: "testField", sections: []string{"testSection"}}},
 		{file: "test.yaml", field: "testField", expected: &YAMLfile{path: "test.yaml", versionField: "testField"}},
 		{file: "test.yml", field: "testField", expected: &YAMLfile{path: "test.yml", versionField: "testField"}},
 		{file: "test.txt", expected: &Versionfile{path: "test.txt"}},
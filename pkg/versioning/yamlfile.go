@@ -1,6 +1,7 @@
 package versioning
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 // YAMLDescriptor holds the unique identifier combination for an artifact
@@ -23,8 +25,10 @@ type YAMLfile struct {
 	content         map[string]interface{}
 	versionField    string
 	artifactIDField string
-	readFile        func(string) ([]byte, error)
-	writeFile       func(string, []byte, os.FileMode) error
+	// additionalVersionFields are updated together with the version in case they are present, e.g. appVersion of a Helm chart
+	additionalVersionFields []string
+	readFile                func(string) ([]byte, error)
+	writeFile               func(string, []byte, os.FileMode) error
 }
 
 func (y *YAMLfile) init() {
@@ -83,20 +87,49 @@ func (y *YAMLfile) GetVersion() (string, error) {
 	return y.readField(y.versionField)
 }
 
-// SetVersion updates the version of the artifact with a YAML-based build descriptor
+// SetVersion updates the version of the artifact with a YAML-based build descriptor.
+// Only the version fields are modified, the order of the remaining content as well as comments are retained.
 func (y *YAMLfile) SetVersion(version string) error {
 	err := y.readContent()
 	if err != nil {
 		return errors.Wrapf(err, "failed to set version")
 	}
+	raw, err := y.readFile(y.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%v'", y.path)
+	}
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(raw, &document); err != nil {
+		return errors.Wrapf(err, "failed to read yaml content of file '%v'", y.path)
+	}
+	if document.Kind == 0 {
+		// empty file
+		document = yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode, Tag: "!!map"}}}
+	}
+	mapping := document.Content[0]
+	if mapping.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("yaml content of file '%v' is not a map", y.path)
+	}
 
+	setYAMLScalar(mapping, y.versionField, version, true)
 	y.content[y.versionField] = version
+	for _, field := range y.additionalVersionFields {
+		if setYAMLScalar(mapping, field, version, false) {
+			y.content[field] = version
+		}
+	}
 
-	content, err := yaml.Marshal(y.content)
-	if err != nil {
+	var content bytes.Buffer
+	encoder := yamlv3.NewEncoder(&content)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return errors.Wrapf(err, "failed to create yaml content for '%v'", y.path)
+	}
+	if err := encoder.Close(); err != nil {
 		return errors.Wrapf(err, "failed to create yaml content for '%v'", y.path)
 	}
-	err = y.writeFile(y.path, content, 0700)
+	err = y.writeFile(y.path, content.Bytes(), 0700)
 	if err != nil {
 		return errors.Wrapf(err, "failed to write file '%v'", y.path)
 	}
@@ -104,6 +137,30 @@ func (y *YAMLfile) SetVersion(version string) error {
 	return nil
 }
 
+// setYAMLScalar sets the value of the key within the mapping node while keeping the style and comments of an existing value.
+// A missing key is only added if requested, the return value indicates whether the value has been set.
+func setYAMLScalar(mapping *yamlv3.Node, key, value string, add bool) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			existing := mapping.Content[i+1]
+			style := existing.Style
+			if existing.Kind != yamlv3.ScalarNode {
+				style = 0
+			}
+			mapping.Content[i+1] = &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value, Style: style,
+				HeadComment: existing.HeadComment, LineComment: existing.LineComment, FootComment: existing.FootComment}
+			return true
+		}
+	}
+	if !add {
+		return false
+	}
+	mapping.Content = append(mapping.Content,
+		&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key},
+		&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value})
+	return true
+}
+
 // GetCoordinates returns the coordinates
 func (y *YAMLfile) GetCoordinates() (Coordinates, error) {
 	result := Coordinates{}
@@ -68,6 +68,36 @@ func TestYAMLfileSetVersion(t *testing.T) {
 		assert.Contains(t, string(content), "theversion: 1.2.4")
 	})
 
+	t.Run("success case - Helm chart", func(t *testing.T) {
+		var content []byte
+		yamlfile := YAMLfile{
+			path:                    "Chart.yaml",
+			versionField:            "version",
+			additionalVersionFields: []string{"appVersion"},
+			readFile: func(filename string) ([]byte, error) {
+				return []byte("# my chart\nname: my-chart\nversion: 1.2.3 # chart version\nappVersion: \"1.2.3\"\nkeywords:\n  - piper\n"), nil
+			},
+			writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := yamlfile.SetVersion("1.2.4")
+		assert.NoError(t, err)
+		assert.Equal(t, "# my chart\nname: my-chart\nversion: 1.2.4 # chart version\nappVersion: \"1.2.4\"\nkeywords:\n  - piper\n", string(content))
+	})
+
+	t.Run("success case - Helm chart without appVersion", func(t *testing.T) {
+		var content []byte
+		yamlfile := YAMLfile{
+			path:                    "Chart.yaml",
+			versionField:            "version",
+			additionalVersionFields: []string{"appVersion"},
+			readFile:                func(filename string) ([]byte, error) { return []byte("name: my-chart\nversion: 1.2.3\n"), nil },
+			writeFile:               func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+		}
+		err := yamlfile.SetVersion("1.2.4")
+		assert.NoError(t, err)
+		assert.Equal(t, "name: my-chart\nversion: 1.2.4\n", string(content))
+	})
+
 	t.Run("error case", func(t *testing.T) {
 		yamlfile := YAMLfile{
 			path:         "my.yaml",
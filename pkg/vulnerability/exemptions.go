@@ -0,0 +1,202 @@
+package vulnerability
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// ExemptionsFile is the versioned file listing the exempted vulnerabilities which is honored by all scan steps
+const ExemptionsFile = ".pipeline/exemptions.yml"
+
+const exemptionDateLayout = "2006-01-02"
+
+// Exemption exempts findings from failing the build until the expiry date.
+// ID matches the CVE, the CWE, the tool specific identifier or the hash of a finding.
+// Component and Version optionally restrict the exemption to a specific component.
+type Exemption struct {
+	ID            string `json:"id"`
+	Component     string `json:"component,omitempty"`
+	Version       string `json:"version,omitempty"`
+	Justification string `json:"justification"`
+	Owner         string `json:"owner"`
+	Expires       string `json:"expires"`
+}
+
+// Exemptions is the content of the ExemptionsFile
+type Exemptions struct {
+	Exemptions []Exemption `json:"exemptions"`
+}
+
+// ExemptionUtils defines the file system functions required for reading the exemptions
+type ExemptionUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+}
+
+// ReadExemptions parses and validates exemptions
+func ReadExemptions(content []byte) (*Exemptions, error) {
+	exemptions := &Exemptions{}
+	if err := yaml.Unmarshal(content, exemptions); err != nil {
+		return nil, errors.Wrap(err, "failed to parse exemptions")
+	}
+	for i, exemption := range exemptions.Exemptions {
+		var missing []string
+		if len(exemption.ID) == 0 {
+			missing = append(missing, "id")
+		}
+		if len(exemption.Justification) == 0 {
+			missing = append(missing, "justification")
+		}
+		if len(exemption.Owner) == 0 {
+			missing = append(missing, "owner")
+		}
+		if len(exemption.Expires) == 0 {
+			missing = append(missing, "expires")
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("exemption %v (%v) is missing the fields %v", i+1, exemption.ID, missing)
+		}
+		if _, err := time.Parse(exemptionDateLayout, exemption.Expires); err != nil {
+			return nil, fmt.Errorf("exemption %v (%v) has an invalid expiry date '%v', expected format is YYYY-MM-DD", i+1, exemption.ID, exemption.Expires)
+		}
+	}
+	return exemptions, nil
+}
+
+// LoadExemptions reads the ExemptionsFile, an empty list of exemptions is returned in case the file does not exist
+func LoadExemptions(utils ExemptionUtils) (*Exemptions, error) {
+	exists, err := utils.FileExists(ExemptionsFile)
+	if err != nil || !exists {
+		return &Exemptions{}, nil
+	}
+	content, err := utils.FileRead(ExemptionsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read exemptions '%v'", ExemptionsFile)
+	}
+	exemptions, err := ReadExemptions(content)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "invalid exemptions '%v'", ExemptionsFile)
+	}
+	return exemptions, nil
+}
+
+// IsExpired checks if the expiry date of the exemption has passed, an exemption is valid until the end of its expiry date
+func (e *Exemption) IsExpired(now time.Time) bool {
+	return now.Format(exemptionDateLayout) > e.Expires
+}
+
+// Expired returns the exemptions which are expired
+func (e *Exemptions) Expired(now time.Time) []Exemption {
+	expired := []Exemption{}
+	if e == nil {
+		return expired
+	}
+	for _, exemption := range e.Exemptions {
+		if exemption.IsExpired(now) {
+			expired = append(expired, exemption)
+		}
+	}
+	return expired
+}
+
+// CheckExpired returns an error listing all expired exemptions
+func (e *Exemptions) CheckExpired(now time.Time) error {
+	expired := e.Expired(now)
+	if len(expired) == 0 {
+		return nil
+	}
+	descriptions := []string{}
+	for _, exemption := range expired {
+		descriptions = append(descriptions, fmt.Sprintf("%v (owner: %v, expired: %v)", exemption.ID, exemption.Owner, exemption.Expires))
+	}
+	log.SetErrorCategory(log.ErrorCompliance)
+	return fmt.Errorf("%v exemptions in '%v' are expired, please review them: %v", len(expired), ExemptionsFile, strings.Join(descriptions, ", "))
+}
+
+// Match returns the exemption matching the finding, nil in case the finding is not exempted.
+// The expiry date is not considered, expired exemptions are reported via CheckExpired.
+func (e *Exemptions) Match(finding *Finding) *Exemption {
+	if e == nil {
+		return nil
+	}
+	for i := range e.Exemptions {
+		if e.Exemptions[i].matches(finding) {
+			return &e.Exemptions[i]
+		}
+	}
+	return nil
+}
+
+func (e *Exemption) matches(finding *Finding) bool {
+	hash := finding.Hash
+	if len(hash) == 0 {
+		hash = finding.hash()
+	}
+	matchesID := false
+	for _, id := range []string{finding.CVE, finding.CWE, finding.ID, hash} {
+		if len(id) > 0 && strings.EqualFold(strings.TrimSpace(e.ID), id) {
+			matchesID = true
+			break
+		}
+	}
+	if !matchesID {
+		return false
+	}
+	if len(e.Component) == 0 {
+		return true
+	}
+	if finding.Component == nil || !strings.EqualFold(strings.TrimSpace(e.Component), strings.TrimSpace(finding.Component.Name)) {
+		return false
+	}
+	return len(e.Version) == 0 || strings.TrimSpace(e.Version) == strings.TrimSpace(finding.Component.Version)
+}
+
+// Apply marks the open findings matching an exemption as suppressed
+func (e *Exemptions) Apply(findings []Finding) []Finding {
+	result := make([]Finding, 0, len(findings))
+	for _, finding := range findings {
+		if exemption := e.Match(&finding); exemption != nil && finding.IsOpen() {
+			finding.Status = StatusSuppressed
+			finding.Justification = exemption.Justification
+			finding.ExemptedBy = exemption.Owner
+			log.Entry().Infof("vulnerability %v exempted by %v: %v", finding.ID, finding.ExemptedBy, finding.Justification)
+		}
+		result = append(result, finding)
+	}
+	return result
+}
+
+// CheckExemptions fails in case exemptions of the ExemptionsFile are expired
+func CheckExemptions(utils ExemptionUtils, now time.Time) error {
+	_, err := ValidExemptions(utils, now)
+	return err
+}
+
+// ValidExemptions reads the ExemptionsFile and fails in case exemptions are expired in order to enforce a regular review of the exemptions.
+// Scan steps use them to compute their quality gates from the findings which are not exempted.
+func ValidExemptions(utils ExemptionUtils, now time.Time) (*Exemptions, error) {
+	exemptions, err := LoadExemptions(utils)
+	if err != nil {
+		return nil, err
+	}
+	if err := exemptions.CheckExpired(now); err != nil {
+		return nil, err
+	}
+	return exemptions, nil
+}
+
+// ApplyExemptions applies the exemptions of the ExemptionsFile to the findings of a scan step.
+// It fails in case exemptions are expired in order to enforce a regular review of the exemptions.
+func ApplyExemptions(findings []Finding, utils ExemptionUtils, now time.Time) ([]Finding, error) {
+	exemptions, err := ValidExemptions(utils, now)
+	if err != nil {
+		return findings, err
+	}
+	return exemptions.Apply(findings), nil
+}
@@ -0,0 +1,130 @@
+package vulnerability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testExemptions = `exemptions:
+  - id: CVE-2021-44228
+    component: log4j-core
+    version: 2.14.1
+    justification: JNDI lookup disabled via system property
+    owner: security-team
+    expires: 2021-12-31
+  - id: cwe-79
+    justification: output is encoded by the framework
+    owner: jane.doe
+    expires: 2022-06-30
+`
+
+func TestReadExemptions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		exemptions, err := ReadExemptions([]byte(testExemptions))
+		assert.NoError(t, err)
+		require.Len(t, exemptions.Exemptions, 2)
+		assert.Equal(t, Exemption{ID: "CVE-2021-44228", Component: "log4j-core", Version: "2.14.1", Justification: "JNDI lookup disabled via system property", Owner: "security-team", Expires: "2021-12-31"}, exemptions.Exemptions[0])
+	})
+
+	t.Run("error - missing fields", func(t *testing.T) {
+		_, err := ReadExemptions([]byte("exemptions:\n  - id: CVE-2021-44228\n    expires: 2021-12-31\n"))
+		assert.EqualError(t, err, "exemption 1 (CVE-2021-44228) is missing the fields [justification owner]")
+	})
+
+	t.Run("error - invalid date", func(t *testing.T) {
+		_, err := ReadExemptions([]byte("exemptions:\n  - id: CVE-2021-44228\n    justification: j\n    owner: o\n    expires: 31.12.2021\n"))
+		assert.EqualError(t, err, "exemption 1 (CVE-2021-44228) has an invalid expiry date '31.12.2021', expected format is YYYY-MM-DD")
+	})
+
+	t.Run("error - invalid yaml", func(t *testing.T) {
+		_, err := ReadExemptions([]byte("exemptions: ["))
+		assert.Contains(t, err.Error(), "failed to parse exemptions")
+	})
+}
+
+func TestLoadExemptions(t *testing.T) {
+	t.Run("no exemptions file", func(t *testing.T) {
+		exemptions, err := LoadExemptions(&mock.FilesMock{})
+		assert.NoError(t, err)
+		assert.Len(t, exemptions.Exemptions, 0)
+	})
+
+	t.Run("invalid exemptions file", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile(ExemptionsFile, []byte("exemptions:\n  - id: CVE-2021-44228\n"))
+		_, err := LoadExemptions(utils)
+		assert.EqualError(t, err, "invalid exemptions '.pipeline/exemptions.yml': exemption 1 (CVE-2021-44228) is missing the fields [justification owner expires]")
+	})
+}
+
+func TestCheckExpired(t *testing.T) {
+	exemptions, err := ReadExemptions([]byte(testExemptions))
+	require.NoError(t, err)
+
+	t.Run("valid on expiry date", func(t *testing.T) {
+		assert.NoError(t, exemptions.CheckExpired(time.Date(2021, 12, 31, 23, 59, 0, 0, time.UTC)))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		err := exemptions.CheckExpired(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+		assert.EqualError(t, err, "1 exemptions in '.pipeline/exemptions.yml' are expired, please review them: CVE-2021-44228 (owner: security-team, expired: 2021-12-31)")
+	})
+
+	t.Run("no exemptions", func(t *testing.T) {
+		var exemptions *Exemptions
+		assert.NoError(t, exemptions.CheckExpired(time.Now()))
+	})
+}
+
+func TestApplyExemptions(t *testing.T) {
+	findings := []Finding{
+		{ID: "CVE-2021-44228", CVE: "CVE-2021-44228", Component: &Component{Name: "log4j-core", Version: "2.14.1"}, CVSS: 10.0, Status: StatusOpen},
+		{ID: "CVE-2021-44228", CVE: "CVE-2021-44228", Component: &Component{Name: "log4j-core", Version: "2.15.0"}, CVSS: 9.0, Status: StatusOpen},
+		{ID: "Reflected_XSS", CWE: "CWE-79", Location: Location{File: "src/index.js"}, Status: StatusOpen},
+		{ID: "SQL_Injection", CWE: "CWE-89", Location: Location{File: "src/db.js"}, Status: StatusSuppressed, Justification: "audited"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile(ExemptionsFile, []byte(testExemptions))
+
+		result, err := ApplyExemptions(findings, utils, time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		require.Len(t, result, 4)
+		assert.Equal(t, StatusSuppressed, result[0].Status)
+		assert.Equal(t, "security-team", result[0].ExemptedBy)
+		assert.Equal(t, "JNDI lookup disabled via system property", result[0].Justification)
+		assert.Equal(t, StatusOpen, result[1].Status)
+		assert.Equal(t, StatusSuppressed, result[2].Status)
+		assert.Equal(t, "jane.doe", result[2].ExemptedBy)
+		assert.Equal(t, "audited", result[3].Justification)
+		assert.Equal(t, "", result[3].ExemptedBy)
+		// input is not modified
+		assert.Equal(t, StatusOpen, findings[0].Status)
+	})
+
+	t.Run("finding hash", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile(ExemptionsFile, []byte("exemptions:\n  - id: "+findings[2].hash()+"\n    justification: test code\n    owner: jane.doe\n    expires: 2099-01-01\n"))
+
+		result, err := ApplyExemptions(findings, utils, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusOpen, result[0].Status)
+		assert.Equal(t, StatusSuppressed, result[2].Status)
+	})
+
+	t.Run("error - expired", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile(ExemptionsFile, []byte(testExemptions))
+
+		_, err := ApplyExemptions(findings, utils, time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.Contains(t, err.Error(), "2 exemptions in '.pipeline/exemptions.yml' are expired")
+	})
+}
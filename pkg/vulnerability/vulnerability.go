@@ -0,0 +1,295 @@
+package vulnerability
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FindingsDirectory is the directory the scan steps write their findings to, they are collected by step pipelineCreateScanSummary
+const FindingsDirectory = ".pipeline/vulnerabilities"
+
+// DefaultSeverityThreshold is the CVSS score from which on a vulnerability is considered severe, i.e. high or critical
+const DefaultSeverityThreshold = 7.0
+
+// Status of a finding
+const (
+	// StatusOpen marks findings which require an action
+	StatusOpen = "open"
+	// StatusSuppressed marks findings which were e.g. triaged, audited as not exploitable or excluded via configuration
+	StatusSuppressed = "suppressed"
+)
+
+// Severities derived from a CVSS score according to the qualitative severity rating scale of CVSS v3
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityNone     = "none"
+)
+
+// Component identifies the affected open source component of a finding
+type Component struct {
+	Group   string `json:"group,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Location identifies the affected file of a finding
+type Location struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// Finding is a vulnerability reported by one or more scan tools
+type Finding struct {
+	// ID is the tool specific identifier, e.g. the CVE, a WhiteSource id or a query name
+	ID          string     `json:"id"`
+	CVE         string     `json:"cve,omitempty"`
+	CWE         string     `json:"cwe,omitempty"`
+	Component   *Component `json:"component,omitempty"`
+	CVSS        float64    `json:"cvss,omitempty"`
+	CVSSVersion string     `json:"cvssVersion,omitempty"`
+	// Severity is the severity as reported by the tool, in case it is empty the severity is derived from the CVSS score
+	Severity    string   `json:"severity,omitempty"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Location    Location `json:"location,omitempty"`
+	// FixedVersions lists the versions of the component fixing the vulnerability, in case the tool reports them
+	FixedVersions []string `json:"fixedVersions,omitempty"`
+	// Tools lists the tools reporting the finding
+	Tools         []string `json:"tools"`
+	Status        string   `json:"status"`
+	Justification string   `json:"justification,omitempty"`
+	// ExemptedBy is the owner of the exemption suppressing the finding
+	ExemptedBy string `json:"exemptedBy,omitempty"`
+	// Hash identifies findings without CVE, e.g. static code analysis findings, in the exemptions
+	Hash string `json:"hash,omitempty"`
+}
+
+// Score returns the CVSS v3 score and falls back to the CVSS v2 score in case no v3 score is available
+func Score(cvss3, cvss2 float64) (float64, string) {
+	if cvss3 > 0 {
+		return cvss3, "3"
+	}
+	if cvss2 > 0 {
+		return cvss2, "2"
+	}
+	return 0, ""
+}
+
+// SeverityFromCVSS maps a CVSS score to its qualitative severity
+func SeverityFromCVSS(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNone
+}
+
+// CVEFromID returns the id in case it is a CVE identifier, an empty string otherwise
+func CVEFromID(id string) string {
+	if strings.HasPrefix(strings.ToUpper(id), "CVE-") {
+		return strings.ToUpper(id)
+	}
+	return ""
+}
+
+// EffectiveSeverity returns the severity reported by the tool or the one derived from the CVSS score
+func (f *Finding) EffectiveSeverity() string {
+	if f.CVSS > 0 {
+		return SeverityFromCVSS(f.CVSS)
+	}
+	if len(f.Severity) > 0 {
+		return strings.ToLower(f.Severity)
+	}
+	return SeverityNone
+}
+
+// IsSevere checks if the CVSS score of the finding reaches the threshold.
+// Findings without CVSS score are considered severe in case the tool reports a high or critical severity.
+func (f *Finding) IsSevere(threshold float64) bool {
+	if f.CVSS > 0 {
+		return f.CVSS >= threshold
+	}
+	severity := f.EffectiveSeverity()
+	return severity == SeverityCritical || severity == SeverityHigh
+}
+
+// IsOpen checks if the finding still requires an action
+func (f *Finding) IsOpen() bool {
+	return f.Status != StatusSuppressed
+}
+
+// hash is independent of the line of the finding in order to remain stable while the code changes
+func (f *Finding) hash() string {
+	parts := []string{f.ID, f.CVE, f.Location.File}
+	if f.Component != nil {
+		parts = append(parts, f.Component.Group, f.Component.Name, f.Component.Version)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(parts, "|"))))[:16]
+}
+
+// key identifies the same vulnerability of the same component across tools, an empty key is returned for findings which can't be correlated.
+// The group of the component is compared separately, see sameGroup.
+func (f *Finding) key() string {
+	if len(f.CVE) == 0 || f.Component == nil || len(f.Component.Name) == 0 {
+		return ""
+	}
+	return strings.Join([]string{f.CVE, strings.ToLower(strings.TrimSpace(f.Component.Name)), strings.TrimSpace(f.Component.Version)}, "|")
+}
+
+// sameGroup checks if the components of both findings belong to the same group, e.g. the groupId of Maven artifacts
+// which may share the same name. Since not all tools report the group, a missing group matches any group.
+func sameGroup(finding, other Finding) bool {
+	group := strings.ToLower(strings.TrimSpace(finding.Component.Group))
+	otherGroup := strings.ToLower(strings.TrimSpace(other.Component.Group))
+	return len(group) == 0 || len(otherGroup) == 0 || group == otherGroup
+}
+
+// Deduplicate merges findings of the same CVE in the same component reported by several tools.
+// The merged finding keeps the highest CVSS score and is only suppressed in case all tools report it as suppressed.
+// Findings without CVE or component are kept as they are. The order of the first occurrence is preserved.
+func Deduplicate(findings []Finding) []Finding {
+	result := []Finding{}
+	index := map[string][]int{}
+	for _, finding := range findings {
+		key := finding.key()
+		existing := -1
+		if len(key) > 0 {
+			for _, candidate := range index[key] {
+				if sameGroup(result[candidate], finding) {
+					existing = candidate
+					break
+				}
+			}
+		}
+		if existing < 0 {
+			if len(key) > 0 {
+				index[key] = append(index[key], len(result))
+			}
+			finding.Tools = append([]string{}, finding.Tools...)
+			result = append(result, finding)
+			continue
+		}
+		result[existing] = merge(result[existing], finding)
+	}
+	return result
+}
+
+func merge(finding, other Finding) Finding {
+	if other.CVSS > finding.CVSS {
+		finding.CVSS, finding.CVSSVersion = other.CVSS, other.CVSSVersion
+	}
+	if finding.IsOpen() || other.IsOpen() {
+		finding.Status, finding.Justification, finding.ExemptedBy = StatusOpen, "", ""
+	} else if len(finding.Justification) == 0 {
+		finding.Justification, finding.ExemptedBy = other.Justification, other.ExemptedBy
+	}
+	finding.CWE = firstNonEmpty(finding.CWE, other.CWE)
+	finding.Severity = firstNonEmpty(finding.Severity, other.Severity)
+	finding.Description = firstNonEmpty(finding.Description, other.Description)
+	if len(finding.Component.Group) == 0 {
+		// only findings with component are merged
+		finding.Component = &Component{Group: other.Component.Group, Name: finding.Component.Name, Version: finding.Component.Version}
+	}
+	finding.URL = firstNonEmpty(finding.URL, other.URL)
+	if len(finding.Location.File) == 0 {
+		finding.Location = other.Location
+	}
+	for _, tool := range other.Tools {
+		if !contains(finding.Tools, tool) {
+			finding.Tools = append(finding.Tools, tool)
+		}
+	}
+	fixedVersions := append([]string{}, finding.FixedVersions...)
+	for _, version := range other.FixedVersions {
+		if !contains(fixedVersions, version) {
+			fixedVersions = append(fixedVersions, version)
+		}
+	}
+	if len(fixedVersions) > 0 {
+		finding.FixedVersions = fixedVersions
+	}
+	sort.Strings(finding.Tools)
+	return finding
+}
+
+func firstNonEmpty(value, other string) string {
+	if len(value) > 0 {
+		return value
+	}
+	return other
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Severe returns the open findings reaching the CVSS threshold
+func Severe(findings []Finding, threshold float64) []Finding {
+	severe := []Finding{}
+	for _, finding := range findings {
+		if finding.IsOpen() && finding.IsSevere(threshold) {
+			severe = append(severe, finding)
+		}
+	}
+	return severe
+}
+
+// FileUtils defines the file system functions required for writing findings
+type FileUtils interface {
+	MkdirAll(path string, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// WriteFindings writes the findings of a scan step into the FindingsDirectory
+func WriteFindings(findings []Finding, name string, utils FileUtils) (string, error) {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	for i := range findings {
+		if len(findings[i].Hash) == 0 {
+			findings[i].Hash = findings[i].hash()
+		}
+	}
+	content, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal findings")
+	}
+	if err := utils.MkdirAll(FindingsDirectory, 0777); err != nil {
+		return "", errors.Wrapf(err, "failed to create directory '%v'", FindingsDirectory)
+	}
+	path := filepath.Join(FindingsDirectory, name+".json")
+	if err := utils.FileWrite(path, content, 0666); err != nil {
+		return "", errors.Wrapf(err, "failed to write findings '%v'", path)
+	}
+	return path, nil
+}
+
+// ReadFindings parses findings written by WriteFindings
+func ReadFindings(content []byte) ([]Finding, error) {
+	findings := []Finding{}
+	if err := json.Unmarshal(content, &findings); err != nil {
+		return nil, errors.Wrap(err, "failed to parse findings")
+	}
+	return findings, nil
+}
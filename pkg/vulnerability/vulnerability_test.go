@@ -0,0 +1,203 @@
+package vulnerability
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScore(t *testing.T) {
+	t.Run("CVSS v3", func(t *testing.T) {
+		score, version := Score(9.8, 5.0)
+		assert.Equal(t, 9.8, score)
+		assert.Equal(t, "3", version)
+	})
+	t.Run("fallback to CVSS v2", func(t *testing.T) {
+		score, version := Score(0, 5.0)
+		assert.Equal(t, 5.0, score)
+		assert.Equal(t, "2", version)
+	})
+	t.Run("no score", func(t *testing.T) {
+		score, version := Score(0, 0)
+		assert.Equal(t, 0.0, score)
+		assert.Equal(t, "", version)
+	})
+}
+
+func TestSeverityFromCVSS(t *testing.T) {
+	assert.Equal(t, SeverityCritical, SeverityFromCVSS(9.0))
+	assert.Equal(t, SeverityHigh, SeverityFromCVSS(7.0))
+	assert.Equal(t, SeverityMedium, SeverityFromCVSS(6.9))
+	assert.Equal(t, SeverityLow, SeverityFromCVSS(0.1))
+	assert.Equal(t, SeverityNone, SeverityFromCVSS(0))
+}
+
+func TestCVEFromID(t *testing.T) {
+	assert.Equal(t, "CVE-2021-44228", CVEFromID("cve-2021-44228"))
+	assert.Equal(t, "", CVEFromID("WS-2020-0001"))
+}
+
+func TestIsSevere(t *testing.T) {
+	t.Run("CVSS score", func(t *testing.T) {
+		assert.True(t, (&Finding{CVSS: 7.0}).IsSevere(7.0))
+		assert.False(t, (&Finding{CVSS: 6.9, Severity: "high"}).IsSevere(7.0))
+	})
+	t.Run("tool severity", func(t *testing.T) {
+		assert.True(t, (&Finding{Severity: "Critical"}).IsSevere(7.0))
+		assert.True(t, (&Finding{Severity: "high"}).IsSevere(9.0))
+		assert.False(t, (&Finding{Severity: "medium"}).IsSevere(0))
+		assert.False(t, (&Finding{}).IsSevere(7.0))
+	})
+}
+
+func TestDeduplicate(t *testing.T) {
+	t.Run("same CVE and component", func(t *testing.T) {
+		findings := []Finding{
+			{ID: "CVE-2021-44228", CVE: "CVE-2021-44228", Component: &Component{Name: "log4j-core", Version: "2.14.1"}, CVSS: 9.8, CVSSVersion: "3", Tools: []string{"WhiteSource"}, Status: StatusOpen},
+			{ID: "WS-2020-0001", Component: &Component{Name: "lodash", Version: "4.17.15"}, Tools: []string{"WhiteSource"}, Status: StatusOpen},
+			{ID: "CVE-2021-44228", CVE: "CVE-2021-44228", Component: &Component{Name: "Log4j-Core", Version: "2.14.1"}, CVSS: 10.0, CVSSVersion: "3", Description: "JNDI lookup", Tools: []string{"Protecode"}, Status: StatusSuppressed, Justification: "not used"},
+			{ID: "CVE-2021-44228", CVE: "CVE-2021-44228", Component: &Component{Name: "log4j-core", Version: "2.15.0"}, CVSS: 9.0, Tools: []string{"BlackDuck"}, Status: StatusOpen},
+			{ID: "SQL_Injection", Location: Location{File: "src/main.go", Line: 12}, Tools: []string{"Checkmarx"}, Status: StatusOpen},
+		}
+
+		result := Deduplicate(findings)
+
+		require.Len(t, result, 4)
+		assert.Equal(t, 10.0, result[0].CVSS)
+		assert.Equal(t, []string{"Protecode", "WhiteSource"}, result[0].Tools)
+		assert.Equal(t, StatusOpen, result[0].Status)
+		assert.Equal(t, "", result[0].Justification)
+		assert.Equal(t, "JNDI lookup", result[0].Description)
+		assert.Equal(t, "WS-2020-0001", result[1].ID)
+		assert.Equal(t, "2.15.0", result[2].Component.Version)
+		assert.Equal(t, "SQL_Injection", result[3].ID)
+		// input is not modified
+		assert.Equal(t, []string{"WhiteSource"}, findings[0].Tools)
+	})
+
+	t.Run("suppressed by all tools", func(t *testing.T) {
+		findings := []Finding{
+			{CVE: "CVE-2020-1234", Component: &Component{Name: "lib"}, Tools: []string{"WhiteSource"}, Status: StatusSuppressed},
+			{CVE: "CVE-2020-1234", Component: &Component{Name: "lib"}, Tools: []string{"Protecode"}, Status: StatusSuppressed, Justification: "false positive"},
+		}
+
+		result := Deduplicate(findings)
+
+		require.Len(t, result, 1)
+		assert.False(t, result[0].IsOpen())
+		assert.Equal(t, "false positive", result[0].Justification)
+	})
+
+	t.Run("same component name in different groups", func(t *testing.T) {
+		findings := []Finding{
+			{CVE: "CVE-2020-1234", Component: &Component{Group: "org.example", Name: "core", Version: "1.0"}, Tools: []string{"WhiteSource"}, Status: StatusOpen},
+			{CVE: "CVE-2020-1234", Component: &Component{Group: "com.other", Name: "core", Version: "1.0"}, Tools: []string{"WhiteSource"}, Status: StatusOpen},
+			{CVE: "CVE-2020-1234", Component: &Component{Group: "Org.Example", Name: "core", Version: "1.0"}, Tools: []string{"Protecode"}, Status: StatusOpen},
+			{CVE: "CVE-2020-1234", Component: &Component{Name: "core", Version: "1.0"}, Tools: []string{"BlackDuck"}, Status: StatusOpen},
+		}
+
+		result := Deduplicate(findings)
+
+		require.Len(t, result, 2)
+		assert.Equal(t, "org.example", result[0].Component.Group)
+		assert.Equal(t, []string{"BlackDuck", "Protecode", "WhiteSource"}, result[0].Tools)
+		assert.Equal(t, "com.other", result[1].Component.Group)
+		assert.Equal(t, []string{"WhiteSource"}, result[1].Tools)
+	})
+
+	t.Run("fixed versions of all tools", func(t *testing.T) {
+		findings := []Finding{
+			{CVE: "CVE-2020-1234", Component: &Component{Name: "lib", Version: "1.0"}, FixedVersions: []string{"1.1"}, Tools: []string{"OSV"}, Status: StatusOpen},
+			{CVE: "CVE-2020-1234", Component: &Component{Name: "lib", Version: "1.0"}, FixedVersions: []string{"1.1", "2.0"}, Tools: []string{"Other"}, Status: StatusOpen},
+		}
+
+		result := Deduplicate(findings)
+
+		require.Len(t, result, 1)
+		assert.Equal(t, []string{"1.1", "2.0"}, result[0].FixedVersions)
+		assert.Equal(t, []string{"1.1"}, findings[0].FixedVersions)
+	})
+
+	t.Run("group only reported by one tool", func(t *testing.T) {
+		findings := []Finding{
+			{CVE: "CVE-2020-1234", Component: &Component{Name: "core", Version: "1.0"}, Tools: []string{"Protecode"}, Status: StatusOpen},
+			{CVE: "CVE-2020-1234", Component: &Component{Group: "org.example", Name: "core", Version: "1.0"}, Tools: []string{"WhiteSource"}, Status: StatusOpen},
+		}
+
+		result := Deduplicate(findings)
+
+		require.Len(t, result, 1)
+		assert.Equal(t, "org.example", result[0].Component.Group)
+		assert.Equal(t, []string{"Protecode", "WhiteSource"}, result[0].Tools)
+		// input is not modified
+		assert.Empty(t, findings[0].Component.Group)
+	})
+}
+
+func TestSevere(t *testing.T) {
+	findings := []Finding{
+		{ID: "1", CVSS: 9.8, Status: StatusOpen},
+		{ID: "2", CVSS: 9.8, Status: StatusSuppressed},
+		{ID: "3", CVSS: 5.0, Status: StatusOpen},
+	}
+	severe := Severe(findings, 7.0)
+	require.Len(t, severe, 1)
+	assert.Equal(t, "1", severe[0].ID)
+}
+
+type filesMock struct {
+	*mock.FilesMock
+	writeError error
+}
+
+func (f *filesMock) FileWrite(path string, content []byte, perm os.FileMode) error {
+	if f.writeError != nil {
+		return f.writeError
+	}
+	return f.FilesMock.FileWrite(path, content, perm)
+}
+
+func TestWriteFindings(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		utils := &filesMock{FilesMock: &mock.FilesMock{}}
+		findings := []Finding{{ID: "CVE-2021-44228", CVE: "CVE-2021-44228", Component: &Component{Name: "log4j-core"}, CVSS: 10.0, Tools: []string{"Protecode"}, Status: StatusOpen}}
+
+		path, err := WriteFindings(findings, "protecodeExecuteScan", utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ".pipeline/vulnerabilities/protecodeExecuteScan.json", path)
+		content, err := utils.FileRead(path)
+		require.NoError(t, err)
+		read, err := ReadFindings(content)
+		assert.NoError(t, err)
+		assert.Equal(t, findings, read)
+		assert.Len(t, read[0].Hash, 16)
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		utils := &filesMock{FilesMock: &mock.FilesMock{}}
+
+		path, err := WriteFindings(nil, "step", utils)
+
+		assert.NoError(t, err)
+		content, _ := utils.FileRead(path)
+		assert.Equal(t, "[]", string(content))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		utils := &filesMock{FilesMock: &mock.FilesMock{}, writeError: fmt.Errorf("write error")}
+
+		_, err := WriteFindings(nil, "step", utils)
+
+		assert.EqualError(t, err, "failed to write findings '.pipeline/vulnerabilities/step.json': write error")
+	})
+}
+
+func TestReadFindings(t *testing.T) {
+	_, err := ReadFindings([]byte("{"))
+	assert.Contains(t, err.Error(), "failed to parse findings")
+}
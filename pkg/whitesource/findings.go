@@ -0,0 +1,31 @@
+package whitesource
+
+import (
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+)
+
+// ConvertAlertsToFindings maps security vulnerability alerts to the common finding model
+func ConvertAlertsToFindings(alerts []Alert) []vulnerability.Finding {
+	findings := []vulnerability.Finding{}
+	for _, alert := range alerts {
+		score, cvssVersion := vulnerability.Score(alert.Vulnerability.CVSS3Score, alert.Vulnerability.Score)
+		name := alert.Library.ArtifactID
+		if len(name) == 0 {
+			name = alert.Library.Name
+		}
+		findings = append(findings, vulnerability.Finding{
+			ID:          alert.Vulnerability.Name,
+			CVE:         vulnerability.CVEFromID(alert.Vulnerability.Name),
+			Component:   &vulnerability.Component{Group: alert.Library.GroupID, Name: name, Version: alert.Library.Version},
+			CVSS:        score,
+			CVSSVersion: cvssVersion,
+			Severity:    alert.Vulnerability.Severity,
+			Description: alert.Vulnerability.Description,
+			URL:         alert.Vulnerability.URL,
+			Location:    vulnerability.Location{File: alert.Library.Filename},
+			Tools:       []string{"WhiteSource"},
+			Status:      vulnerability.StatusOpen,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,41 @@
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAlertsToFindings(t *testing.T) {
+	alerts := []Alert{
+		{
+			Library:       Library{Name: "log4j-core-2.14.1.jar", Filename: "log4j-core-2.14.1.jar", GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1"},
+			Vulnerability: Vulnerability{Name: "CVE-2021-44228", Severity: "high", Score: 9.3, CVSS3Score: 10.0, URL: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"},
+		},
+		{
+			Library:       Library{Name: "lodash-4.17.15.tgz", Version: "4.17.15"},
+			Vulnerability: Vulnerability{Name: "WS-2020-0001", Severity: "medium", Score: 5.3},
+		},
+	}
+
+	findings := ConvertAlertsToFindings(alerts)
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, vulnerability.Finding{
+		ID:          "CVE-2021-44228",
+		CVE:         "CVE-2021-44228",
+		Component:   &vulnerability.Component{Group: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1"},
+		CVSS:        10.0,
+		CVSSVersion: "3",
+		Severity:    "high",
+		URL:         "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+		Location:    vulnerability.Location{File: "log4j-core-2.14.1.jar"},
+		Tools:       []string{"WhiteSource"},
+		Status:      vulnerability.StatusOpen,
+	}, findings[0])
+	assert.Equal(t, "", findings[1].CVE)
+	assert.Equal(t, "lodash-4.17.15.tgz", findings[1].Component.Name)
+	assert.Equal(t, "2", findings[1].CVSSVersion)
+}
@@ -0,0 +1,77 @@
+package whitesource
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// ConvertAlertsToSarif converts security vulnerability alerts into a SARIF log.
+// Every vulnerability is represented as rule, alerts with a CVSS score greater or equal to the cvssSeverityLimit are reported as errors.
+func ConvertAlertsToSarif(alerts []Alert, cvssSeverityLimit float64) format.SARIF {
+	run := format.NewRun("WhiteSource", "", "https://www.whitesourcesoftware.com")
+	for _, alert := range alerts {
+		score, cvssVersion := alert.Vulnerability.CVSS3Score, "v3"
+		if score <= 0 {
+			score, cvssVersion = alert.Vulnerability.Score, "v2"
+		}
+
+		rule := format.SarifRule{
+			ID:               alert.Vulnerability.Name,
+			Name:             alert.Vulnerability.Name,
+			ShortDescription: &format.Message{Text: alert.Vulnerability.Name},
+			FullDescription:  &format.Message{Text: alert.Vulnerability.Description},
+			HelpURI:          alert.Vulnerability.URL,
+			Properties:       &format.SarifRuleProperties{Tags: []string{"security", "vulnerability"}, SecuritySeverity: format.SecuritySeverity(score)},
+		}
+		if topFix := alert.Vulnerability.TopFix; topFix != (Fix{}) {
+			rule.Help = &format.Message{
+				Text:     fmt.Sprintf("%v %v", topFix.Message, topFix.FixResolution),
+				Markdown: fmt.Sprintf("%v<br>%v<br>[%v](%v)", topFix.Message, topFix.FixResolution, topFix.URL, topFix.URL),
+			}
+		}
+		run.AddRule(rule)
+
+		level := format.SarifLevelFromCVSS(score)
+		if cvssSeverityLimit >= 0 {
+			if score >= cvssSeverityLimit {
+				level = format.SarifLevelError
+			} else if level == format.SarifLevelError {
+				level = format.SarifLevelWarning
+			}
+		}
+
+		library := libraryCoordinates(alert.Library)
+		result := format.Results{
+			RuleID:              alert.Vulnerability.Name,
+			Level:               level,
+			Message:             format.Message{Text: fmt.Sprintf("%v in %v", alert.Vulnerability.Name, library)},
+			PartialFingerprints: map[string]string{"whitesourceAlert": fmt.Sprintf("%v/%v/%v", alert.Project, library, alert.Vulnerability.Name)},
+			Properties: map[string]string{
+				"project":     alert.Project,
+				"library":     library,
+				"cvssScore":   fmt.Sprint(score),
+				"cvssVersion": cvssVersion,
+			},
+		}
+		if len(alert.Library.Filename) > 0 {
+			result.Locations = []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: alert.Library.Filename}}}}
+		}
+		run.AddResult(result)
+	}
+	return format.NewSARIF(run)
+}
+
+func libraryCoordinates(library Library) string {
+	parts := []string{}
+	for _, part := range []string{library.GroupID, library.ArtifactID, library.Version} {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	if len(library.ArtifactID) == 0 {
+		return library.Filename
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,57 @@
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAlertsToSarif(t *testing.T) {
+	alerts := []Alert{
+		{
+			Project:       "project1",
+			Library:       Library{Filename: "log4j-core-2.14.1.jar", GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1"},
+			Vulnerability: Vulnerability{Name: "CVE-2021-44228", CVSS3Score: 10.0, Score: 9.3, URL: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228", Description: "JNDI lookup", TopFix: Fix{Message: "Upgrade", FixResolution: "Upgrade to 2.15.0", URL: "https://logging.apache.org"}},
+		},
+		{
+			Project:       "project2",
+			Library:       Library{Filename: "lodash-4.17.15.tgz"},
+			Vulnerability: Vulnerability{Name: "CVE-2020-8203", Score: 7.4},
+		},
+		{
+			Project:       "project2",
+			Library:       Library{Filename: "lodash-4.17.15.tgz"},
+			Vulnerability: Vulnerability{Name: "CVE-2021-23337", CVSS3Score: 5.3},
+		},
+	}
+
+	sarif := ConvertAlertsToSarif(alerts, 8.0)
+
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	assert.Equal(t, "WhiteSource", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 3)
+	rule := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "CVE-2021-44228", rule.ID)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-44228", rule.HelpURI)
+	assert.Equal(t, "10.0", rule.Properties.SecuritySeverity)
+	assert.Equal(t, "Upgrade Upgrade to 2.15.0", rule.Help.Text)
+	assert.Nil(t, run.Tool.Driver.Rules[1].Help)
+
+	require.Len(t, run.Results, 3)
+	first := run.Results[0]
+	assert.Equal(t, format.SarifLevelError, first.Level)
+	assert.Equal(t, "CVE-2021-44228 in org.apache.logging.log4j:log4j-core:2.14.1", first.Message.Text)
+	assert.Equal(t, "log4j-core-2.14.1.jar", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "v3", first.Properties["cvssVersion"])
+
+	// below the severity limit
+	second := run.Results[1]
+	assert.Equal(t, format.SarifLevelWarning, second.Level)
+	assert.Equal(t, "CVE-2020-8203 in lodash-4.17.15.tgz", second.Message.Text)
+	assert.Equal(t, "v2", second.Properties["cvssVersion"])
+
+	assert.Equal(t, format.SarifLevelWarning, run.Results[2].Level)
+}
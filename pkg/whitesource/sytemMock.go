@@ -123,6 +123,11 @@ func (m *SystemMock) GetProjectLibraryLocations(projectToken string) ([]Library,
 	return m.Libraries, nil
 }
 
+// GetProjectLicenses returns the libraries stored in the SystemMock.
+func (m *SystemMock) GetProjectLicenses(projectToken string) ([]Library, error) {
+	return m.Libraries, nil
+}
+
 // NewSystemMockWithProjectName returns a pointer to a new instance of SystemMock using a project with a defined name.
 func NewSystemMockWithProjectName(lastUpdateDate, projectName string) *SystemMock {
 	mockLibrary := Library{
@@ -57,6 +57,22 @@ type Library struct {
 	GroupID    string `json:"groupId,omitempty"`
 	Version    string `json:"version,omitempty"`
 	Project    string `json:"project,omitempty"`
+	// Licenses and CopyrightReferences are only returned by GetProjectLicenses
+	Licenses            []License            `json:"licenses,omitempty"`
+	CopyrightReferences []CopyrightReference `json:"copyrightReferences,omitempty"`
+}
+
+// License defines a license of a library as returned by WhiteSource
+type License struct {
+	Name     string `json:"name,omitempty"`
+	SPDXName string `json:"spdxName,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// CopyrightReference defines a copyright statement found in a library
+type CopyrightReference struct {
+	Type      string `json:"type,omitempty"`
+	Copyright string `json:"copyright,omitempty"`
 }
 
 // Vulnerability defines a vulnerability as returned by WhiteSource
@@ -453,6 +469,27 @@ func (s *System) GetProjectLibraryLocations(projectToken string) ([]Library, err
 	return wsResponse.Libraries, nil
 }
 
+// GetProjectLicenses returns the libraries of a project including their licenses and copyrights
+func (s *System) GetProjectLicenses(projectToken string) ([]Library, error) {
+	wsResponse := struct {
+		Libraries []Library `json:"libraries"`
+	}{
+		Libraries: []Library{},
+	}
+
+	req := Request{
+		RequestType:  "getProjectLicenses",
+		ProjectToken: projectToken,
+	}
+
+	err := s.sendRequestAndDecodeJSON(req, &wsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return wsResponse.Libraries, nil
+}
+
 func (s *System) sendRequestAndDecodeJSON(req Request, result interface{}) error {
 	respBody, err := s.sendRequest(req)
 	if err != nil {
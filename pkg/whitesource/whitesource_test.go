@@ -346,3 +346,36 @@ func TestGetProjectAlertsByType(t *testing.T) {
 
 	})
 }
+
+func TestGetProjectLicenses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success case", func(t *testing.T) {
+		responseBody := `{"libraries":[{"name":"jackson-databind-2.9.8.jar","groupId":"com.fasterxml.jackson.core","artifactId":"jackson-databind","version":"2.9.8","licenses":[{"name":"Apache 2.0","spdxName":"Apache-2.0","url":"http://apache.org/licenses/LICENSE-2.0"}],"copyrightReferences":[{"type":"COPYRIGHT","copyright":"Copyright 2007 FasterXML"}]}]}`
+		myTestClient := whitesourceMockClient{responseBody: responseBody}
+		sys := System{serverURL: "https://my.test.server", httpClient: &myTestClient, orgToken: "test_org_token", userToken: "test_user_token"}
+
+		libraries, err := sys.GetProjectLicenses("test_project_token")
+
+		assert.NoError(t, err)
+		requestBody, err := ioutil.ReadAll(myTestClient.requestBody)
+		assert.NoError(t, err)
+		assert.Contains(t, string(requestBody), `"requestType":"getProjectLicenses"`)
+		assert.Equal(t, []Library{{
+			Name:                "jackson-databind-2.9.8.jar",
+			GroupID:             "com.fasterxml.jackson.core",
+			ArtifactID:          "jackson-databind",
+			Version:             "2.9.8",
+			Licenses:            []License{{Name: "Apache 2.0", SPDXName: "Apache-2.0", URL: "http://apache.org/licenses/LICENSE-2.0"}},
+			CopyrightReferences: []CopyrightReference{{Type: "COPYRIGHT", Copyright: "Copyright 2007 FasterXML"}},
+		}}, libraries)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		myTestClient := whitesourceMockClient{requestError: fmt.Errorf("request failed")}
+		sys := System{serverURL: "https://my.test.server", httpClient: &myTestClient, orgToken: "test_org_token", userToken: "test_user_token"}
+
+		_, err := sys.GetProjectLicenses("test_project_token")
+		assert.EqualError(t, err, "sending whiteSource request failed: failed to send request to WhiteSource: request failed")
+	})
+}
@@ -144,6 +144,7 @@ public class CommonStepsTest extends BasePiperTest{
         'githubPublishRelease', //implementing new golang pattern without fields
         'githubCheckBranchProtection', //implementing new golang pattern without fields
         'githubCommentIssue', //implementing new golang pattern without fields
+        'githubPublishScanResults', //implementing new golang pattern without fields
         'githubSetCommitStatus', //implementing new golang pattern without fields
         'kubernetesDeploy', //implementing new golang pattern without fields
         'piperExecuteBin', //implementing new golang pattern without fields
@@ -176,6 +177,11 @@ public class CommonStepsTest extends BasePiperTest{
         'deployIntegrationArtifact', //implementing new golang pattern without fields
         'newmanExecute', //implementing new golang pattern without fields
         'terraformExecute', //implementing new golang pattern without fields
+        'policyEvaluate', //implementing new golang pattern without fields
+        'jenkinsTriggerJob', //implementing new golang pattern without fields
+        'artifactCreateSBOM', //implementing new golang pattern without fields
+        'osvExecuteScan', //implementing new golang pattern without fields
+        'licenseCheckCompliance', //implementing new golang pattern without fields
         'whitesourceExecuteScan', //implementing new golang pattern without fields
         'uiVeri5ExecuteTests', //implementing new golang pattern without fields
         'integrationArtifactDeploy', //implementing new golang pattern without fields
@@ -13,6 +13,8 @@ void call(Map parameters = [:]) {
     List credentials = [
         [type: 'ssh', id: 'gitSshKeyCredentialsId'],
         [type: 'usernamePassword', id: 'gitHttpsCredentialsId', env: ['PIPER_username', 'PIPER_password']],
+        [type: 'token', id: 'gitTokenCredentialsId', env: ['PIPER_gitToken']],
+        [type: 'file', id: 'githubAppPrivateKeyCredentialsId', env: ['PIPER_githubAppPrivateKeyPath']],
     ]
 
     // Tell dockerExecuteOnKubernetes (if used) to stash also .-folders
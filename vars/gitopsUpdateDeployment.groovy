@@ -5,7 +5,11 @@ import groovy.transform.Field
 
 void call(Map parameters = [:]) {
     List credentials = [
+        [type: 'ssh', id: 'gitSshKeyCredentialsId'],
         [type: 'usernamePassword', id: 'gitHttpsCredentialsId', env: ['PIPER_username', 'PIPER_password']],
+        [type: 'token', id: 'gitTokenCredentialsId', env: ['PIPER_gitToken']],
+        [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']],
+        [type: 'file', id: 'githubAppPrivateKeyCredentialsId', env: ['PIPER_githubAppPrivateKeyPath']],
     ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
@@ -9,5 +9,11 @@ import com.sap.piper.DownloadCacheUtils
 void call(Map parameters = [:]) {
     final script = checkScript(this, parameters) ?: this
 
-    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, [])
+    List credentials = [
+        [type: 'ssh', id: 'gitSshKeyCredentialsId'],
+        [type: 'usernamePassword', id: 'gitHttpsCredentialsId', env: ['PIPER_username', 'PIPER_password']],
+        [type: 'token', id: 'gitTokenCredentialsId', env: ['PIPER_gitToken']],
+        [type: 'file', id: 'githubAppPrivateKeyCredentialsId', env: ['PIPER_githubAppPrivateKeyPath']],
+    ]
+    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }